@@ -4,6 +4,13 @@
 // Package v1alpha1 holds the definition of the v1alpha1 Zarf Package
 package v1alpha1
 
+import (
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
 // ZarfComponent is the primary functional grouping of assets to deploy by Zarf.
 type ZarfComponent struct {
 	// The name of the component.
@@ -21,6 +28,14 @@ type ZarfComponent struct {
 	// Filter when this component is included in package creation or deployment.
 	Only ZarfComponentOnlyTarget `json:"only,omitempty"`
 
+	// Names of components that this component depends on. They are deployed with this component and before it, and
+	// when deploying with --keep-going the component is skipped if one of them failed.
+	DependsOn []string `json:"dependsOn,omitempty"`
+
+	// Names of components that can not be deployed together with this component, including when they are only
+	// brought in as a dependency of a selected component.
+	ConflictsWith []string `json:"conflictsWith,omitempty"`
+
 	// [Deprecated] Create a user selector field based on all components in the same group. This will be removed in Zarf v1.0.0. Consider using 'only.flavor' instead.
 	DeprecatedGroup string `json:"group,omitempty" jsonschema:"deprecated=true"`
 
@@ -45,6 +60,10 @@ type ZarfComponent struct {
 	// List of Tar files of images to bring into the package.
 	ImageArchives []ImageArchive `json:"imageArchives,omitempty"`
 
+	// Do not pull the images of this component into the package. The manifests and charts are still included and
+	// must reference images that are already mirrored to the target registry, so the package is no longer self-contained.
+	SkipImagePull bool `json:"skipImagePull,omitempty"`
+
 	// List of git repos to include in the package.
 	Repos []string `json:"repos,omitempty"`
 
@@ -54,8 +73,30 @@ type ZarfComponent struct {
 	// Custom commands to run at various stages of a package lifecycle.
 	Actions ZarfComponentActions `json:"actions,omitempty"`
 
+	// Path to a file of KEY=VALUE lines added to the environment of every action in this component. Env set in the action
+	// defaults or on an action takes precedence. The entries are recorded in the action defaults when the package is created.
+	EnvFile string `json:"envFile,omitempty"`
+
 	// List of resources to health check after deployment
 	HealthChecks []NamespacedObjectKindReference `json:"healthChecks,omitempty"`
+
+	// Retry the deployment of the whole component when it fails. The onDeploy actions, charts and manifests of the
+	// component are run again on every attempt so they must be safe to re-apply.
+	RetryPolicy *ZarfComponentRetryPolicy `json:"retryPolicy,omitempty"`
+
+	// Annotations added to every resource deployed by the charts and manifests of this component. The commonAnnotations of
+	// a chart or manifest take precedence. Values support variables and constants (e.g. ###ZARF_VAR_TEAM###).
+	ResourceAnnotations map[string]string `json:"resourceAnnotations,omitempty"`
+}
+
+// ZarfComponentRetryPolicy defines how a failed component deployment is retried.
+type ZarfComponentRetryPolicy struct {
+	// The maximum number of times to attempt the deployment of the component, including the first attempt (default 1).
+	MaxAttempts int `json:"maxAttempts,omitempty" jsonschema:"minimum=1"`
+	// The number of seconds to wait before the first retry, doubled before every following retry (default 0).
+	BackoffSeconds int `json:"backoffSeconds,omitempty" jsonschema:"minimum=0"`
+	// Run the onDeploy onFailure actions after every failed attempt instead of only after the last one (default false).
+	OnFailureBetweenAttempts bool `json:"onFailureBetweenAttempts,omitempty"`
 }
 
 // ImageArchive points to an archived file containing an OCI layout
@@ -104,6 +145,14 @@ func (c ZarfComponent) IsRequired() bool {
 	return false
 }
 
+// GetMaxAttempts returns the number of times to attempt the deployment of the component, defaults to 1.
+func (c ZarfComponent) GetMaxAttempts() int {
+	if c.RetryPolicy != nil && c.RetryPolicy.MaxAttempts > 1 {
+		return c.RetryPolicy.MaxAttempts
+	}
+	return 1
+}
+
 // GetImages returns all images specified in the component, including those from ImageArchives.
 func (c ZarfComponent) GetImages() []string {
 	images := []string{}
@@ -138,6 +187,9 @@ type ZarfComponentOnlyTarget struct {
 	Cluster ZarfComponentOnlyCluster `json:"cluster,omitempty"`
 	// Only include this component when a matching '--flavor' is specified on 'zarf package create'.
 	Flavor string `json:"flavor,omitempty"`
+	// Only deploy component when the expression over package variables is true, e.g. ENABLE_MONITORING && DISTRO != "eks".
+	// Supports ==, !=, &&, ||, ! and parentheses, a variable on its own is true when its value is "true".
+	Expression string `json:"expression,omitempty" jsonschema:"example=ENABLE_MONITORING && !AIRGAP"`
 }
 
 // ZarfComponentOnlyCluster represents the architecture and K8s cluster distribution to filter on.
@@ -152,16 +204,27 @@ type ZarfComponentOnlyCluster struct {
 type ZarfFile struct {
 	// Local folder or file path or remote URL to pull into the package.
 	Source string `json:"source"`
-	// (files only) Optional SHA256 checksum of the file.
+	// (files only) Optional checksum of the file. Prefix with the algorithm (sha256: or sha512:) to choose it, defaults to SHA256.
 	Shasum string `json:"shasum,omitempty"`
 	// The absolute or relative path where the file or folder should be copied to during package deploy.
 	Target string `json:"target"`
 	// (files only) Determines if the file should be made executable during package deploy.
 	Executable bool `json:"executable,omitempty"`
-	// List of symlinks to create during package deploy.
+	// Octal permission bits to set on the file or folder after it is placed during package deploy (e.g. "0600").
+	// Takes precedence over executable.
+	Mode string `json:"mode,omitempty" jsonschema:"pattern=^[0-7]{3\\,4}$"`
+	// List of symlinks to create during package deploy, pointing to the target. Relative paths are resolved against the directory of the target and cannot leave it.
 	Symlinks []string `json:"symlinks,omitempty"`
 	// Local folder or file to be extracted from a 'source' archive.
 	ExtractPath string `json:"extractPath,omitempty"`
+	// Paths or glob patterns of files to be extracted from a 'source' archive into the target folder, keeping their paths
+	// in the archive. Every pattern must match at least one file.
+	ExtractPaths []string `json:"extractPaths,omitempty"`
+	// (remote sources only) Retry downloading the file up to the given number of times if it fails, backing off between
+	// attempts (default 0, which uses the Zarf download defaults).
+	MaxRetries int `json:"maxRetries,omitempty" jsonschema:"minimum=0"`
+	// (remote sources only) Timeout in seconds for each attempt to download the file (default 0, no timeout).
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty" jsonschema:"minimum=0"`
 	// [alpha]
 	// Template enables go-templates inside manifests. This is useful for parameterizing fields that the value will be
 	// known at deploy-time. See documentation for Zarf Values for how to set these values.
@@ -178,6 +241,18 @@ func (f ZarfFile) IsTemplate() bool {
 	return false
 }
 
+// GetMode returns the permission bits set in Mode and whether Mode is set.
+func (f ZarfFile) GetMode() (fs.FileMode, bool, error) {
+	if f.Mode == "" {
+		return 0, false, nil
+	}
+	mode, err := strconv.ParseUint(f.Mode, 8, 32)
+	if err != nil || len(f.Mode) < 3 || len(f.Mode) > 4 {
+		return 0, false, fmt.Errorf("%q is not an octal permission string such as \"0600\"", f.Mode)
+	}
+	return fs.FileMode(mode), true, nil
+}
+
 // ZarfChart defines a helm chart to be deployed.
 type ZarfChart struct {
 	// The name of the chart within Zarf; note that this must be unique and does not need to be the same as the name in the chart repo.
@@ -188,6 +263,12 @@ type ZarfChart struct {
 	URL string `json:"url,omitempty" jsonschema:"example=OCI registry: oci://ghcr.io/stefanprodan/charts/podinfo,example=helm chart repo: https://stefanprodan.github.io/podinfo,example=git repo: https://github.com/stefanprodan/podinfo (note the '@' syntax for 'repos' is supported here too)"`
 	// The name of a chart within a Helm repository (defaults to the Zarf name of the chart).
 	RepoName string `json:"repoName,omitempty"`
+	// (OCI registry and chart repo only) The username used to pull the chart during package create. Takes precedence over
+	// credentials from helm registry login and the Helm repository config.
+	Username string `json:"username,omitempty"`
+	// (OCI registry and chart repo only) The name of the environment variable holding the password for username, read
+	// during package create so the password is never written to the zarf.yaml or package.
+	PasswordEnv string `json:"passwordEnv,omitempty" jsonschema:"example=CHART_REGISTRY_PASSWORD"`
 	// (git repo only) The sub directory to the chart within a git repo.
 	GitPath string `json:"gitPath,omitempty" jsonschema:"example=charts/your-chart"`
 	// The path to a local chart's folder or .tgz archive.
@@ -200,6 +281,8 @@ type ZarfChart struct {
 	NoWait bool `json:"noWait,omitempty"`
 	// List of local values file paths or remote URLs to include in the package; these will be merged together when deployed.
 	ValuesFiles []string `json:"valuesFiles,omitempty"`
+	// [alpha] Entries of valuesFiles that are merged after all other values files in the order listed, so they take precedence regardless of their position in valuesFiles.
+	ValuesPriority []string `json:"valuesPriority,omitempty"`
 	// [alpha] List of variables to set in the Helm chart.
 	Variables []ZarfChartVariable `json:"variables,omitempty"`
 	// [alpha] List of values sources to their Helm override target
@@ -213,6 +296,29 @@ type ZarfChart struct {
 	//              was used when the chart was first installed
 	// Defaults to "auto" when omitted.
 	ServerSideApply string `json:"serverSideApply,omitempty" jsonschema:"enum=true,enum=false,enum=auto"`
+	// Skip running the chart's Helm hooks (such as pre-install Jobs) during install, upgrade, and rollback (default false).
+	// Hooks often perform setup the chart depends on, so only disable them when the hooks are known to be unnecessary or
+	// incompatible with the target cluster.
+	DisableHooks bool `json:"disableHooks,omitempty"`
+	// Retry the Helm install or upgrade up to the given number of times when it fails with a transient Kubernetes API
+	// error such as a timeout, conflict, or throttled request (default 0). Errors caused by the chart itself, such as
+	// invalid values or templates, are never retried.
+	MaxRetries int `json:"maxRetries,omitempty" jsonschema:"minimum=0"`
+	// The Kubernetes version to render the chart templates against when finding images or inspecting resources without a cluster
+	// (e.g. "v1.30.0"). Charts that gate templates on .Capabilities.KubeVersion otherwise render against Helm's default.
+	// The --kube-version flag takes precedence when set.
+	KubeVersion string `json:"kubeVersion,omitempty" jsonschema:"example=v1.30.0"`
+	// Annotations added to every resource deployed by the chart, overriding the resourceAnnotations of the component.
+	// Values support variables and constants (e.g. ###ZARF_VAR_TEAM###).
+	CommonAnnotations map[string]string `json:"commonAnnotations,omitempty"`
+	// [alpha] List of local kustomize strategic merge patch files to apply in order to the rendered chart before it is
+	// deployed. Patches are applied after the values are rendered and are packaged with the component.
+	PostRenderPatches []string `json:"postRenderPatches,omitempty"`
+	// Whether Zarf creates the namespaces of the chart when they are missing (default true). When false the deploy
+	// fails if a namespace does not exist and existing namespaces are left untouched.
+	CreateNamespace *bool `json:"createNamespace,omitempty"`
+	// Labels added to the namespace of the chart when Zarf creates or adopts it.
+	NamespaceLabels map[string]string `json:"namespaceLabels,omitempty"`
 }
 
 // ShouldRunSchemaValidation returns if Helm schema validation should be run or not
@@ -223,6 +329,14 @@ func (zc ZarfChart) ShouldRunSchemaValidation() bool {
 	return true
 }
 
+// ShouldCreateNamespace returns if Zarf should create the missing namespaces of the chart, defaults to true
+func (zc ZarfChart) ShouldCreateNamespace() bool {
+	if zc.CreateNamespace != nil {
+		return *zc.CreateNamespace
+	}
+	return true
+}
+
 // GetServerSideApply returns server side apply with default of "auto" if it is not set
 func (zc ZarfChart) GetServerSideApply() string {
 	if zc.ServerSideApply == "" {
@@ -272,10 +386,37 @@ type ZarfManifest struct {
 	//              was used when the chart was first installed
 	// Defaults to "auto" when omitted.
 	ServerSideApply string `json:"serverSideApply,omitempty" jsonschema:"enum=true,enum=false,enum=auto"`
+	// The field manager recorded on the deployed resources (default "zarf"). Lets a different owner of overlapping
+	// fields be told apart from Zarf when Server-Side Apply reports conflicts.
+	FieldManager string `json:"fieldManager,omitempty" jsonschema:"maxLength=128"`
+	// Take ownership of fields managed by others during Server-Side Apply instead of failing on the conflict (default false).
+	// Conflicts are always forced when deploying with --force-conflicts.
+	ForceConflicts bool `json:"forceConflicts,omitempty"`
 	// [alpha]
 	// Template enables go-templates inside manifests. This is useful for parameterizing fields that the value will be
 	// known at deploy-time. See documentation for Zarf Values for how to set these values.
 	Template *bool `json:"template,omitempty"`
+	// [alpha] List of variables to set in the values available to the templates of the manifests, e.g. an image tag in the
+	// output of a kustomization that kustomize can not template itself. Requires template to be true.
+	Variables []ZarfManifestVariable `json:"variables,omitempty"`
+	// Annotations added to every resource deployed by the manifests, overriding the resourceAnnotations of the component.
+	// Values support variables and constants (e.g. ###ZARF_VAR_TEAM###).
+	CommonAnnotations map[string]string `json:"commonAnnotations,omitempty"`
+	// Whether Zarf creates the namespaces of the manifests when they are missing (default true). When false the deploy
+	// fails if a namespace does not exist and existing namespaces are left untouched.
+	CreateNamespace *bool `json:"createNamespace,omitempty"`
+	// Labels added to the namespace of the manifests when Zarf creates or adopts it.
+	NamespaceLabels map[string]string `json:"namespaceLabels,omitempty"`
+}
+
+// ZarfManifestVariable represents a variable that can be set in the values of templated manifests.
+type ZarfManifestVariable struct {
+	// The name of the variable.
+	Name string `json:"name" jsonschema:"pattern=^[A-Z0-9_]+$"`
+	// A brief description of what the variable controls.
+	Description string `json:"description"`
+	// The path within the values where this variable applies, referenced in the manifests as {{ .Values.<path> }}.
+	Path string `json:"path" jsonschema:"example=image.tag"`
 }
 
 // GetServerSideApply returns server side apply with default of "auto" if it is not set
@@ -286,6 +427,14 @@ func (m ZarfManifest) GetServerSideApply() string {
 	return m.ServerSideApply
 }
 
+// ShouldCreateNamespace returns if Zarf should create the missing namespaces of the manifests, defaults to true
+func (m ZarfManifest) ShouldCreateNamespace() bool {
+	if m.CreateNamespace != nil {
+		return *m.CreateNamespace
+	}
+	return true
+}
+
 // IsTemplate returns if the ZarfFile should be templated.
 func (m ZarfManifest) IsTemplate() bool {
 	if m.Template != nil {
@@ -348,6 +497,8 @@ type ZarfComponentActionDefaults struct {
 	Dir string `json:"dir,omitempty"`
 	// Additional environment variables for commands.
 	Env []string `json:"env,omitempty"`
+	// (cmd only) Exit codes of commands that count as success without a retry (default [0]).
+	AllowedExitCodes []int `json:"allowedExitCodes,omitempty"`
 	// (cmd only) Indicates a preference for a shell for the provided cmd to be executed in on supported operating systems.
 	Shell Shell `json:"shell,omitempty"`
 }
@@ -364,6 +515,12 @@ type ZarfComponentAction struct {
 	Dir *string `json:"dir,omitempty"`
 	// Additional environment variables to set for the command.
 	Env []string `json:"env,omitempty"`
+	// (cmd only) Path to a file of KEY=VALUE lines added to the environment of the command, relative to the dir of the
+	// action. The file is read when the action runs and env set on the action takes precedence.
+	EnvFile string `json:"envFile,omitempty"`
+	// (cmd only) Exit codes of the command that count as success without a retry, replacing the default of the action
+	// set (default [0]).
+	AllowedExitCodes []int `json:"allowedExitCodes,omitempty"`
 	// The command to run. Must specify either cmd or wait for the action to do anything.
 	Cmd string `json:"cmd,omitempty"`
 	// (cmd only) Indicates a preference for a shell for the provided cmd to be executed in on supported operating systems.
@@ -372,14 +529,41 @@ type ZarfComponentAction struct {
 	DeprecatedSetVariable string `json:"setVariable,omitempty" jsonschema:"pattern=^[A-Z0-9_]+$"`
 	// (onDeploy/cmd only) An array of variables to update with the output of the command. These variables will be available to all remaining actions and components in the package.
 	SetVariables []Variable `json:"setVariables,omitempty"`
+	// (onDeploy/cmd only) An array of variables to update with the values at JSONPaths of the output of the command, which
+	// must be JSON. These variables will be available to all remaining actions and components in the package.
+	SetVariablesFromJSON []ZarfComponentActionJSONVariable `json:"setVariablesFromJSON,omitempty"`
 	// (onDeploy/onRemove/cmd only) An array of variables to update with the output of the command. These variables will be available to all remaining actions and components in the package.
 	SetValues []SetValue `json:"setValues,omitempty"`
 	// Description of the action to be displayed during package execution instead of the command.
 	Description string `json:"description,omitempty"`
+	// (onDeploy only) Tags used to select a subset of the actions to run with --only-actions-tagged.
+	Tags []string `json:"tags,omitempty"`
 	// Wait for a condition to be met before continuing. Must specify either cmd or wait for the action. See the 'zarf tools wait-for' command for more info.
 	Wait *ZarfComponentActionWait `json:"wait,omitempty"`
 	// Disable go-template processing on the cmd field. This is useful when the cmd contains go-templates that should be passed to another system.
 	Template *bool `json:"template,omitempty"`
+	// Require confirmation before running the action (default false). Interactive runs prompt the user, non-interactive runs abort unless --confirm is set. Not supported in onCreate actions.
+	Confirm bool `json:"confirm,omitempty"`
+	// The message shown when prompting for confirmation. Variables are substituted using ${ZARF_VAR_NAME} syntax and go-templates are applied when template is true.
+	ConfirmPrompt string `json:"confirmPrompt,omitempty"`
+}
+
+// ZarfComponentActionJSONVariable is a variable set to a value in the JSON output of an action.
+type ZarfComponentActionJSONVariable struct {
+	Variable `json:",inline"`
+	// The JSONPath of the value in the output of the command, e.g. .status.loadBalancer.ingress[0].ip. Strings are set
+	// as is, other values are set as JSON.
+	Path string `json:"path"`
+	// Set the variable to an empty value instead of failing the action when the path is not found in the output (default false).
+	Optional bool `json:"optional,omitempty"`
+}
+
+// JSONPath returns the path as a JSONPath template expression, wrapping it in braces when they are omitted.
+func (v ZarfComponentActionJSONVariable) JSONPath() string {
+	if strings.HasPrefix(v.Path, "{") {
+		return v.Path
+	}
+	return "{" + v.Path + "}"
 }
 
 // ShouldTemplate returns if the action cmd should be templated or not.
@@ -395,10 +579,14 @@ func (a ZarfComponentAction) ShouldTemplate() bool {
 
 // ZarfComponentActionWait specifies a condition to wait for before continuing
 type ZarfComponentActionWait struct {
-	// Wait for a condition to be met in the cluster before continuing. Only one of cluster or network can be specified.
+	// Wait for a condition to be met in the cluster before continuing. Only one of cluster, network or variable can be specified.
 	Cluster *ZarfComponentActionWaitCluster `json:"cluster,omitempty"`
-	// Wait for a condition to be met on the network before continuing. Only one of cluster or network can be specified.
+	// Wait for a list of conditions in the cluster that must all be met before continuing, together with cluster if it is set.
+	Clusters []ZarfComponentActionWaitCluster `json:"clusters,omitempty"`
+	// Wait for a condition to be met on the network before continuing. Only one of cluster, network or variable can be specified.
 	Network *ZarfComponentActionWaitNetwork `json:"network,omitempty"`
+	// Wait for a Zarf variable to be set, e.g. by another action running concurrently, before continuing. Only one of cluster, network or variable can be specified.
+	Variable *ZarfComponentActionWaitVariable `json:"variable,omitempty"`
 }
 
 // ZarfComponentActionWaitCluster specifies a condition to wait for before continuing
@@ -410,7 +598,21 @@ type ZarfComponentActionWaitCluster struct {
 	// The namespace of the resource to wait for.
 	Namespace string `json:"namespace,omitempty"`
 	// The condition or jsonpath state to wait for; defaults to exist, a special condition that will wait for the resource to exist.
-	Condition string `json:"condition,omitempty" jsonschema:"example=Ready,example=Available,'{.status.availableReplicas}'=23"`
+	// rolledOut is a special condition that waits for a Deployment, DaemonSet or StatefulSet rollout to complete.
+	// deleted is a special condition that waits until the resource, or every resource matching a selector, no longer exists.
+	Condition string `json:"condition,omitempty" jsonschema:"example=Ready,example=Available,example=rolledOut,example=deleted,'{.status.availableReplicas}'=23"`
+	// The number of resources matching a selector name that must meet a jsonpath condition (default 0, every matching
+	// resource must meet the condition).
+	MinReady int `json:"minReady,omitempty"`
+}
+
+// ClusterConditions returns the conditions of cluster and clusters that must all be met in the cluster.
+func (w ZarfComponentActionWait) ClusterConditions() []ZarfComponentActionWaitCluster {
+	conditions := []ZarfComponentActionWaitCluster{}
+	if w.Cluster != nil {
+		conditions = append(conditions, *w.Cluster)
+	}
+	return append(conditions, w.Clusters...)
 }
 
 // ZarfComponentActionWaitNetwork specifies a condition to wait for before continuing
@@ -421,6 +623,20 @@ type ZarfComponentActionWaitNetwork struct {
 	Address string `json:"address" jsonschema:"example=localhost:8080,example=1.1.1.1"`
 	// The HTTP status code to wait for if using http or https.
 	Code int `json:"code,omitempty" jsonschema:"example=200,example=404"`
+	// The HTTP method to send if using http or https (default GET).
+	Method string `json:"method,omitempty" jsonschema:"enum=GET,enum=HEAD,enum=POST,enum=PUT,enum=PATCH,enum=DELETE,enum=OPTIONS"`
+	// HTTP headers to send if using http or https. Values support Zarf variables.
+	Headers map[string]string `json:"headers,omitempty"`
+	// Skip verifying the TLS certificate of the address if using https.
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// ZarfComponentActionWaitVariable specifies a Zarf variable to wait for before continuing
+type ZarfComponentActionWaitVariable struct {
+	// The name of the variable to wait for.
+	Name string `json:"name" jsonschema:"pattern=^[A-Z0-9_]+$"`
+	// The value to wait for; defaults to waiting for the variable to be set to any non-empty value.
+	Value string `json:"value,omitempty"`
 }
 
 // ZarfContainerTarget defines the destination info for a ZarfData target
@@ -435,6 +651,28 @@ type ZarfContainerTarget struct {
 	Path string `json:"path"`
 }
 
+// DataInjectionMode selects how the data of a data injection is copied into the target container.
+type DataInjectionMode string
+
+const (
+	// DataInjectionModeExec copies data by executing tar inside the target container.
+	DataInjectionModeExec DataInjectionMode = "exec"
+	// DataInjectionModeInitContainer copies data through an ephemeral container that mounts the target container's volume.
+	DataInjectionModeInitContainer DataInjectionMode = "initContainer"
+)
+
+// DataInjectionCompression selects how the data of a data injection is compressed while it is copied.
+type DataInjectionCompression string
+
+const (
+	// DataInjectionCompressionGzip compresses data using gzip.
+	DataInjectionCompressionGzip DataInjectionCompression = "gzip"
+	// DataInjectionCompressionZstd compresses data using zstd.
+	DataInjectionCompressionZstd DataInjectionCompression = "zstd"
+	// DataInjectionCompressionNone copies data without compression.
+	DataInjectionCompressionNone DataInjectionCompression = "none"
+)
+
 // ZarfDataInjection is a data-injection definition.
 type ZarfDataInjection struct {
 	// Either a path to a local folder/file or a remote URL of a file to inject into the given target pod + container.
@@ -442,7 +680,36 @@ type ZarfDataInjection struct {
 	// The target pod + container to inject the data into.
 	Target ZarfContainerTarget `json:"target"`
 	// Compress the data before transmitting using gzip. Note: this requires support for tar/gzip locally and in the target image.
+	// Alias of compressionAlgorithm gzip.
 	Compress bool `json:"compress,omitempty"`
+	// The algorithm used to compress the data before transmitting (default 'gzip' when compress is set, otherwise 'none').
+	// 'zstd' requires the zstd binary locally, and in the target image when using the 'exec' mode.
+	CompressionAlgorithm DataInjectionCompression `json:"compressionAlgorithm,omitempty" jsonschema:"enum=gzip,enum=zstd,enum=none"`
+	// How to copy the data into the target (default 'exec'). 'exec' runs tar inside the target container, which requires
+	// a shell and tar in the target image. 'initContainer' attaches an ephemeral container running the Zarf agent image
+	// that mounts the volume holding the target path, so the target image needs no tooling. It requires the Zarf agent,
+	// ephemeral container support in the cluster, and a target path on a volume mounted without a subPath that is writable
+	// by the agent image's non-root user.
+	Mode DataInjectionMode `json:"mode,omitempty" jsonschema:"enum=exec,enum=initContainer"`
+}
+
+// GetCompressionAlgorithm returns the data injection compression algorithm, defaulting to gzip when compress is set and none otherwise.
+func (d ZarfDataInjection) GetCompressionAlgorithm() DataInjectionCompression {
+	if d.CompressionAlgorithm != "" {
+		return d.CompressionAlgorithm
+	}
+	if d.Compress {
+		return DataInjectionCompressionGzip
+	}
+	return DataInjectionCompressionNone
+}
+
+// GetMode returns the data injection mode, defaulting to exec.
+func (d ZarfDataInjection) GetMode() DataInjectionMode {
+	if d.Mode == "" {
+		return DataInjectionModeExec
+	}
+	return d.Mode
 }
 
 // ZarfComponentImport structure for including imported Zarf components.
@@ -453,6 +720,10 @@ type ZarfComponentImport struct {
 	Path string `json:"path,omitempty"`
 	// [beta] The URL to a Zarf package to import via OCI.
 	URL string `json:"url,omitempty" jsonschema:"pattern=^oci://.*$"`
+	// [beta] A semver constraint that selects the newest matching tag of the package at url, which must then not include a tag.
+	Version string `json:"version,omitempty" jsonschema:"example=>=1.2.0 <2.0.0,example=~1.4"`
+	// The parts of the imported component to merge into this component (defaults to all parts).
+	Parts []string `json:"parts,omitempty" jsonschema:"enum=actions,enum=charts,enum=manifests,enum=files,enum=images,enum=imageArchives,enum=repos,enum=dataInjections,enum=healthChecks"`
 }
 
 // Shell represents the desired shell to use for a given command
@@ -4,6 +4,7 @@
 package v1alpha1
 
 import (
+	"io/fs"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -97,3 +98,67 @@ func TestGetImages(t *testing.T) {
 		})
 	}
 }
+
+func TestGetCompressionAlgorithm(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, DataInjectionCompressionNone, ZarfDataInjection{}.GetCompressionAlgorithm())
+	require.Equal(t, DataInjectionCompressionGzip, ZarfDataInjection{Compress: true}.GetCompressionAlgorithm())
+	require.Equal(t, DataInjectionCompressionZstd, ZarfDataInjection{CompressionAlgorithm: DataInjectionCompressionZstd}.GetCompressionAlgorithm())
+}
+
+func TestZarfFileGetMode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		mode        string
+		expected    fs.FileMode
+		expectedSet bool
+		expectedErr bool
+	}{
+		{mode: ""},
+		{mode: "0600", expected: 0o600, expectedSet: true},
+		{mode: "750", expected: 0o750, expectedSet: true},
+		{mode: "4755", expected: 0o4755, expectedSet: true},
+		{mode: "0800", expectedErr: true},
+		{mode: "rw-------", expectedErr: true},
+		{mode: "07", expectedErr: true},
+		{mode: "00600", expectedErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			t.Parallel()
+			mode, set, err := ZarfFile{Mode: tt.mode}.GetMode()
+			if tt.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedSet, set)
+			require.Equal(t, tt.expected, mode)
+		})
+	}
+}
+
+func TestZarfComponentActionWaitClusterConditions(t *testing.T) {
+	t.Parallel()
+
+	single := ZarfComponentActionWaitCluster{Kind: "Pod", Name: "app=db", Namespace: "data", Condition: "Ready"}
+	multiple := []ZarfComponentActionWaitCluster{
+		{Kind: "StatefulSet", Name: "db", Namespace: "data", Condition: "Ready"},
+		{Kind: "Job", Name: "migrate", Namespace: "data", Condition: "{.status.succeeded}=1"},
+	}
+
+	require.Empty(t, ZarfComponentActionWait{}.ClusterConditions())
+	require.Equal(t, []ZarfComponentActionWaitCluster{single}, ZarfComponentActionWait{Cluster: &single}.ClusterConditions())
+	require.Equal(t, multiple, ZarfComponentActionWait{Clusters: multiple}.ClusterConditions())
+	require.Equal(t, append([]ZarfComponentActionWaitCluster{single}, multiple...), ZarfComponentActionWait{Cluster: &single, Clusters: multiple}.ClusterConditions())
+
+	// The conditions are copies so templating them leaves the action untouched
+	wait := ZarfComponentActionWait{Cluster: &single, Clusters: multiple}
+	conditions := wait.ClusterConditions()
+	conditions[0].Name = "changed"
+	conditions[1].Name = "changed"
+	require.Equal(t, "app=db", wait.Cluster.Name)
+	require.Equal(t, "db", wait.Clusters[0].Name)
+}
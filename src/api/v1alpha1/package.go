@@ -137,7 +137,7 @@ type Variable struct {
 	Sensitive bool `json:"sensitive,omitempty"`
 	// Whether to automatically indent the variable's value (if multiline) when templating. Based on the number of chars before the start of ###ZARF_VAR_.
 	AutoIndent bool `json:"autoIndent,omitempty"`
-	// An optional regex pattern that a variable value must match before a package deployment can continue.
+	// An optional regex pattern that a variable value must match before a package deployment can continue. In action setVariables a pattern with a capture group named value sets the variable to that group of its match in the output instead.
 	Pattern string `json:"pattern,omitempty"`
 	// Changes the handling of a variable to load contents differently (i.e. from a file rather than as a raw variable - templated files should be kept below 1 MiB)
 	Type VariableType `json:"type,omitempty" jsonschema:"enum=raw,enum=file"`
@@ -237,6 +237,9 @@ type ZarfMetadata struct {
 	// Annotations contains arbitrary metadata about the package.
 	// Users are encouraged to follow OCI image-spec https://github.com/opencontainers/image-spec/blob/main/annotations.md
 	Annotations map[string]string `json:"annotations,omitempty"`
+	// Labels are descriptive key/value pairs used to organize and filter packages (e.g. in a package catalog).
+	// Keys and values must follow Kubernetes label syntax.
+	Labels map[string]string `json:"labels,omitempty"`
 	// AllowNamespaceOverride controls whether a package's namespace may be overridden.
 	AllowNamespaceOverride *bool `json:"allowNamespaceOverride,omitempty"`
 }
@@ -257,6 +260,8 @@ type ZarfBuildData struct {
 	Migrations []string `json:"migrations,omitempty"`
 	// Any registry domains that were overridden on package create when pulling images.
 	RegistryOverrides map[string]string `json:"registryOverrides,omitempty"`
+	// Images referenced by tag that were pinned to their digest on package create, keyed by their original reference.
+	PinnedImages map[string]string `json:"pinnedImages,omitempty"`
 	// Whether this package was created with differential components.
 	Differential bool `json:"differential,omitempty"`
 	// Version of a previously built package used as the basis for creating this differential package.
@@ -6,30 +6,63 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
+	"net"
+	"os"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/spf13/cobra"
 
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 	"github.com/zarf-dev/zarf/src/config/lang"
 	"github.com/zarf-dev/zarf/src/pkg/cluster"
 	"github.com/zarf-dev/zarf/src/pkg/logger"
 	"github.com/zarf-dev/zarf/src/pkg/message"
 	"github.com/zarf-dev/zarf/src/pkg/state"
 	"github.com/zarf-dev/zarf/src/pkg/utils/exec"
+	"github.com/zarf-dev/zarf/src/pkg/wait"
 )
 
 type connectOptions struct {
-	open bool
-	zt   cluster.TunnelInfo
+	open     bool
+	wait     bool
+	printCmd bool
+	probe    bool
+	detach   bool
+	// transport is the name of the tunnel transport, it is parsed into zt.Transport
+	transport string
+	// protocol is the name of the tunnel protocol, it is parsed into zt.Protocol
+	protocol string
+	// probeCheck is the check the probe runs, its address is set to the tunnel endpoint
+	probeCheck v1alpha1.ZarfComponentActionWaitNetwork
+	// output is the format the established tunnel is printed in, empty only logs it
+	output string
+	// logs streams the logs of the pod behind the tunnel to stderr while the tunnel is open
+	logs    bool
+	logOpts cluster.TunnelLogOptions
+	zt      cluster.TunnelInfo
 }
 
+// connectProbeTimeout bounds the single reachability check of a probe.
+const connectProbeTimeout = 10 * time.Second
+
+// connectProbeSettle is how long a tcp probe keeps its connection open to see whether the remote end closes it.
+const connectProbeSettle = 2 * time.Second
+
 func newConnectCommand() *cobra.Command {
 	o := &connectOptions{}
 
 	cmd := &cobra.Command{
-		Use:     "connect { REGISTRY | GIT | connect-name }",
+		Use:     "connect { REGISTRY | GIT | connect-name }...",
 		Aliases: []string{"c"},
 		Short:   lang.CmdConnectShort,
 		Long:    lang.CmdConnectLong,
@@ -38,11 +71,32 @@ func newConnectCommand() *cobra.Command {
 
 	cmd.Flags().StringSliceVar(&o.zt.ListenAddresses, "address", []string{helpers.IPV4Localhost}, lang.CmdConnectFlagAddress)
 	cmd.Flags().StringVar(&o.zt.ResourceName, "name", "", lang.CmdConnectFlagName)
-	cmd.Flags().StringVar(&o.zt.Namespace, "namespace", state.ZarfNamespaceName, lang.CmdConnectFlagNamespace)
+	cmd.Flags().StringVar(&o.zt.Namespace, "namespace", "", lang.CmdConnectFlagNamespace)
 	cmd.Flags().StringVar(&o.zt.ResourceType, "type", cluster.SvcResource, lang.CmdConnectFlagType)
 	cmd.Flags().IntVar(&o.zt.LocalPort, "local-port", 0, lang.CmdConnectFlagLocalPort)
 	cmd.Flags().IntVar(&o.zt.RemotePort, "remote-port", 0, lang.CmdConnectFlagRemotePort)
 	cmd.Flags().BoolVar(&o.open, "open", false, lang.CmdConnectFlagOpen)
+	cmd.Flags().BoolVar(&o.wait, "wait", false, lang.CmdConnectFlagWait)
+	cmd.Flags().BoolVar(&o.printCmd, "print-cmd", false, lang.CmdConnectFlagPrintCmd)
+	cmd.Flags().BoolVar(&o.probe, "probe", false, lang.CmdConnectFlagProbe)
+	cmd.Flags().StringVar(&o.probeCheck.Protocol, "probe-protocol", "tcp", lang.CmdConnectFlagProbeProtocol)
+	cmd.Flags().IntVar(&o.probeCheck.Code, "probe-code", 0, lang.CmdConnectFlagProbeCode)
+	cmd.Flags().StringVar(&o.transport, "transport", string(cluster.TunnelTransportAuto), lang.CmdConnectFlagTransport)
+	cmd.Flags().StringVar(&o.protocol, "protocol", string(cluster.TunnelProtocolTCP), lang.CmdConnectFlagProtocol)
+	cmd.Flags().StringVarP(&o.output, "output", "o", "", lang.CmdConnectFlagOutput)
+	cmd.Flags().BoolVar(&o.detach, "detach", false, lang.CmdConnectFlagDetach)
+	cmd.Flags().BoolVar(&o.logs, "logs", false, lang.CmdConnectFlagLogs)
+	cmd.Flags().DurationVar(&o.logOpts.Since, "since", 0, lang.CmdConnectFlagSince)
+	cmd.Flags().Int64Var(&o.logOpts.Tail, "tail", -1, lang.CmdConnectFlagTail)
+	cmd.MarkFlagsMutuallyExclusive("probe", "open")
+	cmd.MarkFlagsMutuallyExclusive("probe", "print-cmd")
+	cmd.MarkFlagsMutuallyExclusive("output", "probe")
+	cmd.MarkFlagsMutuallyExclusive("output", "print-cmd")
+	cmd.MarkFlagsMutuallyExclusive("detach", "probe")
+	cmd.MarkFlagsMutuallyExclusive("detach", "print-cmd")
+	cmd.MarkFlagsMutuallyExclusive("detach", "open")
+	cmd.MarkFlagsMutuallyExclusive("logs", "probe")
+	cmd.MarkFlagsMutuallyExclusive("logs", "detach")
 
 	// Deprecate flags that conflict with positional target argument.
 	// These flags are ignored when a connect-name target is supplied.
@@ -54,6 +108,7 @@ func newConnectCommand() *cobra.Command {
 	// TODO(soltysh): consider splitting sub-commands into separate files
 	cmd.AddCommand(newConnectListCommand())
 	cmd.AddCommand(newConnectResourceCommand())
+	cmd.AddCommand(newConnectStopCommand())
 
 	return cmd
 }
@@ -65,44 +120,464 @@ func (o *connectOptions) run(cmd *cobra.Command, args []string) error {
 	if len(args) > 0 {
 		target = args[0]
 	}
-
-	c, err := cluster.New(ctx)
+	if o.printCmd {
+		if _, ok := connectCommandTemplates[strings.ToUpper(target)]; !ok {
+			return fmt.Errorf("--print-cmd is only supported for the %s and %s targets", cluster.ZarfRegistry, cluster.ZarfGit)
+		}
+	}
+	if o.probe && !slices.Contains([]string{"tcp", "http", "https"}, o.probeCheck.Protocol) {
+		return fmt.Errorf("invalid probe protocol %q, must be one of tcp, http or https", o.probeCheck.Protocol)
+	}
+	if err := validateConnectOutput(o.output); err != nil {
+		return err
+	}
+	if err := validateListenAddresses(ctx, o.zt.ListenAddresses); err != nil {
+		return err
+	}
+	transport, err := cluster.ParseTunnelTransport(o.transport)
 	if err != nil {
 		return err
 	}
+	o.zt.Transport = transport
+	protocol, err := cluster.ParseTunnelProtocol(o.protocol)
+	if err != nil {
+		return err
+	}
+	if o.probe && protocol == cluster.TunnelProtocolUDP {
+		return fmt.Errorf("--probe is not supported for %s tunnels", protocol)
+	}
+	if o.logs && protocol == cluster.TunnelProtocolUDP {
+		return fmt.Errorf("--logs is not supported for %s tunnels", protocol)
+	}
+	if !o.logs && (cmd.Flags().Changed("since") || cmd.Flags().Changed("tail")) {
+		return fmt.Errorf("--since and --tail can only be used with --logs")
+	}
+	o.zt.Protocol = protocol
+
+	if len(args) > 1 {
+		if err := o.validateMultipleTargets(args); err != nil {
+			return err
+		}
+		return o.connectMultiple(ctx, args)
+	}
+
+	// The foreground command only starts the background process, which runs this command again to hold the tunnel
+	detachedName, isDetached := os.LookupEnv(connectDetachedEnv)
+	if o.detach && !isDetached {
+		return o.startDetached(ctx, target)
+	}
 
+	var c *cluster.Cluster
 	var tunnel *cluster.Tunnel
 	if target == "" {
-		tunnel, err = c.ConnectTunnelInfo(ctx, o.zt)
+		c, err = cluster.New(ctx)
+		if err != nil {
+			return err
+		}
+		tunnel, err = c.ConnectTunnelInfo(ctx, resourceTunnelInfo(o.zt))
+		if err != nil {
+			return fmt.Errorf("unable to connect to the service: %w", err)
+		}
 	} else {
 		var ti cluster.TunnelInfo
-		ti, err = c.NewTargetTunnelInfo(ctx, target)
+		c, ti, err = o.resolveTarget(ctx, target)
 		if err != nil {
 			return fmt.Errorf("unable to create tunnel: %w", err)
 		}
-		if o.zt.LocalPort != 0 {
-			ti.LocalPort = o.zt.LocalPort
+
+		tunnel, err = c.ConnectTunnelInfo(ctx, targetTunnelInfo(ti, o.zt))
+		if err != nil {
+			return fmt.Errorf("unable to connect to the service: %w", err)
+		}
+	}
+
+	defer tunnel.Close()
+
+	if isDetached {
+		dir, err := connectTunnelsDir()
+		if err != nil {
+			return err
+		}
+		return holdDetachedTunnel(ctx, dir, detachedName, tunnel)
+	}
+
+	if o.probe {
+		return probeTunnel(ctx, tunnel.Endpoints()[0], o.probeCheck, OutputWriter)
+	}
+
+	if o.printCmd {
+		s, err := c.LoadState(ctx)
+		if err != nil {
+			return err
+		}
+		cmds, err := connectCommands(target, tunnel.Endpoints()[0], s)
+		if err != nil {
+			return err
+		}
+		for _, line := range cmds {
+			fmt.Fprintln(OutputWriter, line)
 		}
-		ti.ListenAddresses = o.zt.ListenAddresses
+		logger.From(ctx).Info("retrieve the password for these commands with zarf tools get-creds", "target", strings.ToLower(target))
+	}
 
-		tunnel, err = c.ConnectTunnelInfo(ctx, ti)
+	if o.logs {
+		// The stream stops once the tunnel is closed when the command returns
+		go func() {
+			if err := tunnel.StreamLogs(ctx, os.Stderr, o.logOpts); err != nil {
+				logger.From(ctx).Warn("unable to stream the logs of the tunnel", "error", err)
+			}
+		}()
 	}
 
+	return waitForTunnel(ctx, tunnel, o.open, o.output, OutputWriter)
+}
+
+// validateMultipleTargets returns an error for targets given more than once and for flags that only apply to a
+// single tunnel.
+func (o *connectOptions) validateMultipleTargets(targets []string) error {
+	seen := map[string]bool{}
+	for _, target := range targets {
+		if seen[target] {
+			return fmt.Errorf("target %s is given more than once", target)
+		}
+		seen[target] = true
+	}
+	singleTargetFlags := map[string]bool{
+		"--local-port": o.zt.LocalPort != 0,
+		"--print-cmd":  o.printCmd,
+		"--probe":      o.probe,
+		"--detach":     o.detach,
+		"--output":     o.output != "",
+		"--logs":       o.logs,
+	}
+	for _, flag := range slices.Sorted(maps.Keys(singleTargetFlags)) {
+		if singleTargetFlags[flag] {
+			return fmt.Errorf("%s can only be used with a single target", flag)
+		}
+	}
+	return nil
+}
+
+// connectMultiple opens a tunnel to each of the targets at the same time, each on an available local port. It prints
+// the tunnels and blocks until interrupted or until one of the tunnels is lost, then closes all of them.
+func (o *connectOptions) connectMultiple(ctx context.Context, targets []string) error {
+	l := logger.From(ctx)
+	var c *cluster.Cluster
+	var err error
+	lookupCtx := ctx
+	if o.wait {
+		timeoutCtx, cancel := context.WithTimeout(ctx, cluster.DefaultTimeout)
+		defer cancel()
+		lookupCtx = timeoutCtx
+		c, err = cluster.NewWithWait(lookupCtx)
+	} else {
+		c, err = cluster.New(ctx)
+	}
 	if err != nil {
-		return fmt.Errorf("unable to connect to the service: %w", err)
+		return err
 	}
 
-	defer tunnel.Close()
-	return waitForTunnel(ctx, tunnel, o.open)
+	tunnels := make([]*cluster.Tunnel, len(targets))
+	defer func() {
+		for _, tunnel := range tunnels {
+			if tunnel != nil {
+				tunnel.Close()
+			}
+		}
+	}()
+	errs := make([]error, len(targets))
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		wg.Go(func() {
+			var ti cluster.TunnelInfo
+			var err error
+			if o.wait {
+				ti, err = c.NewTargetTunnelInfoWithWait(lookupCtx, target)
+			} else {
+				ti, err = c.NewTargetTunnelInfo(ctx, target)
+			}
+			if err != nil {
+				errs[i] = fmt.Errorf("unable to create tunnel to %s: %w", target, err)
+				return
+			}
+			tunnels[i], err = c.ConnectTunnelInfo(ctx, targetTunnelInfo(ti, o.zt))
+			if err != nil {
+				errs[i] = fmt.Errorf("unable to connect to %s: %w", target, err)
+			}
+		})
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	urls := make([][]string, len(tunnels))
+	for i, tunnel := range tunnels {
+		urls[i] = tunnel.FullURLs()
+	}
+	printConnectTunnelTable(OutputWriter, targets, urls)
+	if o.open {
+		l.Info("Tunnels established, opening your default web browser (ctrl-c to end)", "count", len(tunnels))
+		for _, targetURLs := range urls {
+			if err := exec.LaunchURL(targetURLs[0]); err != nil {
+				return err
+			}
+		}
+	} else {
+		l.Info("Tunnels established, waiting for user to interrupt (ctrl-c to end)", "count", len(tunnels))
+	}
+
+	lost := make(chan error, len(tunnels))
+	for i, tunnel := range tunnels {
+		go func() {
+			select {
+			case <-ctx.Done():
+			case err := <-tunnel.ErrChan():
+				lost <- fmt.Errorf("lost connection to %s: %w", targets[i], err)
+			}
+		}()
+	}
+	select {
+	case <-ctx.Done():
+		return nil
+	case err := <-lost:
+		return err
+	}
+}
+
+// printConnectTunnelTable prints the local URLs of the tunnel to each target.
+func printConnectTunnelTable(out io.Writer, targets []string, urls [][]string) {
+	tunnelData := [][]string{}
+	for i, target := range targets {
+		tunnelData = append(tunnelData, []string{target, strings.Join(urls[i], ", ")})
+	}
+	header := []string{"Target", "URL"}
+	message.TableWithWriter(out, header, tunnelData)
+}
+
+// startDetached opens the tunnel in a background process and prints it once it is established.
+func (o *connectOptions) startDetached(ctx context.Context, target string) error {
+	name := detachedTunnelName(target, o.zt)
+	if name == "" {
+		return fmt.Errorf("--detach requires a target or a resource name")
+	}
+	if err := validateDetachedTunnelName(name); err != nil {
+		return err
+	}
+	dir, err := connectTunnelsDir()
+	if err != nil {
+		return err
+	}
+	dt, err := startDetachedTunnel(ctx, dir, name)
+	if err != nil {
+		return err
+	}
+	if o.output == string(outputJSON) {
+		return json.NewEncoder(OutputWriter).Encode(newConnectOutput(dt.URLs, dt.Tunnel))
+	}
+	logger.From(ctx).Info("Tunnel established in the background, stop it with zarf connect stop", "name", dt.Name, "pid", dt.PID, "urls", strings.Join(dt.URLs, ", "))
+	return nil
+}
+
+// probeTunnel checks once whether the target is reachable through the tunnel endpoint and prints the result.
+// An error is returned when the target is not reachable so that the command exits with a non-zero code.
+func probeTunnel(ctx context.Context, endpoint string, check v1alpha1.ZarfComponentActionWaitNetwork, out io.Writer) error {
+	check.Address = endpoint
+	condition := ""
+	if check.Code != 0 {
+		condition = strconv.Itoa(check.Code)
+	}
+	var err error
+	if check.Protocol == "tcp" {
+		err = probeTCP(ctx, check.Address)
+	} else {
+		err = wait.ProbeNetwork(ctx, check.Protocol, check.Address, condition, connectProbeTimeout)
+	}
+	if err != nil {
+		return fmt.Errorf("%s probe of %s failed: %w", check.Protocol, check.Address, err)
+	}
+	fmt.Fprintf(out, "%s probe of %s succeeded\n", check.Protocol, check.Address)
+	return nil
+}
+
+// probeTCP connects to the tunnel endpoint and reads from the connection. The local listener of a port forward accepts
+// every connection and only closes it once the remote end refused it, so the remote end is reachable when the
+// connection stays open or sends data.
+func probeTCP(ctx context.Context, address string) (err error) {
+	dialer := net.Dialer{Timeout: connectProbeTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, conn.Close())
+	}()
+	if err := conn.SetReadDeadline(time.Now().Add(connectProbeSettle)); err != nil {
+		return err
+	}
+	_, err = conn.Read(make([]byte, 1))
+	var netErr net.Error
+	if err == nil || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return nil
+	}
+	if errors.Is(err, io.EOF) {
+		return errors.New("the connection was closed through the tunnel, the remote end is not reachable")
+	}
+	return err
+}
+
+// resourceTunnelInfo returns the tunnel info for a resource selected with the connect flags. Resources are looked up
+// in the Zarf namespace unless a namespace is given.
+func resourceTunnelInfo(flags cluster.TunnelInfo) cluster.TunnelInfo {
+	if flags.Namespace == "" {
+		flags.Namespace = state.ZarfNamespaceName
+	}
+	return flags
+}
+
+// targetTunnelInfo merges the connect flags into the tunnel info resolved for a target. The namespace, resource and
+// remote port of the target are authoritative, e.g. a connect-name service keeps the namespace it was found in,
+// only the local port, listen addresses, transport and protocol are taken from the flags.
+func targetTunnelInfo(ti, flags cluster.TunnelInfo) cluster.TunnelInfo {
+	if flags.LocalPort != 0 {
+		ti.LocalPort = flags.LocalPort
+	}
+	ti.ListenAddresses = flags.ListenAddresses
+	ti.Transport = flags.Transport
+	ti.Protocol = flags.Protocol
+	return ti
+}
+
+// connectCommandTemplates are the follow-up commands printed by --print-cmd for each target.
+// They are formatted with the local tunnel endpoint and the push username of the service.
+var connectCommandTemplates = map[string][]string{
+	cluster.ZarfRegistry: {
+		"docker login %[1]s -u %[2]s",
+		"helm registry login %[1]s --insecure -u %[2]s",
+	},
+	cluster.ZarfGit: {
+		"git clone http://%[2]s@%[1]s/%[2]s/<repository>.git",
+	},
+}
+
+// connectCommands returns the ready to run commands for a target that is reachable at the given tunnel endpoint.
+func connectCommands(target, endpoint string, s *state.State) ([]string, error) {
+	target = strings.ToUpper(target)
+	templates, ok := connectCommandTemplates[target]
+	if !ok {
+		return nil, fmt.Errorf("no commands are available for the target %s", target)
+	}
+	var username string
+	switch target {
+	case cluster.ZarfRegistry:
+		username = s.RegistryInfo.PushUsername
+	case cluster.ZarfGit:
+		username = s.GitServer.PushUsername
+	}
+	cmds := make([]string, 0, len(templates))
+	for _, tmpl := range templates {
+		cmds = append(cmds, fmt.Sprintf(tmpl, endpoint, username))
+	}
+	return cmds, nil
+}
+
+// resolveTarget connects to the cluster and resolves the tunnel info for the target. When wait is set both steps are
+// retried until they succeed or the default timeout is reached.
+func (o *connectOptions) resolveTarget(ctx context.Context, target string) (*cluster.Cluster, cluster.TunnelInfo, error) {
+	if !o.wait {
+		c, err := cluster.New(ctx)
+		if err != nil {
+			return nil, cluster.TunnelInfo{}, err
+		}
+		ti, err := c.NewTargetTunnelInfo(ctx, target)
+		return c, ti, err
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, cluster.DefaultTimeout)
+	defer cancel()
+	c, err := cluster.NewWithWait(timeoutCtx)
+	if err != nil {
+		return nil, cluster.TunnelInfo{}, err
+	}
+	ti, err := c.NewTargetTunnelInfoWithWait(timeoutCtx, target)
+	return c, ti, err
+}
+
+// connectOutput describes an established tunnel for --output json.
+type connectOutput struct {
+	URL          string   `json:"url"`
+	URLs         []string `json:"urls"`
+	LocalPort    int      `json:"localPort"`
+	RemotePort   int      `json:"remotePort"`
+	Namespace    string   `json:"namespace"`
+	ResourceType string   `json:"resourceType"`
+	ResourceName string   `json:"resourceName"`
+	Protocol     string   `json:"protocol"`
+}
+
+func newConnectOutput(urls []string, info cluster.TunnelInfo) connectOutput {
+	out := connectOutput{
+		URLs:         urls,
+		LocalPort:    info.LocalPort,
+		RemotePort:   info.RemotePort,
+		Namespace:    info.Namespace,
+		ResourceType: info.ResourceType,
+		ResourceName: info.ResourceName,
+		Protocol:     string(info.Protocol),
+	}
+	if out.Protocol == "" {
+		out.Protocol = string(cluster.TunnelProtocolTCP)
+	}
+	if len(urls) > 0 {
+		out.URL = urls[0]
+	}
+	return out
+}
+
+// validateConnectOutput returns an error for output formats that connect can not print.
+func validateConnectOutput(output string) error {
+	if output != "" && output != string(outputJSON) {
+		return fmt.Errorf("unsupported output format %q, only json is supported", output)
+	}
+	return nil
 }
 
-func waitForTunnel(ctx context.Context, tunnel *cluster.Tunnel, openBrowser bool) error {
+// validateListenAddresses returns an error for listen addresses that are neither an IP address nor localhost, and warns
+// about addresses that make the tunnel reachable from other hosts.
+func validateListenAddresses(ctx context.Context, addresses []string) error {
+	if len(addresses) == 0 {
+		return fmt.Errorf("at least one listen address is required")
+	}
+	for _, addr := range addresses {
+		if addr == "localhost" {
+			continue
+		}
+		ip := net.ParseIP(addr)
+		if ip == nil {
+			return fmt.Errorf("invalid listen address %q, must be an IP address or localhost", addr)
+		}
+		if !ip.IsLoopback() {
+			logger.From(ctx).Warn("the tunnel listens on a non-loopback address, the resource is reachable by anyone who can reach this address on the network",
+				"address", addr)
+		}
+	}
+	return nil
+}
+
+func waitForTunnel(ctx context.Context, tunnel *cluster.Tunnel, openBrowser bool, output string, out io.Writer) error {
 	l := logger.From(ctx)
 	urls := tunnel.FullURLs()
 	if len(urls) == 0 {
 		return fmt.Errorf("no tunnel URLs found")
 	}
 
+	// Print the tunnel before blocking so that scripts can read it while the tunnel stays open
+	if output == string(outputJSON) {
+		if err := json.NewEncoder(out).Encode(newConnectOutput(urls, tunnel.Info())); err != nil {
+			return err
+		}
+	}
+
 	if openBrowser {
 		l.Info("Tunnel established, opening your default web browser (ctrl-c to end)", "urls", strings.Join(urls, ", "))
 		if err := exec.LaunchURL(urls[0]); err != nil {
@@ -121,8 +596,11 @@ func waitForTunnel(ctx context.Context, tunnel *cluster.Tunnel, openBrowser bool
 }
 
 type connectResourceOptions struct {
-	open bool
-	zt   cluster.TunnelInfo
+	open      bool
+	transport string
+	protocol  string
+	output    string
+	zt        cluster.TunnelInfo
 }
 
 func newConnectResourceCommand() *cobra.Command {
@@ -141,6 +619,9 @@ func newConnectResourceCommand() *cobra.Command {
 	cmd.Flags().IntVar(&o.zt.LocalPort, "local-port", 0, lang.CmdConnectResourceFlagLocalPort)
 	cmd.Flags().StringSliceVar(&o.zt.ListenAddresses, "address", []string{helpers.IPV4Localhost}, lang.CmdConnectFlagAddress)
 	cmd.Flags().BoolVar(&o.open, "open", false, lang.CmdConnectFlagOpen)
+	cmd.Flags().StringVar(&o.transport, "transport", string(cluster.TunnelTransportAuto), lang.CmdConnectFlagTransport)
+	cmd.Flags().StringVar(&o.protocol, "protocol", string(cluster.TunnelProtocolTCP), lang.CmdConnectFlagProtocol)
+	cmd.Flags().StringVarP(&o.output, "output", "o", "", lang.CmdConnectFlagOutput)
 
 	_ = cmd.MarkFlagRequired("name")
 	_ = cmd.MarkFlagRequired("namespace")
@@ -152,6 +633,23 @@ func newConnectResourceCommand() *cobra.Command {
 func (o *connectResourceOptions) run(cmd *cobra.Command, _ []string) error {
 	ctx := cmd.Context()
 
+	if err := validateConnectOutput(o.output); err != nil {
+		return err
+	}
+	if err := validateListenAddresses(ctx, o.zt.ListenAddresses); err != nil {
+		return err
+	}
+	transport, err := cluster.ParseTunnelTransport(o.transport)
+	if err != nil {
+		return err
+	}
+	o.zt.Transport = transport
+	protocol, err := cluster.ParseTunnelProtocol(o.protocol)
+	if err != nil {
+		return err
+	}
+	o.zt.Protocol = protocol
+
 	c, err := cluster.New(ctx)
 	if err != nil {
 		return err
@@ -163,11 +661,21 @@ func (o *connectResourceOptions) run(cmd *cobra.Command, _ []string) error {
 	}
 
 	defer tunnel.Close()
-	return waitForTunnel(ctx, tunnel, o.open)
+	return waitForTunnel(ctx, tunnel, o.open, o.output, OutputWriter)
 }
 
 // connectListOptions holds the command-line options for 'connect list' sub-command.
-type connectListOptions struct{}
+type connectListOptions struct {
+	hosts     bool
+	startPort int
+	timeout   time.Duration
+}
+
+// connectHostSuffix is appended to the name of a connection shortcut to form the hostname mapped to its tunnel.
+const connectHostSuffix = ".zarf.internal"
+
+// defaultConnectStartPort is the local port assigned to the first connection shortcut in the hosts entries.
+const defaultConnectStartPort = 42000
 
 // newConnectListCommand creates the `connect list` sub-command.
 func newConnectListCommand() *cobra.Command {
@@ -176,35 +684,105 @@ func newConnectListCommand() *cobra.Command {
 		Use:     "list",
 		Aliases: []string{"l", "ls"},
 		Short:   lang.CmdConnectListShort,
+		Long:    lang.CmdConnectListLong,
 		RunE:    o.run,
 	}
+
+	cmd.Flags().BoolVar(&o.hosts, "hosts", false, lang.CmdConnectListFlagHosts)
+	cmd.Flags().IntVar(&o.startPort, "start-port", defaultConnectStartPort, lang.CmdConnectListFlagStartPort)
+	cmd.Flags().DurationVar(&o.timeout, "timeout", cluster.DefaultTimeout, lang.CmdConnectListFlagTimeout)
+
 	return cmd
 }
 
 func (o *connectListOptions) run(cmd *cobra.Command, _ []string) error {
-	c, err := cluster.New(cmd.Context())
+	if o.startPort < 1 || o.startPort > 65535 {
+		return fmt.Errorf("invalid start port %d, must be between 1 and 65535", o.startPort)
+	}
+	if o.timeout <= 0 {
+		return fmt.Errorf("invalid timeout %s, must be greater than 0", o.timeout)
+	}
+	ctx := cmd.Context()
+	timeoutCtx, cancel := context.WithTimeout(ctx, o.timeout)
+	defer cancel()
+	c, err := cluster.New(timeoutCtx)
+	if err != nil {
+		return err
+	}
+	connections, err := c.ListConnections(timeoutCtx)
+	// Connections found before the timeout are still listed in the table, the hosts entries assign ports by the
+	// position of a shortcut so they are only printed when every connection is known.
+	timedOut := err != nil && connections != nil && !o.hosts
+	if err != nil && !timedOut {
+		return fmt.Errorf("unable to list the connection shortcuts: %w", err)
+	}
+	if timedOut {
+		logger.From(ctx).Warn("listing the connection shortcuts timed out, the table may be incomplete", "timeout", o.timeout, "error", err)
+	}
+	if o.hosts {
+		return printConnectHosts(OutputWriter, connections, o.startPort)
+	}
+	printConnectStringTable(OutputWriter, connections, timedOut)
+
+	dir, err := connectTunnelsDir()
 	if err != nil {
 		return err
 	}
-	connections, err := c.ListConnections(cmd.Context())
+	tunnels, err := listDetachedTunnels(dir, detachedTunnelRunning)
 	if err != nil {
 		return err
 	}
-	printConnectStringTable(connections)
+	printDetachedTunnelTable(tunnels)
 	return nil
 }
 
-func printConnectStringTable(connectStrings state.ConnectStrings) {
-	if len(connectStrings) > 0 {
-		connectData := [][]string{}
-		// Loop over each connectStrings and convert to a string matrix
-		for name, connect := range connectStrings {
-			name = fmt.Sprintf("zarf connect %s", name)
-			connectData = append(connectData, []string{name, connect.Description})
-		}
+// printConnectHosts writes the connection shortcuts as /etc/hosts entries. The shortcuts are sorted by name and
+// assigned consecutive local ports starting at startPort, each entry documents the command that opens the tunnel on
+// its port.
+func printConnectHosts(out io.Writer, connectStrings state.ConnectStrings, startPort int) error {
+	names := slices.Sorted(maps.Keys(connectStrings))
+	if startPort+len(names)-1 > 65535 {
+		return fmt.Errorf("start port %d leaves no room for %d connection shortcuts", startPort, len(names))
+	}
+
+	var b strings.Builder
+	b.WriteString("# Zarf connection shortcuts, open the tunnel of a shortcut before browsing to it\n")
+	for i, name := range names {
+		host := strings.ToLower(name) + connectHostSuffix
+		port := startPort + i
+		fmt.Fprintf(&b, "# %s\n", connectHostsComment(name, connectStrings[name], port))
+		fmt.Fprintf(&b, "# http://%s:%d%s\n", host, port, connectStrings[name].URL)
+		fmt.Fprintf(&b, "%s %s\n", helpers.IPV4Localhost, host)
+	}
+	_, err := io.WriteString(out, b.String())
+	return err
+}
 
-		// Create the table output with the data
-		header := []string{"Connect Command", "Description"}
-		message.TableWithWriter(OutputWriter, header, connectData)
+// connectHostsComment describes a connection shortcut and the command that opens its tunnel on the given port.
+func connectHostsComment(name string, connect state.ConnectString, port int) string {
+	command := fmt.Sprintf("zarf connect %s --local-port %d", name, port)
+	if connect.Description == "" {
+		return command
 	}
+	return fmt.Sprintf("%s (%s)", command, connect.Description)
+}
+
+// printConnectStringTable prints the connection shortcuts, when timedOut is set a final row marks that the shortcuts
+// that were not listed before the timeout are missing.
+func printConnectStringTable(out io.Writer, connectStrings state.ConnectStrings, timedOut bool) {
+	if len(connectStrings) == 0 && !timedOut {
+		return
+	}
+	connectData := [][]string{}
+	// Loop over each connectStrings and convert to a string matrix
+	for _, name := range slices.Sorted(maps.Keys(connectStrings)) {
+		connectData = append(connectData, []string{fmt.Sprintf("zarf connect %s", name), connectStrings[name].Description})
+	}
+	if timedOut {
+		connectData = append(connectData, []string{"...", "timed out, more connection shortcuts may exist"})
+	}
+
+	// Create the table output with the data
+	header := []string{"Connect Command", "Description"}
+	message.TableWithWriter(out, header, connectData)
 }
@@ -0,0 +1,347 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/pkg/logger"
+	"github.com/zarf-dev/zarf/src/pkg/message"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
+)
+
+// connectDetachedEnv is set on the background process started by 'connect --detach' to the name of its tunnel.
+const connectDetachedEnv = "ZARF_CONNECT_DETACHED"
+
+// connectTunnelsPath is the directory that holds the state and log files of detached tunnels.
+const connectTunnelsPath = "~/.zarf/tunnels"
+
+// connectDetachTimeout bounds how long 'connect --detach' waits for the background tunnel to be established.
+const connectDetachTimeout = time.Minute
+
+// connectStopTimeout bounds how long 'connect stop' waits for a detached tunnel to close.
+const connectStopTimeout = 30 * time.Second
+
+// connectStopPollInterval is how often a detached tunnel checks whether 'connect stop' requested it to close.
+const connectStopPollInterval = 250 * time.Millisecond
+
+// detachedTunnel is the state file a detached tunnel writes once it is established.
+type detachedTunnel struct {
+	Name string `json:"name"`
+	PID  int    `json:"pid"`
+	// StartTime identifies the process together with PID so that a pid reused by another process is not mistaken for
+	// the tunnel, its unit depends on the platform
+	StartTime int64              `json:"startTime"`
+	URLs      []string           `json:"urls"`
+	Tunnel    cluster.TunnelInfo `json:"tunnel"`
+}
+
+// detachedTunnelName returns the name a detached tunnel is stored and stopped by.
+func detachedTunnelName(target string, zt cluster.TunnelInfo) string {
+	if target == "" {
+		return strings.ToLower(zt.ResourceName)
+	}
+	return strings.ToLower(target)
+}
+
+// validateDetachedTunnelName rejects names that would place the files of a detached tunnel outside of its directory.
+func validateDetachedTunnelName(name string) error {
+	if name == "" || name == "." || strings.Contains(name, "..") || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid detached tunnel name %q, it must not be empty or contain path separators or ..", name)
+	}
+	return nil
+}
+
+func connectTunnelsDir() (string, error) {
+	return config.GetAbsHomePath(connectTunnelsPath)
+}
+
+func detachedTunnelStatePath(dir, name string) string {
+	return filepath.Join(dir, name+".json")
+}
+
+func detachedTunnelLogPath(dir, name string) string {
+	return filepath.Join(dir, name+".log")
+}
+
+func detachedTunnelStopPath(dir, name string) string {
+	return filepath.Join(dir, name+".stop")
+}
+
+// detachedTunnelRunning reports whether the process of a detached tunnel is still running. The start time of the
+// process must match as well, so a stale state file never leads to signaling an unrelated process that reused the pid.
+func detachedTunnelRunning(dt detachedTunnel) bool {
+	if !processRunning(dt.PID) {
+		return false
+	}
+	startTime, err := processStartTime(dt.PID)
+	if err != nil {
+		return false
+	}
+	return startTime == dt.StartTime
+}
+
+// writeDetachedTunnel records an established detached tunnel in its state file.
+func writeDetachedTunnel(dir string, dt detachedTunnel) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	b, err := json.Marshal(dt)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(detachedTunnelStatePath(dir, dt.Name), b, 0o600)
+}
+
+// readDetachedTunnel reads the state file of the named detached tunnel.
+func readDetachedTunnel(dir, name string) (detachedTunnel, error) {
+	b, err := os.ReadFile(detachedTunnelStatePath(dir, name))
+	if err != nil {
+		return detachedTunnel{}, err
+	}
+	var dt detachedTunnel
+	if err := json.Unmarshal(b, &dt); err != nil {
+		return detachedTunnel{}, fmt.Errorf("unable to read the state of detached tunnel %s: %w", name, err)
+	}
+	return dt, nil
+}
+
+// removeDetachedTunnel deletes the state, log and stop request files of the named detached tunnel.
+func removeDetachedTunnel(dir, name string) error {
+	var errs []error
+	for _, path := range []string{detachedTunnelStatePath(dir, name), detachedTunnelLogPath(dir, name), detachedTunnelStopPath(dir, name)} {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// listDetachedTunnels returns the detached tunnels that are still running sorted by name. The state of tunnels whose
+// process has exited without cleaning up is removed.
+func listDetachedTunnels(dir string, running func(dt detachedTunnel) bool) ([]detachedTunnel, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	tunnels := []detachedTunnel{}
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".json")
+		if entry.IsDir() || !ok {
+			continue
+		}
+		dt, err := readDetachedTunnel(dir, name)
+		if err != nil {
+			return nil, err
+		}
+		if !running(dt) {
+			if err := removeDetachedTunnel(dir, name); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		tunnels = append(tunnels, dt)
+	}
+	slices.SortFunc(tunnels, func(a, b detachedTunnel) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return tunnels, nil
+}
+
+// startDetachedTunnel starts the current command again as a background process and waits for it to record the
+// established tunnel. The background process is marked with connectDetachedEnv so that it holds the tunnel open
+// instead of detaching again.
+func startDetachedTunnel(ctx context.Context, dir, name string) (detachedTunnel, error) {
+	if dt, err := readDetachedTunnel(dir, name); err == nil && detachedTunnelRunning(dt) {
+		return detachedTunnel{}, fmt.Errorf("a detached tunnel named %s is already running, stop it with zarf connect stop %s", name, name)
+	}
+	// Remove what is left of an earlier tunnel of the same name, including a stop request that would close the new one
+	if err := removeDetachedTunnel(dir, name); err != nil {
+		return detachedTunnel{}, err
+	}
+
+	binaryPath, err := utils.GetFinalExecutablePath()
+	if err != nil {
+		return detachedTunnel{}, err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return detachedTunnel{}, err
+	}
+	logFile, err := os.OpenFile(detachedTunnelLogPath(dir, name), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return detachedTunnel{}, err
+	}
+	defer logFile.Close()
+
+	// The background process must outlive this command so it is not tied to ctx
+	//nolint:gosec // the binary and arguments are the ones of the running command
+	bg := exec.Command(binaryPath, os.Args[1:]...)
+	bg.Env = append(os.Environ(), fmt.Sprintf("%s=%s", connectDetachedEnv, name))
+	bg.Stdout = logFile
+	bg.Stderr = logFile
+	bg.SysProcAttr = detachedProcAttr()
+	if err := bg.Start(); err != nil {
+		return detachedTunnel{}, fmt.Errorf("unable to start the detached tunnel: %w", err)
+	}
+	exited := make(chan error, 1)
+	go func() {
+		exited <- bg.Wait()
+	}()
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, connectDetachTimeout)
+	defer cancel()
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-timeoutCtx.Done():
+			_ = bg.Process.Kill()
+			return detachedTunnel{}, fmt.Errorf("detached tunnel %s was not established in time, see %s", name, detachedTunnelLogPath(dir, name))
+		case err := <-exited:
+			return detachedTunnel{}, fmt.Errorf("detached tunnel %s exited before it was established, see %s: %w", name, detachedTunnelLogPath(dir, name), err)
+		case <-ticker.C:
+			dt, err := readDetachedTunnel(dir, name)
+			if err == nil && dt.PID == bg.Process.Pid {
+				return dt, nil
+			}
+		}
+	}
+}
+
+// holdDetachedTunnel records the established tunnel and keeps it open until 'connect stop' signals the process or
+// writes its stop request. The tunnel is closed before its state is removed so that stop only returns once the
+// port-forward is gone.
+func holdDetachedTunnel(ctx context.Context, dir, name string, tunnel *cluster.Tunnel) error {
+	startTime, err := processStartTime(os.Getpid())
+	if err != nil {
+		return fmt.Errorf("unable to identify the detached tunnel process: %w", err)
+	}
+	dt := detachedTunnel{
+		Name:      name,
+		PID:       os.Getpid(),
+		StartTime: startTime,
+		URLs:      tunnel.FullURLs(),
+		Tunnel:    tunnel.Info(),
+	}
+	if err := writeDetachedTunnel(dir, dt); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go watchStopRequest(ctx, cancel, detachedTunnelStopPath(dir, name))
+	err = waitForTunnel(ctx, tunnel, false, "", OutputWriter)
+	tunnel.Close()
+	return errors.Join(err, os.Remove(detachedTunnelStatePath(dir, name)), os.Remove(detachedTunnelStopPath(dir, name)))
+}
+
+// watchStopRequest cancels the detached tunnel once the stop request file exists. It is how 'connect stop' closes
+// tunnels on platforms where the process can not be signaled to shut down gracefully.
+func watchStopRequest(ctx context.Context, cancel context.CancelFunc, path string) {
+	ticker := time.NewTicker(connectStopPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := os.Stat(path); err == nil {
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+// newConnectStopCommand creates the `connect stop` sub-command.
+func newConnectStopCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stop NAME",
+		Short: lang.CmdConnectStopShort,
+		Long:  lang.CmdConnectStopLong,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := connectTunnelsDir()
+			if err != nil {
+				return err
+			}
+			name := strings.ToLower(args[0])
+			if err := validateDetachedTunnelName(name); err != nil {
+				return err
+			}
+			return stopDetachedTunnel(cmd.Context(), dir, name)
+		},
+	}
+	return cmd
+}
+
+// stopDetachedTunnel requests the named detached tunnel to close and waits for it to remove its state.
+func stopDetachedTunnel(ctx context.Context, dir, name string) error {
+	l := logger.From(ctx)
+	dt, err := readDetachedTunnel(dir, name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("no detached tunnel named %s, list the running tunnels with zarf connect list", name)
+	}
+	if err != nil {
+		return err
+	}
+	if !detachedTunnelRunning(dt) {
+		l.Warn("detached tunnel is no longer running, removing its state", "name", name, "pid", dt.PID)
+		return removeDetachedTunnel(dir, name)
+	}
+	if err := os.WriteFile(detachedTunnelStopPath(dir, name), nil, 0o600); err != nil {
+		return fmt.Errorf("unable to request detached tunnel %s to stop: %w", name, err)
+	}
+	if err := stopProcess(dt.PID); err != nil {
+		return fmt.Errorf("unable to stop detached tunnel %s: %w", name, err)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, connectStopTimeout)
+	defer cancel()
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		_, err := os.Stat(detachedTunnelStatePath(dir, name))
+		if errors.Is(err, fs.ErrNotExist) || !detachedTunnelRunning(dt) {
+			l.Info("detached tunnel stopped", "name", name, "pid", dt.PID)
+			return removeDetachedTunnel(dir, name)
+		}
+		select {
+		case <-timeoutCtx.Done():
+			return fmt.Errorf("detached tunnel %s did not stop within %s", name, connectStopTimeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+// printDetachedTunnelTable lists the running detached tunnels with their local ports.
+func printDetachedTunnelTable(tunnels []detachedTunnel) {
+	if len(tunnels) == 0 {
+		return
+	}
+	data := [][]string{}
+	for _, dt := range tunnels {
+		data = append(data, []string{dt.Name, strconv.Itoa(dt.PID), strconv.Itoa(dt.Tunnel.LocalPort), strings.Join(dt.URLs, ", ")})
+	}
+	header := []string{"Detached Tunnel", "PID", "Local Port", "URL"}
+	message.TableWithWriter(OutputWriter, header, data)
+}
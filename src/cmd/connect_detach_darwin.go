@@ -0,0 +1,24 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+//go:build darwin
+
+package cmd
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// processStartTime returns the start time of the process in nanoseconds since the epoch.
+func processStartTime(pid int) (int64, error) {
+	kp, err := unix.SysctlKinfoProc("kern.proc.pid", pid)
+	if err != nil {
+		return 0, err
+	}
+	if int(kp.Proc.P_pid) != pid {
+		return 0, fmt.Errorf("process %d not found", pid)
+	}
+	return kp.Proc.P_starttime.Nano(), nil
+}
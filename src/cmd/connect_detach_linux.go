@@ -0,0 +1,31 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+//go:build linux
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// processStartTime returns the start time of the process in clock ticks since boot, from field 22 of /proc/PID/stat.
+func processStartTime(pid int) (int64, error) {
+	b, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return 0, err
+	}
+	// The command name in field 2 can contain spaces, the remaining fields start after its closing parenthesis
+	idx := strings.LastIndexByte(string(b), ')')
+	if idx == -1 {
+		return 0, fmt.Errorf("unable to parse the stat of process %d", pid)
+	}
+	fields := strings.Fields(string(b[idx+1:]))
+	if len(fields) < 20 {
+		return 0, fmt.Errorf("unable to parse the stat of process %d", pid)
+	}
+	return strconv.ParseInt(fields[19], 10, 64)
+}
@@ -0,0 +1,11 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+//go:build !linux && !darwin && !windows
+
+package cmd
+
+// processStartTime is not available on this platform, detached tunnels are identified by their pid alone.
+func processStartTime(_ int) (int64, error) {
+	return 0, nil
+}
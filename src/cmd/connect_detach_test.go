@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+)
+
+func TestDetachedTunnelName(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "registry", detachedTunnelName("REGISTRY", cluster.TunnelInfo{ResourceName: "zarf-docker-registry"}))
+	require.Equal(t, "podinfo", detachedTunnelName("", cluster.TunnelInfo{ResourceName: "podinfo"}))
+}
+
+func TestListDetachedTunnels(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	tunnels, err := listDetachedTunnels(filepath.Join(dir, "missing"), detachedTunnelRunning)
+	require.NoError(t, err)
+	require.Empty(t, tunnels)
+
+	running := detachedTunnel{
+		Name:   "registry",
+		PID:    1,
+		URLs:   []string{"http://127.0.0.1:42000"},
+		Tunnel: cluster.TunnelInfo{LocalPort: 42000, RemotePort: 5000, Namespace: "zarf", ResourceType: cluster.SvcResource, ResourceName: "zarf-docker-registry"},
+	}
+	stale := detachedTunnel{Name: "git", PID: 2}
+	require.NoError(t, writeDetachedTunnel(dir, running))
+	require.NoError(t, writeDetachedTunnel(dir, stale))
+	require.NoError(t, os.WriteFile(detachedTunnelLogPath(dir, stale.Name), []byte("log"), 0o600))
+
+	tunnels, err = listDetachedTunnels(dir, func(dt detachedTunnel) bool { return dt.PID == running.PID })
+	require.NoError(t, err)
+	require.Equal(t, []detachedTunnel{running}, tunnels)
+	// The state of the stale tunnel is cleaned up
+	require.NoFileExists(t, detachedTunnelStatePath(dir, stale.Name))
+	require.NoFileExists(t, detachedTunnelLogPath(dir, stale.Name))
+	require.FileExists(t, detachedTunnelStatePath(dir, running.Name))
+}
+
+func TestValidateDetachedTunnelName(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validateDetachedTunnelName("registry"))
+	for _, name := range []string{"", ".", "..", "../registry", "a/b", `a\b`, "registry.."} {
+		require.Error(t, validateDetachedTunnelName(name), name)
+	}
+}
+
+func TestDetachedTunnelRunning(t *testing.T) {
+	t.Parallel()
+
+	startTime, err := processStartTime(os.Getpid())
+	require.NoError(t, err)
+	require.True(t, detachedTunnelRunning(detachedTunnel{PID: os.Getpid(), StartTime: startTime}))
+	// A pid that was reused by another process does not match the recorded start time
+	require.False(t, detachedTunnelRunning(detachedTunnel{PID: os.Getpid(), StartTime: startTime + 1}))
+	require.False(t, detachedTunnelRunning(detachedTunnel{PID: exitedPID(t), StartTime: startTime}))
+}
+
+func TestWatchStopRequest(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "registry.stop")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go watchStopRequest(ctx, cancel, path)
+	require.NoError(t, os.WriteFile(path, nil, 0o600))
+	select {
+	case <-ctx.Done():
+	case <-time.After(10 * time.Second):
+		t.Fatal("the stop request did not cancel the tunnel")
+	}
+}
+
+func TestStopDetachedTunnel(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	err := stopDetachedTunnel(context.Background(), dir, "registry")
+	require.EqualError(t, err, "no detached tunnel named registry, list the running tunnels with zarf connect list")
+
+	// A tunnel whose process is gone only has its state removed
+	exited := detachedTunnel{Name: "registry", PID: exitedPID(t)}
+	require.NoError(t, writeDetachedTunnel(dir, exited))
+	require.NoError(t, stopDetachedTunnel(context.Background(), dir, "registry"))
+	require.NoFileExists(t, detachedTunnelStatePath(dir, "registry"))
+}
+
+// exitedPID returns the pid of a process that has already exited.
+func exitedPID(t *testing.T) int {
+	t.Helper()
+	p, err := os.StartProcess(os.Args[0], []string{os.Args[0], "-test.run=^$"}, &os.ProcAttr{})
+	require.NoError(t, err)
+	_, err = p.Wait()
+	require.NoError(t, err)
+	return p.Pid
+}
@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+//go:build windows
+
+package cmd
+
+import (
+	"os"
+	"syscall"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	createNewProcessGroup = 0x00000200
+	detachedProcess       = 0x00000008
+)
+
+// detachedProcAttr starts the detached tunnel without a console so it survives the terminal closing.
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{CreationFlags: createNewProcessGroup | detachedProcess}
+}
+
+// processRunning reports whether a process with the given pid exists.
+func processRunning(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	_ = p.Release()
+	return true
+}
+
+// processStartTime returns the creation time of the process in nanoseconds since the epoch.
+func processStartTime(pid int) (int64, error) {
+	//nolint:gosec // pids fit in a uint32 on Windows
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return 0, err
+	}
+	defer windows.CloseHandle(h) //nolint:errcheck // the handle is only used for reading
+	var creation, exit, kernel, user windows.Filetime
+	if err := windows.GetProcessTimes(h, &creation, &exit, &kernel, &user); err != nil {
+		return 0, err
+	}
+	return creation.Nanoseconds(), nil
+}
+
+// stopProcess does nothing since Windows processes can not be signaled to shut down gracefully. Killing the process
+// would skip closing the tunnel, instead it closes itself once it sees the stop request written by 'connect stop'.
+func stopProcess(_ int) error {
+	return nil
+}
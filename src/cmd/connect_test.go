@@ -0,0 +1,435 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/pkg/state"
+)
+
+func TestConnectCommands(t *testing.T) {
+	t.Parallel()
+
+	s := &state.State{
+		RegistryInfo: state.RegistryInfo{PushUsername: "zarf-push"},
+		GitServer:    state.GitServerInfo{PushUsername: "zarf-git-user"},
+	}
+
+	tests := []struct {
+		name          string
+		target        string
+		expected      []string
+		expectedError string
+	}{
+		{
+			name:   "registry",
+			target: "REGISTRY",
+			expected: []string{
+				"docker login 127.0.0.1:42000 -u zarf-push",
+				"helm registry login 127.0.0.1:42000 --insecure -u zarf-push",
+			},
+		},
+		{
+			name:     "git",
+			target:   "git",
+			expected: []string{"git clone http://zarf-git-user@127.0.0.1:42000/zarf-git-user/<repository>.git"},
+		},
+		{
+			name:          "unsupported target",
+			target:        "injector",
+			expectedError: "no commands are available for the target INJECTOR",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cmds, err := connectCommands(tt.target, "127.0.0.1:42000", s)
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, cmds)
+		})
+	}
+}
+
+func TestConnectTunnelInfoNamespace(t *testing.T) {
+	t.Parallel()
+
+	t.Run("resource defaults to the zarf namespace", func(t *testing.T) {
+		t.Parallel()
+		ti := resourceTunnelInfo(cluster.TunnelInfo{ResourceName: "podinfo", RemotePort: 9898})
+		require.Equal(t, state.ZarfNamespaceName, ti.Namespace)
+	})
+
+	t.Run("resource uses the namespace flag", func(t *testing.T) {
+		t.Parallel()
+		ti := resourceTunnelInfo(cluster.TunnelInfo{ResourceName: "podinfo", RemotePort: 9898, Namespace: "podinfo"})
+		require.Equal(t, "podinfo", ti.Namespace)
+	})
+
+	t.Run("target keeps its own namespace", func(t *testing.T) {
+		t.Parallel()
+		target := cluster.TunnelInfo{
+			Namespace:       "podinfo",
+			ResourceType:    cluster.SvcResource,
+			ResourceName:    "podinfo",
+			RemotePort:      9898,
+			ListenAddresses: []string{"127.0.0.1"},
+		}
+		flags := cluster.TunnelInfo{
+			Namespace:       "other",
+			ResourceType:    cluster.PodResource,
+			ResourceName:    "other",
+			RemotePort:      8080,
+			LocalPort:       42000,
+			ListenAddresses: []string{"0.0.0.0"},
+			Transport:       cluster.TunnelTransportSPDY,
+			Protocol:        cluster.TunnelProtocolUDP,
+		}
+		ti := targetTunnelInfo(target, flags)
+		require.Equal(t, cluster.TunnelInfo{
+			Namespace:       "podinfo",
+			ResourceType:    cluster.SvcResource,
+			ResourceName:    "podinfo",
+			RemotePort:      9898,
+			LocalPort:       42000,
+			ListenAddresses: []string{"0.0.0.0"},
+			Transport:       cluster.TunnelTransportSPDY,
+			Protocol:        cluster.TunnelProtocolUDP,
+		}, ti)
+	})
+
+	t.Run("target keeps its local port without the flag", func(t *testing.T) {
+		t.Parallel()
+		ti := targetTunnelInfo(cluster.TunnelInfo{Namespace: state.ZarfNamespaceName, LocalPort: 31999}, cluster.TunnelInfo{})
+		require.Equal(t, state.ZarfNamespaceName, ti.Namespace)
+		require.Equal(t, 31999, ti.LocalPort)
+	})
+}
+
+func TestProbeTunnel(t *testing.T) {
+	t.Parallel()
+
+	okServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(okServer.Close)
+	unauthorizedServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	t.Cleanup(unauthorizedServer.Close)
+	// A listener that is closed right away gives an address that refuses connections
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	refusedAddress := closed.Addr().String()
+	require.NoError(t, closed.Close())
+
+	// A listener that closes every connection it accepts behaves like a port forward to a remote port that refuses it
+	closing, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, closing.Close())
+	})
+	go func() {
+		for {
+			conn, err := closing.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	closingAddress := closing.Addr().String()
+
+	okAddress := strings.TrimPrefix(okServer.URL, "http://")
+	unauthorizedAddress := strings.TrimPrefix(unauthorizedServer.URL, "http://")
+
+	tests := []struct {
+		name          string
+		endpoint      string
+		check         v1alpha1.ZarfComponentActionWaitNetwork
+		expectedOut   string
+		expectedError string
+	}{
+		{
+			name:        "tcp reachable",
+			endpoint:    okAddress,
+			check:       v1alpha1.ZarfComponentActionWaitNetwork{Protocol: "tcp"},
+			expectedOut: fmt.Sprintf("tcp probe of %s succeeded\n", okAddress),
+		},
+		{
+			name:        "http success",
+			endpoint:    okAddress,
+			check:       v1alpha1.ZarfComponentActionWaitNetwork{Protocol: "http"},
+			expectedOut: fmt.Sprintf("http probe of %s succeeded\n", okAddress),
+		},
+		{
+			name:        "http expected status code",
+			endpoint:    unauthorizedAddress,
+			check:       v1alpha1.ZarfComponentActionWaitNetwork{Protocol: "http", Code: http.StatusUnauthorized},
+			expectedOut: fmt.Sprintf("http probe of %s succeeded\n", unauthorizedAddress),
+		},
+		{
+			name:          "http wrong status code",
+			endpoint:      unauthorizedAddress,
+			check:         v1alpha1.ZarfComponentActionWaitNetwork{Protocol: "http"},
+			expectedError: fmt.Sprintf("http probe of %[1]s failed: http://%[1]s responded with status code 401, expected a 2xx status code", unauthorizedAddress),
+		},
+		{
+			name:          "tcp connection refused",
+			endpoint:      refusedAddress,
+			check:         v1alpha1.ZarfComponentActionWaitNetwork{Protocol: "tcp"},
+			expectedError: "connection refused",
+		},
+		{
+			name:          "tcp connection closed through the tunnel",
+			endpoint:      closingAddress,
+			check:         v1alpha1.ZarfComponentActionWaitNetwork{Protocol: "tcp"},
+			expectedError: fmt.Sprintf("tcp probe of %s failed: the connection was closed through the tunnel, the remote end is not reachable", closingAddress),
+		},
+		{
+			name:          "http connection refused",
+			endpoint:      refusedAddress,
+			check:         v1alpha1.ZarfComponentActionWaitNetwork{Protocol: "http"},
+			expectedError: "connection refused",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var out bytes.Buffer
+			err := probeTunnel(context.Background(), tt.endpoint, tt.check, &out)
+			if tt.expectedError != "" {
+				require.ErrorContains(t, err, tt.expectedError)
+				require.Empty(t, out.String())
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedOut, out.String())
+		})
+	}
+}
+
+func TestWaitForTunnelOutput(t *testing.T) {
+	t.Parallel()
+
+	tunnel, err := (&cluster.Cluster{}).NewTunnel("zarf", cluster.SvcResource, "zarf-docker-registry", "/v2/_catalog", 42000, 5000)
+	require.NoError(t, err)
+	// A canceled context returns as soon as the tunnel has been printed
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var out bytes.Buffer
+	err = waitForTunnel(ctx, tunnel, false, "json", &out)
+	require.NoError(t, err)
+	expected := `{"url":"http://127.0.0.1:42000/v2/_catalog","urls":["http://127.0.0.1:42000/v2/_catalog"],"localPort":42000,"remotePort":5000,"namespace":"zarf","resourceType":"svc","resourceName":"zarf-docker-registry","protocol":"tcp"}
+`
+	require.Equal(t, expected, out.String())
+
+	out.Reset()
+	err = waitForTunnel(ctx, tunnel, false, "", &out)
+	require.NoError(t, err)
+	require.Empty(t, out.String())
+
+	require.NoError(t, validateConnectOutput(""))
+	require.NoError(t, validateConnectOutput("json"))
+	require.EqualError(t, validateConnectOutput("yaml"), `unsupported output format "yaml", only json is supported`)
+}
+
+func TestValidateListenAddresses(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		addresses     []string
+		expectedError string
+	}{
+		{
+			name:      "loopback",
+			addresses: []string{"127.0.0.1", "::1", "localhost"},
+		},
+		{
+			name:      "all interfaces",
+			addresses: []string{"0.0.0.0"},
+		},
+		{
+			name:          "hostname",
+			addresses:     []string{"127.0.0.1", "example.com"},
+			expectedError: `invalid listen address "example.com", must be an IP address or localhost`,
+		},
+		{
+			name:          "empty",
+			expectedError: "at least one listen address is required",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := validateListenAddresses(context.Background(), tt.addresses)
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestPrintConnectHosts(t *testing.T) {
+	t.Parallel()
+
+	connections := state.ConnectStrings{
+		"podinfo":  {Description: "Web interface for podinfo", URL: "/ui"},
+		"registry": {Description: "Internal Zarf Registry (run zarf tools registry login to authenticate)", URL: "/v2/_catalog"},
+		"Grafana":  {},
+	}
+
+	tests := []struct {
+		name          string
+		startPort     int
+		expected      string
+		expectedError string
+	}{
+		{
+			name:      "hosts",
+			startPort: 42000,
+			expected: `# Zarf connection shortcuts, open the tunnel of a shortcut before browsing to it
+# zarf connect Grafana --local-port 42000
+# http://grafana.zarf.internal:42000
+127.0.0.1 grafana.zarf.internal
+# zarf connect podinfo --local-port 42001 (Web interface for podinfo)
+# http://podinfo.zarf.internal:42001/ui
+127.0.0.1 podinfo.zarf.internal
+# zarf connect registry --local-port 42002 (Internal Zarf Registry (run zarf tools registry login to authenticate))
+# http://registry.zarf.internal:42002/v2/_catalog
+127.0.0.1 registry.zarf.internal
+`,
+		},
+		{
+			name:          "ports out of range",
+			startPort:     65534,
+			expectedError: "start port 65534 leaves no room for 3 connection shortcuts",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var buf bytes.Buffer
+			err := printConnectHosts(&buf, connections, tt.startPort)
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
+
+func TestPrintConnectStringTableTimedOut(t *testing.T) {
+	t.Parallel()
+
+	connections := state.ConnectStrings{
+		"podinfo": state.ConnectString{Description: "Podinfo UI"},
+		"grafana": state.ConnectString{Description: "Grafana UI"},
+	}
+	var buf bytes.Buffer
+	printConnectStringTable(&buf, connections, true)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 4)
+	require.Contains(t, lines[1], "zarf connect grafana")
+	require.Contains(t, lines[2], "zarf connect podinfo")
+	require.Contains(t, lines[3], "timed out, more connection shortcuts may exist")
+
+	buf.Reset()
+	printConnectStringTable(&buf, state.ConnectStrings{}, false)
+	require.Empty(t, buf.String())
+}
+
+func TestValidateMultipleTargets(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		opts          connectOptions
+		targets       []string
+		expectedError string
+	}{
+		{
+			name:    "distinct targets",
+			opts:    connectOptions{open: true, wait: true},
+			targets: []string{"registry", "git", "my-app"},
+		},
+		{
+			name:          "duplicate target",
+			targets:       []string{"registry", "my-app", "registry"},
+			expectedError: "target registry is given more than once",
+		},
+		{
+			name:          "local port",
+			opts:          connectOptions{zt: cluster.TunnelInfo{LocalPort: 42000}},
+			targets:       []string{"registry", "git"},
+			expectedError: "--local-port can only be used with a single target",
+		},
+		{
+			name:          "detach",
+			opts:          connectOptions{detach: true},
+			targets:       []string{"registry", "git"},
+			expectedError: "--detach can only be used with a single target",
+		},
+		{
+			name:          "output",
+			opts:          connectOptions{output: "json"},
+			targets:       []string{"registry", "git"},
+			expectedError: "--output can only be used with a single target",
+		},
+		{
+			name:          "logs",
+			opts:          connectOptions{logs: true},
+			targets:       []string{"registry", "git"},
+			expectedError: "--logs can only be used with a single target",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.opts.validateMultipleTargets(tt.targets)
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestPrintConnectTunnelTable(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	printConnectTunnelTable(&buf, []string{"registry", "my-app"}, [][]string{
+		{"http://127.0.0.1:40001/v2/_catalog"},
+		{"http://127.0.0.1:40002", "http://10.0.0.1:40002"},
+	})
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+	require.Contains(t, lines[1], "registry")
+	require.Contains(t, lines[1], "http://127.0.0.1:40001/v2/_catalog")
+	require.Contains(t, lines[2], "my-app")
+	require.Contains(t, lines[2], "http://127.0.0.1:40002, http://10.0.0.1:40002")
+}
@@ -56,6 +56,9 @@ type initOptions struct {
 	agentTLSCAPath          string
 	agentTLSCertPath        string
 	agentTLSKeyPath         string
+	agentImageFields        []string
+	agentImageRewrites      []string
+	agentImagePassthrough   []string
 }
 
 func newInitCommand() *cobra.Command {
@@ -117,6 +120,11 @@ func newInitCommand() *cobra.Command {
 	cmd.Flags().StringVar(&o.agentTLSCertPath, "agent-tls-cert", v.GetString(VInitAgentTLSCert), "Path to a PEM-encoded TLS certificate for the Zarf agent")
 	cmd.Flags().StringVar(&o.agentTLSKeyPath, "agent-tls-key", v.GetString(VInitAgentTLSKey), "Path to a PEM-encoded TLS private key for the Zarf agent")
 
+	// Flags that configure the custom resources the agent mutates
+	cmd.Flags().StringArrayVar(&o.agentImageFields, "agent-image-field", v.GetStringSlice(VInitAgentImageFields), lang.CmdInitFlagAgentImageField)
+	cmd.Flags().StringArrayVar(&o.agentImageRewrites, "agent-image-rewrite", v.GetStringSlice(VInitAgentImageRewrites), lang.CmdInitFlagAgentImageRewrite)
+	cmd.Flags().StringSliceVar(&o.agentImagePassthrough, "agent-image-passthrough", v.GetStringSlice(VInitAgentImagePassthrough), lang.CmdInitFlagAgentImagePassthrough)
+
 	// Flags that control how a deployment proceeds
 	// Always require adopt-existing-resources flag (no viper)
 	cmd.Flags().BoolVar(&o.adoptExistingResources, "adopt-existing-resources", false, lang.CmdPackageDeployFlagAdoptExistingResources)
@@ -178,6 +186,18 @@ func (o *initOptions) run(cmd *cobra.Command, args []string) error {
 		agentTLS = &loadedTLS
 	}
 
+	agentImageFields, err := state.ParseAgentImageFields(o.agentImageFields)
+	if err != nil {
+		return err
+	}
+	agentImageRewrites, err := state.ParseAgentImageRewrites(o.agentImageRewrites)
+	if err != nil {
+		return err
+	}
+	if err := state.ValidateAgentImagePassthrough(o.agentImagePassthrough); err != nil {
+		return err
+	}
+
 	err = validateExistingStateMatchesInput(cmd.Context(), o.registryInfo, o.gitServer, o.artifactServer, agentTLS)
 	if err != nil {
 		return err
@@ -251,6 +271,9 @@ func (o *initOptions) run(cmd *cobra.Command, args []string) error {
 		RemoteOptions:          defaultRemoteOptions(),
 		IsInteractive:          !o.confirm,
 		AgentTLS:               agentTLS,
+		AgentImageFields:       agentImageFields,
+		AgentImageRewrites:     agentImageRewrites,
+		AgentImagePassthrough:  o.agentImagePassthrough,
 	}
 	_, err = deploy(ctx, pkgLayout, opts, o.setVariables, o.optionalComponents)
 	if err != nil {
@@ -11,6 +11,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/spf13/cobra"
@@ -19,6 +20,7 @@ import (
 	"github.com/zarf-dev/zarf/src/config/lang"
 	"github.com/zarf-dev/zarf/src/internal/agent"
 	"github.com/zarf-dev/zarf/src/internal/gitea"
+	"github.com/zarf-dev/zarf/src/pkg/archive"
 	"github.com/zarf-dev/zarf/src/pkg/cluster"
 	"github.com/zarf-dev/zarf/src/pkg/logger"
 	"github.com/zarf-dev/zarf/src/pkg/state"
@@ -39,11 +41,19 @@ func newInternalCommand(rootCmd *cobra.Command) *cobra.Command {
 	cmd.AddCommand(newInternalUpdateGiteaPVCCommand())
 	cmd.AddCommand(newInternalIsValidHostnameCommand())
 	cmd.AddCommand(newInternalCrc32Command())
+	cmd.AddCommand(newInternalReceiveDataInjectionCommand())
+	cmd.AddCommand(newInternalUDPRelayCommand())
 
 	return cmd
 }
 
-type internalAgentOptions struct{}
+type internalAgentOptions struct {
+	auditURL      string
+	auditStrict   bool
+	dryRun        bool
+	stateTimeout  time.Duration
+	stateFailOpen bool
+}
 
 func newInternalAgentCommand() *cobra.Command {
 	o := &internalAgentOptions{}
@@ -55,6 +65,13 @@ func newInternalAgentCommand() *cobra.Command {
 		RunE:  o.run,
 	}
 
+	v := getViper()
+	cmd.Flags().StringVar(&o.auditURL, "audit-url", v.GetString(VInternalAgentAuditURL), lang.CmdInternalAgentFlagAuditURL)
+	cmd.Flags().BoolVar(&o.auditStrict, "audit-strict", v.GetBool(VInternalAgentAuditStrict), lang.CmdInternalAgentFlagAuditStrict)
+	cmd.Flags().BoolVar(&o.dryRun, "dry-run", v.GetBool(VInternalAgentDryRun), lang.CmdInternalAgentFlagDryRun)
+	cmd.Flags().DurationVar(&o.stateTimeout, "state-timeout", v.GetDuration(VInternalAgentStateTimeout), lang.CmdInternalAgentFlagStateTimeout)
+	cmd.Flags().BoolVar(&o.stateFailOpen, "state-fail-open", v.GetBool(VInternalAgentStateFailOpen), lang.CmdInternalAgentFlagStateFailOpen)
+
 	return cmd
 }
 
@@ -64,7 +81,13 @@ func (o *internalAgentOptions) run(cmd *cobra.Command, _ []string) error {
 	if err != nil {
 		return err
 	}
-	return agent.StartWebhook(ctx, c)
+	return agent.StartWebhook(ctx, c, agent.WebhookOptions{
+		AuditURL:      o.auditURL,
+		AuditStrict:   o.auditStrict,
+		DryRun:        o.dryRun,
+		StateTimeout:  o.stateTimeout,
+		StateFailOpen: o.stateFailOpen,
+	})
 }
 
 type internalHTTPProxyOptions struct{}
@@ -135,6 +158,7 @@ func (o *internalGenCliDocsOptions) run(_ *cobra.Command, _ []string) error {
 					addHiddenDummyFlag(toolCmd, "zarf-cache")
 					addHiddenDummyFlag(toolCmd, "tmpdir")
 					addHiddenDummyFlag(toolCmd, "no-color")
+					addHiddenDummyFlag(toolCmd, "run-id")
 				}
 
 				// Remove the docs for the various sub-commands for `kubectl` and `helm`
@@ -423,3 +447,55 @@ func (o *internalCrc32Options) run(_ *cobra.Command, args []string) {
 	hash := helpers.GetCRCHash(text)
 	fmt.Printf("%d\n", hash)
 }
+
+type internalReceiveDataInjectionOptions struct {
+	compress    bool
+	compression string
+}
+
+func newInternalReceiveDataInjectionCommand() *cobra.Command {
+	o := &internalReceiveDataInjectionOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "receive-data-injection DESTINATION",
+		Short: lang.CmdInternalReceiveDataInjectionShort,
+		Args:  cobra.ExactArgs(1),
+		RunE:  o.run,
+	}
+
+	cmd.Flags().BoolVar(&o.compress, "compress", false, lang.CmdInternalReceiveDataInjectionFlagCompress)
+	cmd.Flags().StringVar(&o.compression, "compression", "", lang.CmdInternalReceiveDataInjectionFlagCompression)
+
+	return cmd
+}
+
+func (o *internalReceiveDataInjectionOptions) run(cmd *cobra.Command, args []string) error {
+	compression := o.compression
+	if compression == "" && o.compress {
+		compression = archive.StreamCompressionGzip
+	}
+	return archive.DecompressStream(cmd.Context(), os.Stdin, args[0], compression)
+}
+
+type internalUDPRelayOptions struct {
+	port int
+}
+
+func newInternalUDPRelayCommand() *cobra.Command {
+	o := &internalUDPRelayOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "udp-relay TARGET",
+		Short: lang.CmdInternalUDPRelayShort,
+		Args:  cobra.ExactArgs(1),
+		RunE:  o.run,
+	}
+
+	cmd.Flags().IntVar(&o.port, "port", cluster.ZarfUDPRelayPort, lang.CmdInternalUDPRelayFlagPort)
+
+	return cmd
+}
+
+func (o *internalUDPRelayOptions) run(cmd *cobra.Command, args []string) error {
+	return cluster.ServeUDPRelay(cmd.Context(), fmt.Sprintf(":%d", o.port), args[0])
+}
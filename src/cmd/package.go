@@ -16,6 +16,7 @@ import (
 	"regexp"
 	"runtime"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -31,6 +32,7 @@ import (
 	"github.com/zarf-dev/zarf/src/config/lang"
 	"github.com/zarf-dev/zarf/src/pkg/cluster"
 	"github.com/zarf-dev/zarf/src/pkg/images"
+	"github.com/zarf-dev/zarf/src/pkg/interactive"
 	"github.com/zarf-dev/zarf/src/pkg/lint"
 	"github.com/zarf-dev/zarf/src/pkg/logger"
 	"github.com/zarf-dev/zarf/src/pkg/message"
@@ -38,8 +40,10 @@ import (
 	"github.com/zarf-dev/zarf/src/pkg/packager/filters"
 	"github.com/zarf-dev/zarf/src/pkg/packager/layout"
 	"github.com/zarf-dev/zarf/src/pkg/state"
+	"github.com/zarf-dev/zarf/src/pkg/tracing"
 	"github.com/zarf-dev/zarf/src/pkg/utils"
 	"github.com/zarf-dev/zarf/src/pkg/zoci"
+	"github.com/zarf-dev/zarf/src/types"
 )
 
 func newPackageCommand() *cobra.Command {
@@ -75,12 +79,23 @@ type packageCreateOptions struct {
 	skipSBOM                bool
 	maxPackageSizeMB        int
 	registryOverrides       []string
+	allowedRegistries       []string
+	deniedRegistries        []string
 	signingKeyPath          string
 	signingKeyPassword      string
 	flavor                  string
+	allFlavors              bool
+	allArchitectures        bool
 	ociConcurrency          int
 	skipVersionCheck        bool
 	withBuildMachineInfo    bool
+	pinDigests              bool
+	noCache                 bool
+	noImportCache           bool
+	imageConcurrency        int
+	chartCertFiles          map[string]string
+	chartKeyFiles           map[string]string
+	chartCAFiles            map[string]string
 }
 
 func newPackageCreateCommand(v *viper.Viper) *cobra.Command {
@@ -117,7 +132,11 @@ func newPackageCreateCommand(v *viper.Viper) *cobra.Command {
 	cmd.Flags().BoolVar(&o.skipSBOM, "skip-sbom", v.GetBool(VPkgCreateSkipSbom), lang.CmdPackageCreateFlagSkipSbom)
 	cmd.Flags().IntVarP(&o.maxPackageSizeMB, "max-package-size", "m", v.GetInt(VPkgCreateMaxPackageSize), lang.CmdPackageCreateFlagMaxPackageSize)
 	cmd.Flags().StringSliceVar(&o.registryOverrides, "registry-override", GetStringSlice(v, VPkgCreateRegistryOverride), lang.CmdPackageCreateFlagRegistryOverride)
+	cmd.Flags().StringSliceVar(&o.allowedRegistries, "allowed-registries", GetStringSlice(v, VPkgCreateAllowedRegistries), lang.CmdPackageCreateFlagAllowedRegistries)
+	cmd.Flags().StringSliceVar(&o.deniedRegistries, "denied-registries", GetStringSlice(v, VPkgCreateDeniedRegistries), lang.CmdPackageCreateFlagDeniedRegistries)
 	cmd.Flags().StringVarP(&o.flavor, "flavor", "f", v.GetString(VPkgCreateFlavor), lang.CmdPackageCreateFlagFlavor)
+	cmd.Flags().BoolVar(&o.allFlavors, "all-flavors", v.GetBool(VPkgCreateAllFlavors), lang.CmdPackageCreateFlagAllFlavors)
+	cmd.Flags().BoolVar(&o.allArchitectures, "all-architectures", v.GetBool(VPkgCreateAllArchitectures), lang.CmdPackageCreateFlagAllArchitectures)
 	cmd.Flags().BoolVar(&o.skipVersionCheck, "skip-version-check", false, "Ignore version requirements when deploying the package")
 	_ = cmd.Flags().MarkHidden("skip-version-check")
 
@@ -125,6 +144,14 @@ func newPackageCreateCommand(v *viper.Viper) *cobra.Command {
 	cmd.Flags().StringVar(&o.signingKeyPassword, "signing-key-pass", v.GetString(VPkgCreateSigningKeyPassword), lang.CmdPackageCreateFlagSigningKeyPassword)
 
 	cmd.Flags().BoolVar(&o.withBuildMachineInfo, "with-build-machine-info", v.GetBool(VPkgCreateWithBuildMachineInfo), lang.CmdPackageCreateFlagWithBuildMachineInfo)
+	cmd.Flags().BoolVar(&o.pinDigests, "pin-digests", v.GetBool(VPkgCreatePinDigests), lang.CmdPackageCreateFlagPinDigests)
+	cmd.Flags().BoolVar(&o.noCache, "no-cache", v.GetBool(VPkgCreateNoCache), lang.CmdPackageCreateFlagNoCache)
+	cmd.Flags().BoolVar(&o.noImportCache, "no-import-cache", v.GetBool(VPkgCreateNoImportCache), lang.CmdPackageCreateFlagNoImportCache)
+	cmd.Flags().IntVar(&o.imageConcurrency, "image-concurrency", v.GetInt(VPkgCreateImageConcurrency), lang.CmdPackageCreateFlagImageConcurrency)
+
+	cmd.Flags().StringToStringVar(&o.chartCertFiles, "chart-cert-file", v.GetStringMapString(VPkgCreateChartCertFile), lang.CmdPackageCreateFlagChartCertFile)
+	cmd.Flags().StringToStringVar(&o.chartKeyFiles, "chart-key-file", v.GetStringMapString(VPkgCreateChartKeyFile), lang.CmdPackageCreateFlagChartKeyFile)
+	cmd.Flags().StringToStringVar(&o.chartCAFiles, "chart-ca-file", v.GetStringMapString(VPkgCreateChartCAFile), lang.CmdPackageCreateFlagChartCAFile)
 
 	cmd.Flags().StringVarP(&o.signingKeyPath, "key", "k", v.GetString(VPkgCreateSigningKey), lang.CmdPackageCreateFlagDeprecatedKey)
 	cmd.Flags().StringVar(&o.signingKeyPassword, "key-pass", v.GetString(VPkgCreateSigningKeyPassword), lang.CmdPackageCreateFlagDeprecatedKeyPassword)
@@ -185,12 +212,42 @@ func parseRegistryOverrides(overrides []string) ([]images.RegistryOverride, erro
 	return result, nil
 }
 
+// parseChartRepoTLS groups the certificate files given per Helm chart repository host by host.
+func parseChartRepoTLS(certFiles, keyFiles, caFiles map[string]string) (map[string]types.ClientTLSOptions, error) {
+	result := map[string]types.ClientTLSOptions{}
+	for host, file := range certFiles {
+		if _, ok := keyFiles[host]; !ok {
+			return nil, fmt.Errorf("chart repository %s has a client certificate without a key", host)
+		}
+		opts := result[host]
+		opts.CertFile = file
+		result[host] = opts
+	}
+	for host, file := range keyFiles {
+		if _, ok := certFiles[host]; !ok {
+			return nil, fmt.Errorf("chart repository %s has a client key without a certificate", host)
+		}
+		opts := result[host]
+		opts.KeyFile = file
+		result[host] = opts
+	}
+	for host, file := range caFiles {
+		opts := result[host]
+		opts.CAFile = file
+		result[host] = opts
+	}
+	return result, nil
+}
+
 func (o *packageCreateOptions) run(ctx context.Context, args []string) error {
 	l := logger.From(ctx)
 	basePath, err := setBaseDirectory(args)
 	if err != nil {
 		return err
 	}
+	if o.allFlavors && o.flavor != "" {
+		return errors.New("--all-flavors and --flavor can not be used together")
+	}
 
 	var isCleanPathRegex = regexp.MustCompile(`^[a-zA-Z0-9\_\-\/\.\~\\:]+$`)
 	if !isCleanPathRegex.MatchString(config.CommonOptions.CachePath) {
@@ -205,14 +262,24 @@ func (o *packageCreateOptions) run(ctx context.Context, args []string) error {
 		return fmt.Errorf("error parsing registry override: %w", err)
 	}
 	l.Debug("parsed registry overrides", "overrides", overrides)
+	chartRepoTLS, err := parseChartRepoTLS(o.chartCertFiles, o.chartKeyFiles, o.chartCAFiles)
+	if err != nil {
+		return err
+	}
 
 	cachePath, err := getCachePath(ctx)
 	if err != nil {
 		return err
 	}
 	opt := packager.CreateOptions{
-		Flavor:                  o.flavor,
-		RegistryOverrides:       overrides,
+		Flavor:            o.flavor,
+		AllFlavors:        o.allFlavors,
+		AllArchitectures:  o.allArchitectures,
+		RegistryOverrides: overrides,
+		RegistryPolicy: images.RegistryPolicy{
+			AllowedRegistries: o.allowedRegistries,
+			DeniedRegistries:  o.deniedRegistries,
+		},
 		SigningKeyPath:          o.signingKeyPath,
 		SigningKeyPassword:      o.signingKeyPassword,
 		SetVariables:            o.setVariables,
@@ -226,7 +293,12 @@ func (o *packageCreateOptions) run(ctx context.Context, args []string) error {
 		IsInteractive:           !o.confirm,
 		SkipVersionCheck:        o.skipVersionCheck,
 		WithBuildMachineInfo:    o.withBuildMachineInfo,
+		PinDigests:              o.pinDigests,
+		NoCache:                 o.noCache,
+		NoImportCache:           o.noImportCache,
+		ImageConcurrency:        o.imageConcurrency,
 	}
+	opt.RemoteOptions.ChartRepoTLS = chartRepoTLS
 	pkgPath, err := packager.Create(ctx, basePath, o.output, opt)
 	// NOTE(mkcp): LintErrors are rendered with a table
 	var lintErr *lint.LintError
@@ -248,18 +320,29 @@ type packageDeployOptions struct {
 	connected               bool
 	forceConflicts          bool
 	timeout                 time.Duration
+	deployTimeout           time.Duration
 	retries                 int
 	setVariables            map[string]string
 	setValues               map[string]string
 	optionalComponents      string
+	flavor                  string
 	shasum                  string
 	verify                  bool
 	skipSignatureValidation bool
 	skipVersionCheck        bool
 	ociConcurrency          int
 	publicKeyPath           string
+	step                    bool
+	keepGoing               bool
+	validateSchema          bool
+	actionTags              []string
+	runUntaggedActions      bool
+	otelEndpoint            string
 }
 
+// otelShutdownTimeout bounds flushing the remaining spans to the OTLP endpoint once the deploy is done.
+const otelShutdownTimeout = 10 * time.Second
+
 func newPackageDeployCommand(v *viper.Viper) *cobra.Command {
 	o := &packageDeployOptions{}
 
@@ -282,7 +365,16 @@ func newPackageDeployCommand(v *viper.Viper) *cobra.Command {
 	cmd.Flags().BoolVar(&o.adoptExistingResources, "adopt-existing-resources", false, lang.CmdPackageDeployFlagAdoptExistingResources)
 	cmd.Flags().BoolVar(&o.connected, "connected", v.GetBool(VPkgDeployConnected), lang.CmdPackageDeployFlagConnected)
 	cmd.Flags().BoolVar(&o.forceConflicts, "force-conflicts", false, lang.CmdPackageDeployFlagForceConflicts)
+	cmd.Flags().BoolVar(&o.keepGoing, "keep-going", v.GetBool(VPkgDeployKeepGoing), lang.CmdPackageDeployFlagKeepGoing)
+	cmd.Flags().BoolVar(&o.validateSchema, "validate-schema", v.GetBool(VPkgDeployValidateSchema), lang.CmdPackageDeployFlagValidateSchema)
+	// Always require step flag (no viper)
+	cmd.Flags().BoolVar(&o.step, "step", false, lang.CmdPackageDeployFlagStep)
+	// Action selection is specific to a single run (no viper)
+	cmd.Flags().StringSliceVar(&o.actionTags, "only-actions-tagged", nil, lang.CmdPackageDeployFlagOnlyActionsTagged)
+	cmd.Flags().BoolVar(&o.runUntaggedActions, "run-untagged-actions", false, lang.CmdPackageDeployFlagRunUntaggedActions)
 	cmd.Flags().DurationVar(&o.timeout, "timeout", v.GetDuration(VPkgDeployTimeout), lang.CmdPackageDeployFlagTimeout)
+	cmd.Flags().DurationVar(&o.deployTimeout, "deploy-timeout", v.GetDuration(VPkgDeployDeployTimeout), lang.CmdPackageDeployFlagDeployTimeout)
+	cmd.Flags().StringVar(&o.otelEndpoint, "otel-endpoint", v.GetString(VPkgDeployOtelEndpoint), lang.CmdPackageDeployFlagOtelEndpoint)
 
 	cmd.Flags().StringSliceVarP(&o.valuesFiles, "values", "v", GetStringSlice(v, VPkgDeployValues), lang.CmdPackageDeployFlagValuesFiles)
 	cmd.Flags().IntVar(&o.retries, "retries", v.GetInt(VPkgRetries), lang.CmdPackageFlagRetries)
@@ -291,6 +383,7 @@ func newPackageDeployCommand(v *viper.Viper) *cobra.Command {
 	cmd.Flags().StringToStringVar(&o.setVariables, "set-variables", v.GetStringMapString(VPkgDeploySet), lang.CmdPackageDeployFlagSetVariables)
 	cmd.Flags().StringToStringVar(&o.setValues, "set-values", v.GetStringMapString(VPkgDeploySetValues), lang.CmdPackageDeployFlagSetValues)
 	cmd.Flags().StringVar(&o.optionalComponents, "components", v.GetString(VPkgDeployComponents), lang.CmdPackageDeployFlagComponents)
+	cmd.Flags().StringVarP(&o.flavor, "flavor", "f", v.GetString(VPkgDeployFlavor), lang.CmdPackageDeployFlagFlavor)
 	cmd.Flags().StringVar(&o.shasum, "shasum", v.GetString(VPkgDeployShasum), lang.CmdPackageDeployFlagShasum)
 	cmd.Flags().StringVarP(&o.namespaceOverride, "namespace", "n", v.GetString(VPkgDeployNamespace), lang.CmdPackageDeployFlagNamespace)
 	cmd.Flags().BoolVar(&o.skipSignatureValidation, "skip-signature-validation", false, lang.CmdPackageFlagSkipSignatureValidation)
@@ -319,6 +412,19 @@ func (o *packageDeployOptions) preRun(cmd *cobra.Command, _ []string) {
 
 func (o *packageDeployOptions) run(cmd *cobra.Command, args []string) (err error) {
 	ctx := cmd.Context()
+	if o.otelEndpoint != "" {
+		tp, tpErr := tracing.NewTracerProvider(ctx, o.otelEndpoint, RunID)
+		if tpErr != nil {
+			return tpErr
+		}
+		defer func() {
+			// Flush the remaining spans even when the deploy was cancelled
+			shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), otelShutdownTimeout)
+			defer cancel()
+			err = errors.Join(err, tp.Shutdown(shutdownCtx))
+		}()
+		ctx = tracing.WithContext(ctx, tp)
+	}
 	packageSource, err := choosePackage(ctx, args)
 	if err != nil {
 		return err
@@ -345,14 +451,16 @@ func (o *packageDeployOptions) run(cmd *cobra.Command, args []string) (err error
 		return err
 	}
 
+	// The flavor is filtered first so that the components of other flavors are never offered or pulled
+	strategies := []filters.ComponentFilterStrategy{filters.ByFlavor(o.flavor)}
 	// If deploy is confirmed, then only pull the necessary layers as we won't need to prompt for optional components
-	filter := filters.Empty()
 	if o.confirm {
-		filter = filters.Combine(
+		strategies = append(strategies,
 			filters.ByLocalOS(runtime.GOOS),
 			filters.ForDeploy(o.optionalComponents, false),
 		)
 	}
+	filter := filters.Combine(strategies...)
 
 	loadOpt := packager.LoadOptions{
 		Shasum:               o.shasum,
@@ -379,12 +487,18 @@ func (o *packageDeployOptions) run(cmd *cobra.Command, args []string) (err error
 		Connected:              o.connected,
 		ForceConflicts:         o.forceConflicts,
 		Timeout:                o.timeout,
+		DeployTimeout:          o.deployTimeout,
 		Retries:                o.retries,
 		OCIConcurrency:         o.ociConcurrency,
 		SetVariables:           o.setVariables,
 		NamespaceOverride:      o.namespaceOverride,
 		RemoteOptions:          defaultRemoteOptions(),
 		IsInteractive:          !o.confirm,
+		Step:                   o.step,
+		KeepGoing:              o.keepGoing,
+		ValidateSchema:         o.validateSchema,
+		ActionTags:             o.actionTags,
+		RunUntaggedActions:     o.runUntaggedActions,
 		SkipVersionCheck:       o.skipVersionCheck,
 	}
 
@@ -392,6 +506,7 @@ func (o *packageDeployOptions) run(cmd *cobra.Command, args []string) (err error
 	if err != nil {
 		return err
 	}
+	logDeploySummary(ctx, pkgLayout.Pkg.Metadata.Name, deployedComponents)
 
 	if pkgLayout.Pkg.IsInitConfig() {
 		return nil
@@ -404,10 +519,22 @@ func (o *packageDeployOptions) run(cmd *cobra.Command, args []string) (err error
 			}
 		}
 	}
-	printConnectStringTable(connectStrings)
+	printConnectStringTable(OutputWriter, connectStrings, false)
 	return nil
 }
 
+// logDeploySummary logs the summary of a deploy. The logger carries the run ID so the summary can be correlated with the
+// rest of the deploy.
+func logDeploySummary(ctx context.Context, name string, deployedComponents []state.DeployedComponent) {
+	l := logger.From(ctx)
+	l.Info("package deployed", "name", name, "components", len(deployedComponents))
+	for _, comp := range deployedComponents {
+		if comp.Attempts > 1 {
+			l.Info("component deployed after retrying", "component", comp.Name, "attempts", comp.Attempts)
+		}
+	}
+}
+
 func deploy(ctx context.Context, pkgLayout *layout.PackageLayout, opts packager.DeployOptions, setVariables map[string]string, optionalComponents string) ([]state.DeployedComponent, error) {
 	// Intentionally duplicate the deploy override logic here to allow us to render the updated package in confirm below
 	if opts.NamespaceOverride != "" {
@@ -422,10 +549,18 @@ func deploy(ctx context.Context, pkgLayout *layout.PackageLayout, opts packager.
 
 	// In the interactive case we wait until after the component prompt to filter
 	if opts.IsInteractive {
-		filter := filters.Combine(
-			filters.ByLocalOS(runtime.GOOS),
-			filters.ForDeploy(optionalComponents, true),
-		)
+		pkgLayout.Pkg.Components, err = filters.ByLocalOS(runtime.GOOS).Apply(pkgLayout.Pkg)
+		if err != nil {
+			return nil, err
+		}
+		filter := filters.ForDeploy(optionalComponents, true)
+		if optionalComponents == "" && canSelectComponents(pkgLayout.Pkg.Components) {
+			chosen, err := interactive.SelectComponents(pkgLayout.Pkg.Components)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", filters.ErrSelectionCanceled, err)
+			}
+			filter = filters.ForSelectedComponents(chosen)
+		}
 		pkgLayout.Pkg.Components, err = filter.Apply(pkgLayout.Pkg)
 		if err != nil {
 			return nil, err
@@ -440,6 +575,24 @@ func deploy(ctx context.Context, pkgLayout *layout.PackageLayout, opts packager.
 	return result.DeployedComponents, nil
 }
 
+// canSelectComponents reports whether the optional components can be picked from a single multi-select. Packages with
+// deprecated groups keep the prompt per component, as do sessions without a terminal to render the list.
+func canSelectComponents(components []v1alpha1.ZarfComponent) bool {
+	if !interactive.IsTerminal() || !interactive.IsOutputTerminal() {
+		return false
+	}
+	optional := false
+	for _, component := range components {
+		if component.DeprecatedGroup != "" {
+			return false
+		}
+		if !component.IsRequired() {
+			optional = true
+		}
+	}
+	return optional
+}
+
 func confirmDeploy(ctx context.Context, pkgLayout *layout.PackageLayout, setVariables map[string]string, isInteractive bool) (err error) {
 	l := logger.From(ctx)
 
@@ -519,6 +672,7 @@ type packageMirrorResourcesOptions struct {
 	registryInfo            state.RegistryInfo
 	ociConcurrency          int
 	publicKeyPath           string
+	force                   bool
 }
 
 func newPackageMirrorResourcesCommand(v *viper.Viper) *cobra.Command {
@@ -547,6 +701,7 @@ func newPackageMirrorResourcesCommand(v *viper.Viper) *cobra.Command {
 
 	cmd.Flags().StringVar(&o.shasum, "shasum", "", lang.CmdPackagePullFlagShasum)
 	cmd.Flags().BoolVar(&o.noImgChecksum, "no-img-checksum", false, lang.CmdPackageMirrorFlagNoChecksum)
+	cmd.Flags().BoolVar(&o.force, "force", false, lang.CmdPackageMirrorFlagForce)
 
 	cmd.Flags().BoolVar(&o.verify, "verify", v.GetBool(VPkgVerify), lang.CmdPackageFlagVerify)
 	cmd.Flags().BoolVar(&o.skipSignatureValidation, "skip-signature-validation", false, lang.CmdPackageFlagSkipSignatureValidation)
@@ -666,6 +821,7 @@ func (o *packageMirrorResourcesOptions) run(cmd *cobra.Command, args []string) (
 			NoImageChecksum: o.noImgChecksum,
 			Retries:         o.retries,
 			OCIConcurrency:  o.ociConcurrency,
+			Force:           o.force,
 			RemoteOptions:   defaultRemoteOptions(),
 		}
 		err = packager.PushImagesToRegistry(ctx, pkgLayout, o.registryInfo, mirrorOpt)
@@ -724,6 +880,7 @@ func newPackageInspectCommand(v *viper.Viper) *cobra.Command {
 	cmd.AddCommand(newPackageInspectImagesCommand(v))
 	cmd.AddCommand(newPackageInspectManifestsCommand(v))
 	cmd.AddCommand(newPackageInspectDefinitionCommand(v))
+	cmd.AddCommand(newPackageInspectComponentsCommand(v))
 	cmd.AddCommand(newPackageInspectValuesFilesCommand(v))
 	cmd.AddCommand(newPackageInspectDocumentationCommand(v))
 	return cmd
@@ -1091,11 +1248,16 @@ type packageInspectImagesOptions struct {
 	skipSignatureValidation bool
 	ociConcurrency          int
 	publicKeyPath           string
+	rewritten               bool
+	registryURL             string
+	outputWriter            io.Writer
 }
 
 func newPackageInspectImagesOptions() *packageInspectImagesOptions {
 	return &packageInspectImagesOptions{
-		verify: false,
+		verify:       false,
+		registryURL:  defaultRegistry,
+		outputWriter: OutputWriter,
 	}
 }
 
@@ -1114,6 +1276,8 @@ func newPackageInspectImagesCommand(v *viper.Viper) *cobra.Command {
 	cmd.Flags().StringVarP(&o.namespaceOverride, "namespace", "n", o.namespaceOverride, lang.CmdPackageInspectFlagNamespace)
 	cmd.Flags().BoolVar(&o.skipSignatureValidation, "skip-signature-validation", o.skipSignatureValidation, lang.CmdPackageFlagSkipSignatureValidation)
 	cmd.Flags().BoolVar(&o.verify, "verify", v.GetBool(VPkgVerify), lang.CmdPackageFlagVerify)
+	cmd.Flags().BoolVar(&o.rewritten, "rewritten", o.rewritten, lang.CmdPackageInspectFlagRewritten)
+	cmd.Flags().StringVar(&o.registryURL, "registry-url", o.registryURL, lang.CmdPackageInspectFlagRegistry)
 	errSig := cmd.Flags().MarkDeprecated("skip-signature-validation", "Signature verification now occurs on every execution, but is not enforced by default. Use --verify to enforce validation. This flag will be removed in Zarf v1.0.0.")
 	if errSig != nil {
 		logger.Default().Debug("unable to mark skip-signature-validation", "error", errSig)
@@ -1171,6 +1335,25 @@ func (o *packageInspectImagesOptions) run(cmd *cobra.Command, args []string) err
 		return fmt.Errorf("no images found in package")
 	}
 
+	if o.rewritten {
+		registryURL := o.registryURL
+		if !cmd.Flags().Changed("registry-url") && cluster != nil {
+			if s, err := cluster.LoadState(ctx); err == nil {
+				registryURL = s.RegistryInfo.Address
+			}
+		}
+		mappings, err := packager.InspectImageMappings(pkg, registryURL)
+		if err != nil {
+			return err
+		}
+		output, err := json.MarshalIndent(mappings, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(o.outputWriter, string(output))
+		return nil
+	}
+
 	for _, image := range images {
 		fmt.Println("-", image)
 	}
@@ -1265,11 +1448,15 @@ type packageInspectDefinitionOptions struct {
 	skipSignatureValidation bool
 	ociConcurrency          int
 	publicKeyPath           string
+	outputFormat            outputFormat
+	outputWriter            io.Writer
 }
 
 func newPackageInspectDefinitionOptions() *packageInspectDefinitionOptions {
 	return &packageInspectDefinitionOptions{
-		verify: false,
+		verify:       false,
+		outputFormat: outputYAML,
+		outputWriter: OutputWriter,
 	}
 }
 
@@ -1288,6 +1475,7 @@ func newPackageInspectDefinitionCommand(v *viper.Viper) *cobra.Command {
 	cmd.Flags().StringVarP(&o.namespaceOverride, "namespace", "n", o.namespaceOverride, lang.CmdPackageInspectFlagNamespace)
 	cmd.Flags().BoolVar(&o.skipSignatureValidation, "skip-signature-validation", o.skipSignatureValidation, lang.CmdPackageFlagSkipSignatureValidation)
 	cmd.Flags().BoolVar(&o.verify, "verify", v.GetBool(VPkgVerify), lang.CmdPackageFlagVerify)
+	cmd.Flags().VarP(&o.outputFormat, "output-format", "o", "Prints the output in the specified format. Valid options: yaml, json")
 	errSig := cmd.Flags().MarkDeprecated("skip-signature-validation", "Signature verification now occurs on every execution, but is not enforced by default. Use --verify to enforce validation. This flag will be removed in Zarf v1.0.0.")
 	if errSig != nil {
 		logger.Default().Debug("unable to mark skip-signature-validation", "error", errSig)
@@ -1336,10 +1524,118 @@ func (o *packageInspectDefinitionOptions) run(cmd *cobra.Command, args []string)
 		return fmt.Errorf("unable to load the package: %w", err)
 	}
 
-	err = utils.ColorPrintYAML(pkg, nil, false)
+	switch o.outputFormat {
+	case outputJSON:
+		output, err := json.MarshalIndent(pkg, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(o.outputWriter, string(output))
+	case outputYAML:
+		err = utils.ColorPrintYAML(pkg, nil, false)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported output format: %s", o.outputFormat)
+	}
+	return nil
+}
+
+type packageInspectComponentsOptions struct {
+	namespaceOverride string
+	components        string
+	verify            bool
+	ociConcurrency    int
+	publicKeyPath     string
+	outputFormat      outputFormat
+	outputWriter      io.Writer
+}
+
+func newPackageInspectComponentsOptions() *packageInspectComponentsOptions {
+	return &packageInspectComponentsOptions{
+		outputFormat: outputTable,
+		outputWriter: OutputWriter,
+	}
+}
+
+func newPackageInspectComponentsCommand(v *viper.Viper) *cobra.Command {
+	o := newPackageInspectComponentsOptions()
+	cmd := &cobra.Command{
+		Use:   "components [ PACKAGE_SOURCE ]",
+		Short: lang.CmdPackageInspectComponentsShort,
+		Long:  lang.CmdPackageInspectComponentsLong,
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  o.run,
+	}
+
+	cmd.Flags().IntVar(&o.ociConcurrency, "oci-concurrency", v.GetInt(VPkgOCIConcurrency), lang.CmdPackageFlagConcurrency)
+	cmd.Flags().StringVarP(&o.publicKeyPath, "key", "k", v.GetString(VPkgPublicKey), lang.CmdPackageFlagFlagPublicKey)
+	cmd.Flags().StringVarP(&o.namespaceOverride, "namespace", "n", o.namespaceOverride, lang.CmdPackageInspectFlagNamespace)
+	cmd.Flags().BoolVar(&o.verify, "verify", v.GetBool(VPkgVerify), lang.CmdPackageFlagVerify)
+	cmd.Flags().StringVar(&o.components, "components", "", lang.CmdPackageInspectComponentsFlagComponents)
+	cmd.Flags().VarP(&o.outputFormat, "output-format", "o", "Prints the output in the specified format. Valid options: table, json, yaml")
+	return cmd
+}
+
+func (o *packageInspectComponentsOptions) run(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	src, err := choosePackage(ctx, args)
+	if err != nil {
+		return err
+	}
+
+	cachePath, err := getCachePath(ctx)
+	if err != nil {
+		return err
+	}
+
+	cluster, _ := cluster.New(ctx) //nolint: errcheck // package source may or may not be a cluster
+	loadOpts := packager.LoadOptions{
+		VerificationStrategy: getVerificationStrategy(o.verify),
+		Architecture:         config.GetArch(),
+		Filter:               filters.BySelectState(o.components),
+		VerifyBlobOptions:    verifyBlobOptionsFromKeyPath(o.publicKeyPath),
+		OCIConcurrency:       o.ociConcurrency,
+		RemoteOptions:        defaultRemoteOptions(),
+		CachePath:            cachePath,
+	}
+	pkg, err := packager.GetPackageFromSourceOrCluster(ctx, cluster, src, o.namespaceOverride, loadOpts)
+	if err != nil {
+		return fmt.Errorf("unable to load the package: %w", err)
+	}
+	requirements, err := packager.InspectClusterRequirements(pkg)
 	if err != nil {
 		return err
 	}
+
+	switch o.outputFormat {
+	case outputJSON:
+		output, err := json.MarshalIndent(requirements, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(o.outputWriter, string(output))
+	case outputYAML:
+		output, err := goyaml.Marshal(requirements)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(o.outputWriter, string(output))
+	case outputTable:
+		header := []string{"Component", "Required", "Requires Cluster"}
+		var componentData [][]string
+		for _, component := range requirements.Components {
+			componentData = append(componentData, []string{
+				component.Name, strconv.FormatBool(component.Required), strconv.FormatBool(component.RequiresCluster),
+			})
+		}
+		message.TableWithWriter(o.outputWriter, header, componentData)
+		fmt.Fprintf(o.outputWriter, "\nPackage requires a cluster: %t\n", requirements.RequiresCluster)
+	default:
+		return fmt.Errorf("unsupported output format: %s", o.outputFormat)
+	}
 	return nil
 }
 
@@ -1546,6 +1842,7 @@ func (o *packageRemoveOptions) run(cmd *cobra.Command, args []string) error {
 		Timeout:           config.ZarfDefaultTimeout,
 		NamespaceOverride: o.namespaceOverride,
 		SkipVersionCheck:  o.skipVersionCheck,
+		IsInteractive:     !o.confirm,
 		Values:            vals,
 	}
 	logger.From(ctx).Info("loaded package for removal", "name", pkg.Metadata.Name)
@@ -2001,6 +2298,7 @@ func (o *packageSignOptions) run(cmd *cobra.Command, args []string) error {
 type packageVerifyOptions struct {
 	publicKeyPath  string
 	ociConcurrency int
+	checksumsOnly  bool
 }
 
 func newPackageVerifyCommand(v *viper.Viper) *cobra.Command {
@@ -2018,6 +2316,8 @@ func newPackageVerifyCommand(v *viper.Viper) *cobra.Command {
 
 	cmd.Flags().StringVarP(&o.publicKeyPath, "key", "k", v.GetString(VPkgPublicKey), lang.CmdPackageVerifyFlagKey)
 	cmd.Flags().IntVar(&o.ociConcurrency, "oci-concurrency", v.GetInt(VPkgOCIConcurrency), lang.CmdPackageFlagConcurrency)
+	cmd.Flags().BoolVar(&o.checksumsOnly, "checksums-only", false, lang.CmdPackageVerifyFlagChecksumsOnly)
+	cmd.MarkFlagsMutuallyExclusive("key", "checksums-only")
 
 	return cmd
 }
@@ -2029,31 +2329,12 @@ func (o *packageVerifyOptions) run(cmd *cobra.Command, args []string) error {
 
 	l.Info("verifying package", "source", packageSource)
 
-	cachePath, err := getCachePath(ctx)
-	if err != nil {
-		return err
-	}
-
-	// Load the package with verification enabled
-	// The verify command always uses strict verification (VerifyAlways)
-	// This will error if: signed package without key, or unsigned package with key
-	loadOpts := packager.LoadOptions{
-		VerifyBlobOptions:    verifyBlobOptionsFromKeyPath(o.publicKeyPath),
-		VerificationStrategy: layout.VerifyAlways, // Always enforce strict verification
-		Filter:               filters.Empty(),
-		Architecture:         config.GetArch(),
-		OCIConcurrency:       o.ociConcurrency,
-		RemoteOptions:        defaultRemoteOptions(),
-		CachePath:            cachePath,
-		LayerTypes:           []zoci.LayerType{zoci.MetadataLayers},
-	}
-
-	pkgLayout, err := packager.LoadPackage(ctx, packageSource, loadOpts)
+	pkgLayout, cleanup, err := o.load(ctx, packageSource)
 	if err != nil {
 		return fmt.Errorf("package verification failed: %w", err)
 	}
 	defer func() {
-		if cleanupErr := pkgLayout.Cleanup(); cleanupErr != nil {
+		if cleanupErr := cleanup(); cleanupErr != nil {
 			l.Warn("failed to cleanup package", "error", cleanupErr)
 		}
 	}()
@@ -2061,6 +2342,11 @@ func (o *packageVerifyOptions) run(cmd *cobra.Command, args []string) error {
 	// If we got here, all verification passed
 	l.Info("checksum verification", "status", "PASSED")
 
+	if o.checksumsOnly {
+		l.Info("verification complete", "status", "SUCCESS")
+		return nil
+	}
+
 	// Log signature verification status
 	if pkgLayout.IsSigned() {
 		// If signed and we got here, signature verification passed
@@ -2076,6 +2362,47 @@ func (o *packageVerifyOptions) run(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// load loads the package with strict verification, which errors if a signed package is verified without a key or an
+// unsigned package is verified with a key. The checksums are always verified, the signature is skipped with
+// --checksums-only. An extracted package directory is verified in place and is not removed by the returned cleanup.
+func (o *packageVerifyOptions) load(ctx context.Context, packageSource string) (*layout.PackageLayout, func() error, error) {
+	verifyBlobOpts := verifyBlobOptionsFromKeyPath(o.publicKeyPath)
+	strategy := layout.VerifyAlways
+	if o.checksumsOnly {
+		strategy = layout.VerifyNever
+	}
+	if fi, err := os.Stat(packageSource); err == nil && fi.IsDir() {
+		pkgLayout, err := layout.LoadFromDir(ctx, packageSource, layout.PackageLayoutOptions{
+			VerifyBlobOptions:    verifyBlobOpts,
+			VerificationStrategy: strategy,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		return pkgLayout, func() error { return nil }, nil
+	}
+
+	cachePath, err := getCachePath(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	loadOpts := packager.LoadOptions{
+		VerifyBlobOptions:    verifyBlobOpts,
+		VerificationStrategy: strategy,
+		Filter:               filters.Empty(),
+		Architecture:         config.GetArch(),
+		OCIConcurrency:       o.ociConcurrency,
+		RemoteOptions:        defaultRemoteOptions(),
+		CachePath:            cachePath,
+		LayerTypes:           []zoci.LayerType{zoci.MetadataLayers},
+	}
+	pkgLayout, err := packager.LoadPackage(ctx, packageSource, loadOpts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pkgLayout, pkgLayout.Cleanup, nil
+}
+
 func choosePackage(ctx context.Context, args []string) (string, error) {
 	if len(args) > 0 {
 		return args[0], nil
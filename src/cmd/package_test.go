@@ -25,6 +25,7 @@ import (
 	"github.com/zarf-dev/zarf/src/pkg/images"
 	"github.com/zarf-dev/zarf/src/pkg/state"
 	"github.com/zarf-dev/zarf/src/pkg/utils"
+	"github.com/zarf-dev/zarf/src/types"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
@@ -530,6 +531,56 @@ func TestParseRegistryOverrides(t *testing.T) {
 	}
 }
 
+func TestParseChartRepoTLS(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		certFiles map[string]string
+		keyFiles  map[string]string
+		caFiles   map[string]string
+		expected  map[string]types.ClientTLSOptions
+		errSubstr string
+	}{
+		{
+			name:     "no certificates",
+			expected: map[string]types.ClientTLSOptions{},
+		},
+		{
+			name:      "certificates per host",
+			certFiles: map[string]string{"charts.example.com": "client.pem"},
+			keyFiles:  map[string]string{"charts.example.com": "client-key.pem"},
+			caFiles:   map[string]string{"charts.example.com": "ca.pem", "helm.example.com:8443": "other-ca.pem"},
+			expected: map[string]types.ClientTLSOptions{
+				"charts.example.com":    {CertFile: "client.pem", KeyFile: "client-key.pem", CAFile: "ca.pem"},
+				"helm.example.com:8443": {CAFile: "other-ca.pem"},
+			},
+		},
+		{
+			name:      "certificate without a key",
+			certFiles: map[string]string{"charts.example.com": "client.pem"},
+			keyFiles:  map[string]string{"helm.example.com": "client-key.pem"},
+			errSubstr: "chart repository charts.example.com has a client certificate without a key",
+		},
+		{
+			name:      "key without a certificate",
+			keyFiles:  map[string]string{"charts.example.com": "client-key.pem"},
+			errSubstr: "chart repository charts.example.com has a client key without a certificate",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result, err := parseChartRepoTLS(tt.certFiles, tt.keyFiles, tt.caFiles)
+			if tt.errSubstr != "" {
+				require.ErrorContains(t, err, tt.errSubstr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, result)
+		})
+	}
+}
+
 func TestPackageInspectDocumentation(t *testing.T) {
 	t.Parallel()
 
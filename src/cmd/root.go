@@ -6,7 +6,9 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"slices"
@@ -33,8 +35,14 @@ var (
 	LogLevelCLI string
 	// LogFormat holds the log format as input from a command
 	LogFormat string
+	// LogFile holds the path of the file logs are additionally written to as input from a command
+	LogFile string
+	// LogFileMaxSizeMB holds the size the log file is rotated at as input from a command
+	LogFileMaxSizeMB int
 	// IsColorDisabled corresponds to the --no-color flag. It disables color codes in terminal output
 	IsColorDisabled bool
+	// RunID correlates every log record of a command, it is generated at command start unless set with --run-id
+	RunID string
 	// OutputWriter provides a default writer to Stdout for user-facing command output
 	OutputWriter = os.Stdout
 )
@@ -70,6 +78,9 @@ func (o *outputFormat) Type() string {
 
 var rootCmd = NewZarfCommand()
 
+// logFile is the file opened for --log-file, Execute closes it once the command is done.
+var logFile io.Closer
+
 func preRun(cmd *cobra.Command, _ []string) error {
 	// This ensures the field manager is set to Zarf during any Helm SDK actions
 	kube.ManagedFieldsManager = cluster.FieldManagerName
@@ -91,18 +102,23 @@ func preRun(cmd *cobra.Command, _ []string) error {
 	}
 
 	// Configure logger and add it to cmd context. We flip NoColor because setLogger wants "isColor"
-	l, err := setupLogger(LogLevelCLI, LogFormat, !IsColorDisabled)
+	RunID, err = resolveRunID(RunID)
+	if err != nil {
+		return err
+	}
+	l, f, err := setupLogger(LogLevelCLI, LogFormat, !IsColorDisabled && logger.ColorSupported(logger.DestinationDefault), RunID, LogFile, LogFileMaxSizeMB)
 	if err != nil {
 		return err
 	}
+	logFile = f
 	ctx := logger.WithContext(cmd.Context(), l)
 	cmd.SetContext(ctx)
 
 	// Print enabled features once we have a logger available
 	l.Debug("User-configured features:", "features", flattenUserFeatures())
 
-	// if --no-color is set, disable PTerm color in message prints
-	if IsColorDisabled {
+	// if --no-color is set, NO_COLOR is set, or stdout is not a terminal, disable PTerm color in message prints
+	if IsColorDisabled || !logger.ColorSupported(os.Stdout) {
 		pterm.DisableColor()
 	}
 
@@ -225,6 +241,9 @@ func setupRootFlags(rootCmd *cobra.Command) {
 	// Logs
 	rootCmd.PersistentFlags().StringVarP(&LogLevelCLI, "log-level", "l", vpr.GetString(VLogLevel), lang.RootCmdFlagLogLevel)
 	rootCmd.PersistentFlags().StringVar(&LogFormat, "log-format", vpr.GetString(VLogFormat), "Select a logging format. Defaults to 'console'. Valid options are: 'console', 'json', 'dev'.")
+	rootCmd.PersistentFlags().StringVar(&LogFile, "log-file", vpr.GetString(VLogFile), lang.RootCmdFlagLogFile)
+	rootCmd.PersistentFlags().IntVar(&LogFileMaxSizeMB, "log-file-max-size", vpr.GetInt(VLogFileMaxSize), lang.RootCmdFlagLogFileMaxSize)
+	rootCmd.PersistentFlags().StringVar(&RunID, "run-id", "", lang.RootCmdFlagRunID)
 	rootCmd.PersistentFlags().BoolVar(&IsColorDisabled, "no-color", vpr.GetBool(VNoColor), "Disable terminal color codes in logging and stdout prints.")
 	rootCmd.PersistentFlags().BoolVar(&showNoProgressDeprecation, "no-progress", v.GetBool("no_progress"), "Disable fancy UI progress bars, spinners, logos, etc")
 	_ = rootCmd.PersistentFlags().MarkDeprecated("no-progress", "Progress bars and spinners were removed with --log-format=legacy, this flag will be removed in a future version of Zarf.")
@@ -240,7 +259,12 @@ func setupRootFlags(rootCmd *cobra.Command) {
 }
 
 // Execute is the entrypoint for the CLI.
-func Execute(ctx context.Context) error {
+func Execute(ctx context.Context) (err error) {
+	defer func() {
+		if logFile != nil {
+			err = errors.Join(err, logFile.Close())
+		}
+	}()
 	cmd, err := rootCmd.ExecuteContextC(ctx)
 	if err == nil {
 		return nil
@@ -259,15 +283,24 @@ func Execute(ctx context.Context) error {
 	return err
 }
 
-// setupLogger handles creating a logger and setting it as the global default.
-func setupLogger(level, format string, isColor bool) (*slog.Logger, error) {
+// resolveRunID returns the run ID given with --run-id, or a new run ID when none was given.
+func resolveRunID(runID string) (string, error) {
+	if runID != "" {
+		return runID, nil
+	}
+	return logger.NewRunID()
+}
+
+// setupLogger handles creating a logger and setting it as the global default. When file is set the records are also
+// written to it, the returned closer closes the file and is nil otherwise.
+func setupLogger(level, format string, isColor bool, runID, file string, fileMaxSizeMB int) (*slog.Logger, io.Closer, error) {
 	// If we didn't get a level from config, fallback to "info"
 	if level == "" {
 		level = "info"
 	}
 	sLevel, err := logger.ParseLevel(level)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	cfg := logger.Config{
 		Level:       sLevel,
@@ -277,9 +310,21 @@ func setupLogger(level, format string, isColor bool) (*slog.Logger, error) {
 	}
 	l, err := logger.New(cfg)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	var closer io.Closer
+	if file != "" {
+		fileHandler, f, err := logger.NewFileHandler(file, fileMaxSizeMB, sLevel)
+		if err != nil {
+			return nil, nil, err
+		}
+		l = slog.New(logger.NewMultiHandler(l.Handler(), fileHandler))
+		closer = f
+	}
+	if runID != "" {
+		l = l.With(logger.RunIDKey, runID)
 	}
 	logger.SetDefault(l)
-	l.Debug("logger successfully initialized", "cfg", cfg)
-	return l, nil
+	l.Debug("logger successfully initialized", "cfg", cfg, "file", file)
+	return l, closer, nil
 }
@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/pkg/logger"
+	"github.com/zarf-dev/zarf/src/pkg/state"
+)
+
+func TestResolveRunID(t *testing.T) {
+	t.Parallel()
+
+	runID, err := resolveRunID("ci-matrix-42")
+	require.NoError(t, err)
+	require.Equal(t, "ci-matrix-42", runID)
+
+	generated, err := resolveRunID("")
+	require.NoError(t, err)
+	require.Len(t, generated, 16)
+}
+
+// setupLogger replaces the default logger, so this test does not run in parallel.
+func TestSetupLoggerRunID(t *testing.T) {
+	defaultLogger := logger.Default()
+	t.Cleanup(func() {
+		logger.SetDefault(defaultLogger)
+	})
+
+	path := filepath.Join(t.TempDir(), "zarf.log")
+	l, f, err := setupLogger("info", string(logger.FormatNone), false, "ci-matrix-42", path, 0)
+	require.NoError(t, err)
+	require.Same(t, l, logger.Default())
+
+	ctx := logger.WithContext(context.Background(), l)
+	logger.From(ctx).With("component", "podinfo").Info("deploying component")
+	logDeploySummary(ctx, "podinfo", []state.DeployedComponent{{Name: "podinfo", Attempts: 2}})
+	require.NoError(t, f.Close())
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	messages := []string{}
+	for _, line := range strings.Split(strings.TrimSpace(string(b)), "\n") {
+		var record map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		require.Equal(t, "ci-matrix-42", record[logger.RunIDKey])
+		messages = append(messages, record["msg"].(string))
+	}
+	require.Equal(t, []string{"deploying component", "package deployed", "component deployed after retrying"}, messages)
+}
@@ -12,6 +12,8 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/zarf-dev/zarf/src/internal/agent"
+	"github.com/zarf-dev/zarf/src/pkg/images"
 	"github.com/zarf-dev/zarf/src/pkg/logger"
 	"github.com/zarf-dev/zarf/src/pkg/zoci"
 
@@ -32,9 +34,11 @@ const (
 
 	// Root config, Logging
 
-	VLogLevel  = "log_level"
-	VLogFormat = "log_format"
-	VNoColor   = "no_color"
+	VLogLevel       = "log_level"
+	VLogFormat      = "log_format"
+	VLogFile        = "log_file"
+	VLogFileMaxSize = "log_file_max_size"
+	VNoColor        = "no_color"
 
 	// Root config, Features
 
@@ -75,6 +79,10 @@ const (
 	VInitAgentTLSCert = "init.agent.tls_cert"
 	VInitAgentTLSKey  = "init.agent.tls_key"
 
+	VInitAgentImageFields      = "init.agent.image_fields"
+	VInitAgentImageRewrites    = "init.agent.image_rewrites"
+	VInitAgentImagePassthrough = "init.agent.image_passthrough"
+
 	// Package config keys
 
 	VPkgOCIConcurrency = "package.oci_concurrency"
@@ -93,19 +101,33 @@ const (
 	VPkgCreateSigningKeyPassword   = "package.create.signing_key_password"
 	VPkgCreateDifferential         = "package.create.differential"
 	VPkgCreateRegistryOverride     = "package.create.registry_override"
+	VPkgCreateAllowedRegistries    = "package.create.allowed_registries"
+	VPkgCreateDeniedRegistries     = "package.create.denied_registries"
 	VPkgCreateFlavor               = "package.create.flavor"
+	VPkgCreateAllFlavors           = "package.create.all_flavors"
+	VPkgCreateAllArchitectures     = "package.create.all_architectures"
 	VPkgCreateWithBuildMachineInfo = "package.create.with_build_machine_info"
+	VPkgCreatePinDigests           = "package.create.pin_digests"
+	VPkgCreateNoCache              = "package.create.no_cache"
+	VPkgCreateNoImportCache        = "package.create.no_import_cache"
+	VPkgCreateImageConcurrency     = "package.create.image_concurrency"
+	VPkgCreateChartCertFile        = "package.create.chart_cert_file"
+	VPkgCreateChartKeyFile         = "package.create.chart_key_file"
+	VPkgCreateChartCAFile          = "package.create.chart_ca_file"
 
 	// Package deploy config keys
 
-	VPkgDeploySet        = "package.deploy.set"
-	VPkgDeployComponents = "package.deploy.components"
-	VPkgDeployShasum     = "package.deploy.shasum"
-	VPkgDeployTimeout    = "package.deploy.timeout"
-	VPkgDeployNamespace  = "package.deploy.namespace"
-	VPkgRetries          = "package.deploy.retries"
-	VPkgDeployValues     = "package.deploy.values"
-	VPkgDeploySetValues  = "package.deploy.set_values"
+	VPkgDeploySet           = "package.deploy.set"
+	VPkgDeployComponents    = "package.deploy.components"
+	VPkgDeployFlavor        = "package.deploy.flavor"
+	VPkgDeployShasum        = "package.deploy.shasum"
+	VPkgDeployTimeout       = "package.deploy.timeout"
+	VPkgDeployDeployTimeout = "package.deploy.deploy_timeout"
+	VPkgDeployOtelEndpoint  = "package.deploy.otel_endpoint"
+	VPkgDeployNamespace     = "package.deploy.namespace"
+	VPkgRetries             = "package.deploy.retries"
+	VPkgDeployValues        = "package.deploy.values"
+	VPkgDeploySetValues     = "package.deploy.set_values"
 
 	// Package publish config keys
 
@@ -131,12 +153,22 @@ const (
 
 	// Package deploy config keys
 
-	VPkgDeployConnected = "package.deploy.connected"
+	VPkgDeployConnected      = "package.deploy.connected"
+	VPkgDeployKeepGoing      = "package.deploy.keep_going"
+	VPkgDeployValidateSchema = "package.deploy.validate_schema"
 
 	// Dev deploy config keys
 
 	VDevDeployNoYolo    = "dev.deploy.no_yolo"
 	VDevDeployConnected = "dev.deploy.connected"
+
+	// Internal agent config keys
+
+	VInternalAgentAuditURL      = "internal.agent.audit_url"
+	VInternalAgentAuditStrict   = "internal.agent.audit_strict"
+	VInternalAgentDryRun        = "internal.agent.dry_run"
+	VInternalAgentStateTimeout  = "internal.agent.state_timeout"
+	VInternalAgentStateFailOpen = "internal.agent.state_fail_open"
 )
 
 var (
@@ -241,17 +273,24 @@ func setDefaults() {
 	v.SetDefault(VLogLevel, "info")
 	v.SetDefault(VZarfCache, config.ZarfDefaultCachePath)
 	v.SetDefault(VLogFormat, string(logger.FormatConsole))
+	v.SetDefault(VLogFileMaxSize, logger.DefaultFileMaxSizeMB)
 
 	// Package defaults that are non-zero values
 	v.SetDefault(VPkgOCIConcurrency, zoci.DefaultConcurrency)
 	v.SetDefault(VPkgRetries, config.ZarfDefaultRetries)
 
+	// Package create opts that are non-zero values
+	v.SetDefault(VPkgCreateImageConcurrency, images.DefaultImageConcurrency)
+
 	// Deploy opts that are non-zero values
 	v.SetDefault(VPkgDeployTimeout, config.ZarfDefaultTimeout)
 
 	// Package publish opts that are non-zero values
 	v.SetDefault(VPkgPublishRetries, 1)
 
+	// Agent defaults
+	v.SetDefault(VInternalAgentStateTimeout, agent.DefaultStateTimeout)
+
 	// Dev deploy defaults
 	v.SetDefault(VDevDeployConnected, true)
 }
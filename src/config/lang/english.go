@@ -43,6 +43,9 @@ const (
 
 	RootCmdFlagLogLevel              = "Log level when running Zarf. Valid options are: warn, info, debug, trace"
 	RootCmdFlagArch                  = "Architecture for OCI images and Zarf packages"
+	RootCmdFlagLogFile               = "Also write logs as JSON without color codes to this file, in addition to the terminal"
+	RootCmdFlagLogFileMaxSize        = "Size in megabytes the --log-file grows to before it is rotated to <log-file>.1, 0 disables rotation"
+	RootCmdFlagRunID                 = "ID attached to every log record of this command to correlate them. Defaults to a randomly generated ID."
 	RootCmdFlagCachePath             = "Specify the location of the Zarf cache directory"
 	RootCmdFlagTempDir               = "Specify the temporary directory to use for intermediate files"
 	RootCmdFlagPlainHTTP             = "Force the connections over HTTP instead of HTTPS. This flag should only be used if you have a specific reason and accept the reduced security posture."
@@ -59,10 +62,27 @@ const (
 		"Packages can provide service manifests that define their own shortcut connection options. These options will be " +
 		"printed to the terminal when the package finishes deploying.\nIf you don't remember what connection shortcuts your deployed " +
 		"package offers, you can search your cluster for services that have the 'zarf.dev/connect-name' label. The value of that label is " +
-		"the name you will pass into the 'zarf connect' command."
+		"the name you will pass into the 'zarf connect' command.\n\n" +
+		"Multiple targets open a tunnel to each of them at the same time, e.g. 'zarf connect registry git my-app'. Every tunnel " +
+		"listens on an available local port, the local URL of each target is printed and all of the tunnels are closed on " +
+		"interrupt. --local-port, --print-cmd, --probe, --detach and --output can only be used with a single target."
 
 	// zarf connect list
 	CmdConnectListShort = "Lists all available connection shortcuts"
+	CmdConnectListLong  = "Lists all available connection shortcuts.\n" +
+		"With --hosts the shortcuts are printed as /etc/hosts entries that map a <name>.zarf.internal hostname of each " +
+		"shortcut to a local tunnel. Every shortcut is assigned a local port, the entries list the zarf connect command " +
+		"that opens the tunnel on that port. No system files are modified.\n" +
+		"Tunnels started with zarf connect --detach are listed with their local ports."
+
+	CmdConnectListFlagHosts     = "Print /etc/hosts entries for the connection shortcuts"
+	CmdConnectListFlagStartPort = "The local port assigned to the first connection shortcut in the --hosts output, every following shortcut is assigned the next port"
+	CmdConnectListFlagTimeout   = "Timeout for listing the connection shortcuts, the shortcuts found before it is reached are listed with a timed out marker"
+
+	// zarf connect stop
+	CmdConnectStopShort = "Stops a tunnel started with zarf connect --detach"
+	CmdConnectStopLong  = "Stops the named tunnel started with zarf connect --detach and closes its port-forward.\n" +
+		"The name of a detached tunnel is its connect-name, REGISTRY or GIT target, or the resource name when no target is given."
 
 	// zarf connect resource
 	CmdConnectResourceShort = "Connect to a service or pod in the cluster"
@@ -72,19 +92,31 @@ const (
 		"  zarf connect resource --name my-svc --namespace my-namespace --remote-port 8080\n\n" +
 		"  # Connect to a pod on a specified local port:\n" +
 		"  zarf connect resource --name my-pod --type=pod --namespace my-namespace --remote-port 8080 --local-port 9090"
-	CmdConnectResourceFlagName       = "The name of the resource to connect to"
+	CmdConnectResourceFlagName       = "The name of the resource to connect to, or a label selector such as app=frontend to connect to the first ready pod of type pod"
 	CmdConnectResourceFlagNamespace  = "The namespace of the resource"
 	CmdConnectResourceFlagRemotePort = "The remote port of the resource to connect to"
 	CmdConnectResourceFlagType       = "The type of resource (svc or pod)"
 	CmdConnectResourceFlagLocalPort  = "(Optional, autogenerated if not provided) The local port to bind to"
 
-	CmdConnectFlagName       = "Specify the resource name.  E.g. name=unicorns or name=unicorn-pod-7448499f4d-b5bk6. Ignored if connect-name is supplied."
-	CmdConnectFlagAddress    = "Specify the addresses to expose the tunnel on - comma separated.  E.g. --address=127.0.0.1,38.0.101.76."
-	CmdConnectFlagNamespace  = "Specify the namespace.  E.g. namespace=default. Ignored if connect-name is supplied."
-	CmdConnectFlagType       = "Specify the resource type.  E.g. type=svc or type=pod. Ignored if connect-name is supplied."
-	CmdConnectFlagLocalPort  = "(Optional, autogenerated if not provided) Specify the local port to bind to.  E.g. local-port=42000."
-	CmdConnectFlagRemotePort = "Specify the remote port of the resource to bind to.  E.g. remote-port=8080. Ignored if connect-name is supplied."
-	CmdConnectFlagOpen       = "Enable browser auto-open"
+	CmdConnectFlagName          = "Specify the resource name.  E.g. name=unicorns or name=unicorn-pod-7448499f4d-b5bk6, or a label selector such as name=app=frontend for type=pod. Ignored if connect-name is supplied."
+	CmdConnectFlagAddress       = "Specify the addresses to expose the tunnel on - comma separated.  E.g. --address=127.0.0.1,38.0.101.76. Addresses other than loopback, such as 0.0.0.0, make the resource reachable from other hosts."
+	CmdConnectFlagNamespace     = "Specify the namespace, defaults to the Zarf namespace.  E.g. namespace=default. Ignored if connect-name is supplied."
+	CmdConnectFlagType          = "Specify the resource type.  E.g. type=svc or type=pod. Ignored if connect-name is supplied."
+	CmdConnectFlagLocalPort     = "(Optional, autogenerated if not provided) Specify the local port to bind to.  E.g. local-port=42000."
+	CmdConnectFlagRemotePort    = "Specify the remote port of the resource to bind to.  E.g. remote-port=8080. Ignored if connect-name is supplied."
+	CmdConnectFlagOpen          = "Enable browser auto-open"
+	CmdConnectFlagWait          = "Wait for the connect target to exist in the cluster before establishing the tunnel"
+	CmdConnectFlagPrintCmd      = "Print ready to run commands (docker, helm, git) that use the tunnel. Only supported for the REGISTRY and GIT targets"
+	CmdConnectFlagProbe         = "Check once whether the target is reachable through the tunnel, print the result and exit with a non-zero code if it is not, instead of keeping the tunnel open"
+	CmdConnectFlagProbeProtocol = "The protocol of the probe (tcp, http or https). tcp checks that a connection to the target is not closed by the remote end"
+	CmdConnectFlagProbeCode     = "The HTTP status code the probe expects when using http or https (default any 2xx status code)"
+	CmdConnectFlagTransport     = "The port forward transport (auto, websocket or spdy). auto uses WebSockets and falls back to SPDY when the API server does not support them"
+	CmdConnectFlagProtocol      = "The protocol of the remote port (tcp or udp). udp starts a relay pod running the Zarf agent image in the namespace of the resource, which requires an initialized cluster and permission to create and delete pods there"
+	CmdConnectFlagDetach        = "Open the tunnel in a background process and return once it is established, stop it with zarf connect stop"
+	CmdConnectFlagOutput        = "Print the established tunnel to stdout in the given format (json) before waiting for the user to interrupt, all other messages are logged to stderr"
+	CmdConnectFlagLogs          = "Stream the logs of the pod behind the tunnel to stderr while the tunnel is open, the stream stops when the tunnel is closed"
+	CmdConnectFlagSince         = "Only stream logs newer than a relative duration like 5s, 2m, or 3h when using --logs (default all logs)"
+	CmdConnectFlagTail          = "Lines of the most recent logs to stream first when using --logs, -1 streams all lines"
 
 	CmdConnectPreparingTunnel = "Preparing a tunnel to connect to %s"
 	CmdConnectEstablishedCLI  = "Tunnel established at %s, waiting for user to interrupt (ctrl-c to end)"
@@ -164,6 +196,10 @@ $ zarf init --artifact-push-password={PASSWORD} --artifact-push-username={USERNA
 	CmdInitFlagComponents   = "Specify which optional components to install.  E.g. --components=git-server"
 	CmdInitFlagStorageClass = "Specify the storage class to use for the registry and git server.  E.g. --storage-class=standard"
 
+	CmdInitFlagAgentImageField       = "Custom resource field holding an image reference that the Zarf agent rewrites (<group>/<version>/<kind>=<jsonpath>).  E.g. --agent-image-field=monitoring.coreos.com/v1/Prometheus=.spec.image"
+	CmdInitFlagAgentImageRewrite     = "Registry whose images the Zarf agent points at a destination other than the Zarf registry (<registry>=<destination>), the registry may be a glob.  E.g. --agent-image-rewrite=quay.io=mirror.example.com/quay"
+	CmdInitFlagAgentImagePassthrough = "Registries whose images the Zarf agent leaves untouched, each may be a glob.  E.g. --agent-image-passthrough=*.dkr.ecr.us-east-1.amazonaws.com"
+
 	CmdInitFlagGitURL      = "External git server url to use for this Zarf cluster"
 	CmdInitFlagGitPushUser = "Username to access to the git server Zarf is configured to use. User must be able to create repositories via 'git push'"
 	CmdInitFlagGitPushPass = "Password for the push-user to access the git server"
@@ -190,6 +226,11 @@ $ zarf init --artifact-push-password={PASSWORD} --artifact-push-username={USERNA
 	CmdInternalAgentLong  = "NOTE: This command is a hidden command and generally shouldn't be run by a human.\n" +
 		"This command starts up a http webhook that Zarf deployments use to mutate pods to conform " +
 		"with the Zarf container registry and Gitea server URLs."
+	CmdInternalAgentFlagAuditURL      = "URL that a JSON audit event is posted to for every pod, job and cron job mutation decision"
+	CmdInternalAgentFlagAuditStrict   = "Reject pods, jobs and cron jobs whose mutation decision could not be posted to the audit URL instead of only logging the failure"
+	CmdInternalAgentFlagStateTimeout  = "How long to wait for the Zarf state to be created before an object is admitted or rejected, 0 does not wait"
+	CmdInternalAgentFlagStateFailOpen = "Admit objects unchanged when the Zarf state is still missing after --state-timeout instead of rejecting them"
+	CmdInternalAgentFlagDryRun        = "Admit every object unchanged and only report the patches the agent would apply in the audit annotations of the admission response and the logs"
 
 	CmdInternalProxyShort = "[alpha] Runs the zarf agent http proxy"
 	CmdInternalProxyLong  = "[alpha] NOTE: This command is a hidden command and generally shouldn't be run by a human.\n" +
@@ -220,6 +261,13 @@ $ zarf init --artifact-push-password={PASSWORD} --artifact-push-username={USERNA
 
 	CmdInternalCrc32Short = "Generates a decimal CRC32 for the given text"
 
+	CmdInternalReceiveDataInjectionShort           = "Extracts a data injection tar stream from stdin into the given directory"
+	CmdInternalReceiveDataInjectionFlagCompress    = "Decompress the tar stream using gzip"
+	CmdInternalReceiveDataInjectionFlagCompression = "Decompress the tar stream using the given algorithm (gzip, zstd or none), takes precedence over --compress"
+
+	CmdInternalUDPRelayShort    = "Relays the length prefixed datagrams of UDP tunnel connections to the given UDP address"
+	CmdInternalUDPRelayFlagPort = "The TCP port to accept UDP tunnel connections on"
+
 	// zarf package
 	CmdPackageShort                       = "Zarf package commands for creating, deploying, and inspecting packages"
 	CmdPackageFlagConcurrency             = "Number of concurrent layer operations when pulling or pushing images or packages to/from OCI registries."
@@ -268,6 +316,11 @@ $ zarf package mirror-resources <your-package.tar.zst> --repos \
 
 	CmdPackageInspectShort = "Commands for gathering information from a built package"
 
+	CmdPackageInspectComponentsShort = "Lists the components of a package and whether they require a cluster to deploy"
+	CmdPackageInspectComponentsLong  = "Lists the components of a package and whether they require a cluster to deploy. " +
+		"A component requires a cluster when it has images, image archives, charts, manifests, repos, data injections or health checks. " +
+		"The package requires a cluster when any of the selected components does."
+
 	CmdPackageListShort         = "Lists out all of the packages that have been deployed to the cluster (runs offline)"
 	CmdPackageListNoPackageWarn = "Unable to get the packages deployed to the cluster"
 
@@ -285,36 +338,60 @@ $ zarf package mirror-resources <your-package.tar.zst> --repos \
 	CmdPackageCreateFlagDeprecatedKeyPassword = "[Deprecated] Password to the private key file used for signing packages (use --signing-key-pass instead)"
 	CmdPackageCreateFlagDifferential          = "Build a package that only contains the differential changes from local resources and differing remote resources from the specified previously built package"
 	CmdPackageCreateFlagRegistryOverride      = "Specify a mapping of domains to override on package create when pulling images (e.g. --registry-override docker.io=dockerio-reg.enterprise.intranet)"
+	CmdPackageCreateFlagAllowedRegistries     = "Fail if any image in the package or found in its charts and manifests is not from one of these registries (e.g. ghcr.io or ghcr.io/my-org). Images without a registry are from docker.io"
+	CmdPackageCreateFlagDeniedRegistries      = "Fail if any image in the package or found in its charts and manifests is from one of these registries (e.g. docker.io or ghcr.io/my-org). Takes precedence over --allowed-registries"
 	CmdPackageCreateFlagFlavor                = "The flavor of components to include in the resulting package (i.e. have a matching or empty \"only.flavor\" key)"
+	CmdPackageCreateFlagAllFlavors            = "Include the components of every flavor in the resulting package so that the flavor is chosen with --flavor on deploy"
+	CmdPackageCreateFlagAllArchitectures      = "Include the components of every cluster architecture (i.e. any \"only.cluster.architecture\" key) in the resulting package so that deploy selects them by the architectures of the cluster nodes"
 	CmdPackageCreateFlagValuesFiles           = "[alpha] Values files to use for templating and Helm overrides. Multiple files can be passed in as a comma separated list, and the flag can be provided multiple times."
 	CmdPackageCreateFlagWithBuildMachineInfo  = "Include build machine information (hostname and username) in the package metadata"
+	CmdPackageCreateFlagPinDigests            = "Resolve every image referenced only by tag, including the images found in charts and manifests, to its digest and store the pinned reference in the package"
+	CmdPackageCreateFlagNoCache               = "Rebuild every component instead of reusing components cached by previous builds"
+	CmdPackageCreateFlagNoImportCache         = "Fetch every OCI import again instead of reusing the imports cached by previous builds"
+	CmdPackageCreateFlagImageConcurrency      = "Number of images to pull in parallel"
+	CmdPackageCreateFlagChartCertFile         = "Client certificate presented to a Helm chart repository that requires mutual TLS, as HOST=PATH where HOST is the host (and port) of the repository. It is not included in the package"
+	CmdPackageCreateFlagChartKeyFile          = "Private key of the client certificate presented to a Helm chart repository, as HOST=PATH"
+	CmdPackageCreateFlagChartCAFile           = "CA bundle used to verify the certificate of a Helm chart repository, as HOST=PATH"
 	CmdPackageCreateCleanPathErr              = "Invalid characters in Zarf cache path, defaulting to %s"
 
-	CmdPackageDeployFlagConfirm                = "Confirms package deployment without prompting. ONLY use with packages you trust. Skips prompts to review SBOM, configure variables, select optional components and review potential breaking changes."
-	CmdPackageDeployFlagAdoptExistingResources = "Adopts any pre-existing K8s resources into the Helm charts managed by Zarf. ONLY use when you have existing deployments you want Zarf to takeover."
-	CmdPackageDeployFlagConnected              = "Deploy without pushing images/repos; label resources to bypass the Zarf agent"
-	CmdPackageDeployFlagForceConflicts         = "Force Helm to take ownership of conflicting fields during Server-Side Apply operations. Use when external tools (kubectl, HPAs, etc.) have modified resources."
-	CmdPackageDeployFlagSetVariables           = "Specify deployment variables to set on the command line (KEY=value)"
-	CmdPackageDeployFlagSetValues              = "Specify deployment package values to set on the command line (key.path=value)."
-	CmdPackageDeployFlagComponents             = "Comma-separated list of components to deploy.  Adding this flag will skip the prompts for selected components.  Globbing component names with '*' and deselecting 'default' components with a leading '-' are also supported."
-	CmdPackageDeployFlagShasum                 = "Shasum of the package to deploy. Required if deploying a remote https package."
-	CmdPackageDeployFlagTimeout                = "Timeout for health checks and Helm operations such as installs and rollbacks"
-	CmdPackageDeployValidateArchitectureErr    = "this package architecture is %s, but the target cluster only has the %s architecture(s). These architectures must be compatible when \"images\" are present"
-	CmdPackageDeployInvalidCLIVersionWarn      = "CLIVersion is set to '%s' which can cause issues with package creation and deployment. To avoid such issues, please set the value to the valid semantic version for this version of Zarf."
-	CmdPackageDeployFlagNamespace              = "[Alpha] Override the namespace for package deployment. Requires the package to have only one distinct namespace defined."
-	CmdPackageDeployFlagValuesFiles            = CmdPackageCreateFlagValuesFiles
-
-	CmdPackageMirrorFlagComponents = "Comma-separated list of components to mirror.  This list will be respected regardless of a component's 'required' or 'default' status.  Globbing component names with '*' and deselecting components with a leading '-' are also supported."
+	CmdPackageDeployFlagConfirm                      = "Confirms package deployment without prompting. ONLY use with packages you trust. Skips prompts to review SBOM, configure variables, select optional components and review potential breaking changes."
+	CmdPackageDeployFlagAdoptExistingResources       = "Adopts any pre-existing K8s resources into the Helm charts managed by Zarf. ONLY use when you have existing deployments you want Zarf to takeover."
+	CmdPackageDeployFlagConnected                    = "Deploy without pushing images/repos; label resources to bypass the Zarf agent"
+	CmdPackageDeployFlagForceConflicts               = "Force Helm to take ownership of conflicting fields during Server-Side Apply operations. Use when external tools (kubectl, HPAs, etc.) have modified resources."
+	CmdPackageDeployFlagKeepGoing                    = "Continue deploying the remaining components when an optional component fails, skipping the components that depend on it. Exits with an error listing the failures. Required component failures still abort the deployment"
+	CmdPackageDeployFlagValidateSchema               = "Validate the rendered resources of every chart and manifest against the OpenAPI schema of the cluster and report all violations before applying them. Requires cluster connectivity"
+	CmdPackageDeployFlagOnlyActionsTagged            = "Comma-separated list of action tags. Only the onDeploy actions with at least one of these tags run, the others are skipped"
+	CmdPackageDeployFlagRunUntaggedActions           = "Also run the onDeploy actions without tags when --only-actions-tagged is set"
+	CmdPackageDeployFlagStep                         = "Pause after each component is deployed and prompt to continue or abort. Aborting runs the component's onFailure actions. Requires a terminal"
+	CmdPackageDeployFlagSetVariables                 = "Specify deployment variables to set on the command line (KEY=value)"
+	CmdPackageDeployFlagSetValues                    = "Specify deployment package values to set on the command line (key.path=value)."
+	CmdPackageDeployFlagComponents                   = "Comma-separated list of components to deploy.  Adding this flag will skip the prompts for selected components.  Globbing component names with '*', matching them with a regular expression wrapped in slashes such as '/istio-(base|cni)/', and deselecting 'default' components with a leading '-' are also supported."
+	CmdPackageDeployFlagFlavor                       = "Only deploy the components of this flavor along with the components without a flavor (i.e. have a matching or empty \"only.flavor\" key). Errors when no component has the flavor. Required for packages created with --all-flavors that contain several flavors"
+	CmdPackageDeployFlagShasum                       = "Shasum of the package to deploy. Required if deploying a remote https package."
+	CmdPackageDeployFlagTimeout                      = "Timeout for health checks and Helm operations such as installs and rollbacks"
+	CmdPackageDeployFlagDeployTimeout                = "Timeout for the entire deployment, in-flight work is cancelled once it is reached. Disabled when 0"
+	CmdPackageDeployFlagOtelEndpoint                 = "OTLP/HTTP endpoint URL to export OpenTelemetry spans of the deployment to, e.g. http://localhost:4318. Tracing is disabled when empty"
+	CmdPackageDeployValidateArchitectureErr          = "this package architecture is %s, but the target cluster only has the %s architecture(s). These architectures must be compatible when \"images\" are present"
+	CmdPackageDeployValidateComponentArchitectureErr = "the components of this package with \"images\" are only for the %s architecture(s), but the target cluster only has the %s architecture(s)"
+	CmdPackageDeployInvalidCLIVersionWarn            = "CLIVersion is set to '%s' which can cause issues with package creation and deployment. To avoid such issues, please set the value to the valid semantic version for this version of Zarf."
+	CmdPackageDeployFlagNamespace                    = "[Alpha] Override the namespace for package deployment. Requires the package to have only one distinct namespace defined."
+	CmdPackageDeployFlagValuesFiles                  = CmdPackageCreateFlagValuesFiles
+
+	CmdPackageMirrorFlagComponents = "Comma-separated list of components to mirror.  This list will be respected regardless of a component's 'required' or 'default' status.  Globbing component names with '*', matching them with a regular expression wrapped in slashes, and deselecting components with a leading '-' are also supported."
 	CmdPackageMirrorFlagNoChecksum = "Turns off the addition of a checksum to image tags (as would be used by the Zarf Agent) while mirroring images."
+	CmdPackageMirrorFlagForce      = "Push every image even if the registry already has it with the same digest. By default images that are already present are skipped."
 
-	CmdPackageInspectFlagSbomOut    = "Specify an output directory for the SBOMs from the inspected Zarf package"
-	CmdPackageInspectFlagListImages = "List images in the package (prints to stdout)"
-	CmdPackageInspectFlagNamespace  = "[Alpha] Override the namespace for package inspection. Applicable only to packages deployed using the namespace flag."
+	CmdPackageInspectFlagSbomOut              = "Specify an output directory for the SBOMs from the inspected Zarf package"
+	CmdPackageInspectFlagListImages           = "List images in the package (prints to stdout)"
+	CmdPackageInspectFlagNamespace            = "[Alpha] Override the namespace for package inspection. Applicable only to packages deployed using the namespace flag."
+	CmdPackageInspectFlagRewritten            = "Print a JSON mapping of each image to the reference the Zarf Agent rewrites it to in the Zarf registry"
+	CmdPackageInspectFlagRegistry             = "The Zarf registry address used to rewrite images. Defaults to the registry in the cluster's Zarf state when available"
+	CmdPackageInspectComponentsFlagComponents = "Comma-separated list of components to inspect.  Globbing component names with '*', matching them with a regular expression wrapped in slashes, and deselecting components with a leading '-' are also supported."
 
 	CmdPackageRemoveShort           = "Removes a Zarf package that has been deployed already (runs offline)"
 	CmdPackageRemoveLong            = "Removes a Zarf package that has been deployed already (runs offline). Remove reverses the deployment order, the last component is removed first."
 	CmdPackageRemoveFlagConfirm     = "Confirms the removal action"
-	CmdPackageRemoveFlagComponents  = "Comma-separated list of components to remove.  This list will be respected regardless of a component's 'required' or 'default' status.  Globbing component names with '*' and deselecting components with a leading '-' are also supported."
+	CmdPackageRemoveFlagComponents  = "Comma-separated list of components to remove.  This list will be respected regardless of a component's 'required' or 'default' status.  Globbing component names with '*', matching them with a regular expression wrapped in slashes, and deselecting components with a leading '-' are also supported."
 	CmdPackageRemoveFlagNamespace   = "[Alpha] Override the namespace for package removal. Applicable only to packages deployed using the namespace flag."
 	CmdPackageRemoveFlagValuesFiles = "Path to values file(s) for removal actions"
 
@@ -360,15 +437,20 @@ $ zarf package sign zarf-package-demo-amd64-1.0.0.tar.zst --signing-key awskms:/
 	CmdPackageSignFlagKey            = "Public key to verify the existing signature before re-signing (optional)"
 
 	CmdPackageVerifyShort   = "Verify the signature and integrity of a Zarf package"
-	CmdPackageVerifyLong    = "Verify the cryptographic signature (if signed) and checksum integrity of a Zarf package. Returns exit code 0 if valid, non-zero if verification fails."
+	CmdPackageVerifyLong    = "Verify the cryptographic signature (if signed) and checksum integrity of a Zarf package. The package can be an archive or a directory the package was extracted to, every file is checked against the checksums of the package and all missing, mismatched and unexpected files are reported. Returns exit code 0 if valid, non-zero if verification fails."
 	CmdPackageVerifyExample = `
 # Verify a signed package
 $ zarf package verify zarf-package-demo-amd64-1.0.0.tar.zst --key ./public-key.pub
 
 # Verify an unsigned package (checksums only)
 $ zarf package verify zarf-package-demo-amd64-1.0.0.tar.zst
+
+# Verify only the checksums of the files of a package that was extracted to a directory
+$ mkdir demo && tar -xf zarf-package-demo-amd64-1.0.0.tar.zst -C demo
+$ zarf package verify ./demo --checksums-only
 `
-	CmdPackageVerifyFlagKey = "Public key for signature verification"
+	CmdPackageVerifyFlagKey           = "Public key for signature verification"
+	CmdPackageVerifyFlagChecksumsOnly = "Only verify the checksums of the package files and skip signature verification, e.g. for unsigned packages"
 
 	CmdPackagePullShort   = "Pulls a Zarf package from a remote registry and save to the local file system"
 	CmdPackagePullExample = `
@@ -643,6 +725,8 @@ $ zarf tools wait-for svc zarf-docker-registry exists -n zarf           #  wait
 $ zarf tools wait-for svc zarf-docker-registry -n zarf                  #  same as above, except exists is the default condition
 $ zarf tools wait-for crd addons.k3s.cattle.io                          #  wait for crd addons.k3s.cattle.io to exist
 $ zarf tools wait-for sts test-sts '{.status.availableReplicas}'=23     #  wait for statefulset test-sts to have 23 available replicas
+$ zarf tools wait-for ds fluent-bit rolledOut -n logging                #  wait for all pods of daemonset fluent-bit to be updated and ready
+$ zarf tools wait-for pod app=operator deleted -n operators             #  wait for pods with label app=operator in namespace operators to be gone
 
 # Wait for network endpoints:
 $ zarf tools wait-for http localhost:8080 200                           #  wait for a 200 response from http://localhost:8080
@@ -664,10 +748,11 @@ $ zarf tools wait-for resource po cool-pod-name -n cool
 $ zarf tools wait-for resource pod my-pod-name ready -n default                  #  wait for pod my-pod-name in namespace default to have the ready condition
 $ zarf tools wait-for resource pod app=podinfo -n podinfo                        #  wait for pod(s) with label app=podinfo in namespace podinfo to be reconciled
 $ zarf tools wait-for resource deployment zarf-docker-registry exists -n zarf    #  wait for deployment zarf-docker-registry in namespace zarf to exist
-$ zarf tools wait-for resource svc zarf-docker-registry delete -n zarf           #  wait for service zarf-docker-registry in namespace zarf to not exist
+$ zarf tools wait-for resource svc zarf-docker-registry deleted -n zarf          #  wait for service zarf-docker-registry in namespace zarf to not exist
 $ zarf tools wait-for resource pvc -n zarf                                       #  wait for any pvc in namespace zarf to exist
 $ zarf tools wait-for resource crd addons.k3s.cattle.io                          #  wait for crd addons.k3s.cattle.io to exist
 $ zarf tools wait-for resource sts test-sts '{.status.availableReplicas}'=23     #  wait for statefulset test-sts to have 23 available replicas
+$ zarf tools wait-for resource sts postgres rolledOut -n db                      #  wait for all pods of statefulset postgres to be updated and ready
 `
 
 	CmdToolsWaitForNetworkShort   = "Waits for a network endpoint to meet the condition"
@@ -744,6 +829,8 @@ const (
 	AgentErrBadRequest             = "could not read request body: %s"
 	AgentErrCouldNotDeserializeReq = "could not deserialize request: %s"
 	AgentErrParsePod               = "failed to parse pod: %w"
+	AgentErrParseJob               = "failed to parse job: %w"
+	AgentErrParseCronJob           = "failed to parse cron job: %w"
 	AgentErrHostnameMatch          = "failed to complete hostname matching: %w"
 	AgentErrInvalidMethod          = "invalid method only POST requests are allowed"
 	AgentErrInvalidOp              = "invalid operation: %s"
@@ -47,24 +47,27 @@ type ApplicationSource struct {
 }
 
 // NewApplicationMutationHook creates a new instance of the ArgoCD Application mutation hook.
-func NewApplicationMutationHook(ctx context.Context, cluster *cluster.Cluster) operations.Hook {
+func NewApplicationMutationHook(ctx context.Context, cluster *cluster.Cluster, stateLookup StateLookup) operations.Hook {
 	return operations.Hook{
 		Create: func(r *v1.AdmissionRequest) (*operations.Result, error) {
-			return mutateApplication(ctx, r, cluster)
+			return mutateApplication(ctx, r, cluster, stateLookup)
 		},
 		Update: func(r *v1.AdmissionRequest) (*operations.Result, error) {
-			return mutateApplication(ctx, r, cluster)
+			return mutateApplication(ctx, r, cluster, stateLookup)
 		},
 	}
 }
 
 // mutateApplication mutates the git repository url to point to the repository URL defined in the ZarfState.
-func mutateApplication(ctx context.Context, r *v1.AdmissionRequest, cluster *cluster.Cluster) (*operations.Result, error) {
+func mutateApplication(ctx context.Context, r *v1.AdmissionRequest, cluster *cluster.Cluster, stateLookup StateLookup) (*operations.Result, error) {
 	l := logger.From(ctx)
-	s, err := cluster.LoadState(ctx)
+	s, err := stateLookup.lookup(ctx, cluster, r.Kind.Kind, r.Namespace, r.Name)
 	if err != nil {
 		return nil, err
 	}
+	if s == nil {
+		return admitUnchanged(), nil
+	}
 	if !s.GitServer.IsConfigured() {
 		l.Debug("no Zarf git server configured, skipping ArgoCD Application mutation")
 		return &operations.Result{Allowed: true}, nil
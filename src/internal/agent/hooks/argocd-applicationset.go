@@ -46,24 +46,27 @@ type GitGenerator struct {
 }
 
 // NewApplicationSetMutationHook creates a new instance of the ArgoCD ApplicationSet mutation hook.
-func NewApplicationSetMutationHook(ctx context.Context, cluster *cluster.Cluster) operations.Hook {
+func NewApplicationSetMutationHook(ctx context.Context, cluster *cluster.Cluster, stateLookup StateLookup) operations.Hook {
 	return operations.Hook{
 		Create: func(r *v1.AdmissionRequest) (*operations.Result, error) {
-			return mutateApplicationSet(ctx, r, cluster)
+			return mutateApplicationSet(ctx, r, cluster, stateLookup)
 		},
 		Update: func(r *v1.AdmissionRequest) (*operations.Result, error) {
-			return mutateApplicationSet(ctx, r, cluster)
+			return mutateApplicationSet(ctx, r, cluster, stateLookup)
 		},
 	}
 }
 
 // mutateApplication mutates the git repository urls to point to the repository URL defined in the ZarfState.
-func mutateApplicationSet(ctx context.Context, r *v1.AdmissionRequest, cluster *cluster.Cluster) (*operations.Result, error) {
+func mutateApplicationSet(ctx context.Context, r *v1.AdmissionRequest, cluster *cluster.Cluster, stateLookup StateLookup) (*operations.Result, error) {
 	l := logger.From(ctx)
-	s, err := cluster.LoadState(ctx)
+	s, err := stateLookup.lookup(ctx, cluster, r.Kind.Kind, r.Namespace, r.Name)
 	if err != nil {
 		return nil, err
 	}
+	if s == nil {
+		return admitUnchanged(), nil
+	}
 	if !s.GitServer.IsConfigured() {
 		l.Debug("no Zarf git server configured, skipping ArgoCD ApplicationSet mutation")
 		return &operations.Result{Allowed: true}, nil
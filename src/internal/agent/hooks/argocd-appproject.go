@@ -35,24 +35,27 @@ type AppProjectSpec struct {
 }
 
 // NewAppProjectMutationHook creates a new mutation hook for ArgoCD AppProjects.
-func NewAppProjectMutationHook(ctx context.Context, cluster *cluster.Cluster) operations.Hook {
+func NewAppProjectMutationHook(ctx context.Context, cluster *cluster.Cluster, stateLookup StateLookup) operations.Hook {
 	return operations.Hook{
 		Create: func(r *v1.AdmissionRequest) (*operations.Result, error) {
-			return mutateAppProject(ctx, r, cluster)
+			return mutateAppProject(ctx, r, cluster, stateLookup)
 		},
 		Update: func(r *v1.AdmissionRequest) (*operations.Result, error) {
-			return mutateAppProject(ctx, r, cluster)
+			return mutateAppProject(ctx, r, cluster, stateLookup)
 		},
 	}
 }
 
 // mutateAppProject mutates the sourceRepos in ArgoCD AppProject to point to the Zarf git server.
-func mutateAppProject(ctx context.Context, r *v1.AdmissionRequest, cluster *cluster.Cluster) (*operations.Result, error) {
+func mutateAppProject(ctx context.Context, r *v1.AdmissionRequest, cluster *cluster.Cluster, stateLookup StateLookup) (*operations.Result, error) {
 	l := logger.From(ctx)
-	s, err := cluster.LoadState(ctx)
+	s, err := stateLookup.lookup(ctx, cluster, r.Kind.Kind, r.Namespace, r.Name)
 	if err != nil {
 		return nil, err
 	}
+	if s == nil {
+		return admitUnchanged(), nil
+	}
 	if !s.GitServer.IsConfigured() {
 		l.Debug("no Zarf git server configured, skipping ArgoCD AppProject mutation")
 		return &operations.Result{Allowed: true}, nil
@@ -38,7 +38,7 @@ func TestArgoAppProjectWebhook(t *testing.T) {
 		PushUsername: "a-push-user",
 	}}
 	c := createTestClientWithZarfState(ctx, t, s)
-	handler := admission.NewHandler().Serve(ctx, NewAppProjectMutationHook(ctx, c))
+	handler := admission.NewHandler().Serve(ctx, NewAppProjectMutationHook(ctx, c, StateLookup{}))
 
 	tests := []admissionTest{
 		{
@@ -35,28 +35,31 @@ type RepoCreds struct {
 }
 
 // NewRepositorySecretMutationHook creates a new instance of the ArgoCD repository secret mutation hook.
-func NewRepositorySecretMutationHook(ctx context.Context, cluster *cluster.Cluster) operations.Hook {
+func NewRepositorySecretMutationHook(ctx context.Context, cluster *cluster.Cluster, stateLookup StateLookup) operations.Hook {
 	return operations.Hook{
 		Create: func(r *v1.AdmissionRequest) (*operations.Result, error) {
-			return mutateRepositorySecret(ctx, r, cluster)
+			return mutateRepositorySecret(ctx, r, cluster, stateLookup)
 		},
 		Update: func(r *v1.AdmissionRequest) (*operations.Result, error) {
-			return mutateRepositorySecret(ctx, r, cluster)
+			return mutateRepositorySecret(ctx, r, cluster, stateLookup)
 		},
 	}
 }
 
 // mutateRepositorySecret mutates the git URL in the ArgoCD repository secret to point to the repository URL defined in the ZarfState.
-func mutateRepositorySecret(ctx context.Context, r *v1.AdmissionRequest, cluster *cluster.Cluster) (*operations.Result, error) {
+func mutateRepositorySecret(ctx context.Context, r *v1.AdmissionRequest, cluster *cluster.Cluster, stateLookup StateLookup) (*operations.Result, error) {
 	l := logger.From(ctx)
 	isCreate := r.Operation == v1.Create
 	isUpdate := r.Operation == v1.Update
 	var isPatched bool
 
-	s, err := cluster.LoadState(ctx)
+	s, err := stateLookup.lookup(ctx, cluster, r.Kind.Kind, r.Namespace, r.Name)
 	if err != nil {
 		return nil, err
 	}
+	if s == nil {
+		return admitUnchanged(), nil
+	}
 	if !s.GitServer.IsConfigured() {
 		l.Debug("no Zarf git server configured, skipping ArgoCD repository secret mutation")
 		return &operations.Result{Allowed: true}, nil
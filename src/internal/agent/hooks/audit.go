@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package hooks provides HTTP handlers for the mutating webhook.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const auditSinkTimeout = 5 * time.Second
+
+// PodAuditEvent records the decision the agent made for the admission request of a pod, or of a workload whose pod
+// template it mutates.
+type PodAuditEvent struct {
+	Time         time.Time `json:"time"`
+	UID          string    `json:"uid"`
+	Operation    string    `json:"operation"`
+	Kind         string    `json:"kind"`
+	SubResource  string    `json:"subResource,omitempty"`
+	Namespace    string    `json:"namespace"`
+	Name         string    `json:"name,omitempty"`
+	GenerateName string    `json:"generateName,omitempty"`
+	// Mutated is false when the agent allowed the pod without changing it
+	Mutated bool `json:"mutated"`
+	// DryRun is set when the mutation was only reported and the pod was admitted unchanged
+	DryRun bool `json:"dryRun,omitempty"`
+	// SkipReason explains why the pod was not mutated
+	SkipReason string `json:"skipReason,omitempty"`
+	// Images lists every image reference the agent rewrote
+	Images []ImageRewrite `json:"images,omitempty"`
+	// PullSecret is the image pull secret the agent set on the pod
+	PullSecret string `json:"pullSecret,omitempty"`
+	// Error is set when the agent failed to mutate the pod
+	Error string `json:"error,omitempty"`
+}
+
+// ImageRewrite records an image reference of a pod and what the agent rewrote it to.
+type ImageRewrite struct {
+	// Source is the field the image came from, e.g. container, initContainer, ephemeralContainer or volume
+	Source    string `json:"source"`
+	Name      string `json:"name"`
+	Original  string `json:"original"`
+	Rewritten string `json:"rewritten"`
+}
+
+// AuditSink posts audit events as JSON to an HTTP endpoint.
+type AuditSink struct {
+	url    string
+	strict bool
+	client *http.Client
+}
+
+// NewAuditSink returns a sink that posts to the given URL. When strict is true a failure to record an event
+// rejects the admission request, otherwise the failure is only logged.
+func NewAuditSink(url string, strict bool) *AuditSink {
+	return &AuditSink{
+		url:    url,
+		strict: strict,
+		client: &http.Client{Timeout: auditSinkTimeout},
+	}
+}
+
+// Send posts the event to the sink.
+func (s *AuditSink) Send(ctx context.Context, event any) error {
+	b, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	//nolint: errcheck // ignore
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("audit sink %s responded with status %s", s.url, resp.Status)
+	}
+	return nil
+}
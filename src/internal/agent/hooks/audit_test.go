@@ -0,0 +1,149 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/internal/agent/http/admission"
+	"github.com/zarf-dev/zarf/src/pkg/state"
+	v1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type auditRecorder struct {
+	mu     sync.Mutex
+	status int
+	events []PodAuditEvent
+}
+
+func (a *auditRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var event PodAuditEvent
+	if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	a.events = append(a.events, event)
+	w.WriteHeader(a.status)
+}
+
+func TestPodMutationAudit(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := &state.State{RegistryInfo: state.RegistryInfo{Address: "127.0.0.1:31999"}}
+	c := createTestClientWithZarfState(ctx, t, s)
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "podinfo-",
+			Namespace:    "podinfo",
+		},
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "init", Image: "busybox"}},
+			Containers:     []corev1.Container{{Name: "nginx", Image: "nginx"}},
+			Volumes: []corev1.Volume{
+				{
+					Name: "artifact",
+					VolumeSource: corev1.VolumeSource{
+						Image: &corev1.ImageVolumeSource{Reference: "quay.io/crio/artifact:v1"},
+					},
+				},
+			},
+		},
+	}
+
+	t.Run("mutated pod", func(t *testing.T) {
+		t.Parallel()
+		recorder := &auditRecorder{status: http.StatusOK}
+		srv := httptest.NewServer(recorder)
+		t.Cleanup(srv.Close)
+
+		handler := admission.NewHandler().Serve(ctx, NewPodMutationHook(ctx, c, NewAuditSink(srv.URL, false), false, StateLookup{}))
+		req := createPodAdmissionRequest(t, v1.Create, pod, "")
+		req.UID = "b6f3f4c1-5f1a-4c5e-9d0a-1f2e3d4c5b6a"
+		req.Namespace = "podinfo"
+		rr := sendAdmissionRequest(t, req, handler)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		require.Len(t, recorder.events, 1)
+		event := recorder.events[0]
+		require.False(t, event.Time.IsZero())
+		event.Time = time.Time{}
+		require.Equal(t, PodAuditEvent{
+			UID:          "b6f3f4c1-5f1a-4c5e-9d0a-1f2e3d4c5b6a",
+			Operation:    "CREATE",
+			Kind:         "Pod",
+			Namespace:    "podinfo",
+			GenerateName: "podinfo-",
+			Mutated:      true,
+			PullSecret:   config.ZarfImagePullSecretName,
+			Images: []ImageRewrite{
+				{Source: "initContainer", Name: "init", Original: "busybox", Rewritten: "127.0.0.1:31999/library/busybox:latest-zarf-2140033595"},
+				{Source: "container", Name: "nginx", Original: "nginx", Rewritten: "127.0.0.1:31999/library/nginx:latest-zarf-3793515731"},
+				{Source: "volume", Name: "artifact", Original: "quay.io/crio/artifact:v1", Rewritten: "127.0.0.1:31999/crio/artifact:v1-zarf-2568457951"},
+			},
+		}, event)
+	})
+
+	t.Run("skipped pod", func(t *testing.T) {
+		t.Parallel()
+		recorder := &auditRecorder{status: http.StatusOK}
+		srv := httptest.NewServer(recorder)
+		t.Cleanup(srv.Close)
+
+		handler := admission.NewHandler().Serve(ctx, NewPodMutationHook(ctx, c, NewAuditSink(srv.URL, false), false, StateLookup{}))
+		patched := pod.DeepCopy()
+		patched.Name = "podinfo-abc12"
+		patched.Labels = map[string]string{"zarf-agent": "patched"}
+		rr := sendAdmissionRequest(t, createPodAdmissionRequest(t, v1.Update, patched, ""), handler)
+		require.Equal(t, http.StatusOK, rr.Code)
+
+		require.Len(t, recorder.events, 1)
+		event := recorder.events[0]
+		require.Equal(t, "UPDATE", event.Operation)
+		require.Equal(t, "podinfo-abc12", event.Name)
+		require.False(t, event.Mutated)
+		require.Equal(t, "pod has already been mutated by the Zarf agent", event.SkipReason)
+		require.Empty(t, event.Images)
+		require.Empty(t, event.PullSecret)
+	})
+
+	t.Run("unreachable sink fails open", func(t *testing.T) {
+		t.Parallel()
+		recorder := &auditRecorder{status: http.StatusServiceUnavailable}
+		srv := httptest.NewServer(recorder)
+		t.Cleanup(srv.Close)
+
+		handler := admission.NewHandler().Serve(ctx, NewPodMutationHook(ctx, c, NewAuditSink(srv.URL, false), false, StateLookup{}))
+		rr := sendAdmissionRequest(t, createPodAdmissionRequest(t, v1.Create, pod, ""), handler)
+		require.Equal(t, http.StatusOK, rr.Code)
+		require.Len(t, recorder.events, 1)
+	})
+
+	t.Run("unreachable sink rejects in strict mode", func(t *testing.T) {
+		t.Parallel()
+		recorder := &auditRecorder{status: http.StatusServiceUnavailable}
+		srv := httptest.NewServer(recorder)
+		t.Cleanup(srv.Close)
+
+		handler := admission.NewHandler().Serve(ctx, NewPodMutationHook(ctx, c, NewAuditSink(srv.URL, true), false, StateLookup{}))
+		rr := sendAdmissionRequest(t, createPodAdmissionRequest(t, v1.Create, pod, ""), handler)
+		verifyAdmission(t, rr, admissionTest{
+			code:        http.StatusInternalServerError,
+			errContains: "unable to record the pod mutation to the audit sink",
+		})
+	})
+}
@@ -9,11 +9,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"path"
 
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/zarf-dev/zarf/src/internal/agent/operations"
 	"github.com/zarf-dev/zarf/src/pkg/images"
 	"github.com/zarf-dev/zarf/src/pkg/state"
+	"github.com/zarf-dev/zarf/src/pkg/transform"
 	"oras.land/oras-go/v2"
 	"oras.land/oras-go/v2/registry"
 	orasRemote "oras.land/oras-go/v2/registry/remote"
@@ -28,6 +30,36 @@ func getLabelPatch(currLabels map[string]string) operations.PatchOperation {
 	return operations.ReplacePatchOperation("/metadata/labels", currLabels)
 }
 
+// transformImage points an image at the destination of the first agent image rewrite whose source matches the registry
+// of the image, or at the Zarf registry when no rewrite matches. It returns false for images of passthrough registries,
+// which must be left untouched.
+func transformImage(zarfState *state.State, image string) (string, bool, error) {
+	ref, err := transform.ParseImageRef(image)
+	if err != nil {
+		return "", false, err
+	}
+	for _, pattern := range zarfState.AgentImagePassthrough {
+		if ok, _ := path.Match(pattern, ref.Host); ok {
+			return image, false, nil
+		}
+	}
+	for _, rewrite := range zarfState.AgentImageRewrites {
+		if ok, _ := path.Match(rewrite.Source, ref.Host); ok {
+			replacement, err := transform.ImageTransformHostWithoutChecksum(rewrite.Destination, image)
+			return replacement, true, err
+		}
+	}
+	replacement, err := transform.ImageTransformHost(zarfState.RegistryInfo.Address, image)
+	return replacement, true, err
+}
+
+// isZarfRegistryImage reports whether an image transformed by transformImage points at the Zarf registry rather than at
+// the destination of an agent image rewrite.
+func isZarfRegistryImage(zarfState *state.State, image string) bool {
+	ref, err := transform.ParseImageRef(image)
+	return err == nil && ref.Host == zarfState.RegistryInfo.Address
+}
+
 func getManifestConfigMediaType(ctx context.Context, zarfState *state.State, transport http.RoundTripper, imageAddress string) (string, error) {
 	ref, err := registry.ParseReference(imageAddress)
 	if err != nil {
@@ -0,0 +1,202 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package hooks contains the mutation hooks for the Zarf agent.
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/internal/agent/operations"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/pkg/logger"
+	v1 "k8s.io/api/admission/v1"
+)
+
+// NewCustomResourceMutationHook creates a new instance of the custom resource mutation hook.
+func NewCustomResourceMutationHook(ctx context.Context, cluster *cluster.Cluster, stateLookup StateLookup) operations.Hook {
+	return operations.Hook{
+		Create: func(r *v1.AdmissionRequest) (*operations.Result, error) {
+			return mutateCustomResource(ctx, r, cluster, stateLookup)
+		},
+		Update: func(r *v1.AdmissionRequest) (*operations.Result, error) {
+			return mutateCustomResource(ctx, r, cluster, stateLookup)
+		},
+	}
+}
+
+// mutateCustomResource rewrites the image fields configured in the Zarf state for the kind of the resource.
+func mutateCustomResource(ctx context.Context, r *v1.AdmissionRequest, cluster *cluster.Cluster, stateLookup StateLookup) (*operations.Result, error) {
+	l := logger.From(ctx)
+
+	obj := map[string]any{}
+	if err := json.Unmarshal(r.Object.Raw, &obj); err != nil {
+		return nil, fmt.Errorf(lang.ErrUnmarshal, err)
+	}
+
+	zarfState, err := stateLookup.lookup(ctx, cluster, r.Kind.Kind, r.Namespace, r.Name)
+	if err != nil {
+		return nil, err
+	}
+	if zarfState == nil {
+		return admitUnchanged(), nil
+	}
+	var patches []operations.PatchOperation
+	for _, field := range zarfState.AgentImageFields {
+		if !field.Matches(r.Kind.Group, r.Kind.Version, r.Kind.Kind) {
+			continue
+		}
+		for _, path := range field.Paths {
+			images, err := findImageFields(obj, path)
+			if err != nil {
+				return nil, fmt.Errorf("unable to find image fields of %s at %s: %w", r.Kind.Kind, path, err)
+			}
+			for _, image := range images {
+				replacement, ok, err := transformImage(zarfState, image.value)
+				if err != nil {
+					return nil, err
+				}
+				if !ok {
+					continue
+				}
+				l.Debug("mutating the custom resource image to the Zarf URL", "kind", r.Kind.Kind, "name", r.Name, "path", image.pointer, "original", image.value, "mutated", replacement)
+				patches = append(patches, operations.ReplacePatchOperation(image.pointer, replacement))
+			}
+		}
+	}
+	if len(patches) == 0 {
+		return &operations.Result{
+			Allowed:  true,
+			PatchOps: patches,
+		}, nil
+	}
+
+	metadata, _ := obj["metadata"].(map[string]any)
+	labels := map[string]string{}
+	if rawLabels, ok := metadata["labels"].(map[string]any); ok {
+		for k, v := range rawLabels {
+			labels[k] = fmt.Sprint(v)
+		}
+	}
+	patches = append(patches, getLabelPatch(labels))
+
+	return &operations.Result{
+		Allowed:  true,
+		PatchOps: patches,
+	}, nil
+}
+
+// fieldSegment is a single step of a JSONPath expression, either a field name or an index into a list.
+type fieldSegment struct {
+	field    string
+	index    int
+	wildcard bool
+	isIndex  bool
+}
+
+// parseFieldPath parses the subset of JSONPath used for image fields, e.g. .spec.image, {.spec.containers[*].image}
+// or $.spec.sidecars[0].image.
+func parseFieldPath(path string) ([]fieldSegment, error) {
+	expr := strings.TrimSpace(path)
+	expr = strings.TrimSuffix(strings.TrimPrefix(expr, "{"), "}")
+	expr = strings.TrimPrefix(expr, "$")
+	if !strings.HasPrefix(expr, ".") {
+		return nil, fmt.Errorf("invalid path %q, must start with a '.'", path)
+	}
+
+	segments := []fieldSegment{}
+	for _, part := range strings.Split(expr[1:], ".") {
+		name, rest, _ := strings.Cut(part, "[")
+		if name == "" {
+			return nil, fmt.Errorf("invalid path %q, field names can not be empty", path)
+		}
+		segments = append(segments, fieldSegment{field: name})
+		for rest != "" {
+			selector, remaining, ok := strings.Cut(rest, "]")
+			if !ok {
+				return nil, fmt.Errorf("invalid path %q, missing ']'", path)
+			}
+			if selector == "*" {
+				segments = append(segments, fieldSegment{isIndex: true, wildcard: true})
+			} else {
+				idx, err := strconv.Atoi(selector)
+				if err != nil || idx < 0 {
+					return nil, fmt.Errorf("invalid path %q, list selectors must be '*' or a non negative index", path)
+				}
+				segments = append(segments, fieldSegment{isIndex: true, index: idx})
+			}
+			if remaining != "" && !strings.HasPrefix(remaining, "[") {
+				return nil, fmt.Errorf("invalid path %q, unexpected %q", path, remaining)
+			}
+			rest = strings.TrimPrefix(remaining, "[")
+		}
+	}
+	return segments, nil
+}
+
+// imageField is an image reference found in a resource and the JSON pointer to it.
+type imageField struct {
+	pointer string
+	value   string
+}
+
+// findImageFields returns the string values the path points to in obj. Fields that are not set in obj are skipped.
+func findImageFields(obj map[string]any, path string) ([]imageField, error) {
+	segments, err := parseFieldPath(path)
+	if err != nil {
+		return nil, err
+	}
+	found := []imageField{}
+	var walk func(value any, ptr string, segments []fieldSegment) error
+	walk = func(value any, ptr string, segments []fieldSegment) error {
+		if len(segments) == 0 {
+			image, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("field %s is not a string", ptr)
+			}
+			if image != "" {
+				found = append(found, imageField{pointer: ptr, value: image})
+			}
+			return nil
+		}
+		seg := segments[0]
+		if !seg.isIndex {
+			m, ok := value.(map[string]any)
+			if !ok {
+				return nil
+			}
+			child, ok := m[seg.field]
+			if !ok {
+				return nil
+			}
+			return walk(child, ptr+"/"+escapeJSONPointer(seg.field), segments[1:])
+		}
+		list, ok := value.([]any)
+		if !ok {
+			return nil
+		}
+		for i, child := range list {
+			if !seg.wildcard && i != seg.index {
+				continue
+			}
+			if err := walk(child, fmt.Sprintf("%s/%d", ptr, i), segments[1:]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(obj, "", segments); err != nil {
+		return nil, err
+	}
+	return found, nil
+}
+
+// escapeJSONPointer escapes a field name for use in a JSON pointer, see https://tools.ietf.org/html/rfc6901.
+func escapeJSONPointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,194 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/internal/agent/http/admission"
+	"github.com/zarf-dev/zarf/src/internal/agent/operations"
+	"github.com/zarf-dev/zarf/src/pkg/state"
+	v1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func createCustomResourceAdmissionRequest(t *testing.T, op v1.Operation, gvk metav1.GroupVersionKind, obj map[string]any) *v1.AdmissionRequest {
+	t.Helper()
+	raw, err := json.Marshal(obj)
+	require.NoError(t, err)
+	return &v1.AdmissionRequest{
+		Operation: op,
+		Kind:      gvk,
+		Object: runtime.RawExtension{
+			Raw: raw,
+		},
+	}
+}
+
+func TestCustomResourceMutationWebhook(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := &state.State{
+		RegistryInfo: state.RegistryInfo{Address: "127.0.0.1:31999"},
+		AgentImageFields: []state.AgentImageField{
+			{
+				Group:   "monitoring.coreos.com",
+				Version: "v1",
+				Kind:    "Prometheus",
+				Paths:   []string{".spec.image", "{.spec.containers[*].image}"},
+			},
+			{
+				Group:   "monitoring.coreos.com",
+				Version: "*",
+				Kind:    "Alertmanager",
+				Paths:   []string{"$.spec.image"},
+			},
+		},
+	}
+	c := createTestClientWithZarfState(ctx, t, s)
+	handler := admission.NewHandler().Serve(ctx, NewCustomResourceMutationHook(ctx, c, StateLookup{}))
+
+	prometheus := metav1.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "Prometheus"}
+	tests := []admissionTest{
+		{
+			name: "should mutate the configured image fields",
+			admissionReq: createCustomResourceAdmissionRequest(t, v1.Create, prometheus, map[string]any{
+				"apiVersion": "monitoring.coreos.com/v1",
+				"kind":       "Prometheus",
+				"metadata": map[string]any{
+					"name":   "k8s",
+					"labels": map[string]any{"app": "prometheus"},
+				},
+				"spec": map[string]any{
+					"image": "quay.io/prometheus/prometheus:v2.53.0",
+					"containers": []any{
+						map[string]any{"name": "thanos-sidecar", "image": "quay.io/thanos/thanos:v0.35.1"},
+						map[string]any{"name": "config-reloader"},
+					},
+				},
+			}),
+			patch: []operations.PatchOperation{
+				operations.ReplacePatchOperation("/spec/image", "127.0.0.1:31999/prometheus/prometheus:v2.53.0-zarf-1047855950"),
+				operations.ReplacePatchOperation("/spec/containers/0/image", "127.0.0.1:31999/thanos/thanos:v0.35.1-zarf-3631845382"),
+				operations.ReplacePatchOperation("/metadata/labels", map[string]string{
+					"app":        "prometheus",
+					"zarf-agent": "patched",
+				}),
+			},
+			code: http.StatusOK,
+		},
+		{
+			name: "should match any version when configured with a wildcard",
+			admissionReq: createCustomResourceAdmissionRequest(t, v1.Update,
+				metav1.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1alpha1", Kind: "Alertmanager"},
+				map[string]any{
+					"spec": map[string]any{"image": "quay.io/prometheus/alertmanager:v0.27.0"},
+				}),
+			patch: []operations.PatchOperation{
+				operations.ReplacePatchOperation("/spec/image", "127.0.0.1:31999/prometheus/alertmanager:v0.27.0-zarf-3373367403"),
+				operations.ReplacePatchOperation("/metadata/labels", map[string]string{"zarf-agent": "patched"}),
+			},
+			code: http.StatusOK,
+		},
+		{
+			name: "should not mutate a resource without the configured fields",
+			admissionReq: createCustomResourceAdmissionRequest(t, v1.Create, prometheus, map[string]any{
+				"spec": map[string]any{"replicas": 2},
+			}),
+			code: http.StatusOK,
+		},
+		{
+			name: "should not mutate an unconfigured kind",
+			admissionReq: createCustomResourceAdmissionRequest(t, v1.Create,
+				metav1.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "ThanosRuler"},
+				map[string]any{
+					"spec": map[string]any{"image": "quay.io/thanos/thanos:v0.35.1"},
+				}),
+			code: http.StatusOK,
+		},
+		{
+			name: "should error when the field is not a string",
+			admissionReq: createCustomResourceAdmissionRequest(t, v1.Create, prometheus, map[string]any{
+				"spec": map[string]any{"image": map[string]any{"repository": "quay.io/prometheus/prometheus"}},
+			}),
+			code:        http.StatusInternalServerError,
+			errContains: "field /spec/image is not a string",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			rr := sendAdmissionRequest(t, tt.admissionReq, handler)
+			verifyAdmission(t, rr, tt)
+		})
+	}
+}
+
+func TestParseFieldPath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		path        string
+		expected    []fieldSegment
+		errContains string
+	}{
+		{
+			name:     "dot path",
+			path:     ".spec.image",
+			expected: []fieldSegment{{field: "spec"}, {field: "image"}},
+		},
+		{
+			name: "wildcard and index in braces",
+			path: "{$.spec.containers[*].env[1].value}",
+			expected: []fieldSegment{
+				{field: "spec"},
+				{field: "containers"},
+				{isIndex: true, wildcard: true},
+				{field: "env"},
+				{isIndex: true, index: 1},
+				{field: "value"},
+			},
+		},
+		{
+			name:        "missing leading dot",
+			path:        "spec.image",
+			errContains: "must start with a '.'",
+		},
+		{
+			name:        "empty field",
+			path:        ".spec..image",
+			errContains: "field names can not be empty",
+		},
+		{
+			name:        "invalid selector",
+			path:        ".spec.containers[name].image",
+			errContains: "list selectors must be '*' or a non negative index",
+		},
+		{
+			name:        "unclosed selector",
+			path:        ".spec.containers[0",
+			errContains: "missing ']'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			segments, err := parseFieldPath(tt.path)
+			if tt.errContains != "" {
+				require.ErrorContains(t, err, tt.errContains)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, segments)
+		})
+	}
+}
@@ -25,26 +25,29 @@ import (
 const AgentErrTransformGitURL = "unable to transform the git url"
 
 // NewGitRepositoryMutationHook creates a new instance of the git repo mutation hook.
-func NewGitRepositoryMutationHook(ctx context.Context, cluster *cluster.Cluster) operations.Hook {
+func NewGitRepositoryMutationHook(ctx context.Context, cluster *cluster.Cluster, stateLookup StateLookup) operations.Hook {
 	return operations.Hook{
 		Create: func(r *v1.AdmissionRequest) (*operations.Result, error) {
-			return mutateGitRepo(ctx, r, cluster)
+			return mutateGitRepo(ctx, r, cluster, stateLookup)
 		},
 		Update: func(r *v1.AdmissionRequest) (*operations.Result, error) {
-			return mutateGitRepo(ctx, r, cluster)
+			return mutateGitRepo(ctx, r, cluster, stateLookup)
 		},
 	}
 }
 
 // mutateGitRepoCreate mutates the git repository url to point to the repository URL defined in the ZarfState.
-func mutateGitRepo(ctx context.Context, r *v1.AdmissionRequest, cluster *cluster.Cluster) (*operations.Result, error) {
+func mutateGitRepo(ctx context.Context, r *v1.AdmissionRequest, cluster *cluster.Cluster, stateLookup StateLookup) (*operations.Result, error) {
 	l := logger.From(ctx)
 	var patches []operations.PatchOperation
 
-	s, err := cluster.LoadState(ctx)
+	s, err := stateLookup.lookup(ctx, cluster, r.Kind.Kind, r.Namespace, r.Name)
 	if err != nil {
 		return nil, err
 	}
+	if s == nil {
+		return admitUnchanged(), nil
+	}
 	if !s.GitServer.IsConfigured() {
 		l.Debug("no Zarf git server configured, skipping Flux GitRepository mutation")
 		return &operations.Result{Allowed: true}, nil
@@ -81,10 +81,10 @@ func TestGitMutationHooksSkipWhenUnConfigured(t *testing.T) {
 	}
 
 	hooks := map[string]http.HandlerFunc{
-		"argocd Application":       admission.NewHandler().Serve(ctx, NewApplicationMutationHook(ctx, c)),
-		"argocd ApplicationSet":    admission.NewHandler().Serve(ctx, NewApplicationSetMutationHook(ctx, c)),
-		"argocd AppProject":        admission.NewHandler().Serve(ctx, NewAppProjectMutationHook(ctx, c)),
-		"argocd repository secret": admission.NewHandler().Serve(ctx, NewRepositorySecretMutationHook(ctx, c)),
+		"argocd Application":       admission.NewHandler().Serve(ctx, NewApplicationMutationHook(ctx, c, StateLookup{})),
+		"argocd ApplicationSet":    admission.NewHandler().Serve(ctx, NewApplicationSetMutationHook(ctx, c, StateLookup{})),
+		"argocd AppProject":        admission.NewHandler().Serve(ctx, NewAppProjectMutationHook(ctx, c, StateLookup{})),
+		"argocd repository secret": admission.NewHandler().Serve(ctx, NewRepositorySecretMutationHook(ctx, c, StateLookup{})),
 	}
 
 	for _, tc := range cases {
@@ -100,7 +100,7 @@ func TestGitMutationHooksSkipWhenUnConfigured(t *testing.T) {
 		t.Parallel()
 		raw := runtime.RawExtension{Raw: []byte(`{"spec":{"url":"https://example.com/org/repo"}}`)}
 		req := &v1.AdmissionRequest{Operation: v1.Create, Object: raw}
-		handler := admission.NewHandler().Serve(ctx, NewGitRepositoryMutationHook(ctx, c))
+		handler := admission.NewHandler().Serve(ctx, NewGitRepositoryMutationHook(ctx, c, StateLookup{}))
 		rr := sendAdmissionRequest(t, req, handler)
 		verifyAdmission(t, rr, admissionTest{code: http.StatusOK})
 	})
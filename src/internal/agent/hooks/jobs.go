@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/zarf-dev/zarf/src/config/lang"
+	"github.com/zarf-dev/zarf/src/internal/agent/operations"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/pkg/logger"
+	v1 "k8s.io/api/admission/v1"
+	batchv1 "k8s.io/api/batch/v1"
+)
+
+// NewJobMutationHook creates a new instance of the job mutation hook. The pod template of a job is immutable so only
+// jobs that are created are mutated. Decisions are audited, dry run and wait for the Zarf state the same as the pod hook.
+func NewJobMutationHook(ctx context.Context, cluster *cluster.Cluster, auditSink *AuditSink, dryRun bool, stateLookup StateLookup) operations.Hook {
+	return operations.Hook{
+		Create: func(r *v1.AdmissionRequest) (*operations.Result, error) {
+			return auditMutation(ctx, r, cluster, auditSink, dryRun, stateLookup, "Job", mutateJob)
+		},
+	}
+}
+
+// NewCronJobMutationHook creates a new instance of the cron job mutation hook. Decisions are audited, dry run and wait
+// for the Zarf state the same as the pod hook.
+func NewCronJobMutationHook(ctx context.Context, cluster *cluster.Cluster, auditSink *AuditSink, dryRun bool, stateLookup StateLookup) operations.Hook {
+	return operations.Hook{
+		Create: func(r *v1.AdmissionRequest) (*operations.Result, error) {
+			return auditMutation(ctx, r, cluster, auditSink, dryRun, stateLookup, "CronJob", mutateCronJob)
+		},
+		Update: func(r *v1.AdmissionRequest) (*operations.Result, error) {
+			return auditMutation(ctx, r, cluster, auditSink, dryRun, stateLookup, "CronJob", mutateCronJob)
+		},
+	}
+}
+
+// mutateJob points the images of the job pod template at the Zarf registry.
+func mutateJob(ctx context.Context, r *v1.AdmissionRequest, cluster *cluster.Cluster, stateLookup StateLookup, event *PodAuditEvent) (*operations.Result, error) {
+	job := &batchv1.Job{}
+	if err := json.Unmarshal(r.Object.Raw, job); err != nil {
+		return nil, fmt.Errorf(lang.AgentErrParseJob, err)
+	}
+	if job.Name != "" {
+		event.Name = job.Name
+	}
+	event.GenerateName = job.GenerateName
+
+	// Jobs created by a mutated cron job carry the label of the job template
+	if job.Labels["zarf-agent"] == "patched" {
+		event.SkipReason = "job has already been mutated by the Zarf agent"
+		return admitUnchanged(), nil
+	}
+	if isAgentIgnored(job.Spec.Template.Annotations) {
+		event.SkipReason = "job is annotated to be ignored by the Zarf agent"
+		return admitUnchanged(), nil
+	}
+
+	state, err := loadState(ctx, cluster, stateLookup, event)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		return admitUnchanged(), nil
+	}
+	registryURL := state.RegistryInfo.Address
+	logger.From(ctx).Info("using the Zarf registry URL to mutate the Job", "name", job.Name, "registry", registryURL)
+
+	patches, err := podSpecPatches(ctx, state, "/spec/template", job.Spec.Template.ObjectMeta, job.Spec.Template.Spec, event)
+	if err != nil {
+		return nil, err
+	}
+	patches = append(patches, getLabelPatch(job.Labels))
+
+	return &operations.Result{
+		Allowed:  true,
+		PatchOps: patches,
+	}, nil
+}
+
+// mutateCronJob points the images of the pod template nested in the cron job template at the Zarf registry. Cron jobs
+// are mutated on every update, not only until they are labeled as patched, so that a changed image is rewritten as well.
+func mutateCronJob(ctx context.Context, r *v1.AdmissionRequest, cluster *cluster.Cluster, stateLookup StateLookup, event *PodAuditEvent) (*operations.Result, error) {
+	cronJob := &batchv1.CronJob{}
+	if err := json.Unmarshal(r.Object.Raw, cronJob); err != nil {
+		return nil, fmt.Errorf(lang.AgentErrParseCronJob, err)
+	}
+	if cronJob.Name != "" {
+		event.Name = cronJob.Name
+	}
+
+	if isAgentIgnored(cronJob.Spec.JobTemplate.Spec.Template.Annotations) {
+		event.SkipReason = "cron job is annotated to be ignored by the Zarf agent"
+		return admitUnchanged(), nil
+	}
+
+	state, err := loadState(ctx, cluster, stateLookup, event)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		return admitUnchanged(), nil
+	}
+	registryURL := state.RegistryInfo.Address
+	logger.From(ctx).Info("using the Zarf registry URL to mutate the CronJob", "name", cronJob.Name, "registry", registryURL)
+
+	jobTemplate := cronJob.Spec.JobTemplate
+	patches, err := podSpecPatches(ctx, state, "/spec/jobTemplate/spec/template", jobTemplate.Spec.Template.ObjectMeta, jobTemplate.Spec.Template.Spec, event)
+	if err != nil {
+		return nil, err
+	}
+	// Label the job template so that the job hook skips the jobs the cron job creates
+	jobTemplateLabels := getLabelPatch(jobTemplate.Labels)
+	jobTemplateLabels.Path = "/spec/jobTemplate/metadata/labels"
+	patches = append(patches, jobTemplateLabels, getLabelPatch(cronJob.Labels))
+
+	return &operations.Result{
+		Allowed:  true,
+		PatchOps: patches,
+	}, nil
+}
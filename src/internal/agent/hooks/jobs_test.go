@@ -0,0 +1,372 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/internal/agent/http/admission"
+	"github.com/zarf-dev/zarf/src/internal/agent/operations"
+	"github.com/zarf-dev/zarf/src/pkg/state"
+	v1 "k8s.io/api/admission/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func createJobAdmissionRequest(t *testing.T, op v1.Operation, obj any) *v1.AdmissionRequest {
+	t.Helper()
+	raw, err := json.Marshal(obj)
+	require.NoError(t, err)
+	return &v1.AdmissionRequest{
+		Operation: op,
+		Object: runtime.RawExtension{
+			Raw: raw,
+		},
+	}
+}
+
+func TestJobMutationWebhook(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	s := &state.State{RegistryInfo: state.RegistryInfo{Address: "127.0.0.1:31999"}}
+	c := createTestClientWithZarfState(ctx, t, s)
+	handler := admission.NewHandler().Serve(ctx, NewJobMutationHook(ctx, c, nil, false, StateLookup{}))
+
+	tests := []admissionTest{
+		{
+			name: "job should be mutated",
+			admissionReq: createJobAdmissionRequest(t, v1.Create, &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"app": "backup"},
+				},
+				Spec: batchv1.JobSpec{
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Annotations: map[string]string{"should-be": "kept"},
+						},
+						Spec: corev1.PodSpec{
+							Containers:     []corev1.Container{{Name: "nginx", Image: "nginx"}},
+							InitContainers: []corev1.Container{{Name: "different", Image: "busybox"}},
+						},
+					},
+				},
+			}),
+			patch: []operations.PatchOperation{
+				operations.ReplacePatchOperation(
+					"/spec/template/spec/imagePullSecrets",
+					[]corev1.LocalObjectReference{{Name: config.ZarfImagePullSecretName}},
+				),
+				operations.ReplacePatchOperation(
+					"/spec/template/spec/initContainers/0/image",
+					"127.0.0.1:31999/library/busybox:latest-zarf-2140033595",
+				),
+				operations.ReplacePatchOperation(
+					"/spec/template/spec/containers/0/image",
+					"127.0.0.1:31999/library/nginx:latest-zarf-3793515731",
+				),
+				operations.ReplacePatchOperation(
+					"/spec/template/metadata/labels",
+					map[string]string{"zarf-agent": "patched"},
+				),
+				operations.ReplacePatchOperation(
+					"/spec/template/metadata/annotations",
+					map[string]string{
+						"zarf.dev/original-image-nginx":     "nginx",
+						"zarf.dev/original-image-different": "busybox",
+						"should-be":                         "kept",
+					},
+				),
+				operations.ReplacePatchOperation(
+					"/metadata/labels",
+					map[string]string{
+						"zarf-agent": "patched",
+						"app":        "backup",
+					},
+				),
+			},
+			code: http.StatusOK,
+		},
+		{
+			name: "job with zarf-agent patched label should not be mutated",
+			admissionReq: createJobAdmissionRequest(t, v1.Create, &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"zarf-agent": "patched"},
+				},
+				Spec: batchv1.JobSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{Name: "nginx", Image: "nginx"}},
+						},
+					},
+				},
+			}),
+			patch: nil,
+			code:  http.StatusOK,
+		},
+		{
+			name: "job with zarf.dev/agent ignore annotation on its pod template should not be mutated",
+			admissionReq: createJobAdmissionRequest(t, v1.Create, &batchv1.Job{
+				Spec: batchv1.JobSpec{
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Annotations: map[string]string{"zarf.dev/agent": "ignore"},
+						},
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{Name: "nginx", Image: "nginx"}},
+						},
+					},
+				},
+			}),
+			patch: nil,
+			code:  http.StatusOK,
+		},
+		{
+			name: "job with an image volume without a reference should error",
+			admissionReq: createJobAdmissionRequest(t, v1.Create, &batchv1.Job{
+				Spec: batchv1.JobSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{Name: "nginx", Image: "nginx"}},
+							Volumes: []corev1.Volume{{
+								Name:         "data",
+								VolumeSource: corev1.VolumeSource{Image: &corev1.ImageVolumeSource{}},
+							}},
+						},
+					},
+				},
+			}),
+			code:        http.StatusInternalServerError,
+			errContains: `volume "data" (index 0) has an ImageVolumeSource with empty reference`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			rr := sendAdmissionRequest(t, tt.admissionReq, handler)
+			verifyAdmission(t, rr, tt)
+		})
+	}
+}
+
+func TestCronJobMutationWebhook(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	s := &state.State{RegistryInfo: state.RegistryInfo{Address: "127.0.0.1:31999"}}
+	c := createTestClientWithZarfState(ctx, t, s)
+	handler := admission.NewHandler().Serve(ctx, NewCronJobMutationHook(ctx, c, nil, false, StateLookup{}))
+
+	tests := []admissionTest{
+		{
+			name: "cron job should be mutated",
+			admissionReq: createJobAdmissionRequest(t, v1.Create, &batchv1.CronJob{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"app": "backup"},
+				},
+				Spec: batchv1.CronJobSpec{
+					Schedule: "0 * * * *",
+					JobTemplate: batchv1.JobTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: map[string]string{"app": "backup"},
+						},
+						Spec: batchv1.JobSpec{
+							Template: corev1.PodTemplateSpec{
+								Spec: corev1.PodSpec{
+									Containers: []corev1.Container{{Name: "nginx", Image: "nginx"}},
+									Volumes: []corev1.Volume{{
+										Name:         "data",
+										VolumeSource: corev1.VolumeSource{Image: &corev1.ImageVolumeSource{Reference: "alpine"}},
+									}},
+								},
+							},
+						},
+					},
+				},
+			}),
+			patch: []operations.PatchOperation{
+				operations.ReplacePatchOperation(
+					"/spec/jobTemplate/spec/template/spec/imagePullSecrets",
+					[]corev1.LocalObjectReference{{Name: config.ZarfImagePullSecretName}},
+				),
+				operations.ReplacePatchOperation(
+					"/spec/jobTemplate/spec/template/spec/containers/0/image",
+					"127.0.0.1:31999/library/nginx:latest-zarf-3793515731",
+				),
+				operations.ReplacePatchOperation(
+					"/spec/jobTemplate/spec/template/spec/volumes/0/image/reference",
+					"127.0.0.1:31999/library/alpine:latest-zarf-1117969859",
+				),
+				operations.ReplacePatchOperation(
+					"/spec/jobTemplate/spec/template/metadata/labels",
+					map[string]string{"zarf-agent": "patched"},
+				),
+				operations.ReplacePatchOperation(
+					"/spec/jobTemplate/spec/template/metadata/annotations",
+					map[string]string{
+						"zarf.dev/original-image-nginx": "nginx",
+						"zarf.dev/original-volume-data": "alpine",
+					},
+				),
+				operations.ReplacePatchOperation(
+					"/spec/jobTemplate/metadata/labels",
+					map[string]string{
+						"zarf-agent": "patched",
+						"app":        "backup",
+					},
+				),
+				operations.ReplacePatchOperation(
+					"/metadata/labels",
+					map[string]string{
+						"zarf-agent": "patched",
+						"app":        "backup",
+					},
+				),
+			},
+			code: http.StatusOK,
+		},
+		{
+			name: "cron job with zarf-agent patched label should be mutated when an image changes",
+			admissionReq: createJobAdmissionRequest(t, v1.Update, &batchv1.CronJob{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"zarf-agent": "patched"},
+				},
+				Spec: batchv1.CronJobSpec{
+					JobTemplate: batchv1.JobTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: map[string]string{"zarf-agent": "patched"},
+						},
+						Spec: batchv1.JobSpec{
+							Template: corev1.PodTemplateSpec{
+								ObjectMeta: metav1.ObjectMeta{
+									Labels: map[string]string{"zarf-agent": "patched"},
+									Annotations: map[string]string{
+										"zarf.dev/original-image-nginx":   "nginx",
+										"zarf.dev/original-image-sidecar": "busybox",
+									},
+								},
+								Spec: corev1.PodSpec{
+									Containers: []corev1.Container{
+										{Name: "nginx", Image: "nginx:1.27"},
+										{Name: "sidecar", Image: "127.0.0.1:31999/library/busybox:latest-zarf-2140033595"},
+									},
+								},
+							},
+						},
+					},
+				},
+			}),
+			patch: []operations.PatchOperation{
+				operations.ReplacePatchOperation(
+					"/spec/jobTemplate/spec/template/spec/imagePullSecrets",
+					[]corev1.LocalObjectReference{{Name: config.ZarfImagePullSecretName}},
+				),
+				operations.ReplacePatchOperation(
+					"/spec/jobTemplate/spec/template/spec/containers/0/image",
+					"127.0.0.1:31999/library/nginx:1.27-zarf-3793515731",
+				),
+				operations.ReplacePatchOperation(
+					"/spec/jobTemplate/spec/template/spec/containers/1/image",
+					"127.0.0.1:31999/library/busybox:latest-zarf-2140033595",
+				),
+				operations.ReplacePatchOperation(
+					"/spec/jobTemplate/spec/template/metadata/labels",
+					map[string]string{"zarf-agent": "patched"},
+				),
+				operations.ReplacePatchOperation(
+					"/spec/jobTemplate/spec/template/metadata/annotations",
+					map[string]string{
+						"zarf.dev/original-image-nginx":   "nginx:1.27",
+						"zarf.dev/original-image-sidecar": "busybox",
+					},
+				),
+				operations.ReplacePatchOperation(
+					"/spec/jobTemplate/metadata/labels",
+					map[string]string{"zarf-agent": "patched"},
+				),
+				operations.ReplacePatchOperation(
+					"/metadata/labels",
+					map[string]string{"zarf-agent": "patched"},
+				),
+			},
+			code: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			rr := sendAdmissionRequest(t, tt.admissionReq, handler)
+			verifyAdmission(t, rr, tt)
+		})
+	}
+}
+
+func TestJobMutationDryRunAndMissingState(t *testing.T) {
+	t.Parallel()
+
+	job := &batchv1.Job{
+		Spec: batchv1.JobSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "nginx", Image: "nginx"}},
+				},
+			},
+		},
+	}
+
+	t.Run("dry run admits the job unchanged", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		c := createTestClientWithZarfState(ctx, t, &state.State{RegistryInfo: state.RegistryInfo{Address: "127.0.0.1:31999"}})
+		handler := admission.NewHandler().Serve(ctx, NewJobMutationHook(ctx, c, nil, true, StateLookup{}))
+
+		rr := sendAdmissionRequest(t, createJobAdmissionRequest(t, v1.Create, job), handler)
+		require.Equal(t, http.StatusOK, rr.Code)
+		var review v1.AdmissionReview
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&review))
+		require.True(t, review.Response.Allowed)
+		require.Empty(t, review.Response.Patch)
+		require.Contains(t, review.Response.AuditAnnotations[dryRunPatchAnnotation], "/spec/template/spec/containers/0/image")
+	})
+
+	t.Run("admits the job unchanged when failing open", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		c, _ := createTestClientWithMissingZarfState(ctx, t, 1000)
+		handler := admission.NewHandler().Serve(ctx, NewJobMutationHook(ctx, c, nil, false, StateLookup{Timeout: 500 * time.Millisecond, FailOpen: true}))
+
+		rr := sendAdmissionRequest(t, createJobAdmissionRequest(t, v1.Create, job), handler)
+		require.Equal(t, http.StatusOK, rr.Code)
+		var review v1.AdmissionReview
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&review))
+		require.True(t, review.Response.Allowed)
+		require.Empty(t, review.Response.Patch)
+	})
+
+	t.Run("rejects the cron job after the timeout", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		c, _ := createTestClientWithMissingZarfState(ctx, t, 1000)
+		handler := admission.NewHandler().Serve(ctx, NewCronJobMutationHook(ctx, c, nil, false, StateLookup{Timeout: 500 * time.Millisecond}))
+
+		cronJob := &batchv1.CronJob{Spec: batchv1.CronJobSpec{JobTemplate: batchv1.JobTemplateSpec{Spec: job.Spec}}}
+		rr := sendAdmissionRequest(t, createJobAdmissionRequest(t, v1.Create, cronJob), handler)
+		verifyAdmission(t, rr, admissionTest{
+			code:        http.StatusInternalServerError,
+			errContains: "the Zarf state was not found within 500ms",
+		})
+	})
+}
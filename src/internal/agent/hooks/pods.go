@@ -8,33 +8,135 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/config/lang"
 	"github.com/zarf-dev/zarf/src/internal/agent/operations"
 	"github.com/zarf-dev/zarf/src/pkg/cluster"
 	"github.com/zarf-dev/zarf/src/pkg/logger"
-	"github.com/zarf-dev/zarf/src/pkg/transform"
+	"github.com/zarf-dev/zarf/src/pkg/state"
 	v1 "k8s.io/api/admission/v1"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const annotationPrefix = "zarf.dev"
 
-// NewPodMutationHook creates a new instance of pods mutation hook.
-func NewPodMutationHook(ctx context.Context, cluster *cluster.Cluster) operations.Hook {
+// dryRunPatchAnnotation is the audit annotation that holds the JSON patch a dry run would have applied.
+const dryRunPatchAnnotation = "dry-run-patch"
+
+// mutateFunc mutates the object of an admission request and records the decision to the event.
+type mutateFunc func(ctx context.Context, r *v1.AdmissionRequest, cluster *cluster.Cluster, stateLookup StateLookup, event *PodAuditEvent) (*operations.Result, error)
+
+// NewPodMutationHook creates a new instance of pods mutation hook. When auditSink is not nil every decision is recorded to it.
+// In dry run the patches are only reported in the audit annotations of the response and the pod is admitted unchanged.
+// stateLookup sets how long the hook waits for the Zarf state when it does not exist yet.
+func NewPodMutationHook(ctx context.Context, cluster *cluster.Cluster, auditSink *AuditSink, dryRun bool, stateLookup StateLookup) operations.Hook {
 	return operations.Hook{
 		Create: func(r *v1.AdmissionRequest) (*operations.Result, error) {
-			return mutatePod(ctx, r, cluster)
+			return auditMutation(ctx, r, cluster, auditSink, dryRun, stateLookup, "Pod", mutatePod)
 		},
 		Update: func(r *v1.AdmissionRequest) (*operations.Result, error) {
-			return mutatePod(ctx, r, cluster)
+			return auditMutation(ctx, r, cluster, auditSink, dryRun, stateLookup, "Pod", mutatePod)
 		},
 	}
 }
 
+// auditMutation mutates the object of the given kind and records the decision to the audit sink.
+// Failing to record the decision only rejects the request when the sink is strict.
+func auditMutation(ctx context.Context, r *v1.AdmissionRequest, cluster *cluster.Cluster, auditSink *AuditSink, dryRun bool, stateLookup StateLookup, kind string, mutate mutateFunc) (*operations.Result, error) {
+	event := &PodAuditEvent{
+		Time:        time.Now().UTC(),
+		UID:         string(r.UID),
+		Operation:   string(r.Operation),
+		Kind:        kind,
+		SubResource: r.SubResource,
+		Namespace:   r.Namespace,
+		Name:        r.Name,
+	}
+	result, err := mutate(ctx, r, cluster, stateLookup, event)
+	if dryRun && err == nil {
+		event.DryRun, err = dryRunMutation(ctx, result, kind, event.Namespace, event.Name)
+	}
+	if auditSink == nil {
+		return result, err
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	if sendErr := auditSink.Send(ctx, event); sendErr != nil {
+		if auditSink.strict && err == nil {
+			return nil, fmt.Errorf("unable to record the %s mutation to the audit sink: %w", strings.ToLower(kind), sendErr)
+		}
+		logger.From(ctx).Warn("unable to record the mutation to the audit sink", "kind", kind, "error", sendErr)
+	}
+	return result, err
+}
+
+// NewDryRunHook wraps a mutation hook that does not support dry run itself, so that objects are admitted unchanged
+// and the patches the hook would have applied are only reported.
+func NewDryRunHook(ctx context.Context, hook operations.Hook) operations.Hook {
+	wrap := func(admit operations.AdmitFunc) operations.AdmitFunc {
+		if admit == nil {
+			return nil
+		}
+		return func(r *v1.AdmissionRequest) (*operations.Result, error) {
+			result, err := admit(r)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := dryRunMutation(ctx, result, r.Kind.Kind, r.Namespace, r.Name); err != nil {
+				return nil, err
+			}
+			return result, nil
+		}
+	}
+	return operations.Hook{
+		Create:  wrap(hook.Create),
+		Update:  wrap(hook.Update),
+		Delete:  wrap(hook.Delete),
+		Connect: wrap(hook.Connect),
+	}
+}
+
+// dryRunMutation moves the patches of the result into its audit annotations so that the object is admitted unchanged.
+// It returns false when there was nothing to patch.
+func dryRunMutation(ctx context.Context, result *operations.Result, kind, namespace, name string) (bool, error) {
+	if len(result.PatchOps) == 0 {
+		return false, nil
+	}
+	patch, err := json.Marshal(result.PatchOps)
+	if err != nil {
+		return false, fmt.Errorf("unable to marshal the dry run patch: %w", err)
+	}
+	logger.From(ctx).Info("dry run, admitting the object without the patch", "kind", kind, "namespace", namespace, "name", name, "patch", string(patch))
+	result.AuditAnnotations = map[string]string{dryRunPatchAnnotation: string(patch)}
+	result.PatchOps = []operations.PatchOperation{}
+	return true, nil
+}
+
+// loadState returns the Zarf state through the lookup. A nil state without an error means that the state was not found
+// and the lookup fails open, the caller must then admit the object unchanged.
+func loadState(ctx context.Context, cluster *cluster.Cluster, stateLookup StateLookup, event *PodAuditEvent) (*state.State, error) {
+	zarfState, err := stateLookup.lookup(ctx, cluster, event.Kind, event.Namespace, event.Name)
+	if zarfState == nil && err == nil {
+		event.SkipReason = "the Zarf state was not found"
+	}
+	return zarfState, err
+}
+
+// admitUnchanged admits the object of the request without patching it.
+func admitUnchanged() *operations.Result {
+	return &operations.Result{
+		Allowed:  true,
+		PatchOps: []operations.PatchOperation{},
+	}
+}
+
 func parsePod(object []byte) (*corev1.Pod, error) {
 	var pod corev1.Pod
 	if err := json.Unmarshal(object, &pod); err != nil {
@@ -43,6 +145,13 @@ func parsePod(object []byte) (*corev1.Pod, error) {
 	return &pod, nil
 }
 
+// isAgentIgnored reports whether the annotations opt a resource out of mutation with the same values as the
+// zarf.dev/agent label. The label is matched by the webhook configuration, the annotation is checked by the hooks.
+func isAgentIgnored(annotations map[string]string) bool {
+	value := annotations[cluster.AgentLabel]
+	return value == "skip" || value == "ignore"
+}
+
 func getImageAnnotationKey(ctx context.Context, containerName string) string {
 	return getAnnotationKey(ctx, "image-"+containerName)
 }
@@ -67,117 +176,194 @@ func getAnnotationKey(ctx context.Context, image string) string {
 	return key
 }
 
-func mutatePod(ctx context.Context, r *v1.AdmissionRequest, cluster *cluster.Cluster) (*operations.Result, error) {
+func mutatePod(ctx context.Context, r *v1.AdmissionRequest, cluster *cluster.Cluster, stateLookup StateLookup, event *PodAuditEvent) (*operations.Result, error) {
 	l := logger.From(ctx)
 	pod, err := parsePod(r.Object.Raw)
 	if err != nil {
 		return nil, fmt.Errorf(lang.AgentErrParsePod, err)
 	}
+	if pod.Name != "" {
+		event.Name = pod.Name
+	}
+	event.GenerateName = pod.GenerateName
+
+	if isAgentIgnored(pod.Annotations) {
+		event.SkipReason = "pod is annotated to be ignored by the Zarf agent"
+		return &operations.Result{
+			Allowed:  true,
+			PatchOps: []operations.PatchOperation{},
+		}, nil
+	}
 
 	if r.SubResource != "" {
-		return mutatePodSubresource(ctx, r, cluster)
+		return mutatePodSubresource(ctx, r, cluster, stateLookup, event)
 	}
 
 	if pod.Labels != nil && pod.Labels["zarf-agent"] == "patched" {
 		// We've already played with this pod, just keep swimming 🐟
+		event.SkipReason = "pod has already been mutated by the Zarf agent"
 		return &operations.Result{
 			Allowed:  true,
 			PatchOps: []operations.PatchOperation{},
 		}, nil
 	}
 
-	state, err := cluster.LoadState(ctx)
+	// The state is read for every request rather than cached so a change of the registry address applies to the next
+	// admission without restarting the agent
+	state, err := loadState(ctx, cluster, stateLookup, event)
 	if err != nil {
 		return nil, err
 	}
+	if state == nil {
+		return admitUnchanged(), nil
+	}
 	registryURL := state.RegistryInfo.Address
 
 	// Pods do not have a metadata.name at the time of admission if from a deployment so we don't log the name
 	l.Info("using the Zarf registry URL to mutate the Pod", "registry", registryURL)
 
-	var patches []operations.PatchOperation
+	patches, err := podSpecPatches(ctx, state, "", pod.ObjectMeta, pod.Spec, event)
+	if err != nil {
+		return nil, err
+	}
+
+	return &operations.Result{
+		Allowed:  true,
+		PatchOps: patches,
+	}, nil
+}
 
-	// Add the zarf secret to the podspec
-	zarfSecret := []corev1.LocalObjectReference{{Name: config.ZarfImagePullSecretName}}
-	patches = append(patches, operations.ReplacePatchOperation("/spec/imagePullSecrets", zarfSecret))
+// podSpecPatches points the images of a pod spec at the Zarf registry and labels the pod as patched, recording the
+// rewritten images to the event. basePath is the path of the pod template within a workload and empty for a pod.
+func podSpecPatches(ctx context.Context, zarfState *state.State, basePath string, meta metav1.ObjectMeta, spec corev1.PodSpec, event *PodAuditEvent) ([]operations.PatchOperation, error) {
+	var patches []operations.PatchOperation
+	usesZarfRegistry := false
 
-	updatedAnnotations := pod.Annotations
+	updatedAnnotations := meta.Annotations
 	if updatedAnnotations == nil {
 		updatedAnnotations = make(map[string]string)
 	}
 
 	// update the image host for each init container
-	for idx, container := range pod.Spec.InitContainers {
-		path := fmt.Sprintf("/spec/initContainers/%d/image", idx)
-		replacement, err := transform.ImageTransformHost(registryURL, container.Image)
+	for idx, container := range spec.InitContainers {
+		path := fmt.Sprintf("%s/spec/initContainers/%d/image", basePath, idx)
+		replacement, ok, err := transformImage(zarfState, container.Image)
 		if err != nil {
 			return nil, err
 		}
-		updatedAnnotations[getImageAnnotationKey(ctx, container.Name)] = container.Image
+		if !ok {
+			continue
+		}
+		setOriginalImageAnnotation(updatedAnnotations, getImageAnnotationKey(ctx, container.Name), container.Image, replacement)
+		usesZarfRegistry = usesZarfRegistry || isZarfRegistryImage(zarfState, replacement)
 		patches = append(patches, operations.ReplacePatchOperation(path, replacement))
+		event.Images = append(event.Images, ImageRewrite{Source: "initContainer", Name: container.Name, Original: container.Image, Rewritten: replacement})
 	}
 
 	// update the image host for each normal container
-	for idx, container := range pod.Spec.Containers {
-		path := fmt.Sprintf("/spec/containers/%d/image", idx)
-		replacement, err := transform.ImageTransformHost(registryURL, container.Image)
+	for idx, container := range spec.Containers {
+		path := fmt.Sprintf("%s/spec/containers/%d/image", basePath, idx)
+		replacement, ok, err := transformImage(zarfState, container.Image)
 		if err != nil {
 			return nil, err
 		}
-		updatedAnnotations[getImageAnnotationKey(ctx, container.Name)] = container.Image
+		if !ok {
+			continue
+		}
+		setOriginalImageAnnotation(updatedAnnotations, getImageAnnotationKey(ctx, container.Name), container.Image, replacement)
+		usesZarfRegistry = usesZarfRegistry || isZarfRegistryImage(zarfState, replacement)
 		patches = append(patches, operations.ReplacePatchOperation(path, replacement))
+		event.Images = append(event.Images, ImageRewrite{Source: "container", Name: container.Name, Original: container.Image, Rewritten: replacement})
 	}
 
 	// update the image host for each volume that contains an "image" reference
-	for idx, volume := range pod.Spec.Volumes {
+	for idx, volume := range spec.Volumes {
 		if volume.Image != nil {
 			if volume.Image.Reference == "" {
 				return nil, fmt.Errorf("volume %q (index %d) has an ImageVolumeSource with empty reference - this is invalid and must be specified", volume.Name, idx)
 			}
-			path := fmt.Sprintf("/spec/volumes/%d/image/reference", idx)
-			replacement, err := transform.ImageTransformHost(registryURL, volume.Image.Reference)
+			path := fmt.Sprintf("%s/spec/volumes/%d/image/reference", basePath, idx)
+			replacement, ok, err := transformImage(zarfState, volume.Image.Reference)
 			if err != nil {
 				return nil, fmt.Errorf("failed to transform volume %q (index %d) image reference %q: %w", volume.Name, idx, volume.Image.Reference, err)
 			}
-			updatedAnnotations[getVolumeAnnotationKey(ctx, volume.Name)] = volume.Image.Reference
+			if !ok {
+				continue
+			}
+			setOriginalImageAnnotation(updatedAnnotations, getVolumeAnnotationKey(ctx, volume.Name), volume.Image.Reference, replacement)
+			usesZarfRegistry = usesZarfRegistry || isZarfRegistryImage(zarfState, replacement)
 			patches = append(patches, operations.ReplacePatchOperation(path, replacement))
+			event.Images = append(event.Images, ImageRewrite{Source: "volume", Name: volume.Name, Original: volume.Image.Reference, Rewritten: replacement})
+		}
+	}
+
+	// Add the zarf secret to the podspec when an image is pulled from the Zarf registry
+	if usesZarfRegistry {
+		if secretPatch, ok := imagePullSecretPatch(basePath, spec.ImagePullSecrets); ok {
+			patches = append([]operations.PatchOperation{secretPatch}, patches...)
 		}
+		event.PullSecret = config.ZarfImagePullSecretName
 	}
 
 	// Add the "zarf-agent"="patched" label patch
-	patches = append(patches, getLabelPatch(pod.Labels))
+	labelPatch := getLabelPatch(meta.Labels)
+	labelPatch.Path = basePath + labelPatch.Path
+	patches = append(patches, labelPatch)
 
 	// Add the annotations label patch
-	patches = append(patches, operations.ReplacePatchOperation("/metadata/annotations", updatedAnnotations))
+	patches = append(patches, operations.ReplacePatchOperation(basePath+"/metadata/annotations", updatedAnnotations))
+	event.Mutated = true
 
-	return &operations.Result{
-		Allowed:  true,
-		PatchOps: patches,
-	}, nil
+	return patches, nil
+}
+
+// imagePullSecretPatch adds the Zarf image pull secret to the pull secrets of a pod spec, keeping the ones the pod
+// already has for other registries. It returns false when the Zarf secret is already listed.
+func imagePullSecretPatch(basePath string, secrets []corev1.LocalObjectReference) (operations.PatchOperation, bool) {
+	zarfSecret := corev1.LocalObjectReference{Name: config.ZarfImagePullSecretName}
+	if slices.Contains(secrets, zarfSecret) {
+		return operations.PatchOperation{}, false
+	}
+	if len(secrets) == 0 {
+		return operations.ReplacePatchOperation(basePath+"/spec/imagePullSecrets", []corev1.LocalObjectReference{zarfSecret}), true
+	}
+	return operations.AddPatchOperation(basePath+"/spec/imagePullSecrets/-", zarfSecret), true
+}
+
+// setOriginalImageAnnotation records the original image reference under key. An image that was already rewritten by an
+// earlier mutation, e.g. on the update of a cron job, keeps the original recorded back then.
+func setOriginalImageAnnotation(annotations map[string]string, key, image, replacement string) {
+	if _, ok := annotations[key]; ok && image == replacement {
+		return
+	}
+	annotations[key] = image
 }
 
 // mutatePodSubresource handles pod subresource mutation
-func mutatePodSubresource(ctx context.Context, r *v1.AdmissionRequest, cluster *cluster.Cluster) (*operations.Result, error) {
+func mutatePodSubresource(ctx context.Context, r *v1.AdmissionRequest, cluster *cluster.Cluster, stateLookup StateLookup, event *PodAuditEvent) (*operations.Result, error) {
 	switch res := r.SubResource; res {
 	case "ephemeralcontainers":
-		return mutateEphemeralContainers(ctx, r, cluster)
+		return mutateEphemeralContainers(ctx, r, cluster, stateLookup, event)
 	default:
 		// this likely won't be hit as the MutatingWebhookConfiguration would need to be modified - but this can help ensure they stay synchronized
 		return nil, fmt.Errorf("attempted mutation of unsupported subresource: %s", res)
 	}
 }
 
-func mutateEphemeralContainers(ctx context.Context, r *v1.AdmissionRequest, cluster *cluster.Cluster) (*operations.Result, error) {
+func mutateEphemeralContainers(ctx context.Context, r *v1.AdmissionRequest, cluster *cluster.Cluster, stateLookup StateLookup, event *PodAuditEvent) (*operations.Result, error) {
 	l := logger.From(ctx)
 	pod, err := parsePod(r.Object.Raw)
 	if err != nil {
 		return nil, fmt.Errorf(lang.AgentErrParsePod, err)
 	}
 
-	state, err := cluster.LoadState(ctx)
+	state, err := loadState(ctx, cluster, stateLookup, event)
 	if err != nil {
 		return nil, err
 	}
+	if state == nil {
+		return admitUnchanged(), nil
+	}
 	registryURL := state.RegistryInfo.Address
 
 	// Pods do not have a metadata.name at the time of admission if from a deployment so we don't log the name
@@ -193,16 +379,21 @@ func mutateEphemeralContainers(ctx context.Context, r *v1.AdmissionRequest, clus
 	// update the image host for each ephemeral container
 	for idx, container := range pod.Spec.EphemeralContainers {
 		path := fmt.Sprintf("/spec/ephemeralContainers/%d/image", idx)
-		replacement, err := transform.ImageTransformHost(registryURL, container.Image)
+		replacement, ok, err := transformImage(state, container.Image)
 		if err != nil {
 			return nil, err
 		}
+		if !ok {
+			continue
+		}
 		updatedAnnotations[getImageAnnotationKey(ctx, container.Name)] = container.Image
 		patches = append(patches, operations.ReplacePatchOperation(path, replacement))
+		event.Images = append(event.Images, ImageRewrite{Source: "ephemeralContainer", Name: container.Name, Original: container.Image, Rewritten: replacement})
 	}
 
 	// Add the annotations label patch
 	patches = append(patches, operations.ReplacePatchOperation("/metadata/annotations", updatedAnnotations))
+	event.Mutated = true
 
 	// Return the result of the subresource mutation
 	return &operations.Result{
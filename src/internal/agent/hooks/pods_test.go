@@ -40,7 +40,7 @@ func TestPodMutationWebhook(t *testing.T) {
 
 	s := &state.State{RegistryInfo: state.RegistryInfo{Address: "127.0.0.1:31999"}}
 	c := createTestClientWithZarfState(ctx, t, s)
-	handler := admission.NewHandler().Serve(ctx, NewPodMutationHook(ctx, c))
+	handler := admission.NewHandler().Serve(ctx, NewPodMutationHook(ctx, c, nil, false, StateLookup{}))
 
 	tests := []admissionTest{
 		{
@@ -86,6 +86,49 @@ func TestPodMutationWebhook(t *testing.T) {
 			},
 			code: http.StatusOK,
 		},
+		{
+			name: "pod with digest pinned images keeps the digests",
+			admissionReq: createPodAdmissionRequest(t, v1.Create, &corev1.Pod{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "nginx", Image: "nginx@sha256:6b86b273ff34fce19d6b804eff5a3f5747ada4eaa22f1d49c01e52ddb7875b4b"},
+						{Name: "tagged", Image: "nginx:1.27"},
+					},
+					InitContainers: []corev1.Container{{Name: "busybox", Image: "busybox:latest@sha256:3fbc632167424a6d997e74f52b878d7cc478225cffac6bc977eedfe51c7f4e79"}},
+				},
+			}, ""),
+			patch: []operations.PatchOperation{
+				operations.ReplacePatchOperation(
+					"/spec/imagePullSecrets",
+					[]corev1.LocalObjectReference{{Name: config.ZarfImagePullSecretName}},
+				),
+				operations.ReplacePatchOperation(
+					"/spec/initContainers/0/image",
+					"127.0.0.1:31999/library/busybox@sha256:3fbc632167424a6d997e74f52b878d7cc478225cffac6bc977eedfe51c7f4e79",
+				),
+				operations.ReplacePatchOperation(
+					"/spec/containers/0/image",
+					"127.0.0.1:31999/library/nginx@sha256:6b86b273ff34fce19d6b804eff5a3f5747ada4eaa22f1d49c01e52ddb7875b4b",
+				),
+				operations.ReplacePatchOperation(
+					"/spec/containers/1/image",
+					"127.0.0.1:31999/library/nginx:1.27-zarf-3793515731",
+				),
+				operations.ReplacePatchOperation(
+					"/metadata/labels",
+					map[string]string{"zarf-agent": "patched"},
+				),
+				operations.ReplacePatchOperation(
+					"/metadata/annotations",
+					map[string]string{
+						"zarf.dev/original-image-nginx":   "nginx@sha256:6b86b273ff34fce19d6b804eff5a3f5747ada4eaa22f1d49c01e52ddb7875b4b",
+						"zarf.dev/original-image-tagged":  "nginx:1.27",
+						"zarf.dev/original-image-busybox": "busybox:latest@sha256:3fbc632167424a6d997e74f52b878d7cc478225cffac6bc977eedfe51c7f4e79",
+					},
+				),
+			},
+			code: http.StatusOK,
+		},
 		{
 			name: "pod with zarf-agent patched label should not be mutated",
 			admissionReq: createPodAdmissionRequest(t, v1.Create, &corev1.Pod{
@@ -137,6 +180,73 @@ func TestPodMutationWebhook(t *testing.T) {
 			},
 			code: http.StatusOK,
 		},
+		{
+			name: "pod with zarf.dev/agent ignore annotation should not be mutated",
+			admissionReq: createPodAdmissionRequest(t, v1.Create, &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{"zarf.dev/agent": "ignore"},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "csi-driver", Image: "registry.k8s.io/sig-storage/csi-node-driver-registrar:v2.13.0"}},
+				},
+			}, ""),
+			patch: nil,
+			code:  http.StatusOK,
+		},
+		{
+			name: "ephemeral containers of a pod with zarf.dev/agent ignore annotation should not be mutated",
+			admissionReq: createPodAdmissionRequest(t, v1.Update, &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{"zarf.dev/agent": "ignore"},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "csi-driver", Image: "registry.k8s.io/sig-storage/csi-node-driver-registrar:v2.13.0"}},
+					EphemeralContainers: []corev1.EphemeralContainer{
+						{
+							EphemeralContainerCommon: corev1.EphemeralContainerCommon{
+								Name:  "debugger",
+								Image: "busybox",
+							},
+						},
+					},
+				},
+			}, "ephemeralcontainers"),
+			patch: nil,
+			code:  http.StatusOK,
+		},
+		{
+			name: "pod without zarf.dev/agent annotation should be mutated",
+			admissionReq: createPodAdmissionRequest(t, v1.Create, &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{"zarf.dev/other": "ignore"},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "nginx", Image: "nginx"}},
+				},
+			}, ""),
+			patch: []operations.PatchOperation{
+				operations.ReplacePatchOperation(
+					"/spec/imagePullSecrets",
+					[]corev1.LocalObjectReference{{Name: config.ZarfImagePullSecretName}},
+				),
+				operations.ReplacePatchOperation(
+					"/spec/containers/0/image",
+					"127.0.0.1:31999/library/nginx:latest-zarf-3793515731",
+				),
+				operations.ReplacePatchOperation(
+					"/metadata/labels",
+					map[string]string{"zarf-agent": "patched"},
+				),
+				operations.ReplacePatchOperation(
+					"/metadata/annotations",
+					map[string]string{
+						"zarf.dev/original-image-nginx": "nginx",
+						"zarf.dev/other":                "ignore",
+					},
+				),
+			},
+			code: http.StatusOK,
+		},
 		{
 			name: "pod with no labels should not error",
 			admissionReq: createPodAdmissionRequest(t, v1.Create, &corev1.Pod{
@@ -247,6 +357,228 @@ func TestPodMutationWebhook(t *testing.T) {
 		})
 	}
 }
+
+func TestPodMutationUsesCurrentRegistryAddress(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c := createTestClientWithZarfState(ctx, t, &state.State{RegistryInfo: state.RegistryInfo{Address: "127.0.0.1:31999"}})
+	handler := admission.NewHandler().Serve(ctx, NewPodMutationHook(ctx, c, nil, false, StateLookup{}))
+
+	podTest := func(registry string) admissionTest {
+		return admissionTest{
+			admissionReq: createPodAdmissionRequest(t, v1.Create, &corev1.Pod{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "nginx", Image: "nginx"}},
+				},
+			}, ""),
+			patch: []operations.PatchOperation{
+				operations.ReplacePatchOperation(
+					"/spec/imagePullSecrets",
+					[]corev1.LocalObjectReference{{Name: config.ZarfImagePullSecretName}},
+				),
+				operations.ReplacePatchOperation(
+					"/spec/containers/0/image",
+					registry+"/library/nginx:latest-zarf-3793515731",
+				),
+				operations.ReplacePatchOperation(
+					"/metadata/labels",
+					map[string]string{"zarf-agent": "patched"},
+				),
+				operations.ReplacePatchOperation(
+					"/metadata/annotations",
+					map[string]string{"zarf.dev/original-image-nginx": "nginx"},
+				),
+			},
+			code: http.StatusOK,
+		}
+	}
+
+	tt := podTest("127.0.0.1:31999")
+	verifyAdmission(t, sendAdmissionRequest(t, tt.admissionReq, handler), tt)
+
+	// Rotating the registry only updates the state secret, the agent is not restarted
+	require.NoError(t, c.SaveState(ctx, &state.State{RegistryInfo: state.RegistryInfo{Address: "10.0.0.5:5000"}}))
+
+	tt = podTest("10.0.0.5:5000")
+	verifyAdmission(t, sendAdmissionRequest(t, tt.admissionReq, handler), tt)
+}
+
+func TestPodMutationImageRewrites(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := &state.State{
+		RegistryInfo:          state.RegistryInfo{Address: "127.0.0.1:31999"},
+		AgentImageRewrites:    []state.AgentImageRewrite{{Source: "quay.io", Destination: "mirror.example.com/quay"}},
+		AgentImagePassthrough: []string{"*.dkr.ecr.us-east-1.amazonaws.com"},
+	}
+	c := createTestClientWithZarfState(ctx, t, s)
+	handler := admission.NewHandler().Serve(ctx, NewPodMutationHook(ctx, c, nil, false, StateLookup{}))
+
+	tt := admissionTest{
+		admissionReq: createPodAdmissionRequest(t, v1.Create, &corev1.Pod{
+			Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "ecr", Image: "123456789012.dkr.ecr.us-east-1.amazonaws.com/app:1.0"}},
+				Containers: []corev1.Container{
+					{Name: "quay", Image: "quay.io/prometheus/prometheus:v3.0.0"},
+					{Name: "nginx", Image: "nginx"},
+				},
+			},
+		}, ""),
+		patch: []operations.PatchOperation{
+			operations.ReplacePatchOperation(
+				"/spec/imagePullSecrets",
+				[]corev1.LocalObjectReference{{Name: config.ZarfImagePullSecretName}},
+			),
+			operations.ReplacePatchOperation(
+				"/spec/containers/0/image",
+				"mirror.example.com/quay/prometheus/prometheus:v3.0.0",
+			),
+			operations.ReplacePatchOperation(
+				"/spec/containers/1/image",
+				"127.0.0.1:31999/library/nginx:latest-zarf-3793515731",
+			),
+			operations.ReplacePatchOperation(
+				"/metadata/labels",
+				map[string]string{"zarf-agent": "patched"},
+			),
+			operations.ReplacePatchOperation(
+				"/metadata/annotations",
+				map[string]string{
+					"zarf.dev/original-image-quay":  "quay.io/prometheus/prometheus:v3.0.0",
+					"zarf.dev/original-image-nginx": "nginx",
+				},
+			),
+		},
+		code: http.StatusOK,
+	}
+	verifyAdmission(t, sendAdmissionRequest(t, tt.admissionReq, handler), tt)
+}
+
+func TestPodMutationImagePullSecrets(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := &state.State{
+		RegistryInfo:          state.RegistryInfo{Address: "127.0.0.1:31999"},
+		AgentImageRewrites:    []state.AgentImageRewrite{{Source: "quay.io", Destination: "mirror.example.com/quay"}},
+		AgentImagePassthrough: []string{"*.dkr.ecr.us-east-1.amazonaws.com"},
+	}
+	c := createTestClientWithZarfState(ctx, t, s)
+	handler := admission.NewHandler().Serve(ctx, NewPodMutationHook(ctx, c, nil, false, StateLookup{}))
+
+	tests := []admissionTest{
+		{
+			name: "zarf secret is appended to the pull secrets of the pod",
+			admissionReq: createPodAdmissionRequest(t, v1.Create, &corev1.Pod{
+				Spec: corev1.PodSpec{
+					Containers:       []corev1.Container{{Name: "nginx", Image: "nginx"}},
+					ImagePullSecrets: []corev1.LocalObjectReference{{Name: "ecr-credentials"}},
+				},
+			}, ""),
+			patch: []operations.PatchOperation{
+				operations.AddPatchOperation(
+					"/spec/imagePullSecrets/-",
+					corev1.LocalObjectReference{Name: config.ZarfImagePullSecretName},
+				),
+				operations.ReplacePatchOperation(
+					"/spec/containers/0/image",
+					"127.0.0.1:31999/library/nginx:latest-zarf-3793515731",
+				),
+				operations.ReplacePatchOperation(
+					"/metadata/labels",
+					map[string]string{"zarf-agent": "patched"},
+				),
+				operations.ReplacePatchOperation(
+					"/metadata/annotations",
+					map[string]string{"zarf.dev/original-image-nginx": "nginx"},
+				),
+			},
+			code: http.StatusOK,
+		},
+		{
+			name: "zarf secret is not added without an image from the zarf registry",
+			admissionReq: createPodAdmissionRequest(t, v1.Create, &corev1.Pod{
+				Spec: corev1.PodSpec{
+					InitContainers:   []corev1.Container{{Name: "ecr", Image: "123456789012.dkr.ecr.us-east-1.amazonaws.com/app:1.0"}},
+					Containers:       []corev1.Container{{Name: "quay", Image: "quay.io/prometheus/prometheus:v3.0.0"}},
+					ImagePullSecrets: []corev1.LocalObjectReference{{Name: "ecr-credentials"}},
+				},
+			}, ""),
+			patch: []operations.PatchOperation{
+				operations.ReplacePatchOperation(
+					"/spec/containers/0/image",
+					"mirror.example.com/quay/prometheus/prometheus:v3.0.0",
+				),
+				operations.ReplacePatchOperation(
+					"/metadata/labels",
+					map[string]string{"zarf-agent": "patched"},
+				),
+				operations.ReplacePatchOperation(
+					"/metadata/annotations",
+					map[string]string{"zarf.dev/original-image-quay": "quay.io/prometheus/prometheus:v3.0.0"},
+				),
+			},
+			code: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			rr := sendAdmissionRequest(t, tt.admissionReq, handler)
+			verifyAdmission(t, rr, tt)
+		})
+	}
+}
+
+func TestPodMutationDryRun(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c := createTestClientWithZarfState(ctx, t, &state.State{RegistryInfo: state.RegistryInfo{Address: "127.0.0.1:31999"}})
+	handler := admission.NewHandler().Serve(ctx, NewPodMutationHook(ctx, c, nil, true, StateLookup{}))
+
+	rr := sendAdmissionRequest(t, createPodAdmissionRequest(t, v1.Create, &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "nginx", Image: "nginx"}},
+		},
+	}, ""), handler)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var review v1.AdmissionReview
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&review))
+	require.True(t, review.Response.Allowed)
+	require.Empty(t, review.Response.Patch)
+	require.Nil(t, review.Response.PatchType)
+
+	var patches []operations.PatchOperation
+	require.NoError(t, json.Unmarshal([]byte(review.Response.AuditAnnotations[dryRunPatchAnnotation]), &patches))
+	require.Contains(t, patches, operations.ReplacePatchOperation("/spec/containers/0/image", "127.0.0.1:31999/library/nginx:latest-zarf-3793515731"))
+}
+
+func TestDryRunHook(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	patch := operations.ReplacePatchOperation("/spec/url", "http://zarf-gitea-http.zarf.svc.cluster.local:3000/zarf-git-user/podinfo")
+	hook := NewDryRunHook(ctx, operations.Hook{
+		Create: func(*v1.AdmissionRequest) (*operations.Result, error) {
+			return &operations.Result{Allowed: true, PatchOps: []operations.PatchOperation{patch}}, nil
+		},
+	})
+	require.Nil(t, hook.Update)
+
+	result, err := hook.Create(&v1.AdmissionRequest{Operation: v1.Create})
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+	require.Empty(t, result.PatchOps)
+	var patches []operations.PatchOperation
+	require.NoError(t, json.Unmarshal([]byte(result.AuditAnnotations[dryRunPatchAnnotation]), &patches))
+	require.Equal(t, []operations.PatchOperation{patch}, patches)
+}
+
 func TestGetImageAnnotationKey(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
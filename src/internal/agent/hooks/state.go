@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package hooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/pkg/logger"
+	"github.com/zarf-dev/zarf/src/pkg/state"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	stateLookupDelay     = 250 * time.Millisecond
+	stateLookupMaxDelay  = 2 * time.Second
+	stateLookupMaxJitter = 250 * time.Millisecond
+)
+
+// errStateNotFound is returned when the Zarf state does not exist by the end of a lookup.
+var errStateNotFound = errors.New("the Zarf state was not found")
+
+// StateLookup configures how the mutation hooks wait for the Zarf state. On a cold start of the cluster the agent can admit
+// pods before the state secret is created, the lookup retries with backoff and jitter until it exists.
+type StateLookup struct {
+	// Timeout bounds how long a lookup retries while the state is not found, 0 does not retry
+	Timeout time.Duration
+	// FailOpen admits objects unchanged when the state is still not found after Timeout instead of rejecting them
+	FailOpen bool
+}
+
+// load returns the Zarf state, retrying while the state secret is not found until the timeout of the lookup passes.
+// Errors other than a missing state are returned without retrying.
+func (s StateLookup) load(ctx context.Context, c *cluster.Cluster) (*state.State, error) {
+	l := logger.From(ctx)
+	lookupCtx := ctx
+	if s.Timeout > 0 {
+		var cancel context.CancelFunc
+		lookupCtx, cancel = context.WithTimeout(ctx, s.Timeout)
+		defer cancel()
+	}
+
+	var lastErr error
+	zarfState, err := retry.DoWithData(func() (*state.State, error) {
+		zarfState, err := c.LoadState(lookupCtx)
+		if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+			lastErr = err
+		}
+		return zarfState, err
+	},
+		retry.Context(lookupCtx),
+		retry.Attempts(0),
+		retry.Delay(stateLookupDelay),
+		retry.MaxDelay(stateLookupMaxDelay),
+		retry.MaxJitter(stateLookupMaxJitter),
+		retry.DelayType(retry.CombineDelay(retry.BackOffDelay, retry.RandomDelay)),
+		retry.RetryIf(func(err error) bool {
+			return s.Timeout > 0 && kerrors.IsNotFound(err)
+		}),
+		retry.OnRetry(func(n uint, err error) {
+			l.Debug("waiting for the Zarf state", "attempt", n+1, "error", err)
+		}),
+	)
+	if err == nil {
+		return zarfState, nil
+	}
+	if s.Timeout == 0 && kerrors.IsNotFound(err) {
+		return nil, fmt.Errorf("%w: %w", errStateNotFound, err)
+	}
+	// The lookup ran out of time while the state was missing, unless the request itself was cancelled
+	if kerrors.IsNotFound(err) || (kerrors.IsNotFound(lastErr) && ctx.Err() == nil) {
+		return nil, fmt.Errorf("%w within %s: %w", errStateNotFound, s.Timeout, lastErr)
+	}
+	return nil, err
+}
+
+// lookup returns the Zarf state for the mutation of the named object. A nil state without an error means that the state
+// was not found and the lookup fails open, the caller must then admit the object unchanged.
+func (s StateLookup) lookup(ctx context.Context, c *cluster.Cluster, kind, namespace, name string) (*state.State, error) {
+	zarfState, err := s.load(ctx, c)
+	if errors.Is(err, errStateNotFound) && s.FailOpen {
+		logger.From(ctx).Warn("admitting the object without mutating it", "kind", kind, "namespace", namespace, "name", name, "error", err)
+		return nil, nil
+	}
+	return zarfState, err
+}
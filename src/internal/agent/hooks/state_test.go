@@ -0,0 +1,181 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	flux "github.com/fluxcd/source-controller/api/v1"
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/internal/agent/http/admission"
+	"github.com/zarf-dev/zarf/src/internal/agent/operations"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/pkg/state"
+	v1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// createTestClientWithMissingZarfState returns a client whose Zarf state is not found for the first misses lookups.
+func createTestClientWithMissingZarfState(ctx context.Context, t *testing.T, misses int32) (*cluster.Cluster, *atomic.Int32) {
+	t.Helper()
+	c := createTestClientWithZarfState(ctx, t, &state.State{RegistryInfo: state.RegistryInfo{Address: "127.0.0.1:31999"}})
+	var lookups atomic.Int32
+	c.Clientset.(*fake.Clientset).PrependReactor("get", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.(k8stesting.GetAction).GetName() != state.ZarfStateSecretName {
+			return false, nil, nil
+		}
+		if lookups.Add(1) <= misses {
+			return true, nil, kerrors.NewNotFound(corev1.Resource("secrets"), state.ZarfStateSecretName)
+		}
+		return false, nil, nil
+	})
+	return c, &lookups
+}
+
+func TestStateLookup(t *testing.T) {
+	t.Parallel()
+
+	t.Run("retries until the state exists", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		c, lookups := createTestClientWithMissingZarfState(ctx, t, 2)
+		s, err := StateLookup{Timeout: 10 * time.Second}.load(ctx, c)
+		require.NoError(t, err)
+		require.Equal(t, "127.0.0.1:31999", s.RegistryInfo.Address)
+		require.Equal(t, int32(3), lookups.Load())
+	})
+
+	t.Run("fails once the timeout passes", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		c, _ := createTestClientWithMissingZarfState(ctx, t, 1000)
+		_, err := StateLookup{Timeout: 500 * time.Millisecond}.load(ctx, c)
+		require.ErrorIs(t, err, errStateNotFound)
+		require.True(t, kerrors.IsNotFound(err))
+		require.ErrorContains(t, err, "the Zarf state was not found within 500ms")
+	})
+
+	t.Run("does not retry without a timeout", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		c, lookups := createTestClientWithMissingZarfState(ctx, t, 1)
+		_, err := StateLookup{}.load(ctx, c)
+		require.ErrorIs(t, err, errStateNotFound)
+		require.Equal(t, int32(1), lookups.Load())
+	})
+
+	t.Run("does not retry other errors", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		c := &cluster.Cluster{Clientset: fake.NewClientset()}
+		var lookups atomic.Int32
+		c.Clientset.(*fake.Clientset).PrependReactor("get", "secrets", func(k8stesting.Action) (bool, runtime.Object, error) {
+			lookups.Add(1)
+			return true, nil, kerrors.NewForbidden(corev1.Resource("secrets"), state.ZarfStateSecretName, nil)
+		})
+		_, err := StateLookup{Timeout: 10 * time.Second}.load(ctx, c)
+		require.True(t, kerrors.IsForbidden(err))
+		require.NotErrorIs(t, err, errStateNotFound)
+		require.Equal(t, int32(1), lookups.Load())
+	})
+}
+
+func TestPodMutationTransientlyMissingState(t *testing.T) {
+	t.Parallel()
+
+	pod := &corev1.Pod{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "nginx", Image: "nginx"}},
+		},
+	}
+
+	t.Run("mutates the pod once the state exists", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		c, _ := createTestClientWithMissingZarfState(ctx, t, 2)
+		handler := admission.NewHandler().Serve(ctx, NewPodMutationHook(ctx, c, nil, false, StateLookup{Timeout: 10 * time.Second}))
+
+		rr := sendAdmissionRequest(t, createPodAdmissionRequest(t, v1.Create, pod, ""), handler)
+		require.Equal(t, http.StatusOK, rr.Code)
+		var review v1.AdmissionReview
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&review))
+		require.True(t, review.Response.Allowed)
+		var patches []operations.PatchOperation
+		require.NoError(t, json.Unmarshal(review.Response.Patch, &patches))
+		require.Contains(t, patches, operations.ReplacePatchOperation("/spec/containers/0/image", "127.0.0.1:31999/library/nginx:latest-zarf-3793515731"))
+	})
+
+	t.Run("rejects the pod after the timeout", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		c, _ := createTestClientWithMissingZarfState(ctx, t, 1000)
+		handler := admission.NewHandler().Serve(ctx, NewPodMutationHook(ctx, c, nil, false, StateLookup{Timeout: 500 * time.Millisecond}))
+
+		rr := sendAdmissionRequest(t, createPodAdmissionRequest(t, v1.Create, pod, ""), handler)
+		verifyAdmission(t, rr, admissionTest{
+			code:        http.StatusInternalServerError,
+			errContains: "the Zarf state was not found within 500ms",
+		})
+	})
+
+	t.Run("admits the pod unchanged when failing open", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		c, _ := createTestClientWithMissingZarfState(ctx, t, 1000)
+		handler := admission.NewHandler().Serve(ctx, NewPodMutationHook(ctx, c, nil, false, StateLookup{Timeout: 500 * time.Millisecond, FailOpen: true}))
+
+		rr := sendAdmissionRequest(t, createPodAdmissionRequest(t, v1.Create, pod, ""), handler)
+		require.Equal(t, http.StatusOK, rr.Code)
+		var review v1.AdmissionReview
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&review))
+		require.True(t, review.Response.Allowed)
+		require.Empty(t, review.Response.Patch)
+	})
+}
+
+func TestGitRepositoryMutationTransientlyMissingState(t *testing.T) {
+	t.Parallel()
+
+	repo := &flux.GitRepository{
+		ObjectMeta: metav1.ObjectMeta{Name: "podinfo"},
+		Spec:       flux.GitRepositorySpec{URL: "https://github.com/stefanprodan/podinfo.git"},
+	}
+
+	t.Run("rejects the repository after the timeout", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		c, _ := createTestClientWithMissingZarfState(ctx, t, 1000)
+		handler := admission.NewHandler().Serve(ctx, NewGitRepositoryMutationHook(ctx, c, StateLookup{Timeout: 500 * time.Millisecond}))
+
+		rr := sendAdmissionRequest(t, createFluxGitRepoAdmissionRequest(t, v1.Create, repo), handler)
+		verifyAdmission(t, rr, admissionTest{
+			code:        http.StatusInternalServerError,
+			errContains: "the Zarf state was not found within 500ms",
+		})
+	})
+
+	t.Run("admits the repository unchanged when failing open", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		c, _ := createTestClientWithMissingZarfState(ctx, t, 1000)
+		handler := admission.NewHandler().Serve(ctx, NewGitRepositoryMutationHook(ctx, c, StateLookup{Timeout: 500 * time.Millisecond, FailOpen: true}))
+
+		rr := sendAdmissionRequest(t, createFluxGitRepoAdmissionRequest(t, v1.Create, repo), handler)
+		require.Equal(t, http.StatusOK, rr.Code)
+		var review v1.AdmissionReview
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&review))
+		require.True(t, review.Response.Allowed)
+		require.Empty(t, review.Response.Patch)
+	})
+}
@@ -97,6 +97,8 @@ func (h *Handler) Serve(ctx context.Context, hook operations.Hook) http.HandlerF
 				UID:     review.Request.UID,
 				Allowed: result.Allowed,
 				Result:  &metav1.Status{Message: result.Msg},
+				// Audit annotations are recorded by the API server in the audit log entry of the request
+				AuditAnnotations: result.AuditAnnotations,
 			},
 		}
 
@@ -16,6 +16,8 @@ type Result struct {
 	Allowed  bool
 	Msg      string
 	PatchOps []PatchOperation
+	// AuditAnnotations are added to the audit log entry of the request by the API server
+	AuditAnnotations map[string]string
 }
 
 // AdmitFunc defines how to process an admission request.
@@ -17,6 +17,7 @@ import (
 	"github.com/zarf-dev/zarf/src/internal/agent/hooks"
 	agentHttp "github.com/zarf-dev/zarf/src/internal/agent/http"
 	"github.com/zarf-dev/zarf/src/internal/agent/http/admission"
+	"github.com/zarf-dev/zarf/src/internal/agent/operations"
 	"github.com/zarf-dev/zarf/src/pkg/cluster"
 	"github.com/zarf-dev/zarf/src/pkg/logger"
 )
@@ -31,22 +32,61 @@ const (
 	tlsKey   = "/etc/certs/tls.key"
 )
 
+// DefaultStateTimeout is how long the pod, job and cron job hooks wait for the Zarf state to be created by default.
+const DefaultStateTimeout = 10 * time.Second
+
+// WebhookOptions configures the Zarf agent mutating webhook.
+type WebhookOptions struct {
+	// AuditURL is the endpoint that pod, job and cron job mutation decisions are posted to, auditing is disabled when empty
+	AuditURL string
+	// AuditStrict rejects pods, jobs and cron jobs whose mutation decision could not be recorded to AuditURL
+	AuditStrict bool
+	// DryRun reports the mutations of every hook in the admission response audit annotations and logs instead of applying them
+	DryRun bool
+	// StateTimeout is how long the mutation hooks retry while the Zarf state does not exist yet, 0 does not retry
+	StateTimeout time.Duration
+	// StateFailOpen admits objects unchanged when the Zarf state is still missing after StateTimeout instead of rejecting them
+	StateFailOpen bool
+}
+
 // StartWebhook launches the Zarf agent mutating webhook in the cluster.
-func StartWebhook(ctx context.Context, cluster *cluster.Cluster) error {
+func StartWebhook(ctx context.Context, cluster *cluster.Cluster, opts WebhookOptions) error {
+	var auditSink *hooks.AuditSink
+	if opts.AuditURL != "" {
+		auditSink = hooks.NewAuditSink(opts.AuditURL, opts.AuditStrict)
+		logger.From(ctx).Info("recording pod, job and cron job mutations to the audit sink", "url", opts.AuditURL, "strict", opts.AuditStrict)
+	}
+	if opts.DryRun {
+		logger.From(ctx).Warn("mutations are only reported, every object is admitted unchanged")
+	}
+	// The pod, job and cron job hooks handle dry run themselves so that it is recorded to the audit sink
+	withDryRun := func(hook operations.Hook) operations.Hook {
+		if !opts.DryRun {
+			return hook
+		}
+		return hooks.NewDryRunHook(ctx, hook)
+	}
+
 	// Routers
 	admissionHandler := admission.NewHandler()
-	podsMutation := hooks.NewPodMutationHook(ctx, cluster)
-	fluxGitRepositoryMutation := hooks.NewGitRepositoryMutationHook(ctx, cluster)
-	argocdApplicationMutation := hooks.NewApplicationMutationHook(ctx, cluster)
-	argocdApplicationSetMutation := hooks.NewApplicationSetMutationHook(ctx, cluster)
-	argocdAppProjectMutation := hooks.NewAppProjectMutationHook(ctx, cluster)
-	argocdRepositoryMutation := hooks.NewRepositorySecretMutationHook(ctx, cluster)
-	fluxHelmRepositoryMutation := hooks.NewHelmRepositoryMutationHook(ctx, cluster)
-	fluxOCIRepositoryMutation := hooks.NewOCIRepositoryMutationHook(ctx, cluster)
+	stateLookup := hooks.StateLookup{Timeout: opts.StateTimeout, FailOpen: opts.StateFailOpen}
+	podsMutation := hooks.NewPodMutationHook(ctx, cluster, auditSink, opts.DryRun, stateLookup)
+	jobsMutation := hooks.NewJobMutationHook(ctx, cluster, auditSink, opts.DryRun, stateLookup)
+	cronJobsMutation := hooks.NewCronJobMutationHook(ctx, cluster, auditSink, opts.DryRun, stateLookup)
+	fluxGitRepositoryMutation := withDryRun(hooks.NewGitRepositoryMutationHook(ctx, cluster, stateLookup))
+	argocdApplicationMutation := withDryRun(hooks.NewApplicationMutationHook(ctx, cluster, stateLookup))
+	argocdApplicationSetMutation := withDryRun(hooks.NewApplicationSetMutationHook(ctx, cluster, stateLookup))
+	argocdAppProjectMutation := withDryRun(hooks.NewAppProjectMutationHook(ctx, cluster, stateLookup))
+	argocdRepositoryMutation := withDryRun(hooks.NewRepositorySecretMutationHook(ctx, cluster, stateLookup))
+	fluxHelmRepositoryMutation := withDryRun(hooks.NewHelmRepositoryMutationHook(ctx, cluster, stateLookup))
+	fluxOCIRepositoryMutation := withDryRun(hooks.NewOCIRepositoryMutationHook(ctx, cluster, stateLookup))
+	customResourceMutation := withDryRun(hooks.NewCustomResourceMutationHook(ctx, cluster, stateLookup))
 
 	// Routers
 	mux := http.NewServeMux()
 	mux.Handle("/mutate/pod", admissionHandler.Serve(ctx, podsMutation))
+	mux.Handle("/mutate/job", admissionHandler.Serve(ctx, jobsMutation))
+	mux.Handle("/mutate/cronjob", admissionHandler.Serve(ctx, cronJobsMutation))
 	mux.Handle("/mutate/flux-gitrepository", admissionHandler.Serve(ctx, fluxGitRepositoryMutation))
 	mux.Handle("/mutate/flux-helmrepository", admissionHandler.Serve(ctx, fluxHelmRepositoryMutation))
 	mux.Handle("/mutate/flux-ocirepository", admissionHandler.Serve(ctx, fluxOCIRepositoryMutation))
@@ -54,6 +94,7 @@ func StartWebhook(ctx context.Context, cluster *cluster.Cluster) error {
 	mux.Handle("/mutate/argocd-applicationset", admissionHandler.Serve(ctx, argocdApplicationSetMutation))
 	mux.Handle("/mutate/argocd-appproject", admissionHandler.Serve(ctx, argocdAppProjectMutation))
 	mux.Handle("/mutate/argocd-repository", admissionHandler.Serve(ctx, argocdRepositoryMutation))
+	mux.Handle("/mutate/custom-resource", admissionHandler.Serve(ctx, customResourceMutation))
 
 	return startServer(ctx, httpPort, mux)
 }
@@ -7,16 +7,26 @@ package v1alpha1
 import (
 	"errors"
 	"fmt"
+	"maps"
+	"path"
+	"slices"
 	"strings"
 
+	"github.com/Masterminds/semver/v3"
+	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/pkg/utils"
+	"github.com/zarf-dev/zarf/src/pkg/variables"
 	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/util/jsonpath"
 )
 
 const (
 	// ZarfMaxChartNameLength limits helm chart name size to account for K8s/helm limits and zarf prefix
-	ZarfMaxChartNameLength   = 40
-	errChartReleaseNameEmpty = "release name empty, unable to fallback to chart name"
+	ZarfMaxChartNameLength = 40
+	// ZarfMaxFieldManagerLength is the Kubernetes limit on the length of a field manager
+	ZarfMaxFieldManagerLength = 128
+	errChartReleaseNameEmpty  = "release name empty, unable to fallback to chart name"
 )
 
 // Package errors found during validation.
@@ -30,6 +40,14 @@ const (
 	PkgValidateErrComponentNameNotUnique  = "component name %q is not unique"
 	PkgValidateErrComponentReqDefault     = "component %q cannot be both required and default"
 	PkgValidateErrComponentReqGrouped     = "component %q cannot be both required and grouped"
+	PkgValidateErrComponentDependsOn      = "component %q depends on %q which is not defined in the package"
+	PkgValidateErrComponentDependsOnCycle = "components depend on each other in a cycle: %s"
+	PkgValidateErrComponentConflictsWith  = "component %q conflicts with %q which is not defined in the package"
+	PkgValidateErrComponentConflictsSelf  = "component %q can not conflict with itself"
+	PkgValidateErrComponentConflictsReq   = "components %q and %q are both required but conflict with each other"
+	PkgValidateErrComponentRetryPolicy    = "component %q has an invalid retry policy, maxAttempts and backoffSeconds can not be negative"
+	PkgValidateErrComponentExpression     = "component %q has an invalid only.expression: %w"
+	PkgValidateErrComponentExpressionVar  = "component %q only.expression refers to %q which is not a package variable"
 	PkgValidateErrChartNameNotUnique      = "chart name %q is not unique"
 	PkgValidateErrChart                   = "invalid chart definition: %w"
 	PkgValidateErrManifestNameNotUnique   = "manifest name %q is not unique"
@@ -38,16 +56,44 @@ const (
 	PkgValidateErrGroupOneComponent       = "group %q only has one component (%q)"
 	PkgValidateErrAction                  = "invalid action: %w"
 	PkgValidateErrActionCmdWait           = "action %q cannot be both a command and wait action"
-	PkgValidateErrActionClusterNetwork    = "a single wait action must contain only one of cluster or network"
+	PkgValidateErrActionClusterNetwork    = "a single wait action must contain only one of cluster, network or variable"
+	PkgValidateErrActionNetworkHTTP       = "wait action for %s://%s can only set method, headers or insecureSkipVerify for the http and https protocols"
+	PkgValidateErrActionJSONVariableCmd   = "only cmd actions can set variables from JSON"
+	PkgValidateErrActionJSONPathEmpty     = "variable %s must have a JSONPath"
+	PkgValidateErrActionJSONPath          = "variable %s has an invalid JSONPath %q: %w"
+	PkgValidateErrActionEnvFileCmd        = "only cmd actions can set an env file"
+	PkgValidateErrActionMinReady          = "wait for %s/%s can only set a positive minReady with a label selector name and a jsonpath condition"
+	PkgValidateErrActionExitCodesCmd      = "only cmd actions can set allowed exit codes"
+	PkgValidateErrActionExitCode          = "allowed exit code %d must be between 0 and 255"
 	PkgValidateErrChartName               = "chart %q exceed the maximum length of %d characters"
 	PkgValidateErrChartNamespaceMissing   = "chart %q must include a namespace"
 	PkgValidateErrChartURLOrPath          = "chart %q must have either a url or localPath"
 	PkgValidateErrChartVersion            = "chart %q must include a chart version"
+	PkgValidateErrChartKubeVersion        = "chart %q has an invalid kube version %q: %w"
+	PkgValidateErrChartCredentials        = "chart %q must set both username and passwordEnv"
+	PkgValidateErrChartCredentialsURL     = "chart %q can only set username and passwordEnv when pulled from a url"
+	PkgValidateErrChartPostRenderPatch    = "chart %q post-render patch %q must be a local file"
 	PkgValidateErrManifestFileOrKustomize = "manifest %q must have at least one file or kustomization"
 	PkgValidateErrManifestNameLength      = "manifest %q exceed the maximum length of %d characters"
+	PkgValidateErrManifestFieldManager    = "manifest %q field manager exceeds the maximum length of %d characters"
+	PkgValidateErrManifestVariableNoTmpl  = "manifest %q can only set variables when template is true"
+	PkgValidateErrManifestVariablePath    = "manifest %q variable %s must have a path"
 	PkgValidateErrVariable                = "invalid package variable: %w"
 	PkgValidateErrNoComponents            = "package does not contain any compatible components"
 	PkgValidateErrActionTemplateOnCreate  = "templating is not supported in onCreate actions"
+	PkgValidateErrActionConfirmOnCreate   = "confirm is not supported in onCreate actions"
+	PkgValidateErrMetadataLabelKey        = "invalid metadata label key %q: %s"
+	PkgValidateErrMetadataLabelValue      = "invalid metadata label value %q for key %q: %s"
+	PkgValidateErrAnnotationKey           = "%s has an invalid annotation key %q: %s"
+	PkgValidateErrNamespaceLabels         = "%s can only set namespaceLabels when createNamespace is true"
+	PkgValidateErrFileShasum              = "file %q has an invalid shasum: %w"
+	PkgValidateErrFileDownload            = "file %q has an invalid download policy, maxRetries and timeoutSeconds can not be negative"
+	PkgValidateErrFileMode                = "file %q has an invalid mode: %w"
+	PkgValidateErrFileExtractPaths        = "file %q can not set both extractPath and extractPaths"
+	PkgValidateErrFileExtractPathsShasum  = "file %q can not set a shasum with extractPaths, the extracted paths are a folder"
+	PkgValidateErrFileExtractPattern      = "file %q has an invalid extractPaths pattern %q: %w"
+	PkgValidateErrDataCompression         = "data injection %q has an unsupported compression algorithm %q"
+	PkgValidateErrDataCompressAlias       = "data injection %q can not set compress together with compression algorithm %q, compress is an alias of gzip"
 )
 
 // ValidatePackage runs all validation checks on the package.
@@ -64,6 +110,11 @@ func ValidatePackage(pkg v1alpha1.ZarfPackage) error {
 			err = errors.Join(err, fmt.Errorf(PkgValidateErrConstant, varErr))
 		}
 	}
+	err = errors.Join(err, validateMetadataLabels(pkg.Metadata.Labels))
+	packageVariables := make(map[string]bool)
+	for _, variable := range pkg.Variables {
+		packageVariables[strings.ToUpper(variable.Name)] = true
+	}
 	uniqueComponentNames := make(map[string]bool)
 	groupDefault := make(map[string]string)
 	groupedComponents := make(map[string][]string)
@@ -119,6 +170,59 @@ func ValidatePackage(pkg v1alpha1.ZarfPackage) error {
 				err = errors.Join(err, fmt.Errorf(PkgValidateErrManifest, manifestErr))
 			}
 		}
+		for _, file := range component.Files {
+			if file.MaxRetries < 0 || file.TimeoutSeconds < 0 {
+				err = errors.Join(err, fmt.Errorf(PkgValidateErrFileDownload, file.Source))
+			}
+			if _, _, modeErr := file.GetMode(); modeErr != nil {
+				err = errors.Join(err, fmt.Errorf(PkgValidateErrFileMode, file.Source, modeErr))
+			}
+			if len(file.ExtractPaths) > 0 {
+				if file.ExtractPath != "" {
+					err = errors.Join(err, fmt.Errorf(PkgValidateErrFileExtractPaths, file.Source))
+				}
+				if file.Shasum != "" {
+					err = errors.Join(err, fmt.Errorf(PkgValidateErrFileExtractPathsShasum, file.Source))
+				}
+				for _, pattern := range file.ExtractPaths {
+					if _, patternErr := path.Match(pattern, ""); patternErr != nil {
+						err = errors.Join(err, fmt.Errorf(PkgValidateErrFileExtractPattern, file.Source, pattern, patternErr))
+					}
+				}
+			}
+			if file.Shasum == "" {
+				continue
+			}
+			if _, _, _, shaErr := utils.ParseChecksum(file.Shasum); shaErr != nil {
+				err = errors.Join(err, fmt.Errorf(PkgValidateErrFileShasum, file.Source, shaErr))
+			}
+		}
+		for _, data := range component.DataInjections {
+			switch data.CompressionAlgorithm {
+			case "", v1alpha1.DataInjectionCompressionGzip, v1alpha1.DataInjectionCompressionZstd, v1alpha1.DataInjectionCompressionNone:
+			default:
+				err = errors.Join(err, fmt.Errorf(PkgValidateErrDataCompression, data.Source, data.CompressionAlgorithm))
+			}
+			if data.Compress && data.CompressionAlgorithm != "" && data.CompressionAlgorithm != v1alpha1.DataInjectionCompressionGzip {
+				err = errors.Join(err, fmt.Errorf(PkgValidateErrDataCompressAlias, data.Source, data.CompressionAlgorithm))
+			}
+		}
+		err = errors.Join(err, validateAnnotationKeys(fmt.Sprintf("component %q", component.Name), component.ResourceAnnotations))
+		if policy := component.RetryPolicy; policy != nil && (policy.MaxAttempts < 0 || policy.BackoffSeconds < 0) {
+			err = errors.Join(err, fmt.Errorf(PkgValidateErrComponentRetryPolicy, component.Name))
+		}
+		if component.Only.Expression != "" {
+			expr, exprErr := variables.ParseExpression(component.Only.Expression)
+			if exprErr != nil {
+				err = errors.Join(err, fmt.Errorf(PkgValidateErrComponentExpression, component.Name, exprErr))
+			} else {
+				for _, name := range expr.Variables() {
+					if !packageVariables[name] {
+						err = errors.Join(err, fmt.Errorf(PkgValidateErrComponentExpressionVar, component.Name, name))
+					}
+				}
+			}
+		}
 		if actionsErr := validateActions(component.Actions); actionsErr != nil {
 			err = errors.Join(err, fmt.Errorf("%q: %w", component.Name, actionsErr))
 		}
@@ -133,6 +237,34 @@ func ValidatePackage(pkg v1alpha1.ZarfPackage) error {
 			groupedComponents[component.DeprecatedGroup] = append(groupedComponents[component.DeprecatedGroup], component.Name)
 		}
 	}
+	// dependencies and conflicts can refer to components defined after the component so they are checked once all names are known
+	for _, component := range pkg.Components {
+		for _, dep := range component.DependsOn {
+			if !uniqueComponentNames[dep] {
+				err = errors.Join(err, fmt.Errorf(PkgValidateErrComponentDependsOn, component.Name, dep))
+			}
+		}
+	}
+	for _, cycle := range findDependencyCycles(pkg.Components) {
+		err = errors.Join(err, fmt.Errorf(PkgValidateErrComponentDependsOnCycle, strings.Join(cycle, " -> ")))
+	}
+	requiredComponents := map[string]bool{}
+	for _, component := range pkg.Components {
+		requiredComponents[component.Name] = component.IsRequired()
+	}
+	for _, component := range pkg.Components {
+		for _, conflict := range component.ConflictsWith {
+			required, ok := requiredComponents[conflict]
+			switch {
+			case conflict == component.Name:
+				err = errors.Join(err, fmt.Errorf(PkgValidateErrComponentConflictsSelf, component.Name))
+			case !ok:
+				err = errors.Join(err, fmt.Errorf(PkgValidateErrComponentConflictsWith, component.Name, conflict))
+			case required && component.IsRequired():
+				err = errors.Join(err, fmt.Errorf(PkgValidateErrComponentConflictsReq, component.Name, conflict))
+			}
+		}
+	}
 	for groupKey, componentNames := range groupedComponents {
 		if len(componentNames) == 1 {
 			err = errors.Join(err, fmt.Errorf(PkgValidateErrGroupOneComponent, groupKey, componentNames[0]))
@@ -142,6 +274,80 @@ func ValidatePackage(pkg v1alpha1.ZarfPackage) error {
 	return err
 }
 
+// findDependencyCycles returns the cycles in the dependsOn of the components, each starting and ending with the same component.
+func findDependencyCycles(components []v1alpha1.ZarfComponent) [][]string {
+	dependsOn := map[string][]string{}
+	for _, component := range components {
+		dependsOn[component.Name] = component.DependsOn
+	}
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := map[string]int{}
+	path := []string{}
+	cycles := [][]string{}
+	var visit func(name string)
+	visit = func(name string) {
+		state[name] = visiting
+		path = append(path, name)
+		for _, dep := range dependsOn[name] {
+			if _, ok := dependsOn[dep]; !ok {
+				continue
+			}
+			switch state[dep] {
+			case visiting:
+				cycle := slices.Clone(path[slices.Index(path, dep):])
+				cycles = append(cycles, append(cycle, dep))
+			case 0:
+				visit(dep)
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = visited
+	}
+	for _, component := range components {
+		if state[component.Name] == 0 {
+			visit(component.Name)
+		}
+	}
+	return cycles
+}
+
+// validateMetadataLabels validates package labels against the Kubernetes label syntax.
+func validateMetadataLabels(labels map[string]string) error {
+	var err error
+	for _, key := range slices.Sorted(maps.Keys(labels)) {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			err = errors.Join(err, fmt.Errorf(PkgValidateErrMetadataLabelKey, key, strings.Join(errs, "; ")))
+		}
+		if errs := validation.IsValidLabelValue(labels[key]); len(errs) > 0 {
+			err = errors.Join(err, fmt.Errorf(PkgValidateErrMetadataLabelValue, labels[key], key, strings.Join(errs, "; ")))
+		}
+	}
+	return err
+}
+
+// validateNamespaceLabels validates namespace labels against the Kubernetes label syntax, they are only applied when
+// Zarf creates the namespace.
+func validateNamespaceLabels(owner string, createNamespace bool, labels map[string]string) error {
+	if len(labels) > 0 && !createNamespace {
+		return fmt.Errorf(PkgValidateErrNamespaceLabels, owner)
+	}
+	return validateMetadataLabels(labels)
+}
+
+// validateAnnotationKeys validates annotation keys against the Kubernetes annotation key syntax.
+func validateAnnotationKeys(owner string, annotations map[string]string) error {
+	var err error
+	for _, key := range slices.Sorted(maps.Keys(annotations)) {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			err = errors.Join(err, fmt.Errorf(PkgValidateErrAnnotationKey, owner, key, strings.Join(errs, "; ")))
+		}
+	}
+	return err
+}
+
 // validateActions validates the actions of a component.
 func validateActions(a v1alpha1.ZarfComponentActions) error {
 	var err error
@@ -156,6 +362,10 @@ func validateActions(a v1alpha1.ZarfComponentActions) error {
 		err = errors.Join(err, errors.New(PkgValidateErrActionTemplateOnCreate))
 	}
 
+	if hasConfirm(a.OnCreate) {
+		err = errors.Join(err, errors.New(PkgValidateErrActionConfirmOnCreate))
+	}
+
 	err = errors.Join(err, validateActionSet(a.OnDeploy))
 
 	if hasSetVariables(a.OnRemove) {
@@ -171,7 +381,21 @@ func validateActions(a v1alpha1.ZarfComponentActions) error {
 func hasSetVariables(as v1alpha1.ZarfComponentActionSet) bool {
 	check := func(actions []v1alpha1.ZarfComponentAction) bool {
 		for _, action := range actions {
-			if len(action.SetVariables) > 0 {
+			if len(action.SetVariables) > 0 || len(action.SetVariablesFromJSON) > 0 {
+				return true
+			}
+		}
+		return false
+	}
+
+	return check(as.Before) || check(as.After) || check(as.OnSuccess) || check(as.OnFailure)
+}
+
+// hasConfirm returns true if any of the actions require confirmation.
+func hasConfirm(as v1alpha1.ZarfComponentActionSet) bool {
+	check := func(actions []v1alpha1.ZarfComponentAction) bool {
+		for _, action := range actions {
+			if action.Confirm {
 				return true
 			}
 		}
@@ -234,14 +458,63 @@ func validateAction(action v1alpha1.ZarfComponentAction) error {
 			err = errors.Join(err, fmt.Errorf(PkgValidateErrActionCmdWait, action.Cmd))
 		}
 
-		// Validate only cluster or network, not both
-		if action.Wait.Cluster != nil && action.Wait.Network != nil {
+		// Validate exactly one of cluster, network or variable
+		waitCount := 0
+		if action.Wait.Cluster != nil || len(action.Wait.Clusters) > 0 {
+			waitCount++
+		}
+		if action.Wait.Network != nil {
+			waitCount++
+		}
+		if action.Wait.Variable != nil {
+			waitCount++
+		}
+		if waitCount != 1 {
 			err = errors.Join(err, errors.New(PkgValidateErrActionClusterNetwork))
 		}
 
-		// Validate at least one of cluster or network
-		if action.Wait.Cluster == nil && action.Wait.Network == nil {
-			err = errors.Join(err, errors.New(PkgValidateErrActionClusterNetwork))
+		for _, cluster := range action.Wait.ClusterConditions() {
+			if cluster.MinReady == 0 {
+				continue
+			}
+			condition := strings.ReplaceAll(cluster.Condition, "'", "")
+			isJSONPath := strings.HasPrefix(condition, "{") && strings.Contains(condition, "}=")
+			if cluster.MinReady < 0 || !strings.ContainsRune(cluster.Name, '=') || !isJSONPath {
+				err = errors.Join(err, fmt.Errorf(PkgValidateErrActionMinReady, cluster.Kind, cluster.Name))
+			}
+		}
+
+		if network := action.Wait.Network; network != nil {
+			isHTTP := strings.HasPrefix(strings.ToLower(network.Protocol), "http")
+			if !isHTTP && (network.Method != "" || len(network.Headers) > 0 || network.InsecureSkipVerify) {
+				err = errors.Join(err, fmt.Errorf(PkgValidateErrActionNetworkHTTP, network.Protocol, network.Address))
+			}
+		}
+	}
+
+	if action.EnvFile != "" && action.Cmd == "" {
+		err = errors.Join(err, errors.New(PkgValidateErrActionEnvFileCmd))
+	}
+
+	if len(action.AllowedExitCodes) > 0 && action.Cmd == "" {
+		err = errors.Join(err, errors.New(PkgValidateErrActionExitCodesCmd))
+	}
+	for _, code := range action.AllowedExitCodes {
+		if code < 0 || code > 255 {
+			err = errors.Join(err, fmt.Errorf(PkgValidateErrActionExitCode, code))
+		}
+	}
+
+	if len(action.SetVariablesFromJSON) > 0 && action.Cmd == "" {
+		err = errors.Join(err, errors.New(PkgValidateErrActionJSONVariableCmd))
+	}
+	for _, v := range action.SetVariablesFromJSON {
+		if v.Path == "" {
+			err = errors.Join(err, fmt.Errorf(PkgValidateErrActionJSONPathEmpty, v.Name))
+			continue
+		}
+		if pathErr := jsonpath.New(v.Name).Parse(v.JSONPath()); pathErr != nil {
+			err = errors.Join(err, fmt.Errorf(PkgValidateErrActionJSONPath, v.Name, v.Path, pathErr))
 		}
 	}
 
@@ -295,10 +568,33 @@ func validateChart(chart v1alpha1.ZarfChart) error {
 		err = errors.Join(err, fmt.Errorf(PkgValidateErrChartVersion, chart.Name))
 	}
 
+	if (chart.Username == "") != (chart.PasswordEnv == "") {
+		err = errors.Join(err, fmt.Errorf(PkgValidateErrChartCredentials, chart.Name))
+	}
+
+	if (chart.Username != "" || chart.PasswordEnv != "") && chart.URL == "" {
+		err = errors.Join(err, fmt.Errorf(PkgValidateErrChartCredentialsURL, chart.Name))
+	}
+
+	if chart.KubeVersion != "" {
+		if _, kvErr := semver.NewVersion(chart.KubeVersion); kvErr != nil {
+			err = errors.Join(err, fmt.Errorf(PkgValidateErrChartKubeVersion, chart.Name, chart.KubeVersion, kvErr))
+		}
+	}
+
+	for _, patch := range chart.PostRenderPatches {
+		if patch == "" || helpers.IsURL(patch) {
+			err = errors.Join(err, fmt.Errorf(PkgValidateErrChartPostRenderPatch, chart.Name, patch))
+		}
+	}
+
 	if nameErr := validateReleaseName(chart.Name, chart.ReleaseName); nameErr != nil {
 		err = errors.Join(err, nameErr)
 	}
 
+	err = errors.Join(err, validateAnnotationKeys(fmt.Sprintf("chart %q", chart.Name), chart.CommonAnnotations))
+	err = errors.Join(err, validateNamespaceLabels(fmt.Sprintf("chart %q", chart.Name), chart.ShouldCreateNamespace(), chart.NamespaceLabels))
+
 	return err
 }
 
@@ -314,5 +610,21 @@ func validateManifest(manifest v1alpha1.ZarfManifest) error {
 		err = errors.Join(err, fmt.Errorf(PkgValidateErrManifestFileOrKustomize, manifest.Name))
 	}
 
+	if len(manifest.FieldManager) > ZarfMaxFieldManagerLength {
+		err = errors.Join(err, fmt.Errorf(PkgValidateErrManifestFieldManager, manifest.Name, ZarfMaxFieldManagerLength))
+	}
+
+	if len(manifest.Variables) > 0 && !manifest.IsTemplate() {
+		err = errors.Join(err, fmt.Errorf(PkgValidateErrManifestVariableNoTmpl, manifest.Name))
+	}
+	for _, variable := range manifest.Variables {
+		if variable.Path == "" {
+			err = errors.Join(err, fmt.Errorf(PkgValidateErrManifestVariablePath, manifest.Name, variable.Name))
+		}
+	}
+
+	err = errors.Join(err, validateAnnotationKeys(fmt.Sprintf("manifest %q", manifest.Name), manifest.CommonAnnotations))
+	err = errors.Join(err, validateNamespaceLabels(fmt.Sprintf("manifest %q", manifest.Name), manifest.ShouldCreateNamespace(), manifest.NamespaceLabels))
+
 	return err
 }
@@ -5,13 +5,17 @@
 package v1alpha1
 
 import (
+	"errors"
 	"fmt"
+	"path"
 	"strings"
 	"testing"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/stretchr/testify/require"
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/util/jsonpath"
 )
 
 func TestZarfPackageValidate(t *testing.T) {
@@ -46,6 +50,226 @@ func TestZarfPackageValidate(t *testing.T) {
 			},
 			expectedErrs: []string{PkgValidateErrNoComponents},
 		},
+		{
+			name: "component dependencies",
+			pkg: v1alpha1.ZarfPackage{
+				Kind: v1alpha1.ZarfPackageConfig,
+				Metadata: v1alpha1.ZarfMetadata{
+					Name: "dependencies",
+				},
+				Components: []v1alpha1.ZarfComponent{
+					{
+						Name:      "first",
+						DependsOn: []string{"second"},
+					},
+					{
+						Name:      "second",
+						DependsOn: []string{"first", "second", "missing"},
+					},
+				},
+			},
+			expectedErrs: []string{
+				fmt.Sprintf(PkgValidateErrComponentDependsOn, "second", "missing"),
+				fmt.Sprintf(PkgValidateErrComponentDependsOnCycle, "first -> second -> first"),
+				fmt.Sprintf(PkgValidateErrComponentDependsOnCycle, "second -> second"),
+			},
+		},
+		{
+			name: "component dependencies defined later",
+			pkg: v1alpha1.ZarfPackage{
+				Kind: v1alpha1.ZarfPackageConfig,
+				Metadata: v1alpha1.ZarfMetadata{
+					Name: "dependencies",
+				},
+				Components: []v1alpha1.ZarfComponent{
+					{
+						Name:      "database",
+						DependsOn: []string{"database-operator"},
+					},
+					{
+						Name: "database-operator",
+					},
+					{
+						Name:      "app",
+						DependsOn: []string{"database", "database-operator"},
+					},
+				},
+			},
+			expectedErrs: nil,
+		},
+		{
+			name: "component conflicts",
+			pkg: v1alpha1.ZarfPackage{
+				Kind: v1alpha1.ZarfPackageConfig,
+				Metadata: v1alpha1.ZarfMetadata{
+					Name: "conflicts",
+				},
+				Components: []v1alpha1.ZarfComponent{
+					{
+						Name:          "first",
+						Required:      helpers.BoolPtr(true),
+						ConflictsWith: []string{"second", "third"},
+					},
+					{
+						Name:          "second",
+						ConflictsWith: []string{"second", "missing"},
+					},
+					{
+						Name:     "third",
+						Required: helpers.BoolPtr(true),
+					},
+				},
+			},
+			expectedErrs: []string{
+				fmt.Sprintf(PkgValidateErrComponentConflictsReq, "first", "third"),
+				fmt.Sprintf(PkgValidateErrComponentConflictsSelf, "second"),
+				fmt.Sprintf(PkgValidateErrComponentConflictsWith, "second", "missing"),
+			},
+		},
+		{
+			name: "component retry policy",
+			pkg: v1alpha1.ZarfPackage{
+				Kind: v1alpha1.ZarfPackageConfig,
+				Metadata: v1alpha1.ZarfMetadata{
+					Name: "retries",
+				},
+				Components: []v1alpha1.ZarfComponent{
+					{
+						Name:        "valid",
+						RetryPolicy: &v1alpha1.ZarfComponentRetryPolicy{MaxAttempts: 3, BackoffSeconds: 5},
+					},
+					{
+						Name:        "negative-attempts",
+						RetryPolicy: &v1alpha1.ZarfComponentRetryPolicy{MaxAttempts: -1},
+					},
+					{
+						Name:        "negative-backoff",
+						RetryPolicy: &v1alpha1.ZarfComponentRetryPolicy{MaxAttempts: 2, BackoffSeconds: -1},
+					},
+				},
+			},
+			expectedErrs: []string{
+				fmt.Sprintf(PkgValidateErrComponentRetryPolicy, "negative-attempts"),
+				fmt.Sprintf(PkgValidateErrComponentRetryPolicy, "negative-backoff"),
+			},
+		},
+		{
+			name: "component only expression",
+			pkg: v1alpha1.ZarfPackage{
+				Kind: v1alpha1.ZarfPackageConfig,
+				Metadata: v1alpha1.ZarfMetadata{
+					Name: "expressions",
+				},
+				Variables: []v1alpha1.InteractiveVariable{
+					{Variable: v1alpha1.Variable{Name: "ENABLE_MONITORING"}},
+					{Variable: v1alpha1.Variable{Name: "DISTRO"}},
+				},
+				Components: []v1alpha1.ZarfComponent{
+					{
+						Name: "valid",
+						Only: v1alpha1.ZarfComponentOnlyTarget{Expression: `ENABLE_MONITORING && DISTRO != "eks"`},
+					},
+					{
+						Name: "invalid",
+						Only: v1alpha1.ZarfComponentOnlyTarget{Expression: "ENABLE_MONITORING &&"},
+					},
+					{
+						Name: "undeclared",
+						Only: v1alpha1.ZarfComponentOnlyTarget{Expression: "ENABLE_MONITORING || AIRGAP"},
+					},
+				},
+			},
+			expectedErrs: []string{
+				fmt.Errorf(PkgValidateErrComponentExpression, "invalid", errors.New("unexpected end of expression")).Error(),
+				fmt.Sprintf(PkgValidateErrComponentExpressionVar, "undeclared", "AIRGAP"),
+			},
+		},
+		{
+			name: "resource annotations",
+			pkg: v1alpha1.ZarfPackage{
+				Kind: v1alpha1.ZarfPackageConfig,
+				Metadata: v1alpha1.ZarfMetadata{
+					Name: "resource-annotations",
+				},
+				Components: []v1alpha1.ZarfComponent{
+					{
+						Name: "valid",
+						ResourceAnnotations: map[string]string{
+							"argocd.argoproj.io/compare-options": "IgnoreExtraneous",
+							"team":                               "###ZARF_VAR_TEAM###",
+						},
+					},
+					{
+						Name:                "invalid",
+						ResourceAnnotations: map[string]string{"team name": "platform"},
+					},
+				},
+			},
+			expectedErrs: []string{
+				fmt.Sprintf(PkgValidateErrAnnotationKey, `component "invalid"`, "team name", strings.Join(validation.IsQualifiedName("team name"), "; ")),
+			},
+		},
+		{
+			name: "files",
+			pkg: v1alpha1.ZarfPackage{
+				Kind: v1alpha1.ZarfPackageConfig,
+				Metadata: v1alpha1.ZarfMetadata{
+					Name: "files",
+				},
+				Components: []v1alpha1.ZarfComponent{
+					{
+						Name: "files",
+						Files: []v1alpha1.ZarfFile{
+							{Source: "plain", Shasum: "abc"},
+							{Source: "sha256", Shasum: "sha256:abc"},
+							{Source: "sha512", Shasum: "sha512:abc"},
+							{Source: "md5", Shasum: "md5:abc"},
+							{Source: "https://example.com/retried", MaxRetries: 3, TimeoutSeconds: 30},
+							{Source: "https://example.com/negative", MaxRetries: -1},
+							{Source: "private-key", Mode: "0600"},
+							{Source: "typo", Mode: "0680"},
+							{Source: "vendor.tar.gz", Target: "vendor", ExtractPaths: []string{"vendor/bin/*", "vendor/lib/libzarf.so"}},
+							{Source: "both.tar.gz", ExtractPath: "bin/zarf", ExtractPaths: []string{"bin/*"}},
+							{Source: "folder.tar.gz", Shasum: "sha256:abc", ExtractPaths: []string{"bin/*"}},
+							{Source: "pattern.tar.gz", ExtractPaths: []string{"bin/[zarf"}},
+						},
+					},
+				},
+			},
+			expectedErrs: []string{
+				fmt.Errorf(PkgValidateErrFileShasum, "md5", errors.New(`unsupported checksum algorithm "md5", must be one of sha256 or sha512`)).Error(),
+				fmt.Sprintf(PkgValidateErrFileDownload, "https://example.com/negative"),
+				fmt.Errorf(PkgValidateErrFileMode, "typo", errors.New(`"0680" is not an octal permission string such as "0600"`)).Error(),
+				fmt.Sprintf(PkgValidateErrFileExtractPaths, "both.tar.gz"),
+				fmt.Sprintf(PkgValidateErrFileExtractPathsShasum, "folder.tar.gz"),
+				fmt.Errorf(PkgValidateErrFileExtractPattern, "pattern.tar.gz", "bin/[zarf", path.ErrBadPattern).Error(),
+			},
+		},
+		{
+			name: "data injection compression",
+			pkg: v1alpha1.ZarfPackage{
+				Kind: v1alpha1.ZarfPackageConfig,
+				Metadata: v1alpha1.ZarfMetadata{
+					Name: "data-injections",
+				},
+				Components: []v1alpha1.ZarfComponent{
+					{
+						Name: "data",
+						DataInjections: []v1alpha1.ZarfDataInjection{
+							{Source: "compress", Compress: true},
+							{Source: "compress-gzip", Compress: true, CompressionAlgorithm: v1alpha1.DataInjectionCompressionGzip},
+							{Source: "zstd", CompressionAlgorithm: v1alpha1.DataInjectionCompressionZstd},
+							{Source: "compress-zstd", Compress: true, CompressionAlgorithm: v1alpha1.DataInjectionCompressionZstd},
+							{Source: "xz", CompressionAlgorithm: "xz"},
+						},
+					},
+				},
+			},
+			expectedErrs: []string{
+				fmt.Sprintf(PkgValidateErrDataCompressAlias, "compress-zstd", "zstd"),
+				fmt.Sprintf(PkgValidateErrDataCompression, "xz", "xz"),
+			},
+		},
 		{
 			name: "invalid package",
 			pkg: v1alpha1.ZarfPackage{
@@ -154,6 +378,60 @@ func TestZarfPackageValidate(t *testing.T) {
 	}
 }
 
+func TestValidateMetadataLabels(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		labels       map[string]string
+		expectedErrs []string
+	}{
+		{
+			name: "valid labels",
+			labels: map[string]string{
+				"team":                  "platform",
+				"catalog.zarf.dev/tier": "gold",
+				"maturity":              "",
+			},
+		},
+		{
+			name: "invalid key and value",
+			labels: map[string]string{
+				"bad key":  "ok",
+				"maturity": "not a valid value",
+			},
+			expectedErrs: []string{
+				`invalid metadata label key "bad key"`,
+				`invalid metadata label value "not a valid value" for key "maturity"`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			pkg := v1alpha1.ZarfPackage{
+				Kind: v1alpha1.ZarfPackageConfig,
+				Metadata: v1alpha1.ZarfMetadata{
+					Name:   "labels",
+					Labels: tt.labels,
+				},
+				Components: []v1alpha1.ZarfComponent{
+					{
+						Name: "component1",
+					},
+				},
+			}
+			err := ValidatePackage(pkg)
+			if tt.expectedErrs == nil {
+				require.NoError(t, err)
+				return
+			}
+			for _, expectedErr := range tt.expectedErrs {
+				require.ErrorContains(t, err, expectedErr)
+			}
+		})
+	}
+}
+
 func TestValidateManifest(t *testing.T) {
 	t.Parallel()
 	longName := strings.Repeat("a", ZarfMaxChartNameLength+1)
@@ -177,6 +455,57 @@ func TestValidateManifest(t *testing.T) {
 			manifest:     v1alpha1.ZarfManifest{Name: "nothing-there"},
 			expectedErrs: []string{fmt.Sprintf(PkgValidateErrManifestFileOrKustomize, "nothing-there")},
 		},
+		{
+			name:         "field manager",
+			manifest:     v1alpha1.ZarfManifest{Name: "crds", Files: []string{"a-file"}, ServerSideApply: "true", FieldManager: "crd-operator", ForceConflicts: true},
+			expectedErrs: nil,
+		},
+		{
+			name:         "long field manager",
+			manifest:     v1alpha1.ZarfManifest{Name: "crds", Files: []string{"a-file"}, FieldManager: strings.Repeat("a", ZarfMaxFieldManagerLength+1)},
+			expectedErrs: []string{fmt.Sprintf(PkgValidateErrManifestFieldManager, "crds", ZarfMaxFieldManagerLength)},
+		},
+		{
+			name: "variables",
+			manifest: v1alpha1.ZarfManifest{
+				Name:           "overlay",
+				Kustomizations: []string{"overlay"},
+				Template:       helpers.BoolPtr(true),
+				Variables:      []v1alpha1.ZarfManifestVariable{{Name: "IMAGE_TAG", Path: "image.tag"}},
+			},
+			expectedErrs: nil,
+		},
+		{
+			name: "variables without template",
+			manifest: v1alpha1.ZarfManifest{
+				Name:           "overlay",
+				Kustomizations: []string{"overlay"},
+				Variables:      []v1alpha1.ZarfManifestVariable{{Name: "IMAGE_TAG"}},
+			},
+			expectedErrs: []string{
+				fmt.Sprintf(PkgValidateErrManifestVariableNoTmpl, "overlay"),
+				fmt.Sprintf(PkgValidateErrManifestVariablePath, "overlay", "IMAGE_TAG"),
+			},
+		},
+		{
+			name: "namespace labels without creating the namespace",
+			manifest: v1alpha1.ZarfManifest{
+				Name:            "provisioned",
+				Files:           []string{"a-file"},
+				CreateNamespace: helpers.BoolPtr(false),
+				NamespaceLabels: map[string]string{"team": "platform"},
+			},
+			expectedErrs: []string{fmt.Sprintf(PkgValidateErrNamespaceLabels, `manifest "provisioned"`)},
+		},
+		{
+			name: "invalid annotation key",
+			manifest: v1alpha1.ZarfManifest{
+				Name:              "annotated",
+				Files:             []string{"a-file"},
+				CommonAnnotations: map[string]string{"-team": "platform"},
+			},
+			expectedErrs: []string{fmt.Sprintf(PkgValidateErrAnnotationKey, `manifest "annotated"`, "-team", strings.Join(validation.IsQualifiedName("-team"), "; "))},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -303,6 +632,57 @@ func TestValidateChart(t *testing.T) {
 			chart:        v1alpha1.ZarfChart{Name: "chart3", Namespace: "namespace", URL: "http://whatever", Version: "v1.0.0"},
 			expectedErrs: nil,
 		},
+		{
+			name:         "valid kubeVersion",
+			chart:        v1alpha1.ZarfChart{Name: "chart4", Namespace: "namespace", URL: "http://whatever", Version: "v1.0.0", KubeVersion: "v1.30.0"},
+			expectedErrs: nil,
+		},
+		{
+			name:         "invalid kubeVersion",
+			chart:        v1alpha1.ZarfChart{Name: "chart5", Namespace: "namespace", URL: "http://whatever", Version: "v1.0.0", KubeVersion: "latest"},
+			expectedErrs: []string{`chart "chart5" has an invalid kube version "latest"`},
+			partialMatch: true,
+		},
+		{
+			name:         "valid credentials",
+			chart:        v1alpha1.ZarfChart{Name: "chart6", Namespace: "namespace", URL: "oci://whatever", Version: "v1.0.0", Username: "robot", PasswordEnv: "CHART_PASSWORD"},
+			expectedErrs: nil,
+		},
+		{
+			name:  "username without passwordEnv",
+			chart: v1alpha1.ZarfChart{Name: "chart7", Namespace: "namespace", URL: "oci://whatever", Version: "v1.0.0", Username: "robot"},
+			expectedErrs: []string{
+				fmt.Sprintf(PkgValidateErrChartCredentials, "chart7"),
+			},
+		},
+		{
+			name:  "credentials on a local chart",
+			chart: v1alpha1.ZarfChart{Name: "chart8", Namespace: "namespace", LocalPath: "wherever", Version: "v1.0.0", Username: "robot", PasswordEnv: "CHART_PASSWORD"},
+			expectedErrs: []string{
+				fmt.Sprintf(PkgValidateErrChartCredentialsURL, "chart8"),
+			},
+		},
+		{
+			name:  "remote post-render patch",
+			chart: v1alpha1.ZarfChart{Name: "chart9", Namespace: "namespace", URL: "http://whatever", Version: "v1.0.0", PostRenderPatches: []string{"patches/drop-host-path.yaml", "https://example.com/patch.yaml"}},
+			expectedErrs: []string{
+				fmt.Sprintf(PkgValidateErrChartPostRenderPatch, "chart9", "https://example.com/patch.yaml"),
+			},
+		},
+		{
+			name:  "namespace labels",
+			chart: v1alpha1.ZarfChart{Name: "chart10", Namespace: "namespace", URL: "http://whatever", Version: "v1.0.0", NamespaceLabels: map[string]string{"istio-injection": "enabled", "team": "-platform"}},
+			expectedErrs: []string{
+				fmt.Sprintf(PkgValidateErrMetadataLabelValue, "-platform", "team", strings.Join(validation.IsValidLabelValue("-platform"), "; ")),
+			},
+		},
+		{
+			name:  "namespace labels without creating the namespace",
+			chart: v1alpha1.ZarfChart{Name: "chart11", Namespace: "namespace", URL: "http://whatever", Version: "v1.0.0", CreateNamespace: helpers.BoolPtr(false), NamespaceLabels: map[string]string{"team": "platform"}},
+			expectedErrs: []string{
+				fmt.Sprintf(PkgValidateErrNamespaceLabels, `chart "chart11"`),
+			},
+		},
 		{
 			name:         "missing name and releaseName",
 			chart:        v1alpha1.ZarfChart{Namespace: "namespace", URL: "http://whatever", Version: "v1.0.0"},
@@ -372,6 +752,20 @@ func TestValidateComponentActions(t *testing.T) {
 			},
 			expectedErrs: []string{"cannot contain setVariables outside of onDeploy in actions"},
 		},
+		{
+			name: "setVariablesFromJSON in onRemove",
+			actions: v1alpha1.ZarfComponentActions{
+				OnRemove: v1alpha1.ZarfComponentActionSet{
+					Before: []v1alpha1.ZarfComponentAction{
+						{
+							Cmd:                  "echo '{}'",
+							SetVariablesFromJSON: []v1alpha1.ZarfComponentActionJSONVariable{{Variable: v1alpha1.Variable{Name: "VAR"}, Path: ".name"}},
+						},
+					},
+				},
+			},
+			expectedErrs: []string{"cannot contain setVariables outside of onDeploy in actions"},
+		},
 		{
 			name: "templating in onCreate",
 			actions: v1alpha1.ZarfComponentActions{
@@ -386,6 +780,20 @@ func TestValidateComponentActions(t *testing.T) {
 			},
 			expectedErrs: []string{PkgValidateErrActionTemplateOnCreate},
 		},
+		{
+			name: "confirm in onCreate",
+			actions: v1alpha1.ZarfComponentActions{
+				OnCreate: v1alpha1.ZarfComponentActionSet{
+					After: []v1alpha1.ZarfComponentAction{
+						{
+							Cmd:     "echo 'confirm not allowed'",
+							Confirm: true,
+						},
+					},
+				},
+			},
+			expectedErrs: []string{PkgValidateErrActionConfirmOnCreate},
+		},
 		{
 			name: "invalid onCreate action",
 			actions: v1alpha1.ZarfComponentActions{
@@ -472,6 +880,129 @@ func TestValidateComponentAction(t *testing.T) {
 			},
 			expectedErrs: []string{PkgValidateErrActionClusterNetwork},
 		},
+		{
+			name: "multiple cluster conditions",
+			action: v1alpha1.ZarfComponentAction{
+				Wait: &v1alpha1.ZarfComponentActionWait{Clusters: []v1alpha1.ZarfComponentActionWaitCluster{{Kind: "StatefulSet"}, {Kind: "Job"}}},
+			},
+		},
+		{
+			name: "cluster and multiple cluster conditions",
+			action: v1alpha1.ZarfComponentAction{
+				Wait: &v1alpha1.ZarfComponentActionWait{Cluster: &v1alpha1.ZarfComponentActionWaitCluster{Kind: "Deployment"}, Clusters: []v1alpha1.ZarfComponentActionWaitCluster{{Kind: "Job"}}},
+			},
+		},
+		{
+			name: "empty list of cluster conditions",
+			action: v1alpha1.ZarfComponentAction{
+				Wait: &v1alpha1.ZarfComponentActionWait{Clusters: []v1alpha1.ZarfComponentActionWaitCluster{}},
+			},
+			expectedErrs: []string{PkgValidateErrActionClusterNetwork},
+		},
+		{
+			name: "network wait with method and headers",
+			action: v1alpha1.ZarfComponentAction{
+				Wait: &v1alpha1.ZarfComponentActionWait{Network: &v1alpha1.ZarfComponentActionWaitNetwork{
+					Protocol:           "https",
+					Address:            "localhost:8443/ready",
+					Method:             "POST",
+					Headers:            map[string]string{"Authorization": "Bearer ###ZARF_VAR_TOKEN###"},
+					InsecureSkipVerify: true,
+				}},
+			},
+		},
+		{
+			name: "tcp network wait with headers",
+			action: v1alpha1.ZarfComponentAction{
+				Wait: &v1alpha1.ZarfComponentActionWait{Network: &v1alpha1.ZarfComponentActionWaitNetwork{
+					Protocol: "tcp",
+					Address:  "localhost:5432",
+					Headers:  map[string]string{"Authorization": "Bearer token"},
+				}},
+			},
+			expectedErrs: []string{fmt.Sprintf(PkgValidateErrActionNetworkHTTP, "tcp", "localhost:5432")},
+		},
+		{
+			name: "variable wait",
+			action: v1alpha1.ZarfComponentAction{
+				Wait: &v1alpha1.ZarfComponentActionWait{Variable: &v1alpha1.ZarfComponentActionWaitVariable{Name: "READY"}},
+			},
+		},
+		{
+			name: "network and variable both set",
+			action: v1alpha1.ZarfComponentAction{
+				Wait: &v1alpha1.ZarfComponentActionWait{Network: &v1alpha1.ZarfComponentActionWaitNetwork{}, Variable: &v1alpha1.ZarfComponentActionWaitVariable{Name: "READY"}},
+			},
+			expectedErrs: []string{PkgValidateErrActionClusterNetwork},
+		},
+		{
+			name: "variables from JSON",
+			action: v1alpha1.ZarfComponentAction{
+				Cmd: "kubectl get svc ingress -o json",
+				SetVariablesFromJSON: []v1alpha1.ZarfComponentActionJSONVariable{
+					{Variable: v1alpha1.Variable{Name: "NAME"}, Path: ".metadata.name"},
+					{Variable: v1alpha1.Variable{Name: "IP"}, Path: "{.status.loadBalancer.ingress[0].ip}"},
+				},
+			},
+		},
+		{
+			name: "min ready with a selector and a jsonpath condition",
+			action: v1alpha1.ZarfComponentAction{
+				Wait: &v1alpha1.ZarfComponentActionWait{Cluster: &v1alpha1.ZarfComponentActionWaitCluster{
+					Kind: "Pod", Name: "app=etcd", Condition: "'{.status.phase}'=Running", MinReady: 2,
+				}},
+			},
+		},
+		{
+			name: "invalid min ready",
+			action: v1alpha1.ZarfComponentAction{
+				Wait: &v1alpha1.ZarfComponentActionWait{Clusters: []v1alpha1.ZarfComponentActionWaitCluster{
+					{Kind: "Pod", Name: "etcd-0", Condition: "{.status.phase}=Running", MinReady: 2},
+					{Kind: "Pod", Name: "app=etcd", Condition: "Ready", MinReady: 2},
+					{Kind: "Pod", Name: "app=web", Condition: "{.status.phase}=Running", MinReady: -1},
+				}},
+			},
+			expectedErrs: []string{
+				fmt.Sprintf(PkgValidateErrActionMinReady, "Pod", "etcd-0"),
+				fmt.Sprintf(PkgValidateErrActionMinReady, "Pod", "app=etcd"),
+				fmt.Sprintf(PkgValidateErrActionMinReady, "Pod", "app=web"),
+			},
+		},
+		{
+			name: "env file on a wait action",
+			action: v1alpha1.ZarfComponentAction{
+				Wait:    &v1alpha1.ZarfComponentActionWait{Variable: &v1alpha1.ZarfComponentActionWaitVariable{Name: "READY"}},
+				EnvFile: "action.env",
+			},
+			expectedErrs: []string{PkgValidateErrActionEnvFileCmd},
+		},
+		{
+			name: "invalid allowed exit codes",
+			action: v1alpha1.ZarfComponentAction{
+				Wait:             &v1alpha1.ZarfComponentActionWait{Variable: &v1alpha1.ZarfComponentActionWaitVariable{Name: "READY"}},
+				AllowedExitCodes: []int{0, -1, 256},
+			},
+			expectedErrs: []string{
+				PkgValidateErrActionExitCodesCmd,
+				fmt.Sprintf(PkgValidateErrActionExitCode, -1),
+				fmt.Sprintf(PkgValidateErrActionExitCode, 256),
+			},
+		},
+		{
+			name: "invalid variables from JSON",
+			action: v1alpha1.ZarfComponentAction{
+				Wait: &v1alpha1.ZarfComponentActionWait{Variable: &v1alpha1.ZarfComponentActionWaitVariable{Name: "READY"}},
+				SetVariablesFromJSON: []v1alpha1.ZarfComponentActionJSONVariable{
+					{Variable: v1alpha1.Variable{Name: "EMPTY"}},
+					{Variable: v1alpha1.Variable{Name: "IP"}, Path: ".status.loadBalancer.ingress[0"},
+				},
+			},
+			expectedErrs: []string{
+				PkgValidateErrActionJSONVariableCmd,
+				fmt.Sprintf(PkgValidateErrActionJSONPathEmpty, "EMPTY"),
+				fmt.Errorf(PkgValidateErrActionJSONPath, "IP", ".status.loadBalancer.ingress[0", jsonpath.New("IP").Parse("{.status.loadBalancer.ingress[0}")).Error(),
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -9,20 +9,24 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/pkg/cluster"
 	"github.com/zarf-dev/zarf/src/pkg/logger"
 	"github.com/zarf-dev/zarf/src/pkg/state"
 	"github.com/zarf-dev/zarf/src/pkg/variables"
 
 	"github.com/Masterminds/semver/v3"
+	"github.com/avast/retry-go/v4"
 	plutoversionsfile "github.com/fairwindsops/pluto/v5"
 	plutoapi "github.com/fairwindsops/pluto/v5/pkg/api"
 	goyaml "github.com/goccy/go-yaml"
 	"helm.sh/helm/v4/pkg/action"
 	"helm.sh/helm/v4/pkg/chart/common"
+	commonutil "helm.sh/helm/v4/pkg/chart/common/util"
 	chartv2 "helm.sh/helm/v4/pkg/chart/v2"
 	"helm.sh/helm/v4/pkg/kube"
 	"helm.sh/helm/v4/pkg/release"
@@ -30,6 +34,7 @@ import (
 	releasev1 "helm.sh/helm/v4/pkg/release/v1"
 	releaseutil "helm.sh/helm/v4/pkg/release/v1/util"
 	"helm.sh/helm/v4/pkg/storage/driver"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/yaml"
@@ -74,6 +79,12 @@ type InstallUpgradeOptions struct {
 	NamespaceOverride string
 	// IsInteractive decides if Zarf can interactively prompt users through the CLI
 	IsInteractive bool
+	// ResourceAnnotations are added to every resource of the chart, the commonAnnotations of the chart take precedence
+	ResourceAnnotations map[string]string
+	// FieldManager overrides the field manager of the chart's resources, the Zarf field manager is used when empty
+	FieldManager string
+	// PostRenderPatchesPath is the directory holding the packaged post-render patches of the chart
+	PostRenderPatchesPath string
 }
 
 // InstallOrUpgradeChart performs a helm install of the given chart.
@@ -95,7 +106,7 @@ func InstallOrUpgradeChart(ctx context.Context, zarfChart v1alpha1.ZarfChart, ch
 	}
 
 	// Setup K8s connection.
-	actionConfig, err := createActionConfig(ctx, zarfChart.Namespace)
+	actionConfig, err := createActionConfig(ctx, zarfChart.Namespace, opts.FieldManager)
 	if err != nil {
 		return nil, zarfChart.ReleaseName, fmt.Errorf("unable to initialize the K8s client: %w", err)
 	}
@@ -104,31 +115,38 @@ func InstallOrUpgradeChart(ctx context.Context, zarfChart v1alpha1.ZarfChart, ch
 	if err != nil {
 		return nil, zarfChart.ReleaseName, fmt.Errorf("unable to create helm renderer: %w", err)
 	}
+	postRender.annotations = mergeAnnotations(opts.ResourceAnnotations, zarfChart.CommonAnnotations)
+	postRender.postRenderPatchesPath = opts.PostRenderPatchesPath
 
 	histClient := action.NewHistory(actionConfig)
 
 	helmCtx, helmCtxCancel := context.WithTimeout(ctx, opts.Timeout)
 	defer helmCtxCancel()
 
-	releases, histErr := histClient.Run(zarfChart.ReleaseName)
+	// The release history is checked on every attempt so a retry after a failed install becomes an upgrade.
+	newRelease, err := retryTransientHelmErrors(helmCtx, zarfChart, helmRetryDelay, func() (release.Releaser, error) {
+		releases, histErr := histClient.Run(zarfChart.ReleaseName)
 
-	l.Debug("checking for existing helm deployment")
+		l.Debug("checking for existing helm deployment")
 
-	var newRelease release.Releaser
-	if errors.Is(histErr, driver.ErrReleaseNotFound) {
-		// No prior release, try to install it.
-		l.Info("performing Helm install", "chart", zarfChart.Name)
+		if errors.Is(histErr, driver.ErrReleaseNotFound) {
+			// No prior release, try to install it.
+			l.Info("performing Helm install", "chart", zarfChart.Name)
 
-		newRelease, err = installChart(helmCtx, zarfChart, chart, values, opts, actionConfig, postRender)
-	} else if histErr == nil && len(releases) > 0 {
-		// Otherwise, there is a prior release so upgrade it.
-		l.Info("performing Helm upgrade", "chart", zarfChart.Name)
+			return installChart(helmCtx, zarfChart, chart, values, opts, actionConfig, postRender)
+		} else if histErr == nil && len(releases) > 0 {
+			// Otherwise, there is a prior release so upgrade it.
+			l.Info("performing Helm upgrade", "chart", zarfChart.Name)
 
-		lastReleaser := releases[len(releases)-1]
+			lastReleaser := releases[len(releases)-1]
 
-		newRelease, err = upgradeChart(helmCtx, zarfChart, chart, values, opts, actionConfig, postRender, lastReleaser)
-	} else {
-		return nil, zarfChart.ReleaseName, fmt.Errorf("unable to verify the chart installation status: %w", histErr)
+			return upgradeChart(helmCtx, zarfChart, chart, values, opts, actionConfig, postRender, lastReleaser)
+		}
+		return nil, &releaseStatusError{err: histErr}
+	})
+	var statusErr *releaseStatusError
+	if errors.As(err, &statusErr) {
+		return nil, zarfChart.ReleaseName, fmt.Errorf("unable to verify the chart installation status: %w", statusErr.err)
 	}
 	if err != nil {
 		removeMsg := "if you need to remove the failed chart, use `zarf package remove`"
@@ -196,7 +214,7 @@ func InstallOrUpgradeChart(ctx context.Context, zarfChart v1alpha1.ZarfChart, ch
 // RemoveChart removes a chart from the cluster.
 func RemoveChart(ctx context.Context, namespace string, name string, timeout time.Duration) error {
 	// Establish a new actionConfig for the namespace.
-	actionConfig, err := createActionConfig(ctx, namespace)
+	actionConfig, err := createActionConfig(ctx, namespace, "")
 	if err != nil {
 		return fmt.Errorf("unable to initialize the K8s client: %w", err)
 	}
@@ -212,7 +230,7 @@ func UpdateReleaseValues(ctx context.Context, zarfChart v1alpha1.ZarfChart, upda
 	l := logger.From(ctx)
 	l.Debug("updating values for helm release", "name", zarfChart.ReleaseName)
 
-	actionConfig, err := createActionConfig(ctx, zarfChart.Namespace)
+	actionConfig, err := createActionConfig(ctx, zarfChart.Namespace, opts.FieldManager)
 	if err != nil {
 		return fmt.Errorf("unable to initialize the K8s client: %w", err)
 	}
@@ -224,6 +242,8 @@ func UpdateReleaseValues(ctx context.Context, zarfChart v1alpha1.ZarfChart, upda
 	if err != nil {
 		return fmt.Errorf("unable to create helm renderer: %w", err)
 	}
+	postRender.annotations = mergeAnnotations(opts.ResourceAnnotations, zarfChart.CommonAnnotations)
+	postRender.postRenderPatchesPath = opts.PostRenderPatchesPath
 
 	histClient := action.NewHistory(actionConfig)
 	histClient.Max = 1
@@ -270,8 +290,90 @@ func UpdateReleaseValues(ctx context.Context, zarfChart v1alpha1.ZarfChart, upda
 	return fmt.Errorf("unable to find the %s helm release", zarfChart.ReleaseName)
 }
 
+// helmRetryDelay is the initial delay between Helm install/upgrade attempts, it doubles with each attempt.
+const helmRetryDelay = 2 * time.Second
+
+// releaseStatusError is returned when the release history of a chart could not be determined.
+type releaseStatusError struct {
+	err error
+}
+
+func (e *releaseStatusError) Error() string {
+	return fmt.Sprintf("unable to determine release status: %v", e.err)
+}
+
+// retryTransientHelmErrors runs fn, retrying up to zarfChart.MaxRetries times when it fails with an error that
+// isTransientHelmError classifies as transient. Any other error is returned immediately.
+func retryTransientHelmErrors(ctx context.Context, zarfChart v1alpha1.ZarfChart, delay time.Duration, fn func() (release.Releaser, error)) (release.Releaser, error) {
+	if zarfChart.MaxRetries <= 0 {
+		return fn()
+	}
+	l := logger.From(ctx)
+	attempts := uint(zarfChart.MaxRetries) + 1
+	return retry.DoWithData(fn,
+		retry.Attempts(attempts),
+		retry.Delay(delay),
+		retry.MaxDelay(config.ZarfDefaultRetryMaxDelay),
+		retry.DelayType(retry.BackOffDelay),
+		retry.LastErrorOnly(true),
+		retry.Context(ctx),
+		retry.RetryIf(isTransientHelmError),
+		retry.OnRetry(func(n uint, err error) {
+			l.Warn("retrying Helm install/upgrade after a transient error",
+				"attempt", n+1,
+				"maxAttempts", attempts,
+				"chart", zarfChart.Name,
+				"error", err,
+			)
+		}),
+	)
+}
+
+// transientHelmErrorMessages are substrings of errors from the Kubernetes API server that Helm does not always wrap
+// as typed API errors.
+var transientHelmErrorMessages = []string{
+	"etcdserver: request timed out",
+	"etcdserver: leader changed",
+	"the object has been modified; please apply your changes to the latest version and try again",
+	"the server is currently unable to handle the request",
+	"http2: client connection lost",
+	"connection reset by peer",
+	"TLS handshake timeout",
+}
+
+// isTransientHelmError classifies whether a Helm install or upgrade error is caused by a transient Kubernetes API
+// condition that is likely to succeed on retry: server timeouts, conflicts, throttling, and unavailable or internal
+// API server errors. Chart errors such as invalid values, failed templates, or schema violations are not transient.
+func isTransientHelmError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var statusErr *releaseStatusError
+	if errors.As(err, &statusErr) {
+		return false
+	}
+	if kerrors.IsServerTimeout(err) || kerrors.IsTimeout(err) || kerrors.IsConflict(err) || kerrors.IsTooManyRequests(err) ||
+		kerrors.IsServiceUnavailable(err) || kerrors.IsInternalError(err) {
+		return true
+	}
+	for _, msg := range transientHelmErrorMessages {
+		if strings.Contains(err.Error(), msg) {
+			return true
+		}
+	}
+	return false
+}
+
 func installChart(ctx context.Context, zarfChart v1alpha1.ZarfChart, chart *chartv2.Chart, chartValues common.Values,
 	opts InstallUpgradeOptions, actionConfig *action.Configuration, postRender *renderer) (release.Releaser, error) {
+	client := newInstallAction(zarfChart, opts, actionConfig, postRender)
+
+	// Perform the loadedChart installation.
+	return client.RunWithContext(ctx, chart, chartValues)
+}
+
+// newInstallAction configures a Helm install action for the given chart.
+func newInstallAction(zarfChart v1alpha1.ZarfChart, opts InstallUpgradeOptions, actionConfig *action.Configuration, postRender *renderer) *action.Install {
 	// Bind the helm action.
 	client := action.NewInstall(actionConfig)
 
@@ -302,8 +404,9 @@ func installChart(ctx context.Context, zarfChart v1alpha1.ZarfChart, chart *char
 	client.ServerSideApply = zarfChart.GetServerSideApply() != "false"
 	client.ForceConflicts = shouldForceConflicts(zarfChart.GetServerSideApply(), nil, opts.ForceConflicts)
 
-	// Perform the loadedChart installation.
-	return client.RunWithContext(ctx, chart, chartValues)
+	client.DisableHooks = zarfChart.DisableHooks
+
+	return client
 }
 
 func upgradeChart(ctx context.Context, zarfChart v1alpha1.ZarfChart, chart *chartv2.Chart, chartValues common.Values,
@@ -314,6 +417,18 @@ func upgradeChart(ctx context.Context, zarfChart v1alpha1.ZarfChart, chart *char
 		return nil, fmt.Errorf("unable to check for API deprecations: %w", err)
 	}
 
+	rel, err := release.NewAccessor(lastRelease)
+	if err != nil {
+		return nil, err
+	}
+	client := newUpgradeAction(zarfChart, opts, actionConfig, postRender, rel)
+
+	// Perform the loadedChart upgrade.
+	return client.RunWithContext(ctx, zarfChart.ReleaseName, chart, chartValues)
+}
+
+// newUpgradeAction configures a Helm upgrade action for the given chart and its last release.
+func newUpgradeAction(zarfChart v1alpha1.ZarfChart, opts InstallUpgradeOptions, actionConfig *action.Configuration, postRender *renderer, lastRelease release.Accessor) *action.Upgrade {
 	// Setup a new upgrade action
 	client := action.NewUpgrade(actionConfig)
 
@@ -328,11 +443,7 @@ func upgradeChart(ctx context.Context, zarfChart v1alpha1.ZarfChart, chart *char
 	}
 
 	client.ServerSideApply = zarfChart.GetServerSideApply()
-	rel, err := release.NewAccessor(lastRelease)
-	if err != nil {
-		return nil, err
-	}
-	client.ForceConflicts = shouldForceConflicts(zarfChart.GetServerSideApply(), rel, opts.ForceConflicts)
+	client.ForceConflicts = shouldForceConflicts(zarfChart.GetServerSideApply(), lastRelease, opts.ForceConflicts)
 
 	client.SkipCRDs = true
 
@@ -346,8 +457,9 @@ func upgradeChart(ctx context.Context, zarfChart v1alpha1.ZarfChart, chart *char
 
 	client.MaxHistory = maxHelmHistory
 
-	// Perform the loadedChart upgrade.
-	return client.RunWithContext(ctx, zarfChart.ReleaseName, chart, chartValues)
+	client.DisableHooks = zarfChart.DisableHooks
+
+	return client
 }
 
 func rollbackChart(zarfChart v1alpha1.ZarfChart, rel release.Accessor, actionConfig *action.Configuration, timeout time.Duration, forceConflicts bool) error {
@@ -359,6 +471,7 @@ func rollbackChart(zarfChart v1alpha1.ZarfChart, rel release.Accessor, actionCon
 	client.Timeout = timeout
 	client.Version = rel.Version()
 	client.MaxHistory = maxHelmHistory
+	client.DisableHooks = zarfChart.DisableHooks
 	return client.Run(zarfChart.ReleaseName)
 }
 
@@ -384,6 +497,23 @@ func LoadChartData(zarfChart v1alpha1.ZarfChart, chartPath string, valuesPath st
 	return loadedChart, chartValues, nil
 }
 
+// ValidateChartValues checks the values, coalesced with the defaults of the chart, against the values.schema.json of the
+// chart and its dependencies so invalid overrides are reported before anything is installed. Charts without a schema
+// and charts with schema validation disabled are not checked.
+func ValidateChartValues(zarfChart v1alpha1.ZarfChart, chart *chartv2.Chart, values common.Values) error {
+	if !zarfChart.ShouldRunSchemaValidation() {
+		return nil
+	}
+	coalesced, err := commonutil.CoalesceValues(chart, values)
+	if err != nil {
+		return fmt.Errorf("unable to merge the values of chart %s: %w", zarfChart.Name, err)
+	}
+	if err := commonutil.ValidateAgainstSchema(chart, coalesced); err != nil {
+		return fmt.Errorf("values for chart %s don't meet the specifications of the schema(s) in the following chart(s):\n%w", zarfChart.Name, err)
+	}
+	return nil
+}
+
 // migrateDeprecatedAPIs searches through all the objects from the latest release and migrates any deprecated APIs to the latest version.
 // If any deprecated fields are found, the release will be updated and saved back to the cluster.
 func migrateDeprecatedAPIs(ctx context.Context, c *cluster.Cluster, actionConfig *action.Configuration, latestReleaser release.Releaser) error {
@@ -0,0 +1,275 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package helm contains operations for working with helm charts.
+package helm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"helm.sh/helm/v4/pkg/action"
+	"helm.sh/helm/v4/pkg/chart/v2/loader"
+	"helm.sh/helm/v4/pkg/release"
+	releasev1 "helm.sh/helm/v4/pkg/release/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestIsTransientHelmError(t *testing.T) {
+	t.Parallel()
+
+	gr := schema.GroupResource{Resource: "deployments"}
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{
+			name:     "nil",
+			err:      nil,
+			expected: false,
+		},
+		{
+			name:     "server timeout",
+			err:      kerrors.NewServerTimeout(gr, "create", 1),
+			expected: true,
+		},
+		{
+			name:     "conflict",
+			err:      kerrors.NewConflict(gr, "podinfo", errors.New("conflict")),
+			expected: true,
+		},
+		{
+			name:     "too many requests",
+			err:      kerrors.NewTooManyRequests("slow down", 1),
+			expected: true,
+		},
+		{
+			name:     "service unavailable",
+			err:      kerrors.NewServiceUnavailable("unavailable"),
+			expected: true,
+		},
+		{
+			name:     "wrapped internal error",
+			err:      fmt.Errorf("failed to create resource: %w", kerrors.NewInternalError(errors.New("boom"))),
+			expected: true,
+		},
+		{
+			name:     "etcd timeout message",
+			err:      errors.New("Internal error occurred: etcdserver: request timed out"),
+			expected: true,
+		},
+		{
+			name:     "invalid values",
+			err:      errors.New("values don't meet the specifications of the schema(s) in the following chart(s)"),
+			expected: false,
+		},
+		{
+			name:     "not found",
+			err:      kerrors.NewNotFound(gr, "podinfo"),
+			expected: false,
+		},
+		{
+			name:     "release status",
+			err:      &releaseStatusError{err: kerrors.NewServiceUnavailable("unavailable")},
+			expected: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.expected, isTransientHelmError(tt.err))
+		})
+	}
+}
+
+func TestRetryTransientHelmErrors(t *testing.T) {
+	t.Parallel()
+
+	transientErr := kerrors.NewServerTimeout(schema.GroupResource{Resource: "deployments"}, "create", 1)
+	chartErr := errors.New("template: podinfo/templates/deployment.yaml:1: unexpected EOF")
+
+	tests := []struct {
+		name             string
+		maxRetries       int
+		errs             []error
+		expectedErr      error
+		expectedAttempts int
+	}{
+		{
+			name:             "no retries configured",
+			maxRetries:       0,
+			errs:             []error{transientErr},
+			expectedErr:      transientErr,
+			expectedAttempts: 1,
+		},
+		{
+			name:             "succeeds after transient errors",
+			maxRetries:       3,
+			errs:             []error{transientErr, transientErr},
+			expectedAttempts: 3,
+		},
+		{
+			name:             "chart errors are not retried",
+			maxRetries:       3,
+			errs:             []error{chartErr},
+			expectedErr:      chartErr,
+			expectedAttempts: 1,
+		},
+		{
+			name:             "retries are exhausted",
+			maxRetries:       2,
+			errs:             []error{transientErr, transientErr, transientErr, transientErr},
+			expectedErr:      transientErr,
+			expectedAttempts: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			zarfChart := v1alpha1.ZarfChart{Name: "podinfo", MaxRetries: tt.maxRetries}
+			attempts := 0
+			rel, err := retryTransientHelmErrors(context.Background(), zarfChart, time.Millisecond, func() (release.Releaser, error) {
+				attempts++
+				if attempts <= len(tt.errs) {
+					return nil, tt.errs[attempts-1]
+				}
+				return &releasev1.Release{Name: "podinfo"}, nil
+			})
+			require.Equal(t, tt.expectedAttempts, attempts)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, rel)
+		})
+	}
+}
+
+func TestHelmActionsDisableHooks(t *testing.T) {
+	t.Parallel()
+
+	for _, disableHooks := range []bool{true, false} {
+		t.Run(fmt.Sprintf("disableHooks=%t", disableHooks), func(t *testing.T) {
+			t.Parallel()
+
+			zarfChart := v1alpha1.ZarfChart{Name: "podinfo", Namespace: "podinfo", ReleaseName: "podinfo", DisableHooks: disableHooks}
+			actionConfig := &action.Configuration{}
+
+			install := newInstallAction(zarfChart, InstallUpgradeOptions{}, actionConfig, nil)
+			require.Equal(t, disableHooks, install.DisableHooks)
+
+			lastRelease, err := release.NewAccessor(&releasev1.Release{Name: "podinfo", Namespace: "podinfo"})
+			require.NoError(t, err)
+			upgrade := newUpgradeAction(zarfChart, InstallUpgradeOptions{}, actionConfig, nil, lastRelease)
+			require.Equal(t, disableHooks, upgrade.DisableHooks)
+		})
+	}
+}
+
+func TestValidateChartValues(t *testing.T) {
+	t.Parallel()
+
+	schemaChart, err := loader.Load(filepath.Join("testdata", "values-schema", "schema-chart"))
+	require.NoError(t, err)
+	simpleChart, err := loader.Load(filepath.Join("testdata", "template", "simple-chart"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		zarfChart   v1alpha1.ZarfChart
+		values      map[string]any
+		noSchema    bool
+		errContains string
+	}{
+		{
+			name:      "chart defaults pass",
+			zarfChart: v1alpha1.ZarfChart{Name: "schema-chart"},
+			values:    map[string]any{},
+		},
+		{
+			name:      "valid overrides pass",
+			zarfChart: v1alpha1.ZarfChart{Name: "schema-chart"},
+			values: map[string]any{
+				"replicaCount": 3,
+				"image":        map[string]any{"tag": "6.5.0"},
+			},
+		},
+		{
+			name:        "override with the wrong type is rejected",
+			zarfChart:   v1alpha1.ZarfChart{Name: "schema-chart"},
+			values:      map[string]any{"replicaCount": "three"},
+			errContains: "replicaCount",
+		},
+		{
+			name:        "override with a misspelled path is rejected",
+			zarfChart:   v1alpha1.ZarfChart{Name: "schema-chart"},
+			values:      map[string]any{"image": map[string]any{"tags": "6.5.0"}},
+			errContains: "tags",
+		},
+		{
+			name:      "disabled schema validation skips the check",
+			zarfChart: v1alpha1.ZarfChart{Name: "schema-chart", SchemaValidation: helpers.BoolPtr(false)},
+			values:    map[string]any{"replicaCount": "three"},
+		},
+		{
+			name:      "chart without a schema is not checked",
+			zarfChart: v1alpha1.ZarfChart{Name: "simple-chart"},
+			values:    map[string]any{"anything": []any{"goes"}},
+			noSchema:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			chart := schemaChart
+			if tt.noSchema {
+				chart = simpleChart
+			}
+			err := ValidateChartValues(tt.zarfChart, chart, tt.values)
+			if tt.errContains != "" {
+				require.ErrorContains(t, err, "values for chart "+tt.zarfChart.Name+" don't meet the specifications")
+				require.ErrorContains(t, err, tt.errContains)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestFieldManagerRoundTripper(t *testing.T) {
+	t.Parallel()
+
+	var queries []url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query())
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+
+	client := &http.Client{Transport: &fieldManagerRoundTripper{fieldManager: "team-a", wrapped: http.DefaultTransport}}
+	for _, target := range []string{"/apis/apps/v1/deployments?fieldManager=helm&force=true", "/api/v1/pods"} {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodPatch, srv.URL+target, nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+	}
+
+	require.Len(t, queries, 2)
+	require.Equal(t, url.Values{"fieldManager": {"team-a"}, "force": {"true"}}, queries[0])
+	require.Empty(t, queries[1])
+}
@@ -10,6 +10,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
 	"path"
 	"path/filepath"
@@ -26,6 +27,8 @@ import (
 	"helm.sh/helm/v4/pkg/cli"
 	"helm.sh/helm/v4/pkg/cli/values"
 	"helm.sh/helm/v4/pkg/getter"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/rest"
 )
 
 var contentCachePath = filepath.Join("helm", "content")
@@ -66,11 +69,14 @@ func ChartFromZarfManifest(manifest v1alpha1.ZarfManifest, manifestPath, package
 	chart := v1alpha1.ZarfChart{
 		Name: tmpChart.Metadata.Name,
 		// Preserve the zarf prefix for chart names to match v0.22.x and earlier behavior.
-		ReleaseName:     fmt.Sprintf("zarf-%s", sha1ReleaseName),
-		Version:         tmpChart.Metadata.Version,
-		Namespace:       manifest.Namespace,
-		NoWait:          manifest.NoWait,
-		ServerSideApply: manifest.GetServerSideApply(),
+		ReleaseName:       fmt.Sprintf("zarf-%s", sha1ReleaseName),
+		Version:           tmpChart.Metadata.Version,
+		Namespace:         manifest.Namespace,
+		NoWait:            manifest.NoWait,
+		ServerSideApply:   manifest.GetServerSideApply(),
+		CommonAnnotations: manifest.CommonAnnotations,
+		CreateNamespace:   manifest.CreateNamespace,
+		NamespaceLabels:   manifest.NamespaceLabels,
 	}
 
 	return chart, tmpChart, nil
@@ -91,6 +97,11 @@ func StandardValuesName(destination string, chart v1alpha1.ZarfChart, idx int) s
 	return fmt.Sprintf("%s-%d", StandardName(destination, chart), idx)
 }
 
+// StandardPostRenderPatchName generates a predictable full path for a post-render patch of a helm chart for zarf
+func StandardPostRenderPatchName(destination string, chart v1alpha1.ZarfChart, idx int) string {
+	return fmt.Sprintf("%s-patch-%d", StandardName(destination, chart), idx)
+}
+
 // loadChartFromTarball returns a helm chart from a tarball.
 func loadChartFromTarball(chart v1alpha1.ZarfChart, chartPath string) (*chartv2.Chart, error) {
 	// Get the path the temporary helm chart tarball
@@ -132,7 +143,7 @@ func parseChartValues(chart v1alpha1.ZarfChart, valuesPath string, valuesOverrid
 	return helpers.MergeMapRecursive(chartValues, valuesOverrides), nil
 }
 
-func createActionConfig(ctx context.Context, namespace string) (*action.Configuration, error) {
+func createActionConfig(ctx context.Context, namespace string, fieldManager string) (*action.Configuration, error) {
 	l := logger.From(ctx)
 	actionConfig := action.NewConfiguration()
 	actionConfig.SetLogger(l.Handler())
@@ -142,9 +153,44 @@ func createActionConfig(ctx context.Context, namespace string) (*action.Configur
 	if l.Enabled(ctx, slog.LevelDebug) {
 		settings.Debug = true
 	}
-	err := actionConfig.Init(settings.RESTClientGetter(), namespace, "")
+	clientGetter := settings.RESTClientGetter()
+	if fieldManager != "" {
+		configFlags, ok := clientGetter.(*genericclioptions.ConfigFlags)
+		if !ok {
+			return nil, fmt.Errorf("unable to set the field manager %q on the Helm client", fieldManager)
+		}
+		wrapConfig := configFlags.WrapConfigFn
+		configFlags.WrapConfigFn = func(config *rest.Config) *rest.Config {
+			if wrapConfig != nil {
+				config = wrapConfig(config)
+			}
+			config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+				return &fieldManagerRoundTripper{fieldManager: fieldManager, wrapped: rt}
+			})
+			return config
+		}
+	}
+	err := actionConfig.Init(clientGetter, namespace, "")
 	if err != nil {
 		return nil, fmt.Errorf("could not get Helm action configuration: %w", err)
 	}
 	return actionConfig, err
 }
+
+// fieldManagerRoundTripper replaces the field manager of the requests that set one. Helm only supports a process wide
+// field manager, so the field manager of a single chart is set on the requests of its client instead.
+type fieldManagerRoundTripper struct {
+	fieldManager string
+	wrapped      http.RoundTripper
+}
+
+func (rt *fieldManagerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	query := req.URL.Query()
+	if !query.Has("fieldManager") {
+		return rt.wrapped.RoundTrip(req)
+	}
+	query.Set("fieldManager", rt.fieldManager)
+	req = req.Clone(req.Context())
+	req.URL.RawQuery = query.Encode()
+	return rt.wrapped.RoundTrip(req)
+}
@@ -8,12 +8,16 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"maps"
+	"os"
 	"slices"
+	"strings"
 
 	"github.com/zarf-dev/zarf/src/pkg/state"
 
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/internal/packager/kustomize"
 	"github.com/zarf-dev/zarf/src/pkg/cluster"
 	"github.com/zarf-dev/zarf/src/pkg/logger"
 	"github.com/zarf-dev/zarf/src/pkg/variables"
@@ -41,6 +45,11 @@ type renderer struct {
 	actionConfig           *action.Configuration
 	variableConfig         *variables.VariableConfig
 
+	// annotations are added to every rendered resource after their values are templated
+	annotations map[string]string
+	// postRenderPatchesPath is the directory holding the packaged post-render patches of the chart
+	postRenderPatchesPath string
+
 	connectStrings    state.ConnectStrings
 	namespaces        map[string]*corev1.Namespace
 	pkgName           string
@@ -77,8 +86,11 @@ func newRenderer(ctx context.Context, chart v1alpha1.ZarfChart, adoptExistingRes
 		return nil, fmt.Errorf("unable to check for existing namespace %q in cluster: %w", rend.chart.Namespace, err)
 	}
 	if kerrors.IsNotFound(err) {
+		if !rend.chart.ShouldCreateNamespace() {
+			return nil, fmt.Errorf("namespace %q of chart %s does not exist and createNamespace is false", rend.chart.Namespace, rend.chart.Name)
+		}
 		rend.namespaces[rend.chart.Namespace] = cluster.NewZarfManagedNamespace(rend.chart.Namespace)
-	} else if rend.adoptExistingResources {
+	} else if rend.adoptExistingResources && rend.chart.ShouldCreateNamespace() {
 		namespace.Labels = cluster.AdoptZarfManagedLabels(namespace.Labels)
 		rend.namespaces[rend.chart.Namespace] = namespace
 	}
@@ -88,6 +100,10 @@ func newRenderer(ctx context.Context, chart v1alpha1.ZarfChart, adoptExistingRes
 
 // Run satisfies the Helm post-renderer interface. It templates the Zarf variables, finds connect strings, adopts namespaces, and applies Zarf state secrets
 func (r *renderer) Run(renderedManifests *bytes.Buffer) (*bytes.Buffer, error) {
+	renderedManifests, err := r.applyPostRenderPatches(renderedManifests)
+	if err != nil {
+		return nil, err
+	}
 	// This is very low cost and consistent for how we replace elsewhere, also good for debugging
 	hooks, resources, err := getTemplatedManifests(renderedManifests, r.variableConfig, r.actionConfig)
 	if err != nil {
@@ -110,6 +126,22 @@ func (r *renderer) Run(renderedManifests *bytes.Buffer) (*bytes.Buffer, error) {
 	return finalManifestsOutput, nil
 }
 
+// applyPostRenderPatches applies the post-render patches of the chart in order to the rendered manifests.
+func (r *renderer) applyPostRenderPatches(renderedManifests *bytes.Buffer) (*bytes.Buffer, error) {
+	manifests := renderedManifests.Bytes()
+	for idx, path := range r.chart.PostRenderPatches {
+		patch, err := os.ReadFile(StandardPostRenderPatchName(r.postRenderPatchesPath, r.chart, idx))
+		if err != nil {
+			return nil, fmt.Errorf("unable to read post-render patch %s of chart %s: %w", path, r.chart.Name, err)
+		}
+		manifests, err = kustomize.Patch(manifests, patch)
+		if err != nil {
+			return nil, fmt.Errorf("unable to apply post-render patch %s of chart %s: %w", path, r.chart.Name, err)
+		}
+	}
+	return bytes.NewBuffer(manifests), nil
+}
+
 func (r *renderer) adoptAndUpdateNamespaces(ctx context.Context) error {
 	l := logger.From(ctx)
 	c := r.cluster
@@ -129,18 +161,23 @@ func (r *renderer) adoptAndUpdateNamespaces(ctx context.Context) error {
 		// If the namespace doesn't exist then create it. If it does exist and is already managed by Zarf then update the labels with
 		// the new package and namespace override labels.
 		if !existingNamespace {
+			if !r.chart.ShouldCreateNamespace() {
+				return fmt.Errorf("namespace %q of chart %s does not exist and createNamespace is false", name, r.chart.Name)
+			}
 			// This is a new namespace, add it
+			r.addNamespaceLabels(namespace)
 			_, err := c.Clientset.CoreV1().Namespaces().Create(ctx, namespace, metav1.CreateOptions{})
 			if err != nil {
 				return fmt.Errorf("unable to create the missing namespace %s", name)
 			}
-		} else if r.adoptExistingResources {
+		} else if r.adoptExistingResources && r.chart.ShouldCreateNamespace() {
 			// Refuse to adopt namespace if it is one of four initial Kubernetes namespaces.
 			// https://kubernetes.io/docs/concepts/overview/working-with-objects/namespaces/#initial-namespaces
 			if slices.Contains([]string{"default", "kube-node-lease", "kube-public", "kube-system"}, name) {
 				l.Warn("refusing to adopt initial namespace", "name", name)
 			} else {
 				// This is an existing namespace to adopt
+				r.addNamespaceLabels(namespace)
 				_, err := c.Clientset.CoreV1().Namespaces().Update(ctx, namespace, metav1.UpdateOptions{})
 				if err != nil {
 					return fmt.Errorf("unable to adopt the existing namespace %s", name)
@@ -178,6 +215,18 @@ func (r *renderer) adoptAndUpdateNamespaces(ctx context.Context) error {
 	return nil
 }
 
+// addNamespaceLabels adds the namespace labels of the chart to its namespace, keeping the labels Zarf manages.
+func (r *renderer) addNamespaceLabels(namespace *corev1.Namespace) {
+	if namespace.Name != r.chart.Namespace || len(r.chart.NamespaceLabels) == 0 {
+		return
+	}
+	if namespace.Labels == nil {
+		namespace.Labels = map[string]string{}
+	}
+	maps.Copy(namespace.Labels, r.chart.NamespaceLabels)
+	namespace.Labels = cluster.AdoptZarfManagedLabels(namespace.Labels)
+}
+
 func (r *renderer) shouldAddAgentIgnoreLabels() bool {
 	return r.connectedDeploy && r.state != nil && r.state.AgentIsConfigured()
 }
@@ -193,6 +242,7 @@ func (r *renderer) editHelmResources(ctx context.Context, resources []releaseuti
 		return err
 	}
 	mapper := restmapper.NewDiscoveryRESTMapper(groupResources)
+	annotations := templateAnnotations(r.annotations, r.variableConfig)
 
 	for _, resource := range resources {
 		// parse to unstructured to have access to more data than just the name
@@ -203,13 +253,14 @@ func (r *renderer) editHelmResources(ctx context.Context, resources []releaseuti
 				labels = map[string]string{}
 			}
 			obj.SetLabels(r.setPackageLabels(labels))
+			addAnnotations(obj, annotations)
 			// Add the package label to pod templates (for Deployments, StatefulSets, etc.)
 			if err := r.addLabelsToNestedPath(obj, []string{"spec", "template", "metadata", "labels"}); err != nil {
 				return fmt.Errorf("failed to add labels to pod template: %w", err)
 			}
 			// In connected or YOLO mode, add agent ignore labels so the webhook doesn't mutate resources
 			if r.shouldAddAgentIgnoreLabels() {
-				if err := addAgentIgnoreLabels(obj); err != nil {
+				if err := addAgentIgnoreLabels(obj, r.state.AgentImageFields); err != nil {
 					return err
 				}
 			}
@@ -356,8 +407,16 @@ var agentMutatedKinds = map[schema.GroupKind][][]string{
 	{Group: "", Kind: "Secret"}:                                 {{"metadata", "labels"}},
 }
 
-func addAgentIgnoreLabels(obj *unstructured.Unstructured) error {
-	labelPaths, ok := agentMutatedKinds[obj.GroupVersionKind().GroupKind()]
+// addAgentIgnoreLabels sets the ignore label on resources the agent mutates, including the custom resources whose
+// image fields are configured in the Zarf state.
+func addAgentIgnoreLabels(obj *unstructured.Unstructured, imageFields []state.AgentImageField) error {
+	gvk := obj.GroupVersionKind()
+	labelPaths, ok := agentMutatedKinds[gvk.GroupKind()]
+	if !ok && slices.ContainsFunc(imageFields, func(f state.AgentImageField) bool {
+		return f.Matches(gvk.Group, gvk.Version, gvk.Kind)
+	}) {
+		labelPaths, ok = [][]string{{"metadata", "labels"}}, true
+	}
 	if !ok {
 		return nil
 	}
@@ -385,6 +444,46 @@ func addAgentIgnoreLabels(obj *unstructured.Unstructured) error {
 	return nil
 }
 
+// mergeAnnotations returns the component annotations overridden by the annotations of the chart.
+func mergeAnnotations(componentAnnotations, chartAnnotations map[string]string) map[string]string {
+	if len(componentAnnotations) == 0 && len(chartAnnotations) == 0 {
+		return nil
+	}
+	annotations := map[string]string{}
+	maps.Copy(annotations, componentAnnotations)
+	maps.Copy(annotations, chartAnnotations)
+	return annotations
+}
+
+// templateAnnotations replaces the variables and constants in the annotation values.
+func templateAnnotations(annotations map[string]string, variableConfig *variables.VariableConfig) map[string]string {
+	if len(annotations) == 0 {
+		return nil
+	}
+	templates := variableConfig.GetAllTemplates()
+	templated := make(map[string]string, len(annotations))
+	for key, value := range annotations {
+		for name, template := range templates {
+			value = strings.ReplaceAll(value, name, template.Value)
+		}
+		templated[key] = value
+	}
+	return templated
+}
+
+// addAnnotations sets the annotations on the object, replacing the values of existing annotations with the same key.
+func addAnnotations(obj *unstructured.Unstructured, annotations map[string]string) {
+	if len(annotations) == 0 {
+		return
+	}
+	existing := obj.GetAnnotations()
+	if existing == nil {
+		existing = map[string]string{}
+	}
+	maps.Copy(existing, annotations)
+	obj.SetAnnotations(existing)
+}
+
 // setPackageLabels will add the package labels to an existing labels map
 func (r *renderer) setPackageLabels(labels map[string]string) map[string]string {
 	if r.pkgName != "" {
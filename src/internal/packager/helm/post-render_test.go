@@ -4,18 +4,30 @@
 package helm
 
 import (
+	"bytes"
+	"context"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
 	"testing"
 
+	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/pkg/cluster"
 	"github.com/zarf-dev/zarf/src/pkg/pki"
 	"github.com/zarf-dev/zarf/src/pkg/state"
+	"github.com/zarf-dev/zarf/src/pkg/variables"
+	"helm.sh/helm/v4/pkg/action"
+	releaseutil "helm.sh/helm/v4/pkg/release/v1/util"
 	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
 	"sigs.k8s.io/yaml"
 )
 
@@ -414,7 +426,7 @@ func TestAddAgentIgnoreLabels(t *testing.T) {
 			// Capture pre-existing top-level labels for preservation check
 			preLabels := tt.obj.GetLabels()
 
-			err := addAgentIgnoreLabels(tt.obj)
+			err := addAgentIgnoreLabels(tt.obj, nil)
 			require.NoError(t, err)
 
 			// Verify existing top-level labels are preserved
@@ -440,6 +452,31 @@ func TestAddAgentIgnoreLabels(t *testing.T) {
 	}
 }
 
+func TestAddAgentIgnoreLabelsCustomResource(t *testing.T) {
+	t.Parallel()
+
+	imageFields := []state.AgentImageField{{Group: "monitoring.coreos.com", Version: "v1", Kind: "Prometheus", Paths: []string{".spec.image"}}}
+	newPrometheus := func(apiVersion string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": apiVersion,
+			"kind":       "Prometheus",
+			"metadata":   map[string]interface{}{"name": "prometheus"},
+		}}
+	}
+
+	obj := newPrometheus("monitoring.coreos.com/v1")
+	require.NoError(t, addAgentIgnoreLabels(obj, imageFields))
+	require.Equal(t, "ignore", obj.GetLabels()["zarf.dev/agent"])
+
+	obj = newPrometheus("monitoring.coreos.com/v1alpha1")
+	require.NoError(t, addAgentIgnoreLabels(obj, imageFields))
+	require.Empty(t, obj.GetLabels())
+
+	obj = newPrometheus("monitoring.coreos.com/v1")
+	require.NoError(t, addAgentIgnoreLabels(obj, nil))
+	require.Empty(t, obj.GetLabels())
+}
+
 func TestAgentMutatedKindsMatchesWebhook(t *testing.T) {
 	t.Parallel()
 
@@ -464,6 +501,8 @@ func TestAgentMutatedKindsMatchesWebhook(t *testing.T) {
 	// Update this when adding a new resource to webhook.yaml.
 	resourceToKind := map[string]string{
 		"pods":             "Pod",
+		"jobs":             "Job",
+		"cronjobs":         "CronJob",
 		"secrets":          "Secret",
 		"gitrepositories":  "GitRepository",
 		"ocirepositories":  "OCIRepository",
@@ -528,3 +567,277 @@ func TestProcessManifestContentEmptyObject(t *testing.T) {
 	require.NotNil(t, rawData)
 	require.Empty(t, rawData.Object)
 }
+
+func TestRendererResourceAnnotations(t *testing.T) {
+	t.Parallel()
+
+	manifestDir := t.TempDir()
+	configMap := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: podinfo-config
+  namespace: podinfo
+data:
+  key: value
+`
+	require.NoError(t, os.WriteFile(filepath.Join(manifestDir, "configmap.yaml"), []byte(configMap), 0o600))
+
+	component := v1alpha1.ZarfComponent{
+		Name: "podinfo",
+		ResourceAnnotations: map[string]string{
+			"argocd.argoproj.io/compare-options": "IgnoreExtraneous",
+			"zarf.dev/team":                      "###ZARF_VAR_TEAM###",
+		},
+		Charts: []v1alpha1.ZarfChart{
+			{
+				Name:              "podinfo",
+				Namespace:         "podinfo",
+				CommonAnnotations: map[string]string{"zarf.dev/team": "chart-team"},
+			},
+		},
+		Manifests: []v1alpha1.ZarfManifest{
+			{
+				Name:              "podinfo-config",
+				Namespace:         "podinfo",
+				Files:             []string{"configmap.yaml"},
+				CommonAnnotations: map[string]string{"zarf.dev/owner": "###ZARF_CONST_OWNER###"},
+			},
+		},
+	}
+	manifestChart, _, err := ChartFromZarfManifest(component.Manifests[0], manifestDir, "test", component.Name)
+	require.NoError(t, err)
+
+	vc := variables.New("zarf", nil, nil)
+	vc.SetVariable("TEAM", "platform", false, false, "")
+	vc.SetConstants([]v1alpha1.Constant{{Name: "OWNER", Value: "sre"}})
+	c := &cluster.Cluster{Clientset: fake.NewClientset(), RestConfig: &rest.Config{}}
+
+	deployment := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: podinfo
+  namespace: podinfo
+  annotations:
+    zarf.dev/team: from-template
+    existing: kept
+`
+	tests := []struct {
+		name     string
+		chart    v1alpha1.ZarfChart
+		content  string
+		expected map[string]string
+	}{
+		{
+			name:    "chart",
+			chart:   component.Charts[0],
+			content: deployment,
+			expected: map[string]string{
+				"argocd.argoproj.io/compare-options": "IgnoreExtraneous",
+				"zarf.dev/team":                      "chart-team",
+				"existing":                           "kept",
+			},
+		},
+		{
+			name:    "manifest",
+			chart:   manifestChart,
+			content: configMap,
+			expected: map[string]string{
+				"argocd.argoproj.io/compare-options": "IgnoreExtraneous",
+				"zarf.dev/team":                      "platform",
+				"zarf.dev/owner":                     "sre",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r := &renderer{
+				chart:          tt.chart,
+				cluster:        c,
+				variableConfig: vc,
+				connectStrings: state.ConnectStrings{},
+				namespaces:     map[string]*corev1.Namespace{},
+				pkgName:        "test",
+				annotations:    mergeAnnotations(component.ResourceAnnotations, tt.chart.CommonAnnotations),
+			}
+			out := bytes.NewBuffer(nil)
+			err := r.editHelmResources(context.Background(), []releaseutil.Manifest{{Name: "resource.yaml", Content: tt.content}}, out)
+			require.NoError(t, err)
+
+			obj := &unstructured.Unstructured{}
+			require.NoError(t, yaml.Unmarshal(out.Bytes(), obj))
+			require.Equal(t, tt.expected, obj.GetAnnotations())
+		})
+	}
+}
+
+func TestRendererPostRenderPatches(t *testing.T) {
+	t.Parallel()
+
+	rendered := `---
+# Source: podinfo/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: podinfo
+  namespace: podinfo
+  annotations:
+    postrenderer.helm.sh/postrender-filename: templates/deployment.yaml
+spec:
+  template:
+    spec:
+      containers:
+      - name: podinfo
+        image: ghcr.io/stefanprodan/podinfo:6.4.0
+      volumes:
+      - name: host
+        hostPath:
+          path: /var/run
+      - name: data
+        emptyDir: {}
+`
+	dropHostPath := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: podinfo
+  namespace: podinfo
+spec:
+  template:
+    spec:
+      volumes:
+      - name: host
+        $patch: delete
+`
+	addLabel := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: podinfo
+  namespace: podinfo
+  labels:
+    team: "###ZARF_VAR_TEAM###"
+`
+	missing := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: missing
+  namespace: podinfo
+spec:
+  replicas: 2
+`
+
+	tests := []struct {
+		name        string
+		patches     map[string]string
+		expectedErr string
+	}{
+		{
+			name:    "no patches",
+			patches: map[string]string{},
+		},
+		{
+			name: "patches are applied in order",
+			patches: map[string]string{
+				"patches/drop-host-path.yaml": dropHostPath,
+				"patches/add-label.yaml":      addLabel,
+			},
+		},
+		{
+			name: "patch of a missing object names the patch",
+			patches: map[string]string{
+				"patches/missing.yaml": missing,
+			},
+			expectedErr: "unable to apply post-render patch patches/missing.yaml of chart podinfo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			chart := v1alpha1.ZarfChart{Name: "podinfo", Version: "6.4.0", Namespace: "podinfo"}
+			patchesPath := t.TempDir()
+			for _, path := range []string{"patches/drop-host-path.yaml", "patches/add-label.yaml", "patches/missing.yaml"} {
+				patch, ok := tt.patches[path]
+				if !ok {
+					continue
+				}
+				require.NoError(t, os.WriteFile(StandardPostRenderPatchName(patchesPath, chart, len(chart.PostRenderPatches)), []byte(patch), 0o600))
+				chart.PostRenderPatches = append(chart.PostRenderPatches, path)
+			}
+			r := &renderer{chart: chart, postRenderPatchesPath: patchesPath}
+
+			out, err := r.applyPostRenderPatches(bytes.NewBufferString(rendered))
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			if len(tt.patches) == 0 {
+				require.Equal(t, rendered, out.String())
+				return
+			}
+
+			obj := &unstructured.Unstructured{}
+			require.NoError(t, yaml.Unmarshal(out.Bytes(), obj))
+			volumes, _, err := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "volumes")
+			require.NoError(t, err)
+			require.Equal(t, []any{map[string]any{"name": "data", "emptyDir": map[string]any{}}}, volumes)
+			// Zarf variables in patches are templated with the rest of the chart
+			require.Equal(t, map[string]string{"team": "###ZARF_VAR_TEAM###"}, obj.GetLabels())
+			require.Equal(t, "templates/deployment.yaml", obj.GetAnnotations()["postrenderer.helm.sh/postrender-filename"])
+		})
+	}
+}
+
+func TestRendererCreateNamespace(t *testing.T) {
+	t.Parallel()
+
+	provisioned := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "provisioned", Labels: map[string]string{"owner": "controller"}}}
+	tests := []struct {
+		name           string
+		chart          v1alpha1.ZarfChart
+		adopt          bool
+		expectedErr    string
+		expectedLabels map[string]string
+	}{
+		{
+			name:           "missing namespace is created with its labels",
+			chart:          v1alpha1.ZarfChart{Name: "podinfo", Namespace: "podinfo", NamespaceLabels: map[string]string{"team": "web", state.ZarfManagedByLabel: "someone"}},
+			expectedLabels: map[string]string{"team": "web", state.ZarfManagedByLabel: "zarf"},
+		},
+		{
+			name:        "missing namespace fails when it is not created",
+			chart:       v1alpha1.ZarfChart{Name: "podinfo", Namespace: "podinfo", CreateNamespace: helpers.BoolPtr(false)},
+			expectedErr: `namespace "podinfo" of chart podinfo does not exist and createNamespace is false`,
+		},
+		{
+			name:           "existing namespace is adopted with its labels",
+			chart:          v1alpha1.ZarfChart{Name: "podinfo", Namespace: "provisioned", NamespaceLabels: map[string]string{"team": "web"}},
+			adopt:          true,
+			expectedLabels: map[string]string{"owner": "controller", "team": "web", state.ZarfManagedByLabel: "zarf"},
+		},
+		{
+			name:           "existing namespace is not adopted when it is not created",
+			chart:          v1alpha1.ZarfChart{Name: "podinfo", Namespace: "provisioned", CreateNamespace: helpers.BoolPtr(false)},
+			adopt:          true,
+			expectedLabels: map[string]string{"owner": "controller"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := context.Background()
+			c := &cluster.Cluster{Clientset: fake.NewClientset(provisioned.DeepCopy())}
+			vc := variables.New("zarf", nil, nil)
+			r, err := newRenderer(ctx, tt.chart, tt.adopt, c, false, &state.State{}, &action.Configuration{}, vc, "test", "")
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, r.adoptAndUpdateNamespaces(ctx))
+
+			namespace, err := c.Clientset.CoreV1().Namespaces().Get(ctx, tt.chart.Namespace, metav1.GetOptions{})
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedLabels, namespace.Labels)
+		})
+	}
+}
@@ -5,11 +5,13 @@
 package helm
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -150,7 +152,9 @@ func PackageChartFromGit(ctx context.Context, chart v1alpha1.ZarfChart, chartPat
 	return PackageChartFromLocalFiles(ctx, chart, chartPath, valuesPath, cachePath)
 }
 
-// DownloadPublishedChart loads a specific chart version from a remote repo.
+// DownloadPublishedChart loads a specific chart version from a remote repo. The certificate files of the repository host
+// in remoteOptions.ChartRepoTLS are used for repositories that require mutual TLS or a custom CA, taking precedence over
+// the files configured for the repo in the Helm repository config.
 func DownloadPublishedChart(ctx context.Context, chart v1alpha1.ZarfChart, chartPath, valuesPath, cachePath string, remoteOptions types.RemoteOptions) error {
 	l := logger.From(ctx)
 	start := time.Now()
@@ -182,9 +186,21 @@ func DownloadPublishedChart(ctx context.Context, chart v1alpha1.ZarfChart, chart
 	var username string
 	var password string
 
+	// Explicit credentials on the chart take precedence over helm registry login and the Helm repository config
+	explicitUsername, explicitPassword, err := chartCredentials(chart)
+	if err != nil {
+		return err
+	}
+
 	// Handle OCI registries
 	if registry.IsOCI(chart.URL) {
-		regClient, err = registry.NewClient(registry.ClientOptEnableCache(true))
+		clientOpts := []registry.ClientOption{registry.ClientOptEnableCache(true)}
+		if explicitUsername != "" {
+			username = explicitUsername
+			password = explicitPassword
+			clientOpts = append(clientOpts, registry.ClientOptBasicAuth(username, password))
+		}
+		regClient, err = registry.NewClient(clientOpts...)
 		if err != nil {
 			return fmt.Errorf("unable to create the new registry client: %w", err)
 		}
@@ -204,9 +220,31 @@ func DownloadPublishedChart(ctx context.Context, chart v1alpha1.ZarfChart, chart
 				if repo.URL == chart.URL {
 					username = repo.Username
 					password = repo.Password
+					pull.CertFile = repo.CertFile
+					pull.KeyFile = repo.KeyFile
+					pull.CaFile = repo.CAFile
 				}
 			}
 		}
+		if explicitUsername != "" {
+			username = explicitUsername
+			password = explicitPassword
+		}
+		if repoTLS, ok := chartRepoTLS(chart.URL, remoteOptions.ChartRepoTLS); ok {
+			if repoTLS.CertFile != "" {
+				pull.CertFile = repoTLS.CertFile
+				pull.KeyFile = repoTLS.KeyFile
+			}
+			if repoTLS.CAFile != "" {
+				pull.CaFile = repoTLS.CAFile
+			}
+		}
+		// Only log whether client TLS is used, the certificate paths are specific to the build machine
+		l.Debug("resolved Helm repository TLS configuration",
+			"repo", chart.URL,
+			"clientCert", pull.CertFile != "",
+			"customCA", pull.CaFile != "",
+		)
 
 		chartURL, err = repov1.FindChartInRepoURL(
 			chart.URL,
@@ -236,6 +274,7 @@ func DownloadPublishedChart(ctx context.Context, chart v1alpha1.ZarfChart, chart
 			getter.WithPlainHTTP(remoteOptions.PlainHTTP),
 			getter.WithInsecureSkipVerifyTLS(remoteOptions.InsecureSkipTLSVerify),
 			getter.WithBasicAuth(username, password),
+			getter.WithTLSClientConfig(pull.CertFile, pull.KeyFile, pull.CaFile),
 		},
 	}
 
@@ -288,6 +327,18 @@ func DownloadPublishedChart(ctx context.Context, chart v1alpha1.ZarfChart, chart
 	return nil
 }
 
+// chartCredentials returns the username and password set on the chart, reading the password from its environment variable.
+func chartCredentials(chart v1alpha1.ZarfChart) (string, string, error) {
+	if chart.Username == "" {
+		return "", "", nil
+	}
+	password := os.Getenv(chart.PasswordEnv)
+	if password == "" {
+		return "", "", fmt.Errorf("the password environment variable %s for chart %q is not set", chart.PasswordEnv, chart.Name)
+	}
+	return chart.Username, password, nil
+}
+
 // DownloadChartFromGitToTemp downloads a chart from git into a temp directory
 func DownloadChartFromGitToTemp(ctx context.Context, url string) (string, error) {
 	path, err := utils.MakeTempDir(config.CommonOptions.TempDirectory)
@@ -313,9 +364,62 @@ func finalizeChartPackage(ctx context.Context, chart v1alpha1.ZarfChart, chartPa
 	if err != nil {
 		return fmt.Errorf("unable to process the values for the package: %w", err)
 	}
+
+	err = packagePostRenderPatches(chart, valuesPath)
+	if err != nil {
+		return err
+	}
+
+	err = validatePackagedValues(ctx, chart, destinationTarball, valuesPath)
+	if err != nil {
+		return err
+	}
 	return nil
 }
 
+// validatePackagedValues checks the values files of the chart against the values schema of the chart at create time.
+// Values files with Zarf variables or constants are only known at deploy time so they are checked during the deploy.
+func validatePackagedValues(ctx context.Context, chart v1alpha1.ZarfChart, chartTarball, valuesPath string) error {
+	if len(chart.ValuesFiles) == 0 || !chart.ShouldRunSchemaValidation() {
+		return nil
+	}
+	for valuesIdx := range chart.ValuesFiles {
+		b, err := os.ReadFile(StandardValuesName(valuesPath, chart, valuesIdx))
+		if err != nil {
+			return err
+		}
+		if bytes.Contains(b, []byte("###ZARF_")) {
+			logger.From(ctx).Debug("skipping values schema validation of templated values files", "chart", chart.Name)
+			return nil
+		}
+	}
+	helmChart, err := loader.LoadFile(chartTarball)
+	if err != nil {
+		return fmt.Errorf("unable to load the chart from %s: %w", chartTarball, err)
+	}
+	if !hasValuesSchema(helmChart) {
+		return nil
+	}
+	values, err := parseChartValues(chart, valuesPath, nil)
+	if err != nil {
+		return fmt.Errorf("unable to parse chart values: %w", err)
+	}
+	return ValidateChartValues(chart, helmChart, values)
+}
+
+// hasValuesSchema returns true if the chart or any of its dependencies has a values schema.
+func hasValuesSchema(chart *chartv2.Chart) bool {
+	if len(chart.Schema) > 0 {
+		return true
+	}
+	for _, dep := range chart.Dependencies() {
+		if hasValuesSchema(dep) {
+			return true
+		}
+	}
+	return false
+}
+
 func packageValues(ctx context.Context, chart v1alpha1.ZarfChart, valuesPath string) error {
 	for valuesIdx, path := range chart.ValuesFiles {
 		dst := StandardValuesName(valuesPath, chart, valuesIdx)
@@ -334,6 +438,16 @@ func packageValues(ctx context.Context, chart v1alpha1.ZarfChart, valuesPath str
 	return nil
 }
 
+// packagePostRenderPatches copies the post-render patches of the chart next to its values files.
+func packagePostRenderPatches(chart v1alpha1.ZarfChart, valuesPath string) error {
+	for patchIdx, path := range chart.PostRenderPatches {
+		if err := helpers.CreatePathAndCopy(path, StandardPostRenderPatchName(valuesPath, chart, patchIdx)); err != nil {
+			return fmt.Errorf("unable to copy chart post-render patch %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
 // buildChartDependencies builds the helm chart dependencies
 func buildChartDependencies(ctx context.Context, chart v1alpha1.ZarfChart, cachePath string) error {
 	l := logger.From(ctx)
@@ -394,3 +508,20 @@ func loadAndValidateChart(location string) (loader.ChartLoader, *chartv2.Chart,
 
 	return cl, chart, nil
 }
+
+// chartRepoTLS returns the certificate files configured for the host of the chart repository at repoURL.
+func chartRepoTLS(repoURL string, repoTLS map[string]types.ClientTLSOptions) (types.ClientTLSOptions, bool) {
+	if len(repoTLS) == 0 {
+		return types.ClientTLSOptions{}, false
+	}
+	u, err := url.Parse(repoURL)
+	if err != nil || u.Host == "" {
+		return types.ClientTLSOptions{}, false
+	}
+	for host, opts := range repoTLS {
+		if strings.EqualFold(host, u.Host) {
+			return opts, true
+		}
+	}
+	return types.ClientTLSOptions{}, false
+}
@@ -0,0 +1,210 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package helm contains operations for working with helm charts.
+package helm
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/pkg/pki"
+	"github.com/zarf-dev/zarf/src/types"
+	"helm.sh/helm/v4/pkg/chart/v2/loader"
+	chartutil "helm.sh/helm/v4/pkg/chart/v2/util"
+	"helm.sh/helm/v4/pkg/provenance"
+	repov1 "helm.sh/helm/v4/pkg/repo/v1"
+)
+
+func TestDownloadPublishedChartClientTLS(t *testing.T) {
+	serverPKI, clientPKI, err := pki.GenerateMTLSCerts("Zarf Test CA", nil, "127.0.0.1", "zarf-client")
+	require.NoError(t, err)
+
+	tmpDir := t.TempDir()
+	caFile := filepath.Join(tmpDir, "ca.pem")
+	certFile := filepath.Join(tmpDir, "client.pem")
+	keyFile := filepath.Join(tmpDir, "client-key.pem")
+	require.NoError(t, os.WriteFile(caFile, clientPKI.CA, 0o600))
+	require.NoError(t, os.WriteFile(certFile, clientPKI.Cert, 0o600))
+	require.NoError(t, os.WriteFile(keyFile, clientPKI.Key, 0o600))
+
+	// Serve a chart repository that only accepts clients presenting a certificate signed by the CA
+	repoDir := t.TempDir()
+	helmChart, err := loader.Load(filepath.Join("testdata", "template", "simple-chart"))
+	require.NoError(t, err)
+	tarball, err := chartutil.Save(helmChart, repoDir)
+	require.NoError(t, err)
+	digest, err := provenance.DigestFile(tarball)
+	require.NoError(t, err)
+	index := repov1.NewIndexFile()
+	require.NoError(t, index.MustAdd(helmChart.Metadata, filepath.Base(tarball), "", digest))
+	require.NoError(t, index.WriteFile(filepath.Join(repoDir, "index.yaml"), 0o644))
+
+	serverCert, err := tls.X509KeyPair(serverPKI.Cert, serverPKI.Key)
+	require.NoError(t, err)
+	clientCAs := x509.NewCertPool()
+	require.True(t, clientCAs.AppendCertsFromPEM(serverPKI.CA))
+	srv := httptest.NewUnstartedServer(http.FileServer(http.Dir(repoDir)))
+	srv.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	}
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+
+	t.Setenv("HELM_REPOSITORY_CONFIG", filepath.Join(tmpDir, "repositories.yaml"))
+	t.Setenv("HELM_REPOSITORY_CACHE", filepath.Join(tmpDir, "repository"))
+
+	chart := v1alpha1.ZarfChart{
+		Name:    "simple-chart",
+		Version: "1.0.0",
+		URL:     srv.URL,
+	}
+
+	srvURL, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	t.Run("client certificate", func(t *testing.T) {
+		outDir := t.TempDir()
+		remoteOpts := types.RemoteOptions{ChartRepoTLS: map[string]types.ClientTLSOptions{
+			srvURL.Host: {CertFile: certFile, KeyFile: keyFile, CAFile: caFile},
+		}}
+		err := DownloadPublishedChart(context.Background(), chart, outDir, outDir, outDir, remoteOpts)
+		require.NoError(t, err)
+		require.FileExists(t, StandardName(outDir, chart)+".tgz")
+	})
+
+	t.Run("missing client certificate", func(t *testing.T) {
+		outDir := t.TempDir()
+		remoteOpts := types.RemoteOptions{ChartRepoTLS: map[string]types.ClientTLSOptions{
+			srvURL.Host: {CAFile: caFile},
+		}}
+		err := DownloadPublishedChart(context.Background(), chart, outDir, outDir, outDir, remoteOpts)
+		require.Error(t, err)
+	})
+
+	t.Run("client certificate of another host", func(t *testing.T) {
+		outDir := t.TempDir()
+		remoteOpts := types.RemoteOptions{ChartRepoTLS: map[string]types.ClientTLSOptions{
+			"charts.example.com": {CertFile: certFile, KeyFile: keyFile, CAFile: caFile},
+		}}
+		err := DownloadPublishedChart(context.Background(), chart, outDir, outDir, outDir, remoteOpts)
+		require.Error(t, err)
+	})
+}
+
+func TestDownloadPublishedChartCredentials(t *testing.T) {
+	// Serve a chart repository that requires basic auth
+	repoDir := t.TempDir()
+	helmChart, err := loader.Load(filepath.Join("testdata", "template", "simple-chart"))
+	require.NoError(t, err)
+	tarball, err := chartutil.Save(helmChart, repoDir)
+	require.NoError(t, err)
+	digest, err := provenance.DigestFile(tarball)
+	require.NoError(t, err)
+	index := repov1.NewIndexFile()
+	require.NoError(t, index.MustAdd(helmChart.Metadata, filepath.Base(tarball), "", digest))
+	require.NoError(t, index.WriteFile(filepath.Join(repoDir, "index.yaml"), 0o644))
+	fileServer := http.FileServer(http.Dir(repoDir))
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		username, password, ok := req.BasicAuth()
+		if !ok || username != "robot" || password != "secret" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fileServer.ServeHTTP(rw, req)
+	}))
+	t.Cleanup(srv.Close)
+
+	// The Helm repository config holds stale credentials for the repo
+	tmpDir := t.TempDir()
+	repoFile := repov1.NewFile()
+	repoFile.Add(&repov1.Entry{Name: "stale", URL: srv.URL, Username: "robot", Password: "stale"})
+	require.NoError(t, repoFile.WriteFile(filepath.Join(tmpDir, "repositories.yaml"), 0o600))
+	t.Setenv("HELM_REPOSITORY_CONFIG", filepath.Join(tmpDir, "repositories.yaml"))
+	t.Setenv("HELM_REPOSITORY_CACHE", filepath.Join(tmpDir, "repository"))
+
+	chart := v1alpha1.ZarfChart{
+		Name:    "simple-chart",
+		Version: "1.0.0",
+		URL:     srv.URL,
+	}
+
+	t.Run("ambient credentials", func(t *testing.T) {
+		outDir := t.TempDir()
+		err := DownloadPublishedChart(context.Background(), chart, outDir, outDir, outDir, types.RemoteOptions{})
+		require.ErrorContains(t, err, "401 Unauthorized")
+	})
+
+	t.Run("explicit credentials take precedence", func(t *testing.T) {
+		t.Setenv("CHART_REPO_PASSWORD", "secret")
+		outDir := t.TempDir()
+		chart := chart
+		chart.Username = "robot"
+		chart.PasswordEnv = "CHART_REPO_PASSWORD"
+		err := DownloadPublishedChart(context.Background(), chart, outDir, outDir, outDir, types.RemoteOptions{})
+		require.NoError(t, err)
+		require.FileExists(t, StandardName(outDir, chart)+".tgz")
+	})
+
+	t.Run("unset password environment variable", func(t *testing.T) {
+		outDir := t.TempDir()
+		chart := chart
+		chart.Username = "robot"
+		chart.PasswordEnv = "CHART_REPO_PASSWORD_UNSET"
+		err := DownloadPublishedChart(context.Background(), chart, outDir, outDir, outDir, types.RemoteOptions{})
+		require.EqualError(t, err, `the password environment variable CHART_REPO_PASSWORD_UNSET for chart "simple-chart" is not set`)
+	})
+}
+
+func TestPackageChartValidatesValuesSchema(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		valuesFile  string
+		errContains string
+	}{
+		{
+			name:       "valid values file",
+			valuesFile: "good-values.yaml",
+		},
+		{
+			name:        "values file with the wrong type",
+			valuesFile:  "bad-values.yaml",
+			errContains: "replicaCount",
+		},
+		{
+			name:       "templated values file is checked at deploy time",
+			valuesFile: "templated-values.yaml",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			chart := v1alpha1.ZarfChart{
+				Name:        "schema-chart",
+				Version:     "1.0.0",
+				LocalPath:   filepath.Join("testdata", "values-schema", "schema-chart"),
+				ValuesFiles: []string{filepath.Join("testdata", "values-schema", tt.valuesFile)},
+			}
+			err := PackageChartFromLocalFiles(t.Context(), chart, t.TempDir(), t.TempDir(), t.TempDir())
+			if tt.errContains != "" {
+				require.ErrorContains(t, err, tt.errContains)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
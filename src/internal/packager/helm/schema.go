@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package helm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/pkg/variables"
+	"github.com/zarf-dev/zarf/src/types"
+	"helm.sh/helm/v4/pkg/chart/common"
+	chartv2 "helm.sh/helm/v4/pkg/chart/v2"
+	releasev1 "helm.sh/helm/v4/pkg/release/v1"
+	releaseutil "helm.sh/helm/v4/pkg/release/v1/util"
+	"k8s.io/kubectl/pkg/validation"
+)
+
+// ValidateChartSchema renders the chart without installing it and checks the rendered resources against the schema.
+// The returned error lists every resource that does not match the schema.
+func ValidateChartSchema(ctx context.Context, zarfChart v1alpha1.ZarfChart, chart *chartv2.Chart, values common.Values,
+	kubeVersion string, variableConfig *variables.VariableConfig, validator validation.Schema) error {
+	manifest, err := TemplateChart(ctx, zarfChart, chart, values, kubeVersion, variableConfig, false, types.RemoteOptions{})
+	if err != nil {
+		return err
+	}
+	hooks, resources, err := releaseutil.SortManifests(manifestsBySource(manifest), nil, releaseutil.InstallOrder)
+	if err != nil {
+		return fmt.Errorf("unable to split the rendered manifests of chart %s: %w", zarfChart.Name, err)
+	}
+	return validateResources(validator, hooks, resources)
+}
+
+// manifestsBySource splits a rendered manifest into its documents keyed by their first "# Source:" comment, so that
+// violations point at the template of the chart rather than the position of the document.
+func manifestsBySource(manifest string) map[string]string {
+	files := map[string]string{}
+	for name, content := range releaseutil.SplitManifests(manifest) {
+		for _, line := range strings.Split(content, "\n") {
+			if source, ok := strings.CutPrefix(line, "# Source: "); ok {
+				name = source
+				break
+			}
+		}
+		if existing, ok := files[name]; ok {
+			content = existing + "\n---\n" + content
+		}
+		files[name] = content
+	}
+	return files
+}
+
+// validateResources checks every rendered hook and resource against the schema and returns an error listing all violations.
+func validateResources(validator validation.Schema, hooks []*releasev1.Hook, resources []releaseutil.Manifest) error {
+	violations := []string{}
+	for _, hook := range hooks {
+		if err := validator.ValidateBytes([]byte(hook.Manifest)); err != nil {
+			violations = append(violations, fmt.Sprintf("%s: %s", hook.Path, err))
+		}
+	}
+	for _, resource := range resources {
+		if err := validator.ValidateBytes([]byte(resource.Content)); err != nil {
+			violations = append(violations, fmt.Sprintf("%s: %s", resource.Name, err))
+		}
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("%d resource(s) do not match the cluster schema:\n - %s", len(violations), strings.Join(violations, "\n - "))
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package helm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	openapiv2 "github.com/google/gnostic-models/openapiv2"
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/internal/packager/template"
+	"github.com/zarf-dev/zarf/src/test/testutil"
+	"helm.sh/helm/v4/pkg/chart/common"
+	"helm.sh/helm/v4/pkg/chart/v2/loader"
+	releasev1 "helm.sh/helm/v4/pkg/release/v1"
+	releaseutil "helm.sh/helm/v4/pkg/release/v1/util"
+	"k8s.io/kubectl/pkg/util/openapi"
+	"k8s.io/kubectl/pkg/validation"
+)
+
+type testOpenAPIResources struct {
+	path string
+}
+
+func (r testOpenAPIResources) OpenAPISchema() (openapi.Resources, error) {
+	b, err := os.ReadFile(r.path)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := openapiv2.ParseDocument(b)
+	if err != nil {
+		return nil, err
+	}
+	return openapi.NewOpenAPIData(doc)
+}
+
+func TestValidateResources(t *testing.T) {
+	t.Parallel()
+
+	validator := validation.NewSchemaValidation(testOpenAPIResources{path: filepath.Join("testdata", "openapi", "swagger.json")})
+
+	validConfigMap := releaseutil.Manifest{
+		Name: "chart/templates/valid.yaml",
+		Content: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: valid
+data:
+  key: value
+`,
+	}
+	unknownField := releaseutil.Manifest{
+		Name: "chart/templates/unknown-field.yaml",
+		Content: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: unknown-field
+datta:
+  key: value
+`,
+	}
+	invalidType := releaseutil.Manifest{
+		Name: "chart/templates/invalid-type.yaml",
+		Content: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: invalid-type
+immutable: "yes"
+`,
+	}
+	unknownKind := releaseutil.Manifest{
+		Name: "chart/templates/custom-resource.yaml",
+		Content: `apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: widget
+spec:
+  anything: goes
+`,
+	}
+
+	t.Run("valid resources", func(t *testing.T) {
+		t.Parallel()
+		err := validateResources(validator, nil, []releaseutil.Manifest{validConfigMap, unknownKind})
+		require.NoError(t, err)
+	})
+
+	t.Run("all violations are reported", func(t *testing.T) {
+		t.Parallel()
+		hooks := []*releasev1.Hook{{Path: "chart/templates/hook.yaml", Manifest: unknownField.Content}}
+		err := validateResources(validator, hooks, []releaseutil.Manifest{validConfigMap, unknownField, invalidType})
+		require.ErrorContains(t, err, "3 resource(s) do not match the cluster schema")
+		require.ErrorContains(t, err, "chart/templates/hook.yaml: ")
+		require.ErrorContains(t, err, "chart/templates/unknown-field.yaml: ")
+		require.ErrorContains(t, err, `unknown field "datta"`)
+		require.ErrorContains(t, err, "chart/templates/invalid-type.yaml: ")
+		require.NotContains(t, err.Error(), "chart/templates/valid.yaml")
+	})
+}
+
+func TestValidateChartSchema(t *testing.T) {
+	t.Parallel()
+
+	validator := validation.NewSchemaValidation(testOpenAPIResources{path: filepath.Join("testdata", "openapi", "swagger.json")})
+	zarfChart := v1alpha1.ZarfChart{
+		Name:      "configmap-chart",
+		Version:   "1.0.0",
+		Namespace: "default",
+	}
+	chart, err := loader.Load(filepath.Join("testdata", "schema", "configmap-chart"))
+	require.NoError(t, err)
+
+	t.Run("valid values", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.TestContext(t)
+		err := ValidateChartSchema(ctx, zarfChart, chart, common.Values{}, "", template.GetZarfVariableConfig(ctx, false), validator)
+		require.NoError(t, err)
+	})
+
+	t.Run("values that render an invalid resource", func(t *testing.T) {
+		t.Parallel()
+		ctx := testutil.TestContext(t)
+		values := common.Values{"immutable": "sometimes"}
+		err := ValidateChartSchema(ctx, zarfChart, chart, values, "", template.GetZarfVariableConfig(ctx, false), validator)
+		require.ErrorContains(t, err, "1 resource(s) do not match the cluster schema")
+		require.ErrorContains(t, err, "configmap-chart/templates/configmap.yaml: ")
+	})
+}
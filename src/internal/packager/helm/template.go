@@ -38,7 +38,7 @@ func TemplateChart(ctx context.Context, zarfChart v1alpha1.ZarfChart, chart *cha
 	l := logger.From(ctx)
 	l.Debug("templating helm chart", "name", zarfChart.Name)
 
-	actionCfg, err := createActionConfig(ctx, zarfChart.Namespace)
+	actionCfg, err := createActionConfig(ctx, zarfChart.Namespace, "")
 	if err != nil {
 		return "", err
 	}
@@ -53,6 +53,10 @@ func TemplateChart(ctx context.Context, zarfChart v1alpha1.ZarfChart, chart *cha
 	client.Verify = false
 	client.PlainHTTP = remoteOptions.PlainHTTP
 	client.InsecureSkipTLSVerify = remoteOptions.InsecureSkipTLSVerify
+	// An explicitly requested kube version takes precedence over the one set on the chart.
+	if kubeVersion == "" {
+		kubeVersion = zarfChart.KubeVersion
+	}
 	if kubeVersion != "" {
 		parsedKubeVersion, err := common.ParseKubeVersion(kubeVersion)
 		if err != nil {
@@ -39,3 +39,56 @@ func TestChartTemplate(t *testing.T) {
 	require.NoError(t, err)
 	require.YAMLEq(t, string(b), manifest)
 }
+
+func TestChartTemplateKubeVersion(t *testing.T) {
+	ctx := context.Background()
+	chartPath := filepath.Join("testdata", "template", "kube-version-chart")
+	tmpdir := t.TempDir()
+	chart := v1alpha1.ZarfChart{
+		Name:      "kube-version-chart",
+		Version:   "1.0.0",
+		Namespace: "default",
+		LocalPath: chartPath,
+	}
+	err := PackageChart(ctx, chart, tmpdir, tmpdir, tmpdir, types.RemoteOptions{})
+	require.NoError(t, err)
+	helmChart, values, err := LoadChartData(chart, tmpdir, tmpdir, nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name               string
+		chartKubeVersion   string
+		kubeVersion        string
+		expectedAPIVersion string
+	}{
+		{
+			name:               "chart kube version before the batch/v1 CronJob",
+			chartKubeVersion:   "v1.20.0",
+			expectedAPIVersion: "apiVersion: batch/v1beta1",
+		},
+		{
+			name:               "chart kube version after the batch/v1 CronJob",
+			chartKubeVersion:   "v1.30.0",
+			expectedAPIVersion: "apiVersion: batch/v1\n",
+		},
+		{
+			name:               "kube version argument takes precedence",
+			chartKubeVersion:   "v1.30.0",
+			kubeVersion:        "v1.20.0",
+			expectedAPIVersion: "apiVersion: batch/v1beta1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chart := chart
+			chart.KubeVersion = tt.chartKubeVersion
+			manifest, err := TemplateChart(ctx, chart, helmChart, values, tt.kubeVersion, nil, false, types.RemoteOptions{})
+			require.NoError(t, err)
+			require.Contains(t, manifest, tt.expectedAPIVersion)
+		})
+	}
+
+	chart.KubeVersion = "latest"
+	_, err = TemplateChart(ctx, chart, helmChart, values, "", nil, false, types.RemoteOptions{})
+	require.ErrorContains(t, err, "invalid kube version latest")
+}
@@ -46,3 +46,25 @@ func Build(path string, destination string, kustomizeAllowAnyDirectory bool, ena
 
 	return os.WriteFile(destination, yaml, helpers.ReadWriteUser)
 }
+
+// Patch applies a kustomize patch to the given manifests in memory and returns the patched manifests. The patch must
+// identify the objects it targets, so strategic merge patches fail when no manifest matches them.
+func Patch(manifests, patch []byte) ([]byte, error) {
+	fSys := filesys.MakeFsInMemory()
+	files := map[string][]byte{
+		"/resources.yaml":     manifests,
+		"/patch.yaml":         patch,
+		"/kustomization.yaml": []byte("resources:\n- resources.yaml\npatches:\n- path: patch.yaml\n"),
+	}
+	for name, b := range files {
+		if err := fSys.WriteFile(name, b); err != nil {
+			return nil, err
+		}
+	}
+
+	resources, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).Run(fSys, "/")
+	if err != nil {
+		return nil, err
+	}
+	return resources.AsYaml()
+}
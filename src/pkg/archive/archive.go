@@ -174,6 +174,9 @@ type DecompressOpts struct {
 	UnarchiveAll bool
 	// Files restricts extraction to these archive paths if non-empty.
 	Files []string
+	// Patterns restricts extraction to the archive paths matching these globs if non-empty, using the syntax of
+	// path.Match. Every pattern must match at least one entry.
+	Patterns []string
 	// StripComponents drops leading path elements from each entry.
 	StripComponents int
 	// OverwriteExisting truncates existing files instead of erroring.
@@ -190,6 +193,8 @@ func Decompress(ctx context.Context, source, dst string, opts DecompressOpts) er
 	switch {
 	case len(opts.Files) > 0:
 		return unarchiveFiltered(ctx, opts.Extractor, source, dst, opts.Files, opts.SkipValidation)
+	case len(opts.Patterns) > 0:
+		return unarchiveMatching(ctx, opts.Extractor, source, dst, opts.Patterns)
 	case opts.StripComponents > 0 || opts.OverwriteExisting:
 		if err := unarchiveWithStrip(ctx, opts.Extractor, source, dst, opts.StripComponents, opts.OverwriteExisting); err != nil {
 			return fmt.Errorf("unable to decompress: %w", err)
@@ -208,6 +213,41 @@ func Decompress(ctx context.Context, source, dst string, opts DecompressOpts) er
 	return nil
 }
 
+// Compression algorithms of the tar streams extracted by DecompressStream.
+const (
+	StreamCompressionNone = "none"
+	StreamCompressionGzip = "gzip"
+	StreamCompressionZstd = "zstd"
+)
+
+// DecompressStream extracts a tar stream, compressed with the given algorithm, from input into dst, overwriting existing files.
+// An empty algorithm is the same as StreamCompressionNone.
+func DecompressStream(ctx context.Context, input io.Reader, dst string, compression string) (err error) {
+	var extractor archives.Extractor
+	switch compression {
+	case "", StreamCompressionNone:
+		extractor = archives.Tar{}
+	case StreamCompressionGzip:
+		extractor = archives.CompressedArchive{Compression: archives.Gz{}, Extraction: archives.Tar{}}
+	case StreamCompressionZstd:
+		extractor = archives.CompressedArchive{Compression: archives.Zstd{}, Extraction: archives.Tar{}}
+	default:
+		return fmt.Errorf("unsupported stream compression %q", compression)
+	}
+	if err := os.MkdirAll(dst, dirPerm); err != nil {
+		return fmt.Errorf("creating dest %q: %w", dst, err)
+	}
+	root, err := os.OpenRoot(dst)
+	if err != nil {
+		return fmt.Errorf("opening root %q: %w", dst, err)
+	}
+	defer func() { err = errors.Join(err, root.Close()) }()
+	if err := extractor.Extract(ctx, input, stripHandler(root, 0, true)); err != nil {
+		return fmt.Errorf("extracting stream: %w", err)
+	}
+	return nil
+}
+
 // withArchive opens, identifies, and creates and asserts an extractor if one is not given
 func withArchive(path string, extractor archives.Extractor, fn func(ex archives.Extractor, input io.Reader) error) (err error) {
 	f, err := os.Open(path)
@@ -304,6 +344,40 @@ func unarchiveFiltered(ctx context.Context, extractor archives.Extractor, src, d
 	return nil
 }
 
+// unarchiveMatching extracts the entries of src that match any of the 'patterns' into dst, keeping their paths in the
+// archive. It returns an error naming every pattern that does not match an entry.
+func unarchiveMatching(ctx context.Context, extractor archives.Extractor, src, dst string, patterns []string) (err error) {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+	matched := make(map[string]bool, len(patterns))
+	if err := os.MkdirAll(dst, dirPerm); err != nil {
+		return fmt.Errorf("creating dest %q: %w", dst, err)
+	}
+	root, err := os.OpenRoot(dst)
+	if err != nil {
+		return fmt.Errorf("opening root %q: %w", dst, err)
+	}
+	defer func() { err = errors.Join(err, root.Close()) }()
+
+	err = withArchive(src, extractor, func(ex archives.Extractor, input io.Reader) error {
+		return ex.Extract(ctx, input, matchHandler(root, patterns, matched))
+	})
+	if err != nil {
+		return fmt.Errorf("filtered extract of %q: %w", src, err)
+	}
+
+	var errs []error
+	for _, pattern := range patterns {
+		if !matched[pattern] {
+			errs = append(errs, fmt.Errorf("no files matching %q found in archive %q", pattern, src))
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // nestedUnarchive walks dst and unarchives each .tar file it finds.
 // It uses WalkDir so that symlinks are visible via d.Type() and skipped,
 // preventing the walk from following symlinks into directories.
@@ -381,6 +455,26 @@ func filterHandler(root *os.Root, wantSet, found map[string]bool) func(_ context
 	}
 }
 
+// matchHandler returns an archive.Entry handler that writes only entries whose names match any of the 'patterns'. It
+// records the patterns that matched an entry in 'matched'.
+func matchHandler(root *os.Root, patterns []string, matched map[string]bool) func(_ context.Context, f archives.FileInfo) error {
+	return func(_ context.Context, f archives.FileInfo) error {
+		name := strings.TrimSuffix(f.NameInArchive, "/")
+		found := false
+		for _, pattern := range patterns {
+			// The patterns are validated before extracting so the error can be ignored
+			if ok, _ := path.Match(pattern, name); ok {
+				matched[pattern] = true
+				found = true
+			}
+		}
+		if !found {
+			return nil
+		}
+		return writeEntry(root, f.NameInArchive, f.LinkTarget, f, os.O_CREATE|os.O_WRONLY)
+	}
+}
+
 // writeEntry validates and dispatches an archive entry within root.
 // Directory and file operations use os.Root methods, which provide
 // kernel-enforced path traversal and symlink escape protection.
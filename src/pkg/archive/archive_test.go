@@ -183,6 +183,47 @@ func TestCompressAndDecompress_MultipleFormats(t *testing.T) {
 	}
 }
 
+func TestDecompressStream(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		extension   string
+		compression string
+		expectedErr string
+	}{
+		{"none", extensionTar, StreamCompressionNone, ""},
+		{"default", extensionTar, "", ""},
+		{"gzip", extensionGz, StreamCompressionGzip, ""},
+		{"zstd", extensionZst, StreamCompressionZstd, ""},
+		{"mismatch", extensionZst, StreamCompressionGzip, "extracting stream"},
+		{"unsupported", extensionTar, "xz", `unsupported stream compression "xz"`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			src := filepath.Join(t.TempDir(), "payload.txt")
+			writeTestFile(t, src, "model weights")
+			archivePath := filepath.Join(t.TempDir(), "archive"+tc.extension)
+			require.NoError(t, Compress(ctx, []string{src}, archivePath, CompressOpts{}))
+
+			f, err := os.Open(archivePath)
+			require.NoError(t, err)
+			t.Cleanup(func() { require.NoError(t, f.Close()) })
+
+			dstDir := t.TempDir()
+			err = DecompressStream(ctx, f, dstDir, tc.compression)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "model weights", readTestFile(t, filepath.Join(dstDir, "payload.txt")))
+		})
+	}
+}
+
 func TestCompressUnsupportedExtension(t *testing.T) {
 	t.Parallel()
 	ctx := context.Background()
@@ -278,6 +319,36 @@ func TestDecompressFiltered(t *testing.T) {
 	}
 }
 
+func TestDecompressPatterns(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	srcDir := filepath.Join(t.TempDir(), "vendor")
+	for _, dir := range []string{"bin", "lib", "docs"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(srcDir, dir), testDirPerm))
+	}
+	for _, file := range []string{"bin/zarf", "bin/helm", "lib/libzarf.so", "docs/README.md"} {
+		writeTestFile(t, filepath.Join(srcDir, file), file)
+	}
+	archivePath := filepath.Join(t.TempDir(), "vendor.tar.gz")
+	require.NoError(t, Compress(ctx, []string{srcDir}, archivePath, CompressOpts{}))
+
+	outDir := t.TempDir()
+	err := Decompress(ctx, archivePath, outDir, DecompressOpts{Patterns: []string{"vendor/bin/*", "vendor/lib/libzarf.so"}})
+	require.NoError(t, err)
+	for _, file := range []string{"bin/zarf", "bin/helm", "lib/libzarf.so"} {
+		require.Equal(t, file, readTestFile(t, filepath.Join(outDir, "vendor", file)))
+	}
+	require.NoDirExists(t, filepath.Join(outDir, "vendor", "docs"))
+
+	err = Decompress(ctx, archivePath, t.TempDir(), DecompressOpts{Patterns: []string{"vendor/bin/*", "vendor/sbin/*", "vendor/etc"}})
+	require.ErrorContains(t, err, `no files matching "vendor/sbin/*" found in archive`)
+	require.ErrorContains(t, err, `no files matching "vendor/etc" found in archive`)
+
+	err = Decompress(ctx, archivePath, t.TempDir(), DecompressOpts{Patterns: []string{"vendor/[bin"}})
+	require.ErrorContains(t, err, `invalid pattern "vendor/[bin"`)
+}
+
 func TestDecompressOptions(t *testing.T) {
 	t.Parallel()
 	ctx := context.Background()
@@ -103,7 +103,7 @@ func NewWithWait(ctx context.Context) (*Cluster, error) {
 }
 
 // New creates a new Cluster instance and validates connection to the cluster by fetching the Kubernetes version.
-func New(_ context.Context) (*Cluster, error) {
+func New(ctx context.Context) (*Cluster, error) {
 	clusterErr := errors.New("unable to connect to the cluster")
 	clientset, cfg, err := ClientAndConfig()
 	if err != nil {
@@ -119,7 +119,7 @@ func New(_ context.Context) (*Cluster, error) {
 		Watcher:    w,
 	}
 	// Dogsled the version output. We just want to ensure no errors were returned to validate cluster connection.
-	_, err = c.Clientset.Discovery().ServerVersion()
+	err = c.Clientset.Discovery().RESTClient().Get().AbsPath("/version").Do(ctx).Error()
 	if err != nil {
 		return nil, errors.Join(clusterErr, err)
 	}
@@ -177,6 +177,12 @@ type InitStateOptions struct {
 	AgentTLS *pki.GeneratedPKI
 	// InternalServices lists the state services that Zarf is deploying in this init run.
 	InternalServices state.ServiceSet
+	// AgentImageFields are the custom resource image fields the agent rewrites, existing fields are kept when empty
+	AgentImageFields []state.AgentImageField
+	// AgentImageRewrites are the registries the agent points at another destination, existing rules are kept when empty
+	AgentImageRewrites []state.AgentImageRewrite
+	// AgentImagePassthrough are the registries the agent leaves untouched, existing registries are kept when empty
+	AgentImagePassthrough []string
 }
 
 // InitState takes initOptions and hydrates a cluster's state from InitStateOptions.
@@ -296,6 +302,15 @@ func (c *Cluster) InitState(ctx context.Context, opts InitStateOptions) (*state.
 	}
 
 	s.IPFamily = ipFamily
+	if len(opts.AgentImageFields) > 0 {
+		s.AgentImageFields = opts.AgentImageFields
+	}
+	if len(opts.AgentImageRewrites) > 0 {
+		s.AgentImageRewrites = opts.AgentImageRewrites
+	}
+	if len(opts.AgentImagePassthrough) > 0 {
+		s.AgentImagePassthrough = opts.AgentImagePassthrough
+	}
 
 	previousMode := s.RegistryInfo.RegistryMode
 	if opts.RegistryInfo.RegistryMode != "" {
@@ -573,6 +588,23 @@ func (c *Cluster) SaveState(ctx context.Context, s *state.State) error {
 	return nil
 }
 
+// GetArchitectures returns the sorted unique architectures of the nodes of the cluster.
+func (c *Cluster) GetArchitectures(ctx context.Context) ([]string, error) {
+	nodeList, err := c.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	architectures := []string{}
+	for _, node := range nodeList.Items {
+		if node.Status.NodeInfo.Architecture == "" {
+			continue
+		}
+		architectures = append(architectures, node.Status.NodeInfo.Architecture)
+	}
+	slices.Sort(architectures)
+	return slices.Compact(architectures), nil
+}
+
 // GetIPFamily returns the IP family of the cluster, can be ipv4, ipv6, or dual.
 func (c *Cluster) GetIPFamily(ctx context.Context) (_ state.IPFamily, err error) {
 	svcName := "zarf-ip-family-test"
@@ -9,7 +9,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -25,6 +27,7 @@ import (
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 	"github.com/zarf-dev/zarf/src/config"
 	"github.com/zarf-dev/zarf/src/pkg/logger"
+	"github.com/zarf-dev/zarf/src/pkg/state"
 	"github.com/zarf-dev/zarf/src/pkg/utils"
 	"github.com/zarf-dev/zarf/src/pkg/utils/exec"
 )
@@ -38,10 +41,7 @@ func (c *Cluster) HandleDataInjection(ctx context.Context, data v1alpha1.ZarfDat
 		return fmt.Errorf("unable to create the data injection completion marker: %w", err)
 	}
 
-	tarCompressFlag := ""
-	if data.Compress {
-		tarCompressFlag = "-z"
-	}
+	compression := data.GetCompressionAlgorithm()
 
 	// Pod filter to ensure we only use the current deployment's pods
 	podFilterByInitContainer := func(pod corev1.Pod) bool {
@@ -59,8 +59,13 @@ func (c *Cluster) HandleDataInjection(ctx context.Context, data v1alpha1.ZarfDat
 	if _, _, err := exec.Cmd(shell, append(shellArgs, "tar --version")...); err != nil {
 		return fmt.Errorf("unable to execute tar, ensure it is installed in the $PATH: %w", err)
 	}
+	if compression == v1alpha1.DataInjectionCompressionZstd {
+		if _, _, err := exec.Cmd(shell, append(shellArgs, "zstd --version")...); err != nil {
+			return fmt.Errorf("unable to execute zstd, ensure it is installed in the $PATH: %w", err)
+		}
+	}
 
-	l.Debug("performing data injection", "target", data.Target)
+	l.Debug("performing data injection", "target", data.Target, "via", dataInjectionVia(data.GetMode()))
 
 	source := filepath.Join(dataInjectionPath, filepath.Base(data.Target.Path))
 	if helpers.InvalidPath(source) {
@@ -86,6 +91,13 @@ func (c *Cluster) HandleDataInjection(ctx context.Context, data v1alpha1.ZarfDat
 
 	// Inject into all the pods
 	for _, pod := range pods {
+		if data.GetMode() == v1alpha1.DataInjectionModeInitContainer {
+			if err := c.injectWithEphemeralContainer(ctx, pod, data, source, dataInjectionPath, dataIdx); err != nil {
+				return err
+			}
+			continue
+		}
+
 		// Try to use the embedded kubectl if we can
 		zarfCommand, err := utils.GetFinalExecutableCommand()
 		kubectlBinPath := "kubectl"
@@ -96,9 +108,7 @@ func (c *Cluster) HandleDataInjection(ctx context.Context, data v1alpha1.ZarfDat
 		}
 		kubectlCmd := fmt.Sprintf("%s exec -i -n %s %s -c %s ", kubectlBinPath, data.Target.Namespace, pod.Name, data.Target.Container)
 
-		// Note that each command flag is separated to provide the widest cross-platform tar support
-		tarCmd := fmt.Sprintf("tar -c %s -f -", tarCompressFlag)
-		untarCmd := fmt.Sprintf("tar -x %s -v -f - -C %s", tarCompressFlag, data.Target.Path)
+		untarCmd := dataInjectionUnpackCommand(compression, data.Target.Path)
 
 		// Must create the target directory before trying to change to it for untar
 		mkdirCmd := fmt.Sprintf("%s -- mkdir -p %s", kubectlCmd, data.Target.Path)
@@ -106,9 +116,16 @@ func (c *Cluster) HandleDataInjection(ctx context.Context, data v1alpha1.ZarfDat
 			return fmt.Errorf("unable to create the data injection target directory %s in pod %s: %w", data.Target.Path, pod.Name, err)
 		}
 
-		cpPodCmd := fmt.Sprintf("%s -C %s . | %s -- %s",
-			tarCmd,
-			source,
+		if compression == v1alpha1.DataInjectionCompressionZstd {
+			zstdCmd := fmt.Sprintf(`%s -- sh -c "command -v zstd"`, kubectlCmd)
+			if _, _, err := exec.Cmd(shell, append(shellArgs, zstdCmd)...); err != nil {
+				return fmt.Errorf("the container %s in pod %s must have zstd installed to decompress the data injection, use mode %s to inject through an ephemeral container or another compression algorithm otherwise: %w",
+					data.Target.Container, pod.Name, v1alpha1.DataInjectionModeInitContainer, err)
+			}
+		}
+
+		cpPodCmd := fmt.Sprintf("%s | %s -- %s",
+			dataInjectionPackCommand(compression, fmt.Sprintf("-C %s .", source)),
 			kubectlCmd,
 			untarCmd,
 		)
@@ -119,10 +136,8 @@ func (c *Cluster) HandleDataInjection(ctx context.Context, data v1alpha1.ZarfDat
 		}
 
 		// Leave a marker in the target container for pods to track the sync action
-		cpPodCmd = fmt.Sprintf("%s -C %s %s | %s -- %s",
-			tarCmd,
-			dataInjectionPath,
-			config.GetDataInjectionMarker(),
+		cpPodCmd = fmt.Sprintf("%s | %s -- %s",
+			dataInjectionPackCommand(compression, fmt.Sprintf("-C %s %s", dataInjectionPath, config.GetDataInjectionMarker())),
 			kubectlCmd,
 			untarCmd,
 		)
@@ -156,6 +171,174 @@ func (c *Cluster) HandleDataInjection(ctx context.Context, data v1alpha1.ZarfDat
 	return nil
 }
 
+// dataInjectionContainerPrefix is the name prefix of ephemeral containers used to inject data.
+const dataInjectionContainerPrefix = "zarf-data-injection"
+
+// injectWithEphemeralContainer injects data into a pod through an ephemeral container running the Zarf agent image
+// that mounts the same volume as the target container, so the target image does not need a shell or tar.
+func (c *Cluster) injectWithEphemeralContainer(ctx context.Context, pod corev1.Pod, data v1alpha1.ZarfDataInjection, source, dataInjectionPath string, dataIdx int) error {
+	l := logger.From(ctx)
+
+	mount, err := findDataInjectionVolumeMount(pod, data.Target.Container, data.Target.Path)
+	if err != nil {
+		return err
+	}
+
+	deployment, err := c.Clientset.AppsV1().Deployments(state.ZarfNamespaceName).Get(ctx, "agent-hook", metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to find the Zarf agent image, data injection through an ephemeral container requires the Zarf agent: %w", err)
+	}
+	if len(deployment.Spec.Template.Spec.Containers) == 0 {
+		return fmt.Errorf("unable to find the Zarf agent image, the agent deployment has no containers")
+	}
+	agentImage := deployment.Spec.Template.Spec.Containers[0].Image
+
+	name := fmt.Sprintf("%s-%d-%d", dataInjectionContainerPrefix, dataIdx, len(pod.Spec.EphemeralContainers))
+	command := []string{"/zarf", "internal", "receive-data-injection", data.Target.Path}
+	switch data.GetCompressionAlgorithm() {
+	case v1alpha1.DataInjectionCompressionGzip:
+		// Agents that predate the compression flag only understand --compress
+		command = append(command, "--compress")
+	case v1alpha1.DataInjectionCompressionZstd:
+		command = append(command, "--compression", string(v1alpha1.DataInjectionCompressionZstd))
+	}
+	pod.Spec.EphemeralContainers = append(pod.Spec.EphemeralContainers, corev1.EphemeralContainer{
+		EphemeralContainerCommon: corev1.EphemeralContainerCommon{
+			Name:      name,
+			Image:     agentImage,
+			Command:   command,
+			Stdin:     true,
+			StdinOnce: true,
+			VolumeMounts: []corev1.VolumeMount{
+				{
+					Name:      mount.Name,
+					MountPath: mount.MountPath,
+				},
+			},
+		},
+		TargetContainerName: data.Target.Container,
+	})
+	l.Debug("adding data injection ephemeral container", "pod", pod.Name, "container", name, "volume", mount.Name)
+	_, err = c.Clientset.CoreV1().Pods(pod.Namespace).UpdateEphemeralContainers(ctx, pod.Name, &pod, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to add the data injection container to pod %s: %w", pod.Name, err)
+	}
+
+	// Wait for the ephemeral container to start before streaming data into it
+	containerTarget := podLookup{
+		Namespace: pod.Namespace,
+		Selector:  data.Target.Selector,
+	}
+	waitCtx, waitCancel := context.WithTimeout(ctx, 90*time.Second)
+	defer waitCancel()
+	_, err = waitForPodsAndContainers(waitCtx, c.Clientset, containerTarget, func(p corev1.Pod) bool {
+		if p.Name != pod.Name {
+			return false
+		}
+		for _, status := range p.Status.EphemeralContainerStatuses {
+			if status.Name == name && status.State.Running != nil {
+				return true
+			}
+		}
+		return false
+	})
+	if err != nil {
+		return fmt.Errorf("data injection container %s in pod %s did not start: %w", name, pod.Name, err)
+	}
+
+	shell, shellArgs := exec.GetOSShell(v1alpha1.Shell{Windows: "cmd"})
+	zarfCommand, err := utils.GetFinalExecutableCommand()
+	kubectlBinPath := "kubectl"
+	if err != nil {
+		l.Warn("unable to get the zarf executable path, falling back to host kubectl", "error", err)
+	} else {
+		kubectlBinPath = fmt.Sprintf("%s tools kubectl", zarfCommand)
+	}
+
+	// Send the data and the completion marker in a single stream since stdin can only be attached once
+	cpPodCmd := fmt.Sprintf("%s | %s attach -i -n %s %s -c %s",
+		dataInjectionPackCommand(data.GetCompressionAlgorithm(), fmt.Sprintf("-C %s . -C %s %s", source, dataInjectionPath, config.GetDataInjectionMarker())),
+		kubectlBinPath,
+		pod.Namespace,
+		pod.Name,
+		name,
+	)
+	if err := exec.CmdWithPrint(shell, append(shellArgs, cpPodCmd)...); err != nil {
+		return fmt.Errorf("could not copy data into the pod %s: %w", pod.Name, err)
+	}
+	return nil
+}
+
+// dataInjectionVia returns the kind of container a data injection mode copies data through, for log output. The
+// initContainer mode copies through an ephemeral container rather than an init container.
+func dataInjectionVia(mode v1alpha1.DataInjectionMode) string {
+	if mode == v1alpha1.DataInjectionModeInitContainer {
+		return "ephemeralContainer"
+	}
+	return "exec"
+}
+
+// dataInjectionPackCommand returns the command that writes a tar stream of the given tar arguments to stdout, compressed with the algorithm.
+// Note that each command flag is separated to provide the widest cross-platform tar support.
+func dataInjectionPackCommand(compression v1alpha1.DataInjectionCompression, tarArgs string) string {
+	switch compression {
+	case v1alpha1.DataInjectionCompressionGzip:
+		return fmt.Sprintf("tar -c -z -f - %s", tarArgs)
+	case v1alpha1.DataInjectionCompressionZstd:
+		return fmt.Sprintf("tar -c -f - %s | zstd -c -q", tarArgs)
+	default:
+		return fmt.Sprintf("tar -c -f - %s", tarArgs)
+	}
+}
+
+// dataInjectionUnpackCommand returns the command that extracts a tar stream compressed with the algorithm from stdin into path.
+func dataInjectionUnpackCommand(compression v1alpha1.DataInjectionCompression, path string) string {
+	switch compression {
+	case v1alpha1.DataInjectionCompressionGzip:
+		return fmt.Sprintf("tar -x -z -v -f - -C %s", path)
+	case v1alpha1.DataInjectionCompressionZstd:
+		return fmt.Sprintf(`sh -c "zstd -d -c -q | tar -x -v -f - -C %s"`, path)
+	default:
+		return fmt.Sprintf("tar -x -v -f - -C %s", path)
+	}
+}
+
+// findDataInjectionVolumeMount returns the volume mount of the named container that holds the target path,
+// preferring the most specific mount path when several match.
+func findDataInjectionVolumeMount(pod corev1.Pod, containerName, targetPath string) (corev1.VolumeMount, error) {
+	var mounts []corev1.VolumeMount
+	found := false
+	for _, container := range slices.Concat(pod.Spec.InitContainers, pod.Spec.Containers) {
+		if container.Name == containerName {
+			mounts = container.VolumeMounts
+			found = true
+			break
+		}
+	}
+	if !found {
+		return corev1.VolumeMount{}, fmt.Errorf("container %s not found in pod %s", containerName, pod.Name)
+	}
+
+	targetPath = path.Clean(targetPath)
+	var match *corev1.VolumeMount
+	for i, mount := range mounts {
+		mountPath := path.Clean(mount.MountPath)
+		if targetPath != mountPath && !strings.HasPrefix(targetPath, strings.TrimSuffix(mountPath, "/")+"/") {
+			continue
+		}
+		if match == nil || len(mountPath) > len(path.Clean(match.MountPath)) {
+			match = &mounts[i]
+		}
+	}
+	if match == nil {
+		return corev1.VolumeMount{}, fmt.Errorf("no volume is mounted at %s in container %s of pod %s", targetPath, containerName, pod.Name)
+	}
+	if match.SubPath != "" || match.SubPathExpr != "" {
+		return corev1.VolumeMount{}, fmt.Errorf("volume %s is mounted with a subPath in container %s of pod %s, which ephemeral containers cannot mount", match.Name, containerName, pod.Name)
+	}
+	return *match, nil
+}
+
 // podLookup is a struct for specifying a pod to target for data injection or lookups.
 type podLookup struct {
 	Namespace string
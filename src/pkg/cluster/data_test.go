@@ -0,0 +1,158 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package cluster contains Zarf-specific cluster management functions.
+package cluster
+
+import (
+	"fmt"
+	"os"
+	osexec "os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/pkg/utils/exec"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFindDataInjectionVolumeMount(t *testing.T) {
+	t.Parallel()
+
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "data-injection",
+		},
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{
+				{
+					Name: "data-loader",
+					VolumeMounts: []corev1.VolumeMount{
+						{Name: "data", MountPath: "/data"},
+					},
+				},
+			},
+			Containers: []corev1.Container{
+				{
+					Name: "app",
+					VolumeMounts: []corev1.VolumeMount{
+						{Name: "data", MountPath: "/data"},
+						{Name: "cache", MountPath: "/data/cache/"},
+						{Name: "config", MountPath: "/etc/app", SubPath: "app"},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		container     string
+		path          string
+		expectedName  string
+		expectedError string
+	}{
+		{
+			name:         "exact mount path",
+			container:    "app",
+			path:         "/data",
+			expectedName: "data",
+		},
+		{
+			name:         "path below mount",
+			container:    "app",
+			path:         "/data/kiwix",
+			expectedName: "data",
+		},
+		{
+			name:         "most specific mount wins",
+			container:    "app",
+			path:         "/data/cache/index",
+			expectedName: "cache",
+		},
+		{
+			name:         "init container target",
+			container:    "data-loader",
+			path:         "/data/kiwix/",
+			expectedName: "data",
+		},
+		{
+			name:          "path prefix that is not a parent directory",
+			container:     "app",
+			path:          "/database",
+			expectedError: "no volume is mounted at /database in container app of pod data-injection",
+		},
+		{
+			name:          "subPath mounts are rejected",
+			container:     "app",
+			path:          "/etc/app/config",
+			expectedError: "volume config is mounted with a subPath in container app of pod data-injection, which ephemeral containers cannot mount",
+		},
+		{
+			name:          "missing container",
+			container:     "sidecar",
+			path:          "/data",
+			expectedError: "container sidecar not found in pod data-injection",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mount, err := findDataInjectionVolumeMount(pod, tt.container, tt.path)
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedName, mount.Name)
+		})
+	}
+}
+
+func TestDataInjectionStreamCommands(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("data injection commands are run through sh in the target container")
+	}
+
+	tests := []struct {
+		compression v1alpha1.DataInjectionCompression
+		binaries    []string
+	}{
+		{compression: v1alpha1.DataInjectionCompressionNone, binaries: []string{"tar"}},
+		{compression: v1alpha1.DataInjectionCompressionGzip, binaries: []string{"tar", "gzip"}},
+		{compression: v1alpha1.DataInjectionCompressionZstd, binaries: []string{"tar", "zstd"}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.compression), func(t *testing.T) {
+			t.Parallel()
+			for _, binary := range tt.binaries {
+				if _, err := osexec.LookPath(binary); err != nil {
+					t.Skipf("%s is not installed", binary)
+				}
+			}
+
+			source := t.TempDir()
+			require.NoError(t, os.MkdirAll(filepath.Join(source, "models"), 0o755))
+			require.NoError(t, os.WriteFile(filepath.Join(source, "models", "weights.bin"), []byte("model weights"), 0o644))
+
+			// The target path stands in for the target container, the unpack command runs exactly as it would be executed there
+			target := filepath.Join(t.TempDir(), "data")
+			require.NoError(t, os.MkdirAll(target, 0o755))
+			cmd := fmt.Sprintf("%s | %s",
+				dataInjectionPackCommand(tt.compression, fmt.Sprintf("-C %s .", source)),
+				dataInjectionUnpackCommand(tt.compression, target),
+			)
+			_, stderr, err := exec.Cmd("sh", "-c", cmd)
+			require.NoError(t, err, stderr)
+
+			b, err := os.ReadFile(filepath.Join(target, "models", "weights.bin"))
+			require.NoError(t, err)
+			require.Equal(t, "model weights", string(b))
+		})
+	}
+}
@@ -0,0 +1,25 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package cluster
+
+import (
+	"k8s.io/kubectl/pkg/util/openapi"
+	"k8s.io/kubectl/pkg/validation"
+)
+
+// openAPIResources lazily fetches and parses the OpenAPI schema served by the cluster.
+type openAPIResources struct {
+	parser *openapi.CachedOpenAPIParser
+}
+
+// OpenAPISchema returns the parsed OpenAPI schema of the cluster.
+func (r *openAPIResources) OpenAPISchema() (openapi.Resources, error) {
+	return r.parser.Parse()
+}
+
+// SchemaValidator returns a validator that checks resources against the OpenAPI schema served by the cluster.
+// Resources of a kind the cluster does not serve, such as custom resources whose CRD is not yet installed, are not validated.
+func (c *Cluster) SchemaValidator() validation.Schema {
+	return validation.NewSchemaValidation(&openAPIResources{parser: openapi.NewOpenAPIParser(c.Clientset.Discovery())})
+}
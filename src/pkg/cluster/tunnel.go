@@ -8,14 +8,18 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/httpstream"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -55,10 +59,18 @@ type TunnelInfo struct {
 	Namespace       string
 	ResourceType    string
 	ResourceName    string
-	urlSuffix       string
+	// Transport is the streaming protocol used for the port forward, defaults to TunnelTransportAuto
+	Transport TunnelTransport
+	// Protocol is the protocol of the remote port, defaults to TunnelProtocolTCP
+	Protocol  TunnelProtocol
+	urlSuffix string
 }
 
-// ListConnections will return a list of all Zarf connect matches found in the cluster.
+// connectionsPageSize is the number of services requested per page when listing the connections.
+const connectionsPageSize = 100
+
+// ListConnections will return a list of all Zarf connect matches found in the cluster. The services are listed in pages,
+// when the context is done the connections found so far, possibly none, are returned along with the context error.
 func (c *Cluster) ListConnections(ctx context.Context) (state.ConnectStrings, error) {
 	selector, err := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{
 		MatchExpressions: []metav1.LabelSelectorRequirement{{
@@ -69,19 +81,28 @@ func (c *Cluster) ListConnections(ctx context.Context) (state.ConnectStrings, er
 	if err != nil {
 		return nil, err
 	}
-	serviceList, err := c.Clientset.CoreV1().Services("").List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
-	if err != nil {
-		return nil, err
-	}
 	connections := state.ConnectStrings{}
-	for _, svc := range serviceList.Items {
-		name := svc.Labels[ZarfConnectLabelName]
-		connections[name] = state.ConnectString{
-			Description: svc.Annotations[ZarfConnectAnnotationDescription],
-			URL:         svc.Annotations[ZarfConnectAnnotationURL],
+	opts := metav1.ListOptions{LabelSelector: selector.String(), Limit: connectionsPageSize}
+	for {
+		serviceList, err := c.Clientset.CoreV1().Services("").List(ctx, opts)
+		if err != nil {
+			if ctx.Err() != nil {
+				return connections, ctx.Err()
+			}
+			return nil, err
 		}
+		for _, svc := range serviceList.Items {
+			name := svc.Labels[ZarfConnectLabelName]
+			connections[name] = state.ConnectString{
+				Description: svc.Annotations[ZarfConnectAnnotationDescription],
+				URL:         svc.Annotations[ZarfConnectAnnotationURL],
+			}
+		}
+		if serviceList.Continue == "" {
+			return connections, nil
+		}
+		opts.Continue = serviceList.Continue
 	}
-	return connections, nil
 }
 
 // NewTargetTunnelInfo returns a new TunnelInfo object for the specified target.
@@ -122,6 +143,34 @@ func (c *Cluster) NewTargetTunnelInfo(ctx context.Context, target string) (Tunne
 	return zt, nil
 }
 
+// NewTargetTunnelInfoWithWait returns a new TunnelInfo object for the specified target, retrying until the target
+// service exists in the cluster. The context should carry a deadline, otherwise it will retry indefinitely.
+func (c *Cluster) NewTargetTunnelInfoWithWait(ctx context.Context, target string) (TunnelInfo, error) {
+	l := logger.From(ctx)
+	return retry.DoWithData(func() (TunnelInfo, error) {
+		zt, err := c.NewTargetTunnelInfo(ctx, target)
+		if err != nil {
+			return TunnelInfo{}, err
+		}
+		if zt.ResourceType == SvcResource {
+			_, err := c.Clientset.CoreV1().Services(zt.Namespace).Get(ctx, zt.ResourceName, metav1.GetOptions{})
+			if err != nil {
+				return TunnelInfo{}, err
+			}
+		}
+		return zt, nil
+	},
+		retry.Context(ctx),
+		retry.Attempts(0),
+		retry.DelayType(retry.FixedDelay),
+		retry.Delay(time.Second),
+		retry.LastErrorOnly(true),
+		retry.OnRetry(func(_ uint, err error) {
+			l.Debug("connect target is not available yet, retrying", "target", target, "error", err)
+		}),
+	)
+}
+
 // Connect will establish a tunnel to the specified target.
 func (c *Cluster) Connect(ctx context.Context, target string) (*Tunnel, error) {
 	zt, err := c.NewTargetTunnelInfo(ctx, target)
@@ -133,7 +182,7 @@ func (c *Cluster) Connect(ctx context.Context, target string) (*Tunnel, error) {
 
 // ConnectTunnelInfo connects to the cluster with the provided TunnelInfo
 func (c *Cluster) ConnectTunnelInfo(ctx context.Context, zt TunnelInfo) (*Tunnel, error) {
-	tunnel, err := c.NewTunnel(zt.Namespace, zt.ResourceType, zt.ResourceName, zt.urlSuffix, zt.LocalPort, zt.RemotePort, WithListenAddress(zt.ListenAddresses))
+	tunnel, err := c.NewTunnel(zt.Namespace, zt.ResourceType, zt.ResourceName, zt.urlSuffix, zt.LocalPort, zt.RemotePort, WithListenAddress(zt.ListenAddresses), WithTransport(zt.Transport), WithProtocol(zt.Protocol))
 	if err != nil {
 		return nil, err
 	}
@@ -327,6 +376,54 @@ const (
 	SvcResource = "svc"
 )
 
+// TunnelTransport is the streaming protocol used to port forward through the API server.
+type TunnelTransport string
+
+// Tunnel transports.
+const (
+	// TunnelTransportAuto uses WebSockets and falls back to SPDY when the upgrade fails
+	TunnelTransportAuto TunnelTransport = "auto"
+	// TunnelTransportWebSocket only uses WebSockets
+	TunnelTransportWebSocket TunnelTransport = "websocket"
+	// TunnelTransportSPDY only uses SPDY
+	TunnelTransportSPDY TunnelTransport = "spdy"
+)
+
+// ParseTunnelTransport returns the tunnel transport for the given name, an empty name is TunnelTransportAuto.
+func ParseTunnelTransport(name string) (TunnelTransport, error) {
+	switch transport := TunnelTransport(strings.ToLower(name)); transport {
+	case "":
+		return TunnelTransportAuto, nil
+	case TunnelTransportAuto, TunnelTransportWebSocket, TunnelTransportSPDY:
+		return transport, nil
+	default:
+		return "", fmt.Errorf("invalid tunnel transport %q, must be one of %s, %s or %s", name, TunnelTransportAuto, TunnelTransportWebSocket, TunnelTransportSPDY)
+	}
+}
+
+// TunnelProtocol is the protocol of the port a tunnel forwards to.
+type TunnelProtocol string
+
+// Tunnel protocols.
+const (
+	// TunnelProtocolTCP forwards to a TCP port
+	TunnelProtocolTCP TunnelProtocol = "tcp"
+	// TunnelProtocolUDP forwards to a UDP port through a relay pod
+	TunnelProtocolUDP TunnelProtocol = "udp"
+)
+
+// ParseTunnelProtocol returns the tunnel protocol for the given name, an empty name is TunnelProtocolTCP.
+func ParseTunnelProtocol(name string) (TunnelProtocol, error) {
+	switch protocol := TunnelProtocol(strings.ToLower(name)); protocol {
+	case "":
+		return TunnelProtocolTCP, nil
+	case TunnelProtocolTCP, TunnelProtocolUDP:
+		return protocol, nil
+	default:
+		return "", fmt.Errorf("invalid tunnel protocol %q, must be one of %s or %s", name, TunnelProtocolTCP, TunnelProtocolUDP)
+	}
+}
+
 // TunnelOption is a function that configures a tunnel
 type TunnelOption func(*Tunnel)
 
@@ -337,6 +434,26 @@ func WithListenAddress(addr []string) TunnelOption {
 	}
 }
 
+// WithTransport will set the streaming protocol used by the tunnel, an empty transport is TunnelTransportAuto
+func WithTransport(transport TunnelTransport) TunnelOption {
+	return func(t *Tunnel) {
+		if transport == "" {
+			transport = TunnelTransportAuto
+		}
+		t.transport = transport
+	}
+}
+
+// WithProtocol will set the protocol of the remote port, an empty protocol is TunnelProtocolTCP
+func WithProtocol(protocol TunnelProtocol) TunnelOption {
+	return func(t *Tunnel) {
+		if protocol == "" {
+			protocol = TunnelProtocolTCP
+		}
+		t.protocol = protocol
+	}
+}
+
 // Tunnel is the main struct that configures and manages port forwarding tunnels to Kubernetes resources.
 type Tunnel struct {
 	clientset     kubernetes.Interface
@@ -348,9 +465,15 @@ type Tunnel struct {
 	resourceName  string
 	urlSuffix     string
 	listenAddress []string
-	stopChan      chan struct{}
-	readyChan     chan struct{}
-	errChan       chan error
+	transport     TunnelTransport
+	protocol      TunnelProtocol
+	// udpRelay holds the resources of a UDP tunnel, they are cleaned up when the tunnel is closed
+	udpRelay *udpRelay
+	// podName is the pod the tunnel forwards to, set once the tunnel is established
+	podName   string
+	stopChan  chan struct{}
+	readyChan chan struct{}
+	errChan   chan error
 }
 
 // NewTunnel will create a new Tunnel struct.
@@ -369,6 +492,8 @@ func (c *Cluster) NewTunnel(namespace, resourceType, resourceName, urlSuffix str
 		listenAddress: []string{
 			"127.0.0.1", // default
 		},
+		transport: TunnelTransportAuto,
+		protocol:  TunnelProtocolTCP,
 		stopChan:  make(chan struct{}, 1),
 		readyChan: make(chan struct{}, 1),
 	}
@@ -415,11 +540,27 @@ func (tunnel *Tunnel) Connect(ctx context.Context) ([]string, error) {
 func (tunnel *Tunnel) Endpoints() []string {
 	endpoints := make([]string, len(tunnel.listenAddress))
 	for i, addr := range tunnel.listenAddress {
-		endpoints[i] = fmt.Sprintf("%s:%d", addr, tunnel.localPort)
+		endpoints[i] = net.JoinHostPort(addr, strconv.Itoa(tunnel.localPort))
 	}
 	return endpoints
 }
 
+// Info returns the resource, ports and addresses of the tunnel. The local port is the port that was selected when the
+// tunnel was established with a local port of 0.
+func (tunnel *Tunnel) Info() TunnelInfo {
+	return TunnelInfo{
+		LocalPort:       tunnel.localPort,
+		RemotePort:      tunnel.remotePort,
+		ListenAddresses: slices.Clone(tunnel.listenAddress),
+		Namespace:       tunnel.namespace,
+		ResourceType:    tunnel.resourceType,
+		ResourceName:    tunnel.resourceName,
+		Transport:       tunnel.transport,
+		Protocol:        tunnel.protocol,
+		urlSuffix:       tunnel.urlSuffix,
+	}
+}
+
 // ErrChan returns the tunnel's error channel
 func (tunnel *Tunnel) ErrChan() chan error {
 	return tunnel.errChan
@@ -435,8 +576,16 @@ func (tunnel *Tunnel) HTTPEndpoints() []string {
 	return httpEndpoints
 }
 
-// FullURLs returns the tunnel endpoint as a HTTP URL string with the urlSuffix appended.
+// FullURLs returns the tunnel endpoint as a HTTP URL string with the urlSuffix appended. The endpoints of UDP tunnels
+// are returned as udp URLs.
 func (tunnel *Tunnel) FullURLs() []string {
+	if tunnel.protocol == TunnelProtocolUDP {
+		endpoints := tunnel.Endpoints()
+		for i, addr := range endpoints {
+			endpoints[i] = fmt.Sprintf("udp://%s", addr)
+		}
+		return endpoints
+	}
 	endpoints := tunnel.HTTPEndpoints()
 	fullEndpoints := make([]string, len(endpoints))
 	for i, addr := range endpoints {
@@ -445,8 +594,13 @@ func (tunnel *Tunnel) FullURLs() []string {
 	return fullEndpoints
 }
 
-// Close disconnects a tunnel connection by closing the StopChan, thereby stopping the goroutine.
+// Close disconnects a tunnel connection by closing the StopChan, thereby stopping the goroutine. The relay pod of a UDP
+// tunnel is deleted.
 func (tunnel *Tunnel) Close() {
+	if tunnel.udpRelay != nil {
+		tunnel.udpRelay.close(context.Background(), tunnel)
+		tunnel.udpRelay = nil
+	}
 	if tunnel.stopChan == nil {
 		return
 	}
@@ -457,8 +611,90 @@ func (tunnel *Tunnel) Close() {
 	}
 }
 
+// TunnelLogOptions selects the logs streamed by Tunnel.StreamLogs.
+type TunnelLogOptions struct {
+	// Since only streams logs newer than the duration, zero streams all logs.
+	Since time.Duration
+	// Tail is the number of lines from the end of the logs to stream first, a negative value streams all lines.
+	Tail int64
+}
+
+// StreamLogs follows the logs of the pod the tunnel forwards to and writes them to out until the tunnel is closed or
+// the context ends, which both stop the stream without an error. The logs of the container exposing the remote port are
+// streamed, or of the default container of the pod when no container declares the port.
+func (tunnel *Tunnel) StreamLogs(ctx context.Context, out io.Writer, opts TunnelLogOptions) error {
+	if tunnel.protocol == TunnelProtocolUDP {
+		return fmt.Errorf("logs are not supported for %s tunnels", tunnel.protocol)
+	}
+	if tunnel.podName == "" {
+		return fmt.Errorf("the tunnel is not established")
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-tunnel.stopChan:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	pod, err := tunnel.clientset.CoreV1().Pods(tunnel.namespace).Get(ctx, tunnel.podName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to get pod %s: %w", tunnel.podName, err)
+	}
+	logOpts := &corev1.PodLogOptions{
+		Container: logContainerForPort(*pod, tunnel.remotePort),
+		Follow:    true,
+	}
+	if opts.Since > 0 {
+		// The API server only accepts whole seconds, round up so that the requested logs are included
+		sinceSeconds := int64((opts.Since + time.Second - 1) / time.Second)
+		logOpts.SinceSeconds = &sinceSeconds
+	}
+	if opts.Tail >= 0 {
+		logOpts.TailLines = &opts.Tail
+	}
+	stream, err := tunnel.clientset.CoreV1().Pods(tunnel.namespace).GetLogs(tunnel.podName, logOpts).Stream(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return fmt.Errorf("unable to stream the logs of pod %s: %w", tunnel.podName, err)
+	}
+	defer stream.Close()
+	_, err = io.Copy(out, stream)
+	if err != nil && ctx.Err() == nil {
+		return fmt.Errorf("log stream of pod %s ended: %w", tunnel.podName, err)
+	}
+	return nil
+}
+
+// logContainerForPort returns the container of the pod that declares the port. When none does it returns the container
+// named by the kubectl default container annotation, or the first container.
+func logContainerForPort(pod corev1.Pod, port int) string {
+	for _, container := range pod.Spec.Containers {
+		for _, containerPort := range container.Ports {
+			if int(containerPort.ContainerPort) == port {
+				return container.Name
+			}
+		}
+	}
+	if name, ok := pod.Annotations["kubectl.kubernetes.io/default-container"]; ok {
+		return name
+	}
+	if len(pod.Spec.Containers) > 0 {
+		return pod.Spec.Containers[0].Name
+	}
+	return ""
+}
+
 // establish opens a tunnel to a kubernetes resource, as specified by the provided tunnel struct.
 func (tunnel *Tunnel) establish(ctx context.Context) ([]string, error) {
+	if tunnel.protocol == TunnelProtocolUDP {
+		return tunnel.establishUDP(ctx)
+	}
+
 	var err error
 	l := logger.From(ctx)
 
@@ -489,6 +725,7 @@ func (tunnel *Tunnel) establish(ctx context.Context) ([]string, error) {
 		"resourceType", tunnel.resourceType,
 		"resourceName", tunnel.resourceName,
 		"namespace", tunnel.namespace,
+		"transport", tunnel.transport,
 	)
 
 	// Find the pod to port forward to
@@ -511,7 +748,7 @@ func (tunnel *Tunnel) establish(ctx context.Context) ([]string, error) {
 
 	l.Debug("using URL to create portforward", "url", portForwardCreateURL)
 
-	dialer, err := createDialer(http.MethodPost, portForwardCreateURL, tunnel.restConfig)
+	dialer, err := createDialer(http.MethodPost, portForwardCreateURL, tunnel.restConfig, tunnel.transport)
 	if err != nil {
 		return []string{}, fmt.Errorf("unable to create the dialer %w", err)
 	}
@@ -544,6 +781,7 @@ func (tunnel *Tunnel) establish(ctx context.Context) ([]string, error) {
 
 		// Store the error channel to listen for errors
 		tunnel.errChan = errChan
+		tunnel.podName = podName
 
 		l.Debug("creating port forwarding tunnel", "urls", urls)
 		return urls, nil
@@ -555,6 +793,9 @@ func (tunnel *Tunnel) establish(ctx context.Context) ([]string, error) {
 func (tunnel *Tunnel) getAttachablePodForResource(ctx context.Context) (string, error) {
 	switch tunnel.resourceType {
 	case PodResource:
+		if isPodSelector(tunnel.resourceName) {
+			return tunnel.getAttachablePodForSelector(ctx)
+		}
 		return tunnel.resourceName, nil
 	case SvcResource:
 		return tunnel.getAttachablePodForService(ctx)
@@ -587,19 +828,71 @@ func (tunnel *Tunnel) getAttachablePodForService(ctx context.Context) (string, e
 	return podList.Items[0].Name, nil
 }
 
-// Inspired by https://github.com/kubernetes/kubernetes/blob/680ea07dbb2c6050d13b93660fa4d27d2d28d6eb/staging/src/k8s.io/kubectl/pkg/cmd/portforward/portforward.go#L139-L156
-func createDialer(method string, url *url.URL, config *rest.Config) (httpstream.Dialer, error) {
-	transport, upgrader, err := spdy.RoundTripperFor(config)
+// isPodSelector reports whether the name of a pod resource is a label selector. Pod names can not contain the
+// operators of a selector so a name such as app=frontend is never an actual pod.
+func isPodSelector(name string) bool {
+	return strings.ContainsAny(name, "=!,()")
+}
+
+// getAttachablePodForSelector will find the first ready pod matching the label selector in the resource name and
+// return the pod name.
+func (tunnel *Tunnel) getAttachablePodForSelector(ctx context.Context) (string, error) {
+	selector, err := labels.Parse(tunnel.resourceName)
 	if err != nil {
-		return nil, err
+		return "", fmt.Errorf("invalid pod label selector %s: %w", tunnel.resourceName, err)
+	}
+	podList, err := tunnel.clientset.CoreV1().Pods(tunnel.namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return "", err
+	}
+	pods := podList.Items
+	slices.SortFunc(pods, func(a, b corev1.Pod) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	for _, pod := range pods {
+		if !isPodReady(pod) {
+			continue
+		}
+		logger.From(ctx).Info("selected pod for the tunnel", "selector", tunnel.resourceName, "pod", pod.Name, "matching", len(pods))
+		return pod.Name, nil
+	}
+	return "", fmt.Errorf("no ready pods found for selector %s in namespace %s", tunnel.resourceName, tunnel.namespace)
+}
+
+func isPodReady(pod corev1.Pod) bool {
+	if pod.Status.Phase != corev1.PodRunning {
+		return false
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// Inspired by https://github.com/kubernetes/kubernetes/blob/680ea07dbb2c6050d13b93660fa4d27d2d28d6eb/staging/src/k8s.io/kubectl/pkg/cmd/portforward/portforward.go#L139-L156
+func createDialer(method string, url *url.URL, config *rest.Config, transport TunnelTransport) (httpstream.Dialer, error) {
+	var spdyDialer httpstream.Dialer
+	if transport != TunnelTransportWebSocket {
+		roundTripper, upgrader, err := spdy.RoundTripperFor(config)
+		if err != nil {
+			return nil, err
+		}
+		spdyDialer = spdy.NewDialer(upgrader, &http.Client{Transport: roundTripper}, method, url)
+		if transport == TunnelTransportSPDY {
+			return spdyDialer, nil
+		}
 	}
-	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, method, url)
 	tunnelingDialer, err := portforward.NewSPDYOverWebsocketDialer(url, config)
 	if err != nil {
 		return nil, err
 	}
+	if transport == TunnelTransportWebSocket {
+		return tunnelingDialer, nil
+	}
 	// First attempt tunneling (websocket) dialer, then fallback to spdy dialer.
-	dialer = portforward.NewFallbackDialer(tunnelingDialer, dialer, func(err error) bool {
+	dialer := portforward.NewFallbackDialer(tunnelingDialer, spdyDialer, func(err error) bool {
 		return httpstream.IsUpgradeFailure(err) || httpstream.IsHTTPSProxyError(err)
 	})
 	return dialer, nil
@@ -4,15 +4,25 @@
 package cluster
 
 import (
+	"bytes"
 	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/zarf-dev/zarf/src/pkg/state"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/portforward"
 )
 
 func TestListConnections(t *testing.T) {
@@ -49,6 +59,51 @@ func TestListConnections(t *testing.T) {
 	require.Equal(t, expectedConnections, connections)
 }
 
+func TestListConnectionsTimeout(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	cs := fake.NewClientset()
+	cs.PrependReactor("list", "services", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		opts := action.(k8stesting.ListActionImpl).GetListOptions()
+		require.Equal(t, int64(connectionsPageSize), opts.Limit)
+		if opts.Continue != "" {
+			cancel()
+			return true, nil, ctx.Err()
+		}
+		svc := corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      "first",
+				Labels:    map[string]string{ZarfConnectLabelName: "first"},
+			},
+		}
+		return true, &corev1.ServiceList{ListMeta: metav1.ListMeta{Continue: "next"}, Items: []corev1.Service{svc}}, nil
+	})
+	c := &Cluster{Clientset: cs}
+
+	connections, err := c.ListConnections(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, state.ConnectStrings{"first": state.ConnectString{}}, connections)
+}
+
+func TestListConnectionsTimeoutFirstPage(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cs := fake.NewClientset()
+	cs.PrependReactor("list", "services", func(k8stesting.Action) (bool, runtime.Object, error) {
+		cancel()
+		return true, nil, ctx.Err()
+	})
+	c := &Cluster{Clientset: cs}
+
+	connections, err := c.ListConnections(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, state.ConnectStrings{}, connections)
+}
+
 func TestCheckForZarfConnectLabel(t *testing.T) {
 	t.Parallel()
 
@@ -129,6 +184,134 @@ func TestCheckForZarfConnectLabel(t *testing.T) {
 	}
 }
 
+func TestNewTargetTunnelInfoNamespace(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "podinfo",
+			Name:      "podinfo",
+			Labels: map[string]string{
+				ZarfConnectLabelName: "podinfo",
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{
+					Port:       9898,
+					TargetPort: intstr.FromInt(9898),
+				},
+			},
+		},
+	}
+	c := &Cluster{
+		Clientset: fake.NewClientset(svc),
+	}
+
+	tests := []struct {
+		target            string
+		expectedNamespace string
+	}{
+		{target: "registry", expectedNamespace: state.ZarfNamespaceName},
+		{target: "GIT", expectedNamespace: state.ZarfNamespaceName},
+		{target: "podinfo", expectedNamespace: "podinfo"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			t.Parallel()
+			ti, err := c.NewTargetTunnelInfo(ctx, tt.target)
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedNamespace, ti.Namespace)
+		})
+	}
+}
+
+func TestNewTargetTunnelInfoWithWait(t *testing.T) {
+	t.Parallel()
+
+	t.Run("resolves once the service is created", func(t *testing.T) {
+		t.Parallel()
+		c := &Cluster{
+			Clientset: fake.NewClientset(),
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		errCh := make(chan error, 1)
+		go func() {
+			time.Sleep(500 * time.Millisecond)
+			svc := &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: state.ZarfNamespaceName,
+					Name:      ZarfRegistryName,
+				},
+			}
+			_, err := c.Clientset.CoreV1().Services(svc.Namespace).Create(ctx, svc, metav1.CreateOptions{})
+			errCh <- err
+		}()
+
+		ti, err := c.NewTargetTunnelInfoWithWait(ctx, "registry")
+		require.NoError(t, err)
+		require.NoError(t, <-errCh)
+		require.Equal(t, ZarfRegistryName, ti.ResourceName)
+		require.Equal(t, state.ZarfNamespaceName, ti.Namespace)
+		require.Equal(t, ZarfRegistryPort, ti.RemotePort)
+	})
+
+	t.Run("resolves once the connect label exists", func(t *testing.T) {
+		t.Parallel()
+		c := &Cluster{
+			Clientset: fake.NewClientset(),
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		errCh := make(chan error, 1)
+		go func() {
+			time.Sleep(500 * time.Millisecond)
+			svc := &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "app-ns",
+					Name:      "web",
+					Labels: map[string]string{
+						ZarfConnectLabelName: "web-ui",
+					},
+				},
+				Spec: corev1.ServiceSpec{
+					Ports: []corev1.ServicePort{
+						{
+							Port:       8080,
+							TargetPort: intstr.FromInt(8080),
+						},
+					},
+				},
+			}
+			_, err := c.Clientset.CoreV1().Services(svc.Namespace).Create(ctx, svc, metav1.CreateOptions{})
+			errCh <- err
+		}()
+
+		ti, err := c.NewTargetTunnelInfoWithWait(ctx, "web-ui")
+		require.NoError(t, err)
+		require.NoError(t, <-errCh)
+		require.Equal(t, "web", ti.ResourceName)
+		require.Equal(t, "app-ns", ti.Namespace)
+		require.Equal(t, 8080, ti.RemotePort)
+	})
+
+	t.Run("times out when the service never exists", func(t *testing.T) {
+		t.Parallel()
+		c := &Cluster{
+			Clientset: fake.NewClientset(),
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 1500*time.Millisecond)
+		defer cancel()
+
+		_, err := c.NewTargetTunnelInfoWithWait(ctx, "git")
+		require.Error(t, err)
+	})
+}
+
 func TestFindPodContainerPort(t *testing.T) {
 	t.Parallel()
 
@@ -330,3 +513,230 @@ func TestServiceInfoFromNodePortURL(t *testing.T) {
 		})
 	}
 }
+
+func TestParseTunnelTransport(t *testing.T) {
+	t.Parallel()
+
+	transport, err := ParseTunnelTransport("")
+	require.NoError(t, err)
+	require.Equal(t, TunnelTransportAuto, transport)
+	transport, err = ParseTunnelTransport("WebSocket")
+	require.NoError(t, err)
+	require.Equal(t, TunnelTransportWebSocket, transport)
+	_, err = ParseTunnelTransport("http2")
+	require.EqualError(t, err, `invalid tunnel transport "http2", must be one of auto, websocket or spdy`)
+}
+
+func TestParseTunnelProtocol(t *testing.T) {
+	t.Parallel()
+
+	protocol, err := ParseTunnelProtocol("")
+	require.NoError(t, err)
+	require.Equal(t, TunnelProtocolTCP, protocol)
+	protocol, err = ParseTunnelProtocol("UDP")
+	require.NoError(t, err)
+	require.Equal(t, TunnelProtocolUDP, protocol)
+	_, err = ParseTunnelProtocol("sctp")
+	require.EqualError(t, err, `invalid tunnel protocol "sctp", must be one of tcp or udp`)
+}
+
+func TestTunnelFullURLs(t *testing.T) {
+	t.Parallel()
+
+	tunnel, err := (&Cluster{}).NewTunnel("zarf", SvcResource, "podinfo", "/ui", 42000, 9898, WithListenAddress([]string{"0.0.0.0", "::1"}))
+	require.NoError(t, err)
+	require.Equal(t, []string{"0.0.0.0:42000", "[::1]:42000"}, tunnel.Endpoints())
+	require.Equal(t, []string{"http://0.0.0.0:42000/ui", "http://[::1]:42000/ui"}, tunnel.FullURLs())
+
+	tunnel, err = (&Cluster{}).NewTunnel("kube-system", SvcResource, "kube-dns", "", 42053, 53, WithProtocol(TunnelProtocolUDP))
+	require.NoError(t, err)
+	require.Equal(t, []string{"udp://127.0.0.1:42053"}, tunnel.FullURLs())
+	require.Equal(t, TunnelProtocolUDP, tunnel.Info().Protocol)
+}
+
+func TestCreateDialer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		transport        TunnelTransport
+		expectedUpgrades []string
+	}{
+		{
+			transport:        TunnelTransportSPDY,
+			expectedUpgrades: []string{"SPDY/3.1"},
+		},
+		{
+			transport:        TunnelTransportWebSocket,
+			expectedUpgrades: []string{"websocket"},
+		},
+		{
+			// The fake server rejects every upgrade, so auto falls back to SPDY
+			transport:        TunnelTransportAuto,
+			expectedUpgrades: []string{"websocket", "SPDY/3.1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.transport), func(t *testing.T) {
+			t.Parallel()
+
+			var mu sync.Mutex
+			upgrades := []string{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				upgrades = append(upgrades, r.Header.Get("Upgrade"))
+				mu.Unlock()
+				w.WriteHeader(http.StatusBadRequest)
+			}))
+			t.Cleanup(server.Close)
+
+			u, err := url.Parse(server.URL + "/api/v1/namespaces/default/pods/test/portforward")
+			require.NoError(t, err)
+			dialer, err := createDialer(http.MethodPost, u, &rest.Config{Host: server.URL}, tt.transport)
+			require.NoError(t, err)
+			_, _, err = dialer.Dial(portforward.PortForwardProtocolV1Name)
+			require.Error(t, err)
+
+			mu.Lock()
+			defer mu.Unlock()
+			require.Equal(t, tt.expectedUpgrades, upgrades)
+		})
+	}
+}
+
+func TestGetAttachablePodForSelector(t *testing.T) {
+	t.Parallel()
+
+	newPod := func(name string, podLabels map[string]string, ready bool) *corev1.Pod {
+		status := corev1.ConditionFalse
+		if ready {
+			status = corev1.ConditionTrue
+		}
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "app", Labels: podLabels},
+			Status: corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}},
+			},
+		}
+	}
+	c := &Cluster{
+		Clientset: fake.NewClientset(
+			newPod("frontend-a", map[string]string{"app": "frontend"}, false),
+			newPod("frontend-b", map[string]string{"app": "frontend"}, true),
+			newPod("frontend-c", map[string]string{"app": "frontend"}, true),
+			newPod("backend-a", map[string]string{"app": "backend"}, true),
+		),
+	}
+
+	tunnel, err := c.NewTunnel("app", PodResource, "app=frontend", "", 0, 8080)
+	require.NoError(t, err)
+	name, err := tunnel.getAttachablePodForResource(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "frontend-b", name)
+
+	tunnel, err = c.NewTunnel("app", PodResource, "app=database", "", 0, 8080)
+	require.NoError(t, err)
+	_, err = tunnel.getAttachablePodForResource(context.Background())
+	require.EqualError(t, err, "no ready pods found for selector app=database in namespace app")
+
+	// A plain pod name is used as is
+	tunnel, err = c.NewTunnel("app", PodResource, "backend-a", "", 0, 8080)
+	require.NoError(t, err)
+	name, err = tunnel.getAttachablePodForResource(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "backend-a", name)
+}
+
+func TestTunnelStreamLogs(t *testing.T) {
+	t.Parallel()
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "podinfo-abc", Namespace: "podinfo"},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "podinfo"}}},
+	}
+	newTunnel := func() *Tunnel {
+		return &Tunnel{
+			clientset:  fake.NewClientset(pod),
+			namespace:  "podinfo",
+			remotePort: 9898,
+			podName:    "podinfo-abc",
+			stopChan:   make(chan struct{}, 1),
+		}
+	}
+
+	t.Run("logs are written", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		err := newTunnel().StreamLogs(context.Background(), &buf, TunnelLogOptions{Since: 1500 * time.Millisecond, Tail: 10})
+		require.NoError(t, err)
+		require.Equal(t, "fake logs", buf.String())
+	})
+
+	t.Run("closed tunnel stops the stream", func(t *testing.T) {
+		t.Parallel()
+		tunnel := newTunnel()
+		tunnel.Close()
+		var buf bytes.Buffer
+		err := tunnel.StreamLogs(context.Background(), &buf, TunnelLogOptions{Tail: -1})
+		require.NoError(t, err)
+	})
+
+	t.Run("tunnel that is not established", func(t *testing.T) {
+		t.Parallel()
+		tunnel := newTunnel()
+		tunnel.podName = ""
+		err := tunnel.StreamLogs(context.Background(), &bytes.Buffer{}, TunnelLogOptions{Tail: -1})
+		require.EqualError(t, err, "the tunnel is not established")
+	})
+
+	t.Run("udp tunnel", func(t *testing.T) {
+		t.Parallel()
+		tunnel := newTunnel()
+		tunnel.protocol = TunnelProtocolUDP
+		err := tunnel.StreamLogs(context.Background(), &bytes.Buffer{}, TunnelLogOptions{Tail: -1})
+		require.EqualError(t, err, "logs are not supported for udp tunnels")
+	})
+}
+
+func TestLogContainerForPort(t *testing.T) {
+	t.Parallel()
+
+	containers := []corev1.Container{
+		{Name: "istio-proxy", Ports: []corev1.ContainerPort{{ContainerPort: 15090}}},
+		{Name: "app", Ports: []corev1.ContainerPort{{ContainerPort: 8080}}},
+		{Name: "sidecar"},
+	}
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		port        int
+		expected    string
+	}{
+		{
+			name:     "container declaring the port",
+			port:     8080,
+			expected: "app",
+		},
+		{
+			name:        "default container annotation",
+			annotations: map[string]string{"kubectl.kubernetes.io/default-container": "sidecar"},
+			port:        9000,
+			expected:    "sidecar",
+		},
+		{
+			name:     "first container",
+			port:     9000,
+			expected: "istio-proxy",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			pod := corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations},
+				Spec:       corev1.PodSpec{Containers: containers},
+			}
+			require.Equal(t, tt.expected, logContainerForPort(pod, tt.port))
+		})
+	}
+}
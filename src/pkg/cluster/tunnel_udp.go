@@ -0,0 +1,419 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package cluster
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/defenseunicorns/pkg/helpers/v2"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/zarf-dev/zarf/src/pkg/logger"
+	"github.com/zarf-dev/zarf/src/pkg/state"
+)
+
+// UDP relay configuration. The port forward of the API server only carries TCP, so UDP tunnels port forward to a relay
+// pod that runs the Zarf agent image to forward the datagrams to the UDP port of the resource. Every datagram is sent
+// through the TCP stream with a two byte length prefix so that the datagram boundaries are kept in both directions.
+const (
+	ZarfUDPRelayName = "zarf-udp-relay"
+	ZarfUDPRelayPort = 5000
+
+	udpRelayReadyTimeout = 2 * time.Minute
+	// udpMaxDatagramSize is the largest payload of a UDP datagram
+	udpMaxDatagramSize = 65535
+)
+
+// udpRelay holds the relay pod, the port forward to it and the UDP listeners of a UDP tunnel.
+type udpRelay struct {
+	podName string
+	tunnel  *Tunnel
+	conns   []net.PacketConn
+
+	mu       sync.Mutex
+	closed   bool
+	sessions map[string]net.Conn
+}
+
+// establishUDP starts a relay pod next to the resource, port forwards to it and relays the UDP datagrams received on
+// the listen addresses through the port forward. Everything is cleaned up when the tunnel can not be established.
+func (tunnel *Tunnel) establishUDP(ctx context.Context) (_ []string, err error) {
+	l := logger.From(ctx)
+
+	targetIP, err := tunnel.udpTargetIP(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	relay := &udpRelay{sessions: map[string]net.Conn{}}
+	defer func() {
+		if err != nil {
+			relay.close(context.WithoutCancel(ctx), tunnel)
+		}
+	}()
+
+	agentPodSpec, err := tunnel.agentPodSpec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	relayPod := buildUDPRelayPod(tunnel.namespace, agentPodSpec.Containers[0].Image, agentPodSpec.ImagePullSecrets, targetIP, tunnel.remotePort)
+	pod, err := tunnel.clientset.CoreV1().Pods(tunnel.namespace).Create(ctx, relayPod, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to create the UDP relay pod: %w", err)
+	}
+	relay.podName = pod.Name
+	l.Debug("created UDP relay pod", "name", pod.Name, "namespace", tunnel.namespace, "target", net.JoinHostPort(targetIP, strconv.Itoa(tunnel.remotePort)))
+
+	if err := tunnel.waitForUDPRelay(ctx, pod.Name); err != nil {
+		return nil, err
+	}
+
+	relay.tunnel = &Tunnel{
+		clientset:     tunnel.clientset,
+		restConfig:    tunnel.restConfig,
+		remotePort:    ZarfUDPRelayPort,
+		namespace:     tunnel.namespace,
+		resourceType:  PodResource,
+		resourceName:  pod.Name,
+		listenAddress: []string{"127.0.0.1"},
+		transport:     tunnel.transport,
+		protocol:      TunnelProtocolTCP,
+		stopChan:      make(chan struct{}, 1),
+		readyChan:     make(chan struct{}, 1),
+	}
+	if _, err := relay.tunnel.establish(ctx); err != nil {
+		return nil, fmt.Errorf("unable to port forward to the UDP relay pod: %w", err)
+	}
+	relayEndpoint := relay.tunnel.Endpoints()[0]
+
+	localPort := tunnel.localPort
+	for _, addr := range tunnel.listenAddress {
+		conn, err := net.ListenPacket("udp", net.JoinHostPort(addr, strconv.Itoa(localPort)))
+		if err != nil {
+			return nil, fmt.Errorf("unable to listen for UDP on %s: %w", addr, err)
+		}
+		relay.conns = append(relay.conns, conn)
+		// Reuse the port selected for the first address on the others
+		if localPort == 0 {
+			localPort = conn.LocalAddr().(*net.UDPAddr).Port
+		}
+	}
+	for _, conn := range relay.conns {
+		go relay.serve(ctx, conn, relayEndpoint)
+	}
+
+	tunnel.localPort = localPort
+	tunnel.udpRelay = relay
+	tunnel.errChan = relay.tunnel.errChan
+	urls := tunnel.FullURLs()
+	l.Debug("creating UDP tunnel", "urls", urls)
+	return urls, nil
+}
+
+// udpTargetIP returns the IP of the pod the tunnel attaches to. The remote port is a port of the pod like it is for
+// TCP tunnels, so the relay targets the pod rather than the service.
+func (tunnel *Tunnel) udpTargetIP(ctx context.Context) (string, error) {
+	podName, err := tunnel.getAttachablePodForResource(ctx)
+	if err != nil {
+		return "", fmt.Errorf("unable to find pod attached to given resource: %w", err)
+	}
+	pod, err := tunnel.clientset.CoreV1().Pods(tunnel.namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	if pod.Status.PodIP == "" {
+		return "", fmt.Errorf("pod %s has no IP address", podName)
+	}
+	return pod.Status.PodIP, nil
+}
+
+// agentPodSpec returns the pod spec of the Zarf agent, whose image runs the relay. The image is already in the Zarf
+// registry of an air-gapped cluster.
+func (tunnel *Tunnel) agentPodSpec(ctx context.Context) (corev1.PodSpec, error) {
+	deployment, err := tunnel.clientset.AppsV1().Deployments(state.ZarfNamespaceName).Get(ctx, "agent-hook", metav1.GetOptions{})
+	if err != nil {
+		return corev1.PodSpec{}, fmt.Errorf("unable to find the Zarf agent image, UDP tunnels require the Zarf agent: %w", err)
+	}
+	if len(deployment.Spec.Template.Spec.Containers) == 0 {
+		return corev1.PodSpec{}, fmt.Errorf("unable to find the Zarf agent image, the agent deployment has no containers")
+	}
+	return deployment.Spec.Template.Spec, nil
+}
+
+// waitForUDPRelay waits until the relay pod accepts connections.
+func (tunnel *Tunnel) waitForUDPRelay(ctx context.Context, name string) error {
+	waitCtx, cancel := context.WithTimeout(ctx, udpRelayReadyTimeout)
+	defer cancel()
+	err := retry.Do(func() error {
+		pod, err := tunnel.clientset.CoreV1().Pods(tunnel.namespace).Get(waitCtx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if pod.Status.Phase == corev1.PodFailed || pod.Status.Phase == corev1.PodSucceeded {
+			return retry.Unrecoverable(fmt.Errorf("UDP relay pod %s stopped with phase %s", name, pod.Status.Phase))
+		}
+		if !isPodReady(*pod) {
+			return fmt.Errorf("UDP relay pod %s is not ready", name)
+		}
+		return nil
+	},
+		retry.Context(waitCtx),
+		retry.Attempts(0),
+		retry.DelayType(retry.FixedDelay),
+		retry.Delay(time.Second),
+		retry.LastErrorOnly(true),
+	)
+	if err != nil {
+		return fmt.Errorf("the UDP relay pod did not become ready: %w", err)
+	}
+	return nil
+}
+
+// buildUDPRelayPod returns a pod that forwards the datagrams of the TCP connections on the relay port to the UDP port of
+// the target.
+func buildUDPRelayPod(namespace, image string, pullSecrets []corev1.LocalObjectReference, targetIP string, targetPort int) *corev1.Pod {
+	// The agent image runs as the nonroot user of its base image
+	userID := int64(65532)
+	gracePeriod := int64(0)
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: ZarfUDPRelayName + "-",
+			Namespace:    namespace,
+			Labels: map[string]string{
+				"app":                    ZarfUDPRelayName,
+				state.ZarfManagedByLabel: "zarf",
+			},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy:                 corev1.RestartPolicyNever,
+			TerminationGracePeriodSeconds: &gracePeriod,
+			ImagePullSecrets:              pullSecrets,
+			SecurityContext: &corev1.PodSecurityContext{
+				RunAsNonRoot:   helpers.BoolPtr(true),
+				RunAsUser:      &userID,
+				SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+			},
+			Containers: []corev1.Container{
+				{
+					Name:            "relay",
+					Image:           image,
+					ImagePullPolicy: corev1.PullIfNotPresent,
+					Command: []string{
+						"/zarf", "internal", "udp-relay",
+						net.JoinHostPort(targetIP, strconv.Itoa(targetPort)),
+						"--port", strconv.Itoa(ZarfUDPRelayPort),
+					},
+					Ports: []corev1.ContainerPort{{ContainerPort: ZarfUDPRelayPort, Protocol: corev1.ProtocolTCP}},
+					ReadinessProbe: &corev1.Probe{
+						ProbeHandler:  corev1.ProbeHandler{TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt(ZarfUDPRelayPort)}},
+						PeriodSeconds: 1,
+					},
+					SecurityContext: &corev1.SecurityContext{
+						AllowPrivilegeEscalation: helpers.BoolPtr(false),
+						ReadOnlyRootFilesystem:   helpers.BoolPtr(true),
+						Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+					},
+				},
+			},
+		},
+	}
+}
+
+// serve relays the datagrams received on conn through a TCP connection to the relay endpoint per client address, the
+// replies of the target are sent back to the client.
+func (relay *udpRelay) serve(ctx context.Context, conn net.PacketConn, relayEndpoint string) {
+	l := logger.From(ctx)
+	buf := make([]byte, udpMaxDatagramSize)
+	for {
+		n, client, err := conn.ReadFrom(buf)
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				l.Debug("unable to read from the UDP listener", "address", conn.LocalAddr().String(), "error", err)
+			}
+			return
+		}
+		session, err := relay.session(ctx, conn, client, relayEndpoint)
+		if err != nil {
+			l.Debug("unable to connect to the UDP relay", "client", client.String(), "error", err)
+			continue
+		}
+		if err := writeDatagram(session, buf[:n]); err != nil {
+			l.Debug("unable to relay the datagram", "client", client.String(), "error", err)
+			relay.endSession(client.String())
+		}
+	}
+}
+
+// session returns the connection to the relay endpoint of the client, a connection is opened for new clients.
+func (relay *udpRelay) session(ctx context.Context, conn net.PacketConn, client net.Addr, relayEndpoint string) (net.Conn, error) {
+	relay.mu.Lock()
+	defer relay.mu.Unlock()
+	if relay.closed {
+		return nil, net.ErrClosed
+	}
+	if session, ok := relay.sessions[client.String()]; ok {
+		return session, nil
+	}
+	session, err := net.Dial("tcp", relayEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	relay.sessions[client.String()] = session
+
+	go func() {
+		defer relay.endSession(client.String())
+		buf := make([]byte, udpMaxDatagramSize)
+		for {
+			n, err := readDatagram(session, buf)
+			if err != nil {
+				return
+			}
+			if _, err := conn.WriteTo(buf[:n], client); err != nil {
+				logger.From(ctx).Debug("unable to send the reply to the UDP client", "client", client.String(), "error", err)
+				return
+			}
+		}
+	}()
+	return session, nil
+}
+
+// endSession closes the connection to the relay endpoint of the client.
+func (relay *udpRelay) endSession(client string) {
+	relay.mu.Lock()
+	defer relay.mu.Unlock()
+	if session, ok := relay.sessions[client]; ok {
+		_ = session.Close()
+		delete(relay.sessions, client)
+	}
+}
+
+// close stops the UDP listeners, the sessions and the port forward, and deletes the relay pod.
+func (relay *udpRelay) close(ctx context.Context, tunnel *Tunnel) {
+	relay.mu.Lock()
+	relay.closed = true
+	for client, session := range relay.sessions {
+		_ = session.Close()
+		delete(relay.sessions, client)
+	}
+	relay.mu.Unlock()
+
+	for _, conn := range relay.conns {
+		_ = conn.Close()
+	}
+	if relay.tunnel != nil {
+		relay.tunnel.Close()
+	}
+	if relay.podName == "" {
+		return
+	}
+	deleteCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	err := tunnel.clientset.CoreV1().Pods(tunnel.namespace).Delete(deleteCtx, relay.podName, metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		logger.From(ctx).Warn("unable to delete the UDP relay pod", "name", relay.podName, "namespace", tunnel.namespace, "error", err)
+	}
+}
+
+// ServeUDPRelay accepts the TCP connections of UDP tunnels on the listen address and relays the length prefixed datagrams
+// of each connection to the UDP target, the replies of the target are sent back through the connection. It runs in the
+// relay pod until the context is cancelled.
+func ServeUDPRelay(ctx context.Context, listenAddress, target string) error {
+	l := logger.From(ctx)
+	ln, err := net.Listen("tcp", listenAddress)
+	if err != nil {
+		return err
+	}
+	go func() {
+		<-ctx.Done()
+		_ = ln.Close()
+	}()
+	l.Info("relaying UDP datagrams", "address", ln.Addr().String(), "target", target)
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		go relayUDPConn(ctx, conn, target)
+	}
+}
+
+// relayUDPConn relays the datagrams of a single tunnel connection through its own UDP socket so that the replies of the
+// target reach the client that sent the request.
+func relayUDPConn(ctx context.Context, conn net.Conn, target string) {
+	l := logger.From(ctx)
+	defer conn.Close()
+	udpConn, err := net.Dial("udp", target)
+	if err != nil {
+		l.Warn("unable to connect to the UDP target", "target", target, "error", err)
+		return
+	}
+	defer udpConn.Close()
+
+	go func() {
+		// Closing the tunnel connection ends the request loop below
+		defer conn.Close()
+		buf := make([]byte, udpMaxDatagramSize)
+		for {
+			n, err := udpConn.Read(buf)
+			if err != nil {
+				return
+			}
+			if err := writeDatagram(conn, buf[:n]); err != nil {
+				return
+			}
+		}
+	}()
+
+	buf := make([]byte, udpMaxDatagramSize)
+	for {
+		n, err := readDatagram(conn, buf)
+		if err != nil {
+			return
+		}
+		if _, err := udpConn.Write(buf[:n]); err != nil {
+			l.Debug("unable to send the datagram to the UDP target", "target", target, "error", err)
+			return
+		}
+	}
+}
+
+// writeDatagram writes the datagram to the stream with a two byte big endian length prefix.
+func writeDatagram(w io.Writer, datagram []byte) error {
+	if len(datagram) > udpMaxDatagramSize {
+		return fmt.Errorf("datagram of %d bytes is larger than the maximum of %d bytes", len(datagram), udpMaxDatagramSize)
+	}
+	frame := make([]byte, 2+len(datagram))
+	binary.BigEndian.PutUint16(frame, uint16(len(datagram)))
+	copy(frame[2:], datagram)
+	_, err := w.Write(frame)
+	return err
+}
+
+// readDatagram reads a length prefixed datagram from the stream into buf, which holds at least udpMaxDatagramSize bytes.
+func readDatagram(r io.Reader, buf []byte) (int, error) {
+	var prefix [2]byte
+	if _, err := io.ReadFull(r, prefix[:]); err != nil {
+		return 0, err
+	}
+	n := int(binary.BigEndian.Uint16(prefix[:]))
+	if _, err := io.ReadFull(r, buf[:n]); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
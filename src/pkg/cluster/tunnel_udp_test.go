@@ -0,0 +1,188 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/pkg/state"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestBuildUDPRelayPod(t *testing.T) {
+	t.Parallel()
+
+	agentImage := "127.0.0.1:31999/zarf-dev/zarf/agent:v0.60.0"
+	pullSecrets := []corev1.LocalObjectReference{{Name: "private-registry"}}
+	pod := buildUDPRelayPod("kube-system", agentImage, pullSecrets, "10.42.0.7", 53)
+	require.Equal(t, "zarf-udp-relay-", pod.GenerateName)
+	require.Equal(t, "kube-system", pod.Namespace)
+	require.Equal(t, pullSecrets, pod.Spec.ImagePullSecrets)
+	require.Len(t, pod.Spec.Containers, 1)
+	require.Equal(t, agentImage, pod.Spec.Containers[0].Image)
+	require.Equal(t, []string{"/zarf", "internal", "udp-relay", "10.42.0.7:53", "--port", "5000"}, pod.Spec.Containers[0].Command)
+
+	pod = buildUDPRelayPod("default", agentImage, nil, "fd00::7", 5353)
+	require.Equal(t, "[fd00::7]:5353", pod.Spec.Containers[0].Command[3])
+}
+
+func TestAgentPodSpec(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tunnel := &Tunnel{clientset: fake.NewClientset()}
+	_, err := tunnel.agentPodSpec(ctx)
+	require.ErrorContains(t, err, "UDP tunnels require the Zarf agent")
+
+	tunnel.clientset = fake.NewClientset(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent-hook", Namespace: state.ZarfNamespaceName},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					ImagePullSecrets: []corev1.LocalObjectReference{{Name: "private-registry"}},
+					Containers:       []corev1.Container{{Name: "server", Image: "127.0.0.1:31999/zarf-dev/zarf/agent:v0.60.0"}},
+				},
+			},
+		},
+	})
+	spec, err := tunnel.agentPodSpec(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "127.0.0.1:31999/zarf-dev/zarf/agent:v0.60.0", spec.Containers[0].Image)
+	require.Equal(t, []corev1.LocalObjectReference{{Name: "private-registry"}}, spec.ImagePullSecrets)
+}
+
+func TestUDPTargetIP(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	cs := fake.NewClientset(
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "kube-dns", Namespace: "kube-system"},
+			Spec:       corev1.ServiceSpec{ClusterIP: "10.43.0.10", Selector: map[string]string{"k8s-app": "kube-dns"}},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "coredns-1", Namespace: "kube-system", Labels: map[string]string{"k8s-app": "kube-dns"}},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.42.0.7"},
+		},
+	)
+	c := &Cluster{Clientset: cs}
+
+	// The remote port is a port of the pod so the pod is targeted instead of the cluster IP of the service
+	tunnel, err := c.NewTunnel("kube-system", SvcResource, "kube-dns", "", 0, 53, WithProtocol(TunnelProtocolUDP))
+	require.NoError(t, err)
+	ip, err := tunnel.udpTargetIP(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "10.42.0.7", ip)
+
+	tunnel, err = c.NewTunnel("kube-system", SvcResource, "missing", "", 0, 53, WithProtocol(TunnelProtocolUDP))
+	require.NoError(t, err)
+	_, err = tunnel.udpTargetIP(ctx)
+	require.ErrorContains(t, err, "unable to find the service")
+}
+
+func TestDatagramFraming(t *testing.T) {
+	t.Parallel()
+
+	var stream bytes.Buffer
+	for _, datagram := range [][]byte{[]byte("first"), {}, []byte("second")} {
+		require.NoError(t, writeDatagram(&stream, datagram))
+	}
+	buf := make([]byte, udpMaxDatagramSize)
+	for _, expected := range []string{"first", "", "second"} {
+		n, err := readDatagram(&stream, buf)
+		require.NoError(t, err)
+		require.Equal(t, expected, string(buf[:n]))
+	}
+	_, err := readDatagram(&stream, buf)
+	require.ErrorIs(t, err, io.EOF)
+
+	require.Error(t, writeDatagram(&stream, make([]byte, udpMaxDatagramSize+1)))
+}
+
+func TestUDPRelayServe(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The UDP echo server stands in for the target and the relay endpoint for the port forward to the relay pod
+	target, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = target.Close() })
+	go func() {
+		buf := make([]byte, udpMaxDatagramSize)
+		for {
+			n, addr, err := target.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			_, _ = target.WriteTo(buf[:n], addr)
+		}
+	}()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	relayEndpoint := ln.Addr().String()
+	require.NoError(t, ln.Close())
+	go func() {
+		_ = ServeUDPRelay(ctx, relayEndpoint, target.LocalAddr().String())
+	}()
+	require.Eventually(t, func() bool {
+		conn, err := net.Dial("tcp", relayEndpoint)
+		if err != nil {
+			return false
+		}
+		_ = conn.Close()
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	relay := &udpRelay{conns: []net.PacketConn{listener}, sessions: map[string]net.Conn{}}
+	go relay.serve(ctx, listener, relayEndpoint)
+
+	client, err := net.Dial("udp", listener.LocalAddr().String())
+	require.NoError(t, err)
+	defer client.Close()
+	require.NoError(t, client.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	// Datagrams sent back to back keep their boundaries through the TCP stream
+	msgs := []string{"first", "second", strings.Repeat("x", 4096)}
+	for _, msg := range msgs {
+		_, err = client.Write([]byte(msg))
+		require.NoError(t, err)
+	}
+	for _, msg := range msgs {
+		buf := make([]byte, udpMaxDatagramSize)
+		n, err := client.Read(buf)
+		require.NoError(t, err)
+		require.Equal(t, msg, string(buf[:n]))
+	}
+	relay.mu.Lock()
+	require.Len(t, relay.sessions, 1)
+	relay.mu.Unlock()
+
+	// Closing the relay deletes its pod
+	relay.podName = "zarf-udp-relay-abcde"
+	cs := fake.NewClientset(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: relay.podName, Namespace: "kube-system"}})
+	tunnel := &Tunnel{clientset: cs, namespace: "kube-system"}
+	relay.close(ctx, tunnel)
+	_, err = cs.CoreV1().Pods("kube-system").Get(ctx, relay.podName, metav1.GetOptions{})
+	require.True(t, kerrors.IsNotFound(err))
+	relay.mu.Lock()
+	require.Empty(t, relay.sessions)
+	relay.mu.Unlock()
+	_, _, err = listener.ReadFrom(make([]byte, 1))
+	require.ErrorIs(t, err, net.ErrClosed)
+}
@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package images provides functions for building and pushing images.
+package images
+
+import (
+	"context"
+	"fmt"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/zarf-dev/zarf/src/internal/dns"
+	"github.com/zarf-dev/zarf/src/pkg/transform"
+	"golang.org/x/sync/errgroup"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/registry"
+	orasRemote "oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+)
+
+// PinDigests resolves the digest of every image that is only referenced by tag and returns a map of the original image
+// references to their pinned form, e.g. "nginx:1.25.3" to "nginx:1.25.3@sha256:...". Images that resolve to an index
+// are pinned to the manifest of the platform for opts.Arch. Images that already have a digest are not included.
+func PinDigests(ctx context.Context, imgs []string, opts PullOptions) (map[string]string, error) {
+	client, _, err := newPullClient(opts)
+	if err != nil {
+		return nil, err
+	}
+	platform := &ocispec.Platform{
+		Architecture: opts.Arch,
+		OS:           "linux",
+	}
+
+	pinned := make([]string, len(imgs))
+	eg, ectx := errgroup.WithContext(ctx)
+	eg.SetLimit(10)
+	for i, img := range imgs {
+		eg.Go(func() error {
+			refInfo, err := transform.ParseImageRef(img)
+			if err != nil {
+				return fmt.Errorf("failed to parse image ref %s: %w", img, err)
+			}
+			if refInfo.Digest != "" {
+				return nil
+			}
+			overridden := overrideImage(refInfo, opts.RegistryOverrides)
+			desc, err := resolveManifest(ectx, overridden.Reference, client, opts.PlainHTTP, platform)
+			if err != nil {
+				return fmt.Errorf("unable to resolve the digest of image %s: %w", img, err)
+			}
+			pinned[i] = fmt.Sprintf("%s@%s", img, desc.Digest)
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	mapping := map[string]string{}
+	for i, img := range imgs {
+		if pinned[i] == "" {
+			continue
+		}
+		mapping[img] = pinned[i]
+	}
+	return mapping, nil
+}
+
+// resolveManifest resolves the descriptor of the image manifest that ref points to, selecting the manifest for the
+// platform when ref points to an index.
+func resolveManifest(ctx context.Context, ref string, client *auth.Client, plainHTTP bool, platform *ocispec.Platform) (ocispec.Descriptor, error) {
+	repo := &orasRemote.Repository{Client: client}
+	var err error
+	repo.Reference, err = registry.ParseReference(ref)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	repo.PlainHTTP = plainHTTP
+	if dns.IsLocalhost(repo.Reference.Host()) && !plainHTTP {
+		// Unlike pulls there is no fallback to the Docker daemon, as images in the daemon have no registry digest
+		repo.PlainHTTP, err = ShouldUsePlainHTTP(ctx, repo.Reference.Host(), client)
+		if err != nil {
+			return ocispec.Descriptor{}, err
+		}
+	}
+	desc, err := oras.Resolve(ctx, repo, ref, oras.ResolveOptions{})
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	if isIndex(desc.MediaType) {
+		desc, err = oras.Resolve(ctx, repo, ref, oras.ResolveOptions{TargetPlatform: platform})
+		if err != nil {
+			return ocispec.Descriptor{}, fmt.Errorf("failed to resolve architecture %s: %w", platform.Architecture, err)
+		}
+	}
+	return desc, nil
+}
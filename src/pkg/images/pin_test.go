@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package images provides functions for building and pushing images.
+package images
+
+import (
+	"fmt"
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/test/testutil"
+)
+
+func TestPinDigests(t *testing.T) {
+	t.Parallel()
+	ctx := testutil.TestContext(t)
+	upstream := testutil.SetupInMemoryRegistryDynamic(ctx, t)
+
+	manifestDigest := testutil.PushImage(ctx, t, upstream+"/fixtures/img", "v1")
+
+	idxRepo := testutil.NewRepo(t, upstream+"/fixtures/idx")
+	children := []ocispec.Descriptor{}
+	for _, arch := range []string{"amd64", "arm64"} {
+		desc := testutil.PushSinglePlatformImage(ctx, t, idxRepo, arch)
+		desc.Platform = &ocispec.Platform{OS: "linux", Architecture: arch}
+		children = append(children, desc)
+	}
+	idx := testutil.PushIndex(ctx, t, idxRepo, children)
+	require.NoError(t, idxRepo.Tag(ctx, idx, "v1"))
+
+	tagged := fmt.Sprintf("%s/fixtures/img:v1", upstream)
+	multiArch := fmt.Sprintf("%s/fixtures/idx:v1", upstream)
+	digested := fmt.Sprintf("%s/fixtures/img@%s", upstream, manifestDigest)
+
+	opts := PullOptions{
+		Arch:      "arm64",
+		PlainHTTP: true,
+	}
+	pinned, err := PinDigests(ctx, []string{tagged, multiArch, digested}, opts)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		tagged:    fmt.Sprintf("%s@%s", tagged, manifestDigest),
+		multiArch: fmt.Sprintf("%s@%s", multiArch, children[1].Digest),
+	}, pinned)
+
+	missing := fmt.Sprintf("%s/fixtures/img:missing", upstream)
+	_, err = PinDigests(ctx, []string{tagged, missing}, opts)
+	require.ErrorContains(t, err, fmt.Sprintf("unable to resolve the digest of image %s", missing))
+}
@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package images provides functions for building and pushing images.
+package images
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/zarf-dev/zarf/src/pkg/transform"
+)
+
+// RegistryPolicy restricts the registries that the images of a package may come from.
+// Entries are a registry host (e.g. "ghcr.io") or a registry host and path prefix (e.g. "ghcr.io/zarf-dev").
+// Images without a registry resolve to "docker.io".
+type RegistryPolicy struct {
+	// AllowedRegistries requires every image to come from one of these registries when set.
+	AllowedRegistries []string
+	// DeniedRegistries rejects images from any of these registries, even if they are also allowed.
+	DeniedRegistries []string
+}
+
+// IsEmpty returns true if the policy has no rules.
+func (p RegistryPolicy) IsEmpty() bool {
+	return len(p.AllowedRegistries) == 0 && len(p.DeniedRegistries) == 0
+}
+
+// Validate checks every image against the policy and returns an error listing all offending images.
+func (p RegistryPolicy) Validate(imgs []string) error {
+	if p.IsEmpty() {
+		return nil
+	}
+
+	violations := []string{}
+	for _, img := range imgs {
+		refInfo, err := transform.ParseImageRef(img)
+		if err != nil {
+			return fmt.Errorf("failed to parse image ref %s: %w", img, err)
+		}
+		if denied, ok := matchRegistry(refInfo, p.DeniedRegistries); ok {
+			violations = append(violations, fmt.Sprintf("%s (denied registry %s)", img, denied))
+			continue
+		}
+		if len(p.AllowedRegistries) > 0 {
+			if _, ok := matchRegistry(refInfo, p.AllowedRegistries); !ok {
+				violations = append(violations, fmt.Sprintf("%s (registry %s is not allowed)", img, refInfo.Host))
+			}
+		}
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("%d image(s) violate the registry policy:\n - %s", len(violations), strings.Join(violations, "\n - "))
+	}
+	return nil
+}
+
+// matchRegistry returns the first registry the image belongs to.
+func matchRegistry(refInfo transform.Image, registries []string) (string, bool) {
+	for _, registry := range registries {
+		prefix := strings.TrimSuffix(strings.TrimPrefix(registry, helpers.OCIURLPrefix), "/")
+		if prefix == "" {
+			continue
+		}
+		if refInfo.Name == prefix || strings.HasPrefix(refInfo.Name, prefix+"/") {
+			return registry, true
+		}
+	}
+	return "", false
+}
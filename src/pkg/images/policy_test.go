@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package images provides functions for building and pushing images.
+package images
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryPolicyValidate(t *testing.T) {
+	t.Parallel()
+
+	imgs := []string{
+		"ghcr.io/zarf-dev/images/hello-world:latest",
+		"ghcr.io/stefanprodan/podinfo:6.4.0",
+		"registry1.dso.mil/ironbank/opensource/nginx/nginx:1.25.3",
+		"nginx:1.25.3",
+	}
+
+	tests := []struct {
+		name          string
+		policy        RegistryPolicy
+		imgs          []string
+		expectedError string
+	}{
+		{
+			name: "empty policy allows everything",
+			imgs: imgs,
+		},
+		{
+			name: "all images from allowed registries",
+			policy: RegistryPolicy{
+				AllowedRegistries: []string{"ghcr.io", "registry1.dso.mil", "docker.io"},
+			},
+			imgs: imgs,
+		},
+		{
+			name: "image from a registry that is not allowed",
+			policy: RegistryPolicy{
+				AllowedRegistries: []string{"ghcr.io", "docker.io"},
+			},
+			imgs:          imgs,
+			expectedError: "1 image(s) violate the registry policy:\n - registry1.dso.mil/ironbank/opensource/nginx/nginx:1.25.3 (registry registry1.dso.mil is not allowed)",
+		},
+		{
+			name: "allowed registry path prefix",
+			policy: RegistryPolicy{
+				AllowedRegistries: []string{"ghcr.io/zarf-dev/"},
+			},
+			imgs:          []string{"ghcr.io/zarf-dev/images/hello-world:latest", "ghcr.io/zarf-dev-fork/hello-world:latest"},
+			expectedError: "1 image(s) violate the registry policy:\n - ghcr.io/zarf-dev-fork/hello-world:latest (registry ghcr.io is not allowed)",
+		},
+		{
+			name: "denied registries",
+			policy: RegistryPolicy{
+				DeniedRegistries: []string{"docker.io", "oci://ghcr.io/stefanprodan"},
+			},
+			imgs:          imgs,
+			expectedError: "2 image(s) violate the registry policy:\n - ghcr.io/stefanprodan/podinfo:6.4.0 (denied registry oci://ghcr.io/stefanprodan)\n - nginx:1.25.3 (denied registry docker.io)",
+		},
+		{
+			name: "deny takes precedence over allow",
+			policy: RegistryPolicy{
+				AllowedRegistries: []string{"ghcr.io"},
+				DeniedRegistries:  []string{"ghcr.io/stefanprodan"},
+			},
+			imgs:          []string{"ghcr.io/zarf-dev/images/hello-world:latest", "ghcr.io/stefanprodan/podinfo:6.4.0"},
+			expectedError: "1 image(s) violate the registry policy:\n - ghcr.io/stefanprodan/podinfo:6.4.0 (denied registry ghcr.io/stefanprodan)",
+		},
+		{
+			name: "registry with a port",
+			policy: RegistryPolicy{
+				AllowedRegistries: []string{"localhost:5000"},
+			},
+			imgs:          []string{"localhost:5000/podinfo:6.4.0", "localhost:5001/podinfo:6.4.0"},
+			expectedError: "1 image(s) violate the registry policy:\n - localhost:5001/podinfo:6.4.0 (registry localhost:5001 is not allowed)",
+		},
+		{
+			name: "invalid image reference",
+			policy: RegistryPolicy{
+				DeniedRegistries: []string{"docker.io"},
+			},
+			imgs:          []string{"INVALID:::"},
+			expectedError: "failed to parse image ref INVALID:::",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.policy.Validate(tt.imgs)
+			if tt.expectedError != "" {
+				require.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
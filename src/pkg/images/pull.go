@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -30,7 +31,9 @@ import (
 	"github.com/zarf-dev/zarf/src/pkg/logger"
 	"golang.org/x/sync/errgroup"
 	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
 	"oras.land/oras-go/v2/content/oci"
+	"oras.land/oras-go/v2/errdef"
 	"oras.land/oras-go/v2/registry"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
@@ -44,9 +47,15 @@ import (
 	"oras.land/oras-go/v2/registry/remote/credentials"
 )
 
+// DefaultImageConcurrency is the amount of images pulled in parallel when PullOptions.ImageConcurrency is not set.
+const DefaultImageConcurrency = 4
+
 // PullOptions is the configuration for pulling images.
 type PullOptions struct {
-	OCIConcurrency        int
+	// OCIConcurrency is the amount of layers pulled in parallel across all the images pulled at a time
+	OCIConcurrency int
+	// ImageConcurrency is the amount of images pulled in parallel, defaults to DefaultImageConcurrency
+	ImageConcurrency      int
 	Arch                  string
 	RegistryOverrides     []RegistryOverride
 	CacheDirectory        string
@@ -95,40 +104,18 @@ func Pull(ctx context.Context, imageList []transform.Image, destinationDirectory
 	imagesWithOverride := []imageWithOverride{}
 	// Iterate over all images, marking each one as overridden.
 	for _, img := range imageList {
-		overriddenImage := img
-		for _, v := range opts.RegistryOverrides {
-			if strings.HasPrefix(img.Reference, v.Source) {
-				// If we have an override, the first override wins.
-				// Doing so allows earlier, longer prefixes (such as docker.io/library)
-				// to supersede shorter prefixes (such as docker.io).
-				overriddenImage.Reference = strings.Replace(img.Reference, v.Source, v.Override, 1)
-				break
-			}
-		}
 		imagesWithOverride = append(imagesWithOverride, imageWithOverride{
 			original:   img,
-			overridden: overriddenImage,
+			overridden: overrideImage(img, opts.RegistryOverrides),
 		})
 	}
 
 	imageFetchStart := time.Now()
 	l.Info("fetching info for images", "count", imageCount, "destination", destinationDirectory)
-	storeOpts := credentials.StoreOptions{}
-	credStore, err := credentials.NewStoreFromDocker(storeOpts)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get credentials: %w", err)
-	}
-	transport, err := orasTransport(opts.InsecureSkipTLSVerify, opts.ResponseHeaderTimeout)
+	client, credStore, err := newPullClient(opts)
 	if err != nil {
 		return nil, err
 	}
-	client := &auth.Client{
-		Client: &http.Client{
-			Transport: transport,
-		},
-		Cache:      auth.NewCache(),
-		Credential: credentials.Credential(credStore),
-	}
 	uniqueHosts := map[string]struct{}{}
 	for _, v := range imagesWithOverride {
 		uniqueHosts[v.overridden.Host] = struct{}{}
@@ -270,11 +257,25 @@ func Pull(ctx context.Context, imageList []transform.Image, destinationDirectory
 		imagesWithManifests = append(imagesWithManifests, daemonImagesWithManifests...)
 	}
 
+	// The cache store is shared by all the pulls as separate stores of the same directory would overwrite each others index
+	cacheStore, err := oci.NewWithContext(ctx, opts.CacheDirectory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create oci formatted directory: %w", err)
+	}
+	localCache := sharedCache{cacheStore}
+	imageConcurrency, layerConcurrency := pullConcurrency(opts.OCIConcurrency, opts.ImageConcurrency)
+	eg, ectx = errgroup.WithContext(ctx)
+	eg.SetLimit(imageConcurrency)
 	for _, imageInfo := range imagesInfo {
-		err = orasSave(ctx, imageInfo, opts, dst, client)
-		if err != nil {
-			return nil, fmt.Errorf("failed to save images: %w", err)
-		}
+		eg.Go(func() error {
+			if err := orasSave(ectx, imageInfo, opts, layerConcurrency, dst, localCache, client); err != nil {
+				return fmt.Errorf("failed to save image %s: %w", imageInfo.ref, err)
+			}
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
 	}
 
 	l.Info("done pulling images", "count", imageCount, "duration", time.Since(pullStart).Round(time.Millisecond*100))
@@ -282,6 +283,69 @@ func Pull(ctx context.Context, imageList []transform.Image, destinationDirectory
 	return imagesWithManifests, nil
 }
 
+// pullConcurrency returns the amount of images pulled in parallel and the amount of layers pulled in parallel for each
+// image, so that the layers pulled at a time across all the images stay within the OCI concurrency.
+func pullConcurrency(ociConcurrency, imageConcurrency int) (int, int) {
+	if imageConcurrency <= 0 {
+		imageConcurrency = DefaultImageConcurrency
+	}
+	if ociConcurrency <= 0 {
+		ociConcurrency = imageConcurrency
+	}
+	imageConcurrency = min(imageConcurrency, ociConcurrency)
+	return imageConcurrency, ociConcurrency / imageConcurrency
+}
+
+// sharedCache is the content store of the image cache shared by the images pulled in parallel. Images that share a
+// layer can both miss the cache and push the layer, the second push is not an error.
+type sharedCache struct {
+	content.Storage
+}
+
+// Push pushes the content to the cache, content that another pull already pushed is read to the end and discarded so
+// that the pull reading through the cache can continue.
+func (c sharedCache) Push(ctx context.Context, expected ocispec.Descriptor, r io.Reader) error {
+	err := c.Storage.Push(ctx, expected, r)
+	if errors.Is(err, errdef.ErrAlreadyExists) {
+		_, err = io.Copy(io.Discard, r)
+	}
+	return err
+}
+
+// overrideImage returns the image with the first matching registry override applied to its reference.
+func overrideImage(img transform.Image, overrides []RegistryOverride) transform.Image {
+	for _, v := range overrides {
+		if strings.HasPrefix(img.Reference, v.Source) {
+			// If we have an override, the first override wins.
+			// Doing so allows earlier, longer prefixes (such as docker.io/library)
+			// to supersede shorter prefixes (such as docker.io).
+			img.Reference = strings.Replace(img.Reference, v.Source, v.Override, 1)
+			break
+		}
+	}
+	return img
+}
+
+// newPullClient returns a registry client that authenticates with the credentials from the default Docker config file.
+func newPullClient(opts PullOptions) (*auth.Client, *credentials.DynamicStore, error) {
+	credStore, err := credentials.NewStoreFromDocker(credentials.StoreOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get credentials: %w", err)
+	}
+	transport, err := orasTransport(opts.InsecureSkipTLSVerify, opts.ResponseHeaderTimeout)
+	if err != nil {
+		return nil, nil, err
+	}
+	client := &auth.Client{
+		Client: &http.Client{
+			Transport: transport,
+		},
+		Cache:      auth.NewCache(),
+		Credential: credentials.Credential(credStore),
+	}
+	return client, credStore, nil
+}
+
 func constructIndexError(idx ocispec.Index, image transform.Image) error {
 	lines := []string{"The following images are available in the index:"}
 	name := image.Name
@@ -444,7 +508,7 @@ func pullFromDockerDaemon(ctx context.Context, daemonImages []imageWithOverride,
 	return imagesWithManifests, nil
 }
 
-func orasSave(ctx context.Context, imageInfo imagePullInfo, opts PullOptions, dst *oci.Store, client *auth.Client) error {
+func orasSave(ctx context.Context, imageInfo imagePullInfo, opts PullOptions, layerConcurrency int, dst *oci.Store, localCache content.Storage, client *auth.Client) error {
 	l := logger.From(ctx)
 	var pullSrc oras.ReadOnlyTarget
 	var err error
@@ -463,13 +527,9 @@ func orasSave(ctx context.Context, imageInfo imagePullInfo, opts PullOptions, ds
 	repo.Client = client
 
 	copyOpts := oras.DefaultCopyOptions
-	copyOpts.Concurrency = opts.OCIConcurrency
+	copyOpts.Concurrency = layerConcurrency
 	copyOpts.WithTargetPlatform(imageInfo.manifestDesc.Platform)
 	l.Info("saving image", "name", imageInfo.registryOverrideRef, "size", utils.ByteFormat(float64(imageInfo.byteSize), 2))
-	localCache, err := oci.NewWithContext(ctx, opts.CacheDirectory)
-	if err != nil {
-		return fmt.Errorf("failed to create oci formatted directory: %w", err)
-	}
 	pullSrc = orasCache.New(repo, localCache)
 	var desc ocispec.Descriptor
 	err = retry.Do(
@@ -10,6 +10,7 @@ import (
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"testing"
@@ -20,6 +21,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/zarf-dev/zarf/src/pkg/transform"
 	"github.com/zarf-dev/zarf/src/test/testutil"
+	"oras.land/oras-go/v2/content/oci"
 	"oras.land/oras-go/v2/registry/remote"
 )
 
@@ -275,3 +277,119 @@ func TestPullInvalidCache(t *testing.T) {
 	pulledLayerSha := sha256.Sum256(pulledLayer)
 	require.Equal(t, correctLayerSha, fmt.Sprintf("%x", pulledLayerSha))
 }
+
+func TestPullConcurrent(t *testing.T) {
+	t.Parallel()
+	ctx := testutil.TestContext(t)
+	upstream := testutil.SetupInMemoryRegistryDynamic(ctx, t)
+
+	// Every image shares a layer so the concurrent pulls write the same blob to the destination and the cache
+	sharedLayer := testutil.RandomBytes(t, 1024)
+	imgs := []transform.Image{}
+	for i := range 8 {
+		repo := testutil.NewRepo(t, fmt.Sprintf("%s/fixtures/concurrent-%d", upstream, i))
+		shared := testutil.PushBlob(ctx, t, repo, ocispec.MediaTypeImageLayer, sharedLayer)
+		layer := testutil.PushBlob(ctx, t, repo, ocispec.MediaTypeImageLayer, testutil.RandomBytes(t, 128))
+		config := testutil.PushBlob(ctx, t, repo, ocispec.MediaTypeImageConfig, []byte(`{"architecture":"amd64"}`))
+		manifest := testutil.PushManifest(ctx, t, repo, config, []ocispec.Descriptor{shared, layer})
+		require.NoError(t, repo.Tag(ctx, manifest, "v1"))
+		img, err := transform.ParseImageRef(fmt.Sprintf("%s/fixtures/concurrent-%d:v1", upstream, i))
+		require.NoError(t, err)
+		imgs = append(imgs, img)
+	}
+
+	destDir := t.TempDir()
+	cacheDir := t.TempDir()
+	imageManifests, err := Pull(ctx, imgs, destDir, PullOptions{
+		CacheDirectory:   cacheDir,
+		Arch:             "amd64",
+		PlainHTTP:        true,
+		ImageConcurrency: 3,
+	})
+	require.NoError(t, err)
+	require.Len(t, imageManifests, len(imgs))
+
+	idx, err := getIndexFromOCILayout(destDir)
+	require.NoError(t, err)
+	refs := []string{}
+	for _, manifest := range idx.Manifests {
+		refs = append(refs, manifest.Annotations[ocispec.AnnotationRefName])
+	}
+	expected := []string{}
+	for _, img := range imgs {
+		expected = append(expected, img.Reference)
+	}
+	require.ElementsMatch(t, expected, refs)
+
+	// The cache is written through a single store so its index stays valid
+	_, err = getIndexFromOCILayout(cacheDir)
+	require.NoError(t, err)
+	for _, imageWithManifest := range imageManifests {
+		for _, layer := range imageWithManifest.Manifest.Layers {
+			require.FileExists(t, filepath.Join(cacheDir, "blobs", "sha256", layer.Digest.Hex()))
+		}
+	}
+}
+
+func TestPullConcurrency(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		ociConcurrency   int
+		imageConcurrency int
+		expectedImages   int
+		expectedLayers   int
+	}{
+		{
+			name:             "layers are split between the images",
+			ociConcurrency:   12,
+			imageConcurrency: 4,
+			expectedImages:   4,
+			expectedLayers:   3,
+		},
+		{
+			name:             "images are limited to the OCI concurrency",
+			ociConcurrency:   2,
+			imageConcurrency: 4,
+			expectedImages:   2,
+			expectedLayers:   1,
+		},
+		{
+			name:           "defaults pull a layer per image",
+			expectedImages: DefaultImageConcurrency,
+			expectedLayers: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			images, layers := pullConcurrency(tt.ociConcurrency, tt.imageConcurrency)
+			require.Equal(t, tt.expectedImages, images)
+			require.Equal(t, tt.expectedLayers, layers)
+		})
+	}
+}
+
+func TestSharedCachePushExisting(t *testing.T) {
+	t.Parallel()
+	ctx := testutil.TestContext(t)
+
+	store, err := oci.NewWithContext(ctx, t.TempDir())
+	require.NoError(t, err)
+	cache := sharedCache{store}
+	blob := testutil.RandomBytes(t, 1024)
+	desc := ocispec.Descriptor{MediaType: ocispec.MediaTypeImageLayer, Digest: digest.FromBytes(blob), Size: int64(len(blob))}
+	require.NoError(t, cache.Push(ctx, desc, bytes.NewReader(blob)))
+
+	// A second push of the same layer succeeds and reads the content so a writer teeing into the cache is not blocked
+	pr, pw := io.Pipe()
+	go func() {
+		_, err := pw.Write(blob)
+		pw.CloseWithError(err)
+	}()
+	require.NoError(t, cache.Push(ctx, desc, pr))
+	exists, err := cache.Exists(ctx, desc)
+	require.NoError(t, err)
+	require.True(t, exists)
+}
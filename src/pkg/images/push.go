@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 
@@ -41,6 +42,11 @@ type PushOptions struct {
 	InsecureSkipTLSVerify bool
 	Cluster               *cluster.Cluster
 	ResponseHeaderTimeout time.Duration
+	// SkipExisting does not push an image when the registry already has the same digest under the target reference
+	SkipExisting bool
+	// PinnedImages are the images pinned to their digest on package create, which are also pushed under their tag so that
+	// workloads referencing the tag are rewritten by the agent to the pinned image
+	PinnedImages []string
 }
 
 // Push pushes images to a registry.
@@ -78,6 +84,7 @@ func Push(ctx context.Context, imageList []transform.Image, sourceDirectory stri
 		return fmt.Errorf("failed to instantiate oci directory: %w", err)
 	}
 
+	skippedCount := 0
 	err = retry.Do(func() error {
 		// reset concurrency to user-provided value on each component retry
 		ociConcurrency := cfg.OCIConcurrency
@@ -143,25 +150,29 @@ func Push(ctx context.Context, imageList []transform.Image, sourceDirectory stri
 			}
 		}
 
-		pushImage := func(srcName, dstName string) error {
+		pushImage := func(srcName, dstName string) (bool, error) {
 			remoteRepo := &orasRemote.Repository{
 				PlainHTTP: plainHTTP,
 				Client:    client,
 			}
 			remoteRepo.Reference, err = registry.ParseReference(dstName)
 			if err != nil {
-				return fmt.Errorf("failed to parse ref %s: %w", dstName, err)
+				return false, fmt.Errorf("failed to parse ref %s: %w", dstName, err)
 			}
 			defaultPlatform := &ocispec.Platform{
 				Architecture: cfg.Arch,
 				OS:           "linux",
 			}
 			if tunnel != nil {
-				return tunnel.Wrap(func() error {
-					return copyImage(ctx, src, remoteRepo, srcName, dstName, ociConcurrency, defaultPlatform)
+				var skipped bool
+				err := tunnel.Wrap(func() error {
+					var err error
+					skipped, err = copyImage(ctx, src, remoteRepo, srcName, dstName, ociConcurrency, defaultPlatform, cfg.SkipExisting)
+					return err
 				})
+				return skipped, err
 			}
-			return copyImage(ctx, src, remoteRepo, srcName, dstName, ociConcurrency, defaultPlatform)
+			return copyImage(ctx, src, remoteRepo, srcName, dstName, ociConcurrency, defaultPlatform, cfg.SkipExisting)
 		}
 		pushed := []string{}
 		// Delete the images that were already successfully pushed so that they aren't attempted on the next retry
@@ -172,15 +183,20 @@ func Push(ctx context.Context, imageList []transform.Image, sourceDirectory stri
 		}()
 		for img := range toPush {
 			l.Info("pushing image", "name", img)
-			// If this is not a no checksum image push it for use with the Zarf agent
-			if !cfg.NoChecksum {
-				offlineNameCRC, err := transform.ImageTransformHost(registryRef.String(), img)
-				if err != nil {
-					return err
-				}
-
+			dstNames, err := pushDestinations(registryRef.String(), img, cfg.NoChecksum, slices.Contains(cfg.PinnedImages, img))
+			if err != nil {
+				return err
+			}
+			// The image only counts as skipped if it was already present under every target reference
+			allSkipped := true
+			for _, dstName := range dstNames {
+				var skipped bool
 				err = retry.Do(
-					func() error { return pushImage(img, offlineNameCRC) },
+					func() error {
+						var err error
+						skipped, err = pushImage(img, dstName)
+						return err
+					},
 					retry.OnRetry(func(_ uint, err error) {
 						ociConcurrency = 1
 						l.Debug("retrying image push", "error", err, "concurrency", ociConcurrency)
@@ -192,30 +208,13 @@ func Push(ctx context.Context, imageList []transform.Image, sourceDirectory stri
 				if err != nil {
 					return err
 				}
-			}
-
-			// To allow for other non-zarf workloads to easily see the images upload a non-checksum version
-			// (this may result in collisions but this is acceptable for this use case)
-			offlineName, err := transform.ImageTransformHostWithoutChecksum(registryRef.String(), img)
-			if err != nil {
-				return err
-			}
-
-			err = retry.Do(
-				func() error { return pushImage(img, offlineName) },
-				retry.OnRetry(func(_ uint, err error) {
-					ociConcurrency = 1
-					l.Debug("retrying image push", "error", err, "concurrency", ociConcurrency)
-				}),
-				retry.Context(ctx),
-				retry.Attempts(2),
-				retry.Delay(500*time.Millisecond),
-			)
-			if err != nil {
-				return err
+				allSkipped = allSkipped && skipped
 			}
 
 			pushed = append(pushed, img)
+			if allSkipped {
+				skippedCount++
+			}
 		}
 		return nil
 	}, retry.Context(ctx), retry.Attempts(uint(cfg.Retries)), retry.Delay(500*time.Millisecond), retry.OnRetry(func(attempt uint, _ error) {
@@ -227,10 +226,42 @@ func Push(ctx context.Context, imageList []transform.Image, sourceDirectory stri
 	if err != nil {
 		return err
 	}
-	l.Info("done pushing images", "count", len(imageList), "duration", time.Since(start).Round(time.Millisecond*100))
+	l.Info("done pushing images", "count", len(imageList), "pushed", len(imageList)-skippedCount, "skipped", skippedCount, "duration", time.Since(start).Round(time.Millisecond*100))
 	return nil
 }
 
+// pushDestinations returns the references in the registry that an image is pushed to. When pushTag is true an image
+// pinned to a digest is also pushed under its tag.
+func pushDestinations(registryHost, img string, noChecksum bool, pushTag bool) ([]string, error) {
+	refs := []string{img}
+	refInfo, err := transform.ParseImageRef(img)
+	if err != nil {
+		return nil, err
+	}
+	if pushTag && refInfo.Tag != "" && refInfo.Digest != "" {
+		refs = append(refs, fmt.Sprintf("%s:%s", refInfo.Name, refInfo.Tag))
+	}
+	dstNames := []string{}
+	for _, ref := range refs {
+		// If this is not a no checksum image push it for use with the Zarf agent
+		if !noChecksum {
+			offlineNameCRC, err := transform.ImageTransformHost(registryHost, ref)
+			if err != nil {
+				return nil, err
+			}
+			dstNames = append(dstNames, offlineNameCRC)
+		}
+		// To allow for other non-zarf workloads to easily see the images upload a non-checksum version
+		// (this may result in collisions but this is acceptable for this use case)
+		offlineName, err := transform.ImageTransformHostWithoutChecksum(registryHost, ref)
+		if err != nil {
+			return nil, err
+		}
+		dstNames = append(dstNames, offlineName)
+	}
+	return dstNames, nil
+}
+
 func addRefNameAnnotationToImages(ociLayoutDirectory string) error {
 	idx, err := getIndexFromOCILayout(ociLayoutDirectory)
 	if err != nil {
@@ -253,30 +284,42 @@ func addRefNameAnnotationToImages(ociLayoutDirectory string) error {
 	return nil
 }
 
-func copyImage(ctx context.Context, src *oci.Store, remote oras.Target, srcName string, dstName string, concurrency int, defaultPlatform *ocispec.Platform) error {
+// copyImage copies an image from the OCI layout to the remote. When skipExisting is true and the remote already has the
+// image under dstName the copy is skipped and true is returned.
+func copyImage(ctx context.Context, src *oci.Store, remote oras.Target, srcName string, dstName string, concurrency int, defaultPlatform *ocispec.Platform, skipExisting bool) (bool, error) {
 	// Assume no platform to start as it can be nil in non container image situations
 	fetchOpts := oras.DefaultFetchBytesOptions
 	desc, b, err := oras.FetchBytes(ctx, src, srcName, fetchOpts)
 	if err != nil {
-		return fmt.Errorf("failed to resolve image: %s: %w", srcName, err)
+		return false, fmt.Errorf("failed to resolve image: %s: %w", srcName, err)
 	}
+	root := desc
 
 	// If an index is pulled we should try pulling with the default platform
 	if isIndex(desc.MediaType) {
 		fetchOpts.TargetPlatform = defaultPlatform
 		desc, b, err = oras.FetchBytes(ctx, src, srcName, fetchOpts)
 		if err != nil {
-			return fmt.Errorf("failed to resolve image %s with architecture %s: %w", srcName, defaultPlatform.Architecture, err)
+			return false, fmt.Errorf("failed to resolve image %s with architecture %s: %w", srcName, defaultPlatform.Architecture, err)
 		}
 	}
 
 	if !isManifest(desc.MediaType) {
-		return fmt.Errorf("expected OCI manifest got %s", desc.MediaType)
+		return false, fmt.Errorf("expected OCI manifest got %s", desc.MediaType)
+	}
+
+	if skipExisting {
+		// The registry can hold the image as it is in the package or only its manifest for the platform of the package.
+		// Any error resolving the remote reference, such as it not existing yet, falls through to pushing the image.
+		if existing, err := remote.Resolve(ctx, dstName); err == nil && (existing.Digest == root.Digest || existing.Digest == desc.Digest) {
+			logger.From(ctx).Info("image already exists in the registry, skipping push", "name", srcName, "digest", existing.Digest)
+			return true, nil
+		}
 	}
 
 	var manifest ocispec.Manifest
 	if err := json.Unmarshal(b, &manifest); err != nil {
-		return err
+		return false, err
 	}
 	size := getSizeOfImage(desc, manifest)
 
@@ -288,9 +331,9 @@ func copyImage(ctx context.Context, src *oci.Store, remote oras.Target, srcName
 	defer trackedRemote.StopReporting()
 	_, err = oras.Copy(ctx, src, srcName, trackedRemote, dstName, copyOpts)
 	if err != nil {
-		return fmt.Errorf("failed to push image %s: %w", srcName, err)
+		return false, fmt.Errorf("failed to push image %s: %w", srcName, err)
 	}
-	return nil
+	return false, nil
 }
 
 // parse registry reference returns a registry.Reference with only the host if the registry URL only contains a host
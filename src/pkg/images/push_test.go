@@ -6,14 +6,19 @@ package images
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"testing"
 
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/stretchr/testify/require"
 	"github.com/zarf-dev/zarf/src/pkg/state"
 	"github.com/zarf-dev/zarf/src/pkg/transform"
 	"github.com/zarf-dev/zarf/src/test/testutil"
 	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content/memory"
+	"oras.land/oras-go/v2/content/oci"
 	"oras.land/oras-go/v2/registry"
 	orasRemote "oras.land/oras-go/v2/registry/remote"
 )
@@ -122,3 +127,163 @@ func verifyImageExists(ctx context.Context, t *testing.T, ref string) {
 	_, err = oras.Resolve(ctx, repo, ref, oras.DefaultResolveOptions)
 	require.NoError(t, err)
 }
+
+// recordingTarget records the references that are tagged in the wrapped target.
+type recordingTarget struct {
+	oras.Target
+	tagged []string
+}
+
+func (r *recordingTarget) Tag(ctx context.Context, desc ocispec.Descriptor, reference string) error {
+	r.tagged = append(r.tagged, reference)
+	return r.Target.Tag(ctx, desc, reference)
+}
+
+func TestCopyImageSkipExisting(t *testing.T) {
+	// Not parallel as TestPush rewrites the index of the OCI layout
+	ctx := testutil.TestContext(t)
+
+	src, err := oci.NewWithContext(ctx, "testdata/oras-oci-layout/images")
+	require.NoError(t, err)
+	platform := &ocispec.Platform{Architecture: "amd64", OS: "linux"}
+	present := "docker.io/library/local-test:1.0.0"
+	missing := "ghcr.io/zarf-dev/images/hello-world:latest"
+
+	remote := &recordingTarget{Target: memory.New()}
+	// The registry already has one of the images
+	skipped, err := copyImage(ctx, src, remote, present, "registry.example.com/library/local-test:1.0.0", 1, platform, true)
+	require.NoError(t, err)
+	require.False(t, skipped)
+	remote.tagged = nil
+
+	skipped, err = copyImage(ctx, src, remote, present, "registry.example.com/library/local-test:1.0.0", 1, platform, true)
+	require.NoError(t, err)
+	require.True(t, skipped)
+	skipped, err = copyImage(ctx, src, remote, missing, "registry.example.com/zarf-dev/images/hello-world:latest", 1, platform, true)
+	require.NoError(t, err)
+	require.False(t, skipped)
+	require.Equal(t, []string{"registry.example.com/zarf-dev/images/hello-world:latest"}, remote.tagged)
+
+	// A different image under an existing reference is pushed
+	remote.tagged = nil
+	skipped, err = copyImage(ctx, src, remote, missing, "registry.example.com/library/local-test:1.0.0", 1, platform, true)
+	require.NoError(t, err)
+	require.False(t, skipped)
+	require.Equal(t, []string{"registry.example.com/library/local-test:1.0.0"}, remote.tagged)
+
+	// Without skipping existing images everything is pushed
+	remote.tagged = nil
+	skipped, err = copyImage(ctx, src, remote, missing, "registry.example.com/zarf-dev/images/hello-world:latest", 1, platform, false)
+	require.NoError(t, err)
+	require.False(t, skipped)
+	require.Equal(t, []string{"registry.example.com/zarf-dev/images/hello-world:latest"}, remote.tagged)
+}
+
+func TestCopyImageSkipExistingPlatformManifest(t *testing.T) {
+	// Not parallel as TestPush rewrites the index of the OCI layout
+	ctx := testutil.TestContext(t)
+
+	images, err := oci.NewWithContext(ctx, "testdata/oras-oci-layout/images")
+	require.NoError(t, err)
+	src, err := oci.NewWithContext(ctx, t.TempDir())
+	require.NoError(t, err)
+	manifestRef := "ghcr.io/zarf-dev/images/hello-world:latest"
+	manifestDesc, err := oras.Copy(ctx, images, manifestRef, src, manifestRef, oras.DefaultCopyOptions)
+	require.NoError(t, err)
+	platform := &ocispec.Platform{Architecture: "amd64", OS: "linux"}
+	manifestDesc.Platform = platform
+	index, err := json.Marshal(ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageIndex,
+		Manifests: []ocispec.Descriptor{manifestDesc},
+	})
+	require.NoError(t, err)
+	indexRef := "ghcr.io/zarf-dev/images/multi-arch:1.0.0"
+	_, err = oras.TagBytes(ctx, src, ocispec.MediaTypeImageIndex, index, indexRef)
+	require.NoError(t, err)
+	dstName := "registry.example.com/zarf-dev/images/multi-arch:1.0.0"
+
+	// The registry only has the manifest for the platform of the package
+	remote := memory.New()
+	_, err = oras.Copy(ctx, src, manifestRef, remote, dstName, oras.DefaultCopyOptions)
+	require.NoError(t, err)
+	skipped, err := copyImage(ctx, src, remote, indexRef, dstName, 1, platform, true)
+	require.NoError(t, err)
+	require.True(t, skipped)
+
+	// The registry has the index as it is in the package
+	remote = memory.New()
+	skipped, err = copyImage(ctx, src, remote, indexRef, dstName, 1, platform, true)
+	require.NoError(t, err)
+	require.False(t, skipped)
+	skipped, err = copyImage(ctx, src, remote, indexRef, dstName, 1, platform, true)
+	require.NoError(t, err)
+	require.True(t, skipped)
+}
+
+func TestPushDestinations(t *testing.T) {
+	t.Parallel()
+
+	digest := "sha256:03b62250a3cb1abd125271d393fc08bf0cc713391eda6b57c02d1ef85efcc25c"
+	tests := []struct {
+		name       string
+		img        string
+		noChecksum bool
+		pushTag    bool
+		expected   []string
+	}{
+		{
+			name: "tag",
+			img:  "ghcr.io/stefanprodan/podinfo:6.4.0",
+			expected: []string{
+				"127.0.0.1:31999/stefanprodan/podinfo:6.4.0-zarf-2985051089",
+				"127.0.0.1:31999/stefanprodan/podinfo:6.4.0",
+			},
+		},
+		{
+			name: "digest",
+			img:  "ghcr.io/stefanprodan/podinfo@" + digest,
+			expected: []string{
+				"127.0.0.1:31999/stefanprodan/podinfo@" + digest,
+				"127.0.0.1:31999/stefanprodan/podinfo@" + digest,
+			},
+		},
+		{
+			name: "tag and digest",
+			img:  "ghcr.io/stefanprodan/podinfo:6.4.0@" + digest,
+			expected: []string{
+				"127.0.0.1:31999/stefanprodan/podinfo@" + digest,
+				"127.0.0.1:31999/stefanprodan/podinfo@" + digest,
+			},
+		},
+		{
+			name:    "pinned tag",
+			img:     "ghcr.io/stefanprodan/podinfo:6.4.0@" + digest,
+			pushTag: true,
+			expected: []string{
+				"127.0.0.1:31999/stefanprodan/podinfo@" + digest,
+				"127.0.0.1:31999/stefanprodan/podinfo@" + digest,
+				"127.0.0.1:31999/stefanprodan/podinfo:6.4.0-zarf-2985051089",
+				"127.0.0.1:31999/stefanprodan/podinfo:6.4.0",
+			},
+		},
+		{
+			name:       "pinned tag without checksum",
+			img:        "ghcr.io/stefanprodan/podinfo:6.4.0@" + digest,
+			noChecksum: true,
+			pushTag:    true,
+			expected: []string{
+				"127.0.0.1:31999/stefanprodan/podinfo@" + digest,
+				"127.0.0.1:31999/stefanprodan/podinfo:6.4.0",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			dstNames, err := pushDestinations("127.0.0.1:31999", tt.img, tt.noChecksum, tt.pushTag)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, dstNames)
+		})
+	}
+}
@@ -6,6 +6,7 @@ package interactive
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/pterm/pterm"
@@ -37,6 +38,46 @@ func SelectOptionalComponent(component v1alpha1.ZarfComponent) (bool, error) {
 	return confirm, nil
 }
 
+// SelectComponents prompts to select the optional components to deploy from a single list, with the default components
+// checked. Required components are always deployed so they are listed in the message instead of being offered.
+func SelectComponents(components []v1alpha1.ZarfComponent) ([]string, error) {
+	message.HorizontalRule()
+
+	var options, defaults, required []string
+	descriptions := map[string]string{}
+	for _, component := range components {
+		if component.IsRequired() {
+			required = append(required, component.Name)
+			continue
+		}
+		options = append(options, component.Name)
+		descriptions[component.Name] = component.Description
+		if component.Default {
+			defaults = append(defaults, component.Name)
+		}
+	}
+
+	msg := "Select the components to deploy:"
+	if len(required) > 0 {
+		msg = fmt.Sprintf("Select the components to deploy (%s are required and always deployed):", strings.Join(required, ", "))
+	}
+	prompt := &survey.MultiSelect{
+		Message: msg,
+		Options: options,
+		Default: defaults,
+		Description: func(value string, _ int) string {
+			return descriptions[value]
+		},
+	}
+
+	chosen := []string{}
+	err := survey.AskOne(prompt, &chosen)
+	if err != nil {
+		return nil, err
+	}
+	return chosen, nil
+}
+
 // SelectChoiceGroup prompts to select component groups
 func SelectChoiceGroup(componentGroup []v1alpha1.ZarfComponent) (v1alpha1.ZarfComponent, error) {
 	message.HorizontalRule()
@@ -7,10 +7,12 @@ package interactive
 import (
 	"context"
 	"fmt"
+	"os"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 	"github.com/zarf-dev/zarf/src/pkg/logger"
+	"golang.org/x/term"
 )
 
 // PromptVariable prompts the user for a value for a variable
@@ -31,3 +33,27 @@ func PromptVariable(ctx context.Context, variable v1alpha1.InteractiveVariable)
 	}
 	return value, nil
 }
+
+// PromptConfirm prompts the user to confirm the given message
+func PromptConfirm(_ context.Context, message string) (bool, error) {
+	prompt := &survey.Confirm{
+		Message: message,
+	}
+
+	var confirm bool
+	err := survey.AskOne(prompt, &confirm)
+	if err != nil {
+		return false, err
+	}
+	return confirm, nil
+}
+
+// IsTerminal reports whether stdin is a terminal that can answer prompts
+func IsTerminal() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// IsOutputTerminal reports whether stdout is a terminal that can render prompts
+func IsOutputTerminal() bool {
+	return term.IsTerminal(int(os.Stdout.Fd()))
+}
@@ -111,6 +111,41 @@ func TestZarfSchema(t *testing.T) {
 				"apiVersion: apiVersion must be one of the following: \"zarf.dev/v1alpha1\"",
 			},
 		},
+		{
+			name: "wait on one or more cluster conditions",
+			pkg: v1alpha1.ZarfPackage{
+				APIVersion: v1alpha1.APIVersion,
+				Kind:       v1alpha1.ZarfPackageConfig,
+				Metadata: v1alpha1.ZarfMetadata{
+					Name: "wait-conditions",
+				},
+				Components: []v1alpha1.ZarfComponent{
+					{
+						Name: "database",
+						Actions: v1alpha1.ZarfComponentActions{
+							OnDeploy: v1alpha1.ZarfComponentActionSet{
+								After: []v1alpha1.ZarfComponentAction{
+									{
+										Wait: &v1alpha1.ZarfComponentActionWait{
+											Cluster: &v1alpha1.ZarfComponentActionWaitCluster{Kind: "StatefulSet", Name: "db", Condition: "Ready"},
+										},
+									},
+									{
+										Wait: &v1alpha1.ZarfComponentActionWait{
+											Clusters: []v1alpha1.ZarfComponentActionWaitCluster{
+												{Kind: "StatefulSet", Name: "db", Condition: "Ready"},
+												{Kind: "Job", Name: "migrate"},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedSchemaStrings: nil,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+)
+
+// EventFunc receives the records logged through an EventHandler.
+type EventFunc func(ctx context.Context, r slog.Record)
+
+// EventHandler is a slog.Handler that forwards records with their level, message and attrs to a callback instead of
+// formatting them. It lets programs that use Zarf as a library follow the progress of an operation.
+type EventHandler struct {
+	level  Level
+	fn     EventFunc
+	attrs  []slog.Attr
+	groups []string
+}
+
+// NewEventHandler returns a handler that calls fn with every record at or above level.
+func NewEventHandler(level Level, fn EventFunc) *EventHandler {
+	return &EventHandler{level: level, fn: fn}
+}
+
+// NewChannelHandler returns a handler that sends every record at or above level to ch. Sending blocks until the record
+// is received or the context of the record is done, so ch must be drained while Zarf is running.
+func NewChannelHandler(level Level, ch chan<- slog.Record) *EventHandler {
+	return NewEventHandler(level, func(ctx context.Context, r slog.Record) {
+		select {
+		case ch <- r:
+		case <-ctx.Done():
+		}
+	})
+}
+
+// Enabled reports whether the record level is at or above the level of the handler.
+func (h *EventHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= slog.Level(h.level)
+}
+
+// Handle passes a copy of the record with the attrs and groups of the handler to the callback.
+func (h *EventHandler) Handle(ctx context.Context, r slog.Record) error {
+	event := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	event.AddAttrs(h.attrs...)
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	event.AddAttrs(groupAttrs(h.groups, attrs)...)
+	h.fn(ctx, event)
+	return nil
+}
+
+// WithAttrs returns a handler that adds attrs to every record.
+func (h *EventHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	h2 := *h
+	h2.attrs = append(slices.Clip(h.attrs), groupAttrs(h.groups, attrs)...)
+	return &h2
+}
+
+// WithGroup returns a handler that nests the attrs added after it in the group.
+func (h *EventHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.groups = append(slices.Clip(h.groups), name)
+	return &h2
+}
+
+// groupAttrs nests attrs in groups, the first group being the outermost.
+func groupAttrs(groups []string, attrs []slog.Attr) []slog.Attr {
+	if len(attrs) == 0 {
+		return nil
+	}
+	for i := len(groups) - 1; i >= 0; i-- {
+		args := make([]any, 0, len(attrs))
+		for _, a := range attrs {
+			args = append(args, a)
+		}
+		attrs = []slog.Attr{slog.Group(groups[i], args...)}
+	}
+	return attrs
+}
+
+// WithEventHandler returns a context whose logger sends every record to h as well as to the logger already in ctx.
+func WithEventHandler(ctx context.Context, h *EventHandler) context.Context {
+	return WithContext(ctx, slog.New(NewMultiHandler(From(ctx).Handler(), h)))
+}
@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package logger implements a log/slog based logger in Zarf.
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// eventString formats a record as its level, message and attrs.
+func eventString(r slog.Record) string {
+	parts := []string{r.Level.String(), r.Message}
+	r.Attrs(func(a slog.Attr) bool {
+		parts = append(parts, a.String())
+		return true
+	})
+	return strings.Join(parts, " ")
+}
+
+func TestEventHandler(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	events := []string{}
+	h := NewEventHandler(Info, func(_ context.Context, r slog.Record) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, eventString(r))
+	})
+	l := slog.New(h)
+
+	l.Debug("filtered")
+	l.Info("deploying package", "name", "podinfo")
+	l.With("component", "app").WithGroup("chart").With("name", "podinfo").Warn("retrying", "attempt", 2)
+	l.WithGroup("empty").Error("failed")
+
+	require.Equal(t, []string{
+		"INFO deploying package name=podinfo",
+		"WARN retrying component=app chart=[name=podinfo] chart=[attempt=2]",
+		"ERROR failed",
+	}, events)
+}
+
+func TestWithEventHandler(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	ctx := WithContext(context.Background(), slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelWarn})))
+	events := []string{}
+	ctx = WithEventHandler(ctx, NewEventHandler(Debug, func(_ context.Context, r slog.Record) {
+		events = append(events, eventString(r))
+	}))
+
+	l := From(ctx).With("component", "app")
+	l.Debug("pulling image")
+	l.Warn("image not signed")
+
+	// The existing logger keeps its level while the event handler receives every record at or above its own
+	require.Equal(t, []string{"DEBUG pulling image component=app", "WARN image not signed component=app"}, events)
+	require.Contains(t, buf.String(), `msg="image not signed" component=app`)
+	require.NotContains(t, buf.String(), "pulling image")
+}
+
+func TestChannelHandlerContextDone(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	// Nothing drains the channel so the record is dropped once the context is done instead of blocking
+	l := slog.New(NewChannelHandler(Info, make(chan slog.Record)))
+	l.InfoContext(ctx, "dropped")
+}
+
+// fakeDeploy logs the progress of deploying components like the packager does.
+func fakeDeploy(ctx context.Context, components []string) {
+	l := From(ctx)
+	l.Info("starting deploy", "components", len(components))
+	for _, name := range components {
+		cl := l.With("component", name)
+		cl.Info("deploying component")
+		cl.Debug("pulling images")
+		cl.Info("component deployed")
+	}
+}
+
+func ExampleNewChannelHandler() {
+	events := make(chan slog.Record)
+	ctx := WithEventHandler(context.Background(), NewChannelHandler(Info, events))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for r := range events {
+			fmt.Println(eventString(r))
+		}
+	}()
+
+	fakeDeploy(ctx, []string{"zarf-registry", "zarf-agent"})
+	close(events)
+	<-done
+	// Output:
+	// INFO starting deploy components=2
+	// INFO deploying component component=zarf-registry
+	// INFO component deployed component=zarf-registry
+	// INFO deploying component component=zarf-agent
+	// INFO component deployed component=zarf-agent
+}
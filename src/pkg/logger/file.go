@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// DefaultFileMaxSizeMB is the size in megabytes a log file grows to before it is rotated.
+const DefaultFileMaxSizeMB = 10
+
+// RotatingFile is an io.WriteCloser that appends to a file and rotates it once it grows past a maximum size. On
+// rotation the file is renamed with a ".1" suffix, replacing the previously rotated file, and a new file is started.
+type RotatingFile struct {
+	mu      sync.Mutex
+	path    string
+	maxSize int64
+	file    *os.File
+	size    int64
+}
+
+// NewRotatingFile opens the file at path for appending, creating it and its parent directories when needed. The file
+// is rotated once writing to it would exceed maxSize bytes, a maxSize of 0 disables rotation.
+func NewRotatingFile(path string, maxSize int64) (*RotatingFile, error) {
+	if maxSize < 0 {
+		return nil, fmt.Errorf("log file max size must not be negative, got %d", maxSize)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("unable to create the log file directory: %w", err)
+	}
+	f := &RotatingFile{path: path, maxSize: maxSize}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("unable to open the log file: %w", err)
+	}
+	fi, err := file.Stat()
+	if err != nil {
+		return errors.Join(err, file.Close())
+	}
+	f.file = file
+	f.size = fi.Size()
+	return nil
+}
+
+// Write appends p to the file, rotating it first when p would grow the file past its maximum size. A single write
+// larger than the maximum size is written whole to a new file.
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+	if f.maxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+func (f *RotatingFile) rotate() error {
+	err := f.file.Close()
+	f.file = nil
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(f.path, f.path+".1"); err != nil {
+		return fmt.Errorf("unable to rotate the log file: %w", err)
+	}
+	return f.open()
+}
+
+// Close closes the file, later writes fail with os.ErrClosed.
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+// NewFileHandler returns a handler that writes records at or above level as JSON to a RotatingFile at path. The file
+// never contains color codes, whichever format is used on screen. Close the returned file once logging is done.
+func NewFileHandler(path string, maxSizeMB int, level Level) (slog.Handler, *RotatingFile, error) {
+	if maxSizeMB < 0 {
+		return nil, nil, fmt.Errorf("log file max size must not be negative, got %d", maxSizeMB)
+	}
+	f, err := NewRotatingFile(path, int64(maxSizeMB)*1024*1024)
+	if err != nil {
+		return nil, nil, err
+	}
+	return slog.NewJSONHandler(f, &slog.HandlerOptions{Level: slog.Level(level)}), f, nil
+}
@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "logs", "zarf.log")
+	f, err := NewRotatingFile(path, 10)
+	require.NoError(t, err)
+
+	_, err = f.Write([]byte("aaaaa\n"))
+	require.NoError(t, err)
+	_, err = f.Write([]byte("bbb\n"))
+	require.NoError(t, err)
+	require.NoFileExists(t, path+".1")
+
+	// The write would grow the file past its max size so the file is rotated first
+	_, err = f.Write([]byte("cc\n"))
+	require.NoError(t, err)
+	b, err := os.ReadFile(path + ".1")
+	require.NoError(t, err)
+	require.Equal(t, "aaaaa\nbbb\n", string(b))
+
+	// A write larger than the max size is written whole to a new file
+	_, err = f.Write([]byte("dddddddddddd\n"))
+	require.NoError(t, err)
+	b, err = os.ReadFile(path + ".1")
+	require.NoError(t, err)
+	require.Equal(t, "cc\n", string(b))
+	b, err = os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "dddddddddddd\n", string(b))
+
+	require.NoError(t, f.Close())
+	_, err = f.Write([]byte("closed\n"))
+	require.ErrorIs(t, err, os.ErrClosed)
+
+	// Reopening appends to the existing file and accounts for its size
+	f, err = NewRotatingFile(path, 20)
+	require.NoError(t, err)
+	_, err = f.Write([]byte("eeeeeeeeeeee\n"))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	b, err = os.ReadFile(path + ".1")
+	require.NoError(t, err)
+	require.Equal(t, "dddddddddddd\n", string(b))
+
+	_, err = NewRotatingFile(path, -1)
+	require.EqualError(t, err, "log file max size must not be negative, got -1")
+}
+
+func TestLogFile(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	path := filepath.Join(t.TempDir(), "zarf.log")
+	l, err := New(Config{
+		Level:       Info,
+		Format:      FormatConsole,
+		Destination: &buf,
+		Color:       true,
+	})
+	require.NoError(t, err)
+	fileHandler, f, err := NewFileHandler(path, 0, Info)
+	require.NoError(t, err)
+	l = slog.New(NewMultiHandler(l.Handler(), fileHandler))
+
+	l.With("component", "podinfo").WithGroup("chart").Info("installing", "attempt", 1)
+	l.Debug("dropped")
+	require.NoError(t, f.Close())
+
+	// Records are written to the destination and the file
+	require.Contains(t, buf.String(), "installing")
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NotContains(t, string(b), "\x1b[")
+	records := strings.Split(strings.TrimSpace(string(b)), "\n")
+	require.Len(t, records, 1)
+	var record map[string]any
+	require.NoError(t, json.Unmarshal([]byte(records[0]), &record))
+	require.Equal(t, "INFO", record["level"])
+	require.Equal(t, "installing", record["msg"])
+	require.Equal(t, "podinfo", record["component"])
+	require.Equal(t, map[string]any{"attempt": float64(1)}, record["chart"])
+
+	_, _, err = NewFileHandler(path, -1, Info)
+	require.EqualError(t, err, "log file max size must not be negative, got -1")
+}
@@ -6,6 +6,8 @@ package logger
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"log/slog"
@@ -16,6 +18,7 @@ import (
 	"github.com/phsym/console-slog"
 
 	"github.com/golang-cz/devslog"
+	"golang.org/x/term"
 )
 
 var defaultLogger atomic.Pointer[slog.Logger]
@@ -134,9 +137,27 @@ type Config struct {
 	Color
 }
 
-// Color is a type that represents whether or not to use color in the logger.
+// Color is a type that represents whether or not to use color in the logger. It is used as given, callers decide whether
+// the destination can render color, see ColorSupported.
 type Color bool
 
+// NoColorEnv disables color in the logger when set to any non-empty value. See https://no-color.org.
+const NoColorEnv = "NO_COLOR"
+
+// ColorSupported reports whether color codes can be written to the destination. Color is not supported when NO_COLOR
+// is set or when the destination is not a terminal, such as when logs are piped or redirected to a file. Use it to pick
+// the Color of a Config writing to a file.
+func ColorSupported(d Destination) bool {
+	if os.Getenv(NoColorEnv) != "" {
+		return false
+	}
+	f, ok := d.(*os.File)
+	if !ok {
+		return false
+	}
+	return term.IsTerminal(int(f.Fd()))
+}
+
 // LogValue of config
 func (c Config) LogValue() slog.Value {
 	return slog.GroupValue(
@@ -153,7 +174,7 @@ func ConfigDefault() Config {
 		Level:       Info,
 		Format:      FormatConsole,
 		Destination: DestinationDefault, // Stderr
-		Color:       true,
+		Color:       Color(ColorSupported(DestinationDefault)),
 	}
 }
 
@@ -200,6 +221,18 @@ func New(cfg Config) (*slog.Logger, error) {
 	return slog.New(handler), nil
 }
 
+// RunIDKey is the attribute key that carries the run ID on every record logged by a Zarf command.
+const RunIDKey = "run_id"
+
+// NewRunID generates a random ID used to correlate the log records of a single Zarf command.
+func NewRunID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("unable to generate run ID: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
 // ctxKey provides a location to store a logger in a context.
 type ctxKey struct{}
 
@@ -5,8 +5,12 @@
 package logger
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"log/slog"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -225,3 +229,143 @@ func TestContext(t *testing.T) {
 		require.NotNil(t, res)
 	})
 }
+
+func TestColorOption(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range []Format{FormatConsole, FormatDev} {
+		t.Run(string(format), func(t *testing.T) {
+			t.Parallel()
+
+			// Color is used as configured whatever the destination is
+			for _, color := range []Color{true, false} {
+				var buf bytes.Buffer
+				l, err := New(Config{
+					Level:       Info,
+					Format:      format,
+					Destination: &buf,
+					Color:       color,
+				})
+				require.NoError(t, err)
+
+				l.Info("deploying package", "name", "podinfo")
+				l.Warn("something happened", "error", "timeout")
+				require.NotEmpty(t, buf.String())
+				require.Equal(t, bool(color), strings.Contains(buf.String(), "\x1b["))
+			}
+		})
+	}
+}
+
+func TestColorSupported(t *testing.T) {
+	t.Run("non-file destinations do not support color", func(t *testing.T) {
+		require.False(t, ColorSupported(&bytes.Buffer{}))
+		require.False(t, ColorSupported(DestinationNone))
+	})
+
+	t.Run("NO_COLOR disables color", func(t *testing.T) {
+		t.Setenv(NoColorEnv, "1")
+		require.False(t, ColorSupported(os.Stderr))
+	})
+}
+
+func TestRunID(t *testing.T) {
+	t.Parallel()
+
+	runID, err := NewRunID()
+	require.NoError(t, err)
+	require.Len(t, runID, 16)
+	otherRunID, err := NewRunID()
+	require.NoError(t, err)
+	require.NotEqual(t, runID, otherRunID)
+
+	var buf bytes.Buffer
+	l, err := New(Config{
+		Level:       Info,
+		Format:      FormatJSON,
+		Destination: &buf,
+	})
+	require.NoError(t, err)
+	ctx := WithContext(context.Background(), l.With(RunIDKey, runID))
+
+	From(ctx).Info("starting deploy", "package", "podinfo")
+	From(ctx).With("component", "podinfo").Warn("retrying")
+
+	records := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, records, 2)
+	for _, record := range records {
+		var attrs map[string]any
+		require.NoError(t, json.Unmarshal([]byte(record), &attrs))
+		require.Equal(t, runID, attrs[RunIDKey])
+	}
+}
+
+func TestJSONFormat(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	l, err := New(Config{
+		Level:       Debug,
+		Format:      FormatJSON,
+		Destination: &buf,
+	})
+	require.NoError(t, err)
+
+	l.With("package", "podinfo").WithGroup("component").With("name", "podinfo").Error("deploy failed", "attempt", 3)
+	l.Warn("retrying")
+
+	records := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, records, 2)
+	var record map[string]any
+	require.NoError(t, json.Unmarshal([]byte(records[0]), &record))
+	// Errors keep their level instead of being reported as warnings
+	require.Equal(t, "ERROR", record["level"])
+	require.Equal(t, "deploy failed", record["msg"])
+	require.Equal(t, "podinfo", record["package"])
+	require.Equal(t, map[string]any{"name": "podinfo", "attempt": float64(3)}, record["component"])
+	require.NoError(t, json.Unmarshal([]byte(records[1]), &record))
+	require.Equal(t, "WARN", record["level"])
+}
+
+func TestConsoleFormatAttrs(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	l, err := New(Config{
+		Level:       Debug,
+		Format:      FormatConsole,
+		Destination: &buf,
+	})
+	require.NoError(t, err)
+
+	l.With("component", "podinfo").WithGroup("chart").WithGroup("release").With("namespace", "podinfo").Info("installing", "attempt", 1)
+
+	// Accumulated attrs follow the message and nested groups prefix their keys joined by dots
+	require.True(t, strings.HasSuffix(buf.String(), "INF installing component=podinfo chart.release.namespace=podinfo chart.release.attempt=1\n"), buf.String())
+}
+
+func TestLevelThreshold(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range []Format{FormatConsole, FormatJSON, FormatDev} {
+		t.Run(string(format), func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			// A zero value level is Info
+			l, err := New(Config{Format: format, Destination: &buf})
+			require.NoError(t, err)
+			require.False(t, l.Enabled(context.Background(), slog.LevelDebug))
+			require.True(t, l.Enabled(context.Background(), slog.LevelInfo))
+			l.Debug("dropped")
+			require.Empty(t, buf.String())
+
+			l, err = New(Config{Level: Warn, Format: format, Destination: &buf})
+			require.NoError(t, err)
+			l.Info("dropped")
+			require.Empty(t, buf.String())
+			l.Warn("kept")
+			require.Contains(t, buf.String(), "kept")
+		})
+	}
+}
@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package logger
+
+import (
+	"context"
+	"log/slog"
+)
+
+// MultiHandler is a slog.Handler that fans every record out to several handlers, such as rendering logs on screen while
+// emitting them as JSON to a file or a collector.
+type MultiHandler struct {
+	handlers []slog.Handler
+}
+
+// NewMultiHandler returns a handler that passes every record to each of handlers.
+func NewMultiHandler(handlers ...slog.Handler) *MultiHandler {
+	return &MultiHandler{handlers: handlers}
+}
+
+// Enabled reports whether any of the handlers handles records at level.
+func (h *MultiHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, handler := range h.handlers {
+		if handler.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle passes a copy of the record to each handler that is enabled for its level. Every handler receives the record
+// even when an earlier one fails, the first error is returned.
+func (h *MultiHandler) Handle(ctx context.Context, r slog.Record) error {
+	var err error
+	for _, handler := range h.handlers {
+		if !handler.Enabled(ctx, r.Level) {
+			continue
+		}
+		if hErr := handler.Handle(ctx, r.Clone()); hErr != nil && err == nil {
+			err = hErr
+		}
+	}
+	return err
+}
+
+// WithAttrs returns a handler that adds attrs to the records of every handler.
+func (h *MultiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make([]slog.Handler, 0, len(h.handlers))
+	for _, handler := range h.handlers {
+		handlers = append(handlers, handler.WithAttrs(attrs))
+	}
+	return &MultiHandler{handlers: handlers}
+}
+
+// WithGroup returns a handler that nests the attrs of every handler under name.
+func (h *MultiHandler) WithGroup(name string) slog.Handler {
+	handlers := make([]slog.Handler, 0, len(h.handlers))
+	for _, handler := range h.handlers {
+		handlers = append(handlers, handler.WithGroup(name))
+	}
+	return &MultiHandler{handlers: handlers}
+}
@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// errHandler fails every record it handles.
+type errHandler struct {
+	slog.Handler
+	err error
+}
+
+func (h errHandler) Handle(context.Context, slog.Record) error {
+	return h.err
+}
+
+func TestMultiHandlerAttrs(t *testing.T) {
+	t.Parallel()
+
+	var a, b bytes.Buffer
+	l := slog.New(NewMultiHandler(
+		slog.NewJSONHandler(&a, &slog.HandlerOptions{Level: slog.LevelDebug}),
+		slog.NewJSONHandler(&b, &slog.HandlerOptions{Level: slog.LevelDebug}),
+	))
+
+	l.With("package", "podinfo").WithGroup("component").With("name", "podinfo").Info("deploying", "attempt", 1)
+
+	for _, buf := range []*bytes.Buffer{&a, &b} {
+		records := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, records, 1)
+		var record map[string]any
+		require.NoError(t, json.Unmarshal([]byte(records[0]), &record))
+		require.Equal(t, "deploying", record["msg"])
+		require.Equal(t, "podinfo", record["package"])
+		require.Equal(t, map[string]any{"name": "podinfo", "attempt": float64(1)}, record["component"])
+	}
+}
+
+func TestMultiHandlerLevels(t *testing.T) {
+	t.Parallel()
+
+	var debug, warn bytes.Buffer
+	h := NewMultiHandler(
+		slog.NewTextHandler(&debug, &slog.HandlerOptions{Level: slog.LevelDebug}),
+		slog.NewTextHandler(&warn, &slog.HandlerOptions{Level: slog.LevelWarn}),
+	)
+	require.True(t, h.Enabled(context.Background(), slog.LevelDebug))
+	require.False(t, NewMultiHandler().Enabled(context.Background(), slog.LevelError))
+
+	// Each handler only receives the records it is enabled for
+	l := slog.New(h)
+	l.Debug("debug")
+	l.Warn("warn")
+	require.Contains(t, debug.String(), "msg=debug")
+	require.Contains(t, debug.String(), "msg=warn")
+	require.NotContains(t, warn.String(), "msg=debug")
+	require.Contains(t, warn.String(), "msg=warn")
+}
+
+func TestMultiHandlerError(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	errFirst := errors.New("first")
+	errSecond := errors.New("second")
+	text := slog.NewTextHandler(&buf, nil)
+	h := NewMultiHandler(
+		errHandler{Handler: text, err: errFirst},
+		text,
+		errHandler{Handler: text, err: errSecond},
+	)
+
+	// Every handler receives the record and the first error is returned
+	err := h.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "deploying", 0))
+	require.ErrorIs(t, err, errFirst)
+	require.NotErrorIs(t, err, errSecond)
+	require.Contains(t, buf.String(), "msg=deploying")
+}
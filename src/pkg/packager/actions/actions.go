@@ -5,12 +5,18 @@
 package actions
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"os"
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -22,27 +28,104 @@ import (
 	ptmpl "github.com/zarf-dev/zarf/src/internal/packager/template"
 	"github.com/zarf-dev/zarf/src/internal/template"
 	"github.com/zarf-dev/zarf/src/pkg/logger"
+	"github.com/zarf-dev/zarf/src/pkg/tracing"
 	"github.com/zarf-dev/zarf/src/pkg/utils"
 	"github.com/zarf-dev/zarf/src/pkg/utils/exec"
 	"github.com/zarf-dev/zarf/src/pkg/value"
 	"github.com/zarf-dev/zarf/src/pkg/variables"
 	"github.com/zarf-dev/zarf/src/pkg/wait"
+	"golang.org/x/sync/errgroup"
+	"k8s.io/client-go/util/jsonpath"
 )
 
-// Run runs all provided actions.
+// ConfirmFunc approves an action that requires confirmation, returning false if the action must not run.
+type ConfirmFunc func(ctx context.Context, prompt string) (bool, error)
+
+// NewConfirmFunc returns a ConfirmFunc that prompts the user when isInteractive is true and stdin is a terminal.
+// When isInteractive is false, the caller has confirmed all actions up front (e.g. with --confirm) so they are approved.
+// Interactive runs without a terminal cannot be confirmed and are refused.
+func NewConfirmFunc(isInteractive bool, isTerminal func() bool, prompt ConfirmFunc) ConfirmFunc {
+	return func(ctx context.Context, message string) (bool, error) {
+		if !isInteractive {
+			return true, nil
+		}
+		if !isTerminal() {
+			return false, errors.New("action requires confirmation but stdin is not a terminal, rerun with --confirm to approve it")
+		}
+		return prompt(ctx, message)
+	}
+}
+
+// TagFilter selects the actions to run by their tags.
+type TagFilter struct {
+	// Tags selects the actions that have at least one of these tags, all actions are selected when empty.
+	Tags []string
+	// IncludeUntagged also selects the actions without tags when Tags is set.
+	IncludeUntagged bool
+}
+
+// Filter returns the actions selected by the filter and logs the ones that are skipped.
+func (f TagFilter) Filter(ctx context.Context, actions []v1alpha1.ZarfComponentAction) []v1alpha1.ZarfComponentAction {
+	if len(f.Tags) == 0 {
+		return actions
+	}
+	selected := []v1alpha1.ZarfComponentAction{}
+	for _, action := range actions {
+		if f.selects(action) {
+			selected = append(selected, action)
+			continue
+		}
+		name := action.Description
+		if name == "" {
+			name = helpers.Truncate(action.Cmd, 60, false)
+		}
+		logger.From(ctx).Info("skipping action not selected by tags", "action", name, "tags", action.Tags)
+	}
+	return selected
+}
+
+func (f TagFilter) selects(action v1alpha1.ZarfComponentAction) bool {
+	if len(action.Tags) == 0 {
+		return f.IncludeUntagged
+	}
+	for _, tag := range action.Tags {
+		if slices.Contains(f.Tags, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// Run runs all provided actions. Actions that require confirmation are approved with the ConfirmFunc of the context set
+// by WithConfirm, if the context has none they are refused.
 func Run(ctx context.Context, basePath string, defaultCfg v1alpha1.ZarfComponentActionDefaults, actions []v1alpha1.ZarfComponentAction, variableConfig *variables.VariableConfig, values value.Values) error {
 	if variableConfig == nil {
 		variableConfig = ptmpl.GetZarfVariableConfig(ctx, false)
 	}
 
 	for _, a := range actions {
-		if err := runAction(ctx, basePath, defaultCfg, a, variableConfig, values); err != nil {
+		actionCtx, span := tracing.Start(ctx, "run action", tracing.ActionKey.String(actionName(a)))
+		err := runAction(actionCtx, basePath, defaultCfg, a, variableConfig, values)
+		tracing.End(span, err)
+		if err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// actionName returns the description of the action, or what it runs when it has none.
+func actionName(action v1alpha1.ZarfComponentAction) string {
+	switch {
+	case action.Description != "":
+		return action.Description
+	case action.Wait != nil:
+		return "wait"
+	default:
+		return action.Cmd
+	}
+}
+
 // Run commands that a component has provided.
 func runAction(ctx context.Context, basePath string, defaultCfg v1alpha1.ZarfComponentActionDefaults, action v1alpha1.ZarfComponentAction, variableConfig *variables.VariableConfig, values value.Values) error {
 	var cmdEscaped string
@@ -55,6 +138,12 @@ func runAction(ctx context.Context, basePath string, defaultCfg v1alpha1.ZarfCom
 		WithConstants(variableConfig.GetConstants()).
 		WithVariables(variableConfig.GetSetVariableMap())
 
+	if action.Confirm {
+		if err := confirmAction(ctx, action, variableConfig, tmplObjs); err != nil {
+			return err
+		}
+	}
+
 	if action.Wait != nil {
 		err := runWaitAction(ctx, action, variableConfig, tmplObjs)
 		if err != nil {
@@ -81,7 +170,14 @@ func runAction(ctx context.Context, basePath string, defaultCfg v1alpha1.ZarfCom
 	l.Info("running command", "cmd", cmdEscaped)
 
 	actionDefaults := actionGetCfg(ctx, defaultCfg, action, variableConfig.GetAllTemplates())
-	actionDefaults.Dir = filepath.Join(basePath, actionDefaults.Dir)
+	actionDefaults.Dir, err = resolveActionDir(ctx, basePath, actionDefaults.Dir, action, variableConfig.GetAllTemplates(), tmplObjs)
+	if err != nil {
+		return fmt.Errorf("unable to resolve the dir for %s: %w", cmdEscaped, err)
+	}
+	actionDefaults.Env, err = resolveActionEnvFile(actionDefaults, len(defaultCfg.Env), action, variableConfig.GetAllTemplates())
+	if err != nil {
+		return fmt.Errorf("unable to resolve the env file for %s: %w", cmdEscaped, err)
+	}
 
 	if cmd, err = actionCmdMutation(ctx, cmd, actionDefaults.Shell, runtime.GOOS); err != nil {
 		l.Error("error mutating command", "cmd", cmdEscaped, "err", err.Error())
@@ -90,27 +186,64 @@ func runAction(ctx context.Context, basePath string, defaultCfg v1alpha1.ZarfCom
 	duration := time.Duration(actionDefaults.MaxTotalSeconds) * time.Second
 	timeout := time.After(duration)
 
+	// lastErr is the error of the last attempt, it is reported once the retries are exhausted
+	var lastErr error
+	// actionErr records the exit code and output of the last attempt
+	actionErr := &ActionError{
+		Action:   cmdEscaped,
+		ExitCode: -1,
+		Retries:  actionDefaults.MaxRetries,
+	}
+	if component, ok := ctx.Value(componentKey{}).(string); ok {
+		actionErr.Component = component
+	}
+
 	// Keep trying until the max retries is reached.
 	// TODO: Refactor using go-retry
 retryCmd:
 	for remaining := actionDefaults.MaxRetries + 1; remaining > 0; remaining-- {
+		// Stop retrying once the context is cancelled, e.g. when the deploy timeout is reached
+		if ctx.Err() != nil {
+			actionErr.Cancelled = true
+			actionErr.Err = context.Cause(ctx)
+			return actionErr
+		}
+
 		// Perform the action run.
 		tryCmd := func(ctx context.Context) error {
 			// Try running the command and continue the retry loop if it fails.
-			stdout, _, err := actionRun(ctx, actionDefaults, cmd)
-			if err != nil {
+			stdout, stderr, err := actionRun(ctx, actionDefaults, actionName(action), cmd)
+			// The output of muted actions is not kept as it may contain sensitive values
+			if !actionDefaults.Mute {
+				actionErr.Stdout, actionErr.Stderr = stdout, stderr
+			}
+			code, exited := exec.ExitCode(err)
+			actionErr.ExitCode = -1
+			if !exited {
 				return err
 			}
+			actionErr.ExitCode = code
+			if !actionDefaults.allowsExitCode(code) {
+				if err == nil {
+					return fmt.Errorf("exit code %d is not one of the allowed exit codes %v", code, actionDefaults.AllowedExitCodes)
+				}
+				return err
+			}
+			if code != 0 {
+				l.Info("action exited with an allowed exit code", "cmd", cmdEscaped, "code", code)
+			}
 			l.Info("action succeeded", "cmd", cmdEscaped)
 
 			outTrimmed := strings.TrimSpace(stdout)
 
 			// If an output variable is defined, set it.
-			for _, v := range action.SetVariables {
-				variableConfig.SetVariable(v.Name, outTrimmed, v.Sensitive, v.AutoIndent, v.Type)
-				if err := variableConfig.CheckVariablePattern(v.Name, v.Pattern); err != nil {
-					return err
-				}
+			if err := setVariables(outTrimmed, action.SetVariables, variableConfig); err != nil {
+				return err
+			}
+
+			// If JSON output variables are defined, set them to the values at their paths.
+			if err := setVariablesFromJSON(outTrimmed, action.SetVariablesFromJSON, variableConfig); err != nil {
+				return err
 			}
 
 			// If an output value is defined, parse the result and set it to values map.
@@ -130,6 +263,7 @@ retryCmd:
 		if actionDefaults.MaxTotalSeconds < 1 {
 			l.Info("waiting for action (no timeout)", "cmd", cmdEscaped)
 			if err := tryCmd(ctx); err != nil {
+				lastErr = err
 				continue retryCmd
 			}
 
@@ -149,6 +283,7 @@ retryCmd:
 			defer cancel()
 			if err := tryCmd(ctx); err != nil {
 				l.Warn("action failed", "cmd", cmdEscaped, "err", err.Error())
+				lastErr = err
 				continue retryCmd
 			}
 
@@ -156,18 +291,150 @@ retryCmd:
 		}
 	}
 
+	actionErr.Err = lastErr
 	select {
 	case <-timeout:
 		// If we reached this point, the timeout was reached or command failed with no retries.
-		if actionDefaults.MaxTotalSeconds < 1 {
-			return fmt.Errorf("command %q failed after %d retries", cmdEscaped, actionDefaults.MaxRetries)
-		} else {
-			return fmt.Errorf("command %q timed out after %d seconds", cmdEscaped, actionDefaults.MaxTotalSeconds)
+		if actionDefaults.MaxTotalSeconds >= 1 {
+			actionErr.Timeout = duration
 		}
 	default:
 		// If we reached this point, the retry limit was reached.
-		return fmt.Errorf("command %q failed after %d retries", cmdEscaped, actionDefaults.MaxRetries)
 	}
+	return actionErr
+}
+
+// ActionError is returned when the command of an action fails, is cancelled or times out.
+type ActionError struct {
+	// Component is the name of the component that ran the action, empty when it was not run for a component.
+	Component string
+	// Action is the description of the action, or its truncated command when it has none.
+	Action string
+	// ExitCode is the exit code of the last attempt, or -1 when the command did not exit.
+	ExitCode int
+	// Stdout and Stderr are the output of the last attempt, they are empty for muted actions.
+	Stdout string
+	Stderr string
+	// Retries is the number of retries allowed after the first attempt.
+	Retries int
+	// Timeout is the maxTotalSeconds of the action when it ran out of time, zero otherwise.
+	Timeout time.Duration
+	// Cancelled is true when the context was cancelled before the action succeeded, e.g. by the deploy timeout.
+	Cancelled bool
+	// Err is the error of the last attempt, or the cause of the cancellation.
+	Err error
+}
+
+func (e *ActionError) Error() string {
+	switch {
+	case e.Cancelled:
+		return fmt.Sprintf("command %q was cancelled: %v", e.Action, e.Err)
+	case e.Timeout > 0:
+		return fmt.Sprintf("command %q timed out after %d seconds", e.Action, int(e.Timeout.Seconds()))
+	case e.Err != nil:
+		return fmt.Sprintf("command %q failed after %d retries: %v", e.Action, e.Retries, e.Err)
+	default:
+		return fmt.Sprintf("command %q failed after %d retries", e.Action, e.Retries)
+	}
+}
+
+func (e *ActionError) Unwrap() error {
+	return e.Err
+}
+
+// TimedOut reports whether the action failed because it was cancelled or ran out of time rather than because its
+// command failed.
+func (e *ActionError) TimedOut() bool {
+	return e.Timeout > 0 || e.Cancelled
+}
+
+// unresolvedTemplateRegex matches variable placeholders that are left in a string after templating.
+var unresolvedTemplateRegex = regexp.MustCompile(`###[A-Z0-9_]+###|\$\{?(ZARF|TF)_(VAR|CONST)_[a-zA-Z0-9_-]+\}?`)
+
+// resolveActionDir templates the working directory of an action and validates that it is an existing directory.
+// Relative directories are resolved against basePath.
+func resolveActionDir(ctx context.Context, basePath, dir string, action v1alpha1.ZarfComponentAction, templates map[string]*variables.TextTemplate, tmplObjs template.Objects) (string, error) {
+	dir = templateVariables(dir, templates)
+	if action.ShouldTemplate() {
+		var err error
+		dir, err = template.Apply(ctx, dir, tmplObjs)
+		if err != nil {
+			return "", err
+		}
+	}
+	if unresolved := unresolvedTemplateRegex.FindString(dir); unresolved != "" {
+		return "", fmt.Errorf("dir %q contains the unresolved variable %s", dir, unresolved)
+	}
+
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(basePath, dir)
+	}
+	// An empty dir runs the action in the current working directory
+	if dir == "" {
+		return "", nil
+	}
+	fi, err := os.Stat(dir)
+	if err != nil {
+		return "", fmt.Errorf("dir %q is not accessible: %w", dir, err)
+	}
+	if !fi.IsDir() {
+		return "", fmt.Errorf("dir %q is not a directory", dir)
+	}
+	return dir, nil
+}
+
+// resolveActionEnvFile adds the entries of the env file of an action to its environment after the defaults at
+// defaultsLen so that env set on the action takes precedence. Relative paths are resolved against the action dir.
+func resolveActionEnvFile(cfg ResolvedAction, defaultsLen int, action v1alpha1.ZarfComponentAction, templates map[string]*variables.TextTemplate) ([]string, error) {
+	if action.EnvFile == "" {
+		return cfg.Env, nil
+	}
+	path := action.EnvFile
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(cfg.Dir, path)
+	}
+	env, err := utils.ParseEnvFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read env file %q: %w", path, err)
+	}
+	for idx, entry := range env {
+		env[idx] = templateVariables(entry, templates)
+	}
+	return slices.Insert(slices.Clone(cfg.Env), defaultsLen, env...), nil
+}
+
+// confirmAction asks for approval to run an action, returning an error if it is refused.
+func confirmAction(ctx context.Context, action v1alpha1.ZarfComponentAction, variableConfig *variables.VariableConfig, tmplObjs template.Objects) error {
+	name := action.Description
+	if name == "" {
+		name = helpers.Truncate(action.Cmd, 60, false)
+	}
+	prompt := action.ConfirmPrompt
+	if prompt == "" {
+		prompt = fmt.Sprintf("Run the action %q?", name)
+	}
+	prompt = templateString(prompt, variableConfig.GetAllTemplates())
+	if action.ShouldTemplate() {
+		var err error
+		prompt, err = template.Apply(ctx, prompt, tmplObjs)
+		if err != nil {
+			return fmt.Errorf("could not template the confirmation prompt for %s: %w", name, err)
+		}
+	}
+
+	confirm, ok := ctx.Value(confirmKey{}).(ConfirmFunc)
+	if !ok || confirm == nil {
+		return fmt.Errorf("action %q requires confirmation", name)
+	}
+	approved, err := confirm(ctx, prompt)
+	if err != nil {
+		return fmt.Errorf("unable to confirm action %q: %w", name, err)
+	}
+	if !approved {
+		return fmt.Errorf("action %q was not confirmed", name)
+	}
+	logger.From(ctx).Debug("action confirmed", "action", name)
+	return nil
 }
 
 func runWaitAction(ctx context.Context, action v1alpha1.ZarfComponentAction, variableConfig *variables.VariableConfig, tmplObjs template.Objects) error {
@@ -190,32 +457,39 @@ func runWaitAction(ctx context.Context, action v1alpha1.ZarfComponentAction, var
 	}
 
 	switch {
-	case waitCfg.Cluster != nil:
-		cluster := waitCfg.Cluster
-		cluster.Kind = templateString(cluster.Kind, templates)
-		cluster.Name = templateString(cluster.Name, templates)
-		cluster.Namespace = templateString(cluster.Namespace, templates)
-		cluster.Condition = templateString(cluster.Condition, templates)
-		if applyTemplates != nil {
-			var err error
-			if cluster.Kind, err = applyTemplates(cluster.Kind); err != nil {
-				return fmt.Errorf("could not template wait.cluster.kind: %w", err)
-			}
-			if cluster.Name, err = applyTemplates(cluster.Name); err != nil {
-				return fmt.Errorf("could not template wait.cluster.name: %w", err)
-			}
-			if cluster.Namespace, err = applyTemplates(cluster.Namespace); err != nil {
-				return fmt.Errorf("could not template wait.cluster.namespace: %w", err)
-			}
-			if cluster.Condition, err = applyTemplates(cluster.Condition); err != nil {
-				return fmt.Errorf("could not template wait.cluster.condition: %w", err)
+	case waitCfg.Cluster != nil || len(waitCfg.Clusters) > 0:
+		conditions := waitCfg.ClusterConditions()
+		for i := range conditions {
+			cluster := &conditions[i]
+			cluster.Kind = templateString(cluster.Kind, templates)
+			cluster.Name = templateString(cluster.Name, templates)
+			cluster.Namespace = templateString(cluster.Namespace, templates)
+			cluster.Condition = templateString(cluster.Condition, templates)
+			if applyTemplates != nil {
+				var err error
+				if cluster.Kind, err = applyTemplates(cluster.Kind); err != nil {
+					return fmt.Errorf("could not template wait.cluster.kind: %w", err)
+				}
+				if cluster.Name, err = applyTemplates(cluster.Name); err != nil {
+					return fmt.Errorf("could not template wait.cluster.name: %w", err)
+				}
+				if cluster.Namespace, err = applyTemplates(cluster.Namespace); err != nil {
+					return fmt.Errorf("could not template wait.cluster.namespace: %w", err)
+				}
+				if cluster.Condition, err = applyTemplates(cluster.Condition); err != nil {
+					return fmt.Errorf("could not template wait.cluster.condition: %w", err)
+				}
 			}
 		}
-		return runWaitClusterAction(ctx, cluster, timeout)
+		return runWaitClusterConditions(ctx, conditions, timeout, wait.ForResourceWithOptions)
 	case waitCfg.Network != nil:
-		network := waitCfg.Network
+		network := *waitCfg.Network
 		network.Protocol = templateString(network.Protocol, templates)
 		network.Address = templateString(network.Address, templates)
+		network.Headers = make(map[string]string, len(waitCfg.Network.Headers))
+		for key, value := range waitCfg.Network.Headers {
+			network.Headers[key] = templateVariables(value, templates)
+		}
 		if applyTemplates != nil {
 			var err error
 			if network.Protocol, err = applyTemplates(network.Protocol); err != nil {
@@ -224,11 +498,35 @@ func runWaitAction(ctx context.Context, action v1alpha1.ZarfComponentAction, var
 			if network.Address, err = applyTemplates(network.Address); err != nil {
 				return fmt.Errorf("could not template wait.network.address: %w", err)
 			}
+			for key, value := range network.Headers {
+				if network.Headers[key], err = applyTemplates(value); err != nil {
+					return fmt.Errorf("could not template wait.network.headers.%s: %w", key, err)
+				}
+			}
 		}
 		return runWaitNetworkAction(ctx, network, timeout)
+	case waitCfg.Variable != nil:
+		variable := *waitCfg.Variable
+		variable.Value = templateString(variable.Value, templates)
+		if applyTemplates != nil {
+			var err error
+			if variable.Value, err = applyTemplates(variable.Value); err != nil {
+				return fmt.Errorf("could not template wait.variable.value: %w", err)
+			}
+		}
+		return runWaitVariableAction(ctx, variable, variableConfig, timeout)
 	default:
-		return fmt.Errorf("wait action is missing a cluster or network")
+		return fmt.Errorf("wait action is missing a cluster, network or variable")
+	}
+}
+
+// templateVariables replaces the ###VAR### placeholders of the variables and constants in s along with the ${VAR} and
+// $VAR syntax replaced by templateString.
+func templateVariables(s string, templates map[string]*variables.TextTemplate) string {
+	for key, tmpl := range templates {
+		s = strings.ReplaceAll(s, key, tmpl.Value)
 	}
+	return templateString(s, templates)
 }
 
 func templateString(s string, templates map[string]*variables.TextTemplate) string {
@@ -254,7 +552,24 @@ func templateString(s string, templates map[string]*variables.TextTemplate) stri
 	return s
 }
 
-func runWaitClusterAction(ctx context.Context, cluster *v1alpha1.ZarfComponentActionWaitCluster, timeout time.Duration) error {
+// waitForResourceFunc waits for a resource in the cluster to meet a condition, see wait.ForResourceWithOptions.
+type waitForResourceFunc func(ctx context.Context, kind, identifier, condition, namespace string, timeout time.Duration, opts wait.ResourceOptions) error
+
+// runWaitClusterConditions waits for all conditions concurrently within the same timeout and stops waiting on the
+// remaining conditions once one of them fails.
+func runWaitClusterConditions(ctx context.Context, conditions []v1alpha1.ZarfComponentActionWaitCluster, timeout time.Duration, waitFor waitForResourceFunc) error {
+	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	g, gCtx := errgroup.WithContext(timeoutCtx)
+	for _, cluster := range conditions {
+		g.Go(func() error {
+			return runWaitClusterAction(gCtx, cluster, timeout, waitFor)
+		})
+	}
+	return g.Wait()
+}
+
+func runWaitClusterAction(ctx context.Context, cluster v1alpha1.ZarfComponentActionWaitCluster, timeout time.Duration, waitFor waitForResourceFunc) error {
 	l := logger.From(ctx)
 
 	kind := cluster.Kind
@@ -266,12 +581,19 @@ func runWaitClusterAction(ctx context.Context, cluster *v1alpha1.ZarfComponentAc
 	if condition != "" {
 		desc = fmt.Sprintf("%s to be %s", desc, condition)
 	}
+	if cluster.MinReady > 0 {
+		desc = fmt.Sprintf("%s (at least %d)", desc, cluster.MinReady)
+	}
 	l.Info("running wait action", "description", desc)
 
-	return wait.ForResource(ctx, kind, identifier, condition, namespace, timeout)
+	opts := wait.ResourceOptions{MinReady: cluster.MinReady}
+	if err := waitFor(ctx, kind, identifier, condition, namespace, timeout, opts); err != nil {
+		return fmt.Errorf("%s: %w", desc, err)
+	}
+	return nil
 }
 
-func runWaitNetworkAction(ctx context.Context, network *v1alpha1.ZarfComponentActionWaitNetwork, timeout time.Duration) error {
+func runWaitNetworkAction(ctx context.Context, network v1alpha1.ZarfComponentActionWaitNetwork, timeout time.Duration) error {
 	l := logger.From(ctx)
 
 	kind := strings.ToLower(network.Protocol)
@@ -289,7 +611,46 @@ func runWaitNetworkAction(ctx context.Context, network *v1alpha1.ZarfComponentAc
 	}
 	l.Info("running wait action", "description", desc)
 
-	return wait.ForNetwork(ctx, kind, identifier, condition, timeout)
+	opts := wait.NetworkOptions{
+		Method:             network.Method,
+		Headers:            network.Headers,
+		InsecureSkipVerify: network.InsecureSkipVerify,
+	}
+	return wait.ForNetworkWithOptions(ctx, kind, identifier, condition, timeout, opts)
+}
+
+// variableWaitInterval is how often a variable wait action checks the variable.
+var variableWaitInterval = 250 * time.Millisecond
+
+// runWaitVariableAction blocks until the variable is set in the variable config, or set to the given value if one is
+// specified. Variables are only shared within the variable config of a single deploy, so this only unblocks when
+// another action of the same deploy sets the variable, e.g. one running concurrently.
+func runWaitVariableAction(ctx context.Context, variable v1alpha1.ZarfComponentActionWaitVariable, variableConfig *variables.VariableConfig, timeout time.Duration) error {
+	desc := fmt.Sprintf("wait for variable %s", variable.Name)
+	if variable.Value != "" {
+		desc = fmt.Sprintf("%s to be %s", desc, variable.Value)
+	}
+	logger.From(ctx).Info("running wait action", "description", desc)
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ticker := time.NewTicker(variableWaitInterval)
+	defer ticker.Stop()
+	for {
+		if v, ok := variableConfig.GetSetVariable(variable.Name); ok && v.Value != "" {
+			if variable.Value == "" || v.Value == variable.Value {
+				return nil
+			}
+		}
+		select {
+		case <-timeoutCtx.Done():
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("timed out after %s waiting for variable %s", timeout, variable.Name)
+		case <-ticker.C:
+		}
+	}
 }
 
 // Perform some basic string mutations to make commands more useful.
@@ -328,31 +689,87 @@ func actionCmdMutation(ctx context.Context, cmd string, shellPref v1alpha1.Shell
 	return cmd, nil
 }
 
-// Merge the ActionSet defaults with the action config.
-func actionGetCfg(_ context.Context, cfg v1alpha1.ZarfComponentActionDefaults, a v1alpha1.ZarfComponentAction, vars map[string]*variables.TextTemplate) v1alpha1.ZarfComponentActionDefaults {
-	if a.Mute != nil {
-		cfg.Mute = *a.Mute
+// ResolvedAction is the effective configuration of an action after the component's action defaults are applied.
+type ResolvedAction struct {
+	// Hide the output of the command.
+	Mute bool
+	// Timeout in seconds for the command, 0 means no timeout.
+	MaxTotalSeconds int
+	// Number of times to retry the command if it fails.
+	MaxRetries int
+	// Working directory for the command.
+	Dir string
+	// Environment variables for the command, the defaults followed by the action's own.
+	Env []string
+	// Shell preference for the command.
+	Shell v1alpha1.Shell
+	// Exit codes of the command that count as success, only 0 when empty.
+	AllowedExitCodes []int
+}
+
+// allowsExitCode returns whether the exit code of the command counts as success.
+func (r ResolvedAction) allowsExitCode(code int) bool {
+	if len(r.AllowedExitCodes) == 0 {
+		return code == 0
+	}
+	return slices.Contains(r.AllowedExitCodes, code)
+}
+
+// ResolveAction merges the action set defaults with the action config. Fields set on the action take precedence
+// over the defaults, except Env which is appended to the default environment.
+func ResolveAction(defaults v1alpha1.ZarfComponentActionDefaults, action v1alpha1.ZarfComponentAction) ResolvedAction {
+	resolved := ResolvedAction{
+		Mute:             defaults.Mute,
+		MaxTotalSeconds:  defaults.MaxTotalSeconds,
+		MaxRetries:       defaults.MaxRetries,
+		Dir:              defaults.Dir,
+		Shell:            defaults.Shell,
+		AllowedExitCodes: defaults.AllowedExitCodes,
+	}
+
+	if action.Mute != nil {
+		resolved.Mute = *action.Mute
 	}
 
 	// Default is no timeout, but add a timeout if one is provided.
-	if a.MaxTotalSeconds != nil {
-		cfg.MaxTotalSeconds = *a.MaxTotalSeconds
+	if action.MaxTotalSeconds != nil {
+		resolved.MaxTotalSeconds = *action.MaxTotalSeconds
+	}
+
+	if action.MaxRetries != nil {
+		resolved.MaxRetries = *action.MaxRetries
 	}
 
-	if a.MaxRetries != nil {
-		cfg.MaxRetries = *a.MaxRetries
+	if action.Dir != nil {
+		resolved.Dir = *action.Dir
 	}
 
-	if a.Dir != nil {
-		cfg.Dir = *a.Dir
+	// Copy the environment so that appending never modifies the defaults shared between actions.
+	env := make([]string, 0, len(defaults.Env)+len(action.Env))
+	env = append(env, defaults.Env...)
+	env = append(env, action.Env...)
+	if len(env) > 0 {
+		resolved.Env = env
 	}
 
-	if len(a.Env) > 0 {
-		cfg.Env = append(cfg.Env, a.Env...)
+	if action.Shell != nil {
+		resolved.Shell = *action.Shell
 	}
 
-	if a.Shell != nil {
-		cfg.Shell = *a.Shell
+	if len(action.AllowedExitCodes) > 0 {
+		resolved.AllowedExitCodes = action.AllowedExitCodes
+	}
+
+	return resolved
+}
+
+// Resolve the action config and add the variables to its environment.
+func actionGetCfg(_ context.Context, cfg v1alpha1.ZarfComponentActionDefaults, a v1alpha1.ZarfComponentAction, vars map[string]*variables.TextTemplate) ResolvedAction {
+	resolved := ResolveAction(cfg, a)
+
+	// Template variables in the environment, such as the entries of a component env file.
+	for idx, env := range resolved.Env {
+		resolved.Env[idx] = templateVariables(env, vars)
 	}
 
 	// Add variables to the environment.
@@ -361,14 +778,14 @@ func actionGetCfg(_ context.Context, cfg v1alpha1.ZarfComponentActionDefaults, a
 		k = strings.ReplaceAll(k, "#", "")
 		// Make terraform variables available to the action as TF_VAR_lowercase_name.
 		k1 := strings.ReplaceAll(strings.ToLower(k), "zarf_var", "TF_VAR")
-		cfg.Env = append(cfg.Env, fmt.Sprintf("%s=%s", k, v.Value))
-		cfg.Env = append(cfg.Env, fmt.Sprintf("%s=%s", k1, v.Value))
+		resolved.Env = append(resolved.Env, fmt.Sprintf("%s=%s", k, v.Value))
+		resolved.Env = append(resolved.Env, fmt.Sprintf("%s=%s", k1, v.Value))
 	}
 
-	return cfg
+	return resolved
 }
 
-func actionRun(ctx context.Context, cfg v1alpha1.ZarfComponentActionDefaults, cmd string) (string, string, error) {
+func actionRun(ctx context.Context, cfg ResolvedAction, name, cmd string) (string, string, error) {
 	l := logger.From(ctx)
 	start := time.Now()
 	shell, shellArgs := exec.GetOSShell(cfg.Shell)
@@ -376,12 +793,28 @@ func actionRun(ctx context.Context, cfg v1alpha1.ZarfComponentActionDefaults, cm
 	l.Debug("running command", "shell", shell, "cmd", cmd)
 
 	execCfg := exec.Config{
-		Env:   cfg.Env,
-		Dir:   cfg.Dir,
-		Print: !cfg.Mute,
+		Env: cfg.Env,
+		Dir: cfg.Dir,
+	}
+
+	// Stream the output line by line as it is produced (respect mute to prevent sensitive values from hitting the logs).
+	var outputWriters []*lineLogWriter
+	if !cfg.Mute {
+		attrs := []any{"action", name}
+		if component, ok := ctx.Value(componentKey{}).(string); ok {
+			attrs = append([]any{"component", component}, attrs...)
+		}
+		stdoutWriter := &lineLogWriter{logger: l.With(append(attrs, "stream", "stdout")...)}
+		stderrWriter := &lineLogWriter{logger: l.With(append(attrs, "stream", "stderr")...)}
+		execCfg.Stdout = stdoutWriter
+		execCfg.Stderr = stderrWriter
+		outputWriters = append(outputWriters, stdoutWriter, stderrWriter)
 	}
 
 	stdout, stderr, err := exec.CmdWithContext(ctx, execCfg, shell, append(shellArgs, cmd)...)
+	for _, w := range outputWriters {
+		w.Flush()
+	}
 	// Dump final complete output (respect mute to prevent sensitive values from hitting the logs).
 	if !cfg.Mute {
 		l.Debug("command complete", "stdout", stdout, "stderr", stderr, "duration", time.Since(start))
@@ -389,6 +822,57 @@ func actionRun(ctx context.Context, cfg v1alpha1.ZarfComponentActionDefaults, cm
 	return stdout, stderr, err
 }
 
+// componentKey is the context key of the name of the component whose actions are run.
+type componentKey struct{}
+
+// WithComponent returns a context that adds the name of the component to the output of the actions run with it.
+func WithComponent(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, componentKey{}, name)
+}
+
+// confirmKey is the context key of the ConfirmFunc that approves the actions run with it.
+type confirmKey struct{}
+
+// WithConfirm returns a context whose actions that require confirmation are approved with confirm.
+func WithConfirm(ctx context.Context, confirm ConfirmFunc) context.Context {
+	return context.WithValue(ctx, confirmKey{}, confirm)
+}
+
+// lineLogWriter logs every line written to it once the line is complete.
+type lineLogWriter struct {
+	logger *slog.Logger
+	buf    []byte
+}
+
+func (w *lineLogWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		idx := bytes.IndexByte(w.buf, '\n')
+		if idx < 0 {
+			break
+		}
+		w.log(w.buf[:idx])
+		w.buf = append(w.buf[:0], w.buf[idx+1:]...)
+	}
+	return len(p), nil
+}
+
+// Flush logs the last line if the output did not end with a newline.
+func (w *lineLogWriter) Flush() {
+	if len(w.buf) > 0 {
+		w.log(w.buf)
+		w.buf = nil
+	}
+}
+
+func (w *lineLogWriter) log(line []byte) {
+	msg := strings.TrimRight(string(line), "\r")
+	if strings.TrimSpace(msg) == "" {
+		return
+	}
+	w.logger.Info(msg)
+}
+
 // MatchAllRegex wraps a get function around each substring match, returning all matches.
 func MatchAllRegex(regex *regexp.Regexp, str string) []func(string) string {
 	// Validate the string.
@@ -404,6 +888,116 @@ func MatchAllRegex(regex *regexp.Regexp, str string) []func(string) string {
 	return funcs
 }
 
+// setVariablesFromJSON parses the output of a command as JSON and sets each variable to the value at its path. A path
+// that is not found fails unless the variable is optional, in which case the variable is set to an empty value.
+func setVariablesFromJSON(output string, jsonVariables []v1alpha1.ZarfComponentActionJSONVariable, variableConfig *variables.VariableConfig) error {
+	if len(jsonVariables) == 0 {
+		return nil
+	}
+	// Numbers are kept as written so that large integers do not lose precision as float64
+	dec := json.NewDecoder(strings.NewReader(output))
+	dec.UseNumber()
+	var data any
+	if err := dec.Decode(&data); err != nil {
+		return fmt.Errorf("unable to parse the output of the command as JSON: %w", err)
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return errors.New("unable to parse the output of the command as JSON: unexpected data after the top-level value")
+	}
+	for _, v := range jsonVariables {
+		val, err := extractJSONPath(data, v.JSONPath())
+		if err != nil {
+			if !v.Optional {
+				return fmt.Errorf("unable to set variable %s from path %s: %w", v.Name, v.Path, err)
+			}
+			val = ""
+		}
+		variableConfig.SetVariable(v.Name, val, v.Sensitive, v.AutoIndent, v.Type)
+		if err := variableConfig.CheckVariablePattern(v.Name, v.Pattern); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setVariables sets the variables to the output of the command. A variable whose pattern has a group named value is set
+// to that group of its match instead, other patterns validate the whole output.
+func setVariables(output string, outputVariables []v1alpha1.Variable, variableConfig *variables.VariableConfig) error {
+	for _, v := range outputVariables {
+		val, extracted, err := extractPattern(output, v)
+		if err != nil {
+			return err
+		}
+		variableConfig.SetVariable(v.Name, val, v.Sensitive, v.AutoIndent, v.Type)
+		if extracted {
+			continue
+		}
+		if err := variableConfig.CheckVariablePattern(v.Name, v.Pattern); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// extractPattern returns the group named value of the variable pattern in the output and whether the pattern has such a
+// group. The output is returned as is for patterns without one, so that existing patterns only validate the output.
+func extractPattern(output string, v v1alpha1.Variable) (string, bool, error) {
+	if v.Pattern == "" {
+		return output, false, nil
+	}
+	re, err := regexp.Compile(v.Pattern)
+	if err != nil {
+		return "", false, fmt.Errorf("invalid pattern %q for variable %s: %w", v.Pattern, v.Name, err)
+	}
+	group := re.SubexpIndex("value")
+	if group == -1 {
+		return output, false, nil
+	}
+	match := re.FindStringSubmatch(output)
+	if match == nil {
+		if v.Sensitive {
+			return "", true, fmt.Errorf("pattern %q for variable %s did not match the output of the command", v.Pattern, v.Name)
+		}
+		return "", true, fmt.Errorf("pattern %q for variable %s did not match the output of the command: %q", v.Pattern, v.Name, output)
+	}
+	return match[group], true, nil
+}
+
+// extractJSONPath returns the value at the JSONPath in the data. Strings are returned as is and other values as JSON,
+// a path that matches multiple values returns them as a JSON array.
+func extractJSONPath(data any, path string) (string, error) {
+	jp := jsonpath.New("setVariablesFromJSON")
+	if err := jp.Parse(path); err != nil {
+		return "", err
+	}
+	results, err := jp.FindResults(data)
+	if err != nil {
+		return "", err
+	}
+	matches := []any{}
+	for _, result := range results {
+		for _, r := range result {
+			matches = append(matches, r.Interface())
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no value found")
+	case 1:
+		switch match := matches[0].(type) {
+		case string:
+			return match, nil
+		case nil:
+			return "", nil
+		}
+		b, err := json.Marshal(matches[0])
+		return string(b), err
+	default:
+		b, err := json.Marshal(matches)
+		return string(b), err
+	}
+}
+
 // parseAndSetValue parses the output string according to the setValue type and sets it in the values map.
 func parseAndSetValue(output string, setValue v1alpha1.SetValue, values value.Values) error {
 	var val any
@@ -4,16 +4,31 @@
 package actions
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/stretchr/testify/require"
 
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/internal/template"
+	"github.com/zarf-dev/zarf/src/pkg/logger"
 	"github.com/zarf-dev/zarf/src/pkg/utils"
 	"github.com/zarf-dev/zarf/src/pkg/value"
 	"github.com/zarf-dev/zarf/src/pkg/variables"
+	"github.com/zarf-dev/zarf/src/pkg/wait"
 )
 
 func Test_actionCmdMutation(t *testing.T) {
@@ -265,6 +280,11 @@ func Test_templateString(t *testing.T) {
 			input:    "${ZARF_VAR_NAME} in $ZARF_VAR_NAMESPACE",
 			expected: "agent-hook in zarf",
 		},
+		{
+			name:     "zarf template syntax is left unchanged",
+			input:    "Bearer ###ZARF_VAR_NAME###",
+			expected: "Bearer ###ZARF_VAR_NAME###",
+		},
 	}
 
 	for _, tt := range tests {
@@ -276,6 +296,15 @@ func Test_templateString(t *testing.T) {
 	}
 }
 
+func Test_templateVariables(t *testing.T) {
+	t.Parallel()
+	templates := map[string]*variables.TextTemplate{
+		"###ZARF_VAR_NAME###": {Value: "agent-hook"},
+	}
+	require.Equal(t, "Bearer agent-hook", templateVariables("Bearer ###ZARF_VAR_NAME###", templates))
+	require.Equal(t, "agent-hook in agent-hook", templateVariables("${ZARF_VAR_NAME} in $ZARF_VAR_NAME", templates))
+}
+
 func Test_parseAndSetValue_Errors(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -332,3 +361,1140 @@ func Test_parseAndSetValue_Errors(t *testing.T) {
 		})
 	}
 }
+
+func Test_NewConfirmFunc(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		isInteractive bool
+		isTerminal    bool
+		answer        bool
+		expected      bool
+		expectPrompt  bool
+		errSubstr     string
+	}{
+		{
+			name:          "terminal prompt approved",
+			isInteractive: true,
+			isTerminal:    true,
+			answer:        true,
+			expected:      true,
+			expectPrompt:  true,
+		},
+		{
+			name:          "terminal prompt declined",
+			isInteractive: true,
+			isTerminal:    true,
+			answer:        false,
+			expected:      false,
+			expectPrompt:  true,
+		},
+		{
+			name:          "non-terminal requires --confirm",
+			isInteractive: true,
+			isTerminal:    false,
+			errSubstr:     "rerun with --confirm",
+		},
+		{
+			name:          "confirmed up front",
+			isInteractive: false,
+			isTerminal:    false,
+			expected:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			prompted := false
+			confirm := NewConfirmFunc(tt.isInteractive, func() bool { return tt.isTerminal }, func(_ context.Context, _ string) (bool, error) {
+				prompted = true
+				return tt.answer, nil
+			})
+
+			ok, err := confirm(context.Background(), "Drop the database?")
+			require.Equal(t, tt.expectPrompt, prompted)
+			if tt.errSubstr != "" {
+				require.ErrorContains(t, err, tt.errSubstr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, ok)
+		})
+	}
+}
+
+func Test_RunConfirm(t *testing.T) {
+	t.Parallel()
+
+	newVariableConfig := func() *variables.VariableConfig {
+		vc := variables.New("zarf", nil, nil)
+		vc.SetVariable("DATABASE", "orders", false, false, v1alpha1.RawVariableType)
+		return vc
+	}
+	action := v1alpha1.ZarfComponentAction{
+		Cmd:           "echo dropped",
+		Confirm:       true,
+		ConfirmPrompt: "Drop the ${ZARF_VAR_DATABASE} database?",
+		SetVariables:  []v1alpha1.Variable{{Name: "OUTPUT"}},
+	}
+
+	t.Run("approved action runs with a templated prompt", func(t *testing.T) {
+		t.Parallel()
+		vc := newVariableConfig()
+		var prompt string
+		ctx := WithConfirm(context.Background(), func(_ context.Context, p string) (bool, error) {
+			prompt = p
+			return true, nil
+		})
+		err := Run(ctx, "", v1alpha1.ZarfComponentActionDefaults{}, []v1alpha1.ZarfComponentAction{action}, vc, nil)
+		require.NoError(t, err)
+		require.Equal(t, "Drop the orders database?", prompt)
+		output, ok := vc.GetSetVariable("OUTPUT")
+		require.True(t, ok)
+		require.Equal(t, "dropped", output.Value)
+	})
+
+	t.Run("declined action does not run", func(t *testing.T) {
+		t.Parallel()
+		vc := newVariableConfig()
+		ctx := WithConfirm(context.Background(), func(_ context.Context, _ string) (bool, error) {
+			return false, nil
+		})
+		err := Run(ctx, "", v1alpha1.ZarfComponentActionDefaults{}, []v1alpha1.ZarfComponentAction{action}, vc, nil)
+		require.EqualError(t, err, `action "echo dropped" was not confirmed`)
+		_, ok := vc.GetSetVariable("OUTPUT")
+		require.False(t, ok)
+	})
+
+	t.Run("actions requiring confirmation are refused without a confirm func", func(t *testing.T) {
+		t.Parallel()
+		err := Run(context.Background(), "", v1alpha1.ZarfComponentActionDefaults{}, []v1alpha1.ZarfComponentAction{action}, newVariableConfig(), nil)
+		require.EqualError(t, err, `action "echo dropped" requires confirmation`)
+	})
+}
+
+func Test_ResolveAction(t *testing.T) {
+	t.Parallel()
+
+	defaults := v1alpha1.ZarfComponentActionDefaults{
+		Mute:            true,
+		MaxTotalSeconds: 60,
+		MaxRetries:      2,
+		Dir:             "defaults",
+		Env:             []string{"DEFAULT=true"},
+		Shell:           v1alpha1.Shell{Linux: "bash"},
+	}
+	zero := 0
+	five := 5
+	emptyDir := ""
+	actionDir := "action"
+	actionShell := v1alpha1.Shell{Linux: "sh", Windows: "pwsh"}
+
+	tests := []struct {
+		name     string
+		defaults v1alpha1.ZarfComponentActionDefaults
+		action   v1alpha1.ZarfComponentAction
+		expected ResolvedAction
+	}{
+		{
+			name:     "no defaults and no overrides",
+			expected: ResolvedAction{},
+		},
+		{
+			name:     "defaults are used when the action sets nothing",
+			defaults: defaults,
+			expected: ResolvedAction{
+				Mute:            true,
+				MaxTotalSeconds: 60,
+				MaxRetries:      2,
+				Dir:             "defaults",
+				Env:             []string{"DEFAULT=true"},
+				Shell:           v1alpha1.Shell{Linux: "bash"},
+			},
+		},
+		{
+			name:     "mute is overridden",
+			defaults: defaults,
+			action:   v1alpha1.ZarfComponentAction{Mute: helpers.BoolPtr(false)},
+			expected: ResolvedAction{
+				Mute:            false,
+				MaxTotalSeconds: 60,
+				MaxRetries:      2,
+				Dir:             "defaults",
+				Env:             []string{"DEFAULT=true"},
+				Shell:           v1alpha1.Shell{Linux: "bash"},
+			},
+		},
+		{
+			name:     "maxTotalSeconds is overridden with zero",
+			defaults: defaults,
+			action:   v1alpha1.ZarfComponentAction{MaxTotalSeconds: &zero},
+			expected: ResolvedAction{
+				Mute:            true,
+				MaxTotalSeconds: 0,
+				MaxRetries:      2,
+				Dir:             "defaults",
+				Env:             []string{"DEFAULT=true"},
+				Shell:           v1alpha1.Shell{Linux: "bash"},
+			},
+		},
+		{
+			name:     "maxRetries is overridden",
+			defaults: defaults,
+			action:   v1alpha1.ZarfComponentAction{MaxRetries: &five},
+			expected: ResolvedAction{
+				Mute:            true,
+				MaxTotalSeconds: 60,
+				MaxRetries:      5,
+				Dir:             "defaults",
+				Env:             []string{"DEFAULT=true"},
+				Shell:           v1alpha1.Shell{Linux: "bash"},
+			},
+		},
+		{
+			name:     "dir is overridden",
+			defaults: defaults,
+			action:   v1alpha1.ZarfComponentAction{Dir: &actionDir},
+			expected: ResolvedAction{
+				Mute:            true,
+				MaxTotalSeconds: 60,
+				MaxRetries:      2,
+				Dir:             "action",
+				Env:             []string{"DEFAULT=true"},
+				Shell:           v1alpha1.Shell{Linux: "bash"},
+			},
+		},
+		{
+			name:     "dir is overridden with an empty string",
+			defaults: defaults,
+			action:   v1alpha1.ZarfComponentAction{Dir: &emptyDir},
+			expected: ResolvedAction{
+				Mute:            true,
+				MaxTotalSeconds: 60,
+				MaxRetries:      2,
+				Dir:             "",
+				Env:             []string{"DEFAULT=true"},
+				Shell:           v1alpha1.Shell{Linux: "bash"},
+			},
+		},
+		{
+			name:     "env is appended to the defaults",
+			defaults: defaults,
+			action:   v1alpha1.ZarfComponentAction{Env: []string{"ACTION=true", "DEFAULT=false"}},
+			expected: ResolvedAction{
+				Mute:            true,
+				MaxTotalSeconds: 60,
+				MaxRetries:      2,
+				Dir:             "defaults",
+				Env:             []string{"DEFAULT=true", "ACTION=true", "DEFAULT=false"},
+				Shell:           v1alpha1.Shell{Linux: "bash"},
+			},
+		},
+		{
+			name:   "env without defaults",
+			action: v1alpha1.ZarfComponentAction{Env: []string{"ACTION=true"}},
+			expected: ResolvedAction{
+				Env: []string{"ACTION=true"},
+			},
+		},
+		{
+			name:     "shell is overridden",
+			defaults: defaults,
+			action:   v1alpha1.ZarfComponentAction{Shell: &actionShell},
+			expected: ResolvedAction{
+				Mute:            true,
+				MaxTotalSeconds: 60,
+				MaxRetries:      2,
+				Dir:             "defaults",
+				Env:             []string{"DEFAULT=true"},
+				Shell:           v1alpha1.Shell{Linux: "sh", Windows: "pwsh"},
+			},
+		},
+		{
+			name:     "allowed exit codes are overridden",
+			defaults: v1alpha1.ZarfComponentActionDefaults{AllowedExitCodes: []int{0, 1}},
+			action:   v1alpha1.ZarfComponentAction{AllowedExitCodes: []int{0, 2}},
+			expected: ResolvedAction{AllowedExitCodes: []int{0, 2}},
+		},
+		{
+			name:     "all fields are overridden",
+			defaults: defaults,
+			action: v1alpha1.ZarfComponentAction{
+				Mute:            helpers.BoolPtr(false),
+				MaxTotalSeconds: &five,
+				MaxRetries:      &zero,
+				Dir:             &actionDir,
+				Env:             []string{"ACTION=true"},
+				Shell:           &actionShell,
+			},
+			expected: ResolvedAction{
+				Mute:            false,
+				MaxTotalSeconds: 5,
+				MaxRetries:      0,
+				Dir:             "action",
+				Env:             []string{"DEFAULT=true", "ACTION=true"},
+				Shell:           v1alpha1.Shell{Linux: "sh", Windows: "pwsh"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.expected, ResolveAction(tt.defaults, tt.action))
+		})
+	}
+}
+
+func Test_RunAllowedExitCodes(t *testing.T) {
+	t.Parallel()
+
+	retries := 2
+
+	tests := []struct {
+		name          string
+		defaults      v1alpha1.ZarfComponentActionDefaults
+		action        v1alpha1.ZarfComponentAction
+		expectedRuns  int
+		expectedError string
+	}{
+		{
+			name:         "allowed exit code succeeds without a retry",
+			action:       v1alpha1.ZarfComponentAction{Cmd: "exit 2", AllowedExitCodes: []int{0, 2}},
+			expectedRuns: 1,
+		},
+		{
+			name:         "allowed exit codes of the defaults are used",
+			defaults:     v1alpha1.ZarfComponentActionDefaults{AllowedExitCodes: []int{0, 2}},
+			action:       v1alpha1.ZarfComponentAction{Cmd: "exit 2"},
+			expectedRuns: 1,
+		},
+		{
+			name:          "other exit codes are retried",
+			action:        v1alpha1.ZarfComponentAction{Cmd: "exit 3", AllowedExitCodes: []int{0, 2}},
+			expectedRuns:  3,
+			expectedError: "exit status 3",
+		},
+		{
+			name:          "non-zero exit codes fail by default",
+			action:        v1alpha1.ZarfComponentAction{Cmd: "exit 2"},
+			expectedRuns:  3,
+			expectedError: "exit status 2",
+		},
+		{
+			name:          "exit code 0 fails when it is not allowed",
+			action:        v1alpha1.ZarfComponentAction{Cmd: "exit 0", AllowedExitCodes: []int{2}},
+			expectedRuns:  3,
+			expectedError: "exit code 0 is not one of the allowed exit codes [2]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			runs := filepath.Join(t.TempDir(), "runs")
+			action := tt.action
+			action.Cmd = fmt.Sprintf("echo run >> %s; %s", runs, action.Cmd)
+			action.MaxRetries = &retries
+			err := Run(context.Background(), "", tt.defaults, []v1alpha1.ZarfComponentAction{action}, variables.New("zarf", nil, nil), nil)
+			if tt.expectedError != "" {
+				require.ErrorContains(t, err, tt.expectedError)
+			} else {
+				require.NoError(t, err)
+			}
+			b, err := os.ReadFile(runs)
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedRuns, strings.Count(string(b), "run"))
+		})
+	}
+}
+
+func Test_ResolveActionDoesNotModifyDefaults(t *testing.T) {
+	t.Parallel()
+
+	env := make([]string, 1, 4)
+	env[0] = "DEFAULT=true"
+	defaults := v1alpha1.ZarfComponentActionDefaults{Env: env}
+
+	first := ResolveAction(defaults, v1alpha1.ZarfComponentAction{Env: []string{"FIRST=true"}})
+	second := ResolveAction(defaults, v1alpha1.ZarfComponentAction{Env: []string{"SECOND=true"}})
+
+	require.Equal(t, []string{"DEFAULT=true", "FIRST=true"}, first.Env)
+	require.Equal(t, []string{"DEFAULT=true", "SECOND=true"}, second.Env)
+	require.Equal(t, []string{"DEFAULT=true"}, defaults.Env)
+}
+
+func Test_resolveActionDir(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(workspace, "build"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, "file"), []byte("not a dir"), 0o644))
+
+	vc := variables.New("zarf", nil, nil)
+	vc.SetVariable("WORKSPACE", workspace, false, false, v1alpha1.RawVariableType)
+	templates := vc.GetAllTemplates()
+
+	tests := []struct {
+		name          string
+		basePath      string
+		dir           string
+		expected      string
+		expectedError string
+	}{
+		{
+			name:     "empty dir",
+			expected: "",
+		},
+		{
+			name:     "relative dir",
+			basePath: workspace,
+			dir:      "build",
+			expected: filepath.Join(workspace, "build"),
+		},
+		{
+			name:     "templated dir",
+			basePath: "/package",
+			dir:      "###ZARF_VAR_WORKSPACE###/build",
+			expected: filepath.Join(workspace, "build"),
+		},
+		{
+			name:     "env style templated dir",
+			basePath: "/package",
+			dir:      "${ZARF_VAR_WORKSPACE}/build",
+			expected: filepath.Join(workspace, "build"),
+		},
+		{
+			name:          "unresolved placeholder",
+			dir:           "###ZARF_VAR_MISSING###/build",
+			expectedError: `dir "###ZARF_VAR_MISSING###/build" contains the unresolved variable ###ZARF_VAR_MISSING###`,
+		},
+		{
+			name:          "unresolved env style placeholder",
+			dir:           "${ZARF_VAR_MISSING}/build",
+			expectedError: `dir "${ZARF_VAR_MISSING}/build" contains the unresolved variable ${ZARF_VAR_MISSING}`,
+		},
+		{
+			name:          "missing dir",
+			dir:           "###ZARF_VAR_WORKSPACE###/missing",
+			expectedError: fmt.Sprintf("dir %q is not accessible", filepath.Join(workspace, "missing")),
+		},
+		{
+			name:          "not a directory",
+			dir:           "###ZARF_VAR_WORKSPACE###/file",
+			expectedError: fmt.Sprintf("dir %q is not a directory", filepath.Join(workspace, "file")),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			dir, err := resolveActionDir(context.Background(), tt.basePath, tt.dir, v1alpha1.ZarfComponentAction{}, templates, template.Objects{})
+			if tt.expectedError != "" {
+				require.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, dir)
+		})
+	}
+}
+
+func Test_RunTemplatedDefaultsDir(t *testing.T) {
+	t.Parallel()
+
+	workspace := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, "marker"), []byte("found"), 0o644))
+
+	vc := variables.New("zarf", nil, nil)
+	vc.SetVariable("WORKSPACE", workspace, false, false, v1alpha1.RawVariableType)
+	defaults := v1alpha1.ZarfComponentActionDefaults{Dir: "###ZARF_VAR_WORKSPACE###"}
+	action := v1alpha1.ZarfComponentAction{
+		Cmd:          "cat marker",
+		SetVariables: []v1alpha1.Variable{{Name: "MARKER"}},
+	}
+	err := Run(context.Background(), "", defaults, []v1alpha1.ZarfComponentAction{action}, vc, nil)
+	require.NoError(t, err)
+	marker, ok := vc.GetSetVariable("MARKER")
+	require.True(t, ok)
+	require.Equal(t, "found", marker.Value)
+
+	defaults.Dir = "###ZARF_VAR_UNSET###"
+	err = Run(context.Background(), "", defaults, []v1alpha1.ZarfComponentAction{action}, vc, nil)
+	require.ErrorContains(t, err, "contains the unresolved variable ###ZARF_VAR_UNSET###")
+}
+
+func Test_RunComponentEnvPrecedence(t *testing.T) {
+	t.Parallel()
+
+	vc := variables.New("zarf", nil, nil)
+	vc.SetVariable("REGION", "us-east-1", false, false, v1alpha1.RawVariableType)
+	// Component env file entries are recorded first in the action defaults when the package is created
+	defaults := v1alpha1.ZarfComponentActionDefaults{
+		Env: []string{"FROM_FILE=file", "REGION=###ZARF_VAR_REGION###", "SHARED=file", "OVERRIDDEN=file", "SHARED=defaults"},
+	}
+	action := v1alpha1.ZarfComponentAction{
+		Cmd:          "echo \"$FROM_FILE $REGION $SHARED $OVERRIDDEN\"",
+		Env:          []string{"OVERRIDDEN=action"},
+		SetVariables: []v1alpha1.Variable{{Name: "OUTPUT"}},
+	}
+	err := Run(context.Background(), "", defaults, []v1alpha1.ZarfComponentAction{action}, vc, nil)
+	require.NoError(t, err)
+	output, ok := vc.GetSetVariable("OUTPUT")
+	require.True(t, ok)
+	require.Equal(t, "file us-east-1 defaults action", output.Value)
+}
+
+func Test_RunActionEnvFile(t *testing.T) {
+	t.Parallel()
+
+	t.Run("merges the env file under the action env", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		envFile := "FROM_FILE=file\nREGION=###ZARF_VAR_REGION###\nSHARED=file\nOVERRIDDEN=file\n"
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "action.env"), []byte(envFile), 0o600))
+		vc := variables.New("zarf", nil, nil)
+		vc.SetVariable("REGION", "us-east-1", false, false, v1alpha1.RawVariableType)
+		defaults := v1alpha1.ZarfComponentActionDefaults{Env: []string{"SHARED=defaults"}}
+		action := v1alpha1.ZarfComponentAction{
+			Cmd:          "echo \"$FROM_FILE $REGION $SHARED $OVERRIDDEN\"",
+			Dir:          &dir,
+			Env:          []string{"OVERRIDDEN=action"},
+			EnvFile:      "action.env",
+			SetVariables: []v1alpha1.Variable{{Name: "OUTPUT"}},
+		}
+		err := Run(context.Background(), "", defaults, []v1alpha1.ZarfComponentAction{action}, vc, nil)
+		require.NoError(t, err)
+		output, ok := vc.GetSetVariable("OUTPUT")
+		require.True(t, ok)
+		require.Equal(t, "file us-east-1 file action", output.Value)
+		require.Equal(t, []string{"SHARED=defaults"}, defaults.Env)
+	})
+
+	t.Run("missing env file fails with its path", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		vc := variables.New("zarf", nil, nil)
+		action := v1alpha1.ZarfComponentAction{
+			Cmd:     "echo hello",
+			Dir:     &dir,
+			EnvFile: "missing.env",
+		}
+		err := Run(context.Background(), "", v1alpha1.ZarfComponentActionDefaults{}, []v1alpha1.ZarfComponentAction{action}, vc, nil)
+		require.ErrorContains(t, err, fmt.Sprintf("unable to resolve the env file for echo hello: unable to read env file %q", filepath.Join(dir, "missing.env")))
+	})
+}
+
+func Test_TagFilter(t *testing.T) {
+	t.Parallel()
+
+	actions := []v1alpha1.ZarfComponentAction{
+		{Cmd: "echo migrate", Tags: []string{"migration"}},
+		{Cmd: "echo seed", Tags: []string{"seed", "data"}},
+		{Cmd: "echo untagged"},
+	}
+	cmds := func(actions []v1alpha1.ZarfComponentAction) []string {
+		out := []string{}
+		for _, a := range actions {
+			out = append(out, a.Cmd)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name     string
+		filter   TagFilter
+		expected []string
+	}{
+		{
+			name:     "no filter runs every action",
+			filter:   TagFilter{},
+			expected: []string{"echo migrate", "echo seed", "echo untagged"},
+		},
+		{
+			name:     "untagged actions are skipped when filtering",
+			filter:   TagFilter{Tags: []string{"migration"}},
+			expected: []string{"echo migrate"},
+		},
+		{
+			name:     "any matching tag selects the action",
+			filter:   TagFilter{Tags: []string{"data", "migration"}},
+			expected: []string{"echo migrate", "echo seed"},
+		},
+		{
+			name:     "untagged actions run when included",
+			filter:   TagFilter{Tags: []string{"seed"}, IncludeUntagged: true},
+			expected: []string{"echo seed", "echo untagged"},
+		},
+		{
+			name:     "no matching tag",
+			filter:   TagFilter{Tags: []string{"backup"}},
+			expected: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.expected, cmds(tt.filter.Filter(context.Background(), actions)))
+		})
+	}
+}
+
+func Test_RunWaitVariable(t *testing.T) {
+	t.Parallel()
+
+	waitAction := func(value string, maxTotalSeconds int) v1alpha1.ZarfComponentAction {
+		return v1alpha1.ZarfComponentAction{
+			MaxTotalSeconds: &maxTotalSeconds,
+			Wait: &v1alpha1.ZarfComponentActionWait{
+				Variable: &v1alpha1.ZarfComponentActionWaitVariable{Name: "DB_READY", Value: value},
+			},
+		}
+	}
+	producer := v1alpha1.ZarfComponentAction{
+		Cmd:          "echo true",
+		SetVariables: []v1alpha1.Variable{{Name: "DB_READY"}},
+	}
+
+	t.Run("consumer unblocks after the producer sets the variable", func(t *testing.T) {
+		t.Parallel()
+		vc := variables.New("zarf", nil, nil)
+		errCh := make(chan error, 1)
+		go func() {
+			time.Sleep(500 * time.Millisecond)
+			errCh <- Run(context.Background(), "", v1alpha1.ZarfComponentActionDefaults{}, []v1alpha1.ZarfComponentAction{producer}, vc, nil)
+		}()
+		err := Run(context.Background(), "", v1alpha1.ZarfComponentActionDefaults{}, []v1alpha1.ZarfComponentAction{waitAction("true", 30)}, vc, nil)
+		require.NoError(t, err)
+		require.NoError(t, <-errCh)
+	})
+
+	t.Run("variable already set", func(t *testing.T) {
+		t.Parallel()
+		vc := variables.New("zarf", nil, nil)
+		vc.SetVariable("DB_READY", "yes", false, false, v1alpha1.RawVariableType)
+		err := Run(context.Background(), "", v1alpha1.ZarfComponentActionDefaults{}, []v1alpha1.ZarfComponentAction{waitAction("", 1)}, vc, nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("times out when the variable is never set", func(t *testing.T) {
+		t.Parallel()
+		vc := variables.New("zarf", nil, nil)
+		// A declared variable without a default is empty and does not satisfy the wait
+		vc.SetVariable("DB_READY", "", false, false, v1alpha1.RawVariableType)
+		err := Run(context.Background(), "", v1alpha1.ZarfComponentActionDefaults{}, []v1alpha1.ZarfComponentAction{waitAction("", 1)}, vc, nil)
+		require.EqualError(t, err, "timed out after 1s waiting for variable DB_READY")
+	})
+
+	t.Run("times out when the variable has a different value", func(t *testing.T) {
+		t.Parallel()
+		vc := variables.New("zarf", nil, nil)
+		vc.SetVariable("DB_READY", "false", false, false, v1alpha1.RawVariableType)
+		err := Run(context.Background(), "", v1alpha1.ZarfComponentActionDefaults{}, []v1alpha1.ZarfComponentAction{waitAction("true", 1)}, vc, nil)
+		require.EqualError(t, err, "timed out after 1s waiting for variable DB_READY")
+	})
+}
+
+func Test_RunWaitNetworkHeaders(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	vc := variables.New("zarf", nil, nil)
+	vc.SetVariable("TOKEN", "secret", true, false, v1alpha1.RawVariableType)
+	maxTotalSeconds := 5
+	action := v1alpha1.ZarfComponentAction{
+		MaxTotalSeconds: &maxTotalSeconds,
+		Wait: &v1alpha1.ZarfComponentActionWait{
+			Network: &v1alpha1.ZarfComponentActionWaitNetwork{
+				Protocol: "http",
+				Address:  strings.TrimPrefix(server.URL, "http://"),
+				Method:   http.MethodPost,
+				Headers:  map[string]string{"Authorization": "Bearer ###ZARF_VAR_TOKEN###"},
+			},
+		},
+	}
+	err := Run(context.Background(), "", v1alpha1.ZarfComponentActionDefaults{}, []v1alpha1.ZarfComponentAction{action}, vc, nil)
+	require.NoError(t, err)
+	// The templated header is not written back to the package
+	require.Equal(t, "Bearer ###ZARF_VAR_TOKEN###", action.Wait.Network.Headers["Authorization"])
+}
+
+func Test_runWaitClusterConditions(t *testing.T) {
+	t.Parallel()
+
+	conditions := []v1alpha1.ZarfComponentActionWaitCluster{
+		{Kind: "StatefulSet", Name: "db", Namespace: "data", Condition: "Ready"},
+		{Kind: "PersistentVolumeClaim", Name: "app=db", Namespace: "data", Condition: "{.status.phase}=Bound"},
+		{Kind: "Job", Name: "migrate", Namespace: "data", Condition: "Complete"},
+	}
+
+	t.Run("waits for all conditions concurrently", func(t *testing.T) {
+		t.Parallel()
+		var started sync.WaitGroup
+		started.Add(len(conditions))
+		var mu sync.Mutex
+		waited := []string{}
+		waitFor := func(ctx context.Context, kind, _, _, _ string, _ time.Duration, _ wait.ResourceOptions) error {
+			// Every condition must be waited on at the same time for all of them to get past this point
+			started.Done()
+			started.Wait()
+			mu.Lock()
+			defer mu.Unlock()
+			waited = append(waited, kind)
+			return ctx.Err()
+		}
+		err := runWaitClusterConditions(context.Background(), conditions, 5*time.Second, waitFor)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"StatefulSet", "PersistentVolumeClaim", "Job"}, waited)
+	})
+
+	t.Run("a failed condition stops the others", func(t *testing.T) {
+		t.Parallel()
+		waitFor := func(ctx context.Context, kind, _, _, _ string, _ time.Duration, _ wait.ResourceOptions) error {
+			if kind == "Job" {
+				return errors.New("job failed")
+			}
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		err := runWaitClusterConditions(context.Background(), conditions, time.Minute, waitFor)
+		require.EqualError(t, err, "wait for Job/migrate to be Complete: job failed")
+	})
+
+	t.Run("conditions share the timeout", func(t *testing.T) {
+		t.Parallel()
+		waitFor := func(ctx context.Context, _, _, _, _ string, _ time.Duration, _ wait.ResourceOptions) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		start := time.Now()
+		err := runWaitClusterConditions(context.Background(), conditions, 100*time.Millisecond, waitFor)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Less(t, time.Since(start), 5*time.Second)
+	})
+
+	t.Run("min ready is passed to the wait", func(t *testing.T) {
+		t.Parallel()
+		quorum := []v1alpha1.ZarfComponentActionWaitCluster{
+			{Kind: "Pod", Name: "app=etcd", Namespace: "data", Condition: "{.status.phase}=Running", MinReady: 2},
+		}
+		waitFor := func(_ context.Context, _, _, _, _ string, _ time.Duration, opts wait.ResourceOptions) error {
+			if opts.MinReady != 2 {
+				return fmt.Errorf("unexpected min ready %d", opts.MinReady)
+			}
+			return errors.New("not enough pods")
+		}
+		err := runWaitClusterConditions(context.Background(), quorum, time.Minute, waitFor)
+		require.EqualError(t, err, "wait for Pod/app=etcd to be {.status.phase}=Running (at least 2): not enough pods")
+	})
+}
+
+func Test_setVariablesFromJSON(t *testing.T) {
+	t.Parallel()
+
+	output := `{
+  "metadata": {"name": "ingress", "labels": {"app": "ingress"}},
+  "spec": {"ports": [{"port": 80}, {"port": 443}], "clusterIP": null},
+  "status": {"loadBalancer": {"ingress": [{"ip": "10.0.0.5"}]}}
+}`
+
+	tests := []struct {
+		name          string
+		variables     []v1alpha1.ZarfComponentActionJSONVariable
+		output        string
+		expected      map[string]string
+		expectedError string
+	}{
+		{
+			name: "extracts multiple values",
+			variables: []v1alpha1.ZarfComponentActionJSONVariable{
+				{Variable: v1alpha1.Variable{Name: "NAME"}, Path: ".metadata.name"},
+				{Variable: v1alpha1.Variable{Name: "IP"}, Path: "{.status.loadBalancer.ingress[0].ip}"},
+				{Variable: v1alpha1.Variable{Name: "PORT"}, Path: ".spec.ports[1].port"},
+				{Variable: v1alpha1.Variable{Name: "PORTS"}, Path: ".spec.ports[*].port"},
+				{Variable: v1alpha1.Variable{Name: "LABELS"}, Path: ".metadata.labels"},
+				{Variable: v1alpha1.Variable{Name: "CLUSTER_IP"}, Path: ".spec.clusterIP"},
+			},
+			output: output,
+			expected: map[string]string{
+				"NAME":       "ingress",
+				"IP":         "10.0.0.5",
+				"PORT":       "443",
+				"PORTS":      "[80,443]",
+				"LABELS":     `{"app":"ingress"}`,
+				"CLUSTER_IP": "",
+			},
+		},
+		{
+			name: "optional missing path is set to an empty value",
+			variables: []v1alpha1.ZarfComponentActionJSONVariable{
+				{Variable: v1alpha1.Variable{Name: "HOSTNAME"}, Path: ".status.loadBalancer.ingress[0].hostname", Optional: true},
+			},
+			output:   output,
+			expected: map[string]string{"HOSTNAME": ""},
+		},
+		{
+			name: "missing path fails",
+			variables: []v1alpha1.ZarfComponentActionJSONVariable{
+				{Variable: v1alpha1.Variable{Name: "NAME"}, Path: ".metadata.name"},
+				{Variable: v1alpha1.Variable{Name: "HOSTNAME"}, Path: ".status.loadBalancer.ingress[0].hostname"},
+			},
+			output:        output,
+			expectedError: "unable to set variable HOSTNAME from path .status.loadBalancer.ingress[0].hostname: hostname is not found",
+		},
+		{
+			name: "index out of range fails",
+			variables: []v1alpha1.ZarfComponentActionJSONVariable{
+				{Variable: v1alpha1.Variable{Name: "IP"}, Path: ".status.loadBalancer.ingress[1].ip"},
+			},
+			output:        output,
+			expectedError: "unable to set variable IP from path .status.loadBalancer.ingress[1].ip: array index out of bounds: index 1, length 1",
+		},
+		{
+			name: "pattern mismatch fails",
+			variables: []v1alpha1.ZarfComponentActionJSONVariable{
+				{Variable: v1alpha1.Variable{Name: "NAME", Pattern: "^[0-9]+$"}, Path: ".metadata.name"},
+			},
+			output:        output,
+			expectedError: "provided value for variable \"NAME\" does not match pattern \"^[0-9]+$\"",
+		},
+		{
+			name: "large integers keep their precision",
+			variables: []v1alpha1.ZarfComponentActionJSONVariable{
+				{Variable: v1alpha1.Variable{Name: "ID"}, Path: ".id"},
+				{Variable: v1alpha1.Variable{Name: "IDS"}, Path: ".ids"},
+				{Variable: v1alpha1.Variable{Name: "RATIO"}, Path: ".ratio"},
+			},
+			output: `{"id": 9007199254740993, "ids": [12345678901234567890], "ratio": 0.5}`,
+			expected: map[string]string{
+				"ID":    "9007199254740993",
+				"IDS":   "[12345678901234567890]",
+				"RATIO": "0.5",
+			},
+		},
+		{
+			name: "trailing data after the JSON fails",
+			variables: []v1alpha1.ZarfComponentActionJSONVariable{
+				{Variable: v1alpha1.Variable{Name: "NAME"}, Path: ".metadata.name"},
+			},
+			output:        output + " {}",
+			expectedError: "unable to parse the output of the command as JSON",
+		},
+		{
+			name: "output that is not JSON fails",
+			variables: []v1alpha1.ZarfComponentActionJSONVariable{
+				{Variable: v1alpha1.Variable{Name: "NAME"}, Path: ".metadata.name"},
+			},
+			output:        "ingress",
+			expectedError: "unable to parse the output of the command as JSON",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			vc := variables.New("zarf", nil, nil)
+			err := setVariablesFromJSON(tt.output, tt.variables, vc)
+			if tt.expectedError != "" {
+				require.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			for name, expected := range tt.expected {
+				v, ok := vc.GetSetVariable(name)
+				require.True(t, ok, name)
+				require.Equal(t, expected, v.Value, name)
+			}
+		})
+	}
+}
+
+func Test_setVariables(t *testing.T) {
+	t.Parallel()
+
+	output := "created user admin\npassword: s3cr3t-Pa55\nuid: 7f3c9a"
+
+	tests := []struct {
+		name          string
+		variables     []v1alpha1.Variable
+		expected      map[string]string
+		expectedError string
+	}{
+		{
+			name:      "sets the whole output without a pattern",
+			variables: []v1alpha1.Variable{{Name: "OUTPUT"}},
+			expected:  map[string]string{"OUTPUT": output},
+		},
+		{
+			name: "extracts the group named value",
+			variables: []v1alpha1.Variable{
+				{Name: "PASSWORD", Pattern: `password: (?P<value>\S+)`},
+				{Name: "UID", Pattern: `(uid): (?P<value>[0-9a-f]+)(.*)`},
+			},
+			expected: map[string]string{"PASSWORD": "s3cr3t-Pa55", "UID": "7f3c9a"},
+		},
+		{
+			name:      "pattern without a group named value validates the output",
+			variables: []v1alpha1.Variable{{Name: "OUTPUT", Pattern: "^created"}},
+			expected:  map[string]string{"OUTPUT": output},
+		},
+		{
+			name:      "pattern with unnamed capture groups validates the output",
+			variables: []v1alpha1.Variable{{Name: "OUTPUT", Pattern: `^(created|updated) user (\w+)`}},
+			expected:  map[string]string{"OUTPUT": output},
+		},
+		{
+			name:          "pattern without a group named value that does not match fails",
+			variables:     []v1alpha1.Variable{{Name: "OUTPUT", Pattern: "^(deleted)"}},
+			expectedError: "provided value for variable \"OUTPUT\" does not match pattern \"^(deleted)\"",
+		},
+		{
+			name:          "value pattern that does not match fails with the output",
+			variables:     []v1alpha1.Variable{{Name: "TOKEN", Pattern: `token: (?P<value>\S+)`}},
+			expectedError: `pattern "token: (?P<value>\\S+)" for variable TOKEN did not match the output of the command: "created user admin\npassword: s3cr3t-Pa55\nuid: 7f3c9a"`,
+		},
+		{
+			name:          "value pattern that does not match a sensitive variable omits the output",
+			variables:     []v1alpha1.Variable{{Name: "TOKEN", Pattern: `token: (?P<value>\S+)`, Sensitive: true}},
+			expectedError: `pattern "token: (?P<value>\\S+)" for variable TOKEN did not match the output of the command`,
+		},
+		{
+			name:          "invalid pattern fails",
+			variables:     []v1alpha1.Variable{{Name: "TOKEN", Pattern: `token: (`}},
+			expectedError: `invalid pattern "token: (" for variable TOKEN`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			vc := variables.New("zarf", nil, nil)
+			err := setVariables(output, tt.variables, vc)
+			if tt.expectedError != "" {
+				require.ErrorContains(t, err, tt.expectedError)
+				if tt.variables[0].Sensitive {
+					require.NotContains(t, err.Error(), "s3cr3t")
+				}
+				return
+			}
+			require.NoError(t, err)
+			for name, expected := range tt.expected {
+				v, ok := vc.GetSetVariable(name)
+				require.True(t, ok, name)
+				require.Equal(t, expected, v.Value, name)
+			}
+		})
+	}
+}
+
+func Test_RunSetVariablesFromJSON(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sets the variables from the command output", func(t *testing.T) {
+		t.Parallel()
+		vc := variables.New("zarf", nil, nil)
+		action := v1alpha1.ZarfComponentAction{
+			Cmd: `echo '{"metadata": {"name": "ingress"}, "data": {"token": "s3cr3t"}}'`,
+			SetVariablesFromJSON: []v1alpha1.ZarfComponentActionJSONVariable{
+				{Variable: v1alpha1.Variable{Name: "NAME"}, Path: ".metadata.name"},
+				{Variable: v1alpha1.Variable{Name: "TOKEN", Sensitive: true}, Path: ".data.token"},
+			},
+		}
+		err := Run(context.Background(), "", v1alpha1.ZarfComponentActionDefaults{}, []v1alpha1.ZarfComponentAction{action}, vc, nil)
+		require.NoError(t, err)
+		name, ok := vc.GetSetVariable("NAME")
+		require.True(t, ok)
+		require.Equal(t, "ingress", name.Value)
+		token, ok := vc.GetSetVariable("TOKEN")
+		require.True(t, ok)
+		require.Equal(t, "s3cr3t", token.Value)
+		require.True(t, token.Sensitive)
+	})
+
+	t.Run("missing path fails the action", func(t *testing.T) {
+		t.Parallel()
+		vc := variables.New("zarf", nil, nil)
+		action := v1alpha1.ZarfComponentAction{
+			Cmd: `echo '{"metadata": {"name": "ingress"}}'`,
+			SetVariablesFromJSON: []v1alpha1.ZarfComponentActionJSONVariable{
+				{Variable: v1alpha1.Variable{Name: "IP"}, Path: ".status.loadBalancer.ingress[0].ip"},
+			},
+		}
+		err := Run(context.Background(), "", v1alpha1.ZarfComponentActionDefaults{}, []v1alpha1.ZarfComponentAction{action}, vc, nil)
+		require.ErrorContains(t, err, "failed after 0 retries: unable to set variable IP from path .status.loadBalancer.ingress[0].ip: status is not found")
+		_, ok := vc.GetSetVariable("IP")
+		require.False(t, ok)
+	})
+}
+
+func Test_lineLogWriter(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	w := &lineLogWriter{logger: slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey || a.Key == slog.LevelKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))}
+	_, err := w.Write([]byte("first li"))
+	require.NoError(t, err)
+	require.Empty(t, buf.String())
+	_, err = w.Write([]byte("ne\r\n\nsecond line\nlast"))
+	require.NoError(t, err)
+	require.Equal(t, "msg=\"first line\"\nmsg=\"second line\"\n", buf.String())
+	w.Flush()
+	require.Equal(t, "msg=\"first line\"\nmsg=\"second line\"\nmsg=last\n", buf.String())
+}
+
+func Test_RunStreamsOutput(t *testing.T) {
+	t.Parallel()
+
+	newCtx := func(buf *bytes.Buffer) context.Context {
+		l := slog.New(slog.NewJSONHandler(buf, nil))
+		return WithComponent(logger.WithContext(context.Background(), l), "app")
+	}
+	outputLines := func(buf *bytes.Buffer) []map[string]any {
+		lines := []map[string]any{}
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			var record map[string]any
+			require.NoError(t, json.Unmarshal([]byte(line), &record))
+			if _, ok := record["stream"]; ok {
+				lines = append(lines, record)
+			}
+		}
+		return lines
+	}
+
+	t.Run("output is streamed with the component and action", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		vc := variables.New("zarf", nil, nil)
+		action := v1alpha1.ZarfComponentAction{
+			Cmd:          "echo one; echo two; echo oops >&2",
+			Description:  "count",
+			SetVariables: []v1alpha1.Variable{{Name: "OUTPUT"}},
+		}
+		err := Run(newCtx(&buf), "", v1alpha1.ZarfComponentActionDefaults{}, []v1alpha1.ZarfComponentAction{action}, vc, nil)
+		require.NoError(t, err)
+		output, ok := vc.GetSetVariable("OUTPUT")
+		require.True(t, ok)
+		require.Equal(t, "one\ntwo", output.Value)
+
+		lines := outputLines(&buf)
+		require.Len(t, lines, 3)
+		streamed := map[string]string{}
+		for _, line := range lines {
+			require.Equal(t, "app", line["component"])
+			require.Equal(t, "count", line["action"])
+			require.Equal(t, "INFO", line["level"])
+			streamed[line["msg"].(string)] = line["stream"].(string)
+		}
+		require.Equal(t, map[string]string{"one": "stdout", "two": "stdout", "oops": "stderr"}, streamed)
+	})
+
+	t.Run("muted output is not streamed", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		vc := variables.New("zarf", nil, nil)
+		action := v1alpha1.ZarfComponentAction{
+			Cmd:          "echo s3cr3t",
+			Description:  "token",
+			Mute:         helpers.BoolPtr(true),
+			SetVariables: []v1alpha1.Variable{{Name: "OUTPUT", Sensitive: true}},
+		}
+		err := Run(newCtx(&buf), "", v1alpha1.ZarfComponentActionDefaults{}, []v1alpha1.ZarfComponentAction{action}, vc, nil)
+		require.NoError(t, err)
+		output, ok := vc.GetSetVariable("OUTPUT")
+		require.True(t, ok)
+		require.Equal(t, "s3cr3t", output.Value)
+		require.Empty(t, outputLines(&buf))
+		require.NotContains(t, buf.String(), "s3cr3t")
+	})
+}
+
+func Test_RunActionError(t *testing.T) {
+	t.Parallel()
+
+	zero := 0
+	one := 1
+	tests := []struct {
+		name          string
+		ctx           func() context.Context
+		action        v1alpha1.ZarfComponentAction
+		expected      ActionError
+		expectedError string
+	}{
+		{
+			name:   "command failure",
+			ctx:    func() context.Context { return WithComponent(context.Background(), "app") },
+			action: v1alpha1.ZarfComponentAction{Cmd: "echo out; echo err >&2; exit 3", Description: "fail", MaxRetries: &one},
+			expected: ActionError{
+				Component: "app",
+				Action:    "fail",
+				ExitCode:  3,
+				Stdout:    "out\n",
+				Stderr:    "err\n",
+				Retries:   1,
+			},
+			expectedError: `command "fail" failed after 1 retries: exit status 3`,
+		},
+		{
+			name:   "muted output is not kept",
+			ctx:    context.Background,
+			action: v1alpha1.ZarfComponentAction{Cmd: "echo s3cr3t; exit 1", Description: "token", Mute: helpers.BoolPtr(true), MaxRetries: &zero},
+			expected: ActionError{
+				Action:   "token",
+				ExitCode: 1,
+			},
+			expectedError: `command "token" failed after 0 retries: exit status 1`,
+		},
+		{
+			name:   "timeout",
+			ctx:    context.Background,
+			action: v1alpha1.ZarfComponentAction{Cmd: "sleep 5", Description: "slow", MaxTotalSeconds: &one, MaxRetries: &zero},
+			expected: ActionError{
+				Action:   "slow",
+				ExitCode: -1,
+				Timeout:  time.Second,
+			},
+			expectedError: `command "slow" timed out after 1 seconds`,
+		},
+		{
+			name: "cancelled",
+			ctx: func() context.Context {
+				ctx, cancel := context.WithCancelCause(context.Background())
+				cancel(errors.New("deploy timed out"))
+				return ctx
+			},
+			action: v1alpha1.ZarfComponentAction{Cmd: "true", Description: "cancelled"},
+			expected: ActionError{
+				Action:    "cancelled",
+				ExitCode:  -1,
+				Cancelled: true,
+			},
+			expectedError: `command "cancelled" was cancelled: deploy timed out`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := Run(tt.ctx(), "", v1alpha1.ZarfComponentActionDefaults{}, []v1alpha1.ZarfComponentAction{tt.action}, variables.New("zarf", nil, nil), nil)
+			require.EqualError(t, err, tt.expectedError)
+			var actionErr *ActionError
+			require.ErrorAs(t, err, &actionErr)
+			require.Error(t, actionErr.Err)
+			actionErr.Err = nil
+			require.Equal(t, tt.expected, *actionErr)
+			require.Equal(t, tt.expected.Timeout > 0 || tt.expected.Cancelled, actionErr.TimedOut())
+		})
+	}
+}
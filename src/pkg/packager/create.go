@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"slices"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/defenseunicorns/pkg/oci"
@@ -16,6 +17,7 @@ import (
 	"github.com/zarf-dev/zarf/src/pkg/logger"
 	"github.com/zarf-dev/zarf/src/pkg/packager/layout"
 	"github.com/zarf-dev/zarf/src/pkg/packager/load"
+	"github.com/zarf-dev/zarf/src/pkg/transform"
 	"github.com/zarf-dev/zarf/src/pkg/utils"
 	"github.com/zarf-dev/zarf/src/pkg/zoci"
 	"github.com/zarf-dev/zarf/src/types"
@@ -23,8 +25,11 @@ import (
 
 // CreateOptions are the optional parameters to create
 type CreateOptions struct {
-	Flavor                  string
-	RegistryOverrides       []images.RegistryOverride
+	Flavor            string
+	RegistryOverrides []images.RegistryOverride
+	// RegistryPolicy restricts the registries the images in the package may come from, including the images found in
+	// the charts and manifests of the components
+	RegistryPolicy          images.RegistryPolicy
 	SigningKeyPath          string
 	SigningKeyPassword      string
 	SetVariables            map[string]string
@@ -35,12 +40,28 @@ type CreateOptions struct {
 	OCIConcurrency          int
 	CachePath               string
 	WithBuildMachineInfo    bool
+	// PinDigests resolves every image referenced only by tag to its digest and stores the pinned reference in the package
+	PinDigests bool
+	// AllFlavors includes the components of every flavor instead of only the components of Flavor, so that the
+	// flavor is chosen on deploy
+	AllFlavors bool
+	// AllArchitectures includes the components of every cluster architecture instead of only the components of the
+	// package architecture, so that deploy selects them by the architectures of the cluster nodes
+	AllArchitectures bool
+	// NoCache rebuilds every component instead of reusing the component tarballs cached by previous builds
+	NoCache bool
+	// NoImportCache fetches every OCI import again instead of reusing the imports cached by previous builds
+	NoImportCache bool
+	// ImageConcurrency is the amount of images pulled in parallel
+	ImageConcurrency int
 	// applicable when output is an OCI registry
 	types.RemoteOptions
 	// IsInteractive decides if Zarf can interactively prompt users through the CLI
 	IsInteractive bool
 	// SkipVersionCheck skips version requirement validation
 	SkipVersionCheck bool
+	// DefinitionHooks transform the package definition before it is validated and created
+	DefinitionHooks []load.DefinitionHook
 }
 
 // Create takes a path to a directory containing a ZarfPackageConfig and returns the path to the created package
@@ -56,11 +77,15 @@ func Create(ctx context.Context, packagePath string, output string, opts CreateO
 
 	loadOpts := load.DefinitionOptions{
 		Flavor:             opts.Flavor,
+		AllFlavors:         opts.AllFlavors,
+		AllArchitectures:   opts.AllArchitectures,
 		SetVariables:       opts.SetVariables,
 		CachePath:          opts.CachePath,
 		IsInteractive:      opts.IsInteractive,
 		SkipRequiredValues: true,
 		SkipVersionCheck:   opts.SkipVersionCheck,
+		NoImportCache:      opts.NoImportCache,
+		Hooks:              opts.DefinitionHooks,
 		RemoteOptions:      opts.RemoteOptions,
 	}
 	pkg, err := load.PackageDefinition(ctx, packagePath, loadOpts)
@@ -73,6 +98,25 @@ func Create(ctx context.Context, packagePath string, output string, opts CreateO
 		return "", fmt.Errorf("unable to access package path %q: %w", packagePath, err)
 	}
 
+	if err := validateRegistryPolicy(ctx, pkg, pkgPath.BaseDir, opts); err != nil {
+		return "", err
+	}
+
+	if opts.PinDigests {
+		scans, err := findImagesInPackage(ctx, pkg, pkgPath.BaseDir, FindImagesOptions{
+			CachePath:     opts.CachePath,
+			SkipCosign:    true,
+			RemoteOptions: opts.RemoteOptions,
+		}, false)
+		if err != nil {
+			return "", fmt.Errorf("unable to find the images of the package to pin: %w", err)
+		}
+		pkg.Components, err = addScannedImages(ctx, pkg.Components, scans)
+		if err != nil {
+			return "", err
+		}
+	}
+
 	var differentialPkg v1alpha1.ZarfPackage
 	if opts.DifferentialPackagePath != "" {
 		pkgLayout, err := LoadPackage(ctx, opts.DifferentialPackagePath, LoadOptions{
@@ -101,6 +145,9 @@ func Create(ctx context.Context, packagePath string, output string, opts CreateO
 		SigningKeyPassword:   opts.SigningKeyPassword,
 		CachePath:            opts.CachePath,
 		WithBuildMachineInfo: opts.WithBuildMachineInfo,
+		PinDigests:           opts.PinDigests,
+		ImageConcurrency:     opts.ImageConcurrency,
+		NoCache:              opts.NoCache,
 		RemoteOptions:        opts.RemoteOptions,
 	}
 	pkgLayout, err := layout.AssemblePackage(ctx, pkg, pkgPath.BaseDir, assembleOpt)
@@ -154,3 +201,83 @@ func Create(ctx context.Context, packagePath string, output string, opts CreateO
 	}
 	return packageLocation, nil
 }
+
+// validateRegistryPolicy checks every image of the package against the registry policy before anything is pulled. This
+// includes the images in image archives and the images found in the charts and manifests of the components.
+func validateRegistryPolicy(ctx context.Context, pkg v1alpha1.ZarfPackage, baseDir string, opts CreateOptions) error {
+	if opts.RegistryPolicy.IsEmpty() {
+		return nil
+	}
+	imgs := []string{}
+	for _, comp := range pkg.Components {
+		imgs = append(imgs, comp.Images...)
+		for _, archive := range comp.ImageArchives {
+			imgs = append(imgs, archive.Images...)
+		}
+	}
+	scans, err := findImagesInPackage(ctx, pkg, baseDir, FindImagesOptions{
+		CachePath:     opts.CachePath,
+		SkipCosign:    true,
+		RemoteOptions: opts.RemoteOptions,
+	}, false)
+	if err != nil {
+		return fmt.Errorf("unable to find the images of the package to check the registry policy: %w", err)
+	}
+	for _, scan := range scans {
+		imgs = append(imgs, scan.Matches...)
+	}
+	slices.Sort(imgs)
+	return opts.RegistryPolicy.Validate(slices.Compact(imgs))
+}
+
+// addScannedImages adds the images found in the charts and manifests of each component to the images of the component,
+// so that they are pinned to their digest and included in the package. Images the component already lists, also by a
+// pinned reference, and images in image archives are not added again.
+func addScannedImages(ctx context.Context, components []v1alpha1.ZarfComponent, scans []ComponentImageScan) ([]v1alpha1.ZarfComponent, error) {
+	archived := map[string]bool{}
+	for _, comp := range components {
+		for _, archive := range comp.ImageArchives {
+			for _, img := range archive.Images {
+				refInfo, err := transform.ParseImageRef(img)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse image ref %s in archive %s: %w", img, archive.Path, err)
+				}
+				archived[refInfo.Reference] = true
+			}
+		}
+	}
+
+	components = slices.Clone(components)
+	for _, scan := range scans {
+		idx := slices.IndexFunc(components, func(c v1alpha1.ZarfComponent) bool { return c.Name == scan.ComponentName })
+		if idx == -1 {
+			continue
+		}
+		comp := &components[idx]
+		listed := map[string]bool{}
+		for _, img := range comp.Images {
+			refInfo, err := transform.ParseImageRef(img)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse image ref %s in component %s: %w", img, comp.Name, err)
+			}
+			listed[refInfo.Reference] = true
+			if refInfo.Tag != "" {
+				listed[refInfo.Name+":"+refInfo.Tag] = true
+			}
+		}
+		comp.Images = slices.Clone(comp.Images)
+		for _, img := range scan.Matches {
+			refInfo, err := transform.ParseImageRef(img)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse image ref %s found in component %s: %w", img, comp.Name, err)
+			}
+			if listed[refInfo.Reference] || archived[refInfo.Reference] {
+				continue
+			}
+			logger.From(ctx).Debug("adding image found in the charts and manifests to pin it", "component", comp.Name, "image", img)
+			comp.Images = append(comp.Images, img)
+			listed[refInfo.Reference] = true
+		}
+	}
+	return components, nil
+}
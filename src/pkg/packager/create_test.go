@@ -4,11 +4,18 @@
 package packager
 
 import (
+	"context"
 	"fmt"
+	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/pkg/images"
+	"github.com/zarf-dev/zarf/src/pkg/packager/layout"
+	"github.com/zarf-dev/zarf/src/pkg/packager/load"
 	"github.com/zarf-dev/zarf/src/test/testutil"
 )
 
@@ -39,6 +46,197 @@ func TestPackageCreatePublishArch(t *testing.T) {
 	}
 }
 
+func TestPackageCreateMetadataLabels(t *testing.T) {
+	ctx := testutil.TestContext(t)
+
+	packagePath, err := Create(ctx, filepath.Join("testdata", "create", "metadata-labels"), t.TempDir(), CreateOptions{
+		SkipSBOM: true,
+	})
+	require.NoError(t, err)
+
+	pkgLayout, err := layout.LoadFromTar(ctx, packagePath, layout.PackageLayoutOptions{})
+	require.NoError(t, err)
+	expected := map[string]string{
+		"team":                      "platform",
+		"tier":                      "gold",
+		"catalog.zarf.dev/maturity": "stable",
+	}
+	require.Equal(t, expected, pkgLayout.Pkg.Metadata.Labels)
+}
+
+func TestPackageCreateDefinitionHook(t *testing.T) {
+	ctx := testutil.TestContext(t)
+
+	addComponent := func(_ context.Context, pkg v1alpha1.ZarfPackage) (v1alpha1.ZarfPackage, error) {
+		pkg.Components = append(pkg.Components, v1alpha1.ZarfComponent{
+			Name:     "generated",
+			Required: helpers.BoolPtr(true),
+			Files: []v1alpha1.ZarfFile{
+				{
+					Source: "generated.txt",
+					Target: "generated.txt",
+				},
+			},
+		})
+		return pkg, nil
+	}
+	packagePath, err := Create(ctx, filepath.Join("testdata", "create", "definition-hook"), t.TempDir(), CreateOptions{
+		SkipSBOM:        true,
+		DefinitionHooks: []load.DefinitionHook{addComponent},
+	})
+	require.NoError(t, err)
+
+	pkgLayout, err := layout.LoadFromTar(ctx, packagePath, layout.PackageLayoutOptions{})
+	require.NoError(t, err)
+	require.Len(t, pkgLayout.Pkg.Components, 2)
+	require.Equal(t, "generated", pkgLayout.Pkg.Components[1].Name)
+
+	filesDir, err := pkgLayout.GetComponentDir(ctx, t.TempDir(), "generated", layout.FilesComponentDir)
+	require.NoError(t, err)
+	b, err := os.ReadFile(filepath.Join(filesDir, "0", "generated.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "generated\n", string(b))
+}
+
+func TestPackageCreateRegistryPolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		policy        images.RegistryPolicy
+		errorContains string
+	}{
+		{
+			name: "no policy",
+		},
+		{
+			name:   "all registries allowed",
+			policy: images.RegistryPolicy{AllowedRegistries: []string{"ghcr.io", "quay.io"}},
+		},
+		{
+			name:          "image found in a manifest from a registry that is not allowed",
+			policy:        images.RegistryPolicy{AllowedRegistries: []string{"ghcr.io"}},
+			errorContains: "1 image(s) violate the registry policy:\n - quay.io/prometheus/busybox:latest (registry quay.io is not allowed)",
+		},
+		{
+			name:          "image found in a manifest from a denied registry",
+			policy:        images.RegistryPolicy{DeniedRegistries: []string{"ghcr.io/stefanprodan"}},
+			errorContains: "1 image(s) violate the registry policy:\n - ghcr.io/stefanprodan/podinfo:6.4.0 (denied registry ghcr.io/stefanprodan)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := testutil.TestContext(t)
+
+			_, err := Create(ctx, filepath.Join("testdata", "create", "registry-policy"), t.TempDir(), CreateOptions{
+				SkipSBOM:       true,
+				RegistryPolicy: tt.policy,
+			})
+			if tt.errorContains != "" {
+				require.ErrorContains(t, err, tt.errorContains)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestValidateRegistryPolicy(t *testing.T) {
+	t.Parallel()
+
+	pkg := v1alpha1.ZarfPackage{
+		Components: []v1alpha1.ZarfComponent{
+			{
+				Name:   "component1",
+				Images: []string{"ghcr.io/stefanprodan/podinfo:6.4.0", "nginx:1.21"},
+			},
+			{
+				Name: "component2",
+				ImageArchives: []v1alpha1.ImageArchive{
+					{
+						Path:   "/path/to/archive1.tar",
+						Images: []string{"quay.io/prometheus/busybox:latest", "nginx:1.21"},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		policy        images.RegistryPolicy
+		errorContains string
+	}{
+		{
+			name:   "all registries allowed",
+			policy: images.RegistryPolicy{AllowedRegistries: []string{"ghcr.io", "docker.io", "quay.io"}},
+		},
+		{
+			name:          "image archive from a registry that is not allowed",
+			policy:        images.RegistryPolicy{AllowedRegistries: []string{"ghcr.io", "docker.io"}},
+			errorContains: "1 image(s) violate the registry policy:\n - quay.io/prometheus/busybox:latest (registry quay.io is not allowed)",
+		},
+		{
+			name:          "duplicate images are reported once",
+			policy:        images.RegistryPolicy{DeniedRegistries: []string{"docker.io"}},
+			errorContains: "1 image(s) violate the registry policy:\n - nginx:1.21 (denied registry docker.io)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateRegistryPolicy(testutil.TestContext(t), pkg, t.TempDir(), CreateOptions{RegistryPolicy: tt.policy})
+			if tt.errorContains != "" {
+				require.ErrorContains(t, err, tt.errorContains)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestAddScannedImages(t *testing.T) {
+	t.Parallel()
+
+	components := []v1alpha1.ZarfComponent{
+		{
+			Name:   "podinfo",
+			Images: []string{"ghcr.io/stefanprodan/podinfo:6.4.0", "nginx:1.25@sha256:0000000000000000000000000000000000000000000000000000000000000000"},
+		},
+		{
+			Name: "archived",
+			ImageArchives: []v1alpha1.ImageArchive{
+				{
+					Path:   "images.tar",
+					Images: []string{"quay.io/prometheus/busybox:latest"},
+				},
+			},
+		},
+	}
+	scans := []ComponentImageScan{
+		{
+			ComponentName: "podinfo",
+			Matches:       []string{"ghcr.io/stefanprodan/podinfo:6.4.0", "docker.io/library/nginx:1.25", "busybox:1.36"},
+		},
+		{
+			ComponentName: "archived",
+			Matches:       []string{"quay.io/prometheus/busybox:latest", "registry.k8s.io/pause:3.9"},
+		},
+	}
+
+	added, err := addScannedImages(testutil.TestContext(t), components, scans)
+	require.NoError(t, err)
+	require.Equal(t, []string{"ghcr.io/stefanprodan/podinfo:6.4.0", "nginx:1.25@sha256:0000000000000000000000000000000000000000000000000000000000000000", "busybox:1.36"}, added[0].Images)
+	require.Equal(t, []string{"registry.k8s.io/pause:3.9"}, added[1].Images)
+	// The components passed in are not modified
+	require.Len(t, components[0].Images, 2)
+	require.Empty(t, components[1].Images)
+}
+
 func TestPackageCreateDifferentialOCIPackage(t *testing.T) {
 	ctx := testutil.TestContext(t)
 	tests := []struct {
@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -27,21 +28,26 @@ import (
 	"github.com/zarf-dev/zarf/src/pkg/cluster"
 	"github.com/zarf-dev/zarf/src/pkg/feature"
 	"github.com/zarf-dev/zarf/src/pkg/images"
+	"github.com/zarf-dev/zarf/src/pkg/interactive"
 	"github.com/zarf-dev/zarf/src/pkg/logger"
 	"github.com/zarf-dev/zarf/src/pkg/packager/actions"
 	"github.com/zarf-dev/zarf/src/pkg/packager/filters"
 	"github.com/zarf-dev/zarf/src/pkg/packager/layout"
 	"github.com/zarf-dev/zarf/src/pkg/pki"
 	"github.com/zarf-dev/zarf/src/pkg/state"
+	"github.com/zarf-dev/zarf/src/pkg/tracing"
 	"github.com/zarf-dev/zarf/src/pkg/transform"
 	"github.com/zarf-dev/zarf/src/pkg/utils"
 	"github.com/zarf-dev/zarf/src/pkg/value"
 	"github.com/zarf-dev/zarf/src/pkg/variables"
 	"github.com/zarf-dev/zarf/src/types"
 	"golang.org/x/sync/errgroup"
+	"helm.sh/helm/v4/pkg/chart/common"
+	chartv2 "helm.sh/helm/v4/pkg/chart/v2"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kubectl/pkg/validation"
 )
 
 // DeployOptions are optional parameters to packager.Deploy
@@ -59,6 +65,8 @@ type DeployOptions struct {
 	ForceConflicts bool
 	// Timeout for Helm operations
 	Timeout time.Duration
+	// DeployTimeout bounds the wall-clock time of the entire deploy, there is no bound when zero
+	DeployTimeout time.Duration
 	// Retries to preform for operations like git and image pushes
 	Retries int
 	// Number of layers to push concurrently per image
@@ -75,11 +83,29 @@ type DeployOptions struct {
 	InjectorPort   int
 	// AgentTLS allows providing user-managed TLS certificates for the agent. When nil, certs are auto-generated.
 	AgentTLS *pki.GeneratedPKI
+	// AgentImageFields are the custom resource image fields the agent rewrites
+	AgentImageFields []state.AgentImageField
+	// AgentImageRewrites are the registries the agent points at another destination instead of the Zarf registry
+	AgentImageRewrites []state.AgentImageRewrite
+	// AgentImagePassthrough are the registries whose images the agent leaves untouched
+	AgentImagePassthrough []string
 
 	// [Library Only] A map of component names to chart names containing Helm Chart values to override values on deploy
 	ValuesOverridesMap ValuesOverrides
 	// IsInteractive decides if Zarf can interactively prompt users through the CLI
 	IsInteractive bool
+	// Step pauses after each successfully deployed component and prompts to continue or abort, requires a terminal
+	Step bool
+	// KeepGoing continues with the remaining components when an optional component fails, skipping the components that
+	// depend on it, and returns an error summarizing the failures at the end
+	KeepGoing bool
+	// ActionTags runs only the onDeploy actions with at least one of these tags, all actions run when empty
+	ActionTags []string
+	// RunUntaggedActions also runs the onDeploy actions without tags when ActionTags is set
+	RunUntaggedActions bool
+	// ValidateSchema checks the rendered resources of every chart and manifest against the OpenAPI schema of the cluster
+	// before any component is deployed
+	ValidateSchema bool
 	// SkipVersionCheck skips version requirement validation
 	SkipVersionCheck bool
 }
@@ -91,6 +117,27 @@ type deployer struct {
 	c    *cluster.Cluster
 	vc   *variables.VariableConfig
 	vals value.Values
+	// step approves continuing to the next component, nil when not stepping through the deployment
+	step actions.ConfirmFunc
+	// actionFilter selects the onDeploy actions to run
+	actionFilter actions.TagFilter
+	// component and stage track what is being deployed so a deploy timeout can report where it was reached
+	component string
+	stage     string
+	// clusterArchitectures are the architectures of the nodes of the cluster, read once connected to the cluster
+	clusterArchitectures []string
+}
+
+// onFailureTimeout bounds the onDeploy failure actions of a component, which run after the deploy context is cancelled.
+const onFailureTimeout = 5 * time.Minute
+
+// errDeployTimeout is the cause of the context cancellation when the deploy timeout is reached.
+var errDeployTimeout = errors.New("deploy timeout reached")
+
+// setStage records the component and the stage of its deployment that is running.
+func (d *deployer) setStage(component, stage string) {
+	d.component = component
+	d.stage = stage
 }
 
 // DeployResult is the result of a successful deploy
@@ -101,11 +148,18 @@ type DeployResult struct {
 }
 
 // Deploy takes a reference to a `layout.PackageLayout` and deploys the package. If successful, returns a list of components that were successfully deployed and the associated variable config.
-func Deploy(ctx context.Context, pkgLayout *layout.PackageLayout, opts DeployOptions) (DeployResult, error) {
+func Deploy(ctx context.Context, pkgLayout *layout.PackageLayout, opts DeployOptions) (_ DeployResult, err error) {
 	start := time.Now()
+	ctx, span := tracing.Start(ctx, "deploy package", tracing.PackageKey.String(pkgLayout.Pkg.Metadata.Name))
+	defer func() {
+		tracing.End(span, err)
+	}()
 	if opts.Connected && pkgLayout.Pkg.IsInitConfig() {
 		return DeployResult{}, fmt.Errorf("--connected is not supported for init packages")
 	}
+	if opts.Step && !interactive.IsTerminal() {
+		return DeployResult{}, errors.New("--step requires a terminal to prompt between components")
+	}
 
 	// Validate operational requirements before proceeding
 	if !opts.SkipVersionCheck {
@@ -142,7 +196,6 @@ func Deploy(ctx context.Context, pkgLayout *layout.PackageLayout, opts DeployOpt
 		opts.Timeout = config.ZarfDefaultTimeout
 	}
 
-	var err error
 	pkgLayout.Pkg.Components, err = filters.ByLocalOS(runtime.GOOS).Apply(pkgLayout.Pkg)
 	if err != nil {
 		return DeployResult{}, err
@@ -153,6 +206,22 @@ func Deploy(ctx context.Context, pkgLayout *layout.PackageLayout, opts DeployOpt
 		return DeployResult{}, err
 	}
 
+	// Components enabled by an expression can only be filtered once the variables are resolved
+	variableValues := map[string]string{}
+	for name, variable := range variableConfig.GetSetVariableMap() {
+		variableValues[name] = variable.Value
+	}
+	filtered, decisions, err := filters.Explain(filters.ByExpression(variableValues), pkgLayout.Pkg)
+	if err != nil {
+		return DeployResult{}, err
+	}
+	for _, decision := range decisions {
+		if !decision.Included {
+			l.Info("skipping component whose expression is false", "component", decision.Name, "reason", decision.Reason)
+		}
+	}
+	pkgLayout.Pkg.Components = filtered
+
 	if len(opts.Values) > 0 && !feature.IsEnabled(feature.Values) {
 		return DeployResult{}, fmt.Errorf("package-level values passed in but \"%s\" feature is not enabled."+
 			" Run again with --features=\"%s=true\"", feature.Values, feature.Values)
@@ -180,11 +249,19 @@ func Deploy(ctx context.Context, pkgLayout *layout.PackageLayout, opts DeployOpt
 	d := deployer{
 		vc:   variableConfig,
 		vals: vals,
+		actionFilter: actions.TagFilter{
+			Tags:            opts.ActionTags,
+			IncludeUntagged: opts.RunUntaggedActions,
+		},
+	}
+	if opts.Step {
+		d.step = interactive.PromptConfirm
 	}
 
 	l.Debug("variables populated", "time", time.Since(start))
 
-	deployedComponents, err := d.deployComponents(ctx, pkgLayout, opts)
+	ctx = actions.WithConfirm(ctx, actions.NewConfirmFunc(opts.IsInteractive, interactive.IsTerminal, interactive.PromptConfirm))
+	deployedComponents, err := d.deployComponentsWithTimeout(ctx, pkgLayout, opts)
 	if err != nil {
 		return DeployResult{}, err
 	}
@@ -202,10 +279,189 @@ func Deploy(ctx context.Context, pkgLayout *layout.PackageLayout, opts DeployOpt
 	return deployResult, nil
 }
 
+// deployComponentsWithTimeout deploys the components, cancelling the deployment once opts.DeployTimeout is reached.
+func (d *deployer) deployComponentsWithTimeout(ctx context.Context, pkgLayout *layout.PackageLayout, opts DeployOptions) ([]state.DeployedComponent, error) {
+	if opts.DeployTimeout <= 0 {
+		return d.deployComponents(ctx, pkgLayout, opts)
+	}
+	ctx, cancel := context.WithTimeoutCause(ctx, opts.DeployTimeout, errDeployTimeout)
+	defer cancel()
+	deployedComponents, err := d.deployComponents(ctx, pkgLayout, opts)
+	if err != nil && errors.Is(context.Cause(ctx), errDeployTimeout) {
+		return nil, fmt.Errorf("deploy timed out after %s while running %s of component %q: %w", opts.DeployTimeout, d.stage, d.component, err)
+	}
+	return deployedComponents, err
+}
+
 func (d *deployer) isConnectedToCluster() bool {
 	return d.c != nil
 }
 
+// skipForClusterArchitecture reports whether the component is restricted to an architecture that no node of the cluster
+// has, along with the reason. The architectures are read from the cluster on first use. Components are kept when the
+// architectures can not be read, and for any of the architectures of a cluster with nodes of multiple architectures.
+func (d *deployer) skipForClusterArchitecture(ctx context.Context, component v1alpha1.ZarfComponent) (string, bool) {
+	l := logger.From(ctx)
+	if d.clusterArchitectures == nil {
+		architectures, err := d.c.GetArchitectures(ctx)
+		switch {
+		case err != nil:
+			l.Warn("unable to get the architectures of the cluster, components are not filtered by architecture", "error", err)
+			architectures = []string{}
+		case len(architectures) == 0:
+			l.Warn("the nodes of the cluster do not report an architecture, components are not filtered by architecture")
+		case len(architectures) > 1:
+			l.Warn("the cluster has nodes of multiple architectures, components for any of them are deployed", "architectures", architectures)
+		}
+		d.clusterArchitectures = architectures
+	}
+	if len(d.clusterArchitectures) == 0 {
+		return "", false
+	}
+	_, decisions, err := filters.Explain(filters.ByClusterArchitecture(d.clusterArchitectures...), v1alpha1.ZarfPackage{Components: []v1alpha1.ZarfComponent{component}})
+	if err != nil || len(decisions) == 0 {
+		return "", false
+	}
+	return decisions[0].Reason, !decisions[0].Included
+}
+
+// connectToCluster connects to the cluster on first use and verifies that the package can be deployed to it.
+func (d *deployer) connectToCluster(ctx context.Context, pkg v1alpha1.ZarfPackage) error {
+	if d.isConnectedToCluster() {
+		return nil
+	}
+	timeout := cluster.DefaultTimeout
+	if pkg.IsInitConfig() {
+		timeout = 5 * time.Minute
+	}
+	connectCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	c, err := cluster.NewWithWait(connectCtx)
+	if err != nil {
+		return fmt.Errorf("unable to connect to the Kubernetes cluster: %w", err)
+	}
+	d.c = c
+	if err := d.verifyPackageIsDeployable(ctx, pkg); err != nil {
+		return fmt.Errorf("package is not deployable to this system: %w", err)
+	}
+	return nil
+}
+
+// validateSchema renders the charts and manifests of every component and checks their resources against the OpenAPI
+// schema of the cluster, so that a package with invalid resources fails before any of its components is deployed.
+// Resources are rendered with the variables known before the deploy, components that come after an action that sets
+// variables or values are not validated as their resources may depend on them.
+func (d *deployer) validateSchema(ctx context.Context, pkgLayout *layout.PackageLayout, opts DeployOptions) error {
+	l := logger.From(ctx)
+	var validator validation.Schema
+	kubeVersion := ""
+	var s *state.State
+	dependsOnActions := false
+	errs := []error{}
+	for _, component := range pkgLayout.Pkg.Components {
+		onDeploy := component.Actions.OnDeploy
+		dependsOnActions = dependsOnActions || setsVariables(onDeploy.Before)
+		if len(component.Charts) == 0 && len(component.Manifests) == 0 {
+			dependsOnActions = dependsOnActions || setsVariables(onDeploy.After) || setsVariables(onDeploy.OnSuccess) || setsVariables(onDeploy.OnFailure)
+			continue
+		}
+		if dependsOnActions {
+			l.Warn("skipping the schema validation of a component that may depend on variables set by actions", "component", component.Name)
+			continue
+		}
+		d.setStage(component.Name, "the schema validation")
+
+		if validator == nil {
+			if err := d.connectToCluster(ctx, pkgLayout.Pkg); err != nil {
+				return err
+			}
+			serverVersion, err := d.c.Clientset.Discovery().ServerVersion()
+			if err != nil {
+				return fmt.Errorf("unable to get the Kubernetes version of the cluster: %w", err)
+			}
+			kubeVersion = serverVersion.GitVersion
+			validator = d.c.SchemaValidator()
+			// The state does not exist before the cluster is initialized, its templates are left unset then
+			//nolint: errcheck // the state is optional for the validation
+			s, _ = d.c.LoadState(ctx)
+		}
+		if component.Only.Cluster.Architecture != "" {
+			if _, skip := d.skipForClusterArchitecture(ctx, component); skip {
+				continue
+			}
+		}
+
+		applicationTemplates, err := ptmpl.GetZarfTemplates(ctx, component.Name, s)
+		if err != nil {
+			return err
+		}
+		d.vc.SetApplicationTemplates(applicationTemplates)
+		if err := d.validateComponentSchema(ctx, pkgLayout, component, validator, kubeVersion, opts); err != nil {
+			errs = append(errs, err)
+		}
+		dependsOnActions = dependsOnActions || setsVariables(onDeploy.After) || setsVariables(onDeploy.OnSuccess) || setsVariables(onDeploy.OnFailure)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("package resources do not match the schema of the cluster:\n%w", err)
+	}
+	return nil
+}
+
+// validateComponentSchema renders the charts and manifests of the component and checks them against the schema.
+func (d *deployer) validateComponentSchema(ctx context.Context, pkgLayout *layout.PackageLayout, component v1alpha1.ZarfComponent, validator validation.Schema, kubeVersion string, opts DeployOptions) (err error) {
+	tmpDir, err := utils.MakeTempDir(config.CommonOptions.TempDirectory)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, os.RemoveAll(tmpDir))
+	}()
+
+	errs := []error{}
+	if len(component.Charts) > 0 {
+		chartDir, err := pkgLayout.GetComponentDir(ctx, tmpDir, component.Name, layout.ChartsComponentDir)
+		if err != nil {
+			return err
+		}
+		valuesDir, err := pkgLayout.GetComponentDir(ctx, tmpDir, component.Name, layout.ValuesComponentDir)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to get values: %w", err)
+		}
+		for _, chart := range component.Charts {
+			helmChart, values, err := d.loadChart(ctx, component, chart, chartDir, valuesDir, opts)
+			if err == nil {
+				err = helm.ValidateChartSchema(ctx, chart, helmChart, values, kubeVersion, d.vc, validator)
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("chart %s of component %s: %w", chart.Name, component.Name, err))
+			}
+		}
+	}
+	if len(component.Manifests) > 0 {
+		manifestDir, err := pkgLayout.GetComponentDir(ctx, tmpDir, component.Name, layout.ManifestsComponentDir)
+		if err != nil {
+			return err
+		}
+		for _, manifest := range component.Manifests {
+			chart, helmChart, err := d.loadManifest(ctx, pkgLayout.Pkg, component, manifest, manifestDir)
+			if err == nil {
+				err = helm.ValidateChartSchema(ctx, chart, helmChart, nil, kubeVersion, d.vc, validator)
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("manifest %s of component %s: %w", manifest.Name, component.Name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// setsVariables reports whether any of the actions sets variables or values for the rest of the deploy.
+func setsVariables(actions []v1alpha1.ZarfComponentAction) bool {
+	return slices.ContainsFunc(actions, func(action v1alpha1.ZarfComponentAction) bool {
+		return action.DeprecatedSetVariable != "" || len(action.SetVariables) > 0 || len(action.SetVariablesFromJSON) > 0 || len(action.SetValues) > 0
+	})
+}
+
 func (d *deployer) deployComponents(ctx context.Context, pkgLayout *layout.PackageLayout, opts DeployOptions) ([]state.DeployedComponent, error) {
 	l := logger.From(ctx)
 	deployedComponents := []state.DeployedComponent{}
@@ -214,25 +470,36 @@ func (d *deployer) deployComponents(ctx context.Context, pkgLayout *layout.Packa
 		return nil, fmt.Errorf("failed to get working directory: %w", err)
 	}
 
-	for _, component := range pkgLayout.Pkg.Components {
+	if opts.ValidateSchema {
+		if err := d.validateSchema(ctx, pkgLayout, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	// failed tracks the optional components that failed or were skipped when keep going
+	failed := map[string]bool{}
+	failures := []string{}
+	for i, component := range pkgLayout.Pkg.Components {
+		if dep := failedDependency(component, failed); dep != "" {
+			l.Warn("skipping component because a component it depends on failed", "component", component.Name, "dependsOn", dep)
+			_, span := tracing.Start(ctx, "deploy component", tracing.ComponentKey.String(component.Name), tracing.OutcomeKey.String(tracing.OutcomeSkipped))
+			span.End()
+			failed[component.Name] = true
+			failures = append(failures, fmt.Sprintf("%s (skipped, depends on failed component %s)", component.Name, dep))
+			continue
+		}
+
+		componentCtx, span := tracing.Start(ctx, "deploy component", tracing.ComponentKey.String(component.Name))
+		// Only the first End of a span is recorded, this ends the span on returns that do not record an outcome
+		defer span.End()
+		componentCtx = actions.WithComponent(componentCtx, component.Name)
 		packageGeneration := 1
+		d.setStage(component.Name, "the cluster connection")
 		// Connect to cluster if a component requires it.
 		if component.RequiresCluster() {
-			if !d.isConnectedToCluster() {
-				timeout := cluster.DefaultTimeout
-				if pkgLayout.Pkg.IsInitConfig() {
-					timeout = 5 * time.Minute
-				}
-				connectCtx, cancel := context.WithTimeout(ctx, timeout)
-				defer cancel()
-				var err error
-				d.c, err = cluster.NewWithWait(connectCtx)
-				if err != nil {
-					return nil, fmt.Errorf("unable to connect to the Kubernetes cluster: %w", err)
-				}
-				if err := d.verifyPackageIsDeployable(ctx, pkgLayout.Pkg); err != nil {
-					return nil, fmt.Errorf("package is not deployable to this system: %w", err)
-				}
+			if err := d.connectToCluster(ctx, pkgLayout.Pkg); err != nil {
+				tracing.End(span, err)
+				return nil, err
 			}
 			// If this package has been deployed before, increment the package generation within the secret
 			//nolint: errcheck // this may be the first time deploying the package therefore it will not exist
@@ -241,6 +508,15 @@ func (d *deployer) deployComponents(ctx context.Context, pkgLayout *layout.Packa
 			}
 		}
 
+		if d.isConnectedToCluster() && component.Only.Cluster.Architecture != "" {
+			if reason, skip := d.skipForClusterArchitecture(ctx, component); skip {
+				l.Info("skipping component that does not match the architecture of the cluster", "component", component.Name, "reason", reason)
+				span.SetAttributes(tracing.OutcomeKey.String(tracing.OutcomeSkipped))
+				span.End()
+				continue
+			}
+		}
+
 		deployedComponent := state.DeployedComponent{
 			Name:               component.Name,
 			Status:             state.ComponentStatusDeploying,
@@ -263,23 +539,48 @@ func (d *deployer) deployComponents(ctx context.Context, pkgLayout *layout.Packa
 				l.Debug("unable to record package deployment", "component", component.Name, "error", err.Error())
 			}
 		}
-		var charts []state.InstalledChart
-		var deployErr error
-		if pkgLayout.Pkg.IsInitConfig() {
-			charts, deployErr = d.deployInitComponent(ctx, pkgLayout, component, opts)
-		} else {
-			charts, deployErr = d.deployComponent(ctx, pkgLayout, component, false, false, opts)
-		}
-
 		onDeploy := component.Actions.OnDeploy
 
 		onFailure := func() {
-			if err := actions.Run(ctx, cwd, onDeploy.Defaults, onDeploy.OnFailure, d.vc, d.vals); err != nil {
+			// The deploy can already be cancelled, e.g. by the deploy timeout, so the failure actions get their own deadline
+			failureCtx, cancel := context.WithTimeout(context.WithoutCancel(componentCtx), onFailureTimeout)
+			defer cancel()
+			if err := actions.Run(failureCtx, cwd, onDeploy.Defaults, d.actionFilter.Filter(ctx, onDeploy.OnFailure), d.vc, d.vals); err != nil {
 				l.Debug("unable to run component failure action", "error", err.Error())
 			}
 		}
 
+		var charts []state.InstalledChart
+		var deployErr error
+		maxAttempts := component.GetMaxAttempts()
+		attempt := 1
+		for ; ; attempt++ {
+			if pkgLayout.Pkg.IsInitConfig() {
+				charts, deployErr = d.deployInitComponent(componentCtx, pkgLayout, component, opts)
+			} else {
+				charts, deployErr = d.deployComponent(componentCtx, pkgLayout, component, false, false, opts)
+			}
+			if deployErr == nil || attempt >= maxAttempts || ctx.Err() != nil {
+				break
+			}
+			l.Warn("component deployment failed, retrying", "component", component.Name, "attempt", attempt, "maxAttempts", maxAttempts, "error", deployErr.Error())
+			deployedComponents[idx].InstalledCharts = state.MergeInstalledChartsForComponent(deployedComponents[idx].InstalledCharts, charts, true)
+			if component.RetryPolicy.OnFailureBetweenAttempts {
+				onFailure()
+			}
+			if !waitForRetry(ctx, retryBackoff(*component.RetryPolicy, attempt)) {
+				break
+			}
+		}
+		if maxAttempts > 1 {
+			deployedComponents[idx].Attempts = attempt
+		}
+		if deployErr != nil && attempt > 1 {
+			deployErr = fmt.Errorf("failed after %d attempts: %w", attempt, deployErr)
+		}
+
 		if deployErr != nil {
+			tracing.End(span, deployErr)
 			cleanup := func(ctx context.Context) {
 				onFailure()
 				l.Debug("component deployment failed", "component", component.Name, "error", deployErr.Error())
@@ -298,6 +599,12 @@ func (d *deployer) deployComponents(ctx context.Context, pkgLayout *layout.Packa
 				return nil, fmt.Errorf("context cancelled while deploying component %q: %w", component.Name, deployErr)
 			default:
 				cleanup(ctx)
+				if opts.KeepGoing && !component.IsRequired() {
+					l.Error("component deployment failed, continuing with the remaining components", "component", component.Name, "error", deployErr.Error())
+					failed[component.Name] = true
+					failures = append(failures, fmt.Sprintf("%s: %s", component.Name, deployErr.Error()))
+					continue
+				}
 				return nil, fmt.Errorf("unable to deploy component %q: %w", component.Name, deployErr)
 			}
 		}
@@ -311,15 +618,110 @@ func (d *deployer) deployComponents(ctx context.Context, pkgLayout *layout.Packa
 			}
 		}
 
-		if err := actions.Run(ctx, cwd, onDeploy.Defaults, onDeploy.OnSuccess, d.vc, d.vals); err != nil {
+		d.setStage(component.Name, "the onDeploy success actions")
+		err := actions.Run(componentCtx, cwd, onDeploy.Defaults, d.actionFilter.Filter(ctx, onDeploy.OnSuccess), d.vc, d.vals)
+		tracing.End(span, err)
+		if err != nil {
 			onFailure()
+			if opts.KeepGoing && !component.IsRequired() && ctx.Err() == nil {
+				l.Error("component success action failed, continuing with the remaining components", "component", component.Name, "error", err.Error())
+				failed[component.Name] = true
+				failures = append(failures, fmt.Sprintf("%s: unable to run component success action: %s", component.Name, err.Error()))
+				continue
+			}
 			return nil, fmt.Errorf("unable to run component success action: %w", err)
 		}
+
+		// Pause for manual verification before moving on to the next component
+		if d.step != nil && i < len(pkgLayout.Pkg.Components)-1 {
+			next := pkgLayout.Pkg.Components[i+1].Name
+			proceed, err := d.step(ctx, stepPrompt(component, deployedComponents[idx], next))
+			if err != nil {
+				return nil, fmt.Errorf("unable to prompt to continue the deployment: %w", err)
+			}
+			if !proceed {
+				// An aborted component is handled like a failed one so its failure actions can undo it
+				onFailure()
+				deployedComponents[idx].Status = state.ComponentStatusFailed
+				if d.isConnectedToCluster() {
+					if _, err := d.c.RecordPackageDeployment(ctx, pkgLayout.Pkg, deployedComponents, packageGeneration, state.WithPackageConnectivity(opts.Connected), state.WithPackageNamespaceOverride(opts.NamespaceOverride)); err != nil {
+						l.Debug("unable to record package deployment", "component", component.Name, "error", err.Error())
+					}
+				}
+				return nil, fmt.Errorf("deployment aborted after component %q, %q and the remaining components were not deployed", component.Name, next)
+			}
+		}
 	}
 
+	if len(failures) > 0 {
+		return nil, fmt.Errorf("%d component(s) failed to deploy:\n - %s", len(failures), strings.Join(failures, "\n - "))
+	}
 	return deployedComponents, nil
 }
 
+// retryBackoff returns how long to wait before retrying a component after the given failed attempt, the backoff is
+// doubled after every attempt.
+func retryBackoff(policy v1alpha1.ZarfComponentRetryPolicy, attempt int) time.Duration {
+	return time.Duration(policy.BackoffSeconds) * time.Second << (attempt - 1)
+}
+
+// waitForRetry waits for the backoff to pass and returns false when the context is cancelled before it does.
+func waitForRetry(ctx context.Context, backoff time.Duration) bool {
+	if backoff <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// failedDependency returns the first component the given component depends on that failed or was skipped.
+func failedDependency(component v1alpha1.ZarfComponent, failed map[string]bool) string {
+	for _, dep := range component.DependsOn {
+		if failed[dep] {
+			return dep
+		}
+	}
+	return ""
+}
+
+// stepPrompt summarizes what a component deployed and asks whether to continue with the next component.
+func stepPrompt(component v1alpha1.ZarfComponent, deployed state.DeployedComponent, next string) string {
+	summary := []string{}
+	if len(deployed.InstalledCharts) > 0 {
+		charts := []string{}
+		for _, chart := range deployed.InstalledCharts {
+			charts = append(charts, fmt.Sprintf("%s/%s", chart.Namespace, chart.ChartName))
+		}
+		summary = append(summary, fmt.Sprintf("charts: %s", strings.Join(charts, ", ")))
+	}
+	if len(component.Manifests) > 0 {
+		summary = append(summary, fmt.Sprintf("manifests: %d", len(component.Manifests)))
+	}
+	if imgs := component.GetImages(); len(imgs) > 0 {
+		summary = append(summary, fmt.Sprintf("images: %d", len(imgs)))
+	}
+	if len(component.Repos) > 0 {
+		summary = append(summary, fmt.Sprintf("repos: %d", len(component.Repos)))
+	}
+	if len(component.Files) > 0 {
+		summary = append(summary, fmt.Sprintf("files: %d", len(component.Files)))
+	}
+	if deployed.Attempts > 1 {
+		summary = append(summary, fmt.Sprintf("attempts: %d", deployed.Attempts))
+	}
+	deployedMsg := fmt.Sprintf("Component %q deployed", component.Name)
+	if len(summary) > 0 {
+		deployedMsg = fmt.Sprintf("%s (%s)", deployedMsg, strings.Join(summary, "; "))
+	}
+	return fmt.Sprintf("%s. Continue with component %q?", deployedMsg, next)
+}
+
 // internalServicesFor returns the state services Zarf will deploy internally in this init run.
 func internalServicesFor(components []v1alpha1.ZarfComponent, opts DeployOptions) state.ServiceSet {
 	services := state.NewServiceSet()
@@ -349,6 +751,7 @@ func (d *deployer) deployInitComponent(ctx context.Context, pkgLayout *layout.Pa
 
 	// Always init the state before the first component that requires the cluster (on most deployments, the zarf-seed-registry)
 	if component.RequiresCluster() && d.s == nil {
+		d.setStage(component.Name, "the Zarf state initialization")
 		applianceMode := false
 		for _, component := range pkgLayout.Pkg.Components {
 			if component.Name == "k3s" {
@@ -357,14 +760,17 @@ func (d *deployer) deployInitComponent(ctx context.Context, pkgLayout *layout.Pa
 		}
 		var err error
 		d.s, err = d.c.InitState(ctx, cluster.InitStateOptions{
-			GitServer:        opts.GitServer,
-			RegistryInfo:     opts.RegistryInfo,
-			ArtifactServer:   opts.ArtifactServer,
-			ApplianceMode:    applianceMode,
-			StorageClass:     opts.StorageClass,
-			InjectorPort:     opts.InjectorPort,
-			AgentTLS:         opts.AgentTLS,
-			InternalServices: internalServicesFor(pkgLayout.Pkg.Components, opts),
+			GitServer:             opts.GitServer,
+			RegistryInfo:          opts.RegistryInfo,
+			ArtifactServer:        opts.ArtifactServer,
+			ApplianceMode:         applianceMode,
+			StorageClass:          opts.StorageClass,
+			InjectorPort:          opts.InjectorPort,
+			AgentTLS:              opts.AgentTLS,
+			InternalServices:      internalServicesFor(pkgLayout.Pkg.Components, opts),
+			AgentImageFields:      opts.AgentImageFields,
+			AgentImageRewrites:    opts.AgentImageRewrites,
+			AgentImagePassthrough: opts.AgentImagePassthrough,
 		})
 		if err != nil {
 			return nil, fmt.Errorf("unable to initialize Zarf state: %w", err)
@@ -380,6 +786,7 @@ func (d *deployer) deployInitComponent(ctx context.Context, pkgLayout *layout.Pa
 
 	// Before deploying the seed registry, start the injector
 	if isSeedRegistry {
+		d.setStage(component.Name, "the registry injection")
 		switch d.s.RegistryInfo.RegistryMode {
 		case state.RegistryModeProxy:
 			var err error
@@ -463,17 +870,20 @@ func (d *deployer) deployComponent(ctx context.Context, pkgLayout *layout.Packag
 	d.vc.SetApplicationTemplates(applicationTemplates)
 
 	// Populate objects available to templates in before actions
-	if err := actions.Run(ctx, cwd, onDeploy.Defaults, onDeploy.Before, d.vc, d.vals); err != nil {
+	d.setStage(component.Name, "the onDeploy before actions")
+	if err := actions.Run(ctx, cwd, onDeploy.Defaults, d.actionFilter.Filter(ctx, onDeploy.Before), d.vc, d.vals); err != nil {
 		return nil, fmt.Errorf("unable to run component before action: %w", err)
 	}
 
 	if hasFiles {
+		d.setStage(component.Name, "the file processing")
 		if err := processComponentFiles(ctx, pkgLayout, component, d.vc, d.vals); err != nil {
 			return nil, fmt.Errorf("unable to process the component files: %w", err)
 		}
 	}
 
 	if hasImages {
+		d.setStage(component.Name, "the image push")
 		refs := []transform.Image{}
 		for _, img := range component.GetImages() {
 			ref, err := transform.ParseImageRef(img)
@@ -482,14 +892,20 @@ func (d *deployer) deployComponent(ctx context.Context, pkgLayout *layout.Packag
 			}
 			refs = append(refs, ref)
 		}
+		// The images of a component restricted to a cluster architecture were pulled for that architecture
+		arch := pkgLayout.Pkg.Build.Architecture
+		if component.Only.Cluster.Architecture != "" {
+			arch = component.Only.Cluster.Architecture
+		}
 		pushOpts := images.PushOptions{
 			OCIConcurrency:        opts.OCIConcurrency,
 			PlainHTTP:             opts.PlainHTTP,
 			NoChecksum:            noImgChecksum,
-			Arch:                  pkgLayout.Pkg.Build.Architecture,
+			Arch:                  arch,
 			Retries:               opts.Retries,
 			InsecureSkipTLSVerify: opts.InsecureSkipTLSVerify,
 			Cluster:               d.c,
+			PinnedImages:          slices.Collect(maps.Values(pkgLayout.Pkg.Build.PinnedImages)),
 		}
 		err := images.Push(ctx, refs, pkgLayout.GetImageDirPath(), d.s.RegistryInfo, pushOpts)
 		if err != nil {
@@ -498,6 +914,7 @@ func (d *deployer) deployComponent(ctx context.Context, pkgLayout *layout.Packag
 	}
 
 	if hasRepos {
+		d.setStage(component.Name, "the repository push")
 		if err := pushComponentReposToRegistry(ctx, component, pkgLayout, d.s.GitServer, d.c, opts.Retries); err != nil {
 			return nil, fmt.Errorf("unable to push the repos to the repository: %w", err)
 		}
@@ -523,6 +940,7 @@ func (d *deployer) deployComponent(ctx context.Context, pkgLayout *layout.Packag
 
 	charts := []state.InstalledChart{}
 	if hasCharts {
+		d.setStage(component.Name, "the Helm chart installs")
 		helmCharts, err := d.installCharts(ctx, pkgLayout, component, opts)
 		charts = append(charts, helmCharts...)
 		if err != nil {
@@ -531,6 +949,7 @@ func (d *deployer) deployComponent(ctx context.Context, pkgLayout *layout.Packag
 	}
 
 	if hasManifests {
+		d.setStage(component.Name, "the manifest installs")
 		chartsFromManifests, err := d.installManifests(ctx, pkgLayout, component, opts)
 		charts = append(charts, chartsFromManifests...)
 		if err != nil {
@@ -539,11 +958,13 @@ func (d *deployer) deployComponent(ctx context.Context, pkgLayout *layout.Packag
 	}
 
 	// Populate objects available to templates in after actions
-	if err := actions.Run(ctx, cwd, onDeploy.Defaults, onDeploy.After, d.vc, d.vals); err != nil {
+	d.setStage(component.Name, "the onDeploy after actions")
+	if err := actions.Run(ctx, cwd, onDeploy.Defaults, d.actionFilter.Filter(ctx, onDeploy.After), d.vc, d.vals); err != nil {
 		return charts, fmt.Errorf("unable to run component after action: %w", err)
 	}
 
 	if len(component.HealthChecks) > 0 {
+		d.setStage(component.Name, "the health checks")
 		healthCheckContext, cancel := context.WithTimeout(ctx, opts.Timeout)
 		defer cancel()
 		l.Info("running health checks")
@@ -552,6 +973,7 @@ func (d *deployer) deployComponent(ctx context.Context, pkgLayout *layout.Packag
 		}
 	}
 
+	d.setStage(component.Name, "the data injections")
 	if err := g.Wait(); err != nil {
 		return charts, err
 	}
@@ -560,7 +982,6 @@ func (d *deployer) deployComponent(ctx context.Context, pkgLayout *layout.Packag
 }
 
 func (d *deployer) installCharts(ctx context.Context, pkgLayout *layout.PackageLayout, component v1alpha1.ZarfComponent, opts DeployOptions) (_ []state.InstalledChart, err error) {
-	l := logger.From(ctx)
 	installedCharts := []state.InstalledChart{}
 
 	tmpDir, err := utils.MakeTempDir(config.CommonOptions.TempDirectory)
@@ -586,19 +1007,7 @@ func (d *deployer) installCharts(ctx context.Context, pkgLayout *layout.PackageL
 			chart.NoWait = true
 		}
 
-		// zarf magic for the value file
-		for idx := range chart.ValuesFiles {
-			valueFilePath := helm.StandardValuesName(valuesDir, chart, idx)
-			if err := d.vc.ReplaceTextTemplate(valueFilePath); err != nil {
-				return installedCharts, err
-			}
-		}
-
-		valuesOverrides, err := generateValuesOverrides(ctx, chart, component.Name, overrideOpts{
-			variableConfig:     d.vc,
-			values:             d.vals,
-			valuesOverridesMap: opts.ValuesOverridesMap,
-		})
+		helmChart, values, err := d.loadChart(ctx, component, chart, chartDir, valuesDir, opts)
 		if err != nil {
 			return installedCharts, err
 		}
@@ -614,14 +1023,13 @@ func (d *deployer) installCharts(ctx context.Context, pkgLayout *layout.PackageL
 			PkgName:                pkgLayout.Pkg.Metadata.Name,
 			NamespaceOverride:      opts.NamespaceOverride,
 			IsInteractive:          opts.IsInteractive,
+			ResourceAnnotations:    component.ResourceAnnotations,
+			PostRenderPatchesPath:  valuesDir,
 		}
-		helmChart, values, err := helm.LoadChartData(chart, chartDir, valuesDir, valuesOverrides)
-		if err != nil {
-			return installedCharts, fmt.Errorf("failed to load chart data: %w", err)
-		}
-		l.Debug("loaded chart", "metadata", helmChart.Metadata, "chartValues", helmChart.Values)
 
-		connectStrings, installedChartName, err := helm.InstallOrUpgradeChart(ctx, chart, helmChart, values, helmOpts)
+		chartCtx, span := tracing.Start(ctx, "install chart", tracing.ComponentKey.String(component.Name), tracing.ChartKey.String(chart.Name))
+		connectStrings, installedChartName, err := helm.InstallOrUpgradeChart(chartCtx, chart, helmChart, values, helmOpts)
+		tracing.End(span, err)
 		if err != nil {
 			installedCharts = append(installedCharts, state.InstalledChart{Namespace: chart.Namespace, ChartName: installedChartName, ConnectStrings: connectStrings, Status: state.ChartStatusFailed})
 			return installedCharts, err
@@ -632,8 +1040,66 @@ func (d *deployer) installCharts(ctx context.Context, pkgLayout *layout.PackageL
 	return installedCharts, nil
 }
 
+// loadChart templates the values files of the chart and loads the chart along with its values.
+func (d *deployer) loadChart(ctx context.Context, component v1alpha1.ZarfComponent, chart v1alpha1.ZarfChart, chartDir, valuesDir string, opts DeployOptions) (*chartv2.Chart, common.Values, error) {
+	// zarf magic for the value file
+	for idx := range chart.ValuesFiles {
+		valueFilePath := helm.StandardValuesName(valuesDir, chart, idx)
+		if err := d.vc.ReplaceTextTemplate(valueFilePath); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	valuesOverrides, err := generateValuesOverrides(ctx, chart, component.Name, overrideOpts{
+		variableConfig:     d.vc,
+		values:             d.vals,
+		valuesOverridesMap: opts.ValuesOverridesMap,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	helmChart, values, err := helm.LoadChartData(chart, chartDir, valuesDir, valuesOverrides)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load chart data: %w", err)
+	}
+	if err := helm.ValidateChartValues(chart, helmChart, values); err != nil {
+		return nil, nil, err
+	}
+	logger.From(ctx).Debug("loaded chart", "metadata", helmChart.Metadata, "chartValues", helmChart.Values)
+	return helmChart, values, nil
+}
+
+// manifestTemplateObjects returns the objects available to Go templates in manifests.
+func (d *deployer) manifestTemplateObjects(pkg v1alpha1.ZarfPackage, vals value.Values) template.Objects {
+	return template.NewObjects(vals).
+		WithPackage(pkg).
+		WithBuild(pkg.Build).
+		WithVariables(d.vc.GetSetVariableMap()).
+		WithConstants(d.vc.GetConstants())
+}
+
+// templateManifest applies Go templates to a manifest file in place and errors if any ###ZARF_*### variables are left unresolved.
+func templateManifest(ctx context.Context, path string, vc *variables.VariableConfig, objs template.Objects) error {
+	if err := template.ApplyToFile(ctx, path, path, objs); err != nil {
+		return fmt.Errorf("error applying Go templates to manifest %s: %w", filepath.Base(path), err)
+	}
+	return checkUnresolvedTemplates(filepath.Base(path), path, vc)
+}
+
+// checkUnresolvedTemplates errors if the manifest file at path still contains ###ZARF_*### variables without a value.
+func checkUnresolvedTemplates(name, path string, vc *variables.VariableConfig) error {
+	unresolved, err := vc.FindUnresolvedTemplates(path)
+	if err != nil {
+		return err
+	}
+	if len(unresolved) > 0 {
+		return fmt.Errorf("manifest %s references unresolved variables: %s", name, strings.Join(unresolved, ", "))
+	}
+	return nil
+}
+
 func (d *deployer) installManifests(ctx context.Context, pkgLayout *layout.PackageLayout, component v1alpha1.ZarfComponent, opts DeployOptions) (_ []state.InstalledChart, err error) {
-	l := logger.From(ctx)
 	tmpDir, err := utils.MakeTempDir(config.CommonOptions.TempDirectory)
 	if err != nil {
 		return nil, err
@@ -648,51 +1114,13 @@ func (d *deployer) installManifests(ctx context.Context, pkgLayout *layout.Packa
 
 	installedCharts := []state.InstalledChart{}
 	for _, manifest := range component.Manifests {
-		for idx := range manifest.Files {
-			manifest.Files[idx] = fmt.Sprintf("%s-%d.yaml", manifest.Name, idx)
-			path := filepath.Join(manifestDir, manifest.Files[idx])
-			if helpers.InvalidPath(path) {
-				return installedCharts, fmt.Errorf("unable to find manifest file %s", manifest.Files[idx])
-			}
-			// Apply ###ZARF_VAR_*### substitution before Helm sees the file.
-			if err := d.vc.ReplaceTextTemplate(path); err != nil {
-				return installedCharts, fmt.Errorf("error templating manifest %s: %w", path, err)
-			}
-			if manifest.IsTemplate() {
-				l.Debug("start manifest template", "manifest", manifest.Name, "path", path)
-				objs := template.NewObjects(d.vals).
-					WithPackage(pkgLayout.Pkg).
-					WithBuild(pkgLayout.Pkg.Build).
-					WithVariables(d.vc.GetSetVariableMap()).
-					WithConstants(d.vc.GetConstants())
-				if err := template.ApplyToFile(ctx, path, path, objs); err != nil {
-					return nil, err
-				}
-			}
-		}
-		// Move kustomizations to files now, applying ###ZARF_VAR_*### substitution as well.
-		for idx := range manifest.Kustomizations {
-			kustomization := fmt.Sprintf("kustomization-%s-%d.yaml", manifest.Name, idx)
-			manifest.Files = append(manifest.Files, kustomization)
-			path := filepath.Join(manifestDir, kustomization)
-			if err := d.vc.ReplaceTextTemplate(path); err != nil {
-				return installedCharts, fmt.Errorf("error templating kustomization %s: %w", path, err)
-			}
-		}
-
-		if manifest.Namespace == "" {
-			// Helm gets sad when you don't provide a namespace even though we aren't using helm templating
-			manifest.Namespace = corev1.NamespaceDefault
-		}
-
-		// Create a helmChart and helm cfg from a given Zarf Manifest.
-		chart, helmChart, err := helm.ChartFromZarfManifest(manifest, manifestDir, pkgLayout.Pkg.Metadata.Name, component.Name)
+		chart, helmChart, err := d.loadManifest(ctx, pkgLayout.Pkg, component, manifest, manifestDir)
 		if err != nil {
 			return installedCharts, err
 		}
 		helmOpts := helm.InstallUpgradeOptions{
 			AdoptExistingResources: opts.AdoptExistingResources,
-			ForceConflicts:         opts.ForceConflicts,
+			ForceConflicts:         opts.ForceConflicts || manifest.ForceConflicts,
 			VariableConfig:         d.vc,
 			State:                  d.s,
 			Cluster:                d.c,
@@ -701,20 +1129,80 @@ func (d *deployer) installManifests(ctx context.Context, pkgLayout *layout.Packa
 			PkgName:                pkgLayout.Pkg.Metadata.Name,
 			NamespaceOverride:      opts.NamespaceOverride,
 			IsInteractive:          opts.IsInteractive,
+			ResourceAnnotations:    component.ResourceAnnotations,
+			FieldManager:           manifest.FieldManager,
 		}
 
 		// Install the chart.
-		connectStrings, installedChartName, err := helm.InstallOrUpgradeChart(ctx, chart, helmChart, nil, helmOpts)
+		manifestCtx, span := tracing.Start(ctx, "apply manifest", tracing.ComponentKey.String(component.Name), tracing.ManifestKey.String(manifest.Name))
+		connectStrings, installedChartName, err := helm.InstallOrUpgradeChart(manifestCtx, chart, helmChart, nil, helmOpts)
+		tracing.End(span, err)
 		if err != nil {
-			installedCharts = append(installedCharts, state.InstalledChart{Namespace: manifest.Namespace, ChartName: installedChartName, ConnectStrings: connectStrings, Status: state.ChartStatusFailed})
+			installedCharts = append(installedCharts, state.InstalledChart{Namespace: chart.Namespace, ChartName: installedChartName, ConnectStrings: connectStrings, Status: state.ChartStatusFailed})
 			return installedCharts, err
 		}
-		installedCharts = append(installedCharts, state.InstalledChart{Namespace: manifest.Namespace, ChartName: installedChartName, ConnectStrings: connectStrings, Status: state.ChartStatusSucceeded})
+		installedCharts = append(installedCharts, state.InstalledChart{Namespace: chart.Namespace, ChartName: installedChartName, ConnectStrings: connectStrings, Status: state.ChartStatusSucceeded})
 	}
 
 	return installedCharts, nil
 }
 
+// loadManifest templates the files and kustomizations of the manifest and wraps them in a chart.
+func (d *deployer) loadManifest(ctx context.Context, pkg v1alpha1.ZarfPackage, component v1alpha1.ZarfComponent, manifest v1alpha1.ZarfManifest, manifestDir string) (v1alpha1.ZarfChart, *chartv2.Chart, error) {
+	l := logger.From(ctx)
+	// The files are renamed to their names in the package, clone them to leave the component untouched.
+	manifest.Files = slices.Clone(manifest.Files)
+	var tmplObjs template.Objects
+	if manifest.IsTemplate() {
+		vals, err := manifestTemplateValues(manifest, d.vc, d.vals)
+		if err != nil {
+			return v1alpha1.ZarfChart{}, nil, fmt.Errorf("unable to set the variables of manifest %s: %w", manifest.Name, err)
+		}
+		tmplObjs = d.manifestTemplateObjects(pkg, vals)
+	}
+	for idx := range manifest.Files {
+		manifest.Files[idx] = fmt.Sprintf("%s-%d.yaml", manifest.Name, idx)
+		path := filepath.Join(manifestDir, manifest.Files[idx])
+		if helpers.InvalidPath(path) {
+			return v1alpha1.ZarfChart{}, nil, fmt.Errorf("unable to find manifest file %s", manifest.Files[idx])
+		}
+		// Apply ###ZARF_VAR_*### substitution before Helm sees the file.
+		if err := d.vc.ReplaceTextTemplate(path); err != nil {
+			return v1alpha1.ZarfChart{}, nil, fmt.Errorf("error templating manifest %s: %w", path, err)
+		}
+		if manifest.IsTemplate() {
+			l.Debug("start manifest template", "manifest", manifest.Name, "path", path)
+			if err := templateManifest(ctx, path, d.vc, tmplObjs); err != nil {
+				return v1alpha1.ZarfChart{}, nil, err
+			}
+		}
+	}
+	// Move kustomizations to files now, applying ###ZARF_VAR_*### substitution as well.
+	// Kustomizations are built during package create so they are templated after the build.
+	for idx := range manifest.Kustomizations {
+		kustomization := fmt.Sprintf("kustomization-%s-%d.yaml", manifest.Name, idx)
+		manifest.Files = append(manifest.Files, kustomization)
+		path := filepath.Join(manifestDir, kustomization)
+		if err := d.vc.ReplaceTextTemplate(path); err != nil {
+			return v1alpha1.ZarfChart{}, nil, fmt.Errorf("error templating kustomization %s: %w", path, err)
+		}
+		if manifest.IsTemplate() {
+			l.Debug("start kustomization template", "manifest", manifest.Name, "path", path)
+			if err := templateManifest(ctx, path, d.vc, tmplObjs); err != nil {
+				return v1alpha1.ZarfChart{}, nil, err
+			}
+		}
+	}
+
+	if manifest.Namespace == "" {
+		// Helm gets sad when you don't provide a namespace even though we aren't using helm templating
+		manifest.Namespace = corev1.NamespaceDefault
+	}
+
+	// Create a helmChart and helm cfg from a given Zarf Manifest.
+	return helm.ChartFromZarfManifest(manifest, manifestDir, pkg.Metadata.Name, component.Name)
+}
+
 func (d *deployer) verifyPackageIsDeployable(ctx context.Context, pkg v1alpha1.ZarfPackage) error {
 	if err := verifyClusterCompatibility(ctx, d.c, pkg); err != nil {
 		if errors.Is(err, lang.ErrUnableToCheckArch) {
@@ -767,26 +1255,31 @@ func verifyClusterCompatibility(ctx context.Context, c *cluster.Cluster, pkg v1a
 		return nil
 	}
 
-	// Get node architectures
-	nodeList, err := c.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
-	if err != nil {
-		return lang.ErrUnableToCheckArch
-	}
-	if len(nodeList.Items) == 0 {
+	architectures, err := c.GetArchitectures(ctx)
+	if err != nil || len(architectures) == 0 {
 		return lang.ErrUnableToCheckArch
 	}
-	archMap := map[string]bool{}
-	for _, node := range nodeList.Items {
-		archMap[node.Status.NodeInfo.Architecture] = true
-	}
-	architectures := []string{}
-	for arch := range archMap {
-		architectures = append(architectures, arch)
-	}
 
-	// Check if the package architecture and the cluster architecture are the same.
-	if !slices.Contains(architectures, pkg.Metadata.Architecture) {
-		return fmt.Errorf(lang.CmdPackageDeployValidateArchitectureErr, pkg.Metadata.Architecture, strings.Join(architectures, ", "))
+	// The images of components restricted to a cluster architecture are only needed when a node has that architecture,
+	// all other images are for the package architecture.
+	imageArchitectures := []string{}
+	for _, component := range pkg.Components {
+		if len(component.Images) == 0 && len(component.ImageArchives) == 0 {
+			continue
+		}
+		arch := component.Only.Cluster.Architecture
+		if arch == "" {
+			arch = pkg.Metadata.Architecture
+			if !slices.Contains(architectures, arch) {
+				return fmt.Errorf(lang.CmdPackageDeployValidateArchitectureErr, arch, strings.Join(architectures, ", "))
+			}
+		}
+		imageArchitectures = append(imageArchitectures, arch)
+	}
+	slices.Sort(imageArchitectures)
+	imageArchitectures = slices.Compact(imageArchitectures)
+	if !slices.ContainsFunc(imageArchitectures, func(arch string) bool { return slices.Contains(architectures, arch) }) {
+		return fmt.Errorf(lang.CmdPackageDeployValidateComponentArchitectureErr, strings.Join(imageArchitectures, ", "), strings.Join(architectures, ", "))
 	}
 
 	return nil
@@ -810,6 +1303,21 @@ func processComponentFiles(ctx context.Context, pkgLayout *layout.PackageLayout,
 		return err
 	}
 
+	// Resolve every target up front so symlinks can be validated across all files before anything is written
+	targets := make([]string, len(component.Files))
+	for fileIdx, file := range component.Files {
+		// Replace temp target directory and home directory
+		target, err := config.GetAbsHomePath(strings.Replace(file.Target, "###ZARF_TEMP###", pkgLayout.DirPath(), 1))
+		if err != nil {
+			return err
+		}
+		targets[fileIdx] = target
+	}
+	symlinks, err := resolveSymlinks(component.Files, targets)
+	if err != nil {
+		return err
+	}
+
 	for fileIdx, file := range component.Files {
 		l.Info("loading file", "name", file.Target)
 
@@ -821,17 +1329,12 @@ func processComponentFiles(ctx context.Context, pkgLayout *layout.PackageLayout,
 		// If a shasum is specified check it again on deployment as well
 		if file.Shasum != "" {
 			l.Debug("Validating SHASUM", "file", file.Target)
-			if err := helpers.SHAsMatch(fileLocation, file.Shasum); err != nil {
+			if err := utils.ChecksumMatches(fileLocation, file.Shasum); err != nil {
 				return err
 			}
 		}
 
-		// Replace temp target directory and home directory
-		target, err := config.GetAbsHomePath(strings.Replace(file.Target, "###ZARF_TEMP###", pkgLayout.DirPath(), 1))
-		if err != nil {
-			return err
-		}
-		file.Target = target
+		file.Target = targets[fileIdx]
 
 		fileList := []string{}
 		if helpers.IsDir(fileLocation) {
@@ -880,8 +1383,22 @@ func processComponentFiles(ctx context.Context, pkgLayout *layout.PackageLayout,
 			return fmt.Errorf("unable to copy file %s to %s: %w", fileLocation, file.Target, err)
 		}
 
+		// Apply explicit permissions once the file is in place
+		mode, hasMode, err := file.GetMode()
+		if err != nil {
+			return fmt.Errorf("file %s has an invalid mode: %w", file.Target, err)
+		}
+		if hasMode {
+			if file.Executable {
+				l.Warn("file sets both mode and executable, using mode", "name", file.Target, "mode", file.Mode)
+			}
+			if err := os.Chmod(file.Target, mode); err != nil {
+				return fmt.Errorf("unable to set mode %s on %s: %w", file.Mode, file.Target, err)
+			}
+		}
+
 		// Loop over all symlinks and create them
-		for _, link := range file.Symlinks {
+		for _, link := range symlinks[fileIdx] {
 			// Try to remove the filepath if it exists
 			if err := os.RemoveAll(link); err != nil {
 				return fmt.Errorf("failed to existing file at symlink location %s: %w", link, err)
@@ -902,3 +1419,49 @@ func processComponentFiles(ctx context.Context, pkgLayout *layout.PackageLayout,
 
 	return nil
 }
+
+// resolveSymlinks returns the absolute symlink paths for each file given the resolved file targets.
+// Relative symlinks are resolved against the directory of the file's target and may not escape it.
+// Symlinks that point at themselves, contain their own target, or form a cycle with other symlinks are rejected.
+func resolveSymlinks(files []v1alpha1.ZarfFile, targets []string) ([][]string, error) {
+	symlinks := make([][]string, len(files))
+	linkTargets := map[string]string{}
+	for fileIdx, file := range files {
+		target := filepath.Clean(targets[fileIdx])
+		targetDir := filepath.Dir(target)
+		for _, link := range file.Symlinks {
+			if !filepath.IsAbs(link) {
+				if !filepath.IsLocal(link) {
+					return nil, fmt.Errorf("symlink %s escapes the directory %s of its target", link, targetDir)
+				}
+				link = filepath.Join(targetDir, link)
+			}
+			link = filepath.Clean(link)
+
+			// The symlink is removed before it is created, which would also remove a target within it
+			if rel, err := filepath.Rel(link, target); err == nil && filepath.IsLocal(rel) {
+				return nil, fmt.Errorf("symlink %s creates a loop with its target %s", link, target)
+			}
+			if existing, ok := linkTargets[link]; ok && existing != target {
+				return nil, fmt.Errorf("symlink %s is defined for both %s and %s", link, existing, target)
+			}
+			linkTargets[link] = target
+			symlinks[fileIdx] = append(symlinks[fileIdx], link)
+		}
+	}
+
+	// Follow each symlink through the other symlinks, if it gets back to where it started the links form a cycle
+	for link := range linkTargets {
+		next, ok := linkTargets[link]
+		for range len(linkTargets) {
+			if !ok {
+				break
+			}
+			if next == link {
+				return nil, fmt.Errorf("symlink %s is part of a symlink cycle", link)
+			}
+			next, ok = linkTargets[next]
+		}
+	}
+	return symlinks, nil
+}
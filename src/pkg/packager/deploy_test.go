@@ -5,13 +5,30 @@ package packager
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/stretchr/testify/require"
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 	"github.com/zarf-dev/zarf/src/internal/healthchecks"
+	"github.com/zarf-dev/zarf/src/internal/template"
 	"github.com/zarf-dev/zarf/src/pkg/cluster"
+	"github.com/zarf-dev/zarf/src/pkg/interactive"
+	"github.com/zarf-dev/zarf/src/pkg/packager/layout"
 	"github.com/zarf-dev/zarf/src/pkg/state"
+	"github.com/zarf-dev/zarf/src/pkg/tracing"
+	"github.com/zarf-dev/zarf/src/pkg/value"
+	"github.com/zarf-dev/zarf/src/pkg/variables"
+	"github.com/zarf-dev/zarf/src/test/testutil"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
@@ -115,3 +132,694 @@ func TestVerifyPackageIsDeployableSkipsAgentCertCheckWhenAgentIsNotConfigured(t
 	err = d.verifyPackageIsDeployable(ctx, v1alpha1.ZarfPackage{})
 	require.NoError(t, err)
 }
+
+func TestVerifyClusterCompatibility(t *testing.T) {
+	t.Parallel()
+
+	newComponent := func(name, arch string) v1alpha1.ZarfComponent {
+		return v1alpha1.ZarfComponent{
+			Name:   name,
+			Images: []string{name + ":1.0.0"},
+			Only:   v1alpha1.ZarfComponentOnlyTarget{Cluster: v1alpha1.ZarfComponentOnlyCluster{Architecture: arch}},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		architectures []string
+		components    []v1alpha1.ZarfComponent
+		expectedErr   string
+	}{
+		{
+			name:          "package architecture is present",
+			architectures: []string{"amd64"},
+			components:    []v1alpha1.ZarfComponent{newComponent("any", "")},
+		},
+		{
+			name:          "package architecture is missing",
+			architectures: []string{"arm64"},
+			components:    []v1alpha1.ZarfComponent{newComponent("any", "")},
+			expectedErr:   "this package architecture is amd64, but the target cluster only has the arm64 architecture(s)",
+		},
+		{
+			name:          "components restricted to the architecture of the cluster",
+			architectures: []string{"arm64"},
+			components:    []v1alpha1.ZarfComponent{newComponent("amd64", "amd64"), newComponent("arm64", "arm64")},
+		},
+		{
+			name:          "components restricted to other architectures than the cluster",
+			architectures: []string{"s390x"},
+			components:    []v1alpha1.ZarfComponent{newComponent("amd64", "amd64"), newComponent("arm64", "arm64")},
+			expectedErr:   "the components of this package with \"images\" are only for the amd64, arm64 architecture(s), but the target cluster only has the s390x architecture(s)",
+		},
+		{
+			name:          "unrestricted components need the package architecture",
+			architectures: []string{"arm64"},
+			components:    []v1alpha1.ZarfComponent{newComponent("any", ""), newComponent("arm64", "arm64")},
+			expectedErr:   "this package architecture is amd64, but the target cluster only has the arm64 architecture(s)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := testutil.TestContext(t)
+			cs := fake.NewClientset()
+			for i, arch := range tt.architectures {
+				node := &corev1.Node{
+					ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("node-%d", i)},
+					Status:     corev1.NodeStatus{NodeInfo: corev1.NodeSystemInfo{Architecture: arch}},
+				}
+				_, err := cs.CoreV1().Nodes().Create(ctx, node, metav1.CreateOptions{})
+				require.NoError(t, err)
+			}
+			c := &cluster.Cluster{Clientset: cs}
+			pkg := v1alpha1.ZarfPackage{
+				Metadata:   v1alpha1.ZarfMetadata{Architecture: "amd64"},
+				Components: tt.components,
+			}
+			err := verifyClusterCompatibility(ctx, c, pkg)
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestDeployComponentsClusterArchitecture(t *testing.T) {
+	t.Parallel()
+
+	newComponent := func(name, arch string) v1alpha1.ZarfComponent {
+		return v1alpha1.ZarfComponent{
+			Name: name,
+			Only: v1alpha1.ZarfComponentOnlyTarget{Cluster: v1alpha1.ZarfComponentOnlyCluster{Architecture: arch}},
+		}
+	}
+	pkgLayout := &layout.PackageLayout{Pkg: v1alpha1.ZarfPackage{
+		Metadata:   v1alpha1.ZarfMetadata{Name: "multi-arch"},
+		Components: []v1alpha1.ZarfComponent{newComponent("any", ""), newComponent("amd64", "amd64"), newComponent("arm64", "arm64")},
+	}}
+
+	tests := []struct {
+		name          string
+		architectures []string
+		connected     bool
+		expected      []string
+	}{
+		{
+			name:          "components for other architectures are skipped",
+			architectures: []string{"arm64", "arm64"},
+			connected:     true,
+			expected:      []string{"any", "arm64"},
+		},
+		{
+			name:          "mixed architectures keep the components of any of them",
+			architectures: []string{"amd64", "arm64"},
+			connected:     true,
+			expected:      []string{"any", "amd64", "arm64"},
+		},
+		{
+			name:      "nodes without an architecture keep every component",
+			connected: true,
+			expected:  []string{"any", "amd64", "arm64"},
+		},
+		{
+			name:     "components are not filtered without a cluster",
+			expected: []string{"any", "amd64", "arm64"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := testutil.TestContext(t)
+			d := deployer{vc: variables.New("zarf", nil, nil)}
+			if tt.connected {
+				cs := fake.NewClientset()
+				_, err := cs.CoreV1().Nodes().Create(ctx, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "unknown"}}, metav1.CreateOptions{})
+				require.NoError(t, err)
+				for i, arch := range tt.architectures {
+					node := &corev1.Node{
+						ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("node-%d", i)},
+						Status:     corev1.NodeStatus{NodeInfo: corev1.NodeSystemInfo{Architecture: arch}},
+					}
+					_, err := cs.CoreV1().Nodes().Create(ctx, node, metav1.CreateOptions{})
+					require.NoError(t, err)
+				}
+				d.c = &cluster.Cluster{Clientset: cs, Watcher: healthchecks.NewImmediateWatcher(status.CurrentStatus)}
+			}
+			deployedComponents, err := d.deployComponents(ctx, pkgLayout, DeployOptions{})
+			require.NoError(t, err)
+			names := []string{}
+			for _, component := range deployedComponents {
+				names = append(names, component.Name)
+			}
+			require.Equal(t, tt.expected, names)
+		})
+	}
+}
+
+func TestDeployComponentsStep(t *testing.T) {
+	newComponent := func(name string) v1alpha1.ZarfComponent {
+		return v1alpha1.ZarfComponent{
+			Name: name,
+			Actions: v1alpha1.ZarfComponentActions{
+				OnDeploy: v1alpha1.ZarfComponentActionSet{
+					OnSuccess: []v1alpha1.ZarfComponentAction{
+						{Cmd: "echo deployed", SetVariables: []v1alpha1.Variable{{Name: strings.ToUpper(name) + "_DEPLOYED"}}},
+					},
+					OnFailure: []v1alpha1.ZarfComponentAction{
+						{Cmd: "echo aborted", SetVariables: []v1alpha1.Variable{{Name: strings.ToUpper(name) + "_ABORTED"}}},
+					},
+				},
+			},
+		}
+	}
+	pkgLayout := &layout.PackageLayout{
+		Pkg: v1alpha1.ZarfPackage{
+			Components: []v1alpha1.ZarfComponent{newComponent("first"), newComponent("second"), newComponent("third")},
+		},
+	}
+
+	t.Run("continue through every component", func(t *testing.T) {
+		prompts := []string{}
+		d := deployer{
+			vc: variables.New("zarf", nil, nil),
+			step: func(_ context.Context, prompt string) (bool, error) {
+				prompts = append(prompts, prompt)
+				return true, nil
+			},
+		}
+		deployedComponents, err := d.deployComponents(context.Background(), pkgLayout, DeployOptions{})
+		require.NoError(t, err)
+		require.Len(t, deployedComponents, 3)
+		require.Equal(t, []string{
+			`Component "first" deployed. Continue with component "second"?`,
+			`Component "second" deployed. Continue with component "third"?`,
+		}, prompts)
+	})
+
+	t.Run("abort runs the failure actions", func(t *testing.T) {
+		vc := variables.New("zarf", nil, nil)
+		d := deployer{
+			vc: vc,
+			step: func(_ context.Context, _ string) (bool, error) {
+				return false, nil
+			},
+		}
+		_, err := d.deployComponents(context.Background(), pkgLayout, DeployOptions{})
+		require.EqualError(t, err, `deployment aborted after component "first", "second" and the remaining components were not deployed`)
+		_, ok := vc.GetSetVariable("FIRST_DEPLOYED")
+		require.True(t, ok)
+		_, ok = vc.GetSetVariable("FIRST_ABORTED")
+		require.True(t, ok)
+		_, ok = vc.GetSetVariable("SECOND_DEPLOYED")
+		require.False(t, ok)
+	})
+
+	t.Run("prompt errors stop the deployment", func(t *testing.T) {
+		d := deployer{
+			vc: variables.New("zarf", nil, nil),
+			step: func(_ context.Context, _ string) (bool, error) {
+				return false, errors.New("interrupt")
+			},
+		}
+		_, err := d.deployComponents(context.Background(), pkgLayout, DeployOptions{})
+		require.EqualError(t, err, "unable to prompt to continue the deployment: interrupt")
+	})
+}
+
+func TestDeployComponentsKeepGoing(t *testing.T) {
+	newComponent := func(name string, fail bool, dependsOn ...string) v1alpha1.ZarfComponent {
+		component := v1alpha1.ZarfComponent{
+			Name:      name,
+			DependsOn: dependsOn,
+			Actions: v1alpha1.ZarfComponentActions{
+				OnDeploy: v1alpha1.ZarfComponentActionSet{
+					OnSuccess: []v1alpha1.ZarfComponentAction{
+						{Cmd: "echo deployed", SetVariables: []v1alpha1.Variable{{Name: strings.ToUpper(name) + "_DEPLOYED"}}},
+					},
+				},
+			},
+		}
+		if fail {
+			component.Actions.OnDeploy.Before = []v1alpha1.ZarfComponentAction{{Cmd: "exit 1"}}
+		}
+		return component
+	}
+
+	tests := []struct {
+		name          string
+		components    []v1alpha1.ZarfComponent
+		keepGoing     bool
+		deployed      []string
+		notDeployed   []string
+		expectedError string
+	}{
+		{
+			name:          "failure aborts without keep going",
+			components:    []v1alpha1.ZarfComponent{newComponent("first", true), newComponent("second", false)},
+			notDeployed:   []string{"SECOND"},
+			expectedError: `unable to deploy component "first": unable to run component before action`,
+		},
+		{
+			name:          "independent failure",
+			components:    []v1alpha1.ZarfComponent{newComponent("first", true), newComponent("second", false)},
+			keepGoing:     true,
+			deployed:      []string{"SECOND"},
+			expectedError: "1 component(s) failed to deploy:\n - first: unable to run component before action",
+		},
+		{
+			name: "dependents of a failed component are skipped",
+			components: []v1alpha1.ZarfComponent{
+				newComponent("first", true),
+				newComponent("second", false, "first"),
+				newComponent("third", false, "second"),
+				newComponent("fourth", false),
+			},
+			keepGoing:     true,
+			deployed:      []string{"FOURTH"},
+			notDeployed:   []string{"SECOND", "THIRD"},
+			expectedError: "3 component(s) failed to deploy:\n - first: unable to run component before action: ",
+		},
+		{
+			name: "required component failure aborts",
+			components: []v1alpha1.ZarfComponent{
+				func() v1alpha1.ZarfComponent {
+					c := newComponent("first", true)
+					c.Required = helpers.BoolPtr(true)
+					return c
+				}(),
+				newComponent("second", false),
+			},
+			keepGoing:     true,
+			notDeployed:   []string{"SECOND"},
+			expectedError: `unable to deploy component "first"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vc := variables.New("zarf", nil, nil)
+			d := deployer{vc: vc}
+			pkgLayout := &layout.PackageLayout{Pkg: v1alpha1.ZarfPackage{Components: tt.components}}
+			_, err := d.deployComponents(context.Background(), pkgLayout, DeployOptions{KeepGoing: tt.keepGoing})
+			require.ErrorContains(t, err, tt.expectedError)
+			for _, name := range tt.deployed {
+				_, ok := vc.GetSetVariable(name + "_DEPLOYED")
+				require.True(t, ok, name)
+			}
+			for _, name := range tt.notDeployed {
+				_, ok := vc.GetSetVariable(name + "_DEPLOYED")
+				require.False(t, ok, name)
+			}
+		})
+	}
+
+	t.Run("skipped components are listed", func(t *testing.T) {
+		d := deployer{vc: variables.New("zarf", nil, nil)}
+		pkgLayout := &layout.PackageLayout{Pkg: v1alpha1.ZarfPackage{Components: []v1alpha1.ZarfComponent{
+			newComponent("first", true),
+			newComponent("second", false, "first"),
+			newComponent("third", false, "second"),
+		}}}
+		_, err := d.deployComponents(context.Background(), pkgLayout, DeployOptions{KeepGoing: true})
+		require.ErrorContains(t, err, "\n - second (skipped, depends on failed component first)\n - third (skipped, depends on failed component second)")
+	})
+}
+
+func TestDeployComponentsDeployTimeout(t *testing.T) {
+	t.Parallel()
+
+	pkgLayout := &layout.PackageLayout{Pkg: v1alpha1.ZarfPackage{Components: []v1alpha1.ZarfComponent{
+		{Name: "fast"},
+		{
+			Name: "slow",
+			Actions: v1alpha1.ZarfComponentActions{
+				OnDeploy: v1alpha1.ZarfComponentActionSet{
+					Defaults:  v1alpha1.ZarfComponentActionDefaults{MaxRetries: 3},
+					Before:    []v1alpha1.ZarfComponentAction{{Cmd: "sleep 30"}},
+					OnFailure: []v1alpha1.ZarfComponentAction{{Cmd: "echo failed", SetVariables: []v1alpha1.Variable{{Name: "SLOW_FAILED"}}}},
+				},
+			},
+		},
+		{
+			Name: "never",
+			Actions: v1alpha1.ZarfComponentActions{
+				OnDeploy: v1alpha1.ZarfComponentActionSet{
+					Before: []v1alpha1.ZarfComponentAction{{Cmd: "echo deployed", SetVariables: []v1alpha1.Variable{{Name: "NEVER_DEPLOYED"}}}},
+				},
+			},
+		},
+	}}}
+
+	vc := variables.New("zarf", nil, nil)
+	d := deployer{vc: vc}
+	start := time.Now()
+	_, err := d.deployComponentsWithTimeout(context.Background(), pkgLayout, DeployOptions{DeployTimeout: 500 * time.Millisecond})
+	require.ErrorContains(t, err, `deploy timed out after 500ms while running the onDeploy before actions of component "slow"`)
+	require.ErrorIs(t, err, errDeployTimeout)
+	// The retries of the cancelled action must not keep the deploy running
+	require.Less(t, time.Since(start), 10*time.Second)
+	_, ok := vc.GetSetVariable("NEVER_DEPLOYED")
+	require.False(t, ok)
+	// The failure actions still run once the deploy timeout is reached
+	failed, ok := vc.GetSetVariable("SLOW_FAILED")
+	require.True(t, ok)
+	require.Equal(t, "failed", failed.Value)
+}
+
+func TestDeployComponentsSpans(t *testing.T) {
+	t.Parallel()
+
+	pkgLayout := &layout.PackageLayout{Pkg: v1alpha1.ZarfPackage{Components: []v1alpha1.ZarfComponent{
+		{
+			Name: "first",
+			Actions: v1alpha1.ZarfComponentActions{
+				OnDeploy: v1alpha1.ZarfComponentActionSet{
+					Before: []v1alpha1.ZarfComponentAction{{Cmd: "echo first", Description: "say hello"}},
+				},
+			},
+		},
+		{
+			Name: "broken",
+			Actions: v1alpha1.ZarfComponentActions{
+				OnDeploy: v1alpha1.ZarfComponentActionSet{
+					Before: []v1alpha1.ZarfComponentAction{{Cmd: "exit 1"}},
+				},
+			},
+		},
+		{Name: "after", DependsOn: []string{"broken"}},
+	}}}
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	ctx := tracing.WithContext(context.Background(), tp)
+	d := deployer{vc: variables.New("zarf", nil, nil)}
+	_, err := d.deployComponents(ctx, pkgLayout, DeployOptions{KeepGoing: true})
+	require.ErrorContains(t, err, "2 component(s) failed to deploy")
+
+	type span struct {
+		spanID string
+		parent string
+		attrs  map[attribute.Key]string
+	}
+	spans := map[string]span{}
+	for _, stub := range exporter.GetSpans() {
+		attrs := map[attribute.Key]string{}
+		for _, attr := range stub.Attributes {
+			attrs[attr.Key] = attr.Value.Emit()
+		}
+		key := stub.Name + "/" + attrs[tracing.ComponentKey] + attrs[tracing.ActionKey]
+		spans[key] = span{spanID: stub.SpanContext.SpanID().String(), parent: stub.Parent.SpanID().String(), attrs: attrs}
+	}
+	require.Len(t, spans, 5)
+
+	require.Equal(t, tracing.OutcomeSucceeded, spans["deploy component/first"].attrs[tracing.OutcomeKey])
+	require.Equal(t, tracing.OutcomeFailed, spans["deploy component/broken"].attrs[tracing.OutcomeKey])
+	require.Equal(t, tracing.OutcomeSkipped, spans["deploy component/after"].attrs[tracing.OutcomeKey])
+
+	hello := spans["run action/say hello"]
+	require.Equal(t, tracing.OutcomeSucceeded, hello.attrs[tracing.OutcomeKey])
+	require.Equal(t, spans["deploy component/first"].spanID, hello.parent)
+	exit := spans["run action/exit 1"]
+	require.Equal(t, tracing.OutcomeFailed, exit.attrs[tracing.OutcomeKey])
+	require.Equal(t, spans["deploy component/broken"].spanID, exit.parent)
+}
+
+func TestDeployComponentsRetryPolicy(t *testing.T) {
+	t.Parallel()
+
+	countLines := func(t *testing.T, path string) int {
+		t.Helper()
+		b, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return 0
+		}
+		require.NoError(t, err)
+		return strings.Count(string(b), "\n")
+	}
+
+	t.Run("component that fails once succeeds on retry", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		marker := filepath.Join(dir, "marker")
+		failures := filepath.Join(dir, "failures")
+		pkgLayout := &layout.PackageLayout{Pkg: v1alpha1.ZarfPackage{Components: []v1alpha1.ZarfComponent{
+			{
+				Name:        "flaky",
+				RetryPolicy: &v1alpha1.ZarfComponentRetryPolicy{MaxAttempts: 3, OnFailureBetweenAttempts: true},
+				Actions: v1alpha1.ZarfComponentActions{
+					OnDeploy: v1alpha1.ZarfComponentActionSet{
+						Before:    []v1alpha1.ZarfComponentAction{{Cmd: "test -f " + marker + " || { touch " + marker + "; exit 1; }"}},
+						OnFailure: []v1alpha1.ZarfComponentAction{{Cmd: "echo failed >> " + failures}},
+					},
+				},
+			},
+		}}}
+
+		d := deployer{vc: variables.New("zarf", nil, nil)}
+		deployed, err := d.deployComponents(context.Background(), pkgLayout, DeployOptions{})
+		require.NoError(t, err)
+		require.Len(t, deployed, 1)
+		require.Equal(t, state.ComponentStatusSucceeded, deployed[0].Status)
+		require.Equal(t, 2, deployed[0].Attempts)
+		// The failure actions run between the attempts
+		require.Equal(t, 1, countLines(t, failures))
+	})
+
+	t.Run("component that exhausts its retries fails", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		attempts := filepath.Join(dir, "attempts")
+		failures := filepath.Join(dir, "failures")
+		pkgLayout := &layout.PackageLayout{Pkg: v1alpha1.ZarfPackage{Components: []v1alpha1.ZarfComponent{
+			{
+				Name:        "broken",
+				RetryPolicy: &v1alpha1.ZarfComponentRetryPolicy{MaxAttempts: 3},
+				Actions: v1alpha1.ZarfComponentActions{
+					OnDeploy: v1alpha1.ZarfComponentActionSet{
+						Before:    []v1alpha1.ZarfComponentAction{{Cmd: "echo attempt >> " + attempts + "; exit 1"}},
+						OnFailure: []v1alpha1.ZarfComponentAction{{Cmd: "echo failed >> " + failures}},
+					},
+				},
+			},
+		}}}
+
+		d := deployer{vc: variables.New("zarf", nil, nil)}
+		_, err := d.deployComponents(context.Background(), pkgLayout, DeployOptions{})
+		require.ErrorContains(t, err, `unable to deploy component "broken": failed after 3 attempts: unable to run component before action`)
+		require.Equal(t, 3, countLines(t, attempts))
+		// The failure actions only run once the retries are exhausted
+		require.Equal(t, 1, countLines(t, failures))
+	})
+}
+
+func TestRetryBackoff(t *testing.T) {
+	t.Parallel()
+
+	policy := v1alpha1.ZarfComponentRetryPolicy{MaxAttempts: 4, BackoffSeconds: 5}
+	require.Equal(t, 5*time.Second, retryBackoff(policy, 1))
+	require.Equal(t, 10*time.Second, retryBackoff(policy, 2))
+	require.Equal(t, 20*time.Second, retryBackoff(policy, 3))
+	require.Equal(t, time.Duration(0), retryBackoff(v1alpha1.ZarfComponentRetryPolicy{MaxAttempts: 2}, 1))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.False(t, waitForRetry(ctx, time.Minute))
+	require.True(t, waitForRetry(context.Background(), 0))
+}
+
+func TestSetsVariables(t *testing.T) {
+	t.Parallel()
+
+	require.False(t, setsVariables(nil))
+	require.False(t, setsVariables([]v1alpha1.ZarfComponentAction{{Cmd: "echo hello"}}))
+	require.True(t, setsVariables([]v1alpha1.ZarfComponentAction{{Cmd: "echo hello"}, {Cmd: "echo world", SetVariables: []v1alpha1.Variable{{Name: "WORLD"}}}}))
+	require.True(t, setsVariables([]v1alpha1.ZarfComponentAction{{Cmd: "echo world", DeprecatedSetVariable: "WORLD"}}))
+	require.True(t, setsVariables([]v1alpha1.ZarfComponentAction{{Cmd: `echo '{"a":1}'`, SetVariablesFromJSON: []v1alpha1.ZarfComponentActionJSONVariable{{Variable: v1alpha1.Variable{Name: "A"}, Path: ".a"}}}}))
+	require.True(t, setsVariables([]v1alpha1.ZarfComponentAction{{Cmd: "echo world", SetValues: []v1alpha1.SetValue{{Key: ".world"}}}}))
+}
+
+func TestDeployStepRequiresTerminal(t *testing.T) {
+	if interactive.IsTerminal() {
+		t.Skip("stdin is a terminal")
+	}
+	_, err := Deploy(context.Background(), &layout.PackageLayout{}, DeployOptions{Step: true})
+	require.EqualError(t, err, "--step requires a terminal to prompt between components")
+}
+
+func TestStepPrompt(t *testing.T) {
+	t.Parallel()
+
+	component := v1alpha1.ZarfComponent{
+		Name:      "podinfo",
+		Images:    []string{"ghcr.io/stefanprodan/podinfo:6.4.0"},
+		Manifests: []v1alpha1.ZarfManifest{{Name: "podinfo"}},
+	}
+	deployed := state.DeployedComponent{
+		Name:            "podinfo",
+		InstalledCharts: []state.InstalledChart{{Namespace: "podinfo", ChartName: "podinfo"}},
+	}
+	prompt := stepPrompt(component, deployed, "monitoring")
+	require.Equal(t, `Component "podinfo" deployed (charts: podinfo/podinfo; manifests: 1; images: 1). Continue with component "monitoring"?`, prompt)
+}
+
+func TestResolveSymlinks(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		files         []v1alpha1.ZarfFile
+		targets       []string
+		expected      [][]string
+		expectedError string
+	}{
+		{
+			name: "absolute and relative symlinks",
+			files: []v1alpha1.ZarfFile{
+				{Symlinks: []string{"/usr/local/bin/k9s", "k9s", "k9s-current"}},
+			},
+			targets:  []string{"/opt/k9s/bin/k9s-v0.40.0"},
+			expected: [][]string{{"/usr/local/bin/k9s", "/opt/k9s/bin/k9s", "/opt/k9s/bin/k9s-current"}},
+		},
+		{
+			name: "relative symlink in a sub directory",
+			files: []v1alpha1.ZarfFile{
+				{},
+				{Symlinks: []string{"current/config.yaml"}},
+			},
+			targets:  []string{"/opt/app/bin/app", "/opt/app/config-v2.yaml"},
+			expected: [][]string{nil, {"/opt/app/current/config.yaml"}},
+		},
+		{
+			name: "relative symlink escapes the target directory",
+			files: []v1alpha1.ZarfFile{
+				{Symlinks: []string{"../../etc/passwd"}},
+			},
+			targets:       []string{"/opt/app/app"},
+			expectedError: "symlink ../../etc/passwd escapes the directory /opt/app of its target",
+		},
+		{
+			name: "symlink to itself",
+			files: []v1alpha1.ZarfFile{
+				{Symlinks: []string{"/opt/app/app"}},
+			},
+			targets:       []string{"/opt/app/app"},
+			expectedError: "symlink /opt/app/app creates a loop with its target /opt/app/app",
+		},
+		{
+			name: "symlink contains its target",
+			files: []v1alpha1.ZarfFile{
+				{Symlinks: []string{"/opt/app"}},
+			},
+			targets:       []string{"/opt/app/bin/app"},
+			expectedError: "symlink /opt/app creates a loop with its target /opt/app/bin/app",
+		},
+		{
+			name: "symlink defined for multiple targets",
+			files: []v1alpha1.ZarfFile{
+				{Symlinks: []string{"/usr/local/bin/app"}},
+				{Symlinks: []string{"/usr/local/bin/app"}},
+			},
+			targets:       []string{"/opt/app-v1/app", "/opt/app-v2/app"},
+			expectedError: "symlink /usr/local/bin/app is defined for both /opt/app-v1/app and /opt/app-v2/app",
+		},
+		{
+			name: "symlink cycle between files",
+			files: []v1alpha1.ZarfFile{
+				{Symlinks: []string{"/opt/b"}},
+				{Symlinks: []string{"/opt/c"}},
+				{Symlinks: []string{"/opt/a"}},
+			},
+			targets:       []string{"/opt/a", "/opt/b", "/opt/c"},
+			expectedError: "is part of a symlink cycle",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			symlinks, err := resolveSymlinks(tt.files, tt.targets)
+			if tt.expectedError != "" {
+				require.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, symlinks)
+		})
+	}
+}
+
+func TestTemplateManifest(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		content       string
+		expected      string
+		expectedError string
+	}{
+		{
+			name: "substituted manifest",
+			content: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Values.app.name }}
+data:
+  domain: ###ZARF_VAR_DOMAIN###
+  replicas: "{{ .Variables.REPLICAS }}"
+`,
+			expected: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: podinfo
+data:
+  domain: example.com
+  replicas: "3"
+`,
+		},
+		{
+			name: "unresolved variable",
+			content: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Values.app.name }}
+data:
+  domain: ###ZARF_VAR_MISSING###
+  tls: ###ZARF_CONST_TLS###
+`,
+			expectedError: "manifest manifest.yaml references unresolved variables: ###ZARF_VAR_MISSING###, ###ZARF_CONST_TLS###",
+		},
+		{
+			name: "missing value",
+			content: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Values.app.missing.name }}
+`,
+			expectedError: "error applying Go templates to manifest manifest.yaml",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			vc := variables.New("zarf", nil, nil)
+			vc.SetVariable("DOMAIN", "example.com", false, false, v1alpha1.RawVariableType)
+			vc.SetVariable("REPLICAS", "3", false, false, v1alpha1.RawVariableType)
+			objs := template.NewObjects(value.Values{"app": map[string]any{"name": "podinfo"}}).
+				WithVariables(vc.GetSetVariableMap())
+			path := filepath.Join(t.TempDir(), "manifest.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+			// Variables are substituted before Go templates are applied, the same as during deploy
+			require.NoError(t, vc.ReplaceTextTemplate(path))
+			err := templateManifest(context.Background(), path, vc, objs)
+			if tt.expectedError != "" {
+				require.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			b, err := os.ReadFile(path)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, string(b))
+		})
+	}
+}
@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package filters contains core implementations of the ComponentFilterStrategy interface.
+package filters
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+)
+
+// ByClusterArchitecture creates a new filter that filters components based on the architectures of the nodes of the
+// cluster. Components restricted to a cluster architecture are kept when any of the nodes has that architecture.
+func ByClusterArchitecture(architectures ...string) ComponentFilterStrategy {
+	return &clusterArchitectureFilter{architectures}
+}
+
+// clusterArchitectureFilter filters components based on the architectures of the nodes of the cluster.
+type clusterArchitectureFilter struct {
+	architectures []string
+}
+
+// ErrClusterArchitectureRequired is returned when no cluster architecture is set.
+var ErrClusterArchitectureRequired = errors.New("at least one cluster architecture is required")
+
+// Apply applies the filter.
+func (f *clusterArchitectureFilter) Apply(pkg v1alpha1.ZarfPackage) ([]v1alpha1.ZarfComponent, error) {
+	filtered, _, err := f.Explain(pkg)
+	return filtered, err
+}
+
+// Explain applies the filter and records the components that are restricted to an architecture the cluster does not
+// have. Components without an architecture restriction are passed through without a decision.
+func (f *clusterArchitectureFilter) Explain(pkg v1alpha1.ZarfPackage) ([]v1alpha1.ZarfComponent, []ComponentDecision, error) {
+	if len(f.architectures) == 0 {
+		return nil, nil, ErrClusterArchitectureRequired
+	}
+
+	filtered := []v1alpha1.ZarfComponent{}
+	decisions := []ComponentDecision{}
+	for _, component := range pkg.Components {
+		if component.Only.Cluster.Architecture == "" {
+			filtered = append(filtered, component)
+			continue
+		}
+		decision := ComponentDecision{
+			Name:     component.Name,
+			Included: slices.Contains(f.architectures, component.Only.Cluster.Architecture),
+			Reason:   fmt.Sprintf("only for cluster architecture %s", component.Only.Cluster.Architecture),
+			Filter:   filterName(f),
+		}
+		decisions = append(decisions, decision)
+		if decision.Included {
+			filtered = append(filtered, component)
+		}
+	}
+	return filtered, decisions, nil
+}
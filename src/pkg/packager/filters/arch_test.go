@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package filters_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/pkg/packager/filters"
+)
+
+func TestClusterArchitectureFilter(t *testing.T) {
+	t.Parallel()
+
+	pkg := v1alpha1.ZarfPackage{
+		Components: []v1alpha1.ZarfComponent{
+			{Name: "any"},
+			{Name: "amd64", Only: v1alpha1.ZarfComponentOnlyTarget{Cluster: v1alpha1.ZarfComponentOnlyCluster{Architecture: "amd64"}}},
+			{Name: "arm64", Only: v1alpha1.ZarfComponentOnlyTarget{Cluster: v1alpha1.ZarfComponentOnlyCluster{Architecture: "arm64"}}},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		architectures []string
+		expected      []string
+		expectedErr   error
+	}{
+		{
+			name:          "single architecture",
+			architectures: []string{"arm64"},
+			expected:      []string{"any", "arm64"},
+		},
+		{
+			name:          "mixed architectures keep the components of any of them",
+			architectures: []string{"amd64", "arm64"},
+			expected:      []string{"any", "amd64", "arm64"},
+		},
+		{
+			name:          "architecture without components",
+			architectures: []string{"s390x"},
+			expected:      []string{"any"},
+		},
+		{
+			name:        "no architectures",
+			expectedErr: filters.ErrClusterArchitectureRequired,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result, err := filters.ByClusterArchitecture(tt.architectures...).Apply(pkg)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			names := []string{}
+			for _, component := range result {
+				names = append(names, component.Name)
+			}
+			require.Equal(t, tt.expected, names)
+		})
+	}
+
+	_, decisions, err := filters.Explain(filters.ByClusterArchitecture("amd64"), pkg)
+	require.NoError(t, err)
+	require.Equal(t, []filters.ComponentDecision{
+		{Name: "any", Included: true},
+		{Name: "amd64", Included: true, Reason: "only for cluster architecture amd64", Filter: "clusterArchitectureFilter"},
+		{Name: "arm64", Included: false, Reason: "only for cluster architecture arm64", Filter: "clusterArchitectureFilter"},
+	}, decisions)
+}
@@ -10,18 +10,27 @@ import (
 	"strings"
 
 	"github.com/agnivade/levenshtein"
-	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 	"github.com/zarf-dev/zarf/src/pkg/interactive"
 )
 
 // ForDeploy creates a new deployment filter.
 func ForDeploy(optionalComponents string, isInteractive bool) ComponentFilterStrategy {
-	requested := helpers.StringToSlice(optionalComponents)
+	requested := splitComponentRequests(optionalComponents)
 
 	return &deploymentFilter{
-		requested,
-		isInteractive,
+		requestedComponents: requested,
+		isInteractive:       isInteractive,
+	}
+}
+
+// ForSelectedComponents creates a new deployment filter for the optional components chosen from an interactive
+// selection. Default components are only deployed when they were chosen, and the components that a chosen component
+// depends on are deployed along with it.
+func ForSelectedComponents(chosen []string) ComponentFilterStrategy {
+	return &deploymentFilter{
+		requestedComponents: chosen,
+		onlyRequested:       true,
 	}
 }
 
@@ -29,6 +38,8 @@ func ForDeploy(optionalComponents string, isInteractive bool) ComponentFilterStr
 type deploymentFilter struct {
 	requestedComponents []string
 	isInteractive       bool
+	// onlyRequested leaves out the default components that were not requested
+	onlyRequested bool
 }
 
 // Errors for the deployment filter.
@@ -37,6 +48,10 @@ var (
 	ErrNoDefaultOrSelection = fmt.Errorf("no default or selected component found")
 	ErrNotFound             = fmt.Errorf("no compatible components found")
 	ErrSelectionCanceled    = fmt.Errorf("selection canceled")
+	ErrConflictingSelection = fmt.Errorf("cannot deploy conflicting components")
+	ErrExcludedDependency   = fmt.Errorf("cannot exclude a component that a selected component depends on")
+	ErrMissingDependency    = fmt.Errorf("component dependency not found")
+	ErrDependencyCycle      = fmt.Errorf("components depend on each other in a cycle")
 )
 
 // Apply applies the filter.
@@ -59,7 +74,8 @@ func (f *deploymentFilter) Apply(pkg v1alpha1.ZarfPackage) ([]v1alpha1.ZarfCompo
 		groupedComponents[groupKey] = append(groupedComponents[groupKey], component)
 	}
 
-	isPartial := len(f.requestedComponents) > 0 && f.requestedComponents[0] != ""
+	isPartial := f.onlyRequested || (len(f.requestedComponents) > 0 && f.requestedComponents[0] != "")
+	excludedComponents := map[string]bool{}
 
 	if isPartial {
 		matchedRequests := map[string]bool{}
@@ -82,8 +98,9 @@ func (f *deploymentFilter) Apply(pkg v1alpha1.ZarfPackage) ([]v1alpha1.ZarfCompo
 					if selectState == excluded {
 						// If the component was explicitly excluded, record the match and continue
 						matchedRequests[matchedRequest] = true
+						excludedComponents[component.Name] = true
 						continue
-					} else if selectState == unknown && component.Default && groupDefault == nil {
+					} else if selectState == unknown && component.Default && groupDefault == nil && !f.onlyRequested {
 						// If the component is default but not included or excluded, remember the default
 						groupDefault = &component
 					}
@@ -123,6 +140,14 @@ func (f *deploymentFilter) Apply(pkg v1alpha1.ZarfPackage) ([]v1alpha1.ZarfCompo
 		// Check that we have matched against all requests
 		for _, requestedComponent := range f.requestedComponents {
 			if _, ok := matchedRequests[requestedComponent]; !ok {
+				// Suggestions are meaningless for a pattern so list every component it could have matched
+				if isComponentPattern(requestedComponent) {
+					componentNames := []string{}
+					for _, c := range pkg.Components {
+						componentNames = append(componentNames, c.Name)
+					}
+					return nil, fmt.Errorf("%w: %s matches no component, available components (%s)", ErrNotFound, requestedComponent, strings.Join(componentNames, ", "))
+				}
 				closeEnough := []string{}
 				for _, c := range pkg.Components {
 					d := levenshtein.ComputeDistance(c.Name, requestedComponent)
@@ -188,5 +213,123 @@ func (f *deploymentFilter) Apply(pkg v1alpha1.ZarfPackage) ([]v1alpha1.ZarfCompo
 		}
 	}
 
+	selectedComponents, pulledInBy, err := resolveDependencies(pkg.Components, orderedComponentGroups, selectedComponents, excludedComponents)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkConflicts(selectedComponents, pulledInBy); err != nil {
+		return nil, err
+	}
+
 	return selectedComponents, nil
 }
+
+// resolveDependencies adds the components that the selected components depend on to the selection and orders it so
+// that every component comes after its dependencies. Components that were explicitly excluded are only pulled back in
+// for required components, otherwise excluding a dependency is an error. The returned map records the selected
+// component that brought each component in.
+func resolveDependencies(components []v1alpha1.ZarfComponent, orderedComponentGroups []string, selected []v1alpha1.ZarfComponent, excluded map[string]bool) ([]v1alpha1.ZarfComponent, map[string]string, error) {
+	byName := map[string]v1alpha1.ZarfComponent{}
+	for _, component := range components {
+		byName[component.Name] = component
+	}
+	pulledInBy := map[string]string{}
+	selectedGroups := map[string]string{}
+	for _, component := range selected {
+		pulledInBy[component.Name] = component.Name
+		if component.DeprecatedGroup != "" {
+			selectedGroups[component.DeprecatedGroup] = component.Name
+		}
+	}
+
+	resolved := slices.Clone(selected)
+	for _, component := range selected {
+		queue := []string{component.Name}
+		for len(queue) > 0 {
+			name := queue[0]
+			queue = queue[1:]
+			for _, dep := range byName[name].DependsOn {
+				if _, ok := pulledInBy[dep]; ok {
+					continue
+				}
+				depComponent, ok := byName[dep]
+				if !ok {
+					return nil, nil, fmt.Errorf("%w: %q depends on %q which is not available for this system", ErrMissingDependency, name, dep)
+				}
+				if excluded[dep] && !byName[name].IsRequired() {
+					return nil, nil, fmt.Errorf("%w: %q depends on %q", ErrExcludedDependency, name, dep)
+				}
+				if group := depComponent.DeprecatedGroup; group != "" {
+					if other, ok := selectedGroups[group]; ok {
+						return nil, nil, fmt.Errorf("%w: group: %s selected: %s, %s (a dependency of %q)", ErrMultipleSameGroup, group, other, dep, name)
+					}
+					selectedGroups[group] = dep
+				}
+				pulledInBy[dep] = component.Name
+				resolved = append(resolved, depComponent)
+				queue = append(queue, dep)
+			}
+		}
+	}
+
+	// Keep the package order where the dependencies allow it, placing grouped components where their group first appears
+	position := map[string]int{}
+	for _, component := range components {
+		groupKey := component.Name
+		if component.DeprecatedGroup != "" {
+			groupKey = component.DeprecatedGroup
+		}
+		position[component.Name] = slices.Index(orderedComponentGroups, groupKey)
+	}
+	slices.SortStableFunc(resolved, func(a, b v1alpha1.ZarfComponent) int {
+		return position[a.Name] - position[b.Name]
+	})
+
+	sorted := make([]v1alpha1.ZarfComponent, 0, len(resolved))
+	done := map[string]bool{}
+	for len(resolved) > 0 {
+		next := slices.IndexFunc(resolved, func(component v1alpha1.ZarfComponent) bool {
+			for _, dep := range component.DependsOn {
+				if !done[dep] {
+					return false
+				}
+			}
+			return true
+		})
+		if next == -1 {
+			names := []string{}
+			for _, component := range resolved {
+				names = append(names, component.Name)
+			}
+			return nil, nil, fmt.Errorf("%w: %s", ErrDependencyCycle, strings.Join(names, ", "))
+		}
+		done[resolved[next].Name] = true
+		sorted = append(sorted, resolved[next])
+		resolved = slices.Delete(resolved, next, next+1)
+	}
+	return sorted, pulledInBy, nil
+}
+
+// checkConflicts returns an error naming both components when a selected component, or a component one of them
+// depends on, conflicts with another component that would be deployed.
+func checkConflicts(selected []v1alpha1.ZarfComponent, pulledInBy map[string]string) error {
+	deployed := map[string]bool{}
+	for _, component := range selected {
+		deployed[component.Name] = true
+	}
+	describe := func(name string) string {
+		if pulledInBy[name] == name {
+			return fmt.Sprintf("%q", name)
+		}
+		return fmt.Sprintf("%q (a dependency of %q)", name, pulledInBy[name])
+	}
+	for _, component := range selected {
+		for _, conflict := range component.ConflictsWith {
+			if deployed[conflict] {
+				return fmt.Errorf("%w: %s conflicts with %s", ErrConflictingSelection, describe(component.Name), describe(conflict))
+			}
+		}
+	}
+	return nil
+}
@@ -217,3 +217,274 @@ func TestDeployFilter_Apply(t *testing.T) {
 		})
 	}
 }
+
+func TestDeployFilter_Conflicts(t *testing.T) {
+	t.Parallel()
+
+	pkg := v1alpha1.ZarfPackage{
+		Components: []v1alpha1.ZarfComponent{
+			{Name: "nginx-ingress", ConflictsWith: []string{"traefik-ingress"}},
+			{Name: "traefik-ingress"},
+			{Name: "traefik-dashboard", DependsOn: []string{"traefik-ingress"}},
+			{Name: "podinfo", Default: true},
+		},
+	}
+
+	tests := []struct {
+		name               string
+		optionalComponents string
+		expected           []string
+		expectedErr        string
+	}{
+		{
+			name:               "selection without a conflict",
+			optionalComponents: "nginx-ingress,podinfo",
+			expected:           []string{"nginx-ingress", "podinfo"},
+		},
+		{
+			name:               "dependency without a conflict",
+			optionalComponents: "traefik-dashboard",
+			expected:           []string{"traefik-ingress", "traefik-dashboard", "podinfo"},
+		},
+		{
+			name:               "selection with a conflict",
+			optionalComponents: "nginx-ingress,traefik-ingress",
+			expectedErr:        `"nginx-ingress" conflicts with "traefik-ingress"`,
+		},
+		{
+			name:               "conflict introduced by a dependency",
+			optionalComponents: "nginx-ingress,traefik-dashboard",
+			expectedErr:        `"nginx-ingress" conflicts with "traefik-ingress" (a dependency of "traefik-dashboard")`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result, err := ForDeploy(tt.optionalComponents, false).Apply(pkg)
+			if tt.expectedErr != "" {
+				require.ErrorIs(t, err, ErrConflictingSelection)
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			names := []string{}
+			for _, component := range result {
+				names = append(names, component.Name)
+			}
+			require.Equal(t, tt.expected, names)
+		})
+	}
+}
+
+func TestDeployFilter_Dependencies(t *testing.T) {
+	t.Parallel()
+
+	pkg := v1alpha1.ZarfPackage{
+		Components: []v1alpha1.ZarfComponent{
+			{Name: "database", DependsOn: []string{"database-operator"}},
+			{Name: "database-operator"},
+			{Name: "app", Required: helpers.BoolPtr(true), DependsOn: []string{"database"}},
+			{Name: "monitoring", DependsOn: []string{"database-operator"}},
+			{Name: "dashboard-a", DeprecatedGroup: "dashboard", Default: true},
+			{Name: "dashboard-b", DeprecatedGroup: "dashboard"},
+			{Name: "metrics", DependsOn: []string{"dashboard-b"}},
+			{Name: "logging", DependsOn: []string{"missing"}},
+		},
+	}
+
+	tests := []struct {
+		name               string
+		optionalComponents string
+		expected           []string
+		expectedErr        error
+	}{
+		{
+			name:               "dependencies are included and ordered first",
+			optionalComponents: "dashboard-a",
+			expected:           []string{"database-operator", "database", "app", "dashboard-a"},
+		},
+		{
+			name:               "excluded dependency of a required component is pulled back in",
+			optionalComponents: "-database,dashboard-a",
+			expected:           []string{"database-operator", "database", "app", "dashboard-a"},
+		},
+		{
+			name:               "excluded dependency of an optional component",
+			optionalComponents: "monitoring,-database-operator",
+			expectedErr:        ErrExcludedDependency,
+		},
+		{
+			name:               "dependency in a group with another selection",
+			optionalComponents: "metrics,dashboard-a",
+			expectedErr:        ErrMultipleSameGroup,
+		},
+		{
+			name:               "dependency selected from a group",
+			optionalComponents: "metrics,dashboard-b",
+			expected:           []string{"database-operator", "database", "app", "dashboard-b", "metrics"},
+		},
+		{
+			name:               "dependency not in the package",
+			optionalComponents: "logging,dashboard-a",
+			expectedErr:        ErrMissingDependency,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result, err := ForDeploy(tt.optionalComponents, false).Apply(pkg)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			names := []string{}
+			for _, component := range result {
+				names = append(names, component.Name)
+			}
+			require.Equal(t, tt.expected, names)
+		})
+	}
+}
+
+func TestSelectedComponentsFilter(t *testing.T) {
+	t.Parallel()
+
+	pkg := v1alpha1.ZarfPackage{
+		Components: []v1alpha1.ZarfComponent{
+			{Name: "base", Required: helpers.BoolPtr(true)},
+			{Name: "database", Default: true},
+			{Name: "logging", Default: true},
+			{Name: "app", DependsOn: []string{"database"}},
+			{Name: "docs"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		chosen   []string
+		expected []string
+	}{
+		{
+			name:     "unchosen default components are left out",
+			chosen:   []string{"logging", "docs"},
+			expected: []string{"base", "logging", "docs"},
+		},
+		{
+			name:     "unchosen dependencies of a chosen component are included",
+			chosen:   []string{"app"},
+			expected: []string{"base", "database", "app"},
+		},
+		{
+			name:     "nothing chosen only deploys the required components",
+			chosen:   []string{},
+			expected: []string{"base"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result, err := ForSelectedComponents(tt.chosen).Apply(pkg)
+			require.NoError(t, err)
+			names := []string{}
+			for _, component := range result {
+				names = append(names, component.Name)
+			}
+			require.Equal(t, tt.expected, names)
+		})
+	}
+}
+
+func TestDeployFilter_DependencyCycle(t *testing.T) {
+	t.Parallel()
+
+	pkg := v1alpha1.ZarfPackage{
+		Components: []v1alpha1.ZarfComponent{
+			{Name: "first", Default: true, DependsOn: []string{"second"}},
+			{Name: "second", DependsOn: []string{"first"}},
+		},
+	}
+	_, err := ForDeploy("", false).Apply(pkg)
+	require.ErrorIs(t, err, ErrDependencyCycle)
+	require.ErrorContains(t, err, "first, second")
+}
+
+func TestDeployFilter_Patterns(t *testing.T) {
+	t.Parallel()
+
+	pkg := v1alpha1.ZarfPackage{
+		Components: []v1alpha1.ZarfComponent{
+			{Name: "istio-base"},
+			{Name: "istio-cni"},
+			{Name: "istio-gateway"},
+			{Name: "podinfo"},
+			{Name: "ingress-a", DeprecatedGroup: "ingress", Default: true},
+			{Name: "ingress-b", DeprecatedGroup: "ingress"},
+		},
+	}
+
+	tests := []struct {
+		name               string
+		optionalComponents string
+		expected           []string
+		expectedErr        error
+		expectedErrMsg     string
+	}{
+		{
+			name:               "glob",
+			optionalComponents: "istio-*",
+			expected:           []string{"istio-base", "istio-cni", "istio-gateway", "ingress-a"},
+		},
+		{
+			name:               "regular expression",
+			optionalComponents: "/istio-(base|cni)/,podinfo",
+			expected:           []string{"istio-base", "istio-cni", "podinfo", "ingress-a"},
+		},
+		{
+			name:               "glob with a regular expression exclusion",
+			optionalComponents: "istio-*,-/.*-gateway/",
+			expected:           []string{"istio-base", "istio-cni", "ingress-a"},
+		},
+		{
+			name:               "regular expression matches the whole name",
+			optionalComponents: "/istio/",
+			expectedErr:        ErrNotFound,
+			expectedErrMsg:     "/istio/ matches no component, available components (istio-base, istio-cni, istio-gateway, podinfo, ingress-a, ingress-b)",
+		},
+		{
+			name:               "glob without a match",
+			optionalComponents: "linkerd-*",
+			expectedErr:        ErrNotFound,
+			expectedErrMsg:     "linkerd-* matches no component, available components (istio-base, istio-cni, istio-gateway, podinfo, ingress-a, ingress-b)",
+		},
+		{
+			name:               "pattern matching several components of a group",
+			optionalComponents: "ingress-*",
+			expectedErr:        ErrMultipleSameGroup,
+		},
+		{
+			name:               "invalid regular expression",
+			optionalComponents: "/istio-(/",
+			expectedErrMsg:     "invalid component regular expression /istio-(/",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result, err := ForDeploy(tt.optionalComponents, false).Apply(pkg)
+			if tt.expectedErr != nil || tt.expectedErrMsg != "" {
+				if tt.expectedErr != nil {
+					require.ErrorIs(t, err, tt.expectedErr)
+				}
+				require.ErrorContains(t, err, tt.expectedErrMsg)
+				return
+			}
+			require.NoError(t, err)
+			names := []string{}
+			for _, component := range result {
+				names = append(names, component.Name)
+			}
+			require.Equal(t, tt.expected, names)
+		})
+	}
+}
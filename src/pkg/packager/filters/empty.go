@@ -18,3 +18,8 @@ type emptyFilter struct{}
 func (f *emptyFilter) Apply(pkg v1alpha1.ZarfPackage) ([]v1alpha1.ZarfComponent, error) {
 	return pkg.Components, nil
 }
+
+// Explain returns the components unchanged without any decisions.
+func (f *emptyFilter) Explain(pkg v1alpha1.ZarfPackage) ([]v1alpha1.ZarfComponent, []ComponentDecision, error) {
+	return pkg.Components, nil, nil
+}
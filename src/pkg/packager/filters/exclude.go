@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package filters contains core implementations of the ComponentFilterStrategy interface.
+package filters
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+)
+
+// ErrExcludedRequired is returned when a required component is excluded by name.
+var ErrExcludedRequired = errors.New("cannot exclude a required component")
+
+// ByExcluded creates a new filter that keeps every component except the excluded ones. Entries are names, globs or
+// regular expressions as for the included filter, with or without the leading dash.
+func ByExcluded(excludedComponents []string) ComponentFilterStrategy {
+	return &excludedFilter{excludedComponents}
+}
+
+// excludedFilter keeps the components that do not match any of the excluded components.
+type excludedFilter struct {
+	excludedComponents []string
+}
+
+// Apply applies the filter.
+func (f *excludedFilter) Apply(pkg v1alpha1.ZarfPackage) ([]v1alpha1.ZarfComponent, error) {
+	result, _, err := f.Explain(pkg)
+	return result, err
+}
+
+// Explain applies the filter and records the components that were excluded. A required component that matches a
+// pattern is kept, while excluding one by name is an error. Components that are not excluded are passed through
+// without a decision.
+func (f *excludedFilter) Explain(pkg v1alpha1.ZarfPackage) ([]v1alpha1.ZarfComponent, []ComponentDecision, error) {
+	matchedRequests := map[string]bool{}
+	result := []v1alpha1.ZarfComponent{}
+	decisions := []ComponentDecision{}
+	for _, component := range pkg.Components {
+		selectState, matchedRequest, err := includedOrExcluded(component.Name, f.excludedComponents)
+		if err != nil {
+			return nil, nil, err
+		}
+		if selectState == unknown {
+			result = append(result, component)
+			continue
+		}
+		matchedRequests[matchedRequest] = true
+		decision := ComponentDecision{
+			Name:   component.Name,
+			Reason: fmt.Sprintf("excluded by %q", matchedRequest),
+			Filter: filterName(f),
+		}
+		if component.IsRequired() {
+			if !isComponentPattern(matchedRequest) {
+				return nil, nil, fmt.Errorf("%w: %s", ErrExcludedRequired, component.Name)
+			}
+			decision.Included = true
+			decision.Reason = fmt.Sprintf("required, kept although it matches %q", matchedRequest)
+			result = append(result, component)
+		}
+		decisions = append(decisions, decision)
+	}
+
+	for _, excludedComponent := range f.excludedComponents {
+		if excludedComponent == "" || matchedRequests[excludedComponent] {
+			continue
+		}
+		componentNames := []string{}
+		for _, c := range pkg.Components {
+			componentNames = append(componentNames, c.Name)
+		}
+		return nil, nil, fmt.Errorf("%w: %s matches no component, available components (%s)", ErrNotFound, excludedComponent, strings.Join(componentNames, ", "))
+	}
+	return result, decisions, nil
+}
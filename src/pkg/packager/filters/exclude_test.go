@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package filters
+
+import (
+	"testing"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+)
+
+func TestExcludedFilter(t *testing.T) {
+	t.Parallel()
+
+	pkg := v1alpha1.ZarfPackage{
+		Components: []v1alpha1.ZarfComponent{
+			{Name: "base", Required: helpers.BoolPtr(true)},
+			{Name: "logging"},
+			{Name: "monitoring"},
+			{Name: "docs"},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		excluded       []string
+		expectedResult []string
+		expectedErr    error
+	}{
+		{
+			name:           "no exclusions keeps every component",
+			excluded:       []string{},
+			expectedResult: []string{"base", "logging", "monitoring", "docs"},
+		},
+		{
+			name:           "names are excluded",
+			excluded:       []string{"logging", "-docs"},
+			expectedResult: []string{"base", "monitoring"},
+		},
+		{
+			name:           "a pattern keeps required components",
+			excluded:       []string{"*"},
+			expectedResult: []string{"base"},
+		},
+		{
+			name:        "a required component can not be excluded by name",
+			excluded:    []string{"base"},
+			expectedErr: ErrExcludedRequired,
+		},
+		{
+			name:        "unmatched exclusions are an error",
+			excluded:    []string{"loging"},
+			expectedErr: ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result, err := ByExcluded(tt.excluded).Apply(pkg)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			names := []string{}
+			for _, component := range result {
+				names = append(names, component.Name)
+			}
+			require.Equal(t, tt.expectedResult, names)
+		})
+	}
+
+	_, decisions, err := Explain(ByExcluded([]string{"*"}), pkg)
+	require.NoError(t, err)
+	require.Equal(t, ComponentDecision{Name: "base", Included: true, Reason: `required, kept although it matches "*"`, Filter: "excludedFilter"}, decisions[0])
+	require.Equal(t, ComponentDecision{Name: "logging", Reason: `excluded by "*"`, Filter: "excludedFilter"}, decisions[1])
+}
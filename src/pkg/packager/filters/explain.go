@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package filters contains core implementations of the ComponentFilterStrategy interface.
+package filters
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+)
+
+// ComponentDecision records whether a component was included and which filter made the decision.
+type ComponentDecision struct {
+	Name     string
+	Included bool
+	Reason   string
+	Filter   string
+}
+
+// ComponentFilterExplainer is implemented by filters that can report the decision they made for each component.
+// Filters may leave out components they pass through without considering them.
+type ComponentFilterExplainer interface {
+	ComponentFilterStrategy
+	Explain(v1alpha1.ZarfPackage) ([]v1alpha1.ZarfComponent, []ComponentDecision, error)
+}
+
+// Explain applies the filter and returns a decision for every component of the package, in package order.
+// Filters that do not implement ComponentFilterExplainer are explained by comparing their input and output.
+func Explain(filter ComponentFilterStrategy, pkg v1alpha1.ZarfPackage) ([]v1alpha1.ZarfComponent, []ComponentDecision, error) {
+	components, decisions, err := explain(filter, pkg)
+	if err != nil {
+		return nil, nil, err
+	}
+	byName := map[string]ComponentDecision{}
+	for _, decision := range decisions {
+		byName[decision.Name] = decision
+	}
+	result := make([]ComponentDecision, 0, len(pkg.Components))
+	for _, component := range pkg.Components {
+		decision, ok := byName[component.Name]
+		if !ok {
+			decision = ComponentDecision{Name: component.Name, Included: true}
+		}
+		result = append(result, decision)
+	}
+	return components, result, nil
+}
+
+func explain(filter ComponentFilterStrategy, pkg v1alpha1.ZarfPackage) ([]v1alpha1.ZarfComponent, []ComponentDecision, error) {
+	if explainer, ok := filter.(ComponentFilterExplainer); ok {
+		return explainer.Explain(pkg)
+	}
+	components, err := filter.Apply(pkg)
+	if err != nil {
+		return nil, nil, err
+	}
+	kept := map[string]bool{}
+	for _, component := range components {
+		kept[component.Name] = true
+	}
+	decisions := []ComponentDecision{}
+	for _, component := range pkg.Components {
+		if kept[component.Name] {
+			continue
+		}
+		decisions = append(decisions, ComponentDecision{
+			Name:   component.Name,
+			Reason: "excluded by the filter",
+			Filter: filterName(filter),
+		})
+	}
+	return components, decisions, nil
+}
+
+// filterName returns a short name for a filter, e.g. "deploymentFilter".
+func filterName(filter ComponentFilterStrategy) string {
+	name := fmt.Sprintf("%T", filter)
+	return name[strings.LastIndex(name, ".")+1:]
+}
@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package filters contains core implementations of the ComponentFilterStrategy interface.
+package filters
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+)
+
+func TestExplain(t *testing.T) {
+	t.Parallel()
+
+	pkg := v1alpha1.ZarfPackage{
+		Components: []v1alpha1.ZarfComponent{
+			{Name: "component1"},
+			{Name: "component2"},
+			{Name: "component3", Only: v1alpha1.ZarfComponentOnlyTarget{LocalOS: "windows"}},
+			{Name: "component4"},
+			{Name: "other"},
+		},
+	}
+
+	t.Run("mixed filter pipeline", func(t *testing.T) {
+		t.Parallel()
+		filter := Combine(ByLocalOS("linux"), BySelectState("comp*, -component2"), Empty(), ForDeploy("component1", false))
+		components, decisions, err := Explain(filter, pkg)
+		require.NoError(t, err)
+		require.Equal(t, []v1alpha1.ZarfComponent{{Name: "component1"}}, components)
+		require.Equal(t, []ComponentDecision{
+			{Name: "component1", Included: true, Reason: `requested by "comp*"`, Filter: "selectStateFilter"},
+			{Name: "component2", Included: false, Reason: `excluded by "-component2"`, Filter: "selectStateFilter"},
+			{Name: "component3", Included: false, Reason: "only for local OS windows", Filter: "localOSFilter"},
+			{Name: "component4", Included: false, Reason: "excluded by the filter", Filter: "deploymentFilter"},
+			{Name: "other", Included: false, Reason: "not requested", Filter: "selectStateFilter"},
+		}, decisions)
+
+		// Explain must agree with Apply
+		applied, err := filter.Apply(pkg)
+		require.NoError(t, err)
+		require.Equal(t, applied, components)
+	})
+
+	t.Run("default selection", func(t *testing.T) {
+		t.Parallel()
+		components, decisions, err := Explain(Combine(BySelectState(""), ByLocalOS("windows")), pkg)
+		require.NoError(t, err)
+		require.Len(t, components, 5)
+		require.Equal(t, ComponentDecision{Name: "component1", Included: true, Reason: "included by default as no components were requested", Filter: "selectStateFilter"}, decisions[0])
+		require.Equal(t, ComponentDecision{Name: "component3", Included: true, Reason: "only for local OS windows", Filter: "localOSFilter"}, decisions[2])
+	})
+
+	t.Run("filters without decisions", func(t *testing.T) {
+		t.Parallel()
+		components, decisions, err := Explain(Empty(), pkg)
+		require.NoError(t, err)
+		require.Equal(t, pkg.Components, components)
+		for i, decision := range decisions {
+			require.Equal(t, ComponentDecision{Name: pkg.Components[i].Name, Included: true}, decision)
+		}
+	})
+
+	t.Run("errors are returned", func(t *testing.T) {
+		t.Parallel()
+		_, _, err := Explain(Combine(ByLocalOS("")), pkg)
+		require.ErrorIs(t, err, ErrLocalOSRequired)
+	})
+}
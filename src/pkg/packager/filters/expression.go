@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package filters contains core implementations of the ComponentFilterStrategy interface.
+package filters
+
+import (
+	"fmt"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/pkg/variables"
+)
+
+// ByExpression creates a new filter that filters components based on their only.expression evaluated with the given
+// variable values. Variable names are expected to be upper case.
+func ByExpression(values map[string]string) ComponentFilterStrategy {
+	return &expressionFilter{values}
+}
+
+// expressionFilter filters components based on their only.expression.
+type expressionFilter struct {
+	values map[string]string
+}
+
+// Apply applies the filter.
+func (f *expressionFilter) Apply(pkg v1alpha1.ZarfPackage) ([]v1alpha1.ZarfComponent, error) {
+	filtered, _, err := f.Explain(pkg)
+	return filtered, err
+}
+
+// Explain applies the filter and records the components whose expression is false. Components without an expression
+// are passed through without a decision.
+func (f *expressionFilter) Explain(pkg v1alpha1.ZarfPackage) ([]v1alpha1.ZarfComponent, []ComponentDecision, error) {
+	filtered := []v1alpha1.ZarfComponent{}
+	decisions := []ComponentDecision{}
+	for _, component := range pkg.Components {
+		if component.Only.Expression == "" {
+			filtered = append(filtered, component)
+			continue
+		}
+		expr, err := variables.ParseExpression(component.Only.Expression)
+		if err != nil {
+			return nil, nil, fmt.Errorf("component %q has an invalid only.expression: %w", component.Name, err)
+		}
+		decision := ComponentDecision{
+			Name:     component.Name,
+			Included: expr.Evaluate(f.values),
+			Reason:   fmt.Sprintf("only when %s", component.Only.Expression),
+			Filter:   filterName(f),
+		}
+		decisions = append(decisions, decision)
+		if decision.Included {
+			filtered = append(filtered, component)
+		}
+	}
+	return filtered, decisions, nil
+}
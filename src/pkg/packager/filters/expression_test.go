@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package filters_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/pkg/packager/filters"
+)
+
+func TestExpressionFilter(t *testing.T) {
+	t.Parallel()
+
+	pkg := v1alpha1.ZarfPackage{
+		Components: []v1alpha1.ZarfComponent{
+			{Name: "always"},
+			{Name: "monitoring", Only: v1alpha1.ZarfComponentOnlyTarget{Expression: "ENABLE_MONITORING"}},
+			{Name: "eks", Only: v1alpha1.ZarfComponentOnlyTarget{Expression: `DISTRO == "eks" && !AIRGAP`}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		values   map[string]string
+		expected []string
+	}{
+		{
+			name:     "no variables set",
+			expected: []string{"always"},
+		},
+		{
+			name:     "single expression true",
+			values:   map[string]string{"ENABLE_MONITORING": "true", "DISTRO": "k3s"},
+			expected: []string{"always", "monitoring"},
+		},
+		{
+			name:     "all expressions true",
+			values:   map[string]string{"ENABLE_MONITORING": "TRUE", "DISTRO": "eks", "AIRGAP": "false"},
+			expected: []string{"always", "monitoring", "eks"},
+		},
+		{
+			name:     "negated variable",
+			values:   map[string]string{"DISTRO": "eks", "AIRGAP": "true"},
+			expected: []string{"always"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result, err := filters.ByExpression(tt.values).Apply(pkg)
+			require.NoError(t, err)
+			names := []string{}
+			for _, component := range result {
+				names = append(names, component.Name)
+			}
+			require.Equal(t, tt.expected, names)
+		})
+	}
+
+	_, decisions, err := filters.Explain(filters.ByExpression(map[string]string{"ENABLE_MONITORING": "true"}), pkg)
+	require.NoError(t, err)
+	require.Equal(t, []filters.ComponentDecision{
+		{Name: "always", Included: true},
+		{Name: "monitoring", Included: true, Reason: "only when ENABLE_MONITORING", Filter: "expressionFilter"},
+		{Name: "eks", Included: false, Reason: `only when DISTRO == "eks" && !AIRGAP`, Filter: "expressionFilter"},
+	}, decisions)
+
+	_, err = filters.ByExpression(nil).Apply(v1alpha1.ZarfPackage{
+		Components: []v1alpha1.ZarfComponent{{Name: "invalid", Only: v1alpha1.ZarfComponentOnlyTarget{Expression: "A &&"}}},
+	})
+	require.EqualError(t, err, `component "invalid" has an invalid only.expression: unexpected end of expression`)
+}
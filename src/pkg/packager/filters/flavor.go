@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package filters contains core implementations of the ComponentFilterStrategy interface.
+package filters
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+)
+
+// ErrFlavorNotFound is returned when no component of the package has the requested flavor.
+var ErrFlavorNotFound = errors.New("no components found for flavor")
+
+// ErrFlavorRequired is returned when no flavor is requested for a package that contains the components of several flavors.
+var ErrFlavorRequired = errors.New("the package contains several flavors, a flavor is required")
+
+// ByFlavor creates a new filter that keeps the components without a flavor and the components of the given flavor.
+// A flavor that no component has is an error so that a misspelled flavor does not silently drop components.
+// Without a flavor every component is kept, unless the package was created with the components of several flavors.
+func ByFlavor(flavor string) ComponentFilterStrategy {
+	return &flavorFilter{flavor}
+}
+
+// flavorFilter filters components based on their flavor.
+type flavorFilter struct {
+	flavor string
+}
+
+// Apply applies the filter.
+func (f *flavorFilter) Apply(pkg v1alpha1.ZarfPackage) ([]v1alpha1.ZarfComponent, error) {
+	filtered, _, err := f.Explain(pkg)
+	return filtered, err
+}
+
+// Explain applies the filter and records the components that are restricted to a flavor.
+// Components without a flavor are passed through without a decision.
+func (f *flavorFilter) Explain(pkg v1alpha1.ZarfPackage) ([]v1alpha1.ZarfComponent, []ComponentDecision, error) {
+	flavors := []string{}
+	for _, component := range pkg.Components {
+		if component.Only.Flavor != "" && !slices.Contains(flavors, component.Only.Flavor) {
+			flavors = append(flavors, component.Only.Flavor)
+		}
+	}
+	if f.flavor == "" {
+		if len(flavors) > 1 {
+			return nil, nil, fmt.Errorf("%w, available flavors (%s)", ErrFlavorRequired, strings.Join(flavors, ", "))
+		}
+		return pkg.Components, nil, nil
+	}
+	if !slices.Contains(flavors, f.flavor) {
+		return nil, nil, fmt.Errorf("%w: %s, available flavors (%s)", ErrFlavorNotFound, f.flavor, strings.Join(flavors, ", "))
+	}
+
+	filtered := []v1alpha1.ZarfComponent{}
+	decisions := []ComponentDecision{}
+	for _, component := range pkg.Components {
+		if component.Only.Flavor == "" {
+			filtered = append(filtered, component)
+			continue
+		}
+		decision := ComponentDecision{
+			Name:     component.Name,
+			Included: component.Only.Flavor == f.flavor,
+			Reason:   fmt.Sprintf("only for flavor %s", component.Only.Flavor),
+			Filter:   filterName(f),
+		}
+		decisions = append(decisions, decision)
+		if decision.Included {
+			filtered = append(filtered, component)
+		}
+	}
+	return filtered, decisions, nil
+}
@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package filters
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+)
+
+func TestFlavorFilter(t *testing.T) {
+	t.Parallel()
+
+	pkg := v1alpha1.ZarfPackage{
+		Components: []v1alpha1.ZarfComponent{
+			{Name: "shared"},
+			{Name: "enterprise-auth", Only: v1alpha1.ZarfComponentOnlyTarget{Flavor: "enterprise"}},
+			{Name: "community-auth", Only: v1alpha1.ZarfComponentOnlyTarget{Flavor: "community"}},
+		},
+	}
+
+	result, err := ByFlavor("community").Apply(pkg)
+	require.NoError(t, err)
+	require.Equal(t, []v1alpha1.ZarfComponent{pkg.Components[0], pkg.Components[2]}, result)
+
+	// Without a flavor a package with several flavors is an error
+	_, err = ByFlavor("").Apply(pkg)
+	require.ErrorIs(t, err, ErrFlavorRequired)
+	require.EqualError(t, err, "the package contains several flavors, a flavor is required, available flavors (enterprise, community)")
+
+	// Without a flavor a package created for a single flavor is kept whole
+	single := v1alpha1.ZarfPackage{Components: pkg.Components[:2]}
+	result, err = ByFlavor("").Apply(single)
+	require.NoError(t, err)
+	require.Equal(t, single.Components, result)
+
+	_, err = ByFlavor("comunity").Apply(pkg)
+	require.ErrorIs(t, err, ErrFlavorNotFound)
+	require.EqualError(t, err, "no components found for flavor: comunity, available flavors (enterprise, community)")
+}
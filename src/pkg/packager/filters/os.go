@@ -6,6 +6,7 @@ package filters
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 )
@@ -25,15 +26,34 @@ var ErrLocalOSRequired = errors.New("localOS is required")
 
 // Apply applies the filter.
 func (f *localOSFilter) Apply(pkg v1alpha1.ZarfPackage) ([]v1alpha1.ZarfComponent, error) {
+	filtered, _, err := f.Explain(pkg)
+	return filtered, err
+}
+
+// Explain applies the filter and records the components that are restricted to another OS.
+// Components without an OS restriction are passed through without a decision.
+func (f *localOSFilter) Explain(pkg v1alpha1.ZarfPackage) ([]v1alpha1.ZarfComponent, []ComponentDecision, error) {
 	if f.localOS == "" {
-		return nil, ErrLocalOSRequired
+		return nil, nil, ErrLocalOSRequired
 	}
 
 	filtered := []v1alpha1.ZarfComponent{}
+	decisions := []ComponentDecision{}
 	for _, component := range pkg.Components {
-		if component.Only.LocalOS == "" || component.Only.LocalOS == f.localOS {
+		if component.Only.LocalOS == "" {
+			filtered = append(filtered, component)
+			continue
+		}
+		decision := ComponentDecision{
+			Name:     component.Name,
+			Included: component.Only.LocalOS == f.localOS,
+			Reason:   fmt.Sprintf("only for local OS %s", component.Only.LocalOS),
+			Filter:   filterName(f),
+		}
+		decisions = append(decisions, decision)
+		if decision.Included {
 			filtered = append(filtered, component)
 		}
 	}
-	return filtered, nil
+	return filtered, decisions, nil
 }
@@ -5,13 +5,14 @@
 package filters
 
 import (
-	"github.com/defenseunicorns/pkg/helpers/v2"
+	"fmt"
+
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 )
 
 // BySelectState creates a new simple included filter.
 func BySelectState(optionalComponents string) ComponentFilterStrategy {
-	requested := helpers.StringToSlice(optionalComponents)
+	requested := splitComponentRequests(optionalComponents)
 
 	return &selectStateFilter{
 		requested,
@@ -25,21 +26,42 @@ type selectStateFilter struct {
 
 // Apply applies the filter.
 func (f *selectStateFilter) Apply(pkg v1alpha1.ZarfPackage) ([]v1alpha1.ZarfComponent, error) {
+	result, _, err := f.Explain(pkg)
+	return result, err
+}
+
+// Explain applies the filter and records whether each component was requested, excluded or included by default.
+func (f *selectStateFilter) Explain(pkg v1alpha1.ZarfPackage) ([]v1alpha1.ZarfComponent, []ComponentDecision, error) {
 	isPartial := len(f.requestedComponents) > 0 && f.requestedComponents[0] != ""
 	result := []v1alpha1.ZarfComponent{}
+	decisions := []ComponentDecision{}
 	for _, component := range pkg.Components {
-		selectState := included
+		decision := ComponentDecision{
+			Name:     component.Name,
+			Included: true,
+			Reason:   "included by default as no components were requested",
+			Filter:   filterName(f),
+		}
 		if isPartial {
-			var err error
-			selectState, _, err = includedOrExcluded(component.Name, f.requestedComponents)
+			selectState, matchedRequest, err := includedOrExcluded(component.Name, f.requestedComponents)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
+			}
+			switch selectState {
+			case included:
+				decision.Reason = fmt.Sprintf("requested by %q", matchedRequest)
+			case excluded:
+				decision.Included = false
+				decision.Reason = fmt.Sprintf("excluded by %q", matchedRequest)
+			default:
+				decision.Included = false
+				decision.Reason = "not requested"
 			}
 		}
-		if selectState != included {
-			continue
+		decisions = append(decisions, decision)
+		if decision.Included {
+			result = append(result, component)
 		}
-		result = append(result, component)
 	}
-	return result, nil
+	return result, decisions, nil
 }
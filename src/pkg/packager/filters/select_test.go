@@ -47,6 +47,19 @@ func Test_selectStateFilter_Apply(t *testing.T) {
 			},
 			expectedError: nil,
 		},
+		{
+			name:                "Test when requestedComponents contains a regular expression with a comma",
+			requestedComponents: "/^db-[0-9]{1,3}$/",
+			components: []v1alpha1.ZarfComponent{
+				{Name: "db-1"},
+				{Name: "db-1000"},
+				{Name: "web"},
+			},
+			expectedResult: []v1alpha1.ZarfComponent{
+				{Name: "db-1"},
+			},
+			expectedError: nil,
+		},
 		{
 			name:                "Test when requestedComponents contains an excluded component name",
 			requestedComponents: "comp*, -component2",
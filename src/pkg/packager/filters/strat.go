@@ -35,6 +35,33 @@ func (f *comboFilter) Apply(pkg v1alpha1.ZarfPackage) ([]v1alpha1.ZarfComponent,
 	return result.Components, nil
 }
 
+// Explain applies the filters in sequence and returns the decision of the filter that excluded each component, or
+// the decision of the last filter that considered it for the components that are included.
+func (f *comboFilter) Explain(pkg v1alpha1.ZarfPackage) ([]v1alpha1.ZarfComponent, []ComponentDecision, error) {
+	result := pkg
+	decisions := []ComponentDecision{}
+	for _, filter := range f.filters {
+		components, filterDecisions, err := explain(filter, result)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error applying filter %T: %w", filter, err)
+		}
+		decisions = append(decisions, filterDecisions...)
+		result.Components = components
+	}
+	// Later decisions override earlier ones as a component is only considered by later filters while it is included
+	latest := map[string]ComponentDecision{}
+	for _, decision := range decisions {
+		latest[decision.Name] = decision
+	}
+	merged := []ComponentDecision{}
+	for _, component := range pkg.Components {
+		if decision, ok := latest[component.Name]; ok {
+			merged = append(merged, decision)
+		}
+	}
+	return result.Components, merged, nil
+}
+
 // Combine creates a new filter that applies a sequence of filters.
 func Combine(filters ...ComponentFilterStrategy) ComponentFilterStrategy {
 	return &comboFilter{filters}
@@ -57,3 +57,26 @@ func TestCombine(t *testing.T) {
 	_, err = combo.Apply(pkg)
 	require.Error(t, err)
 }
+
+func TestCombineFlavorAndSelection(t *testing.T) {
+	pkg := v1alpha1.ZarfPackage{
+		Components: []v1alpha1.ZarfComponent{
+			{Name: "shared"},
+			{Name: "app-upstream", Only: v1alpha1.ZarfComponentOnlyTarget{Flavor: "upstream"}},
+			{Name: "app-registry1", Only: v1alpha1.ZarfComponentOnlyTarget{Flavor: "registry1"}},
+			{Name: "tools-upstream", Only: v1alpha1.ZarfComponentOnlyTarget{Flavor: "upstream"}},
+		},
+	}
+
+	// The components of the flavor that were also selected
+	result, err := Combine(ByFlavor("upstream"), BySelectState("shared,app-*")).Apply(pkg)
+	require.NoError(t, err)
+	require.Equal(t, []v1alpha1.ZarfComponent{
+		{Name: "shared"},
+		{Name: "app-upstream", Only: v1alpha1.ZarfComponentOnlyTarget{Flavor: "upstream"}},
+	}, result)
+
+	// Filters run left to right so a later filter only sees what the earlier ones kept
+	_, err = Combine(ByFlavor("upstream"), ForDeploy("app-registry1", false)).Apply(pkg)
+	require.ErrorIs(t, err, ErrNotFound)
+}
@@ -5,7 +5,9 @@
 package filters
 
 import (
+	"fmt"
 	"path"
+	"regexp"
 	"strings"
 )
 
@@ -21,9 +23,7 @@ func includedOrExcluded(componentName string, requestedComponentNames []string)
 	// Check if the component has a leading dash indicating it should be excluded - this is done first so that exclusions precede inclusions
 	for _, requestedComponent := range requestedComponentNames {
 		if strings.HasPrefix(requestedComponent, "-") {
-			// If the component glob matches one of the requested components, then return true
-			// This supports globbing with "path" in order to have the same behavior across OSes (if we ever allow namespaced components with /)
-			matched, err := path.Match(strings.TrimPrefix(requestedComponent, "-"), componentName)
+			matched, err := matchComponentName(strings.TrimPrefix(requestedComponent, "-"), componentName)
 			if err != nil {
 				return unknown, "", err
 			}
@@ -34,9 +34,7 @@ func includedOrExcluded(componentName string, requestedComponentNames []string)
 	}
 	// Check if the component matches a glob pattern and should be included
 	for _, requestedComponent := range requestedComponentNames {
-		// If the component glob matches one of the requested components, then return true
-		// This supports globbing with "path" in order to have the same behavior across OSes (if we ever allow namespaced components with /)
-		matched, err := path.Match(requestedComponent, componentName)
+		matched, err := matchComponentName(requestedComponent, componentName)
 		if err != nil {
 			return unknown, "", err
 		}
@@ -48,3 +46,74 @@ func includedOrExcluded(componentName string, requestedComponentNames []string)
 	// All other cases we don't know if we should include or exclude yet
 	return unknown, "", nil
 }
+
+// matchComponentName reports whether the component name matches a requested component. A request wrapped in slashes,
+// e.g. /istio-(base|cni)/, is a regular expression that must match the whole name, all other requests are globs.
+func matchComponentName(requestedComponent, componentName string) (bool, error) {
+	if expr, ok := componentRegexp(requestedComponent); ok {
+		re, err := regexp.Compile("^(?:" + expr + ")$")
+		if err != nil {
+			return false, fmt.Errorf("invalid component regular expression %s: %w", requestedComponent, err)
+		}
+		return re.MatchString(componentName), nil
+	}
+	// This supports globbing with "path" in order to have the same behavior across OSes (if we ever allow namespaced components with /)
+	return path.Match(requestedComponent, componentName)
+}
+
+// componentRegexp returns the regular expression of a request wrapped in slashes.
+func componentRegexp(requestedComponent string) (string, bool) {
+	if len(requestedComponent) < 2 || !strings.HasPrefix(requestedComponent, "/") || !strings.HasSuffix(requestedComponent, "/") {
+		return "", false
+	}
+	return requestedComponent[1 : len(requestedComponent)-1], true
+}
+
+// isComponentPattern reports whether a request is a glob or regular expression rather than a component name.
+func isComponentPattern(requestedComponent string) bool {
+	requestedComponent = strings.TrimPrefix(requestedComponent, "-")
+	if _, ok := componentRegexp(requestedComponent); ok {
+		return true
+	}
+	return strings.ContainsAny(requestedComponent, `*?[\`)
+}
+
+// splitComponentRequests splits the comma separated component requests of a flag. Commas inside a regular expression
+// wrapped in slashes, e.g. /^db-[0-9]{1,3}$/, do not split the request. Names and globs are lower cased like component
+// names, regular expressions are kept as given so that classes such as \S keep their meaning.
+func splitComponentRequests(s string) []string {
+	requests := []string{}
+	if s == "" {
+		return requests
+	}
+	for {
+		request, rest, found := cutComponentRequest(s)
+		requests = append(requests, request)
+		if !found {
+			return requests
+		}
+		s = rest
+	}
+}
+
+// cutComponentRequest cuts the first component request from s and reports whether a comma followed it. A request that
+// starts with a slash ends at the first slash that is followed by a comma or the end of s.
+func cutComponentRequest(s string) (string, string, bool) {
+	body := strings.TrimPrefix(strings.TrimLeft(s, " "), "-")
+	if strings.HasPrefix(body, "/") {
+		for i := len(s) - len(body) + 1; i < len(s); i++ {
+			if s[i] != '/' {
+				continue
+			}
+			after := strings.TrimLeft(s[i+1:], " ")
+			if after == "" {
+				return strings.TrimSpace(s), "", false
+			}
+			if after[0] == ',' {
+				return strings.TrimSpace(s[:i+1]), after[1:], true
+			}
+		}
+	}
+	request, rest, found := strings.Cut(s, ",")
+	return strings.ToLower(strings.TrimSpace(request)), rest, found
+}
@@ -77,6 +77,27 @@ func Test_includedOrExcluded(t *testing.T) {
 			wantState:               unknown,
 			wantRequestedComponent:  "",
 		},
+		{
+			name:                    "Test when component is included via regular expression",
+			componentName:           "example",
+			requestedComponentNames: []string{"/ex(ample|tra)/"},
+			wantState:               included,
+			wantRequestedComponent:  "/ex(ample|tra)/",
+		},
+		{
+			name:                    "Test when component is excluded via regular expression",
+			componentName:           "example",
+			requestedComponentNames: []string{"ex*", "-/e.*/"},
+			wantState:               excluded,
+			wantRequestedComponent:  "-/e.*/",
+		},
+		{
+			name:                    "Test when regular expression only matches part of the component",
+			componentName:           "example",
+			requestedComponentNames: []string{"/ex/"},
+			wantState:               unknown,
+			wantRequestedComponent:  "",
+		},
 		{
 			name:                    "Test error",
 			componentName:           "example",
@@ -104,3 +125,50 @@ func Test_includedOrExcluded(t *testing.T) {
 		})
 	}
 }
+
+func TestSplitComponentRequests(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		requests string
+		expected []string
+	}{
+		{
+			name:     "empty",
+			requests: "",
+			expected: []string{},
+		},
+		{
+			name:     "names and globs",
+			requests: "Podinfo, istio-*,-db",
+			expected: []string{"podinfo", "istio-*", "-db"},
+		},
+		{
+			name:     "regular expression with a comma",
+			requests: "/^db-[0-9]{1,3}$/",
+			expected: []string{"/^db-[0-9]{1,3}$/"},
+		},
+		{
+			name:     "regular expressions among names",
+			requests: "web, -/^db-[0-9]{1,3}$/ ,/(a|b),c/,cache",
+			expected: []string{"web", "-/^db-[0-9]{1,3}$/", "/(a|b),c/", "cache"},
+		},
+		{
+			name:     "regular expressions keep their case",
+			requests: `/db-\S+/`,
+			expected: []string{`/db-\S+/`},
+		},
+		{
+			name:     "unterminated regular expression",
+			requests: "/db-[0-9]{1,3}",
+			expected: []string{"/db-[0-9]{1", "3}"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.expected, splitComponentRequests(tt.requests))
+		})
+	}
+}
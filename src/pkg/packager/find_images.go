@@ -87,8 +87,6 @@ type ComponentImageScan struct {
 // FindImages iterates over the manifests and charts within each component to find any container images
 // It returns a FindImageResults which contains a scan result for each component
 func FindImages(ctx context.Context, packagePath string, opts FindImagesOptions) (_ []ComponentImageScan, err error) {
-	l := logger.From(ctx)
-
 	opts.CachePath, err = utils.ResolveCachePath(opts.CachePath)
 	if err != nil {
 		return nil, err
@@ -107,6 +105,18 @@ func FindImages(ctx context.Context, packagePath string, opts FindImagesOptions)
 		return nil, err
 	}
 
+	pkgPath, err := layout.ResolvePackagePath(packagePath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to access package path %q: %w", packagePath, err)
+	}
+	return findImagesInPackage(ctx, pkg, pkgPath.BaseDir, opts, true)
+}
+
+// findImagesInPackage finds the images in the charts and manifests of a loaded package definition whose relative paths
+// are resolved against baseDir. Potential matches are only looked up in their registries when checkPotentialMatches is true.
+func findImagesInPackage(ctx context.Context, pkg v1alpha1.ZarfPackage, baseDir string, opts FindImagesOptions, checkPotentialMatches bool) (_ []ComponentImageScan, err error) {
+	l := logger.From(ctx)
+
 	s, err := state.Default()
 	if err != nil {
 		return nil, err
@@ -119,11 +129,7 @@ func FindImages(ctx context.Context, packagePath string, opts FindImagesOptions)
 		return nil, err
 	}
 
-	pkgPath, err := layout.ResolvePackagePath(packagePath)
-	if err != nil {
-		return nil, fmt.Errorf("unable to access package path %q: %w", packagePath, err)
-	}
-	vals, err := loadPackageValues(ctx, pkg, pkgPath.BaseDir, opts.Values)
+	vals, err := loadPackageValues(ctx, pkg, baseDir, opts.Values)
 	if err != nil {
 		return nil, err
 	}
@@ -178,7 +184,7 @@ func FindImages(ctx context.Context, packagePath string, opts FindImagesOptions)
 		matchedImages := map[string]bool{}
 		maybeImages := map[string]bool{}
 		for _, zarfChart := range component.Charts {
-			chartResource, values, err := getTemplatedChart(ctx, zarfChart, component.Name, pkgPath.BaseDir, compBuildPath, variableConfig, vals, opts.KubeVersionOverride, opts.IsInteractive, opts.CachePath, opts.RemoteOptions)
+			chartResource, values, err := getTemplatedChart(ctx, zarfChart, component.Name, baseDir, compBuildPath, variableConfig, vals, opts.KubeVersionOverride, opts.IsInteractive, opts.CachePath, opts.RemoteOptions)
 			if err != nil {
 				return nil, err
 			}
@@ -221,7 +227,7 @@ func FindImages(ctx context.Context, packagePath string, opts FindImagesOptions)
 			}
 		}
 		for _, manifest := range component.Manifests {
-			manifestResources, err := getTemplatedManifests(ctx, manifest, pkgPath.BaseDir, compBuildPath, variableConfig, vals, pkg)
+			manifestResources, err := getTemplatedManifests(ctx, manifest, baseDir, compBuildPath, variableConfig, vals, pkg)
 			if err != nil {
 				return nil, err
 			}
@@ -261,7 +267,7 @@ func FindImages(ctx context.Context, packagePath string, opts FindImagesOptions)
 
 		// Handle the "maybes"
 		var validMaybeImages []string
-		if len(sortedExpectedImages) > 0 {
+		if checkPotentialMatches && len(sortedExpectedImages) > 0 {
 			for _, image := range sortedExpectedImages {
 				if descriptor, err := crane.Head(image, images.WithGlobalInsecureFlag(opts.InsecureSkipTLSVerify)...); err != nil {
 					// Test if this is a real image, if not just quiet log to debug, this is normal
@@ -4,6 +4,7 @@
 package packager
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -20,6 +21,7 @@ import (
 	"github.com/zarf-dev/zarf/src/pkg/packager/layout"
 	"github.com/zarf-dev/zarf/src/pkg/packager/load"
 	"github.com/zarf-dev/zarf/src/pkg/state"
+	"github.com/zarf-dev/zarf/src/pkg/transform"
 	"github.com/zarf-dev/zarf/src/pkg/utils"
 	"github.com/zarf-dev/zarf/src/pkg/value"
 	"github.com/zarf-dev/zarf/src/pkg/variables"
@@ -175,13 +177,17 @@ func InspectPackageResources(ctx context.Context, pkgLayout *layout.PackageLayou
 						return nil, fmt.Errorf("error templating the manifest: %w", err)
 					}
 					if manifest.IsTemplate() {
-						objs := tmpl.NewObjects(vals).
+						manifestVals, err := manifestTemplateValues(manifest, variableConfig, vals)
+						if err != nil {
+							return nil, fmt.Errorf("unable to set the variables of manifest %s: %w", manifest.Name, err)
+						}
+						objs := tmpl.NewObjects(manifestVals).
 							WithPackage(pkgLayout.Pkg).
 							WithBuild(pkgLayout.Pkg.Build).
 							WithVariables(variableConfig.GetSetVariableMap()).
 							WithConstants(variableConfig.GetConstants())
-						if err := tmpl.ApplyToFile(ctx, path, path, objs); err != nil {
-							return nil, fmt.Errorf("error applying Go templates to manifest: %w", err)
+						if err := templateManifest(ctx, path, variableConfig, objs); err != nil {
+							return nil, err
 						}
 					}
 					contents, err := os.ReadFile(path)
@@ -201,6 +207,71 @@ func InspectPackageResources(ctx context.Context, pkgLayout *layout.PackageLayou
 	return resources, nil
 }
 
+// ImageMapping maps an image declared in a package to the reference it is rewritten to in the Zarf registry.
+type ImageMapping struct {
+	Component string `json:"component"`
+	Original  string `json:"original"`
+	Rewritten string `json:"rewritten"`
+}
+
+// InspectImageMappings returns the rewritten internal registry reference for each image in the package.
+// The rewrite uses the same transform as the Zarf agent so the output matches what will run in-cluster.
+func InspectImageMappings(pkg v1alpha1.ZarfPackage, registryAddress string) ([]ImageMapping, error) {
+	if registryAddress == "" {
+		return nil, errors.New("registry address must be provided to rewrite images")
+	}
+	var mappings []ImageMapping
+	for _, component := range pkg.Components {
+		for _, image := range helpers.Unique(component.GetImages()) {
+			rewritten, err := transform.ImageTransformHost(registryAddress, image)
+			if err != nil {
+				return nil, fmt.Errorf("unable to rewrite image %s in component %s: %w", image, component.Name, err)
+			}
+			mappings = append(mappings, ImageMapping{
+				Component: component.Name,
+				Original:  image,
+				Rewritten: rewritten,
+			})
+		}
+	}
+	return mappings, nil
+}
+
+// ClusterRequirements reports whether deploying a package, and each of its components, needs a Kubernetes cluster.
+type ClusterRequirements struct {
+	// RequiresCluster is true when any component requires a cluster
+	RequiresCluster bool                          `json:"requiresCluster"`
+	Components      []ComponentClusterRequirement `json:"components"`
+}
+
+// ComponentClusterRequirement reports whether deploying a component needs a Kubernetes cluster.
+type ComponentClusterRequirement struct {
+	Name            string `json:"name"`
+	Required        bool   `json:"required"`
+	RequiresCluster bool   `json:"requiresCluster"`
+}
+
+// InspectClusterRequirements returns whether the components of the package require a cluster to deploy.
+// Imports must already be resolved, as the assets of an imported component are not known until then.
+func InspectClusterRequirements(pkg v1alpha1.ZarfPackage) (ClusterRequirements, error) {
+	requirements := ClusterRequirements{
+		Components: []ComponentClusterRequirement{},
+	}
+	for _, component := range pkg.Components {
+		if component.Import.Path != "" || component.Import.URL != "" {
+			return ClusterRequirements{}, fmt.Errorf("component %s has an unresolved import", component.Name)
+		}
+		requiresCluster := component.RequiresCluster()
+		requirements.RequiresCluster = requirements.RequiresCluster || requiresCluster
+		requirements.Components = append(requirements.Components, ComponentClusterRequirement{
+			Name:            component.Name,
+			Required:        component.IsRequired(),
+			RequiresCluster: requiresCluster,
+		})
+	}
+	return requirements, nil
+}
+
 func templateValuesFiles(chart v1alpha1.ZarfChart, valuesDir string, variableConfig *variables.VariableConfig) error {
 	for idx := range chart.ValuesFiles {
 		valueFilePath := helm.StandardValuesName(valuesDir, chart, idx)
@@ -356,7 +427,11 @@ func getTemplatedManifests(ctx context.Context, manifest v1alpha1.ZarfManifest,
 		var content []byte
 		if manifest.IsTemplate() {
 			// Create template objects with values, metadata, build, constants, and variables
-			objs := tmpl.NewObjects(vals).
+			manifestVals, err := manifestTemplateValues(manifest, variableConfig, vals)
+			if err != nil {
+				return fmt.Errorf("unable to set the variables of manifest %s: %w", manifest.Name, err)
+			}
+			objs := tmpl.NewObjects(manifestVals).
 				WithPackage(pkg).
 				WithVariables(variableConfig.GetSetVariableMap())
 
@@ -374,6 +449,9 @@ func getTemplatedManifests(ctx context.Context, manifest v1alpha1.ZarfManifest,
 			if err := tmpl.ApplyToFile(ctx, manifestFile, tmpFile, objs); err != nil {
 				return fmt.Errorf("error applying Go templates to manifest: %w", err)
 			}
+			if err := checkUnresolvedTemplates(filepath.Base(manifestFile), tmpFile, variableConfig); err != nil {
+				return err
+			}
 
 			content, err = os.ReadFile(tmpFile)
 			if err != nil {
@@ -386,6 +464,10 @@ func getTemplatedManifests(ctx context.Context, manifest v1alpha1.ZarfManifest,
 			}
 		}
 
+		// Manifests are printed one after the other, so end each on a new line in case the file does not
+		if len(content) > 0 && !bytes.HasSuffix(content, []byte("\n")) {
+			content = append(content, '\n')
+		}
 		resources = append(resources, Resource{
 			Content:      string(content),
 			Name:         manifestFile,
@@ -3,11 +3,14 @@
 package packager
 
 import (
+	"context"
 	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 	"github.com/zarf-dev/zarf/src/pkg/feature"
+	"github.com/zarf-dev/zarf/src/pkg/packager/load"
 	"github.com/zarf-dev/zarf/src/pkg/value"
 	"github.com/zarf-dev/zarf/src/test/testutil"
 )
@@ -234,3 +237,91 @@ func TestInspectDefinitionResources_Errors(t *testing.T) {
 		})
 	}
 }
+
+func TestInspectImageMappings(t *testing.T) {
+	t.Parallel()
+
+	pkg := v1alpha1.ZarfPackage{
+		Components: []v1alpha1.ZarfComponent{
+			{
+				Name:   "first",
+				Images: []string{"nginx:1.23.3", "nginx:1.23.3", "ghcr.io/stefanprodan/podinfo:6.3.3"},
+			},
+			{
+				Name: "second",
+				ImageArchives: []v1alpha1.ImageArchive{
+					{
+						Path:   "archive.tar",
+						Images: []string{"zarf-dev/zarf-agent@sha256:84605f731c6a18194794c51e70021c671ab064654b751aa57e905bce55be13de"},
+					},
+				},
+			},
+		},
+	}
+
+	mappings, err := InspectImageMappings(pkg, "127.0.0.1:31999")
+	require.NoError(t, err)
+	expected := []ImageMapping{
+		{
+			Component: "first",
+			Original:  "nginx:1.23.3",
+			Rewritten: "127.0.0.1:31999/library/nginx:1.23.3-zarf-3793515731",
+		},
+		{
+			Component: "first",
+			Original:  "ghcr.io/stefanprodan/podinfo:6.3.3",
+			Rewritten: "127.0.0.1:31999/stefanprodan/podinfo:6.3.3-zarf-2985051089",
+		},
+		{
+			Component: "second",
+			Original:  "zarf-dev/zarf-agent@sha256:84605f731c6a18194794c51e70021c671ab064654b751aa57e905bce55be13de",
+			Rewritten: "127.0.0.1:31999/zarf-dev/zarf-agent@sha256:84605f731c6a18194794c51e70021c671ab064654b751aa57e905bce55be13de",
+		},
+	}
+	require.Equal(t, expected, mappings)
+
+	_, err = InspectImageMappings(pkg, "")
+	require.Error(t, err)
+
+	pkg.Components[0].Images = []string{"i am not a ref at all"}
+	_, err = InspectImageMappings(pkg, "127.0.0.1:31999")
+	require.Error(t, err)
+}
+
+func TestInspectClusterRequirements(t *testing.T) {
+	t.Parallel()
+
+	pkg, err := load.PackageDefinition(context.Background(), inspectTestDataPath("cluster-requirements"), load.DefinitionOptions{CachePath: t.TempDir()})
+	require.NoError(t, err)
+
+	requirements, err := InspectClusterRequirements(pkg)
+	require.NoError(t, err)
+	expected := ClusterRequirements{
+		RequiresCluster: true,
+		Components: []ComponentClusterRequirement{
+			{
+				Name:            "files-only",
+				Required:        true,
+				RequiresCluster: false,
+			},
+			{
+				Name:            "imported-manifests",
+				Required:        false,
+				RequiresCluster: true,
+			},
+		},
+	}
+	require.Equal(t, expected, requirements)
+
+	pkg.Components = pkg.Components[:1]
+	requirements, err = InspectClusterRequirements(pkg)
+	require.NoError(t, err)
+	require.False(t, requirements.RequiresCluster)
+
+	pkg.Components = append(pkg.Components, v1alpha1.ZarfComponent{
+		Name:   "unresolved",
+		Import: v1alpha1.ZarfComponentImport{Path: "child"},
+	})
+	_, err = InspectClusterRequirements(pkg)
+	require.ErrorContains(t, err, "component unresolved has an unresolved import")
+}
@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
+	"maps"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -55,6 +56,12 @@ type AssembleOptions struct {
 	CachePath string
 	// WithBuildMachineInfo includes build machine information (hostname and username) in the package metadata
 	WithBuildMachineInfo bool
+	// PinDigests resolves every image referenced only by tag to its digest and stores the pinned reference in the package
+	PinDigests bool
+	// NoCache rebuilds every component instead of reusing the component tarballs cached by previous builds
+	NoCache bool
+	// ImageConcurrency is the amount of images pulled in parallel
+	ImageConcurrency int
 	types.RemoteOptions
 }
 
@@ -99,18 +106,37 @@ func AssemblePackage(ctx context.Context, pkg v1alpha1.ZarfPackage, packagePath
 		}
 	}
 
+	pkg.Components = skipImagePulls(ctx, pkg.Components)
+
+	if opts.PinDigests {
+		var err error
+		pkg.Components, pkg.Build.PinnedImages, err = pinImageDigests(ctx, pkg.Components, images.PullOptions{
+			Arch:                  pkg.Metadata.Architecture,
+			RegistryOverrides:     opts.RegistryOverrides,
+			PlainHTTP:             opts.RemoteOptions.PlainHTTP,
+			InsecureSkipTLSVerify: opts.RemoteOptions.InsecureSkipTLSVerify,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	buildPath, err := utils.MakeTempDir(config.CommonOptions.TempDirectory)
 	if err != nil {
 		return nil, err
 	}
 	for _, component := range pkg.Components {
-		err := assemblePackageComponent(ctx, component, packagePath, buildPath, opts.CachePath, opts.RemoteOptions)
+		err := assembleComponentWithCache(ctx, component, packagePath, buildPath, opts)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	componentImages := []transform.Image{}
+	imageArchs, err := imageArchitectures(pkg.Components, pkg.Metadata.Architecture)
+	if err != nil {
+		return nil, err
+	}
+	componentImages := map[string][]transform.Image{}
 	manifests := []images.ImageWithManifest{}
 	for _, component := range pkg.Components {
 		for _, imageArchive := range component.ImageArchives {
@@ -118,7 +144,7 @@ func AssemblePackage(ctx context.Context, pkg v1alpha1.ZarfPackage, packagePath
 				imageArchive.Path = filepath.Join(packagePath, imageArchive.Path)
 			}
 
-			archiveImageManifests, err := images.Unpack(ctx, imageArchive, filepath.Join(buildPath, ImagesDir), pkg.Metadata.Architecture)
+			archiveImageManifests, err := images.Unpack(ctx, imageArchive, filepath.Join(buildPath, ImagesDir), componentArchitecture(component, pkg.Metadata.Architecture))
 			if err != nil {
 				return nil, err
 			}
@@ -129,23 +155,27 @@ func AssemblePackage(ctx context.Context, pkg v1alpha1.ZarfPackage, packagePath
 			if err != nil {
 				return nil, fmt.Errorf("failed to create ref for image %s: %w", src, err)
 			}
-			if slices.Contains(componentImages, refInfo) {
+			arch := imageArchs[src]
+			if slices.Contains(componentImages[arch], refInfo) {
 				continue
 			}
-			componentImages = append(componentImages, refInfo)
+			componentImages[arch] = append(componentImages[arch], refInfo)
 		}
 	}
 	sbomImageList := []transform.Image{}
-	if len(componentImages) > 0 {
+	// Images are pulled once per architecture into the same layout so that a package can hold the images of
+	// components restricted to different cluster architectures
+	for _, arch := range slices.Sorted(maps.Keys(componentImages)) {
 		pullOpts := images.PullOptions{
 			OCIConcurrency:        opts.OCIConcurrency,
-			Arch:                  pkg.Metadata.Architecture,
+			ImageConcurrency:      opts.ImageConcurrency,
+			Arch:                  arch,
 			RegistryOverrides:     opts.RegistryOverrides,
 			CacheDirectory:        filepath.Join(opts.CachePath, ImagesDir),
 			PlainHTTP:             opts.RemoteOptions.PlainHTTP,
 			InsecureSkipTLSVerify: opts.RemoteOptions.InsecureSkipTLSVerify,
 		}
-		imageManifests, err := images.Pull(ctx, componentImages, filepath.Join(buildPath, ImagesDir), pullOpts)
+		imageManifests, err := images.Pull(ctx, componentImages[arch], filepath.Join(buildPath, ImagesDir), pullOpts)
 		if err != nil {
 			return nil, err
 		}
@@ -314,6 +344,87 @@ func AssembleSkeleton(ctx context.Context, pkg v1alpha1.ZarfPackage, packagePath
 	return pkgLayout, nil
 }
 
+// skipImagePulls removes the images and image archives of components that set skipImagePull so they are not added to the package.
+func skipImagePulls(ctx context.Context, components []v1alpha1.ZarfComponent) []v1alpha1.ZarfComponent {
+	components = slices.Clone(components)
+	for i, comp := range components {
+		if !comp.SkipImagePull {
+			continue
+		}
+		logger.From(ctx).Warn("skipping the images of the component, the package will not be self-contained and the images must already be available in the target registry",
+			"component", comp.Name, "images", comp.GetImages())
+		components[i].Images = nil
+		components[i].ImageArchives = nil
+	}
+	return components
+}
+
+// pinImageDigests replaces every image of the components that is referenced only by tag with its pinned digest form
+// and returns the pinned images keyed by their original reference. Images are pinned to the manifest of the
+// architecture of their component, opts.Arch is used for components that are not restricted to a cluster architecture.
+func pinImageDigests(ctx context.Context, components []v1alpha1.ZarfComponent, opts images.PullOptions) ([]v1alpha1.ZarfComponent, map[string]string, error) {
+	imageArchs, err := imageArchitectures(components, opts.Arch)
+	if err != nil {
+		return nil, nil, err
+	}
+	imgsByArch := map[string][]string{}
+	for img, arch := range imageArchs {
+		imgsByArch[arch] = append(imgsByArch[arch], img)
+	}
+	if len(imgsByArch) == 0 {
+		return components, nil, nil
+	}
+	pinned := map[string]string{}
+	for _, arch := range slices.Sorted(maps.Keys(imgsByArch)) {
+		imgs := imgsByArch[arch]
+		slices.Sort(imgs)
+		logger.From(ctx).Info("pinning images to digests", "count", len(imgs), "arch", arch)
+		archOpts := opts
+		archOpts.Arch = arch
+		archPinned, err := images.PinDigests(ctx, imgs, archOpts)
+		if err != nil {
+			return nil, nil, err
+		}
+		maps.Copy(pinned, archPinned)
+	}
+	components = slices.Clone(components)
+	for i, comp := range components {
+		components[i].Images = slices.Clone(comp.Images)
+		for j, img := range comp.Images {
+			if p, ok := pinned[img]; ok {
+				logger.From(ctx).Info("pinned image to digest", "component", comp.Name, "image", img, "pinned", p)
+				components[i].Images[j] = p
+			}
+		}
+	}
+	return components, pinned, nil
+}
+
+// componentArchitecture returns the architecture the images of the component are pulled for, which is the cluster
+// architecture the component is restricted to or else the package architecture.
+func componentArchitecture(component v1alpha1.ZarfComponent, pkgArch string) string {
+	if component.Only.Cluster.Architecture != "" {
+		return component.Only.Cluster.Architecture
+	}
+	return pkgArch
+}
+
+// imageArchitectures returns the architecture every image of the components is pulled for. An image is stored once
+// in the package, so it errors when components of different architectures use the same image.
+func imageArchitectures(components []v1alpha1.ZarfComponent, pkgArch string) (map[string]string, error) {
+	imageArchs := map[string]string{}
+	for _, comp := range components {
+		arch := componentArchitecture(comp, pkgArch)
+		for _, img := range comp.Images {
+			if existing, ok := imageArchs[img]; ok && existing != arch {
+				return nil, fmt.Errorf("image %s is used by components of the %s and %s architectures, a package can only contain one architecture of an image", img, existing, arch)
+			}
+			imageArchs[img] = arch
+		}
+	}
+	return imageArchs, nil
+}
+
 // validateImageArchivesNoDuplicates ensures no image appears in multiple image archives
 // and that images in image archives don't conflict with images in component.Images.
 func validateImageArchivesNoDuplicates(components []v1alpha1.ZarfComponent) error {
@@ -354,6 +465,34 @@ func validateImageArchivesNoDuplicates(components []v1alpha1.ZarfComponent) erro
 	return nil
 }
 
+// extractFile extracts the extractPath or extractPaths of the file from the archive at src to dst. A single extractPath
+// is moved to dst, extractPaths are extracted into the dst folder with their paths in the archive.
+func extractFile(ctx context.Context, file v1alpha1.ZarfFile, src, dst string) error {
+	if len(file.ExtractPaths) > 0 {
+		if err := archive.Decompress(ctx, src, dst, archive.DecompressOpts{Patterns: file.ExtractPaths}); err != nil {
+			return fmt.Errorf(lang.ErrFileExtract, strings.Join(file.ExtractPaths, ", "), src, err)
+		}
+		return nil
+	}
+
+	destinationDir := filepath.Dir(dst)
+	decompressOpts := archive.DecompressOpts{
+		Files: []string{file.ExtractPath},
+	}
+	if err := archive.Decompress(ctx, src, destinationDir, decompressOpts); err != nil {
+		return fmt.Errorf(lang.ErrFileExtract, file.ExtractPath, src, err)
+	}
+
+	// Make sure dst reflects the actual file or directory.
+	updatedExtractedFileOrDir := filepath.Join(destinationDir, file.ExtractPath)
+	if updatedExtractedFileOrDir != dst {
+		if err := os.Rename(updatedExtractedFileOrDir, dst); err != nil {
+			return fmt.Errorf(lang.ErrWritingFile, dst, err)
+		}
+	}
+	return nil
+}
+
 func assemblePackageComponent(ctx context.Context, component v1alpha1.ZarfComponent, packagePath, buildPath, cachePath string, remoteOpts types.RemoteOptions) (err error) {
 	tmpBuildPath, err := utils.MakeTempDir(config.CommonOptions.TempDirectory)
 	if err != nil {
@@ -369,7 +508,8 @@ func assemblePackageComponent(ctx context.Context, component v1alpha1.ZarfCompon
 	}
 
 	onCreate := component.Actions.OnCreate
-	if err := actions.Run(ctx, packagePath, onCreate.Defaults, onCreate.Before, nil, nil); err != nil {
+	actionCtx := actions.WithComponent(ctx, component.Name)
+	if err := actions.Run(actionCtx, packagePath, onCreate.Defaults, onCreate.Before, nil, nil); err != nil {
 		return fmt.Errorf("unable to run component before action: %w", err)
 	}
 
@@ -386,10 +526,9 @@ func assemblePackageComponent(ctx context.Context, component v1alpha1.ZarfCompon
 	for filesIdx, file := range component.Files {
 		rel := filepath.Join(string(FilesComponentDir), strconv.Itoa(filesIdx), filepath.Base(file.Target))
 		dst := filepath.Join(compBuildPath, rel)
-		destinationDir := filepath.Dir(dst)
 
 		if helpers.IsURL(file.Source) {
-			if file.ExtractPath != "" {
+			if file.ExtractPath != "" || len(file.ExtractPaths) > 0 {
 				// get the compressedFileName from the source
 				compressedFileName, err := helpers.ExtractBasePathFromURL(file.Source)
 				if err != nil {
@@ -405,18 +544,14 @@ func assemblePackageComponent(ctx context.Context, component v1alpha1.ZarfCompon
 				compressedFile := filepath.Join(tmpDir, compressedFileName)
 
 				// If the file is an archive, download it to the componentPath.Temp
-				if err := utils.DownloadToFile(ctx, file.Source, compressedFile); err != nil {
+				if err := utils.DownloadToFileWithOptions(ctx, file.Source, compressedFile, fileDownloadOptions(file)); err != nil {
 					return fmt.Errorf(lang.ErrDownloading, file.Source, err)
 				}
-				decompressOpts := archive.DecompressOpts{
-					Files: []string{file.ExtractPath},
-				}
-				err = archive.Decompress(ctx, compressedFile, destinationDir, decompressOpts)
-				if err != nil {
-					return fmt.Errorf(lang.ErrFileExtract, file.ExtractPath, compressedFileName, err)
+				if err := extractFile(ctx, file, compressedFile, dst); err != nil {
+					return err
 				}
 			} else {
-				if err := utils.DownloadToFile(ctx, file.Source, dst); err != nil {
+				if err := utils.DownloadToFileWithOptions(ctx, file.Source, dst, fileDownloadOptions(file)); err != nil {
 					return fmt.Errorf(lang.ErrDownloading, file.Source, err)
 				}
 			}
@@ -425,13 +560,9 @@ func assemblePackageComponent(ctx context.Context, component v1alpha1.ZarfCompon
 			if !filepath.IsAbs(file.Source) {
 				src = filepath.Join(packagePath, file.Source)
 			}
-			if file.ExtractPath != "" {
-				decompressOpts := archive.DecompressOpts{
-					Files: []string{file.ExtractPath},
-				}
-				err = archive.Decompress(ctx, src, destinationDir, decompressOpts)
-				if err != nil {
-					return fmt.Errorf(lang.ErrFileExtract, file.ExtractPath, src, err)
+			if file.ExtractPath != "" || len(file.ExtractPaths) > 0 {
+				if err := extractFile(ctx, file, src, dst); err != nil {
+					return err
 				}
 			} else {
 				if err := helpers.CreatePathAndCopy(src, dst); err != nil {
@@ -440,19 +571,9 @@ func assemblePackageComponent(ctx context.Context, component v1alpha1.ZarfCompon
 			}
 		}
 
-		if file.ExtractPath != "" {
-			// Make sure dst reflects the actual file or directory.
-			updatedExtractedFileOrDir := filepath.Join(destinationDir, file.ExtractPath)
-			if updatedExtractedFileOrDir != dst {
-				if err := os.Rename(updatedExtractedFileOrDir, dst); err != nil {
-					return fmt.Errorf(lang.ErrWritingFile, dst, err)
-				}
-			}
-		}
-
 		// Abort packaging on invalid shasum (if one is specified).
 		if file.Shasum != "" {
-			if err := helpers.SHAsMatch(dst, file.Shasum); err != nil {
+			if err := utils.ChecksumMatches(dst, file.Shasum); err != nil {
 				return fmt.Errorf("sha mismatch for %s: %w", file.Source, err)
 			}
 		}
@@ -512,7 +633,7 @@ func assemblePackageComponent(ctx context.Context, component v1alpha1.ZarfCompon
 		}
 	}
 
-	if err := actions.Run(ctx, packagePath, onCreate.Defaults, onCreate.After, nil, nil); err != nil {
+	if err := actions.Run(actionCtx, packagePath, onCreate.Defaults, onCreate.After, nil, nil); err != nil {
 		return fmt.Errorf("unable to run component after action: %w", err)
 	}
 
@@ -536,6 +657,13 @@ func assemblePackageComponent(ctx context.Context, component v1alpha1.ZarfCompon
 	return nil
 }
 
+func fileDownloadOptions(file v1alpha1.ZarfFile) utils.DownloadOptions {
+	return utils.DownloadOptions{
+		MaxRetries: file.MaxRetries,
+		Timeout:    time.Duration(file.TimeoutSeconds) * time.Second,
+	}
+}
+
 // PackageManifest takes a Zarf manifest definition and packs it into a package layout
 func PackageManifest(ctx context.Context, manifest v1alpha1.ZarfManifest, compBuildPath string, packagePath string) error {
 	for fileIdx, path := range manifest.Files {
@@ -588,6 +716,14 @@ func PackageChart(ctx context.Context, chart v1alpha1.ZarfChart, packagePath, ch
 		valuesFiles = append(valuesFiles, v)
 	}
 	chart.ValuesFiles = valuesFiles
+	postRenderPatches := []string{}
+	for _, p := range chart.PostRenderPatches {
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(packagePath, p)
+		}
+		postRenderPatches = append(postRenderPatches, p)
+	}
+	chart.PostRenderPatches = postRenderPatches
 	if err := helm.PackageChart(ctx, chart, chartPath, valuesFilePath, cachePath, remoteOpts); err != nil {
 		return err
 	}
@@ -640,6 +776,18 @@ func assembleSkeletonComponent(ctx context.Context, component v1alpha1.ZarfCompo
 				return fmt.Errorf("unable to copy chart values file %s: %w", path, err)
 			}
 		}
+
+		for patchIdx, path := range chart.PostRenderPatches {
+			rel := filepath.ToSlash(helm.StandardPostRenderPatchName(string(ValuesComponentDir), chart, patchIdx))
+			component.Charts[chartIdx].PostRenderPatches[patchIdx] = rel
+
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(packagePath, path)
+			}
+			if err := helpers.CreatePathAndCopy(path, filepath.Join(compBuildPath, rel)); err != nil {
+				return fmt.Errorf("unable to copy chart post-render patch %s: %w", path, err)
+			}
+		}
 	}
 
 	for filesIdx, file := range component.Files {
@@ -649,27 +797,14 @@ func assembleSkeletonComponent(ctx context.Context, component v1alpha1.ZarfCompo
 
 		rel := filepath.ToSlash(filepath.Join(string(FilesComponentDir), strconv.Itoa(filesIdx), filepath.Base(file.Target)))
 		dst := filepath.Join(compBuildPath, rel)
-		destinationDir := filepath.Dir(dst)
 		src := file.Source
 		if !filepath.IsAbs(src) {
 			src = filepath.Join(packagePath, src)
 		}
 
-		if file.ExtractPath != "" {
-			decompressOpts := archive.DecompressOpts{
-				Files: []string{file.ExtractPath},
-			}
-			err = archive.Decompress(ctx, src, destinationDir, decompressOpts)
-			if err != nil {
-				return fmt.Errorf(lang.ErrFileExtract, file.ExtractPath, src, err)
-			}
-
-			// Make sure dst reflects the actual file or directory.
-			updatedExtractedFileOrDir := filepath.Join(destinationDir, file.ExtractPath)
-			if updatedExtractedFileOrDir != dst {
-				if err := os.Rename(updatedExtractedFileOrDir, dst); err != nil {
-					return fmt.Errorf(lang.ErrWritingFile, dst, err)
-				}
+		if file.ExtractPath != "" || len(file.ExtractPaths) > 0 {
+			if err := extractFile(ctx, file, src, dst); err != nil {
+				return err
 			}
 		} else {
 			if err := helpers.CreatePathAndCopy(src, dst); err != nil {
@@ -680,12 +815,13 @@ func assembleSkeletonComponent(ctx context.Context, component v1alpha1.ZarfCompo
 		// Change the source to the new relative source directory (any remote files will have been skipped above)
 		component.Files[filesIdx].Source = rel
 
-		// Remove the extractPath from a skeleton since it will already extract it
+		// Remove the extractPath and extractPaths from a skeleton since it will already extract them
 		component.Files[filesIdx].ExtractPath = ""
+		component.Files[filesIdx].ExtractPaths = nil
 
 		// Abort packaging on invalid shasum (if one is specified).
 		if file.Shasum != "" {
-			if err := helpers.SHAsMatch(dst, file.Shasum); err != nil {
+			if err := utils.ChecksumMatches(dst, file.Shasum); err != nil {
 				return fmt.Errorf("sha mismatch for %s: %w", file.Source, err)
 			}
 		}
@@ -4,6 +4,7 @@
 package layout
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -11,6 +12,9 @@ import (
 	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/stretchr/testify/require"
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/pkg/archive"
+	"github.com/zarf-dev/zarf/src/pkg/images"
+	"github.com/zarf-dev/zarf/src/test/testutil"
 )
 
 func TestGetChecksum(t *testing.T) {
@@ -58,6 +62,46 @@ func TestCreateReproducibleTarballFromDir(t *testing.T) {
 	require.Equal(t, "c09d17f612f241cdf549e5fb97c9e063a8ad18ae7a9f3af066332ed6b38556ad", shaSum)
 }
 
+func TestExtractFile(t *testing.T) {
+	t.Parallel()
+	ctx := testutil.TestContext(t)
+
+	srcDir := filepath.Join(t.TempDir(), "vendor")
+	files := map[string]string{
+		"bin/zarf":       "zarf",
+		"bin/helm":       "helm",
+		"lib/libzarf.so": "lib",
+		"docs/README.md": "docs",
+	}
+	for k, v := range files {
+		require.NoError(t, os.MkdirAll(filepath.Join(srcDir, filepath.Dir(k)), 0o700))
+		require.NoError(t, os.WriteFile(filepath.Join(srcDir, k), []byte(v), 0o600))
+	}
+	src := filepath.Join(t.TempDir(), "vendor.tar.gz")
+	require.NoError(t, archive.Compress(ctx, []string{srcDir}, src, archive.CompressOpts{}))
+
+	dst := filepath.Join(t.TempDir(), "files", "0", "zarf")
+	err := extractFile(ctx, v1alpha1.ZarfFile{ExtractPath: "vendor/bin/zarf"}, src, dst)
+	require.NoError(t, err)
+	b, err := os.ReadFile(dst)
+	require.NoError(t, err)
+	require.Equal(t, "zarf", string(b))
+
+	dst = filepath.Join(t.TempDir(), "files", "1", "vendor")
+	err = extractFile(ctx, v1alpha1.ZarfFile{ExtractPaths: []string{"vendor/bin/*", "vendor/lib/*.so"}}, src, dst)
+	require.NoError(t, err)
+	extracted, err := helpers.RecursiveFileList(dst, nil, false)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{
+		filepath.Join(dst, "vendor", "bin", "zarf"),
+		filepath.Join(dst, "vendor", "bin", "helm"),
+		filepath.Join(dst, "vendor", "lib", "libzarf.so"),
+	}, extracted)
+
+	err = extractFile(ctx, v1alpha1.ZarfFile{ExtractPaths: []string{"vendor/bin/*", "vendor/sbin/*"}}, src, t.TempDir())
+	require.ErrorContains(t, err, `no files matching "vendor/sbin/*" found in archive`)
+}
+
 func TestValidateImageArchivesNoDuplicates(t *testing.T) {
 	t.Parallel()
 
@@ -217,3 +261,78 @@ func TestValidateImageArchivesNoDuplicates(t *testing.T) {
 		})
 	}
 }
+
+func TestPinImageDigests(t *testing.T) {
+	t.Parallel()
+	ctx := testutil.TestContext(t)
+	upstream := testutil.SetupInMemoryRegistryDynamic(ctx, t)
+	manifestDigest := testutil.PushImage(ctx, t, upstream+"/fixtures/img", "v1")
+
+	tagged := fmt.Sprintf("%s/fixtures/img:v1", upstream)
+	digested := fmt.Sprintf("%s/fixtures/img@%s", upstream, manifestDigest)
+	components := []v1alpha1.ZarfComponent{
+		{
+			Name:   "first",
+			Images: []string{tagged},
+		},
+		{
+			Name:   "second",
+			Images: []string{digested, tagged},
+		},
+	}
+
+	pinned, mapping, err := pinImageDigests(ctx, components, images.PullOptions{Arch: "amd64", PlainHTTP: true})
+	require.NoError(t, err)
+	expected := fmt.Sprintf("%s@%s", tagged, manifestDigest)
+	require.Equal(t, map[string]string{tagged: expected}, mapping)
+	require.Equal(t, []string{expected}, pinned[0].Images)
+	require.Equal(t, []string{digested, expected}, pinned[1].Images)
+	// The components passed in are not modified
+	require.Equal(t, []string{tagged}, components[0].Images)
+}
+
+func TestImageArchitectures(t *testing.T) {
+	t.Parallel()
+
+	newComponent := func(arch string, imgs ...string) v1alpha1.ZarfComponent {
+		return v1alpha1.ZarfComponent{
+			Images: imgs,
+			Only:   v1alpha1.ZarfComponentOnlyTarget{Cluster: v1alpha1.ZarfComponentOnlyCluster{Architecture: arch}},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		components  []v1alpha1.ZarfComponent
+		expected    map[string]string
+		expectedErr string
+	}{
+		{
+			name:       "unrestricted components use the package architecture",
+			components: []v1alpha1.ZarfComponent{newComponent("", "nginx:1.27"), newComponent("amd64", "nginx:1.27")},
+			expected:   map[string]string{"nginx:1.27": "amd64"},
+		},
+		{
+			name:       "restricted components use their own architecture",
+			components: []v1alpha1.ZarfComponent{newComponent("", "nginx:1.27"), newComponent("arm64", "agent:arm64")},
+			expected:   map[string]string{"nginx:1.27": "amd64", "agent:arm64": "arm64"},
+		},
+		{
+			name:        "an image can not be used for two architectures",
+			components:  []v1alpha1.ZarfComponent{newComponent("", "nginx:1.27"), newComponent("arm64", "nginx:1.27")},
+			expectedErr: "image nginx:1.27 is used by components of the amd64 and arm64 architectures",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			imageArchs, err := imageArchitectures(tt.components, "amd64")
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, imageArchs)
+		})
+	}
+}
@@ -0,0 +1,230 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package layout
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/config"
+	"github.com/zarf-dev/zarf/src/pkg/logger"
+	"github.com/zarf-dev/zarf/src/pkg/transform"
+)
+
+// ComponentCacheDir is the directory in the Zarf cache that holds the built tarballs of components.
+const ComponentCacheDir = "components"
+
+// assembleComponentWithCache builds the component tarball, reusing the tarball of a previous build when the
+// component definition and its local sources are unchanged.
+func assembleComponentWithCache(ctx context.Context, component v1alpha1.ZarfComponent, packagePath, buildPath string, opts AssembleOptions) error {
+	l := logger.From(ctx)
+	build := func() error {
+		return assemblePackageComponent(ctx, component, packagePath, buildPath, opts.CachePath, opts.RemoteOptions)
+	}
+	if opts.CachePath == "" {
+		return build()
+	}
+	key, err := componentCacheKey(component, packagePath)
+	if err != nil {
+		// The build reports missing or unreadable sources with a better error than the hash can
+		l.Debug("unable to compute the component cache key, building without the cache", "component", component.Name, "error", err)
+		return build()
+	}
+	if key == "" {
+		l.Debug("component runs onCreate actions and is not cached", "component", component.Name)
+		return build()
+	}
+	if src := unpinnedRemoteSource(component); src != "" {
+		l.Debug("component has a remote source that is not pinned and is not cached", "component", component.Name, "source", src)
+		return build()
+	}
+
+	cachedPath := filepath.Join(opts.CachePath, ComponentCacheDir, fmt.Sprintf("%s.tar", key))
+	tarPath := filepath.Join(buildPath, "components", fmt.Sprintf("%s.tar", component.Name))
+	if !opts.NoCache && !helpers.InvalidPath(cachedPath) {
+		l.Info("using cached component", "component", component.Name, "key", key)
+		return helpers.CreatePathAndCopy(cachedPath, tarPath)
+	}
+
+	if err := build(); err != nil {
+		return err
+	}
+	// Components without any assets do not produce a tarball
+	if helpers.InvalidPath(tarPath) {
+		return nil
+	}
+	if err := helpers.CreateDirectory(filepath.Dir(cachedPath), helpers.ReadExecuteAllWriteUser); err != nil {
+		return err
+	}
+	// Copy to a temporary file first so that an interrupted build never leaves a partial tarball in the cache
+	tmpPath := fmt.Sprintf("%s.tmp", cachedPath)
+	if err := helpers.CreatePathAndCopy(tarPath, tmpPath); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, cachedPath); err != nil {
+		return errors.Join(err, os.Remove(tmpPath))
+	}
+	l.Debug("cached component", "component", component.Name, "key", key)
+	return nil
+}
+
+// componentCacheKey returns a hash of the Zarf version, the component definition and the content of its local sources.
+// Remote sources such as charts from repositories are only keyed by their definition.
+// An empty key is returned for components with onCreate before or after actions, as they may change the sources in
+// ways the hash can not see.
+func componentCacheKey(component v1alpha1.ZarfComponent, packagePath string) (string, error) {
+	onCreate := component.Actions.OnCreate
+	if len(onCreate.Before) > 0 || len(onCreate.After) > 0 {
+		return "", nil
+	}
+
+	h := sha256.New()
+	if _, err := fmt.Fprintln(h, config.CLIVersion); err != nil {
+		return "", err
+	}
+	b, err := json.Marshal(component)
+	if err != nil {
+		return "", err
+	}
+	if _, err := h.Write(b); err != nil {
+		return "", err
+	}
+	for _, src := range localComponentSources(component) {
+		if !filepath.IsAbs(src) {
+			src = filepath.Join(packagePath, src)
+		}
+		if err := hashPath(h, src); err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// gitCommitRegex matches a full SHA-1 or SHA-256 git commit hash.
+var gitCommitRegex = regexp.MustCompile(`^([0-9a-f]{40}|[0-9a-f]{64})$`)
+
+// unpinnedRemoteSource returns the first remote source of the component whose content can change without a change to
+// the definition, or an empty string when every remote source is pinned. Git repos and charts are pinned by a commit
+// hash, files by a shasum, while remote manifests, kustomizations and data injections can not be pinned.
+func unpinnedRemoteSource(component v1alpha1.ZarfComponent) string {
+	gitPinned := func(url string) bool {
+		_, ref, err := transform.GitURLSplitRef(url)
+		return err == nil && gitCommitRegex.MatchString(ref)
+	}
+	for _, repo := range component.Repos {
+		if !gitPinned(repo) {
+			return repo
+		}
+	}
+	for _, chart := range component.Charts {
+		url, _, err := transform.GitURLSplitRef(chart.URL)
+		if chart.URL != "" && err == nil && strings.HasSuffix(url, ".git") && !gitPinned(chart.URL) {
+			return chart.URL
+		}
+	}
+	for _, file := range component.Files {
+		if helpers.IsURL(file.Source) && file.Shasum == "" {
+			return file.Source
+		}
+	}
+	for _, data := range component.DataInjections {
+		if helpers.IsURL(data.Source) {
+			return data.Source
+		}
+	}
+	for _, manifest := range component.Manifests {
+		for _, src := range slices.Concat(manifest.Files, manifest.Kustomizations) {
+			if helpers.IsURL(src) {
+				return src
+			}
+		}
+	}
+	return ""
+}
+
+// localComponentSources returns the local files and directories that are included in the component tarball.
+func localComponentSources(component v1alpha1.ZarfComponent) []string {
+	srcs := []string{}
+	addLocal := func(src string) {
+		if src != "" && !helpers.IsURL(src) {
+			srcs = append(srcs, src)
+		}
+	}
+	for _, chart := range component.Charts {
+		addLocal(chart.LocalPath)
+		for _, v := range chart.ValuesFiles {
+			addLocal(v)
+		}
+		for _, p := range chart.PostRenderPatches {
+			addLocal(p)
+		}
+	}
+	for _, file := range component.Files {
+		addLocal(file.Source)
+	}
+	for _, data := range component.DataInjections {
+		addLocal(data.Source)
+	}
+	for _, manifest := range component.Manifests {
+		for _, f := range manifest.Files {
+			addLocal(f)
+		}
+		for _, k := range manifest.Kustomizations {
+			addLocal(k)
+		}
+	}
+	return srcs
+}
+
+// hashPath writes the path, mode and content of the file, or of every file in the directory, to the hash.
+func hashPath(h hash.Hash, root string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(h, "%s\x00%s\x00%s\x00", root, filepath.ToSlash(rel), info.Mode()); err != nil {
+			return err
+		}
+		switch {
+		case info.Mode()&fs.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			_, err = io.WriteString(h, target)
+			return err
+		case info.Mode().IsRegular():
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(h, f)
+			return errors.Join(err, f.Close())
+		default:
+			return nil
+		}
+	})
+}
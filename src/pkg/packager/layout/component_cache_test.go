@@ -0,0 +1,180 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package layout
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/test/testutil"
+)
+
+func TestAssembleComponentWithCache(t *testing.T) {
+	t.Parallel()
+
+	newComponent := func(t *testing.T) (v1alpha1.ZarfComponent, string) {
+		t.Helper()
+		packagePath := t.TempDir()
+		err := os.MkdirAll(filepath.Join(packagePath, "config"), 0o700)
+		require.NoError(t, err)
+		err = os.WriteFile(filepath.Join(packagePath, "config", "app.conf"), []byte("replicas=1"), 0o600)
+		require.NoError(t, err)
+		component := v1alpha1.ZarfComponent{
+			Name: "cached",
+			Files: []v1alpha1.ZarfFile{
+				{
+					Source: "config",
+					Target: "/etc/app",
+				},
+			},
+		}
+		return component, packagePath
+	}
+	// assemble builds the component and returns the content of the resulting tarball
+	assemble := func(t *testing.T, component v1alpha1.ZarfComponent, packagePath string, opts AssembleOptions) []byte {
+		t.Helper()
+		buildPath := t.TempDir()
+		err := assembleComponentWithCache(testutil.TestContext(t), component, packagePath, buildPath, opts)
+		require.NoError(t, err)
+		b, err := os.ReadFile(filepath.Join(buildPath, "components", "cached.tar"))
+		require.NoError(t, err)
+		return b
+	}
+	// poisonCache overwrites the cached tarball so that a cache hit can be told apart from a rebuild
+	poisonCache := func(t *testing.T, component v1alpha1.ZarfComponent, packagePath, cachePath string) {
+		t.Helper()
+		key, err := componentCacheKey(component, packagePath)
+		require.NoError(t, err)
+		cachedPath := filepath.Join(cachePath, ComponentCacheDir, key+".tar")
+		require.FileExists(t, cachedPath)
+		err = os.WriteFile(cachedPath, []byte("from cache"), 0o600)
+		require.NoError(t, err)
+	}
+
+	t.Run("unchanged component is served from the cache", func(t *testing.T) {
+		t.Parallel()
+		component, packagePath := newComponent(t)
+		opts := AssembleOptions{CachePath: t.TempDir()}
+
+		built := assemble(t, component, packagePath, opts)
+		require.NotEqual(t, []byte("from cache"), built)
+		poisonCache(t, component, packagePath, opts.CachePath)
+		require.Equal(t, []byte("from cache"), assemble(t, component, packagePath, opts))
+	})
+
+	t.Run("changed source is rebuilt", func(t *testing.T) {
+		t.Parallel()
+		component, packagePath := newComponent(t)
+		opts := AssembleOptions{CachePath: t.TempDir()}
+
+		assemble(t, component, packagePath, opts)
+		poisonCache(t, component, packagePath, opts.CachePath)
+		err := os.WriteFile(filepath.Join(packagePath, "config", "app.conf"), []byte("replicas=2"), 0o600)
+		require.NoError(t, err)
+		require.NotEqual(t, []byte("from cache"), assemble(t, component, packagePath, opts))
+	})
+
+	t.Run("changed definition is rebuilt", func(t *testing.T) {
+		t.Parallel()
+		component, packagePath := newComponent(t)
+		opts := AssembleOptions{CachePath: t.TempDir()}
+
+		assemble(t, component, packagePath, opts)
+		poisonCache(t, component, packagePath, opts.CachePath)
+		component.Files[0].Target = "/etc/other"
+		require.NotEqual(t, []byte("from cache"), assemble(t, component, packagePath, opts))
+	})
+
+	t.Run("no cache rebuilds and refreshes the cache", func(t *testing.T) {
+		t.Parallel()
+		component, packagePath := newComponent(t)
+		opts := AssembleOptions{CachePath: t.TempDir()}
+
+		built := assemble(t, component, packagePath, opts)
+		poisonCache(t, component, packagePath, opts.CachePath)
+		opts.NoCache = true
+		require.Equal(t, built, assemble(t, component, packagePath, opts))
+		opts.NoCache = false
+		require.Equal(t, built, assemble(t, component, packagePath, opts))
+	})
+
+	t.Run("component with onCreate actions is not cached", func(t *testing.T) {
+		t.Parallel()
+		component, packagePath := newComponent(t)
+		component.Actions.OnCreate.Before = []v1alpha1.ZarfComponentAction{{Cmd: "true"}}
+		opts := AssembleOptions{CachePath: t.TempDir()}
+
+		key, err := componentCacheKey(component, packagePath)
+		require.NoError(t, err)
+		require.Empty(t, key)
+		assemble(t, component, packagePath, opts)
+		require.NoDirExists(t, filepath.Join(opts.CachePath, ComponentCacheDir))
+	})
+}
+
+func TestUnpinnedRemoteSource(t *testing.T) {
+	t.Parallel()
+
+	commit := "01a23218923f24194133b5eb11268cf8d73ff1bb"
+	tests := []struct {
+		name      string
+		component v1alpha1.ZarfComponent
+		expected  string
+	}{
+		{
+			name: "local sources",
+			component: v1alpha1.ZarfComponent{
+				Files:     []v1alpha1.ZarfFile{{Source: "config"}},
+				Manifests: []v1alpha1.ZarfManifest{{Name: "app", Files: []string{"deployment.yaml"}, Kustomizations: []string{"kustomize"}}},
+			},
+		},
+		{
+			name: "pinned remote sources",
+			component: v1alpha1.ZarfComponent{
+				Repos:  []string{"https://github.com/zarf-dev/zarf.git@" + commit},
+				Charts: []v1alpha1.ZarfChart{{Name: "podinfo", URL: "https://github.com/stefanprodan/podinfo.git@" + commit}, {Name: "nginx", URL: "oci://ghcr.io/stefanprodan/charts/podinfo", Version: "6.4.0"}},
+				Files:  []v1alpha1.ZarfFile{{Source: "https://example.com/app.conf", Shasum: "abc"}},
+			},
+		},
+		{
+			name:      "repo on a tag",
+			component: v1alpha1.ZarfComponent{Repos: []string{"https://github.com/zarf-dev/zarf.git@v0.60.0"}},
+			expected:  "https://github.com/zarf-dev/zarf.git@v0.60.0",
+		},
+		{
+			name:      "repo without a ref",
+			component: v1alpha1.ZarfComponent{Repos: []string{"https://github.com/zarf-dev/zarf.git"}},
+			expected:  "https://github.com/zarf-dev/zarf.git",
+		},
+		{
+			name:      "git chart on a version",
+			component: v1alpha1.ZarfComponent{Charts: []v1alpha1.ZarfChart{{Name: "podinfo", URL: "https://github.com/stefanprodan/podinfo.git", Version: "6.4.0"}}},
+			expected:  "https://github.com/stefanprodan/podinfo.git",
+		},
+		{
+			name:      "file without a shasum",
+			component: v1alpha1.ZarfComponent{Files: []v1alpha1.ZarfFile{{Source: "https://example.com/app.conf"}}},
+			expected:  "https://example.com/app.conf",
+		},
+		{
+			name:      "remote manifest",
+			component: v1alpha1.ZarfComponent{Manifests: []v1alpha1.ZarfManifest{{Name: "app", Files: []string{"https://example.com/deployment.yaml"}}}},
+			expected:  "https://example.com/deployment.yaml",
+		},
+		{
+			name:      "remote kustomization",
+			component: v1alpha1.ZarfComponent{Manifests: []v1alpha1.ZarfManifest{{Name: "app", Kustomizations: []string{"https://github.com/stefanprodan/podinfo//kustomize?ref=6.4.0"}}}},
+			expected:  "https://github.com/stefanprodan/podinfo//kustomize?ref=6.4.0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.expected, unpinnedRemoteSource(tt.component))
+		})
+	}
+}
@@ -265,3 +265,51 @@ func TestCreateAbsolutePathImports(t *testing.T) {
 	require.NoError(t, err)
 	require.FileExists(t, filepath.Join(tmpdir, "zarf-component-file-import.json"))
 }
+
+func TestAssembleSkipImagePull(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.TestContext(t)
+	tmpdir := t.TempDir()
+	pkg := v1alpha1.ZarfPackage{
+		Kind: v1alpha1.ZarfPackageConfig,
+		Metadata: v1alpha1.ZarfMetadata{
+			Name: "skip-image-pull",
+		},
+		Components: []v1alpha1.ZarfComponent{
+			{
+				Name:          "mirrored",
+				SkipImagePull: true,
+				Manifests: []v1alpha1.ZarfManifest{
+					{
+						Name:  "deployment",
+						Files: []string{filepath.Join("testdata", "zarf-package", "deployment.yaml")},
+					},
+				},
+				// These references can not be pulled and would fail the create if they were not skipped
+				Images: []string{"localhost:1/does-not-exist:0.0.1"},
+				ImageArchives: []v1alpha1.ImageArchive{
+					{
+						Path:   "does-not-exist.tar",
+						Images: []string{"localhost:1/archived:0.0.1"},
+					},
+				},
+			},
+		},
+	}
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	pkgLayout, err := layout.AssemblePackage(ctx, pkg, wd, layout.AssembleOptions{SkipSBOM: true})
+	require.NoError(t, err)
+
+	require.Len(t, pkgLayout.Pkg.Components, 1)
+	require.True(t, pkgLayout.Pkg.Components[0].SkipImagePull)
+	require.Empty(t, pkgLayout.Pkg.Components[0].GetImages())
+	require.NoDirExists(t, pkgLayout.GetImageDirPath())
+	// The original definition is not modified
+	require.Len(t, pkg.Components[0].Images, 1)
+
+	manifestsDir, err := pkgLayout.GetComponentDir(ctx, tmpdir, "mirrored", layout.ManifestsComponentDir)
+	require.NoError(t, err)
+	require.FileExists(t, filepath.Join(manifestsDir, "deployment-0.yaml"))
+}
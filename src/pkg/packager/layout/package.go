@@ -687,6 +687,8 @@ func validatePackageIntegrity(pkgLayout *PackageLayout, isPartial bool) error {
 	if err != nil {
 		return err
 	}
+	// Every missing and mismatched file is collected so that all of them are reported at once
+	var errs []error
 	lines := strings.Split(string(b), "\n")
 	for _, line := range lines {
 		// If the line is empty (i.e. there is no checksum) simply skip it, this can result from a package with no images/components.
@@ -711,19 +713,21 @@ func validatePackageIntegrity(pkgLayout *PackageLayout, isPartial bool) error {
 			continue
 		}
 		if !ok {
-			return fmt.Errorf("file %s from checksum missing in layout", rel)
+			errs = append(errs, fmt.Errorf("file %s from checksum missing in layout", rel))
+			continue
 		}
 		err = helpers.SHAsMatch(path, sha)
 		if err != nil {
-			return err
+			errs = append(errs, fmt.Errorf("file %s does not match its checksum: %w", rel, err))
 		}
 		delete(packageFiles, path)
 	}
 
 	if len(packageFiles) > 0 {
 		filePaths := slices.Collect(maps.Keys(packageFiles))
-		return fmt.Errorf("package contains additional files not present in the checksum %s", strings.Join(filePaths, ", "))
+		slices.Sort(filePaths)
+		errs = append(errs, fmt.Errorf("package contains additional files not present in the checksum %s", strings.Join(filePaths, ", ")))
 	}
 
-	return nil
+	return errors.Join(errs...)
 }
@@ -6,8 +6,10 @@ package layout
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	goyaml "github.com/goccy/go-yaml"
@@ -1721,6 +1723,57 @@ func TestValidatePackageIntegrity_ProvenanceFiles(t *testing.T) {
 	})
 }
 
+func TestValidatePackageIntegrity_ReportsEveryFailure(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.TestContext(t)
+
+	pkgDir := t.TempDir()
+	files := map[string]string{
+		"components/a.tar":        "a",
+		"components/b.tar":        "b",
+		"images/blobs/sha256/abc": "blob",
+	}
+	checksums := []string{}
+	for _, rel := range []string{"components/a.tar", "components/b.tar", "images/blobs/sha256/abc"} {
+		path := filepath.Join(pkgDir, rel)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+		require.NoError(t, os.WriteFile(path, []byte(files[rel]), 0o644))
+		sum := sha256.Sum256([]byte(files[rel]))
+		checksums = append(checksums, fmt.Sprintf("%s %s", hex.EncodeToString(sum[:]), rel))
+	}
+	checksumsContent := strings.Join(checksums, "\n")
+	checksumsHash := sha256.Sum256([]byte(checksumsContent))
+	pkg := v1alpha1.ZarfPackage{
+		Kind: v1alpha1.ZarfPackageConfig,
+		Metadata: v1alpha1.ZarfMetadata{
+			Name:              "test-integrity",
+			AggregateChecksum: hex.EncodeToString(checksumsHash[:]),
+		},
+		Build: v1alpha1.ZarfBuildData{
+			Architecture: "amd64",
+		},
+	}
+	yamlContent, err := goyaml.Marshal(pkg)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(pkgDir, ZarfYAML), yamlContent, 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(pkgDir, Checksums), []byte(checksumsContent), 0o644))
+
+	_, err = LoadFromDir(ctx, pkgDir, PackageLayoutOptions{VerificationStrategy: VerifyNever})
+	require.NoError(t, err)
+
+	// Tamper with one file, remove another and add an unexpected one
+	require.NoError(t, os.WriteFile(filepath.Join(pkgDir, "components/a.tar"), []byte("tampered"), 0o644))
+	require.NoError(t, os.Remove(filepath.Join(pkgDir, "images/blobs/sha256/abc")))
+	require.NoError(t, os.WriteFile(filepath.Join(pkgDir, "injected.bin"), []byte("extra"), 0o644))
+
+	_, err = LoadFromDir(ctx, pkgDir, PackageLayoutOptions{VerificationStrategy: VerifyNever})
+	require.ErrorContains(t, err, "file components/a.tar does not match its checksum")
+	require.ErrorContains(t, err, "file images/blobs/sha256/abc from checksum missing in layout")
+	require.ErrorContains(t, err, "package contains additional files not present in the checksum")
+	require.NotContains(t, err.Error(), "components/b.tar")
+}
+
 func TestSignPackage_PopulatesProvenanceFiles(t *testing.T) {
 	t.Parallel()
 
@@ -5,14 +5,16 @@ package load
 
 import (
 	"context"
-	"crypto/sha256"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/mholt/archives"
 	pkgvalidate "github.com/zarf-dev/zarf/src/internal/packager/requirements"
 	"github.com/zarf-dev/zarf/src/internal/pkgcfg"
@@ -25,6 +27,7 @@ import (
 	"github.com/defenseunicorns/pkg/oci"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	ocistore "oras.land/oras-go/v2/content/oci"
+	"oras.land/oras-go/v2/registry"
 
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 	"github.com/zarf-dev/zarf/src/pkg/zoci"
@@ -37,7 +40,7 @@ func getComponentToImportName(component v1alpha1.ZarfComponent) string {
 	return component.Name
 }
 
-func resolveImports(ctx context.Context, pkg v1alpha1.ZarfPackage, packagePath, arch, flavor string, importStack []string, cachePath string, skipVersionCheck bool, remoteOptions types.RemoteOptions) (v1alpha1.ZarfPackage, error) {
+func resolveImports(ctx context.Context, pkg v1alpha1.ZarfPackage, packagePath, arch, flavor string, allArchitectures, allFlavors bool, importStack []string, cachePath string, skipVersionCheck, noImportCache bool, remoteOptions types.RemoteOptions) (v1alpha1.ZarfPackage, error) {
 	l := logger.From(ctx)
 	start := time.Now()
 
@@ -57,7 +60,9 @@ func resolveImports(ctx context.Context, pkg v1alpha1.ZarfPackage, packagePath,
 		"pkg", pkg.Metadata.Name,
 		"path", pkgPath.ManifestFile,
 		"arch", arch,
+		"allArchitectures", allArchitectures,
 		"flavor", flavor,
+		"allFlavors", allFlavors,
 		"importStack", len(importStack),
 	)
 
@@ -67,7 +72,7 @@ func resolveImports(ctx context.Context, pkg v1alpha1.ZarfPackage, packagePath,
 	components := []v1alpha1.ZarfComponent{}
 
 	for _, component := range pkg.Components {
-		if !compatibleComponent(component, arch, flavor) {
+		if !compatibleComponent(component, arch, flavor, allArchitectures, allFlavors) {
 			continue
 		}
 
@@ -81,7 +86,19 @@ func resolveImports(ctx context.Context, pkg v1alpha1.ZarfPackage, packagePath,
 			return v1alpha1.ZarfPackage{}, fmt.Errorf("invalid imported definition for %s: %w", component.Name, err)
 		}
 
+		// A flavored component only imports the components of its own flavor, even when every flavor is kept
+		importFlavor, importAllFlavors := flavor, allFlavors
+		if allFlavors && component.Only.Flavor != "" {
+			importFlavor, importAllFlavors = component.Only.Flavor, false
+		}
+		// The same applies to a component restricted to a cluster architecture when every architecture is kept
+		importArch, importAllArchitectures := arch, allArchitectures
+		if allArchitectures && component.Only.Cluster.Architecture != "" {
+			importArch, importAllArchitectures = component.Only.Cluster.Architecture, false
+		}
+
 		var importedPkg v1alpha1.ZarfPackage
+		var cacheEntry *importCacheEntry
 		if component.Import.Path != "" {
 			importPath := filepath.Join(pkgPath.BaseDir, component.Import.Path)
 			for _, sp := range importStack {
@@ -110,11 +127,15 @@ func resolveImports(ctx context.Context, pkg v1alpha1.ZarfPackage, packagePath,
 				}
 			}
 			importedPkg.Components = relevantComponents
-			importedPkg, err = resolveImports(ctx, importedPkg, importPkgPath.ManifestFile, arch, flavor, importStack, cachePath, skipVersionCheck, remoteOptions)
+			importedPkg, err = resolveImports(ctx, importedPkg, importPkgPath.ManifestFile, importArch, importFlavor, importAllArchitectures, importAllFlavors, importStack, cachePath, skipVersionCheck, noImportCache, remoteOptions)
 			if err != nil {
 				return v1alpha1.ZarfPackage{}, err
 			}
 		} else if component.Import.URL != "" {
+			component.Import.URL, err = resolveImportVersion(ctx, component.Import, remoteOptions)
+			if err != nil {
+				return v1alpha1.ZarfPackage{}, err
+			}
 			cacheModifier, err := zoci.GetOCICacheModifier(ctx, cachePath)
 			if err != nil {
 				return v1alpha1.ZarfPackage{}, err
@@ -124,17 +145,33 @@ func resolveImports(ctx context.Context, pkg v1alpha1.ZarfPackage, packagePath,
 			if err != nil {
 				return v1alpha1.ZarfPackage{}, err
 			}
-			_, err = remote.ResolveRoot(ctx)
+			root, err := remote.ResolveRoot(ctx)
 			if err != nil {
 				if strings.Contains(err.Error(), "no matching manifest was found in the manifest list") {
 					return v1alpha1.ZarfPackage{}, fmt.Errorf("package at %s exists but has not been published as a skeleton: %w", component.Import.URL, err)
 				}
 				return v1alpha1.ZarfPackage{}, err
 			}
-			importedPkg, err = remote.FetchZarfYAML(ctx)
+			cacheEntry, err = openImportCacheEntry(cachePath, component.Import.URL, root.Digest.Encoded(), noImportCache)
 			if err != nil {
 				return v1alpha1.ZarfPackage{}, err
 			}
+			var cached bool
+			importedPkg, cached, err = cacheEntry.definition(ctx)
+			if err != nil {
+				return v1alpha1.ZarfPackage{}, err
+			}
+			if cached {
+				l.Debug("using cached import", "url", component.Import.URL, "digest", root.Digest)
+			} else {
+				importedPkg, err = remote.FetchZarfYAML(ctx)
+				if err != nil {
+					return v1alpha1.ZarfPackage{}, err
+				}
+				if err := cacheEntry.storeDefinition(importedPkg); err != nil {
+					return v1alpha1.ZarfPackage{}, fmt.Errorf("unable to cache the import %s: %w", component.Import.URL, err)
+				}
+			}
 
 			if len(importedPkg.Values.Files) > 0 || importedPkg.Values.Schema != "" {
 				return v1alpha1.ZarfPackage{}, fmt.Errorf("imported skeleton %s declares values which are not yet supported", component.Import.URL)
@@ -151,7 +188,7 @@ func resolveImports(ctx context.Context, pkg v1alpha1.ZarfPackage, packagePath,
 		name := getComponentToImportName(component)
 		found := []v1alpha1.ZarfComponent{}
 		for _, component := range importedPkg.Components {
-			if component.Name == name && compatibleComponent(component, arch, flavor) {
+			if component.Name == name && compatibleComponent(component, importArch, importFlavor, importAllArchitectures, importAllFlavors) {
 				found = append(found, component)
 			}
 		}
@@ -162,7 +199,7 @@ func resolveImports(ctx context.Context, pkg v1alpha1.ZarfPackage, packagePath,
 		}
 		importedComponent := found[0]
 
-		importPath, err := fetchOCISkeleton(ctx, component, pkgPath.BaseDir, cachePath, remoteOptions)
+		importPath, err := fetchOCISkeleton(ctx, component, pkgPath.BaseDir, cachePath, cacheEntry, remoteOptions)
 		if err != nil {
 			return v1alpha1.ZarfPackage{}, err
 		}
@@ -177,6 +214,10 @@ func resolveImports(ctx context.Context, pkg v1alpha1.ZarfPackage, packagePath,
 			importPath = filepath.Dir(importPath)
 		}
 		importedComponent = fixPaths(importedComponent, importPath, pkgPath.BaseDir)
+		importedComponent, err = selectImportParts(importedComponent, component.Import.Parts)
+		if err != nil {
+			return v1alpha1.ZarfPackage{}, fmt.Errorf("invalid imported definition for %s: %w", component.Name, err)
+		}
 		composed, err := overrideMetadata(importedComponent, component)
 		if err != nil {
 			return v1alpha1.ZarfPackage{}, err
@@ -256,17 +297,78 @@ func validateComponentCompose(c v1alpha1.ZarfComponent) error {
 			errs = append(errs, errors.New("URL is not a valid OCI URL"))
 		}
 	}
+	if c.Import.Version != "" {
+		if c.Import.URL == "" {
+			errs = append(errs, errors.New("version can only be used with a URL"))
+		} else if ref, err := registry.ParseReference(strings.TrimPrefix(c.Import.URL, helpers.OCIURLPrefix)); err == nil && ref.Reference != "" {
+			errs = append(errs, errors.New("URL cannot include a tag or digest when a version is set"))
+		}
+		if _, err := semver.NewConstraint(c.Import.Version); err != nil {
+			errs = append(errs, fmt.Errorf("version %q is not a valid semver constraint: %w", c.Import.Version, err))
+		}
+	}
 	return errors.Join(errs...)
 }
 
-func compatibleComponent(c v1alpha1.ZarfComponent, arch, flavor string) bool {
-	satisfiesArch := c.Only.Cluster.Architecture == "" || c.Only.Cluster.Architecture == arch
-	satisfiesFlavor := c.Only.Flavor == "" || c.Only.Flavor == flavor
+// resolveImportVersion returns the URL of the import, tagged with the newest tag in the registry that satisfies the
+// version constraint when one is set.
+func resolveImportVersion(ctx context.Context, imp v1alpha1.ZarfComponentImport, remoteOptions types.RemoteOptions) (string, error) {
+	if imp.Version == "" {
+		return imp.URL, nil
+	}
+	remote, err := zoci.NewRemote(ctx, imp.URL, zoci.PlatformForSkeleton(),
+		oci.WithPlainHTTP(remoteOptions.PlainHTTP), oci.WithInsecureSkipVerify(remoteOptions.InsecureSkipTLSVerify))
+	if err != nil {
+		return "", err
+	}
+	tags, err := remote.ListTags(ctx)
+	if err != nil {
+		return "", err
+	}
+	tag, err := newestMatchingTag(imp.Version, tags)
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve the version of %s: %w", imp.URL, err)
+	}
+	logger.From(ctx).Info("resolved import version", "url", imp.URL, "version", imp.Version, "tag", tag)
+	return fmt.Sprintf("%s:%s", imp.URL, tag), nil
+}
+
+// newestMatchingTag returns the highest semver tag that satisfies the constraint, ignoring tags that are not semver.
+func newestMatchingTag(constraint string, tags []string) (string, error) {
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return "", fmt.Errorf("version %q is not a valid semver constraint: %w", constraint, err)
+	}
+	var newest *semver.Version
+	newestTag := ""
+	for _, tag := range tags {
+		v, err := semver.NewVersion(tag)
+		if err != nil || !c.Check(v) {
+			continue
+		}
+		if newest == nil || v.GreaterThan(newest) {
+			newest = v
+			newestTag = tag
+		}
+	}
+	if newest == nil {
+		available := slices.Sorted(slices.Values(tags))
+		if len(available) == 0 {
+			return "", fmt.Errorf("no tag satisfies version %q, the repository has no tags", constraint)
+		}
+		return "", fmt.Errorf("no tag satisfies version %q, available tags: %s", constraint, strings.Join(available, ", "))
+	}
+	return newestTag, nil
+}
+
+func compatibleComponent(c v1alpha1.ZarfComponent, arch, flavor string, allArchitectures, allFlavors bool) bool {
+	satisfiesArch := allArchitectures || c.Only.Cluster.Architecture == "" || c.Only.Cluster.Architecture == arch
+	satisfiesFlavor := allFlavors || c.Only.Flavor == "" || c.Only.Flavor == flavor
 	return satisfiesArch && satisfiesFlavor
 }
 
 // TODO (phillebaba): Refactor package structure so that pullOCI can be used instead.
-func fetchOCISkeleton(ctx context.Context, component v1alpha1.ZarfComponent, packagePath string, cachePath string, remoteOptions types.RemoteOptions) (string, error) {
+func fetchOCISkeleton(ctx context.Context, component v1alpha1.ZarfComponent, packagePath string, cachePath string, cacheEntry *importCacheEntry, remoteOptions types.RemoteOptions) (_ string, err error) {
 	if component.Import.URL == "" {
 		return component.Import.Path, nil
 	}
@@ -276,6 +378,20 @@ func fetchOCISkeleton(ctx context.Context, component v1alpha1.ZarfComponent, pac
 		name = component.Import.Name
 	}
 
+	abs, err := filepath.Abs(packagePath)
+	if err != nil {
+		return "", err
+	}
+
+	// Components extracted by an earlier create are reused while the digest of the import is unchanged
+	dir, cached, err := cacheEntry.componentDir(name)
+	if err != nil {
+		return "", err
+	}
+	if cached {
+		return filepath.Rel(abs, dir)
+	}
+
 	cache := filepath.Join(cachePath, "oci")
 	if err := helpers.CreateDirectory(cache, helpers.ReadWriteExecuteUser); err != nil {
 		return "", err
@@ -300,19 +416,21 @@ func fetchOCISkeleton(ctx context.Context, component v1alpha1.ZarfComponent, pac
 	if err != nil {
 		return "", err
 	}
+
+	// The component is extracted to a temporary directory and moved into the cache once complete
+	tmpDir, err := os.MkdirTemp(cacheEntry.dir, "component-*")
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		err = errors.Join(err, os.RemoveAll(tmpDir))
+	}()
+
 	componentDesc := manifest.Locate(filepath.Join(layout.ComponentsDir, fmt.Sprintf("%s.tar", name)))
-	var tarball, dir string
 	// If the descriptor for the component tarball was not found then all resources in the component are remote
 	// In this case, we represent the component with an empty directory
-	if oci.IsEmptyDescriptor(componentDesc) {
-		h := sha256.New()
-		h.Write([]byte(component.Import.URL + name))
-		id := fmt.Sprintf("%x", h.Sum(nil))
-
-		dir = filepath.Join(cache, "dirs", id)
-	} else {
-		tarball = filepath.Join(cache, "blobs", "sha256", componentDesc.Digest.Encoded())
-		dir = filepath.Join(cache, "dirs", componentDesc.Digest.Encoded())
+	if !oci.IsEmptyDescriptor(componentDesc) {
+		tarball := filepath.Join(cache, "blobs", "sha256", componentDesc.Digest.Encoded())
 		store, err := ocistore.New(cache)
 		if err != nil {
 			return "", err
@@ -327,37 +445,91 @@ func fetchOCISkeleton(ctx context.Context, component v1alpha1.ZarfComponent, pac
 				return "", err
 			}
 		}
-	}
 
-	if err := helpers.CreateDirectory(dir, helpers.ReadWriteExecuteUser); err != nil {
-		return "", err
+		decompressOpts := archive.DecompressOpts{
+			OverwriteExisting: true,
+			StripComponents:   1,
+			Extractor:         archives.Tar{},
+		}
+		err = archive.Decompress(ctx, tarball, tmpDir, decompressOpts)
+		if err != nil {
+			return "", fmt.Errorf("unable to extract archive %q: %w", tarball, err)
+		}
 	}
 
-	abs, err := filepath.Abs(packagePath)
-	if err != nil {
-		return "", err
-	}
-	rel, err := filepath.Rel(abs, dir)
-	if err != nil {
-		return "", err
+	if err := cacheEntry.storeComponentDir(tmpDir, name); err != nil {
+		return "", fmt.Errorf("unable to cache component %s of the import %s: %w", name, component.Import.URL, err)
 	}
+	return filepath.Rel(abs, dir)
+}
 
-	// If it is a remote component, there is nothing to extract
-	if oci.IsEmptyDescriptor(componentDesc) {
-		return rel, nil
+// selectImportParts keeps only the requested parts of an imported component.
+// When no parts are requested the whole component is imported.
+func selectImportParts(comp v1alpha1.ZarfComponent, parts []string) (v1alpha1.ZarfComponent, error) {
+	if len(parts) == 0 {
+		return comp, nil
 	}
 
-	decompressOpts := archive.DecompressOpts{
-		OverwriteExisting: true,
-		StripComponents:   1,
-		Extractor:         archives.Tar{},
+	selected := v1alpha1.ZarfComponent{
+		Name:        comp.Name,
+		Description: comp.Description,
+		Only:        comp.Only,
 	}
-	err = archive.Decompress(ctx, tarball, dir, decompressOpts)
-	if err != nil {
-		return "", fmt.Errorf("unable to extract archive %q: %w", tarball, err)
+	errs := []error{}
+	for _, part := range parts {
+		exists := true
+		switch part {
+		case "actions":
+			selected.Actions = comp.Actions
+			selected.DeprecatedScripts = comp.DeprecatedScripts
+			selected.EnvFile = comp.EnvFile
+			exists = hasActions(comp)
+		case "charts":
+			selected.Charts = comp.Charts
+			exists = len(comp.Charts) > 0
+		case "manifests":
+			selected.Manifests = comp.Manifests
+			exists = len(comp.Manifests) > 0
+		case "files":
+			selected.Files = comp.Files
+			exists = len(comp.Files) > 0
+		case "images":
+			selected.Images = comp.Images
+			selected.SkipImagePull = comp.SkipImagePull
+			exists = len(comp.Images) > 0
+		case "imageArchives":
+			selected.ImageArchives = comp.ImageArchives
+			exists = len(comp.ImageArchives) > 0
+		case "repos":
+			selected.Repos = comp.Repos
+			exists = len(comp.Repos) > 0
+		case "dataInjections":
+			selected.DataInjections = comp.DataInjections
+			exists = len(comp.DataInjections) > 0
+		case "healthChecks":
+			selected.HealthChecks = comp.HealthChecks
+			exists = len(comp.HealthChecks) > 0
+		default:
+			errs = append(errs, fmt.Errorf("unknown import part %q", part))
+			continue
+		}
+		if !exists {
+			errs = append(errs, fmt.Errorf("component %q does not define any %s to import", comp.Name, part))
+		}
 	}
+	if err := errors.Join(errs...); err != nil {
+		return v1alpha1.ZarfComponent{}, err
+	}
+	return selected, nil
+}
 
-	return rel, nil
+func hasActions(comp v1alpha1.ZarfComponent) bool {
+	for _, set := range []v1alpha1.ZarfComponentActionSet{comp.Actions.OnCreate, comp.Actions.OnDeploy, comp.Actions.OnRemove} {
+		if len(set.Before) > 0 || len(set.After) > 0 || len(set.OnSuccess) > 0 || len(set.OnFailure) > 0 {
+			return true
+		}
+	}
+	return len(comp.DeprecatedScripts.Before) > 0 || len(comp.DeprecatedScripts.After) > 0
 }
 
 func overrideMetadata(comp v1alpha1.ZarfComponent, override v1alpha1.ZarfComponent) (v1alpha1.ZarfComponent, error) {
@@ -365,12 +537,30 @@ func overrideMetadata(comp v1alpha1.ZarfComponent, override v1alpha1.ZarfCompone
 	comp.Name = override.Name
 	comp.Default = override.Default
 	comp.Required = override.Required
+	// Dependencies and conflicts refer to components of the importing package
+	comp.DependsOn = override.DependsOn
+	comp.ConflictsWith = override.ConflictsWith
 
 	// Override description if it was provided.
 	if override.Description != "" {
 		comp.Description = override.Description
 	}
 
+	// Override the retry policy if it was provided.
+	if override.RetryPolicy != nil {
+		comp.RetryPolicy = override.RetryPolicy
+	}
+
+	// Merge the resource annotations, the annotations of the importing component take precedence.
+	if len(override.ResourceAnnotations) > 0 {
+		annotations := maps.Clone(comp.ResourceAnnotations)
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		maps.Copy(annotations, override.ResourceAnnotations)
+		comp.ResourceAnnotations = annotations
+	}
+
 	// If the imported component has a flavor, mark the component with that flavor
 	if override.Only.Flavor != "" {
 		comp.Only.Flavor = override.Only.Flavor
@@ -405,6 +595,10 @@ func overrideDeprecated(comp v1alpha1.ZarfComponent, override v1alpha1.ZarfCompo
 }
 
 func overrideActions(comp v1alpha1.ZarfComponent, override v1alpha1.ZarfComponent) v1alpha1.ZarfComponent {
+	if override.EnvFile != "" {
+		comp.EnvFile = override.EnvFile
+	}
+
 	comp.Actions.OnCreate.Defaults = override.Actions.OnCreate.Defaults
 	comp.Actions.OnCreate.Before = append(comp.Actions.OnCreate.Before, override.Actions.OnCreate.Before...)
 	comp.Actions.OnCreate.After = append(comp.Actions.OnCreate.After, override.Actions.OnCreate.After...)
@@ -429,6 +623,8 @@ func overrideResources(comp v1alpha1.ZarfComponent, override v1alpha1.ZarfCompon
 	comp.DataInjections = append(comp.DataInjections, override.DataInjections...)
 	comp.Files = append(comp.Files, override.Files...)
 	comp.Images = append(comp.Images, override.Images...)
+	// Either the imported or the importing component can opt out of pulling images
+	comp.SkipImagePull = comp.SkipImagePull || override.SkipImagePull
 	comp.Repos = append(comp.Repos, override.Repos...)
 
 	// Merge charts with the same name to keep them unique
@@ -449,8 +645,10 @@ func overrideResources(comp v1alpha1.ZarfComponent, override v1alpha1.ZarfCompon
 					comp.Charts[idx].URL = overrideChart.URL
 				}
 				comp.Charts[idx].ValuesFiles = append(comp.Charts[idx].ValuesFiles, overrideChart.ValuesFiles...)
+				comp.Charts[idx].ValuesPriority = append(comp.Charts[idx].ValuesPriority, overrideChart.ValuesPriority...)
 				comp.Charts[idx].Variables = append(comp.Charts[idx].Variables, overrideChart.Variables...)
 				comp.Charts[idx].Values = append(comp.Charts[idx].Values, overrideChart.Values...)
+				comp.Charts[idx].PostRenderPatches = append(comp.Charts[idx].PostRenderPatches, overrideChart.PostRenderPatches...)
 				existing = true
 			}
 		}
@@ -512,6 +710,14 @@ func fixPaths(child v1alpha1.ZarfComponent, relativeToHead, packagePath string)
 			composed := makePathRelativeTo(valuesFile, relativeToHead)
 			child.Charts[chartIdx].ValuesFiles[valuesIdx] = composed
 		}
+		for priorityIdx, valuesFile := range chart.ValuesPriority {
+			composed := makePathRelativeTo(valuesFile, relativeToHead)
+			child.Charts[chartIdx].ValuesPriority[priorityIdx] = composed
+		}
+		for patchIdx, patch := range chart.PostRenderPatches {
+			composed := makePathRelativeTo(patch, relativeToHead)
+			child.Charts[chartIdx].PostRenderPatches[patchIdx] = composed
+		}
 		if child.Charts[chartIdx].LocalPath != "" {
 			composed := makePathRelativeTo(chart.LocalPath, relativeToHead)
 			child.Charts[chartIdx].LocalPath = composed
@@ -538,6 +744,10 @@ func fixPaths(child v1alpha1.ZarfComponent, relativeToHead, packagePath string)
 		child.DataInjections[dataInjectionsIdx].Source = composed
 	}
 
+	if child.EnvFile != "" {
+		child.EnvFile = makePathRelativeTo(child.EnvFile, relativeToHead)
+	}
+
 	defaultDir := child.Actions.OnCreate.Defaults.Dir
 	child.Actions.OnCreate.Before = fixActionPaths(child.Actions.OnCreate.Before, defaultDir, relativeToHead)
 	child.Actions.OnCreate.After = fixActionPaths(child.Actions.OnCreate.After, defaultDir, relativeToHead)
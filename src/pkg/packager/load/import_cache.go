@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package load
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	goyaml "github.com/goccy/go-yaml"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/internal/pkgcfg"
+	"github.com/zarf-dev/zarf/src/pkg/packager/layout"
+)
+
+// importCacheDir is the directory within the Zarf cache that holds the resolved OCI imports.
+const importCacheDir = "imports"
+
+// importCacheEntry holds the definition and extracted components of an OCI import at the digest of its root manifest,
+// so that later creates reuse them while the published package is unchanged. Entries are stored under
+// <cache>/imports/<sha256 of the URL>/<digest>.
+type importCacheEntry struct {
+	dir string
+	// refresh ignores the cached contents of the entry and replaces them with freshly fetched ones
+	refresh bool
+}
+
+// openImportCacheEntry returns the cache entry of the import URL at the digest of its root manifest. The entries of
+// the URL at any other digest are removed, the import changed since they were cached and must not be used again.
+func openImportCacheEntry(cachePath, url, digest string, refresh bool) (*importCacheEntry, error) {
+	urlDir := filepath.Join(cachePath, importCacheDir, fmt.Sprintf("%x", sha256.Sum256([]byte(url))))
+	entries, err := os.ReadDir(urlDir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.Name() == digest {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(urlDir, entry.Name())); err != nil {
+			return nil, fmt.Errorf("unable to remove the stale import cache entry of %s: %w", url, err)
+		}
+	}
+	dir := filepath.Join(urlDir, digest)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &importCacheEntry{dir: dir, refresh: refresh}, nil
+}
+
+// definition returns the cached package definition of the import, false is returned when it is not cached.
+func (e *importCacheEntry) definition(ctx context.Context) (v1alpha1.ZarfPackage, bool, error) {
+	if e.refresh {
+		return v1alpha1.ZarfPackage{}, false, nil
+	}
+	b, err := os.ReadFile(filepath.Join(e.dir, layout.ZarfYAML))
+	if errors.Is(err, os.ErrNotExist) {
+		return v1alpha1.ZarfPackage{}, false, nil
+	}
+	if err != nil {
+		return v1alpha1.ZarfPackage{}, false, err
+	}
+	pkg, err := pkgcfg.ParseMultiDoc(ctx, b)
+	if err != nil {
+		return v1alpha1.ZarfPackage{}, false, fmt.Errorf("unable to parse the cached definition of the import: %w", err)
+	}
+	return pkg, true, nil
+}
+
+// storeDefinition caches the package definition of the import.
+func (e *importCacheEntry) storeDefinition(pkg v1alpha1.ZarfPackage) error {
+	b, err := goyaml.Marshal(pkg)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(e.dir, layout.ZarfYAML), b)
+}
+
+// componentDir returns the directory the component of the import is extracted to and whether it was extracted by an
+// earlier create.
+func (e *importCacheEntry) componentDir(name string) (string, bool, error) {
+	dir := filepath.Join(e.dir, layout.ComponentsDir, name)
+	if e.refresh {
+		return dir, false, nil
+	}
+	fi, err := os.Stat(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return dir, false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return dir, fi.IsDir(), nil
+}
+
+// storeComponentDir moves a component that was extracted to tmpDir into the entry. The component is only moved once it
+// is completely extracted, so an interrupted create never leaves a partial component in the cache.
+func (e *importCacheEntry) storeComponentDir(tmpDir, name string) error {
+	dir := filepath.Join(e.dir, layout.ComponentsDir, name)
+	if err := os.MkdirAll(filepath.Dir(dir), 0o700); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	return os.Rename(tmpDir, dir)
+}
+
+// writeFileAtomic writes the file through a temporary file in the same directory so that readers never see it partially written.
+func writeFileAtomic(path string, b []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(b)
+	err = errors.Join(err, tmp.Close())
+	if err != nil {
+		return errors.Join(err, os.Remove(tmp.Name()))
+	}
+	return os.Rename(tmp.Name(), path)
+}
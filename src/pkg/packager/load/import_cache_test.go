@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package load
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/api/v1alpha1"
+	"github.com/zarf-dev/zarf/src/test/testutil"
+)
+
+func TestImportCacheEntry(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.TestContext(t)
+	cachePath := t.TempDir()
+	url := "oci://example.com/skeletons/test:1.0.0"
+
+	entry, err := openImportCacheEntry(cachePath, url, "aaa", false)
+	require.NoError(t, err)
+
+	_, cached, err := entry.definition(ctx)
+	require.NoError(t, err)
+	require.False(t, cached)
+
+	pkg := v1alpha1.ZarfPackage{
+		APIVersion: v1alpha1.APIVersion,
+		Kind:       v1alpha1.ZarfPackageConfig,
+		Metadata:   v1alpha1.ZarfMetadata{Name: "test"},
+		Components: []v1alpha1.ZarfComponent{{Name: "component"}},
+	}
+	require.NoError(t, entry.storeDefinition(pkg))
+	cachedPkg, cached, err := entry.definition(ctx)
+	require.NoError(t, err)
+	require.True(t, cached)
+	require.Equal(t, pkg.Metadata.Name, cachedPkg.Metadata.Name)
+	require.Equal(t, pkg.Components, cachedPkg.Components)
+
+	dir, cached, err := entry.componentDir("component")
+	require.NoError(t, err)
+	require.False(t, cached)
+	tmpDir, err := os.MkdirTemp(entry.dir, "component-*")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "file.txt"), []byte("hello"), 0o600))
+	require.NoError(t, entry.storeComponentDir(tmpDir, "component"))
+	require.NoDirExists(t, tmpDir)
+	cachedDir, cached, err := entry.componentDir("component")
+	require.NoError(t, err)
+	require.True(t, cached)
+	require.Equal(t, dir, cachedDir)
+	require.FileExists(t, filepath.Join(dir, "file.txt"))
+
+	// Refreshing ignores the cached contents without removing them
+	refreshed, err := openImportCacheEntry(cachePath, url, "aaa", true)
+	require.NoError(t, err)
+	_, cached, err = refreshed.definition(ctx)
+	require.NoError(t, err)
+	require.False(t, cached)
+	_, cached, err = refreshed.componentDir("component")
+	require.NoError(t, err)
+	require.False(t, cached)
+	require.DirExists(t, entry.dir)
+
+	// A different digest invalidates the entry
+	updated, err := openImportCacheEntry(cachePath, url, "bbb", false)
+	require.NoError(t, err)
+	require.NoDirExists(t, entry.dir)
+	_, cached, err = updated.definition(ctx)
+	require.NoError(t, err)
+	require.False(t, cached)
+
+	// Entries of other imports are left untouched
+	other, err := openImportCacheEntry(cachePath, "oci://example.com/skeletons/other:1.0.0", "aaa", false)
+	require.NoError(t, err)
+	require.DirExists(t, updated.dir)
+	require.NotEqual(t, updated.dir, other.dir)
+}
@@ -4,6 +4,7 @@
 package load
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -17,6 +18,8 @@ import (
 	"github.com/zarf-dev/zarf/src/pkg/value"
 	"github.com/zarf-dev/zarf/src/test/testutil"
 	"github.com/zarf-dev/zarf/src/types"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/registry/remote"
 )
 
 func TestResolveImportsCircular(t *testing.T) {
@@ -29,7 +32,7 @@ func TestResolveImportsCircular(t *testing.T) {
 	pkg, err := pkgcfg.Parse(ctx, b)
 	require.NoError(t, err)
 
-	_, err = resolveImports(ctx, pkg, "./testdata/import/circular/first", "", "", []string{}, "", false, types.RemoteOptions{})
+	_, err = resolveImports(ctx, pkg, "./testdata/import/circular/first", "", "", false, false, []string{}, "", false, false, types.RemoteOptions{})
 	require.EqualError(t, err, "package testdata/import/circular/second imported in cycle by testdata/import/circular/third in component component")
 }
 
@@ -40,7 +43,10 @@ func TestResolveImports(t *testing.T) {
 	testCases := []struct {
 		name             string
 		path             string
+		arch             string
+		allArchitectures bool
 		flavor           string
+		allFlavors       bool
 		expectedChecksum string
 	}{
 		{
@@ -94,6 +100,19 @@ func TestResolveImports(t *testing.T) {
 			flavor:           "pistachio",
 			expectedChecksum: "9c60125954b1b38a5947401411b87cde3d586e5ff8eef03bcc37dae1e24ab08e",
 		},
+		{
+			name:             "every flavor is kept and flavored components import their own flavor",
+			path:             "./testdata/import/all-flavors",
+			allFlavors:       true,
+			expectedChecksum: "c9e1f5464454ed67b073c52a0ccf69eee296db46ffc3f4ed6749020fee8f0384",
+		},
+		{
+			name:             "every architecture is kept and restricted components import their own architecture",
+			path:             "./testdata/import/all-architectures",
+			arch:             "amd64",
+			allArchitectures: true,
+			expectedChecksum: "bb7d609cfe2b0425be74b6f8cbf172f9f8f504fd87bdb32191324177606915f8",
+		},
 		{
 			name:             "chart version and url properties are not overridden",
 			path:             "./testdata/import/chart",
@@ -115,7 +134,7 @@ func TestResolveImports(t *testing.T) {
 			pkg, err := pkgcfg.Parse(ctx, b)
 			require.NoError(t, err)
 
-			resolvedPkg, err := resolveImports(ctx, pkg, tc.path, "", tc.flavor, []string{}, "", false, types.RemoteOptions{})
+			resolvedPkg, err := resolveImports(ctx, pkg, tc.path, tc.arch, tc.flavor, tc.allArchitectures, tc.allFlavors, []string{}, "", false, false, types.RemoteOptions{})
 			require.NoError(t, err)
 
 			b, err = os.ReadFile(filepath.Join(tc.path, "expected.yaml"))
@@ -150,7 +169,7 @@ func TestResolveImportsDedupNormalization(t *testing.T) {
 	// Reuse an existing fixture's directory only as the on-disk anchor — resolveImports
 	// stats the path but does not re-parse zarf.yaml when pkg is passed in.
 	resolved, err := resolveImports(ctx, pkg, "./testdata/import/values/duplicate-consecutive",
-		"", "", []string{}, "", false, types.RemoteOptions{})
+		"", "", false, false, []string{}, "", false, false, types.RemoteOptions{})
 	require.NoError(t, err)
 	require.Equal(t, []string{"parent-values.yaml"}, resolved.Values.Files)
 }
@@ -245,7 +264,7 @@ func TestResolveImportsValueMerge(t *testing.T) {
 			pkg, err := pkgcfg.Parse(ctx, b)
 			require.NoError(t, err)
 
-			resolved, err := resolveImports(ctx, pkg, tc.path, "", "", []string{}, "", false, types.RemoteOptions{})
+			resolved, err := resolveImports(ctx, pkg, tc.path, "", "", false, false, []string{}, "", false, false, types.RemoteOptions{})
 			require.NoError(t, err)
 
 			absPaths := make([]string, len(resolved.Values.Files))
@@ -349,6 +368,44 @@ func TestValidateComponentCompose(t *testing.T) {
 				"package templates are not supported for import path or URL",
 			},
 		},
+		{
+			name: "valid version constraint",
+			component: v1alpha1.ZarfComponent{
+				Name: "version",
+				Import: v1alpha1.ZarfComponentImport{
+					URL:     "oci://example.com/package",
+					Version: ">=1.2.0 <2.0.0",
+				},
+			},
+			expectedErrs: nil,
+		},
+		{
+			name: "version constraint with a tagged URL",
+			component: v1alpha1.ZarfComponent{
+				Name: "version",
+				Import: v1alpha1.ZarfComponentImport{
+					URL:     "oci://example.com/package:v0.0.1",
+					Version: "not-a-constraint",
+				},
+			},
+			expectedErrs: []string{
+				"URL cannot include a tag or digest when a version is set",
+				`version "not-a-constraint" is not a valid semver constraint: improper constraint: not-a-constraint`,
+			},
+		},
+		{
+			name: "version constraint with a path",
+			component: v1alpha1.ZarfComponent{
+				Name: "version",
+				Import: v1alpha1.ZarfComponentImport{
+					Path:    "relative/path",
+					Version: "^1.0.0",
+				},
+			},
+			expectedErrs: []string{
+				"version can only be used with a URL",
+			},
+		},
 		{
 			name: "package template URL provided",
 			component: v1alpha1.ZarfComponent{
@@ -379,15 +436,101 @@ func TestValidateComponentCompose(t *testing.T) {
 	}
 }
 
+func TestNewestMatchingTag(t *testing.T) {
+	t.Parallel()
+
+	tags := []string{"latest", "1.1.0", "v1.2.0", "1.10.1", "1.11.0-rc.1", "2.0.0", "1.3.0-upstream"}
+	tests := []struct {
+		name        string
+		constraint  string
+		tags        []string
+		expected    string
+		expectedErr string
+	}{
+		{
+			name:       "newest tag within a range",
+			constraint: ">=1.2.0 <2.0.0",
+			tags:       tags,
+			expected:   "1.10.1",
+		},
+		{
+			name:       "tags with a v prefix",
+			constraint: "~1.2",
+			tags:       tags,
+			expected:   "v1.2.0",
+		},
+		{
+			name:       "pre-releases are only matched when asked for",
+			constraint: ">=1.11.0-0 <2.0.0",
+			tags:       tags,
+			expected:   "1.11.0-rc.1",
+		},
+		{
+			name:        "no tag satisfies the constraint",
+			constraint:  "^3.0.0",
+			tags:        tags,
+			expectedErr: `no tag satisfies version "^3.0.0", available tags: 1.1.0, 1.10.1, 1.11.0-rc.1, 1.3.0-upstream, 2.0.0, latest, v1.2.0`,
+		},
+		{
+			name:        "no tags",
+			constraint:  "^1.0.0",
+			expectedErr: `no tag satisfies version "^1.0.0", the repository has no tags`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			tag, err := newestMatchingTag(tt.constraint, tt.tags)
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, tag)
+		})
+	}
+}
+
+func TestResolveImportVersion(t *testing.T) {
+	t.Parallel()
+
+	ctx := testutil.TestContext(t)
+	registryURL := testutil.SetupInMemoryRegistryDynamic(ctx, t)
+	repo, err := remote.NewRepository(fmt.Sprintf("%s/skeletons/versioned", registryURL))
+	require.NoError(t, err)
+	repo.PlainHTTP = true
+	desc, err := oras.PackManifest(ctx, repo, oras.PackManifestVersion1_1, "application/vnd.zarf.test", oras.PackManifestOptions{})
+	require.NoError(t, err)
+	for _, tag := range []string{"1.0.0", "1.2.3", "2.0.0"} {
+		require.NoError(t, repo.Tag(ctx, desc, tag))
+	}
+
+	url := fmt.Sprintf("oci://%s/skeletons/versioned", registryURL)
+	remoteOptions := types.RemoteOptions{PlainHTTP: true}
+
+	resolved, err := resolveImportVersion(ctx, v1alpha1.ZarfComponentImport{URL: url, Version: "<2.0.0"}, remoteOptions)
+	require.NoError(t, err)
+	require.Equal(t, url+":1.2.3", resolved)
+
+	resolved, err = resolveImportVersion(ctx, v1alpha1.ZarfComponentImport{URL: url + ":1.0.0"}, remoteOptions)
+	require.NoError(t, err)
+	require.Equal(t, url+":1.0.0", resolved)
+
+	_, err = resolveImportVersion(ctx, v1alpha1.ZarfComponentImport{URL: url, Version: ">=3.0.0"}, remoteOptions)
+	require.ErrorContains(t, err, "available tags: 1.0.0, 1.2.3, 2.0.0")
+}
+
 func TestCompatibleComponent(t *testing.T) {
 	t.Parallel()
 
 	tests := []struct {
-		name           string
-		component      v1alpha1.ZarfComponent
-		arch           string
-		flavor         string
-		expectedResult bool
+		name             string
+		component        v1alpha1.ZarfComponent
+		arch             string
+		flavor           string
+		allArchitectures bool
+		allFlavors       bool
+		expectedResult   bool
 	}{
 		{
 			name: "set architecture and set flavor",
@@ -459,13 +602,87 @@ func TestCompatibleComponent(t *testing.T) {
 			flavor:         "foo",
 			expectedResult: false,
 		},
+		{
+			name: "flavor miss match with all flavors",
+			component: v1alpha1.ZarfComponent{
+				Only: v1alpha1.ZarfComponentOnlyTarget{
+					Flavor: "bar",
+				},
+			},
+			arch:           "amd64",
+			allFlavors:     true,
+			expectedResult: true,
+		},
+		{
+			name: "architecture miss match with all architectures",
+			component: v1alpha1.ZarfComponent{
+				Only: v1alpha1.ZarfComponentOnlyTarget{
+					Cluster: v1alpha1.ZarfComponentOnlyCluster{
+						Architecture: "arm64",
+					},
+				},
+			},
+			arch:             "amd64",
+			allArchitectures: true,
+			expectedResult:   true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			result := compatibleComponent(tt.component, tt.arch, tt.flavor)
+			result := compatibleComponent(tt.component, tt.arch, tt.flavor, tt.allArchitectures, tt.allFlavors)
 			require.Equal(t, tt.expectedResult, result)
 		})
 	}
 }
+
+func TestResolveImportsParts(t *testing.T) {
+	t.Parallel()
+	ctx := testutil.TestContext(t)
+
+	path := "./testdata/import/parts"
+	b, err := os.ReadFile(filepath.Join(path, layout.ZarfYAML))
+	require.NoError(t, err)
+
+	t.Run("actions only", func(t *testing.T) {
+		t.Parallel()
+		pkg, err := pkgcfg.Parse(ctx, b)
+		require.NoError(t, err)
+
+		resolvedPkg, err := resolveImports(ctx, pkg, path, "", "", false, false, []string{}, "", false, false, types.RemoteOptions{})
+		require.NoError(t, err)
+		require.Len(t, resolvedPkg.Components, 1)
+		comp := resolvedPkg.Components[0]
+		require.Equal(t, "app", comp.Name)
+		require.True(t, comp.IsRequired())
+		require.Empty(t, comp.Files)
+		require.Len(t, comp.Manifests, 1)
+		require.Len(t, comp.Actions.OnDeploy.Before, 1)
+		require.Equal(t, "echo \"deploying the app\"", comp.Actions.OnDeploy.Before[0].Cmd)
+		require.Len(t, comp.Actions.OnDeploy.After, 1)
+		require.Equal(t, "echo \"waiting for the app\"", comp.Actions.OnDeploy.After[0].Cmd)
+	})
+
+	t.Run("requested part missing from source", func(t *testing.T) {
+		t.Parallel()
+		pkg, err := pkgcfg.Parse(ctx, b)
+		require.NoError(t, err)
+		pkg.Components[0].Import.Parts = []string{"actions", "charts"}
+
+		_, err = resolveImports(ctx, pkg, path, "", "", false, false, []string{}, "", false, false, types.RemoteOptions{})
+		require.EqualError(t, err, "invalid imported definition for app: component \"wait-for-app\" does not define any charts to import")
+	})
+
+	t.Run("full import by default", func(t *testing.T) {
+		t.Parallel()
+		pkg, err := pkgcfg.Parse(ctx, b)
+		require.NoError(t, err)
+		pkg.Components[0].Import.Parts = nil
+
+		resolvedPkg, err := resolveImports(ctx, pkg, path, "", "", false, false, []string{}, "", false, false, types.RemoteOptions{})
+		require.NoError(t, err)
+		require.Len(t, resolvedPkg.Components[0].Files, 1)
+		require.Equal(t, "library/readme.txt", resolvedPkg.Components[0].Files[0].Source)
+	})
+}
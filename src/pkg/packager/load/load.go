@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"time"
 
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
@@ -33,15 +34,29 @@ type DefinitionOptions struct {
 	// SkipRequiredValues ignores values schema validation errors when a "required" field is empty. Used when a package
 	// value should be supplied at deploy-time and doesn't have a default set in the package values.
 	SkipRequiredValues bool
+	// AllFlavors keeps the components of every flavor instead of only the components of Flavor
+	AllFlavors bool
+	// AllArchitectures keeps the components of every cluster architecture instead of only the components of the
+	// package architecture
+	AllArchitectures bool
 	// CachePath is used to cache layers from skeleton package pulls
 	CachePath string
 	// IsInteractive decides if Zarf can interactively prompt users through the CLI
 	IsInteractive bool
 	// SkipVersionCheck skips version requirement validation
 	SkipVersionCheck bool
+	// NoImportCache fetches every OCI import from its registry instead of reusing the imports cached by previous loads
+	NoImportCache bool
+	// Hooks transform the package definition in order once its imports are resolved, before it is templated and validated
+	Hooks []DefinitionHook
 	types.RemoteOptions
 }
 
+// DefinitionHook lets Go callers add, remove or modify the components, variables and other fields of a package
+// definition before it is created. Imports are resolved before the hooks run, so components added by a hook can not
+// import other components.
+type DefinitionHook func(ctx context.Context, pkg v1alpha1.ZarfPackage) (v1alpha1.ZarfPackage, error)
+
 // PackageDefinition returns a validated package definition after flavors, imports, variables, and values are applied.
 func PackageDefinition(ctx context.Context, packagePath string, opts DefinitionOptions) (v1alpha1.ZarfPackage, error) {
 	l := logger.From(ctx)
@@ -49,6 +64,8 @@ func PackageDefinition(ctx context.Context, packagePath string, opts DefinitionO
 	l.Debug("start layout.LoadPackage",
 		"path", packagePath,
 		"flavor", opts.Flavor,
+		"allFlavors", opts.AllFlavors,
+		"allArchitectures", opts.AllArchitectures,
 		"setVariables", opts.SetVariables,
 	)
 
@@ -70,7 +87,23 @@ func PackageDefinition(ctx context.Context, packagePath string, opts DefinitionO
 	if err != nil {
 		return v1alpha1.ZarfPackage{}, err
 	}
-	pkg, err = resolveImports(ctx, pkg, pkgPath.ManifestFile, pkg.Metadata.Architecture, opts.Flavor, []string{}, opts.CachePath, opts.SkipVersionCheck, opts.RemoteOptions)
+	pkg, err = resolveImports(ctx, pkg, pkgPath.ManifestFile, pkg.Metadata.Architecture, opts.Flavor, opts.AllArchitectures, opts.AllFlavors, []string{}, opts.CachePath, opts.SkipVersionCheck, opts.NoImportCache, opts.RemoteOptions)
+	if err != nil {
+		return v1alpha1.ZarfPackage{}, err
+	}
+
+	for i, hook := range opts.Hooks {
+		pkg, err = hook(ctx, pkg)
+		if err != nil {
+			return v1alpha1.ZarfPackage{}, fmt.Errorf("package definition hook %d failed: %w", i, err)
+		}
+	}
+
+	pkg, err = resolveEnvFiles(pkg, pkgPath.BaseDir)
+	if err != nil {
+		return v1alpha1.ZarfPackage{}, err
+	}
+	pkg, err = resolveValuesPriority(pkg)
 	if err != nil {
 		return v1alpha1.ZarfPackage{}, err
 	}
@@ -86,7 +119,7 @@ func PackageDefinition(ctx context.Context, packagePath string, opts DefinitionO
 			return v1alpha1.ZarfPackage{}, err
 		}
 	}
-	err = validate(ctx, pkg, pkgPath.ManifestFile, opts.SetVariables, opts.Flavor, opts.SkipRequiredValues)
+	err = validate(ctx, pkg, pkgPath.ManifestFile, opts.SetVariables, opts.Flavor, opts.AllFlavors, opts.SkipRequiredValues)
 	if err != nil {
 		return v1alpha1.ZarfPackage{}, err
 	}
@@ -94,7 +127,63 @@ func PackageDefinition(ctx context.Context, packagePath string, opts DefinitionO
 	return pkg, nil
 }
 
-func validate(ctx context.Context, pkg v1alpha1.ZarfPackage, packagePath string, setVariables map[string]string, flavor string, skipRequiredValues bool) error {
+// resolveEnvFiles records the entries of each component env file in the defaults of its action sets so that the
+// built package no longer depends on the file. The entries come first so the defaults and actions override them.
+func resolveEnvFiles(pkg v1alpha1.ZarfPackage, basePath string) (v1alpha1.ZarfPackage, error) {
+	for i, component := range pkg.Components {
+		if component.EnvFile == "" {
+			continue
+		}
+		path := component.EnvFile
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(basePath, path)
+		}
+		env, err := utils.ParseEnvFile(path)
+		if err != nil {
+			return v1alpha1.ZarfPackage{}, fmt.Errorf("unable to load the env file of component %s: %w", component.Name, err)
+		}
+		for _, set := range []*v1alpha1.ZarfComponentActionSet{&component.Actions.OnCreate, &component.Actions.OnDeploy, &component.Actions.OnRemove} {
+			merged := make([]string, 0, len(env)+len(set.Defaults.Env))
+			merged = append(merged, env...)
+			set.Defaults.Env = append(merged, set.Defaults.Env...)
+		}
+		component.EnvFile = ""
+		pkg.Components[i] = component
+	}
+	return pkg, nil
+}
+
+// resolveValuesPriority moves the values files listed in the valuesPriority of each chart to the end of its valuesFiles
+// so that the built package merges them last without depending on the field.
+func resolveValuesPriority(pkg v1alpha1.ZarfPackage) (v1alpha1.ZarfPackage, error) {
+	for i, component := range pkg.Components {
+		for j, chart := range component.Charts {
+			if len(chart.ValuesPriority) == 0 {
+				continue
+			}
+			valuesFiles := make([]string, 0, len(chart.ValuesFiles))
+			for _, valuesFile := range chart.ValuesFiles {
+				if !slices.Contains(chart.ValuesPriority, valuesFile) {
+					valuesFiles = append(valuesFiles, valuesFile)
+				}
+			}
+			for _, valuesFile := range chart.ValuesPriority {
+				if !slices.Contains(chart.ValuesFiles, valuesFile) {
+					return v1alpha1.ZarfPackage{}, fmt.Errorf("valuesPriority entry %s of chart %s in component %s is not one of its valuesFiles", valuesFile, chart.Name, component.Name)
+				}
+				if !slices.Contains(valuesFiles, valuesFile) {
+					valuesFiles = append(valuesFiles, valuesFile)
+				}
+			}
+			chart.ValuesFiles = valuesFiles
+			chart.ValuesPriority = nil
+			pkg.Components[i].Charts[j] = chart
+		}
+	}
+	return pkg, nil
+}
+
+func validate(ctx context.Context, pkg v1alpha1.ZarfPackage, packagePath string, setVariables map[string]string, flavor string, allFlavors, skipRequiredValues bool) error {
 	l := logger.From(ctx)
 	start := time.Now()
 	l.Debug("start layout.Validate",
@@ -104,7 +193,7 @@ func validate(ctx context.Context, pkg v1alpha1.ZarfPackage, packagePath string,
 		"setVariables", setVariables,
 	)
 
-	if !hasFlavoredComponent(pkg, flavor) {
+	if !allFlavors && !hasFlavoredComponent(pkg, flavor) {
 		l.Warn("flavor not used in package", "flavor", flavor)
 	}
 	if err := internalv1alpha1.ValidatePackage(pkg); err != nil {
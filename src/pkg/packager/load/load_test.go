@@ -5,7 +5,9 @@ package load
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -154,3 +156,168 @@ func TestPackageDefinitionWithValuesSchema(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveEnvFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	envFile := `# shared configuration
+REGION=###ZARF_VAR_REGION###
+export CLUSTER_NAME="dev"
+
+LOG_LEVEL='debug'
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "dev.env"), []byte(envFile), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "invalid.env"), []byte("REGION=us-east-1\nnot a pair\n"), 0o600))
+
+	pkg := v1alpha1.ZarfPackage{
+		Components: []v1alpha1.ZarfComponent{
+			{
+				Name:    "with-env-file",
+				EnvFile: "dev.env",
+				Actions: v1alpha1.ZarfComponentActions{
+					OnDeploy: v1alpha1.ZarfComponentActionSet{
+						Defaults: v1alpha1.ZarfComponentActionDefaults{Env: []string{"LOG_LEVEL=info"}},
+					},
+				},
+			},
+			{
+				Name: "without-env-file",
+			},
+		},
+	}
+	resolved, err := resolveEnvFiles(pkg, dir)
+	require.NoError(t, err)
+
+	fileEnv := []string{"REGION=###ZARF_VAR_REGION###", "CLUSTER_NAME=dev", "LOG_LEVEL=debug"}
+	comp := resolved.Components[0]
+	require.Empty(t, comp.EnvFile)
+	require.Equal(t, fileEnv, comp.Actions.OnCreate.Defaults.Env)
+	// The defaults of the action set come after the env file so they take precedence
+	require.Equal(t, append(fileEnv, "LOG_LEVEL=info"), comp.Actions.OnDeploy.Defaults.Env)
+	require.Equal(t, fileEnv, comp.Actions.OnRemove.Defaults.Env)
+	require.Empty(t, resolved.Components[1].Actions.OnDeploy.Defaults.Env)
+
+	pkg.Components[0].EnvFile = "invalid.env"
+	_, err = resolveEnvFiles(pkg, dir)
+	require.EqualError(t, err, "unable to load the env file of component with-env-file: line 2 of invalid.env is not a KEY=VALUE pair")
+}
+
+func TestResolveValuesPriority(t *testing.T) {
+	t.Parallel()
+
+	pkg := v1alpha1.ZarfPackage{
+		Components: []v1alpha1.ZarfComponent{
+			{
+				Name: "podinfo",
+				Charts: []v1alpha1.ZarfChart{
+					{
+						Name:           "podinfo",
+						ValuesFiles:    []string{"env/prod.yaml", "base.yaml", "override.yaml", "tuning.yaml"},
+						ValuesPriority: []string{"env/prod.yaml", "override.yaml"},
+					},
+					{
+						Name:        "unchanged",
+						ValuesFiles: []string{"b.yaml", "a.yaml"},
+					},
+				},
+			},
+		},
+	}
+	resolved, err := resolveValuesPriority(pkg)
+	require.NoError(t, err)
+	charts := resolved.Components[0].Charts
+	require.Equal(t, []string{"base.yaml", "tuning.yaml", "env/prod.yaml", "override.yaml"}, charts[0].ValuesFiles)
+	require.Empty(t, charts[0].ValuesPriority)
+	require.Equal(t, []string{"b.yaml", "a.yaml"}, charts[1].ValuesFiles)
+
+	pkg.Components[0].Charts[0].ValuesPriority = []string{"missing.yaml"}
+	_, err = resolveValuesPriority(pkg)
+	require.EqualError(t, err, "valuesPriority entry missing.yaml of chart podinfo in component podinfo is not one of its valuesFiles")
+}
+
+func TestPackageDefinitionHooks(t *testing.T) {
+	t.Parallel()
+
+	addComponent := func(name string) DefinitionHook {
+		return func(_ context.Context, pkg v1alpha1.ZarfPackage) (v1alpha1.ZarfPackage, error) {
+			pkg.Components = append(pkg.Components, v1alpha1.ZarfComponent{Name: name})
+			return pkg, nil
+		}
+	}
+
+	tests := []struct {
+		name               string
+		hooks              []DefinitionHook
+		expectedComponents []string
+		expectedVariables  []string
+		expectedErr        string
+	}{
+		{
+			name:               "hooks run in order",
+			expectedComponents: []string{"test-flavor", "generated", "generated-variables"},
+			expectedVariables:  []string{"ENVIRONMENT"},
+			hooks: []DefinitionHook{
+				addComponent("generated"),
+				func(_ context.Context, pkg v1alpha1.ZarfPackage) (v1alpha1.ZarfPackage, error) {
+					// The component added by the previous hook is visible
+					pkg.Components = append(pkg.Components, v1alpha1.ZarfComponent{Name: pkg.Components[len(pkg.Components)-1].Name + "-variables"})
+					pkg.Variables = append(pkg.Variables, v1alpha1.InteractiveVariable{Variable: v1alpha1.Variable{Name: "ENVIRONMENT"}})
+					return pkg, nil
+				},
+			},
+		},
+		{
+			name:               "hooks can remove components",
+			expectedComponents: []string{"generated"},
+			expectedVariables:  []string{},
+			hooks: []DefinitionHook{
+				addComponent("generated"),
+				func(_ context.Context, pkg v1alpha1.ZarfPackage) (v1alpha1.ZarfPackage, error) {
+					pkg.Components = pkg.Components[1:]
+					return pkg, nil
+				},
+			},
+		},
+		{
+			name:        "the package is validated after the hooks",
+			hooks:       []DefinitionHook{addComponent("test-flavor")},
+			expectedErr: "component name \"test-flavor\" is not unique",
+		},
+		{
+			name: "hook errors are returned",
+			hooks: []DefinitionHook{
+				func(_ context.Context, _ v1alpha1.ZarfPackage) (v1alpha1.ZarfPackage, error) {
+					return v1alpha1.ZarfPackage{}, errors.New("environment not found")
+				},
+			},
+			expectedErr: "package definition hook 0 failed: environment not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			opts := DefinitionOptions{
+				Flavor: "cashew",
+				Hooks:  tt.hooks,
+			}
+			pkg, err := PackageDefinition(context.Background(), filepath.Join("testdata", "package-with-flavors"), opts)
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			names := []string{}
+			for _, comp := range pkg.Components {
+				names = append(names, comp.Name)
+			}
+			require.Equal(t, tt.expectedComponents, names)
+			variables := []string{}
+			for _, v := range pkg.Variables {
+				variables = append(variables, v.Name)
+			}
+			require.Equal(t, tt.expectedVariables, variables)
+		})
+	}
+}
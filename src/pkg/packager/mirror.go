@@ -7,7 +7,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
 	"time"
 
 	"github.com/avast/retry-go/v4"
@@ -33,6 +35,8 @@ type ImagePushOptions struct {
 	NoImageChecksum bool
 	Retries         int
 	OCIConcurrency  int
+	// Force pushes images even if the registry already has them with the same digest
+	Force bool
 	types.RemoteOptions
 }
 
@@ -68,6 +72,8 @@ func PushImagesToRegistry(ctx context.Context, pkgLayout *layout.PackageLayout,
 		Retries:               opts.Retries,
 		InsecureSkipTLSVerify: opts.InsecureSkipTLSVerify,
 		Cluster:               opts.Cluster,
+		SkipExisting:          !opts.Force,
+		PinnedImages:          slices.Collect(maps.Values(pkgLayout.Pkg.Build.PinnedImages)),
 	}
 	err := images.Push(ctx, refs, pkgLayout.GetImageDirPath(), registryInfo, pushOpts)
 	if err != nil {
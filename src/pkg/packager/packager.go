@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"path/filepath"
 
+	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 	"github.com/zarf-dev/zarf/src/internal/packager/template"
 	"github.com/zarf-dev/zarf/src/pkg/value"
@@ -104,6 +105,26 @@ func generateValuesOverrides(_ context.Context, chart v1alpha1.ZarfChart, compon
 	return chartOverrides, nil
 }
 
+// manifestTemplateValues returns the values available to the templates of a manifest, which are the package values with
+// the variables of the manifest set at their paths.
+func manifestTemplateValues(manifest v1alpha1.ZarfManifest, variableConfig *variables.VariableConfig, values value.Values) (value.Values, error) {
+	manifestOverrides := make(value.Values)
+	for _, variable := range manifest.Variables {
+		if setVar, ok := variableConfig.GetSetVariable(variable.Name); ok && setVar != nil {
+			// Add leading dot to variable.Path to create a valid value.Path
+			path := "." + variable.Path
+			if err := manifestOverrides.Set(value.Path(path), setVar.Value); err != nil {
+				return nil, fmt.Errorf("unable to set value at path %s: %w", path, err)
+			}
+		}
+	}
+	if len(manifestOverrides) == 0 {
+		return values, nil
+	}
+	// MergeMapRecursive copies the maps it merges into so the package values are left untouched
+	return helpers.MergeMapRecursive(values, manifestOverrides), nil
+}
+
 // OverridePackageNamespace overrides the package namespace if the package contains only one unique namespace
 func OverridePackageNamespace(pkg *v1alpha1.ZarfPackage, namespace string) error {
 	if !pkg.AllowsNamespaceOverride() {
@@ -154,8 +175,16 @@ func overrideActionSetWaitNamespaces(set *v1alpha1.ZarfComponentActionSet, origi
 
 func overrideActionWaitNamespaces(actions []v1alpha1.ZarfComponentAction, original, target string) {
 	for i := range actions {
-		if actions[i].Wait != nil && actions[i].Wait.Cluster != nil && actions[i].Wait.Cluster.Namespace == original {
+		if actions[i].Wait == nil {
+			continue
+		}
+		if actions[i].Wait.Cluster != nil && actions[i].Wait.Cluster.Namespace == original {
 			actions[i].Wait.Cluster.Namespace = target
 		}
+		for j := range actions[i].Wait.Clusters {
+			if actions[i].Wait.Clusters[j].Namespace == original {
+				actions[i].Wait.Clusters[j].Namespace = target
+			}
+		}
 	}
 }
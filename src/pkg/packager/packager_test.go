@@ -3,6 +3,7 @@
 package packager
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -689,3 +690,52 @@ func Test_generateValuesOverrides_Errors(t *testing.T) {
 		})
 	}
 }
+
+func Test_manifestTemplateValues(t *testing.T) {
+	t.Parallel()
+
+	vc := variables.New("", nil, nil)
+	vc.SetVariable("IMAGE_TAG", "v1.2.3", false, false, "")
+
+	tests := []struct {
+		name     string
+		manifest v1alpha1.ZarfManifest
+		values   value.Values
+		expect   value.Values
+	}{
+		{
+			name:     "no variables returns the package values",
+			manifest: v1alpha1.ZarfManifest{Name: "app"},
+			values:   value.Values{"image": map[string]any{"repository": "nginx"}},
+			expect:   value.Values{"image": map[string]any{"repository": "nginx"}},
+		},
+		{
+			name: "variables are merged into the package values",
+			manifest: v1alpha1.ZarfManifest{
+				Name:      "app",
+				Variables: []v1alpha1.ZarfManifestVariable{{Name: "IMAGE_TAG", Path: "image.tag"}},
+			},
+			values: value.Values{"image": map[string]any{"repository": "nginx", "tag": "latest"}},
+			expect: value.Values{"image": map[string]any{"repository": "nginx", "tag": "v1.2.3"}},
+		},
+		{
+			name: "unset variables are skipped",
+			manifest: v1alpha1.ZarfManifest{
+				Name:      "app",
+				Variables: []v1alpha1.ZarfManifestVariable{{Name: "UNSET", Path: "image.tag"}},
+			},
+			values: value.Values{},
+			expect: value.Values{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			original := fmt.Sprint(tt.values)
+			result, err := manifestTemplateValues(tt.manifest, vc, tt.values)
+			require.NoError(t, err)
+			require.Equal(t, tt.expect, result)
+			require.Equal(t, original, fmt.Sprint(tt.values))
+		})
+	}
+}
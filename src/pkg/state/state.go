@@ -7,13 +7,19 @@ package state
 import (
 	"context"
 	"fmt"
+	"path"
 	"regexp"
+	"slices"
+	"strings"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 	"github.com/zarf-dev/zarf/src/config/lang"
 	"github.com/zarf-dev/zarf/src/pkg/logger"
 	"github.com/zarf-dev/zarf/src/pkg/pki"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 // Declares secrets and metadata keys and values.
@@ -161,6 +167,103 @@ type State struct {
 	RegistryInfo RegistryInfo `json:"registryInfo"`
 	// Information about the artifact registry Zarf is configured to use
 	ArtifactServer ArtifactServerInfo `json:"artifactServer"`
+	// Custom resource fields holding image references that the agent rewrites to the Zarf registry
+	AgentImageFields []AgentImageField `json:"agentImageFields,omitempty"`
+	// Rules that point the agent at a destination other than the Zarf registry for the images of matching registries
+	AgentImageRewrites []AgentImageRewrite `json:"agentImageRewrites,omitempty"`
+	// Registry patterns whose images the agent leaves untouched
+	AgentImagePassthrough []string `json:"agentImagePassthrough,omitempty"`
+}
+
+// AgentImageRewrite rewrites the images of the registries matching Source to Destination instead of the Zarf registry.
+type AgentImageRewrite struct {
+	// Source is a glob matched against the registry of an image, e.g. quay.io or *.dkr.ecr.us-east-1.amazonaws.com
+	Source string `json:"source"`
+	// Destination is the registry and optional path prefix the image path is appended to, e.g. mirror.example.com/quay
+	Destination string `json:"destination"`
+}
+
+// ParseAgentImageRewrites parses image rewrite rules in the form <registry>=<destination>.
+func ParseAgentImageRewrites(rewrites []string) ([]AgentImageRewrite, error) {
+	parsed := []AgentImageRewrite{}
+	for _, rewrite := range rewrites {
+		source, destination, ok := strings.Cut(rewrite, "=")
+		if !ok || source == "" || destination == "" {
+			return nil, fmt.Errorf("invalid agent image rewrite %q, must be in the form <registry>=<destination>", rewrite)
+		}
+		if _, err := path.Match(source, ""); err != nil {
+			return nil, fmt.Errorf("invalid agent image rewrite %q: %w", rewrite, err)
+		}
+		if strings.Contains(destination, "://") {
+			return nil, fmt.Errorf("invalid agent image rewrite %q, the destination must not include a scheme", rewrite)
+		}
+		parsed = append(parsed, AgentImageRewrite{Source: source, Destination: strings.TrimSuffix(destination, "/")})
+	}
+	return parsed, nil
+}
+
+// ValidateAgentImagePassthrough returns an error for passthrough registry patterns that are not valid globs.
+func ValidateAgentImagePassthrough(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+			return fmt.Errorf("invalid agent image passthrough registry %q", pattern)
+		}
+	}
+	return nil
+}
+
+// AgentImageField points the agent at the fields of a custom resource that hold image references.
+type AgentImageField struct {
+	// Group of the custom resource, e.g. monitoring.coreos.com
+	Group string `json:"group"`
+	// Version of the custom resource, "*" matches every version
+	Version string `json:"version"`
+	// Kind of the custom resource, e.g. Prometheus
+	Kind string `json:"kind"`
+	// JSONPath expressions to the image fields, e.g. .spec.image or .spec.containers[*].image
+	Paths []string `json:"paths"`
+}
+
+// Matches returns true if the field applies to resources of the given group, version and kind.
+func (f AgentImageField) Matches(group, version, kind string) bool {
+	return f.Group == group && f.Kind == kind && (f.Version == "*" || f.Version == version)
+}
+
+// AgentWebhookRules returns the admission rules the agent webhook needs to receive the custom resources of the fields.
+// The resource of each kind is its lowercase plural, as custom resource definitions are not required to exist at init.
+func AgentWebhookRules(fields []AgentImageField) []admissionregistrationv1.Rule {
+	rules := []admissionregistrationv1.Rule{}
+	for _, f := range fields {
+		plural, _ := meta.UnsafeGuessKindToResource(schema.GroupVersionKind{Group: f.Group, Version: f.Version, Kind: f.Kind})
+		rules = append(rules, admissionregistrationv1.Rule{
+			APIGroups:   []string{f.Group},
+			APIVersions: []string{f.Version},
+			Resources:   []string{plural.Resource},
+		})
+	}
+	return rules
+}
+
+// ParseAgentImageFields parses image fields in the form <group>/<version>/<kind>=<jsonpath>, merging the paths of
+// fields that target the same resource.
+func ParseAgentImageFields(fields []string) ([]AgentImageField, error) {
+	parsed := []AgentImageField{}
+	for _, field := range fields {
+		gvk, path, ok := strings.Cut(field, "=")
+		parts := strings.Split(gvk, "/")
+		if !ok || path == "" || len(parts) != 3 || slices.Contains(parts, "") {
+			return nil, fmt.Errorf("invalid agent image field %q, must be in the form <group>/<version>/<kind>=<jsonpath>", field)
+		}
+		idx := slices.IndexFunc(parsed, func(f AgentImageField) bool {
+			return f.Group == parts[0] && f.Version == parts[1] && f.Kind == parts[2]
+		})
+		if idx == -1 {
+			parsed = append(parsed, AgentImageField{Group: parts[0], Version: parts[1], Kind: parts[2]})
+			idx = len(parsed) - 1
+		}
+		parsed[idx].Paths = append(parsed[idx].Paths, path)
+	}
+	return parsed, nil
 }
 
 // AgentIsConfigured returns true when Zarf has agent TLS configured.
@@ -655,6 +758,8 @@ type DeployedComponent struct {
 	InstalledCharts    []InstalledChart `json:"installedCharts"`
 	Status             ComponentStatus  `json:"status"`
 	ObservedGeneration int              `json:"observedGeneration"`
+	// Attempts is the number of times the deployment of the component was attempted, set when it has a retry policy
+	Attempts int `json:"attempts,omitempty"`
 }
 
 // ChartStatus is the status of a Helm Chart release
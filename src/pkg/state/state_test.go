@@ -11,6 +11,7 @@ import (
 	"github.com/defenseunicorns/pkg/helpers/v2"
 	"github.com/stretchr/testify/require"
 	"github.com/zarf-dev/zarf/src/pkg/pki"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 )
 
 func TestAgentIsConfigured(t *testing.T) {
@@ -21,6 +22,68 @@ func TestAgentIsConfigured(t *testing.T) {
 	require.True(t, (&State{AgentTLS: pki.GeneratedPKI{Cert: []byte("cert")}}).AgentIsConfigured())
 }
 
+func TestParseAgentImageFields(t *testing.T) {
+	t.Parallel()
+
+	fields, err := ParseAgentImageFields([]string{
+		"monitoring.coreos.com/v1/Prometheus=.spec.image",
+		"monitoring.coreos.com/*/Alertmanager=.spec.image",
+		"monitoring.coreos.com/v1/Prometheus=.spec.containers[*].image",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []AgentImageField{
+		{Group: "monitoring.coreos.com", Version: "v1", Kind: "Prometheus", Paths: []string{".spec.image", ".spec.containers[*].image"}},
+		{Group: "monitoring.coreos.com", Version: "*", Kind: "Alertmanager", Paths: []string{".spec.image"}},
+	}, fields)
+	require.True(t, fields[1].Matches("monitoring.coreos.com", "v1alpha1", "Alertmanager"))
+	require.False(t, fields[0].Matches("monitoring.coreos.com", "v1alpha1", "Prometheus"))
+	require.Equal(t, []admissionregistrationv1.Rule{
+		{APIGroups: []string{"monitoring.coreos.com"}, APIVersions: []string{"v1"}, Resources: []string{"prometheuses"}},
+		{APIGroups: []string{"monitoring.coreos.com"}, APIVersions: []string{"*"}, Resources: []string{"alertmanagers"}},
+	}, AgentWebhookRules(fields))
+	require.Empty(t, AgentWebhookRules(nil))
+
+	for _, invalid := range []string{
+		"monitoring.coreos.com/v1/Prometheus",
+		"monitoring.coreos.com/v1/Prometheus=",
+		"v1/Prometheus=.spec.image",
+		"monitoring.coreos.com//Prometheus=.spec.image",
+	} {
+		_, err := ParseAgentImageFields([]string{invalid})
+		require.ErrorContains(t, err, "must be in the form <group>/<version>/<kind>=<jsonpath>")
+	}
+}
+
+func TestParseAgentImageRewrites(t *testing.T) {
+	t.Parallel()
+
+	rewrites, err := ParseAgentImageRewrites([]string{
+		"quay.io=mirror.example.com/quay/",
+		"*.gcr.io=mirror.example.com",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []AgentImageRewrite{
+		{Source: "quay.io", Destination: "mirror.example.com/quay"},
+		{Source: "*.gcr.io", Destination: "mirror.example.com"},
+	}, rewrites)
+
+	for _, invalid := range []string{
+		"quay.io",
+		"quay.io=",
+		"=mirror.example.com",
+	} {
+		_, err := ParseAgentImageRewrites([]string{invalid})
+		require.ErrorContains(t, err, "must be in the form <registry>=<destination>")
+	}
+	_, err = ParseAgentImageRewrites([]string{"[quay.io=mirror.example.com"})
+	require.ErrorContains(t, err, "syntax error in pattern")
+	_, err = ParseAgentImageRewrites([]string{"quay.io=https://mirror.example.com"})
+	require.ErrorContains(t, err, "must not include a scheme")
+
+	require.NoError(t, ValidateAgentImagePassthrough([]string{"*.dkr.ecr.us-east-1.amazonaws.com"}))
+	require.Error(t, ValidateAgentImagePassthrough([]string{"[ecr"}))
+}
+
 // TODO: Change password gen method to make testing possible.
 func TestMergeStateRegistry(t *testing.T) {
 	t.Parallel()
@@ -0,0 +1,124 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package tracing creates OpenTelemetry spans for the operations of a Zarf deploy.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/zarf-dev/zarf/src/config"
+)
+
+// instrumentationName identifies the spans created by Zarf.
+const instrumentationName = "github.com/zarf-dev/zarf"
+
+// Span attribute keys.
+const (
+	PackageKey   = attribute.Key("zarf.package")
+	ComponentKey = attribute.Key("zarf.component")
+	ChartKey     = attribute.Key("zarf.chart")
+	ManifestKey  = attribute.Key("zarf.manifest")
+	ActionKey    = attribute.Key("zarf.action")
+	OutcomeKey   = attribute.Key("zarf.outcome")
+	RunIDKey     = attribute.Key("zarf.run_id")
+)
+
+// Span outcomes.
+const (
+	OutcomeSucceeded = "succeeded"
+	OutcomeFailed    = "failed"
+	OutcomeSkipped   = "skipped"
+)
+
+// ctxKey provides a location to store a tracer provider in a context.
+type ctxKey struct{}
+
+// WithContext takes a context.Context and a trace.TracerProvider, storing it on the key
+func WithContext(ctx context.Context, tp trace.TracerProvider) context.Context {
+	return context.WithValue(ctx, ctxKey{}, tp)
+}
+
+// From takes a context and reads out a trace.TracerProvider. If From does not find a value it will return a no-op
+// provider so spans are only recorded when tracing was configured.
+func From(ctx context.Context) trace.TracerProvider {
+	if ctx == nil {
+		return noop.NewTracerProvider()
+	}
+	tp, ok := ctx.Value(ctxKey{}).(trace.TracerProvider)
+	if !ok {
+		return noop.NewTracerProvider()
+	}
+	return tp
+}
+
+// Start creates a span with the tracer provider of the context, the span is a child of the span in the context if any.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return From(ctx).Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records the outcome of the operation on the span and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.SetAttributes(OutcomeKey.String(OutcomeFailed))
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else {
+		span.SetAttributes(OutcomeKey.String(OutcomeSucceeded))
+		span.SetStatus(codes.Ok, "")
+	}
+	span.End()
+}
+
+// NewTracerProvider creates a tracer provider that exports spans over OTLP/HTTP to the endpoint URL, e.g.
+// http://localhost:4318. Every span carries the run ID so traces can be correlated with the logs of the command.
+// The provider must be shut down to flush the remaining spans.
+func NewTracerProvider(ctx context.Context, endpoint, runID string, opts ...sdktrace.TracerProviderOption) (*sdktrace.TracerProvider, error) {
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create the OTLP exporter for %s: %w", endpoint, err)
+	}
+	return newTracerProvider(runID, append(opts, sdktrace.WithBatcher(exporter))...), nil
+}
+
+func newTracerProvider(runID string, opts ...sdktrace.TracerProviderOption) *sdktrace.TracerProvider {
+	res := resource.NewSchemaless(
+		attribute.String("service.name", "zarf"),
+		attribute.String("service.version", config.CLIVersion),
+	)
+	opts = append([]sdktrace.TracerProviderOption{
+		sdktrace.WithResource(res),
+		sdktrace.WithSpanProcessor(runIDProcessor{runID: runID}),
+	}, opts...)
+	return sdktrace.NewTracerProvider(opts...)
+}
+
+// runIDProcessor sets the run ID attribute on every span when it starts.
+type runIDProcessor struct {
+	runID string
+}
+
+// OnStart sets the run ID on the span.
+func (p runIDProcessor) OnStart(_ context.Context, s sdktrace.ReadWriteSpan) {
+	if p.runID != "" {
+		s.SetAttributes(RunIDKey.String(p.runID))
+	}
+}
+
+// OnEnd does nothing as the run ID is set when the span starts.
+func (runIDProcessor) OnEnd(sdktrace.ReadOnlySpan) {}
+
+// Shutdown does nothing as the processor holds no resources.
+func (runIDProcessor) Shutdown(context.Context) error { return nil }
+
+// ForceFlush does nothing as the processor does not buffer spans.
+func (runIDProcessor) ForceFlush(context.Context) error { return nil }
@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestStartWithoutProvider(t *testing.T) {
+	t.Parallel()
+
+	_, span := Start(context.Background(), "deploy package")
+	require.False(t, span.IsRecording())
+	End(span, nil)
+}
+
+func TestSpans(t *testing.T) {
+	t.Parallel()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := newTracerProvider("0123456789abcdef", sdktrace.WithSyncer(exporter))
+	ctx := WithContext(context.Background(), tp)
+
+	ctx, parent := Start(ctx, "deploy package", PackageKey.String("podinfo"))
+	_, child := Start(ctx, "deploy component", ComponentKey.String("podinfo"))
+	End(child, errors.New("chart failed"))
+	End(parent, nil)
+	// The exporter drops its spans on shutdown so they are read first
+	spans := exporter.GetSpans()
+	require.NoError(t, tp.Shutdown(context.Background()))
+	require.Len(t, spans, 2)
+	component, pkg := spans[0], spans[1]
+
+	require.Equal(t, "deploy component", component.Name)
+	require.Equal(t, pkg.SpanContext.SpanID(), component.Parent.SpanID())
+	require.Equal(t, codes.Error, component.Status.Code)
+	require.ElementsMatch(t, []attribute.KeyValue{
+		ComponentKey.String("podinfo"),
+		RunIDKey.String("0123456789abcdef"),
+		OutcomeKey.String(OutcomeFailed),
+	}, component.Attributes)
+
+	require.Equal(t, "deploy package", pkg.Name)
+	require.Equal(t, codes.Ok, pkg.Status.Code)
+	require.ElementsMatch(t, []attribute.KeyValue{
+		PackageKey.String("podinfo"),
+		RunIDKey.String("0123456789abcdef"),
+		OutcomeKey.String(OutcomeSucceeded),
+	}, pkg.Attributes)
+}
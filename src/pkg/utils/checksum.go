@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+// Package utils provides generic helper functions.
+package utils
+
+import (
+	"crypto"
+	// Register the hash implementations used by ParseChecksum.
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/defenseunicorns/pkg/helpers/v2"
+)
+
+var checksumAlgorithms = map[string]crypto.Hash{
+	"sha256": crypto.SHA256,
+	"sha512": crypto.SHA512,
+}
+
+// ParseChecksum splits an algorithm prefixed checksum (e.g. sha512:abc...) into its hash and hex digest.
+// Checksums without a prefix are treated as SHA256.
+func ParseChecksum(checksum string) (string, crypto.Hash, string, error) {
+	algorithm, digest, found := strings.Cut(checksum, ":")
+	if !found {
+		return "sha256", crypto.SHA256, checksum, nil
+	}
+	algorithm = strings.ToLower(algorithm)
+	hash, ok := checksumAlgorithms[algorithm]
+	if !ok {
+		return "", 0, "", fmt.Errorf("unsupported checksum algorithm %q, must be one of sha256 or sha512", algorithm)
+	}
+	return algorithm, hash, digest, nil
+}
+
+// ChecksumMatches verifies that the file at path matches the given checksum.
+// The checksum may be prefixed with its algorithm and defaults to SHA256.
+func ChecksumMatches(path, checksum string) error {
+	algorithm, hash, expected, err := ParseChecksum(checksum)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	actual, err := helpers.GetCryptoHash(f, hash)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(actual, expected) {
+		return fmt.Errorf("expected %s of %s to be %s, found %s", algorithm, path, expected, actual)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecksumMatches(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "file.txt")
+	require.NoError(t, os.WriteFile(path, []byte("zarf"), 0o600))
+
+	sha256sum := "34a1bb201e0005920ad900ba59e5335a385ef90746d912ac9b257238548fefaa"
+	sha512sum := "74385fa6911a7d9b57a8d68e34651980686d1b12a3cbdc7eaaa578af7f372b0489a3b222f59dde18cef500e507d2d9860ebf39590aaaf05df01995dc897ecf0e"
+	tests := []struct {
+		name        string
+		checksum    string
+		expectedErr string
+	}{
+		{
+			name:     "plain hex defaults to sha256",
+			checksum: sha256sum,
+		},
+		{
+			name:     "sha256 prefix",
+			checksum: "sha256:" + sha256sum,
+		},
+		{
+			name:     "sha512 prefix",
+			checksum: "sha512:" + sha512sum,
+		},
+		{
+			name:     "uppercase prefix and digest",
+			checksum: "SHA512:" + strings.ToUpper(sha512sum),
+		},
+		{
+			name:        "sha512 mismatch",
+			checksum:    "sha512:" + sha256sum,
+			expectedErr: "expected sha512 of",
+		},
+		{
+			name:        "unknown algorithm",
+			checksum:    "md5:" + sha256sum,
+			expectedErr: `unsupported checksum algorithm "md5"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ChecksumMatches(path, tt.checksum)
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
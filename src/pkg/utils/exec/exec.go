@@ -28,6 +28,9 @@ type Config struct {
 	Dir            string
 	Env            []string
 	CommandPrinter func(format string, a ...any)
+	// Stdout and Stderr receive the outputs of the command as they are produced, in addition to the returned outputs.
+	Stdout io.Writer
+	Stderr io.Writer
 }
 
 // PrintCfg is a helper function for returning a Config struct with Print set to true.
@@ -100,6 +103,13 @@ func CmdWithContext(ctx context.Context, cfg Config, command string, args ...str
 		}
 	}
 
+	if cfg.Stdout != nil {
+		stdoutWriters = append(stdoutWriters, cfg.Stdout)
+	}
+	if cfg.Stderr != nil {
+		stdErrWriters = append(stdErrWriters, cfg.Stderr)
+	}
+
 	// Bind all the writers.
 	stdout := io.MultiWriter(stdoutWriters...)
 	stderr := io.MultiWriter(stdErrWriters...)
@@ -130,7 +140,20 @@ func CmdWithContext(ctx context.Context, cfg Config, command string, args ...str
 	}()
 
 	// Wait for the goroutines to finish (if any).
-	wg.Wait()
+	copied := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(copied)
+	}()
+	select {
+	case <-copied:
+	case <-ctx.Done():
+		// The command is killed once the context is done, but processes it started can keep the outputs open.
+		// Wait closes the pipes so the goroutines finish without waiting on those processes.
+		err := cmd.Wait()
+		<-copied
+		return stdoutBuf.String(), stderrBuf.String(), err
+	}
 
 	// Abort if there was an error capturing the command's outputs.
 	if errStdout != nil {
@@ -144,6 +167,19 @@ func CmdWithContext(ctx context.Context, cfg Config, command string, args ...str
 	return stdoutBuf.String(), stderrBuf.String(), cmd.Wait()
 }
 
+// ExitCode returns the exit code of a command from the error returned by running it. It returns false when the error is
+// not the exit of the command, e.g. when the command could not be started.
+func ExitCode(err error) (int, bool) {
+	if err == nil {
+		return 0, true
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), true
+	}
+	return 0, false
+}
+
 // LaunchURL opens a URL in the default browser.
 func LaunchURL(url string) error {
 	switch runtime.GOOS {
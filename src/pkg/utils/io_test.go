@@ -73,3 +73,22 @@ func TestGetFinalExecutableCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestParseEnvFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".env")
+	content := "# comment\n\nexport REGION=us-east-1\nNAME = \"app name\"\nTOKEN='s3cr3t'\nEMPTY=\nURL=http://host?a=b\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	env, err := ParseEnvFile(path)
+	require.NoError(t, err)
+	require.Equal(t, []string{"REGION=us-east-1", "NAME=app name", "TOKEN=s3cr3t", "EMPTY=", "URL=http://host?a=b"}, env)
+
+	require.NoError(t, os.WriteFile(path, []byte("REGION=us-east-1\nnot a pair\n"), 0o600))
+	_, err = ParseEnvFile(path)
+	require.EqualError(t, err, "line 2 of .env is not a KEY=VALUE pair")
+
+	_, err = ParseEnvFile(filepath.Join(dir, "missing.env"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
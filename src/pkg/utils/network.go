@@ -51,8 +51,21 @@ func parseChecksum(src string) (string, string, error) {
 	return src, checksum, nil
 }
 
+// DownloadOptions configures how a file is downloaded.
+type DownloadOptions struct {
+	// MaxRetries is the number of times a failed download is retried, the Zarf default is used when zero.
+	MaxRetries int
+	// Timeout limits each download attempt, attempts are not limited when zero.
+	Timeout time.Duration
+}
+
 // DownloadToFile downloads a given URL to the target filepath (including the cosign key if necessary).
-func DownloadToFile(ctx context.Context, src, dst string) (err error) {
+func DownloadToFile(ctx context.Context, src, dst string) error {
+	return DownloadToFileWithOptions(ctx, src, dst, DownloadOptions{})
+}
+
+// DownloadToFileWithOptions downloads a given URL to the target filepath, retrying and timing out each attempt as configured.
+func DownloadToFileWithOptions(ctx context.Context, src, dst string, opts DownloadOptions) (err error) {
 	// check if the parsed URL has a checksum
 	// if so, remove it and use the checksum to validate the file
 	src, checksum, err := parseChecksum(src)
@@ -65,19 +78,31 @@ func DownloadToFile(ctx context.Context, src, dst string) (err error) {
 		return fmt.Errorf(lang.ErrCreatingDir, filepath.Dir(dst), err)
 	}
 
+	maxAttempts := config.ZarfDefaultRetries
+	if opts.MaxRetries > 0 {
+		maxAttempts = opts.MaxRetries + 1
+	}
+	attempts := 0
 	l := logger.From(ctx)
 	err = retry.Do(
 		func() error {
+			attempts++
 			// Create the file
 			file, createErr := os.Create(dst)
 			if createErr != nil {
 				return retry.Unrecoverable(fmt.Errorf(lang.ErrWritingFile, dst, createErr))
 			}
-			getErr := httpGetFile(ctx, src, file)
+			attemptCtx := ctx
+			if opts.Timeout > 0 {
+				var cancel context.CancelFunc
+				attemptCtx, cancel = context.WithTimeout(ctx, opts.Timeout)
+				defer cancel()
+			}
+			getErr := httpGetFile(attemptCtx, src, file)
 			closeErr := file.Close()
 			return errors.Join(getErr, closeErr)
 		},
-		retry.Attempts(uint(config.ZarfDefaultRetries)),
+		retry.Attempts(uint(maxAttempts)),
 		retry.Delay(config.ZarfDefaultRetryDelay),
 		retry.MaxDelay(config.ZarfDefaultRetryMaxDelay),
 		retry.DelayType(func(n uint, err error, rc *retry.Config) time.Duration {
@@ -90,10 +115,10 @@ func DownloadToFile(ctx context.Context, src, dst string) (err error) {
 		retry.LastErrorOnly(true),
 		retry.Context(ctx),
 		retry.OnRetry(func(n uint, err error) {
-			if config.ZarfDefaultRetries > 1 && n+1 < uint(config.ZarfDefaultRetries) {
+			if maxAttempts > 1 && n+1 < uint(maxAttempts) {
 				l.Warn("retrying download",
 					"attempt", n+1,
-					"maxAttempts", config.ZarfDefaultRetries,
+					"maxAttempts", maxAttempts,
 					"url", src,
 					"error", err,
 				)
@@ -101,7 +126,7 @@ func DownloadToFile(ctx context.Context, src, dst string) (err error) {
 		}),
 	)
 	if err != nil {
-		return err
+		return fmt.Errorf("download failed after %d attempt(s): %w", attempts, err)
 	}
 
 	// If the file has a checksum, validate it
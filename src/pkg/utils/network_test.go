@@ -8,10 +8,13 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"path"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/zarf-dev/zarf/src/test/testutil"
 
@@ -151,3 +154,70 @@ func TestDownloadToFile(t *testing.T) {
 		})
 	}
 }
+
+func TestDownloadToFileWithOptions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		failures      int32
+		delay         time.Duration
+		opts          DownloadOptions
+		expectedCalls int32
+		expectedErr   string
+	}{
+		{
+			name:          "succeeds after retrying server errors",
+			failures:      2,
+			opts:          DownloadOptions{MaxRetries: 3},
+			expectedCalls: 3,
+		},
+		{
+			name:          "fails once retries are exhausted",
+			failures:      5,
+			opts:          DownloadOptions{MaxRetries: 1},
+			expectedCalls: 2,
+			expectedErr:   "download failed after 2 attempt(s): server error: 502 Bad Gateway",
+		},
+		{
+			name:          "times out each attempt",
+			delay:         time.Second,
+			opts:          DownloadOptions{MaxRetries: 1, Timeout: 50 * time.Millisecond},
+			expectedCalls: 2,
+			expectedErr:   "download failed after 2 attempt(s)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var calls atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				if calls.Add(1) <= tt.failures {
+					rw.WriteHeader(http.StatusBadGateway)
+					return
+				}
+				select {
+				case <-time.After(tt.delay):
+				case <-req.Context().Done():
+					return
+				}
+				//nolint:errcheck // ignore
+				rw.Write([]byte("Hello World\n"))
+			}))
+			t.Cleanup(func() { srv.Close() })
+
+			dst := filepath.Join(t.TempDir(), "README.md")
+			err := DownloadToFileWithOptions(testutil.TestContext(t), fmt.Sprintf("%s/README.md", srv.URL), dst, tt.opts)
+			require.Equal(t, tt.expectedCalls, calls.Load())
+			if tt.expectedErr != "" {
+				require.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			b, err := os.ReadFile(dst)
+			require.NoError(t, err)
+			require.Equal(t, "Hello World\n", string(b))
+		})
+	}
+}
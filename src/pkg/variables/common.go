@@ -6,6 +6,8 @@ package variables
 
 import (
 	"log/slog"
+	"slices"
+	"sync"
 
 	"github.com/zarf-dev/zarf/src/api/v1alpha1"
 )
@@ -14,6 +16,9 @@ import (
 type VariableConfig struct {
 	templatePrefix string
 
+	// mu guards applicationTemplates, setVariableMap and constants so that a variable can be waited on while another
+	// action sets it
+	mu                   sync.RWMutex
 	applicationTemplates map[string]*TextTemplate
 	setVariableMap       SetVariableMap
 	constants            []v1alpha1.Constant
@@ -35,15 +40,21 @@ func New(templatePrefix string, prompt func(variable v1alpha1.InteractiveVariabl
 
 // SetApplicationTemplates sets the application-specific templates for the variable config (i.e. ZARF_REGISTRY for Zarf)
 func (vc *VariableConfig) SetApplicationTemplates(applicationTemplates map[string]*TextTemplate) {
+	vc.mu.Lock()
+	defer vc.mu.Unlock()
 	vc.applicationTemplates = applicationTemplates
 }
 
 // SetConstants sets the constants for a variable config (templated as PREFIX_CONST_NAME)
 func (vc *VariableConfig) SetConstants(constants []v1alpha1.Constant) {
+	vc.mu.Lock()
+	defer vc.mu.Unlock()
 	vc.constants = constants
 }
 
-// GetConstants fetches the package constants.
+// GetConstants fetches a copy of the package constants.
 func (vc *VariableConfig) GetConstants() []v1alpha1.Constant {
-	return vc.constants
+	vc.mu.RLock()
+	defer vc.mu.RUnlock()
+	return slices.Clone(vc.constants)
 }
@@ -0,0 +1,273 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package variables
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Expression is a boolean expression over package variables, such as ENABLE_MONITORING == "true" && !AIRGAP.
+//
+// Operands are variable names, quoted strings and the true and false literals, which are compared with == and !=.
+// A variable that is not compared is true when its value is "true". Comparisons are combined with && and ||,
+// negated with ! and grouped with parentheses. Variables that are not set have an empty value.
+type Expression struct {
+	root      expressionNode
+	variables []string
+}
+
+// ParseExpression parses a boolean expression over package variables.
+func ParseExpression(expr string) (*Expression, error) {
+	tokens, err := tokenizeExpression(expr)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("expression is empty")
+	}
+	p := &expressionParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %s at position %d", p.tokens[p.pos].text, p.tokens[p.pos].pos)
+	}
+	slices.Sort(p.variables)
+	return &Expression{root: root, variables: slices.Compact(p.variables)}, nil
+}
+
+// Variables returns the sorted names of the variables the expression refers to.
+func (e *Expression) Variables() []string {
+	return slices.Clone(e.variables)
+}
+
+// Evaluate evaluates the expression with the given variable values.
+func (e *Expression) Evaluate(values map[string]string) bool {
+	return e.root.evaluate(values)
+}
+
+type expressionTokenKind int
+
+const (
+	tokenName expressionTokenKind = iota
+	tokenString
+	tokenOperator
+)
+
+type expressionToken struct {
+	kind expressionTokenKind
+	text string
+	pos  int
+}
+
+// expressionOperators are ordered so that two character operators are matched before their one character prefix.
+var expressionOperators = []string{"==", "!=", "&&", "||", "!", "(", ")"}
+
+func tokenizeExpression(expr string) ([]expressionToken, error) {
+	tokens := []expressionToken{}
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(expr[i+1:], c)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string at position %d", i)
+			}
+			tokens = append(tokens, expressionToken{kind: tokenString, text: expr[i+1 : i+1+end], pos: i})
+			i += end + 2
+		case isExpressionNameChar(c):
+			start := i
+			for i < len(expr) && isExpressionNameChar(expr[i]) {
+				i++
+			}
+			word := expr[start:i]
+			// true and false are literals so that variables can be compared with them without quotes
+			if word == "true" || word == "false" {
+				tokens = append(tokens, expressionToken{kind: tokenString, text: word, pos: start})
+				continue
+			}
+			tokens = append(tokens, expressionToken{kind: tokenName, text: strings.ToUpper(word), pos: start})
+		default:
+			found := false
+			for _, op := range expressionOperators {
+				if strings.HasPrefix(expr[i:], op) {
+					tokens = append(tokens, expressionToken{kind: tokenOperator, text: op, pos: i})
+					i += len(op)
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("unexpected character %q at position %d", c, i)
+			}
+		}
+	}
+	return tokens, nil
+}
+
+func isExpressionNameChar(c byte) bool {
+	return c == '_' || (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9')
+}
+
+// expressionParser is a recursive descent parser, from the lowest to the highest precedence:
+//
+//	or      = and { "||" and }
+//	and     = unary { "&&" unary }
+//	unary   = "!" unary | primary
+//	primary = "(" or ")" | operand [ ( "==" | "!=" ) operand ]
+//	operand = name | string
+type expressionParser struct {
+	tokens    []expressionToken
+	pos       int
+	variables []string
+}
+
+func (p *expressionParser) peekOperator(op string) bool {
+	return p.pos < len(p.tokens) && p.tokens[p.pos].kind == tokenOperator && p.tokens[p.pos].text == op
+}
+
+func (p *expressionParser) parseOr() (expressionNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekOperator("||") {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *expressionParser) parseAnd() (expressionNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekOperator("&&") {
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *expressionParser) parseUnary() (expressionNode, error) {
+	if p.peekOperator("!") {
+		p.pos++
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *expressionParser) parsePrimary() (expressionNode, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	if p.peekOperator("(") {
+		p.pos++
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.peekOperator(")") {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return node, nil
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	if p.peekOperator("==") || p.peekOperator("!=") {
+		op := p.tokens[p.pos].text
+		p.pos++
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return compareNode{left: left, right: right, equal: op == "=="}, nil
+	}
+	if left.kind == tokenString {
+		return nil, fmt.Errorf("string %q at position %d must be compared with == or !=", left.text, left.pos)
+	}
+	return compareNode{left: left, right: expressionToken{kind: tokenString, text: "true"}, equal: true, fold: true}, nil
+}
+
+func (p *expressionParser) parseOperand() (expressionToken, error) {
+	if p.pos >= len(p.tokens) {
+		return expressionToken{}, fmt.Errorf("unexpected end of expression")
+	}
+	tok := p.tokens[p.pos]
+	if tok.kind == tokenOperator {
+		return expressionToken{}, fmt.Errorf("unexpected %s at position %d", tok.text, tok.pos)
+	}
+	p.pos++
+	if tok.kind == tokenName {
+		p.variables = append(p.variables, tok.text)
+	}
+	return tok, nil
+}
+
+type expressionNode interface {
+	evaluate(values map[string]string) bool
+}
+
+type orNode struct{ left, right expressionNode }
+
+func (n orNode) evaluate(values map[string]string) bool {
+	return n.left.evaluate(values) || n.right.evaluate(values)
+}
+
+type andNode struct{ left, right expressionNode }
+
+func (n andNode) evaluate(values map[string]string) bool {
+	return n.left.evaluate(values) && n.right.evaluate(values)
+}
+
+type notNode struct{ operand expressionNode }
+
+func (n notNode) evaluate(values map[string]string) bool {
+	return !n.operand.evaluate(values)
+}
+
+// compareNode compares two operands, fold compares case-insensitively for variables that are not compared.
+type compareNode struct {
+	left, right expressionToken
+	equal       bool
+	fold        bool
+}
+
+func (n compareNode) evaluate(values map[string]string) bool {
+	left, right := operandValue(n.left, values), operandValue(n.right, values)
+	equal := left == right
+	if n.fold {
+		equal = strings.EqualFold(left, right)
+	}
+	return equal == n.equal
+}
+
+func operandValue(tok expressionToken, values map[string]string) string {
+	if tok.kind == tokenName {
+		return values[tok.text]
+	}
+	return tok.text
+}
@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: Apache-2.0
+// SPDX-FileCopyrightText: 2021-Present The Zarf Authors
+
+package variables
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpression(t *testing.T) {
+	t.Parallel()
+
+	values := map[string]string{
+		"ENABLE_MONITORING": "true",
+		"AIRGAP":            "False",
+		"DISTRO":            "k3s",
+	}
+
+	tests := []struct {
+		name              string
+		expr              string
+		expected          bool
+		expectedVariables []string
+	}{
+		{
+			name:              "variable is true",
+			expr:              "ENABLE_MONITORING",
+			expected:          true,
+			expectedVariables: []string{"ENABLE_MONITORING"},
+		},
+		{
+			name:              "variable is not true",
+			expr:              "AIRGAP",
+			expectedVariables: []string{"AIRGAP"},
+		},
+		{
+			name:              "unset variable",
+			expr:              "UNSET",
+			expectedVariables: []string{"UNSET"},
+		},
+		{
+			name:              "equality with a string",
+			expr:              `DISTRO == "k3s"`,
+			expected:          true,
+			expectedVariables: []string{"DISTRO"},
+		},
+		{
+			name:              "inequality with a single quoted string",
+			expr:              `DISTRO != 'eks'`,
+			expected:          true,
+			expectedVariables: []string{"DISTRO"},
+		},
+		{
+			name:              "equality with a literal",
+			expr:              "ENABLE_MONITORING == true",
+			expected:          true,
+			expectedVariables: []string{"ENABLE_MONITORING"},
+		},
+		{
+			name:              "comparison is case sensitive",
+			expr:              "AIRGAP == false",
+			expectedVariables: []string{"AIRGAP"},
+		},
+		{
+			name:              "variable names are case insensitive",
+			expr:              `distro == "k3s"`,
+			expected:          true,
+			expectedVariables: []string{"DISTRO"},
+		},
+		{
+			name:              "and with not",
+			expr:              "ENABLE_MONITORING && !AIRGAP",
+			expected:          true,
+			expectedVariables: []string{"AIRGAP", "ENABLE_MONITORING"},
+		},
+		{
+			name:              "and binds tighter than or",
+			expr:              `DISTRO == "eks" && AIRGAP || ENABLE_MONITORING`,
+			expected:          true,
+			expectedVariables: []string{"AIRGAP", "DISTRO", "ENABLE_MONITORING"},
+		},
+		{
+			name:              "parentheses",
+			expr:              `DISTRO == "eks" && (AIRGAP || ENABLE_MONITORING)`,
+			expectedVariables: []string{"AIRGAP", "DISTRO", "ENABLE_MONITORING"},
+		},
+		{
+			name:              "double negation",
+			expr:              "!!ENABLE_MONITORING",
+			expected:          true,
+			expectedVariables: []string{"ENABLE_MONITORING"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			expr, err := ParseExpression(tt.expr)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, expr.Evaluate(values))
+			require.Equal(t, tt.expectedVariables, expr.Variables())
+		})
+	}
+}
+
+func TestParseExpressionErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		expr          string
+		expectedError string
+	}{
+		{expr: "", expectedError: "expression is empty"},
+		{expr: "ENABLE_MONITORING ==", expectedError: "unexpected end of expression"},
+		{expr: "ENABLE_MONITORING &&", expectedError: "unexpected end of expression"},
+		{expr: `DISTRO == "k3s`, expectedError: "unterminated string at position 10"},
+		{expr: `"k3s"`, expectedError: `string "k3s" at position 0 must be compared with == or !=`},
+		{expr: "(ENABLE_MONITORING", expectedError: "missing closing parenthesis"},
+		{expr: "ENABLE_MONITORING)", expectedError: "unexpected ) at position 17"},
+		{expr: "ENABLE_MONITORING AIRGAP", expectedError: "unexpected AIRGAP at position 18"},
+		{expr: "DISTRO = k3s", expectedError: `unexpected character '=' at position 7`},
+		{expr: "DISTRO == && AIRGAP", expectedError: "unexpected && at position 10"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			t.Parallel()
+			_, err := ParseExpression(tt.expr)
+			require.EqualError(t, err, tt.expectedError)
+		})
+	}
+}
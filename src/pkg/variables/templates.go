@@ -6,10 +6,12 @@ package variables
 
 import (
 	"bufio"
-	"errors"
+	"bytes"
 	"fmt"
+	"maps"
 	"os"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/defenseunicorns/pkg/helpers/v2"
@@ -26,7 +28,12 @@ type TextTemplate struct {
 
 // GetAllTemplates gets all of the current templates stored in the VariableConfig
 func (vc *VariableConfig) GetAllTemplates() map[string]*TextTemplate {
-	templateMap := vc.applicationTemplates
+	vc.mu.RLock()
+	defer vc.mu.RUnlock()
+	templateMap := maps.Clone(vc.applicationTemplates)
+	if templateMap == nil {
+		templateMap = make(map[string]*TextTemplate)
+	}
 
 	for key, variable := range vc.setVariableMap {
 		// Variable keys are always uppercase in the format ###ZARF_VAR_KEY###
@@ -49,24 +56,39 @@ func (vc *VariableConfig) GetAllTemplates() map[string]*TextTemplate {
 	return templateMap
 }
 
+// FindUnresolvedTemplates returns the template keys (e.g. ###ZARF_VAR_KEY###) in the file at path that have no value.
+func (vc *VariableConfig) FindUnresolvedTemplates(path string) ([]string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	templateRegex := regexp.MustCompile(fmt.Sprintf("###%s_[A-Z0-9_]+###", strings.ToUpper(vc.templatePrefix)))
+	templateMap := vc.GetAllTemplates()
+	unresolved := []string{}
+	for _, key := range templateRegex.FindAllString(string(b), -1) {
+		if _, ok := templateMap[key]; ok || slices.Contains(unresolved, key) {
+			continue
+		}
+		unresolved = append(unresolved, key)
+	}
+	return unresolved, nil
+}
+
 // ReplaceTextTemplate loads a file from a given path, replaces text in it and writes it back in place.
-func (vc *VariableConfig) ReplaceTextTemplate(path string) (err error) {
+// Line endings are kept as they are and files without any replaced text are left untouched.
+func (vc *VariableConfig) ReplaceTextTemplate(path string) error {
 	templateRegex := fmt.Sprintf("###%s_[A-Z0-9_]+###", strings.ToUpper(vc.templatePrefix))
 	templateMap := vc.GetAllTemplates()
 
-	textFile, err := os.Open(path)
+	original, err := os.ReadFile(path)
 	if err != nil {
 		return err
 	}
-	defer func() {
-		err2 := textFile.Close()
-		err = errors.Join(err, err2)
-	}()
 
 	// This regex takes a line and parses the text before and after a discovered template: https://regex101.com/r/ilUxAz/1
 	regexTemplateLine := regexp.MustCompile(fmt.Sprintf("(?P<preTemplate>.*?)(?P<template>%s)(?P<postTemplate>.*)", templateRegex))
 
-	fileScanner := bufio.NewScanner(textFile)
+	fileScanner := bufio.NewScanner(bytes.NewReader(original))
 
 	// Set the buffer to 1 MiB to handle long lines (i.e. base64 text in a secret)
 	// 1 MiB is around the documented maximum size for secrets and configmaps
@@ -74,20 +96,27 @@ func (vc *VariableConfig) ReplaceTextTemplate(path string) (err error) {
 	buf := make([]byte, maxCapacity)
 	fileScanner.Buffer(buf, maxCapacity)
 
-	// Set the scanner to split on new lines
-	fileScanner.Split(bufio.ScanLines)
+	// Set the scanner to split on new lines, keeping the line endings
+	fileScanner.Split(scanLinesWithEndings)
 
 	text := ""
 
 	for fileScanner.Scan() {
 		line := fileScanner.Text()
+		ending := ""
+		if strings.HasSuffix(line, "\n") {
+			line, ending = strings.TrimSuffix(line, "\n"), "\n"
+			if strings.HasSuffix(line, "\r") {
+				line, ending = strings.TrimSuffix(line, "\r"), "\r\n"
+			}
+		}
 
 		for {
 			matches := regexTemplateLine.FindStringSubmatch(line)
 
 			// No template left on this line so move on
 			if len(matches) == 0 {
-				text += fmt.Sprintln(line)
+				text += line + ending
 				break
 			}
 
@@ -134,6 +163,15 @@ func (vc *VariableConfig) ReplaceTextTemplate(path string) (err error) {
 		}
 	}
 
+	if err := fileScanner.Err(); err != nil {
+		return err
+	}
+
+	// Leave the file untouched if nothing was replaced
+	if text == string(original) {
+		return nil
+	}
+
 	// NOTE(mkcp): The extra if err != nil is not necessary, but is here to be explicit
 	err = os.WriteFile(path, []byte(text), helpers.ReadWriteUser)
 	if err != nil {
@@ -141,3 +179,17 @@ func (vc *VariableConfig) ReplaceTextTemplate(path string) (err error) {
 	}
 	return nil
 }
+
+// scanLinesWithEndings is a bufio.SplitFunc like bufio.ScanLines that keeps the line endings in the returned tokens.
+func scanLinesWithEndings(data []byte, atEOF bool) (int, []byte, error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		return i + 1, data[:i+1], nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
@@ -61,7 +61,7 @@ This is a test file for templating.
 
 func TestReplaceTextTemplate(t *testing.T) {
 	type test struct {
-		vc           VariableConfig
+		vc           *VariableConfig
 		path         string
 		wantErr      bool
 		wantContents string
@@ -69,13 +69,13 @@ func TestReplaceTextTemplate(t *testing.T) {
 
 	tests := []test{
 		{
-			vc:           VariableConfig{setVariableMap: SetVariableMap{}, applicationTemplates: map[string]*TextTemplate{}},
+			vc:           &VariableConfig{setVariableMap: SetVariableMap{}, applicationTemplates: map[string]*TextTemplate{}},
 			path:         "non-existent.test",
 			wantErr:      true,
 			wantContents: start,
 		},
 		{
-			vc: VariableConfig{
+			vc: &VariableConfig{
 				templatePrefix: "PREFIX",
 				setVariableMap: SetVariableMap{
 					"REPLACE_ME": {Value: "VAR_REPLACED"},
@@ -88,7 +88,7 @@ func TestReplaceTextTemplate(t *testing.T) {
 			wantContents: simple,
 		},
 		{
-			vc: VariableConfig{
+			vc: &VariableConfig{
 				templatePrefix: "PREFIX",
 				setVariableMap: SetVariableMap{
 					"REPLACE_ME": {Value: "VAR_REPLACED\nVAR_SECOND"},
@@ -101,7 +101,7 @@ func TestReplaceTextTemplate(t *testing.T) {
 			wantContents: multiline,
 		},
 		{
-			vc: VariableConfig{
+			vc: &VariableConfig{
 				templatePrefix: "PREFIX",
 				setVariableMap: SetVariableMap{
 					"REPLACE_ME": {Value: "VAR_REPLACED\nVAR_SECOND", Variable: v1alpha1.Variable{AutoIndent: true}},
@@ -114,7 +114,7 @@ func TestReplaceTextTemplate(t *testing.T) {
 			wantContents: autoIndent,
 		},
 		{
-			vc: VariableConfig{
+			vc: &VariableConfig{
 				templatePrefix: "PREFIX",
 				setVariableMap: SetVariableMap{
 					"REPLACE_ME": {Value: "testdata/file.txt", Variable: v1alpha1.Variable{Type: v1alpha1.FileVariableType}},
@@ -157,3 +157,86 @@ func TestReplaceTextTemplate(t *testing.T) {
 		}
 	}
 }
+
+func TestReplaceTextTemplateRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		contents     string
+		wantContents string
+	}{
+		{
+			name:         "no templates",
+			contents:     "domain: example.com\n---\nreplicas: 2",
+			wantContents: "domain: example.com\n---\nreplicas: 2",
+		},
+		{
+			name:         "unknown templates",
+			contents:     "domain: ###PREFIX_VAR_MISSING###\n",
+			wantContents: "domain: ###PREFIX_VAR_MISSING###\n",
+		},
+		{
+			name:         "multiple documents",
+			contents:     "---\ningress:\n  host: ###PREFIX_VAR_DOMAIN###\n---\nreplicas: 2\n",
+			wantContents: "---\ningress:\n  host: example.com\n---\nreplicas: 2\n",
+		},
+		{
+			name:         "no trailing newline",
+			contents:     "host: ###PREFIX_VAR_DOMAIN###",
+			wantContents: "host: example.com",
+		},
+		{
+			name:         "windows line endings",
+			contents:     "host: ###PREFIX_VAR_DOMAIN###\r\nreplicas: 2\r\n",
+			wantContents: "host: example.com\r\nreplicas: 2\r\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			vc := &VariableConfig{
+				templatePrefix: "PREFIX",
+				setVariableMap: SetVariableMap{
+					"DOMAIN": {Value: "example.com"},
+				},
+				applicationTemplates: map[string]*TextTemplate{},
+			}
+			path := filepath.Join(t.TempDir(), "values.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.contents), 0o644))
+			// Files that are rewritten are only readable by the user, so the mode shows whether the file was touched
+			require.NoError(t, os.Chmod(path, 0o644))
+
+			require.NoError(t, vc.ReplaceTextTemplate(path))
+			gotContents, err := os.ReadFile(path)
+			require.NoError(t, err)
+			require.Equal(t, tt.wantContents, string(gotContents))
+			if tt.contents == tt.wantContents {
+				fi, err := os.Stat(path)
+				require.NoError(t, err)
+				require.Equal(t, os.FileMode(0o644), fi.Mode().Perm())
+			}
+		})
+	}
+}
+
+func TestFindUnresolvedTemplates(t *testing.T) {
+	vc := VariableConfig{
+		templatePrefix: "PREFIX",
+		setVariableMap: SetVariableMap{
+			"REPLACE_ME": {Value: "VAR_REPLACED"},
+		},
+		applicationTemplates: map[string]*TextTemplate{},
+	}
+
+	path := filepath.Join(t.TempDir(), "manifest.yaml")
+	content := "###PREFIX_VAR_REPLACE_ME###\n###PREFIX_VAR_MISSING### ###PREFIX_CONST_MISSING###\n###PREFIX_VAR_MISSING###\n###OTHER_VAR_MISSING###\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	unresolved, err := vc.FindUnresolvedTemplates(path)
+	require.NoError(t, err)
+	require.Equal(t, []string{"###PREFIX_VAR_MISSING###", "###PREFIX_CONST_MISSING###"}, unresolved)
+
+	_, err = vc.FindUnresolvedTemplates(filepath.Join(t.TempDir(), "non-existent.yaml"))
+	require.Error(t, err)
+}
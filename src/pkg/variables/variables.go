@@ -15,15 +15,28 @@ import (
 // SetVariableMap represents a map of variable names to their set values
 type SetVariableMap map[string]*v1alpha1.SetVariable
 
-// GetSetVariable gets a variable set within a VariableConfig by its name
+// GetSetVariable gets a copy of a variable set within a VariableConfig by its name
 func (vc *VariableConfig) GetSetVariable(name string) (*v1alpha1.SetVariable, bool) {
+	vc.mu.RLock()
+	defer vc.mu.RUnlock()
 	variable, ok := vc.setVariableMap[strings.ToUpper(name)]
-	return variable, ok
+	if !ok {
+		return nil, false
+	}
+	v := *variable
+	return &v, true
 }
 
-// GetSetVariableMap retrieves all SetVariables
+// GetSetVariableMap retrieves a copy of all SetVariables
 func (vc *VariableConfig) GetSetVariableMap() SetVariableMap {
-	return vc.setVariableMap
+	vc.mu.RLock()
+	defer vc.mu.RUnlock()
+	setVariableMap := make(SetVariableMap, len(vc.setVariableMap))
+	for name, variable := range vc.setVariableMap {
+		v := *variable
+		setVariableMap[name] = &v
+	}
+	return setVariableMap
 }
 
 // PopulateVariables handles setting the active variables within a VariableConfig's SetVariableMap
@@ -34,13 +47,11 @@ func (vc *VariableConfig) PopulateVariables(variables []v1alpha1.InteractiveVari
 
 	for _, variable := range variables {
 		variable.Name = strings.ToUpper(variable.Name)
-		_, present := vc.setVariableMap[variable.Name]
+		setVariable, present := vc.GetSetVariable(variable.Name)
 
 		// Variable is present, no need to continue checking
 		if present {
-			vc.setVariableMap[variable.Name].Sensitive = variable.Sensitive
-			vc.setVariableMap[variable.Name].AutoIndent = variable.AutoIndent
-			vc.setVariableMap[variable.Name].Type = variable.Type
+			vc.SetVariable(variable.Name, setVariable.Value, variable.Sensitive, variable.AutoIndent, variable.Type)
 			if err := vc.CheckVariablePattern(variable.Name, variable.Pattern); err != nil {
 				return err
 			}
@@ -73,6 +84,8 @@ func (vc *VariableConfig) PopulateVariables(variables []v1alpha1.InteractiveVari
 // SetVariable sets a variable in a VariableConfig's SetVariableMap
 func (vc *VariableConfig) SetVariable(name, value string, sensitive bool, autoIndent bool, varType v1alpha1.VariableType) {
 	name = strings.ToUpper(name)
+	vc.mu.Lock()
+	defer vc.mu.Unlock()
 	vc.setVariableMap[name] = &v1alpha1.SetVariable{
 		Variable: v1alpha1.Variable{
 			Name:       name,
@@ -86,7 +99,7 @@ func (vc *VariableConfig) SetVariable(name, value string, sensitive bool, autoIn
 
 // CheckVariablePattern checks to see if a current variable is set to a value that matches its pattern
 func (vc *VariableConfig) CheckVariablePattern(name, pattern string) error {
-	if variable, ok := vc.setVariableMap[name]; ok {
+	if variable, ok := vc.GetSetVariable(name); ok {
 		r, err := regexp.Compile(pattern)
 		if err != nil {
 			return err
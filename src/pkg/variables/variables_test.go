@@ -4,6 +4,8 @@
 package variables
 
 import (
+	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -13,7 +15,7 @@ import (
 
 func TestPopulateVariables(t *testing.T) {
 	type test struct {
-		vc       VariableConfig
+		vc       *VariableConfig
 		vars     []v1alpha1.InteractiveVariable
 		presets  map[string]string
 		wantErr  bool
@@ -24,13 +26,13 @@ func TestPopulateVariables(t *testing.T) {
 
 	tests := []test{
 		{
-			vc:       VariableConfig{setVariableMap: SetVariableMap{}},
+			vc:       &VariableConfig{setVariableMap: SetVariableMap{}},
 			vars:     []v1alpha1.InteractiveVariable{{Variable: v1alpha1.Variable{Name: "NAME"}}},
 			presets:  map[string]string{},
 			wantVars: SetVariableMap{"NAME": {Variable: v1alpha1.Variable{Name: "NAME"}}},
 		},
 		{
-			vc: VariableConfig{setVariableMap: SetVariableMap{}},
+			vc: &VariableConfig{setVariableMap: SetVariableMap{}},
 			vars: []v1alpha1.InteractiveVariable{
 				{Variable: v1alpha1.Variable{Name: "NAME"}, Default: "Default"},
 			},
@@ -40,7 +42,7 @@ func TestPopulateVariables(t *testing.T) {
 			},
 		},
 		{
-			vc: VariableConfig{setVariableMap: SetVariableMap{}},
+			vc: &VariableConfig{setVariableMap: SetVariableMap{}},
 			vars: []v1alpha1.InteractiveVariable{
 				{Variable: v1alpha1.Variable{Name: "NAME"}, Default: "Default"},
 			},
@@ -50,7 +52,7 @@ func TestPopulateVariables(t *testing.T) {
 			},
 		},
 		{
-			vc: VariableConfig{setVariableMap: SetVariableMap{}},
+			vc: &VariableConfig{setVariableMap: SetVariableMap{}},
 			vars: []v1alpha1.InteractiveVariable{
 				{Variable: v1alpha1.Variable{Name: "NAME", Sensitive: true, AutoIndent: true, Type: v1alpha1.FileVariableType}},
 			},
@@ -60,7 +62,7 @@ func TestPopulateVariables(t *testing.T) {
 			},
 		},
 		{
-			vc: VariableConfig{setVariableMap: SetVariableMap{}},
+			vc: &VariableConfig{setVariableMap: SetVariableMap{}},
 			vars: []v1alpha1.InteractiveVariable{
 				{Variable: v1alpha1.Variable{Name: "NAME", Sensitive: true, AutoIndent: true, Type: v1alpha1.FileVariableType}},
 			},
@@ -70,7 +72,7 @@ func TestPopulateVariables(t *testing.T) {
 			},
 		},
 		{
-			vc: VariableConfig{setVariableMap: SetVariableMap{}, prompt: prompt},
+			vc: &VariableConfig{setVariableMap: SetVariableMap{}, prompt: prompt},
 			vars: []v1alpha1.InteractiveVariable{
 				{Variable: v1alpha1.Variable{Name: "NAME"}, Prompt: true},
 			},
@@ -80,7 +82,7 @@ func TestPopulateVariables(t *testing.T) {
 			},
 		},
 		{
-			vc: VariableConfig{setVariableMap: SetVariableMap{}, prompt: prompt},
+			vc: &VariableConfig{setVariableMap: SetVariableMap{}, prompt: prompt},
 			vars: []v1alpha1.InteractiveVariable{
 				{Variable: v1alpha1.Variable{Name: "NAME"}, Default: "Default", Prompt: true},
 			},
@@ -90,7 +92,7 @@ func TestPopulateVariables(t *testing.T) {
 			},
 		},
 		{
-			vc: VariableConfig{setVariableMap: SetVariableMap{}, prompt: prompt},
+			vc: &VariableConfig{setVariableMap: SetVariableMap{}, prompt: prompt},
 			vars: []v1alpha1.InteractiveVariable{
 				{Variable: v1alpha1.Variable{Name: "NAME"}, Prompt: true},
 			},
@@ -100,7 +102,7 @@ func TestPopulateVariables(t *testing.T) {
 			},
 		},
 		{
-			vc: VariableConfig{setVariableMap: SetVariableMap{}, prompt: prompt},
+			vc: &VariableConfig{setVariableMap: SetVariableMap{}, prompt: prompt},
 			vars: []v1alpha1.InteractiveVariable{
 				{Variable: v1alpha1.Variable{Name: "lowercase-prompt"}, Prompt: true},
 			},
@@ -132,7 +134,7 @@ func TestPopulateVariables(t *testing.T) {
 
 func TestCheckVariablePattern(t *testing.T) {
 	type test struct {
-		vc         VariableConfig
+		vc         *VariableConfig
 		name       string
 		pattern    string
 		wantErrMsg string
@@ -140,22 +142,22 @@ func TestCheckVariablePattern(t *testing.T) {
 
 	tests := []test{
 		{
-			vc: VariableConfig{setVariableMap: SetVariableMap{}}, name: "NAME", pattern: "n[a-z]me",
+			vc: &VariableConfig{setVariableMap: SetVariableMap{}}, name: "NAME", pattern: "n[a-z]me",
 			wantErrMsg: "variable \"NAME\" was not found in the current variable map",
 		},
 		{
-			vc: VariableConfig{
+			vc: &VariableConfig{
 				setVariableMap: SetVariableMap{"NAME": &v1alpha1.SetVariable{Value: "name"}},
 			}, name: "NAME", pattern: "n[^a]me",
 			wantErrMsg: "provided value for variable \"NAME\" does not match pattern \"n[^a]me\"",
 		},
 		{
-			vc: VariableConfig{
+			vc: &VariableConfig{
 				setVariableMap: SetVariableMap{"NAME": &v1alpha1.SetVariable{Value: "name"}},
 			}, name: "NAME", pattern: "n[a-z]me", wantErrMsg: "",
 		},
 		{
-			vc: VariableConfig{
+			vc: &VariableConfig{
 				setVariableMap: SetVariableMap{"NAME": &v1alpha1.SetVariable{Value: "name"}},
 			}, name: "NAME", pattern: "n[a-z-bad-pattern", wantErrMsg: "error parsing regexp: missing closing ]: `[a-z-bad-pattern`",
 		},
@@ -170,3 +172,35 @@ func TestCheckVariablePattern(t *testing.T) {
 		}
 	}
 }
+
+func TestVariableConfigConcurrentAccess(t *testing.T) {
+	t.Parallel()
+
+	vc := New("zarf", nil, nil)
+	vc.SetApplicationTemplates(map[string]*TextTemplate{"###ZARF_REGISTRY###": {Value: "127.0.0.1:31999"}})
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Go(func() {
+			vc.SetVariable(fmt.Sprintf("VAR_%d", i), "value", false, false, v1alpha1.RawVariableType)
+		})
+		wg.Go(func() {
+			vc.GetAllTemplates()
+			vc.GetSetVariableMap()
+			vc.GetConstants()
+		})
+	}
+	wg.Wait()
+
+	require.Len(t, vc.GetSetVariableMap(), 10)
+	templates := vc.GetAllTemplates()
+	require.Len(t, templates, 11)
+	// The variables are not added to the application templates
+	require.Len(t, vc.applicationTemplates, 1)
+
+	// Changing a returned variable does not change the config
+	variables := vc.GetSetVariableMap()
+	variables["VAR_0"].Value = "changed"
+	variable, ok := vc.GetSetVariable("VAR_0")
+	require.True(t, ok)
+	require.Equal(t, "value", variable.Value)
+}
@@ -6,6 +6,7 @@ package wait
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"io"
@@ -18,8 +19,10 @@ import (
 	"github.com/zarf-dev/zarf/src/internal/healthchecks"
 	"github.com/zarf-dev/zarf/src/pkg/cluster"
 	"github.com/zarf-dev/zarf/src/pkg/logger"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
@@ -29,16 +32,31 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/jsonpath"
 	cmdwait "k8s.io/kubectl/pkg/cmd/wait"
+	"k8s.io/kubectl/pkg/polymorphichelpers"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/cli-utils/pkg/object"
 )
 
+// ResourceOptions are the options of waiting for resources that are only used when waiting with ForResourceWithOptions.
+type ResourceOptions struct {
+	// MinReady is the number of resources matching a label selector that must meet a jsonpath condition. Every
+	// matching resource must meet the condition when it is 0.
+	MinReady int
+}
+
 // ForResource waits for a Kubernetes resource to meet the specified condition.
 // It uses the same logic as `kubectl wait`, with retry logic for resources that don't exist yet.
 // If identifier is empty, it will wait for any resource of the given kind to exist.
 // This function retries on cluster connection errors, allowing it to wait for a cluster to become available.
 func ForResource(ctx context.Context, kind, identifier, condition, namespace string, timeout time.Duration) error {
+	return ForResourceWithOptions(ctx, kind, identifier, condition, namespace, timeout, ResourceOptions{})
+}
+
+// ForResourceWithOptions waits for a Kubernetes resource to meet the specified condition like ForResource. A jsonpath
+// condition on a label selector must hold for every matching resource, or for opts.MinReady of them when it is set.
+func ForResourceWithOptions(ctx context.Context, kind, identifier, condition, namespace string, timeout time.Duration, opts ResourceOptions) error {
 	l := logger.From(ctx)
 	if kind == "" {
 		return errors.New("kind is required")
@@ -82,7 +100,7 @@ func ForResource(ctx context.Context, kind, identifier, condition, namespace str
 		condition = "exists"
 	}
 
-	return waitFor(ctx, restConfig, clientCfg, kind, namespace, identifier, condition, deadline)
+	return waitFor(ctx, restConfig, clientCfg, kind, namespace, identifier, condition, deadline, opts)
 }
 
 // ForResourceDefaultReady waits for any resource
@@ -105,10 +123,10 @@ func ForResourceDefaultReady(ctx context.Context, kind, identifier, condition, n
 		return err
 	}
 
-	return waitFor(ctx, restConfig, clientCfg, kind, namespace, identifier, condition, deadline)
+	return waitFor(ctx, restConfig, clientCfg, kind, namespace, identifier, condition, deadline, ResourceOptions{})
 }
 
-func waitFor(ctx context.Context, restConfig *rest.Config, clientCfg clientcmd.ClientConfig, kind string, namespace string, identifier string, condition string, deadline time.Time) error {
+func waitFor(ctx context.Context, restConfig *rest.Config, clientCfg clientcmd.ClientConfig, kind string, namespace string, identifier string, condition string, deadline time.Time, opts ResourceOptions) error {
 	l := logger.From(ctx)
 	waitInterval := time.Second
 	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
@@ -125,6 +143,11 @@ func waitFor(ctx context.Context, restConfig *rest.Config, clientCfg clientcmd.C
 		}
 		restMapper := restmapper.NewShortcutExpander(restmapper.NewDiscoveryRESTMapper(groupResources), discoveryClient, nil)
 		mapping, err = resolveResourceKind(restMapper, kind)
+		if err != nil && isDeletedCondition(condition) {
+			// No resources can exist for a kind the cluster does not have, such as once the CRD is removed
+			l.Debug("resource kind not found, nothing to wait for", "kind", kind, "error", err)
+			return true, nil
+		}
 		if err != nil {
 			l.Debug("failed to resolve resource kind, retrying", "kind", kind, "error", err)
 			return false, nil
@@ -134,6 +157,10 @@ func waitFor(ctx context.Context, restConfig *rest.Config, clientCfg clientcmd.C
 	if err != nil {
 		return fmt.Errorf("timed out waiting to resolve resource kind %q: %w", kind, err)
 	}
+	if mapping == nil {
+		l.Info("resource kind does not exist in the cluster", "kind", kind, "condition", condition)
+		return nil
+	}
 
 	dynamicClient, err := dynamic.NewForConfig(restConfig)
 	if err != nil {
@@ -148,6 +175,10 @@ func waitFor(ctx context.Context, restConfig *rest.Config, clientCfg clientcmd.C
 		namespace = ns
 	}
 
+	if isDeletedCondition(condition) {
+		return waitForDeleted(ctx, dynamicClient, mapping, identifier, namespace, deadline)
+	}
+
 	if identifier == "" {
 		return waitForSingleResourceMatchingCriteria(ctx, dynamicClient, mapping.Resource, namespace, deadline)
 	}
@@ -156,6 +187,14 @@ func waitFor(ctx context.Context, restConfig *rest.Config, clientCfg clientcmd.C
 		return waitForReconciled(ctx, restConfig, dynamicClient, mapping, identifier, namespace, deadline)
 	}
 
+	if isRolloutCondition(condition) {
+		return waitForRollout(ctx, dynamicClient, mapping, identifier, namespace, deadline)
+	}
+
+	if isJSONPathWaitType(condition) && strings.ContainsRune(identifier, '=') {
+		return waitForSelectorJSONPath(ctx, dynamicClient, mapping, condition, identifier, namespace, opts.MinReady, deadline)
+	}
+
 	return waitForResourceCondition(ctx, dynamicClient, condition, mapping.GroupVersionKind.GroupKind().String(), identifier, namespace, deadline)
 }
 
@@ -308,6 +347,148 @@ func listResourcesWithSelector(ctx context.Context, dynamicClient dynamic.Interf
 	return objs, nil
 }
 
+// RolloutCondition is a special condition that waits for a Deployment, DaemonSet or StatefulSet rollout to complete,
+// the same as `kubectl rollout status`.
+const RolloutCondition = "rolledOut"
+
+func isRolloutCondition(condition string) bool {
+	return strings.EqualFold(condition, RolloutCondition)
+}
+
+// waitForRollout waits until every matching resource has all of its desired pods updated and ready.
+func waitForRollout(ctx context.Context, dynamicClient dynamic.Interface, mapping *meta.RESTMapping, identifier, namespace string, deadline time.Time) error {
+	l := logger.From(ctx)
+	groupKind := mapping.GroupVersionKind.GroupKind()
+	viewer, err := polymorphichelpers.StatusViewerFor(groupKind)
+	if err != nil {
+		return fmt.Errorf("the %s condition is not supported for %s: %w", RolloutCondition, groupKind, err)
+	}
+
+	var resourceClient dynamic.ResourceInterface
+	resourceClient = dynamicClient.Resource(mapping.Resource)
+	if namespace != "" {
+		resourceClient = dynamicClient.Resource(mapping.Resource).Namespace(namespace)
+	}
+
+	l.Info("waiting for rollout to complete", "kind", groupKind.String(), "identifier", identifier, "namespace", namespace)
+	status := "resource not found"
+	waitInterval := time.Second
+	err = wait.PollUntilContextTimeout(ctx, waitInterval, time.Until(deadline), true, func(ctx context.Context) (bool, error) {
+		var objs []unstructured.Unstructured
+		if strings.ContainsRune(identifier, '=') {
+			list, err := resourceClient.List(ctx, metav1.ListOptions{LabelSelector: identifier})
+			if err != nil {
+				return true, fmt.Errorf("failed to list resources: %w", err)
+			}
+			objs = list.Items
+		} else {
+			obj, err := resourceClient.Get(ctx, identifier, metav1.GetOptions{})
+			if kerrors.IsNotFound(err) {
+				l.Debug("resource not found, retrying", "kind", groupKind.String(), "name", identifier)
+				return false, nil
+			}
+			if err != nil {
+				return true, fmt.Errorf("failed to get resource: %w", err)
+			}
+			objs = []unstructured.Unstructured{*obj}
+		}
+		if len(objs) == 0 {
+			return false, nil
+		}
+		for _, obj := range objs {
+			msg, done, err := viewer.Status(&obj, 0)
+			if err != nil {
+				return true, fmt.Errorf("unable to get the rollout status of %s/%s: %w", groupKind, obj.GetName(), err)
+			}
+			if !done {
+				status = strings.TrimSpace(msg)
+				l.Debug("rollout in progress", "status", status)
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("timed out waiting for %s/%s to be rolled out: %s", groupKind, identifier, status)
+		}
+		return err
+	}
+	l.Info("rollout complete", "kind", groupKind.String(), "identifier", identifier, "namespace", namespace)
+	return nil
+}
+
+// DeletedCondition is a special condition that waits until the resource no longer exists, the inverse of exist. It
+// waits for every resource matching a selector, or every resource of the kind without a name, to be deleted.
+const DeletedCondition = "deleted"
+
+func isDeletedCondition(condition string) bool {
+	return strings.EqualFold(condition, DeletedCondition) || strings.EqualFold(condition, "delete")
+}
+
+// waitForDeleted waits until no resource matches the identifier, including resources that are still being finalized.
+// The error on timeout lists the resources that are still present.
+func waitForDeleted(ctx context.Context, dynamicClient dynamic.Interface, mapping *meta.RESTMapping, identifier, namespace string, deadline time.Time) error {
+	l := logger.From(ctx)
+	groupKind := mapping.GroupVersionKind.GroupKind().String()
+	var resourceClient dynamic.ResourceInterface
+	resourceClient = dynamicClient.Resource(mapping.Resource)
+	if namespace != "" {
+		resourceClient = dynamicClient.Resource(mapping.Resource).Namespace(namespace)
+	}
+
+	description := fmt.Sprintf("%s/%s", groupKind, identifier)
+	switch {
+	case identifier == "":
+		description = fmt.Sprintf("every %s", groupKind)
+	case strings.ContainsRune(identifier, '='):
+		description = fmt.Sprintf("%s matching %s", groupKind, identifier)
+	}
+
+	l.Info("waiting for resources to be deleted", "kind", groupKind, "identifier", identifier, "namespace", namespace)
+	var remaining []string
+	waitInterval := time.Second
+	err := wait.PollUntilContextTimeout(ctx, waitInterval, time.Until(deadline), true, func(ctx context.Context) (bool, error) {
+		var objs []unstructured.Unstructured
+		if identifier == "" || strings.ContainsRune(identifier, '=') {
+			list, err := resourceClient.List(ctx, metav1.ListOptions{LabelSelector: identifier})
+			if err != nil {
+				return true, fmt.Errorf("failed to list resources: %w", err)
+			}
+			objs = list.Items
+		} else {
+			obj, err := resourceClient.Get(ctx, identifier, metav1.GetOptions{})
+			if kerrors.IsNotFound(err) {
+				return true, nil
+			}
+			if err != nil {
+				return true, fmt.Errorf("failed to get resource: %w", err)
+			}
+			objs = []unstructured.Unstructured{*obj}
+		}
+		remaining = make([]string, 0, len(objs))
+		for _, obj := range objs {
+			name := obj.GetName()
+			if obj.GetNamespace() != "" {
+				name = fmt.Sprintf("%s/%s", obj.GetNamespace(), name)
+			}
+			remaining = append(remaining, name)
+		}
+		if len(remaining) > 0 {
+			l.Debug("waiting for resources to be deleted", "kind", groupKind, "remaining", remaining)
+		}
+		return len(remaining) == 0, nil
+	})
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("timed out waiting for %s to be deleted, still present: %s", description, strings.Join(remaining, ", "))
+		}
+		return err
+	}
+	l.Info("resources are deleted", "kind", groupKind, "identifier", identifier, "namespace", namespace)
+	return nil
+}
+
 func isJSONPathWaitType(condition string) bool {
 	return len(condition) != 0 && condition[0] == '{' && strings.Contains(condition, "=") && strings.Contains(condition, "}")
 }
@@ -322,6 +503,85 @@ func isExistsCondition(condition string) bool {
 	return false
 }
 
+// waitForSelectorJSONPath waits until the jsonpath condition holds for every resource matching the label selector, or
+// for minReady of them when it is set.
+func waitForSelectorJSONPath(ctx context.Context, dynamicClient dynamic.Interface, mapping *meta.RESTMapping, condition, selector, namespace string, minReady int, deadline time.Time) error {
+	l := logger.From(ctx)
+	groupKind := mapping.GroupVersionKind.GroupKind().String()
+	path, value, ok := strings.Cut(condition, "}=")
+	if !ok {
+		return fmt.Errorf("jsonpath condition %s must be in the form {.path}=value", condition)
+	}
+	jp := jsonpath.New("condition").AllowMissingKeys(true)
+	if err := jp.Parse(path + "}"); err != nil {
+		return fmt.Errorf("invalid jsonpath condition %s: %w", condition, err)
+	}
+
+	var resourceClient dynamic.ResourceInterface
+	resourceClient = dynamicClient.Resource(mapping.Resource)
+	if namespace != "" {
+		resourceClient = dynamicClient.Resource(mapping.Resource).Namespace(namespace)
+	}
+
+	l.Info("waiting for resources matching selector", "kind", groupKind, "selector", selector, "condition", condition, "namespace", namespace, "minReady", minReady)
+	status := "no resources found"
+	waitInterval := time.Second
+	err := wait.PollUntilContextTimeout(ctx, waitInterval, time.Until(deadline), true, func(ctx context.Context) (bool, error) {
+		list, err := resourceClient.List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return true, fmt.Errorf("failed to list resources: %w", err)
+		}
+		if len(list.Items) == 0 {
+			return false, nil
+		}
+		ready := 0
+		for _, item := range list.Items {
+			met, err := jsonPathConditionMet(jp, item.Object, value)
+			if err != nil {
+				return true, fmt.Errorf("unable to evaluate %s on %s/%s: %w", condition, groupKind, item.GetName(), err)
+			}
+			if met {
+				ready++
+			}
+		}
+		required := len(list.Items)
+		if minReady > 0 {
+			required = minReady
+		}
+		status = fmt.Sprintf("%d of %d resources meet the condition, %d required", ready, len(list.Items), required)
+		l.Debug("waiting for resources matching selector", "status", status)
+		return ready >= required, nil
+	})
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("timed out waiting for %s matching %s to be %s: %s", groupKind, selector, condition, status)
+		}
+		return err
+	}
+	l.Info("wait-for condition met", "kind", groupKind, "selector", selector, "condition", condition, "namespace", namespace, "status", status)
+	return nil
+}
+
+// jsonPathConditionMet returns whether the jsonpath of the object is a single value equal to value.
+func jsonPathConditionMet(jp *jsonpath.JSONPath, obj map[string]any, value string) (bool, error) {
+	results, err := jp.FindResults(obj)
+	if err != nil {
+		return false, err
+	}
+	if len(results) != 1 || len(results[0]) == 0 {
+		return false, nil
+	}
+	if len(results[0]) > 1 {
+		return false, errors.New("the jsonpath matches more than one value")
+	}
+	switch v := results[0][0].Interface().(type) {
+	case map[string]any, []any:
+		return false, errors.New("the jsonpath does not match a single value")
+	default:
+		return fmt.Sprint(v) == value, nil
+	}
+}
+
 func waitForResourceCondition(ctx context.Context, dynamicClient dynamic.Interface, condition, groupKind, identifier, namespace string, deadline time.Time) error {
 	l := logger.From(ctx)
 	var args []string
@@ -334,7 +594,7 @@ func waitForResourceCondition(ctx context.Context, dynamicClient dynamic.Interfa
 	}
 
 	forCondition := "create" // default: wait for existence
-	if condition != "" && condition != "delete" && !isExistsCondition(condition) {
+	if condition != "" && !isExistsCondition(condition) {
 		if isJSONPathWaitType(condition) {
 			forCondition = fmt.Sprintf("jsonpath=%s", condition)
 		} else {
@@ -342,10 +602,6 @@ func waitForResourceCondition(ctx context.Context, dynamicClient dynamic.Interfa
 		}
 	}
 
-	if condition == "delete" {
-		forCondition = "delete"
-	}
-
 	l.Info("waiting for resource", "kind", groupKind, "identifier", identifier, "condition", forCondition, "namespace", namespace)
 
 	configFlags := genericclioptions.NewConfigFlags(true)
@@ -393,24 +649,51 @@ func waitForResourceCondition(ctx context.Context, dynamicClient dynamic.Interfa
 
 // ForNetwork waits for a network endpoint to respond.
 func ForNetwork(ctx context.Context, protocol, address, condition string, timeout time.Duration) error {
+	return ForNetworkWithOptions(ctx, protocol, address, condition, timeout, NetworkOptions{})
+}
+
+// NetworkOptions are optional parameters for the requests sent to http and https endpoints.
+type NetworkOptions struct {
+	// Method is the HTTP method of the request, defaults to GET.
+	Method string
+	// Headers are added to the request.
+	Headers map[string]string
+	// InsecureSkipVerify skips verifying the TLS certificate of https endpoints.
+	InsecureSkipVerify bool
+}
+
+// ForNetworkWithOptions waits for a network endpoint to respond like ForNetwork, sending the requests to http and
+// https endpoints with the given options.
+func ForNetworkWithOptions(ctx context.Context, protocol, address, condition string, timeout time.Duration, opts NetworkOptions) error {
 	waitInterval := time.Second
-	return forNetwork(ctx, protocol, address, condition, timeout, waitInterval)
+	return forNetwork(ctx, protocol, address, condition, timeout, waitInterval, opts)
 }
 
-func forNetwork(ctx context.Context, protocol string, address string, condition string, timeout time.Duration, waitInterval time.Duration) error {
+// ProbeNetwork checks once whether a network endpoint responds, using the same protocol and condition as ForNetwork.
+func ProbeNetwork(ctx context.Context, protocol, address, condition string, timeout time.Duration) error {
+	httpClient := &http.Client{
+		Timeout: timeout,
+	}
+	return probeNetwork(ctx, httpClient, protocol, address, condition, NetworkOptions{})
+}
+
+// errInvalidNetworkCondition is returned for conditions that can never be met, so that waits fail immediately.
+var errInvalidNetworkCondition = errors.New("invalid network condition")
+
+func forNetwork(ctx context.Context, protocol string, address string, condition string, timeout time.Duration, waitInterval time.Duration, opts NetworkOptions) error {
 	l := logger.From(ctx)
 	expired := time.After(timeout)
 
-	condition = strings.ToLower(condition)
-	if condition == "" {
-		condition = "success"
-	}
-
 	// Create an HTTP client with a per-request timeout that is slightly shorter than our wait-interval to prevent
 	// hanging on slow or unresponsive servers.
 	httpClient := &http.Client{
 		Timeout: waitInterval - (time.Millisecond * 5),
 	}
+	if opts.InsecureSkipVerify {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true} //nolint:gosec // only skipped when explicitly requested
+		httpClient.Transport = transport
+	}
 
 	delay := 100 * time.Millisecond
 
@@ -425,70 +708,13 @@ func forNetwork(ctx context.Context, protocol string, address string, condition
 		case <-ctx.Done():
 			return errors.New("received interrupt")
 		default:
-			switch protocol {
-			case "http", "https":
-				// Handle HTTP and HTTPS endpoints.
-				url := fmt.Sprintf("%s://%s", protocol, address)
-
-				// Default to checking for a 2xx response.
-				if condition == "success" {
-					// Try to get the URL and check the status code.
-					resp, err := httpClient.Get(url)
-					if err != nil {
-						l.Debug(err.Error())
-						continue
-					}
-					err = resp.Body.Close()
-					if err != nil {
-						l.Debug(err.Error())
-					}
-
-					// If the status code is not in the 2xx range, try again.
-					if resp.StatusCode < 200 || resp.StatusCode > 299 {
-						l.Debug("did not receive 2xx status code", "responseCode", resp.StatusCode)
-						continue
-					}
-
-					// Success, break out of the switch statement.
-					break
-				}
-
-				// Convert the condition to an int and check if it's a valid HTTP status code.
-				code, err := strconv.Atoi(condition)
-				if err != nil {
-					return fmt.Errorf("http status code %s is not an integer: %w", condition, err)
-				}
-				if http.StatusText(code) == "" {
-					return errors.New("http status code is unknown")
-				}
-
-				// Try to get the URL and check the status code.
-				resp, err := httpClient.Get(url)
-				if err != nil {
-					l.Debug(err.Error())
-					continue
-				}
-				err = resp.Body.Close()
-				if err != nil {
-					l.Debug(err.Error())
-				}
-
-				if resp.StatusCode != code {
-					l.Debug("did not receive expected status code", "expected", code, "actual", resp.StatusCode)
-					continue
-				}
-			default:
-				// Fallback to any generic protocol using net.Dial
-				conn, err := net.Dial(protocol, address)
-				if err != nil {
-					l.Debug(err.Error())
-					continue
-				}
-				err = conn.Close()
-				if err != nil {
-					l.Debug(err.Error())
-					continue
-				}
+			err := probeNetwork(ctx, httpClient, protocol, address, condition, opts)
+			if errors.Is(err, errInvalidNetworkCondition) {
+				return err
+			}
+			if err != nil {
+				l.Debug(err.Error())
+				continue
 			}
 
 			// Yay, we made it!
@@ -496,3 +722,73 @@ func forNetwork(ctx context.Context, protocol string, address string, condition
 		}
 	}
 }
+
+// probeNetwork checks once whether the endpoint responds. HTTP endpoints must return a 2xx status code, or the status
+// code in condition when it is set to one, while any other protocol only has to accept a connection.
+func probeNetwork(ctx context.Context, httpClient *http.Client, protocol string, address string, condition string, opts NetworkOptions) error {
+	condition = strings.ToLower(condition)
+	if condition == "" {
+		condition = "success"
+	}
+
+	switch protocol {
+	case "http", "https":
+		// Handle HTTP and HTTPS endpoints.
+		url := fmt.Sprintf("%s://%s", protocol, address)
+
+		code := 0
+		// Default to checking for a 2xx response, otherwise convert the condition to an int and check if it's a valid HTTP status code.
+		if condition != "success" {
+			var err error
+			code, err = strconv.Atoi(condition)
+			if err != nil {
+				return fmt.Errorf("%w: http status code %s is not an integer: %w", errInvalidNetworkCondition, condition, err)
+			}
+			if http.StatusText(code) == "" {
+				return fmt.Errorf("%w: http status code is unknown", errInvalidNetworkCondition)
+			}
+		}
+
+		// Try to get the URL and check the status code.
+		method := http.MethodGet
+		if opts.Method != "" {
+			method = strings.ToUpper(opts.Method)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, url, nil)
+		if err != nil {
+			return err
+		}
+		for key, value := range opts.Headers {
+			req.Header.Set(key, value)
+		}
+		// Go only uses the Host header through req.Host
+		if host := req.Header.Get("Host"); host != "" {
+			req.Host = host
+		}
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		//nolint: errcheck // ignore
+		resp.Body.Close()
+
+		if code == 0 && (resp.StatusCode < 200 || resp.StatusCode > 299) {
+			return fmt.Errorf("%s responded with status code %d, expected a 2xx status code", url, resp.StatusCode)
+		}
+		if code != 0 && resp.StatusCode != code {
+			return fmt.Errorf("%s responded with status code %d, expected %d", url, resp.StatusCode, code)
+		}
+		return nil
+	default:
+		// Fallback to any generic protocol using net.Dial
+		var dialer net.Dialer
+		if httpClient.Timeout > 0 {
+			dialer.Timeout = httpClient.Timeout
+		}
+		conn, err := dialer.DialContext(ctx, protocol, address)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+}
@@ -5,6 +5,7 @@
 package wait
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -12,6 +13,13 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/utils/ptr"
 )
 
 func TestIsJSONPathWaitType(t *testing.T) {
@@ -140,7 +148,7 @@ func TestForNetwork(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			err := forNetwork(t.Context(), "http", tt.host, tt.condition, tt.timeout, tt.interval)
+			err := forNetwork(t.Context(), "http", tt.host, tt.condition, tt.timeout, tt.interval, NetworkOptions{})
 			if tt.expectErr {
 				require.Error(t, err)
 				return
@@ -149,3 +157,391 @@ func TestForNetwork(t *testing.T) {
 		})
 	}
 }
+
+func TestForNetworkWithOptions(t *testing.T) {
+	t.Parallel()
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	})
+	httpServer := httptest.NewServer(handler)
+	t.Cleanup(httpServer.Close)
+	tlsServer := httptest.NewTLSServer(handler)
+	t.Cleanup(tlsServer.Close)
+
+	httpAddress := strings.TrimPrefix(httpServer.URL, "http://")
+	tlsAddress := strings.TrimPrefix(tlsServer.URL, "https://")
+	authorized := NetworkOptions{
+		Method:  "post",
+		Headers: map[string]string{"Authorization": "Bearer token"},
+	}
+
+	tests := []struct {
+		name      string
+		protocol  string
+		address   string
+		condition string
+		opts      NetworkOptions
+		expectErr bool
+	}{
+		{
+			name:      "method and headers are sent",
+			protocol:  "http",
+			address:   httpAddress,
+			condition: "202",
+			opts:      authorized,
+		},
+		{
+			name:      "missing headers",
+			protocol:  "http",
+			address:   httpAddress,
+			condition: "202",
+			opts:      NetworkOptions{Method: http.MethodPost},
+			expectErr: true,
+		},
+		{
+			name:      "self-signed certificate is rejected",
+			protocol:  "https",
+			address:   tlsAddress,
+			condition: "202",
+			opts:      authorized,
+			expectErr: true,
+		},
+		{
+			name:      "self-signed certificate is skipped",
+			protocol:  "https",
+			address:   tlsAddress,
+			condition: "202",
+			opts: NetworkOptions{
+				Method:             authorized.Method,
+				Headers:            authorized.Headers,
+				InsecureSkipVerify: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := forNetwork(t.Context(), tt.protocol, tt.address, tt.condition, 500*time.Millisecond, 10*time.Millisecond, tt.opts)
+			if tt.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestWaitForRollout(t *testing.T) {
+	t.Parallel()
+
+	daemonSet := func(name string, updated, available int32) *appsv1.DaemonSet {
+		return &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Generation: 1, Labels: map[string]string{"app": name}},
+			Spec: appsv1.DaemonSetSpec{
+				UpdateStrategy: appsv1.DaemonSetUpdateStrategy{Type: appsv1.RollingUpdateDaemonSetStrategyType},
+			},
+			Status: appsv1.DaemonSetStatus{
+				ObservedGeneration:     1,
+				DesiredNumberScheduled: 3,
+				UpdatedNumberScheduled: updated,
+				NumberAvailable:        available,
+			},
+		}
+	}
+	statefulSet := func(name string, ready int32, currentRevision, updateRevision string) *appsv1.StatefulSet {
+		return &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Generation: 2},
+			Spec: appsv1.StatefulSetSpec{
+				Replicas:       ptr.To(int32(3)),
+				UpdateStrategy: appsv1.StatefulSetUpdateStrategy{Type: appsv1.RollingUpdateStatefulSetStrategyType},
+			},
+			Status: appsv1.StatefulSetStatus{
+				ObservedGeneration: 2,
+				ReadyReplicas:      ready,
+				CurrentReplicas:    ready,
+				CurrentRevision:    currentRevision,
+				UpdateRevision:     updateRevision,
+			},
+		}
+	}
+	onDelete := daemonSet("on-delete", 3, 3)
+	onDelete.Spec.UpdateStrategy.Type = appsv1.OnDeleteDaemonSetStrategyType
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, appsv1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(scheme,
+		daemonSet("rolled-out", 3, 3),
+		daemonSet("updating", 1, 1),
+		daemonSet("unavailable", 3, 2),
+		onDelete,
+		statefulSet("rolled-out", 3, "web-1", "web-1"),
+		statefulSet("not-ready", 1, "web-1", "web-1"),
+		statefulSet("updating", 3, "web-1", "web-2"),
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "default"}},
+	)
+
+	daemonSets := &meta.RESTMapping{
+		Resource:         appsv1.SchemeGroupVersion.WithResource("daemonsets"),
+		GroupVersionKind: appsv1.SchemeGroupVersion.WithKind("DaemonSet"),
+		Scope:            meta.RESTScopeNamespace,
+	}
+	statefulSets := &meta.RESTMapping{
+		Resource:         appsv1.SchemeGroupVersion.WithResource("statefulsets"),
+		GroupVersionKind: appsv1.SchemeGroupVersion.WithKind("StatefulSet"),
+		Scope:            meta.RESTScopeNamespace,
+	}
+	configMaps := &meta.RESTMapping{
+		Resource:         corev1.SchemeGroupVersion.WithResource("configmaps"),
+		GroupVersionKind: corev1.SchemeGroupVersion.WithKind("ConfigMap"),
+		Scope:            meta.RESTScopeNamespace,
+	}
+
+	tests := []struct {
+		name          string
+		mapping       *meta.RESTMapping
+		identifier    string
+		expectedError string
+	}{
+		{
+			name:       "daemonset rolled out",
+			mapping:    daemonSets,
+			identifier: "rolled-out",
+		},
+		{
+			name:       "daemonset rolled out by selector",
+			mapping:    daemonSets,
+			identifier: "app=rolled-out",
+		},
+		{
+			name:          "daemonset pods not updated",
+			mapping:       daemonSets,
+			identifier:    "updating",
+			expectedError: `timed out waiting for DaemonSet.apps/updating to be rolled out: Waiting for daemon set "updating" rollout to finish: 1 out of 3 new pods have been updated...`,
+		},
+		{
+			name:          "daemonset pods not available",
+			mapping:       daemonSets,
+			identifier:    "unavailable",
+			expectedError: `timed out waiting for DaemonSet.apps/unavailable to be rolled out: Waiting for daemon set "unavailable" rollout to finish: 2 of 3 updated pods are available...`,
+		},
+		{
+			name:          "daemonset without rolling updates",
+			mapping:       daemonSets,
+			identifier:    "on-delete",
+			expectedError: "unable to get the rollout status of DaemonSet.apps/on-delete: rollout status is only available for RollingUpdate strategy type",
+		},
+		{
+			name:       "statefulset rolled out",
+			mapping:    statefulSets,
+			identifier: "rolled-out",
+		},
+		{
+			name:          "statefulset pods not ready",
+			mapping:       statefulSets,
+			identifier:    "not-ready",
+			expectedError: "timed out waiting for StatefulSet.apps/not-ready to be rolled out: Waiting for 2 pods to be ready...",
+		},
+		{
+			name:          "statefulset update in progress",
+			mapping:       statefulSets,
+			identifier:    "updating",
+			expectedError: "timed out waiting for StatefulSet.apps/updating to be rolled out: waiting for statefulset rolling update to complete 0 pods at revision web-2...",
+		},
+		{
+			name:          "missing resource",
+			mapping:       statefulSets,
+			identifier:    "missing",
+			expectedError: "timed out waiting for StatefulSet.apps/missing to be rolled out: resource not found",
+		},
+		{
+			name:          "unsupported kind",
+			mapping:       configMaps,
+			identifier:    "config",
+			expectedError: "the rolledOut condition is not supported for ConfigMap",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			deadline := time.Now().Add(1500 * time.Millisecond)
+			err := waitForRollout(context.Background(), dynamicClient, tt.mapping, tt.identifier, "default", deadline)
+			if tt.expectedError != "" {
+				require.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestWaitForSelectorJSONPath(t *testing.T) {
+	t.Parallel()
+
+	pod := func(name, app string, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": app}},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}, {Name: "sidecar"}}},
+			Status:     corev1.PodStatus{Phase: phase},
+		}
+	}
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(scheme,
+		pod("web-0", "web", corev1.PodRunning),
+		pod("web-1", "web", corev1.PodRunning),
+		pod("etcd-0", "etcd", corev1.PodRunning),
+		pod("etcd-1", "etcd", corev1.PodRunning),
+		pod("etcd-2", "etcd", corev1.PodPending),
+	)
+	pods := &meta.RESTMapping{
+		Resource:         corev1.SchemeGroupVersion.WithResource("pods"),
+		GroupVersionKind: corev1.SchemeGroupVersion.WithKind("Pod"),
+		Scope:            meta.RESTScopeNamespace,
+	}
+
+	tests := []struct {
+		name          string
+		selector      string
+		condition     string
+		minReady      int
+		expectedError string
+	}{
+		{
+			name:      "every matching resource meets the condition",
+			selector:  "app=web",
+			condition: "{.status.phase}=Running",
+		},
+		{
+			name:          "a matching resource does not meet the condition",
+			selector:      "app=etcd",
+			condition:     "{.status.phase}=Running",
+			expectedError: "timed out waiting for Pod matching app=etcd to be {.status.phase}=Running: 2 of 3 resources meet the condition, 3 required",
+		},
+		{
+			name:      "min ready resources meet the condition",
+			selector:  "app=etcd",
+			condition: "{.status.phase}=Running",
+			minReady:  2,
+		},
+		{
+			name:          "fewer resources than min ready",
+			selector:      "app=web",
+			condition:     "{.status.phase}=Running",
+			minReady:      3,
+			expectedError: "timed out waiting for Pod matching app=web to be {.status.phase}=Running: 2 of 2 resources meet the condition, 3 required",
+		},
+		{
+			name:          "no matching resources",
+			selector:      "app=missing",
+			condition:     "{.status.phase}=Running",
+			expectedError: "timed out waiting for Pod matching app=missing to be {.status.phase}=Running: no resources found",
+		},
+		{
+			name:          "jsonpath with multiple values",
+			selector:      "app=web",
+			condition:     "{.spec.containers[*].name}=app",
+			expectedError: "unable to evaluate {.spec.containers[*].name}=app on Pod/web-0: the jsonpath matches more than one value",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			deadline := time.Now().Add(1500 * time.Millisecond)
+			err := waitForSelectorJSONPath(context.Background(), dynamicClient, pods, tt.condition, tt.selector, "default", tt.minReady, deadline)
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestWaitForDeleted(t *testing.T) {
+	t.Parallel()
+
+	configMap := func(name, app string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": app}}}
+	}
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	configMaps := &meta.RESTMapping{
+		Resource:         corev1.SchemeGroupVersion.WithResource("configmaps"),
+		GroupVersionKind: corev1.SchemeGroupVersion.WithKind("ConfigMap"),
+		Scope:            meta.RESTScopeNamespace,
+	}
+
+	tests := []struct {
+		name          string
+		identifier    string
+		namespace     string
+		deleted       string
+		expectedError string
+	}{
+		{
+			name:       "missing resource",
+			identifier: "missing",
+			namespace:  "default",
+		},
+		{
+			name:          "resource still present",
+			identifier:    "operator",
+			namespace:     "default",
+			expectedError: "timed out waiting for ConfigMap/operator to be deleted, still present: default/operator",
+		},
+		{
+			name:       "resource deleted while waiting",
+			identifier: "operator",
+			namespace:  "default",
+			deleted:    "operator",
+		},
+		{
+			name:       "no resources matching selector",
+			identifier: "app=missing",
+			namespace:  "default",
+		},
+		{
+			name:          "resources matching selector still present",
+			identifier:    "app=instance",
+			namespace:     "default",
+			expectedError: "timed out waiting for ConfigMap matching app=instance to be deleted, still present: default/instance-0, default/instance-1",
+		},
+		{
+			name:      "no resources of kind",
+			namespace: "other",
+		},
+		{
+			name:          "resources of kind still present",
+			expectedError: "timed out waiting for every ConfigMap to be deleted, still present: default/instance-0, default/instance-1, default/operator",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			dynamicClient := dynamicfake.NewSimpleDynamicClient(scheme,
+				configMap("operator", "operator"),
+				configMap("instance-0", "instance"),
+				configMap("instance-1", "instance"),
+			)
+			if tt.deleted != "" {
+				go func() {
+					time.Sleep(200 * time.Millisecond)
+					//nolint:errcheck // the wait times out if the delete fails
+					dynamicClient.Resource(configMaps.Resource).Namespace("default").Delete(context.Background(), tt.deleted, metav1.DeleteOptions{})
+				}()
+			}
+			deadline := time.Now().Add(1500 * time.Millisecond)
+			err := waitForDeleted(context.Background(), dynamicClient, configMaps, tt.identifier, tt.namespace, deadline)
+			if tt.expectedError != "" {
+				require.EqualError(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
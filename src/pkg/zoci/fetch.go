@@ -32,6 +32,19 @@ func (r *Remote) FetchZarfYAML(ctx context.Context) (v1alpha1.ZarfPackage, error
 	return pkgcfg.ParseMultiDoc(ctx, b)
 }
 
+// ListTags lists the tags of the remote repository.
+func (r *Remote) ListTags(ctx context.Context) ([]string, error) {
+	tags := []string{}
+	err := r.Repo().Tags(ctx, "", func(page []string) error {
+		tags = append(tags, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the tags of %s: %w", r.Repo().Reference, err)
+	}
+	return tags, nil
+}
+
 // FetchImagesIndex fetches the images/index.json file from the remote repository.
 func (r *Remote) FetchImagesIndex(ctx context.Context) (*ocispec.Index, error) {
 	manifest, err := r.FetchRoot(ctx)
@@ -77,6 +77,34 @@ func TestFlavorArchFiltering(t *testing.T) {
 	}
 }
 
+func TestDeployFlavor(t *testing.T) {
+	t.Log("E2E: Create a package with every flavor and choose the flavor on deploy")
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	flavorTest := filepath.Join("src", "test", "packages", "10-deploy-flavors")
+	_, _, err := e2e.Zarf(t, "package", "create", flavorTest, "-o", tmpDir, "--all-flavors", "--no-color", "--confirm")
+	require.NoError(t, err)
+	tarPath := filepath.Join(tmpDir, fmt.Sprintf("zarf-package-test-deploy-flavors-%s-v0.0.0.tar.zst", e2e.Arch))
+
+	stdOut, stdErr, err := e2e.Zarf(t, "package", "deploy", tarPath, "--flavor", "vanilla", "--confirm")
+	require.NoError(t, err, stdOut, stdErr)
+	require.Contains(t, stdErr, "deployed the shared component")
+	require.Contains(t, stdErr, "deployed the vanilla flavor")
+	require.NotContains(t, stdErr, "deployed the chocolate flavor")
+
+	stdOut, stdErr, err = e2e.Zarf(t, "package", "deploy", tarPath, "--flavor", "chocolate", "--confirm")
+	require.NoError(t, err, stdOut, stdErr)
+	require.Contains(t, stdErr, "deployed the shared component")
+	require.Contains(t, stdErr, "deployed the chocolate flavor")
+	require.NotContains(t, stdErr, "deployed the vanilla flavor")
+
+	// A package with several flavors can not be deployed without choosing one
+	_, stdErr, err = e2e.Zarf(t, "package", "deploy", tarPath, "--confirm")
+	require.Error(t, err)
+	require.Contains(t, stdErr, "a flavor is required")
+}
+
 func TestPublishFlavor(t *testing.T) {
 	t.Log("E2E: Publish skeleton flavor package")
 	t.Parallel()
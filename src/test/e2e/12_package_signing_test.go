@@ -5,10 +5,12 @@ package test
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"github.com/zarf-dev/zarf/src/pkg/archive"
 )
 
 func TestPackageSigning(t *testing.T) {
@@ -52,6 +54,19 @@ func TestPackageSigning(t *testing.T) {
 		stdOut, stdErr, err = e2e.Zarf(t, "package", "verify", testPath)
 		require.Error(t, err, stdOut, stdErr)
 		require.Contains(t, stdErr, "package is not signed - verification cannot be performed")
+
+		// verify only the checksums of the unsigned package and of the package extracted to a directory
+		stdOut, stdErr, err = e2e.Zarf(t, "package", "verify", testPath, "--checksums-only")
+		require.NoError(t, err, stdOut, stdErr)
+		extractPath := filepath.Join(tmpdir, "extracted")
+		require.NoError(t, archive.Decompress(t.Context(), testPath, extractPath, archive.DecompressOpts{}))
+		stdOut, stdErr, err = e2e.Zarf(t, "package", "verify", extractPath, "--checksums-only")
+		require.NoError(t, err, stdOut, stdErr)
+
+		// a tampered file fails the verification
+		require.NoError(t, os.WriteFile(filepath.Join(extractPath, "checksums.txt"), []byte("tampered"), 0o644))
+		stdOut, stdErr, err = e2e.Zarf(t, "package", "verify", extractPath, "--checksums-only")
+		require.Error(t, err, stdOut, stdErr)
 	})
 
 	t.Run("Verify signed package without key fails", func(t *testing.T) {
@@ -52,6 +52,9 @@ func RequireNoBackslashInPackagePaths(t *testing.T, pkg v1alpha1.ZarfPackage) {
 			for vi, v := range ch.ValuesFiles {
 				check(prefix+".charts["+strconv.Itoa(chi)+"].valuesFiles["+strconv.Itoa(vi)+"]", v)
 			}
+			for pi, p := range ch.PostRenderPatches {
+				check(prefix+".charts["+strconv.Itoa(chi)+"].postRenderPatches["+strconv.Itoa(pi)+"]", p)
+			}
 		}
 		for mi, m := range comp.Manifests {
 			for fi, f := range m.Files {
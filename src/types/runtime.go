@@ -8,6 +8,15 @@ package types
 type RemoteOptions struct {
 	PlainHTTP             bool
 	InsecureSkipTLSVerify bool
+	// ChartRepoTLS maps the host (and port) of a Helm chart repository to the certificate files used to connect to it
+	ChartRepoTLS map[string]ClientTLSOptions
+}
+
+// ClientTLSOptions are paths to the certificate files used to authenticate to a remote service that requires mutual TLS
+type ClientTLSOptions struct {
+	CertFile string
+	KeyFile  string
+	CAFile   string
 }
 
 // ZarfCommonOptions tracks the user-defined preferences used across commands.